@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/badge"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge [result.json]",
+	Short: "Generate an SVG health badge for README files",
+	Long: `Scan the project, or read the JSON result of a previous scan, and render an
+SVG shield for embedding in README files. The inactive kind shows the number
+of inactive dependencies, e.g. "deps: 3 inactive". The grade kind shows an
+overall health grade from A to F by the mean health score of the
+dependencies, known vulnerabilities cap it at C.
+
+govital serve renders the same badges for the latest scan of a project at
+/badge?project=<path>.`,
+	Example: `  govital badge --out badge.svg
+  govital scan -o json > result.json && govital badge result.json --kind grade --out health.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		kindFlag, err := cmd.Flags().GetString("kind")
+		if err != nil {
+			return err
+		}
+		kind, err := badge.ParseKind(kindFlag)
+		if err != nil {
+			return err
+		}
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			return err
+		}
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+
+		var result *scanner.ScanResult
+		if len(args) == 1 {
+			result, err = scanner.LoadResults(args[0])
+			if err != nil {
+				return err
+			}
+		} else {
+			s, err := newScanner(cmd, projectPath)
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := scanContext(cmd)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			if err := s.Scan(ctx); err != nil {
+				eslog.Logger.Error("Scan failed", slog.Any("error", err))
+				return err
+			}
+			result = s.GetResults()
+		}
+
+		var w io.Writer = os.Stdout
+		if out != "" {
+			file, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create badge: %w", err)
+			}
+			defer file.Close()
+			w = file
+		}
+		return badge.ForResult(result, kind, label).WriteSVG(w)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+
+	addScannerFlags(badgeCmd)
+	badgeCmd.Flags().String("kind", string(badge.Inactive), "What the badge shows: inactive or grade")
+	badgeCmd.Flags().String("label", "", "Text on the left of the badge (default deps or health)")
+	badgeCmd.Flags().String("out", "", "Write the SVG to this file instead of stdout")
+}
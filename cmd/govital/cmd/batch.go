@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/batch"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch [module...]",
+	Short: "Scan many published modules and write a result file per module",
+	Long: `Scan many published modules, e.g. all repositories of an organization,
+fetching their go.mod from the Go proxy like 'govital scan --remote'.
+
+Each result is written to results/<module path>.json in the output
+directory, index.json rolls up the state and summary of every scan. The
+index is saved after every scan, so an interrupted run continues where it
+stopped: modules with a result are skipped, failed ones are retried. Use
+--fresh to scan everything again.
+
+--concurrency limits the modules scanned at once, --per-forge the modules
+of the same host, e.g. github.com, to stay within its rate limits.`,
+	Example: `  govital batch github.com/org/api github.com/org/web@main
+  govital batch --modules-file repos.txt --output-dir results --per-forge 2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modulesFile, err := cmd.Flags().GetString("modules-file")
+		if err != nil {
+			return err
+		}
+
+		targets := args
+		if modulesFile != "" {
+			listed, err := readModuleList(modulesFile)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, listed...)
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no modules to scan, pass them as arguments or with --modules-file")
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		index, err := runBatch(ctx, cmd, os.Stdout, targets)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		return batchFailures(index)
+	},
+}
+
+// runBatch scans the targets remotely into the output directory with the
+// batch flags of cmd and prints the totals to w
+func runBatch(ctx context.Context, cmd *cobra.Command, w io.Writer, targets []string) (*batch.Index, error) {
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		return nil, err
+	}
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	if err != nil {
+		return nil, err
+	}
+	perForge, err := cmd.Flags().GetInt("per-forge")
+	if err != nil {
+		return nil, err
+	}
+	fresh, err := cmd.Flags().GetBool("fresh")
+	if err != nil {
+		return nil, err
+	}
+
+	targetPublishers, err := publishers(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// newScanner reads the shared config and publishers like the history
+	// append to shared files, both happen one scan at a time
+	var mutex sync.Mutex
+	scan := func(ctx context.Context, target string) (*scanner.ScanResult, error) {
+		mutex.Lock()
+		s, err := newScanner(cmd, target)
+		mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		s.SetProgress(nil)
+
+		if err := s.ScanRemote(ctx, target); err != nil {
+			return nil, err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err := publishResults(ctx, targetPublishers, s.GetResults()); err != nil {
+			return nil, err
+		}
+		return s.GetResults(), nil
+	}
+
+	runner := batch.NewRunner(outputDir, scan)
+	runner.SetConcurrency(concurrency)
+	runner.SetPerForgeLimit(perForge)
+	index, err := runner.Run(ctx, targets, !fresh)
+	if index != nil {
+		total := index.Total()
+		fmt.Fprintf(w, "Scanned %d modules into %s: %d dependencies, %d inactive, %d outdated, %d vulnerable\n",
+			len(index.Entries)-len(index.Failed()), outputDir, total.Total, total.Inactive, total.Outdated, total.Vulnerable)
+	}
+	return index, err
+}
+
+// batchFailures logs the failed scans of the index and returns an error if
+// there are any
+func batchFailures(index *batch.Index) error {
+	failed := index.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	for _, entry := range failed {
+		eslog.Logger.Error("Scan failed", slog.String("target", entry.Target), slog.String("error", entry.Error))
+	}
+	return fmt.Errorf("%d of %d scans failed, run again to retry them", len(failed), len(index.Entries))
+}
+
+// readModuleList reads one module per line, empty lines and lines starting
+// with # are skipped
+func readModuleList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module list: %w", err)
+	}
+	defer file.Close()
+
+	var modules []string
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		modules = append(modules, line)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read module list: %w", err)
+	}
+	return modules, nil
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	addScannerFlags(batchCmd)
+	addPublishFlags(batchCmd)
+	// The modules are passed as arguments
+	_ = batchCmd.Flags().MarkHidden("project-path")
+	batchCmd.Flags().String("modules-file", "", "File listing the modules to scan, one per line")
+	addBatchFlags(batchCmd, "govital-results")
+}
+
+// addBatchFlags adds the flags of runBatch
+func addBatchFlags(cmd *cobra.Command, outputDir string) {
+	cmd.Flags().StringP("output-dir", "d", outputDir, "Directory for the result files and the index")
+	cmd.Flags().Int("concurrency", 4, "Number of modules scanned at once")
+	cmd.Flags().Int("per-forge", 2, "Number of modules of the same host scanned at once")
+	cmd.Flags().Bool("fresh", false, "Scan all modules again instead of resuming the previous run")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache of the scans",
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Fetch the metadata of all dependencies into the response cache",
+	Long: `Scan a Go project without reporting, replacing the cached proxy and forge
+responses with fresh ones, e.g. in a nightly job. Later scans with the cache
+enabled by --cache or cache.enabled answer their lookups from the cache and
+finish in seconds while the responses are younger than cache.ttl.
+
+Use the same checks as the scans to warm for, e.g. --check-repositories,
+since only the lookups of enabled checks are cached. Vulnerability queries
+are never cached, so scans always see new advisories.`,
+	Example: `  govital cache warm --project-path .
+  govital cache warm --project-path . --include-indirect --check-repositories --check-licenses
+
+  # crontab: warm the cache every night at 02:00
+  0 2 * * * cd /src/app && govital cache warm --check-repositories`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+
+		// Warming always fills the cache and replaces what is in it
+		for _, flag := range []string{"cache", "refresh-cache"} {
+			if err := cmd.Flags().Set(flag, "true"); err != nil {
+				return err
+			}
+		}
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := s.Scan(ctx); err != nil {
+			eslog.Logger.Error("Scan failed", slog.Any("error", err))
+			return err
+		}
+
+		cfg := config.NewConfig()
+		cacheConfig := cfg.GetCacheConfig()
+		eslog.Logger.Info("Warmed the cache", slog.String("dir", cacheConfig.Dir), slog.Int("dependencies", s.GetResults().Summary.Total), slog.String("project", projectPath))
+		if !cacheConfig.Enabled {
+			eslog.Logger.Info("Scans only use the cache with --cache or cache.enabled: true in the config file")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheWarmCmd)
+
+	addScannerFlags(cacheWarmCmd)
+	_ = cacheWarmCmd.Flags().MarkHidden("cache")
+	_ = cacheWarmCmd.Flags().MarkHidden("refresh-cache")
+}
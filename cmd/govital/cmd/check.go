@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// exitCodePolicyViolation is returned if dependencies meet a fail-on
+// condition, so CI can tell policy failures apart from scan errors
+const exitCodePolicyViolation = 2
+
+// defaultFailOn is used by govital check if no condition is configured
+var defaultFailOn = []string{"inactive"}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail if dependencies meet the configured fail-on conditions",
+	Long: `Scan the dependencies of a Go project and exit with code 2 if any dependency
+meets one of the fail-on conditions. Scan errors exit with code 1.
+
+Conditions: inactive, unknown, outdated, vulnerable, error, license-changed,
+retracted, deprecated, score<N and score<=N.
+Without --fail-on the policy.fail_on list of the config file is used,
+falling back to "inactive" unless policy rules are configured. Rules of the
+policy.rules list fail or warn by expressions like
+"days_since_release > 365 and direct", reported with the rule name. --strict additionally fails on dependencies whose
+maintenance status is unknown, including failed lookups.`,
+	Example: `  govital check
+  govital check --fail-on inactive --fail-on "score<50"
+  govital check --fail-on outdated,vulnerable --check-vulnerabilities
+  govital check --strict`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+
+		conditions, err := failOnConditions(cmd, defaultFailOn)
+		if err != nil {
+			return err
+		}
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := s.Scan(ctx); err != nil {
+			eslog.Logger.Error("Scan failed", slog.Any("error", err))
+			return err
+		}
+
+		// Policy violations are no usage errors
+		cmd.SilenceUsage = true
+		return enforcePolicy(os.Stdout, s.GetResults(), conditions)
+	},
+}
+
+// exitError makes Execute exit with a specific code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// addFailOnFlag registers the --fail-on and --strict flags
+func addFailOnFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("fail-on", nil, "Exit with code 2 if a dependency meets the condition: inactive, unknown, outdated, vulnerable, error, license-changed, retracted, deprecated or score<N (repeatable)")
+	cmd.Flags().Bool("strict", false, "Exit with code 2 if the maintenance status of a dependency is unknown, e.g. because a lookup failed")
+}
+
+// failOnConditions returns the conditions of the --fail-on flag, falling
+// back to the config file and then to defaults, followed by the policy
+// rules of the config file. The defaults only apply without rules. Strict
+// mode adds the unknown condition.
+func failOnConditions(cmd *cobra.Command, defaults []string) ([]policy.Condition, error) {
+	specs, err := cmd.Flags().GetStringSlice("fail-on")
+	if err != nil {
+		return nil, err
+	}
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.NewConfig()
+	cfg.Init()
+	if !cmd.Flags().Changed("fail-on") {
+		specs = cfg.GetFailOn()
+	}
+	if !cmd.Flags().Changed("strict") {
+		strict = cfg.GetStrict()
+	}
+	rules, err := cfg.GetPolicyRules()
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 && len(rules) == 0 {
+		specs = defaults
+	}
+	if strict {
+		specs = append(specs, "unknown")
+	}
+
+	conditions, err := policy.ParseConditions(specs)
+	if err != nil {
+		return nil, err
+	}
+	ruleConditions, err := policy.ParseRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return append(conditions, ruleConditions...), nil
+}
+
+// enforcePolicy writes the violations of the result to w and returns an
+// exitError if any dependency meets a failing condition. Warn rules are
+// only written.
+func enforcePolicy(w io.Writer, result *scanner.ScanResult, conditions []policy.Condition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	violations := policy.Evaluate(result, conditions)
+	policy.WriteViolations(w, violations)
+	failures := policy.Failures(violations)
+	if failures == 0 {
+		return nil
+	}
+	return &exitError{
+		code: exitCodePolicyViolation,
+		err:  fmt.Errorf("%d dependencies meet a fail-on condition", failures),
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	addScannerFlags(checkCmd)
+	addFailOnFlag(checkCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/daemon"
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Rescan projects on a schedule and notify about regressions",
+	Long: `Rescan the projects of daemon.projects in the config file on the cron schedule
+of daemon.schedule and send a notification to the channels configured under
+notify when a dependency newly becomes inactive, archived or vulnerable.
+
+The projects are scanned right away on start; this first scan is the
+baseline and doesn't notify. Enable --check-repositories and
+--check-vulnerabilities to be notified about archived and vulnerable
+dependencies. Results are published like with 'govital scan', e.g. to the
+history with --save-history. --notify restricts the notifications to some
+kinds of targets, e.g. to Slack.`,
+	Example: `  govital daemon
+  govital daemon --schedule "@every 6h" --check-vulnerabilities --check-repositories
+  govital daemon --notify slack,teams`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+		cfg.Init()
+		if cmd.Flags().Changed("schedule") {
+			schedule, err := cmd.Flags().GetString("schedule")
+			if err != nil {
+				return err
+			}
+			cfg.SetDaemonSchedule(schedule)
+		}
+		daemonConfig, err := cfg.GetDaemonConfig()
+		if err != nil {
+			return err
+		}
+		targets, err := publishers(cmd)
+		if err != nil {
+			return err
+		}
+
+		scan := func(ctx context.Context, project daemon.Project) (*scanner.ScanResult, error) {
+			s, err := newScanner(cmd, project.Name())
+			if err != nil {
+				return nil, err
+			}
+			s.SetProgress(nil)
+
+			if project.Remote != "" {
+				err = s.ScanRemote(ctx, project.Remote)
+			} else {
+				err = s.Scan(ctx)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := publishResults(ctx, targets, s.GetResults()); err != nil {
+				return nil, err
+			}
+			return s.GetResults(), nil
+		}
+
+		router, err := notifyRouter(cmd, true)
+		if err != nil {
+			return err
+		}
+		d, err := daemon.New(daemonConfig, scan, []notify.Notifier{router})
+		if err != nil {
+			return err
+		}
+		return d.Run(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	addScannerFlags(daemonCmd)
+	// The projects are configured in the config file
+	_ = daemonCmd.Flags().MarkHidden("project-path")
+	addPublishFlags(daemonCmd)
+	addNotifyFlag(daemonCmd, "Notify only these targets of the config file")
+	daemonCmd.Flags().String("schedule", "", "Cron schedule of the rescans, e.g. \"0 6 * * 1-5\" or \"@every 6h\" (default daemon.schedule)")
+}
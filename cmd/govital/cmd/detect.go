@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/detect"
+)
+
+var detectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Show which modules a scan of the project path covers",
+	Long: `Inspect the project path and print what a scan would cover: the Go module
+or the members of the go.work workspace, whether they vendor their
+dependencies, nested modules in subdirectories which have to be scanned
+separately and manifests of other ecosystems, which govital doesn't scan.
+
+Exits with an error if there is nothing to scan.`,
+	Example: `  govital detect
+  govital detect -p ./monorepo -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+
+		project, err := detect.Detect(projectPath)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(project); err != nil {
+				return err
+			}
+		} else {
+			detect.Write(os.Stdout, project)
+		}
+
+		if !project.Scannable() {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("nothing to scan in %s", projectPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(detectCmd)
+
+	detectCmd.Flags().StringP("project-path", "p", ".", "Path to the directory to inspect")
+	detectCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/diff"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old.json new.json]",
+	Short: "Compare dependency health between two git refs or two scan results",
+	Long: `Compare the dependencies declared in go.mod at two git refs of the same
+repository and report the dependency health changes introduced by the head ref.
+The go.mod files are read via git plumbing, the working tree is not touched.
+
+Given the JSON results of two scans instead, e.g. of the default branch and of
+a pull request, the results are compared without any lookups. The report lists
+the dependencies which were added, removed, became inactive or newly outdated.`,
+	Example: `  govital diff --git-ref main..feature-branch
+  govital diff --git-ref main...HEAD --include-indirect
+  govital diff main.json pr.json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("accepts either no or two scan results, received %d", len(args))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 2 {
+			if cmd.Flags().Changed("git-ref") {
+				return fmt.Errorf("--git-ref can't be combined with scan results")
+			}
+			return compareResults(os.Stdout, args[0], args[1], force)
+		}
+
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+
+		refRange, err := cmd.Flags().GetString("git-ref")
+		if err != nil {
+			return err
+		}
+		if refRange == "" {
+			return fmt.Errorf("--git-ref is required")
+		}
+
+		baseRef, headRef, symmetric, err := diff.ParseRefRange(refRange)
+		if err != nil {
+			return err
+		}
+		if symmetric {
+			if baseRef, err = diff.MergeBase(projectPath, baseRef, headRef); err != nil {
+				return err
+			}
+		}
+
+		eslog.Logger.Info("Comparing dependencies", slog.String("base", baseRef), slog.String("head", headRef), slog.String("project", projectPath))
+
+		baseDeps, baseFingerprint, err := dependenciesAtRef(cmd, projectPath, baseRef)
+		if err != nil {
+			return err
+		}
+		headDeps, headFingerprint, err := dependenciesAtRef(cmd, projectPath, headRef)
+		if err != nil {
+			return err
+		}
+
+		// Only dependencies which differ between the refs need to be looked up
+		baseDeps, headDeps = changedDependencies(baseDeps, headDeps)
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		baseResult, err := scanDependencies(ctx, cmd, projectPath, baseDeps, baseFingerprint)
+		if err != nil {
+			return err
+		}
+		headResult, err := scanDependencies(ctx, cmd, projectPath, headDeps, headFingerprint)
+		if err != nil {
+			return err
+		}
+
+		if !force {
+			if err := diff.CheckComparable(baseResult, headResult); err != nil {
+				return fmt.Errorf("%w (use --force to compare anyway)", err)
+			}
+		}
+
+		report := diff.Compare(baseResult, headResult)
+		report.Base = baseRef
+		report.Head = headRef
+		report.Print(os.Stdout)
+		return nil
+	},
+}
+
+// compareResults prints the changes between the scan results stored in the
+// JSON files basePath and headPath
+func compareResults(w io.Writer, basePath, headPath string, force bool) error {
+	base, err := scanner.LoadResults(basePath)
+	if err != nil {
+		return err
+	}
+	head, err := scanner.LoadResults(headPath)
+	if err != nil {
+		return err
+	}
+	if !force {
+		if err := diff.CheckComparable(base, head); err != nil {
+			return fmt.Errorf("%w (use --force to compare anyway)", err)
+		}
+	}
+
+	report := diff.Compare(base, head)
+	report.Base = basePath
+	report.Head = headPath
+	report.Print(w)
+	return nil
+}
+
+// dependenciesAtRef reads go.mod at the given ref and returns its
+// requirements together with the fingerprint of the ref
+func dependenciesAtRef(cmd *cobra.Command, projectPath, ref string) ([]scanner.Dependency, *scanner.Fingerprint, error) {
+	goMod, err := diff.ReadFileAtRef(projectPath, ref, "go.mod")
+	if err != nil {
+		return nil, nil, err
+	}
+	goSum, err := diff.ReadFileAtRef(projectPath, ref, "go.sum")
+	if err != nil {
+		eslog.Logger.Debug("No go.sum", slog.String("ref", ref), slog.Any("error", err))
+		goSum = nil
+	}
+
+	fingerprint := scanner.NewFingerprint(goMod, goSum)
+	if fingerprint.Revision, err = diff.ResolveRef(projectPath, ref); err != nil {
+		return nil, nil, err
+	}
+
+	s, err := newScanner(cmd, projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	deps, err := s.ParseGoMod(goMod)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deps, fingerprint, nil
+}
+
+// changedDependencies drops all dependencies required at the same version
+// by both refs
+func changedDependencies(base, head []scanner.Dependency) ([]scanner.Dependency, []scanner.Dependency) {
+	key := func(dep scanner.Dependency) string { return dep.Path + "@" + dep.Version }
+
+	inBase := make(map[string]bool, len(base))
+	for _, dep := range base {
+		inBase[key(dep)] = true
+	}
+	inHead := make(map[string]bool, len(head))
+	for _, dep := range head {
+		inHead[key(dep)] = true
+	}
+
+	var changedBase, changedHead []scanner.Dependency
+	for _, dep := range base {
+		if !inHead[key(dep)] {
+			changedBase = append(changedBase, dep)
+		}
+	}
+	for _, dep := range head {
+		if !inBase[key(dep)] {
+			changedHead = append(changedHead, dep)
+		}
+	}
+	return changedBase, changedHead
+}
+
+func scanDependencies(ctx context.Context, cmd *cobra.Command, projectPath string, deps []scanner.Dependency, fingerprint *scanner.Fingerprint) (*scanner.ScanResult, error) {
+	s, err := newScanner(cmd, projectPath)
+	if err != nil {
+		return nil, err
+	}
+	s.SetFingerprint(fingerprint)
+	if err := s.ScanDependencies(ctx, deps); err != nil {
+		return nil, err
+	}
+	return s.GetResults(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	addScannerFlags(diffCmd)
+	diffCmd.Flags().StringP("git-ref", "g", "", "Git revision range to compare, e.g. main..feature-branch")
+	diffCmd.Flags().Bool("force", false, "Compare even if the refs or results declare different module paths")
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/digest"
+	"github.com/steffakasid/govital/pkg/notify"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize how the dependencies of a project changed over a period",
+	Long: `Compare the last recorded scan before the period with the latest scan and
+summarize what changed: newly stale, recovered and archived dependencies,
+new and resolved vulnerabilities, upgrades, and added and removed
+dependencies.
+
+Scans are recorded with 'govital scan --save-history' or history.enabled in
+the config file. With --send the digest goes to the notify targets of the
+config file instead of stdout, which gives teams e.g. a weekly summary
+instead of an alert per scan.`,
+	Example: `  govital digest --since 7d
+  govital digest --since 2w --send
+  govital digest --since 7d --send --channel platform`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return err
+		}
+		period, err := digest.ParsePeriod(since)
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+		send, err := cmd.Flags().GetBool("send")
+		if err != nil {
+			return err
+		}
+		channel, err := cmd.Flags().GetString("channel")
+		if err != nil {
+			return err
+		}
+		project, err := historyProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg := config.NewConfig()
+		cfg.Init()
+		records, err := loadRecords(cmd, cfg, project)
+		if err != nil {
+			return err
+		}
+		eslog.Logger.Debug("Loaded recorded scans", slog.Int("scans", len(records)), slog.String("project", project))
+		d, err := digest.Build(project, records, time.Now().Add(-period))
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		var text bytes.Buffer
+		digest.Write(&text, d)
+		if !send {
+			if output == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(d)
+			}
+			_, err := text.WriteTo(os.Stdout)
+			return err
+		}
+
+		notifyConfig, err := cfg.GetNotifyConfig()
+		if err != nil {
+			return err
+		}
+		targets := notifyConfig.ChannelConfig
+		if channel != "" {
+			var ok bool
+			if targets, ok = channelConfig(notifyConfig, channel); !ok {
+				return fmt.Errorf("unknown notify channel %q", channel)
+			}
+		}
+		if len(notify.New(targets)) == 0 {
+			return fmt.Errorf("no notify targets configured, set notify.webhook, notify.slack, notify.teams or notify.email")
+		}
+		message := notify.Message{Subject: d.Subject(), Text: text.String(), Payload: d}
+		if err := notify.Send(cmd.Context(), targets, message); err != nil {
+			return err
+		}
+		eslog.Logger.Info("Sent digest", slog.String("project", project))
+		return nil
+	},
+}
+
+// channelConfig returns the named channel, channel names are case
+// insensitive like in notify rules
+func channelConfig(config notify.Config, name string) (notify.ChannelConfig, bool) {
+	for channelName, channel := range config.Channels {
+		if strings.EqualFold(channelName, name) {
+			return channel, true
+		}
+	}
+	return notify.ChannelConfig{}, false
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().StringP("project-path", "p", ".", "Path to the Go project")
+	digestCmd.Flags().String("project", "", "Module path the scans were recorded for, e.g. of a remote scan")
+	digestCmd.Flags().String("since", "7d", "Period to summarize, e.g. 7d, 2w or 36h")
+	digestCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	digestCmd.Flags().Bool("send", false, "Send the digest to the notify targets of the config file instead of printing it")
+	digestCmd.Flags().String("channel", "", "Send to this channel of notify.channels instead of the default targets")
+}
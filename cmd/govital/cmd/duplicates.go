@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/duplicates"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates <result.json>...",
+	Short: "Report modules used at inconsistent versions across projects",
+	Long: `Read the JSON results of scans of several projects, as written by
+'govital scan -o json', and report the modules the projects use at
+different versions. Each module shows the projects per version, whether the
+versions differ in health and the version to converge on, which is the
+latest known release or else the newest used version.
+
+The modules are ranked by the number of projects on an older version, so
+the coordinated upgrades which benefit the most projects come first.`,
+	Example: `  for p in billing payments shipping; do govital scan -p $p -o json > $p.json; done
+  govital duplicates billing.json payments.json shipping.json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+
+		results := make([]*scanner.ScanResult, 0, len(args))
+		for _, path := range args {
+			result, err := scanner.LoadResults(path)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+
+		found := duplicates.Find(results)
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(found)
+		}
+		return duplicates.Write(os.Stdout, found)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duplicatesCmd)
+
+	duplicatesCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
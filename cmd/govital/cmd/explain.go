@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/explain"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <module>[@version]",
+	Short: "Show everything govital knows about a single dependency",
+	Long: `Scan a single module with all checks and print a detailed breakdown: the
+maintenance status, release dates of the used and the latest version, the
+available versions, the source repository with its archived flag,
+maintainers, open issues and responsiveness, known vulnerabilities, licenses
+and the fail-on conditions the module meets.
+
+Without a version the version required by the project is explained, or the
+latest one if the project doesn't require the module. License,
+vulnerability and repository checks are enabled unless turned off
+explicitly. Without --fail-on the policy.fail_on list of the config file is
+used, falling back to "inactive".`,
+	Example: `  govital explain github.com/foo/bar
+  govital explain github.com/foo/bar@v1.4.0 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+
+		conditions, err := failOnConditions(cmd, defaultFailOn)
+		if err != nil {
+			return err
+		}
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("check-licenses") {
+			s.SetCheckLicenses(true)
+		}
+		if !cmd.Flags().Changed("check-vulnerabilities") {
+			s.SetCheckVulnerabilities(true)
+		}
+		if !cmd.Flags().Changed("check-repositories") {
+			cfg := config.NewConfig()
+			cfg.Init()
+			s.SetCheckRepositories(true, cfg.GetForgeConfig())
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		target := args[0]
+		if !strings.Contains(target, "@") {
+			target = requiredVersion(s, projectPath, target)
+		}
+		dep, _, err := s.ResolveRemote(ctx, target)
+		if err != nil {
+			return err
+		}
+		dep, err = scanModule(ctx, s, dep)
+		if err != nil {
+			return err
+		}
+
+		versions, err := s.Versions(ctx, dep.Path)
+		if err != nil {
+			eslog.Logger.Warn("Failed to list the versions", slog.String("module", dep.Path), slog.Any("error", err))
+		}
+
+		explanation := explain.Explain(dep, versions, conditions)
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(explanation)
+		}
+		explain.Write(os.Stdout, explanation)
+		return nil
+	},
+}
+
+// requiredVersion returns the module with the version the project requires,
+// or the module alone to explain its latest version
+func requiredVersion(s *scanner.Scanner, projectPath, modulePath string) string {
+	project, err := projectRequirements(s, projectPath)
+	if err != nil {
+		return modulePath
+	}
+	for _, dep := range project {
+		if dep.Path == modulePath {
+			return modulePath + "@" + dep.Version
+		}
+	}
+	return modulePath
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	addScannerFlags(explainCmd)
+	addFailOnFlag(explainCmd)
+	explainCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/report"
+)
+
+var formatsCmd = &cobra.Command{
+	Use:   "formats",
+	Short: "List the available output formats",
+	Long: `List all output formats which can be used with --output. Besides the
+built-in formats, executables named ` + report.PluginPrefix + `<format> on the PATH
+are available as plugin formats. They receive the scan result as JSON on stdin
+and write the rendered report to stdout.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FORMAT\tDESCRIPTION")
+		for _, format := range report.Formats() {
+			description := format.Description
+			if format.Plugin != "" {
+				description = fmt.Sprintf("Plugin (%s)", format.Plugin)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", format.Name, description)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(formatsCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/report"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate files from a dependency scan",
+}
+
+var readmeSectionCmd = &cobra.Command{
+	Use:   "readme-section",
+	Short: "Generate a dependency health section for a README",
+	Long: `Scan the dependencies and print a Markdown section with a health badge, the
+summary table and the scan date. With --update the region between
+` + report.ReadmeStartMarker + ` and ` + report.ReadmeEndMarker + ` of an existing
+README is replaced in place.`,
+	Example: `  govital generate readme-section
+  govital generate readme-section --update README.md`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+
+		readmePath, err := cmd.Flags().GetString("update")
+		if err != nil {
+			return err
+		}
+
+		// Fail before scanning if the README can't be updated
+		var readme []byte
+		if readmePath != "" {
+			if readme, err = os.ReadFile(readmePath); err != nil {
+				return fmt.Errorf("failed to read README: %w", err)
+			}
+			if _, err := report.UpdateReadme(readme, ""); err != nil {
+				return err
+			}
+		}
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := s.Scan(ctx); err != nil {
+			eslog.Logger.Error("Scan failed", slog.Any("error", err))
+			return err
+		}
+
+		section := report.ReadmeSection(s.GetResults(), time.Now())
+		if readmePath == "" {
+			fmt.Print(section)
+			return nil
+		}
+
+		updated, err := report.UpdateReadme(readme, section)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(readmePath, updated, 0o644); err != nil {
+			return fmt.Errorf("failed to write README: %w", err)
+		}
+		eslog.Logger.Info("Updated dependency health section", slog.String("file", readmePath))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(readmeSectionCmd)
+
+	addScannerFlags(readmeSectionCmd)
+	readmeSectionCmd.Flags().String("update", "", "Replace the marked region of this README instead of printing the section")
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the recorded scans of a project",
+	Long: `List the summaries of the scans recorded with 'govital scan --save-history'
+or history.enabled in the config file, oldest first.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeRecords(cmd, history.WriteHistory)
+	},
+}
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show how the dependency health of a project evolves",
+	Long: `Show the change of the total, inactive, outdated, vulnerable and failed
+dependency counts between the first and the last recorded scan, with a
+sparkline of the recent scans.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeRecords(cmd, history.WriteTrend)
+	},
+}
+
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove recorded scans exceeding the retention",
+	Long: `Remove the recorded scans of all projects exceeding history.retention of the
+config file, or the limits given by flags. The latest scan of each project
+is always kept.
+
+Recorded scans are pruned automatically after each scan if a retention is
+configured, the command cleans up an existing history or applies a stricter
+limit once.`,
+	Example: `  govital history prune
+  govital history prune --max-scans 100 --max-age-days 365`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+		cfg.Init()
+		historyConfig := cfg.GetHistoryConfig()
+		retention := historyConfig.Retention
+		if cmd.Flags().Changed("max-scans") {
+			maxScans, err := cmd.Flags().GetInt("max-scans")
+			if err != nil {
+				return err
+			}
+			retention.MaxScans = maxScans
+		}
+		if cmd.Flags().Changed("max-age-days") {
+			maxAgeDays, err := cmd.Flags().GetInt("max-age-days")
+			if err != nil {
+				return err
+			}
+			retention.MaxAgeDays = maxAgeDays
+		}
+		if !retention.Enabled() {
+			return fmt.Errorf("no retention configured, set history.retention or --max-scans or --max-age-days")
+		}
+
+		history, err := openStore(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		defer history.Close()
+		result, err := history.Prune(cmd.Context(), retention)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d of %d recorded scans from %s\n", result.Removed, result.Kept+result.Removed, history.Location())
+		return nil
+	},
+}
+
+// writeRecords loads the recorded scans of the selected project and writes
+// them with write
+func writeRecords(cmd *cobra.Command, write func(w io.Writer, records []history.Record) error) error {
+	project, err := historyProject(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.NewConfig()
+	cfg.Init()
+	records, err := loadRecords(cmd, cfg, project)
+	if err != nil {
+		return err
+	}
+	eslog.Logger.Debug("Loaded recorded scans", slog.Int("scans", len(records)), slog.String("project", project))
+	return write(os.Stdout, records)
+}
+
+// loadRecords returns the recorded scans of the project from the
+// configured storage
+func loadRecords(cmd *cobra.Command, cfg *config.Config, project string) ([]history.Record, error) {
+	store, err := openStore(cmd.Context(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.Load(cmd.Context(), project)
+}
+
+// historyProject returns the --project flag or the key of the project at
+// --project-path, which is its module path if it has a go.mod
+func historyProject(cmd *cobra.Command) (string, error) {
+	project, err := cmd.Flags().GetString("project")
+	if err != nil || project != "" {
+		return project, err
+	}
+
+	projectPath, err := cmd.Flags().GetString("project-path")
+	if err != nil {
+		return "", err
+	}
+	var fingerprint *scanner.Fingerprint
+	if goMod, err := os.ReadFile(filepath.Join(projectPath, "go.mod")); err == nil {
+		fingerprint = scanner.NewFingerprint(goMod, nil)
+	}
+	return history.ProjectKey(projectPath, fingerprint), nil
+}
+
+func init() {
+	historyCmd.AddCommand(historyPruneCmd)
+	historyPruneCmd.Flags().Int("max-scans", 0, "Number of scans kept per project, overrides history.retention.max_scans")
+	historyPruneCmd.Flags().Int("max-age-days", 0, "Number of days scans are kept, overrides history.retention.max_age_days")
+
+	for _, cmd := range []*cobra.Command{historyCmd, trendCmd} {
+		rootCmd.AddCommand(cmd)
+		cmd.Flags().StringP("project-path", "p", ".", "Path to the Go project")
+		cmd.Flags().String("project", "", "Module path the scans were recorded for, e.g. of a remote scan")
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/diff"
+	"github.com/steffakasid/govital/pkg/hook"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Block commits which introduce unhealthy dependencies",
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a git hook running govital hook run",
+	Long: `Write a pre-commit or pre-push hook into the git repository of the current
+directory. The hook runs 'govital hook run', so govital must be on the PATH.
+Existing hooks which were not installed by govital are only replaced with
+--force.
+
+Users of the pre-commit framework (https://pre-commit.com) reference the
+govital hook of the .pre-commit-hooks.yaml in this repository instead.`,
+	Example: `  govital hook install
+  govital hook install --type pre-push`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := cmd.Flags().GetString("type")
+		if err != nil {
+			return err
+		}
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		path, err := hook.Install(".", kind, force)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Installed %s hook at %s\n", kind, path)
+		return nil
+	},
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:   "run [go.mod...]",
+	Short: "Check the dependencies introduced by changed go.mod files",
+	Long: `Quick scan the dependencies which were added or updated in the changed go.mod
+files and exit with code 2 if any of them meets a fail-on condition, which
+makes git abort the commit or push. Dependencies which were already required
+before are not checked, so existing findings don't block unrelated commits.
+
+Without arguments the go.mod files staged for commit are compared with
+--base. With --head the go.mod files of that commit are compared instead,
+e.g. in pre-push hooks. File arguments, as passed by the pre-commit
+framework, select the go.mod files to check. The command has to run in the
+top level directory of the repository, as git hooks do.`,
+	Example: `  govital hook run
+  govital hook run --base origin/main --head HEAD
+  govital hook run --fail-on inactive,retracted service/go.mod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, err := cmd.Flags().GetString("base")
+		if err != nil {
+			return err
+		}
+		head, err := cmd.Flags().GetString("head")
+		if err != nil {
+			return err
+		}
+
+		conditions, err := failOnConditions(cmd, defaultFailOn)
+		if err != nil {
+			return err
+		}
+
+		goMods := args
+		if len(goMods) == 0 {
+			if goMods, err = diff.ChangedFiles(".", base, head, "go.mod"); err != nil {
+				return err
+			}
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		result := &scanner.ScanResult{}
+		for _, goMod := range goMods {
+			// git lists the files with forward slashes, pre-commit with
+			// the separator of the system
+			goMod = filepath.FromSlash(goMod)
+			if filepath.Base(goMod) != "go.mod" {
+				continue
+			}
+			dir := filepath.Dir(goMod)
+
+			var baseDeps []scanner.Dependency
+			if base != "" {
+				if baseDeps, err = hookDependencies(cmd, dir, base); err != nil {
+					eslog.Logger.Debug("No go.mod at the base, all dependencies are new", slog.String("file", goMod), slog.String("base", base), slog.Any("error", err))
+				}
+			}
+			headDeps, err := hookDependencies(cmd, dir, head)
+			if err != nil {
+				return err
+			}
+			_, introduced := changedDependencies(baseDeps, headDeps)
+			if len(introduced) == 0 {
+				continue
+			}
+
+			eslog.Logger.Info("Checking new dependencies", slog.Int("dependencies", len(introduced)), slog.String("file", goMod))
+			s, err := newScanner(cmd, dir)
+			if err != nil {
+				return err
+			}
+			s.SetQuick(true)
+			if err := s.ScanDependencies(ctx, introduced); err != nil {
+				return err
+			}
+			result.Dependencies = append(result.Dependencies, s.GetResults().Dependencies...)
+		}
+
+		cmd.SilenceUsage = true
+		return enforcePolicy(os.Stderr, result, conditions)
+	},
+}
+
+// hookDependencies returns the requirements of the go.mod in dir at ref, or
+// in the index if ref is empty
+func hookDependencies(cmd *cobra.Command, dir, ref string) ([]scanner.Dependency, error) {
+	goMod, err := diff.ReadFileAtRef(dir, ref, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+	s, err := newScanner(cmd, dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.ParseGoMod(goMod)
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookInstallCmd, hookRunCmd)
+
+	hookInstallCmd.Flags().String("type", hook.PreCommit, "Kind of hook to install: pre-commit or pre-push")
+	hookInstallCmd.Flags().Bool("force", false, "Replace an existing hook which was not installed by govital")
+
+	addScannerFlags(hookRunCmd)
+	addFailOnFlag(hookRunCmd)
+	hookRunCmd.Flags().String("base", "HEAD", "Git ref to compare against, empty to treat all dependencies as new")
+	hookRunCmd.Flags().String("head", "", "Git ref to check instead of the staged changes")
+}
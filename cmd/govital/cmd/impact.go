@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/impact"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact <module>",
+	Short: "Show what changes if a dependency is removed or replaced",
+	Long: `Report the packages importing a dependency, the modules which are only
+required through it and disappear with it, and the change of the average
+health score of all dependencies. With --replace-with the replacement module
+is scanned and counted instead, its own dependencies are not included.`,
+	Example: `  govital impact github.com/foo/bar
+  govital impact github.com/foo/bar --replace-with github.com/baz/bar@v1.4.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modulePath := args[0]
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+		replaceWith, err := cmd.Flags().GetString("replace-with")
+		if err != nil {
+			return err
+		}
+		var replacement *scanner.Dependency
+		if replaceWith != "" {
+			path, version, ok := strings.Cut(replaceWith, "@")
+			if !ok || path == "" || version == "" {
+				return fmt.Errorf("invalid --replace-with %q, expected <module>@<version>", replaceWith)
+			}
+			replacement = &scanner.Dependency{Path: path, Version: version, IsActive: true}
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		graph, err := impact.LoadGraph(ctx, projectPath)
+		if err != nil {
+			return err
+		}
+		importedBy, err := impact.ImportingPackages(ctx, projectPath, modulePath)
+		if err != nil {
+			return err
+		}
+
+		// The modules dropped with the dependency are indirect in most cases
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		s.SetIncludeIndirectDependencies(true)
+		if err := s.Scan(ctx); err != nil {
+			eslog.Logger.Error("Scan failed", slog.Any("error", err))
+			return err
+		}
+
+		if replacement != nil {
+			replacementScanner, err := newScanner(cmd, projectPath)
+			if err != nil {
+				return err
+			}
+			scanned, err := scanModule(ctx, replacementScanner, *replacement)
+			if err != nil {
+				return err
+			}
+			replacement = &scanned
+		}
+
+		report := impact.Analyze(s.GetResults(), graph, modulePath, importedBy, replacement)
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+		impact.Write(os.Stdout, report)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(impactCmd)
+
+	addScannerFlags(impactCmd)
+	impactCmd.Flags().String("replace-with", "", "Module replacing the dependency, e.g. github.com/baz/bar@v1.4.0")
+	impactCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
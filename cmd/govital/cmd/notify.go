@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// addNotifyFlag registers the flag selecting the notify targets
+func addNotifyFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringSlice("notify", nil, usage+", one or more of "+strings.Join(notify.Targets, ", "))
+}
+
+// notifyRouter creates the router of the notify targets of the config file
+// selected by --notify. Without --notify all targets are used if all is
+// set, otherwise the router is nil.
+func notifyRouter(cmd *cobra.Command, all bool) (*notify.Router, error) {
+	targets, err := cmd.Flags().GetStringSlice("notify")
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 && !all {
+		return nil, nil
+	}
+
+	cfg := config.NewConfig()
+	cfg.Init()
+	notifyConfig, err := cfg.GetNotifyConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > 0 {
+		if notifyConfig, err = notifyConfig.Select(targets); err != nil {
+			return nil, err
+		}
+	}
+	router, err := notify.NewRouter(notifyConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > 0 && !router.Enabled() {
+		return nil, fmt.Errorf("no %s target configured under notify", strings.Join(targets, " or "))
+	}
+	return router, nil
+}
+
+// notifyFindings sends the findings of the scan which are new since the
+// previous one. Without previous scan all findings are new.
+func notifyFindings(ctx context.Context, router *notify.Router, project string, previous, result *scanner.ScanResult) error {
+	if previous == nil {
+		previous = &scanner.ScanResult{}
+	}
+	findings := notify.Changes(previous, result)
+	if len(findings) == 0 {
+		eslog.Logger.Info("No new findings to notify about")
+		return nil
+	}
+	event := notify.Event{Project: project, ScannedAt: time.Now(), Findings: findings}
+	if err := router.Notify(ctx, event); err != nil {
+		return err
+	}
+	eslog.Logger.Info("Sent new findings", slog.Int("findings", len(findings)), slog.String("project", project))
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/batch"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/org"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Scan all Go repositories of a forge organization",
+}
+
+var orgScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan the Go repositories of an organization and rank the risky dependencies",
+	Long: `List the repositories of a GitHub organization or a GitLab group including
+its subgroups, scan the go.mod of the default branch of every repository
+with a go.mod in its root like 'govital batch', and report the risky
+dependencies shared by the most repositories: inactive, vulnerable,
+archived, deprecated or retracted ones.
+
+Archived repositories and repositories GitHub detected another language for
+are skipped. The forge tokens and self-hosted APIs of the config file are
+used, --host selects a GitHub Enterprise Server or self-hosted GitLab.
+
+The results are kept in the output directory, so interrupted runs continue
+where they stopped, see 'govital batch'.`,
+	Example: `  govital org scan --github-org myorg --check-vulnerabilities
+  govital org scan --gitlab-group mygroup/platform --top 10 -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		githubOrg, err := cmd.Flags().GetString("github-org")
+		if err != nil {
+			return err
+		}
+		gitlabGroup, err := cmd.Flags().GetString("gitlab-group")
+		if err != nil {
+			return err
+		}
+		host, err := cmd.Flags().GetString("host")
+		if err != nil {
+			return err
+		}
+		includeArchived, err := cmd.Flags().GetBool("include-archived")
+		if err != nil {
+			return err
+		}
+		top, err := cmd.Flags().GetInt("top")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+
+		name := githubOrg
+		if host == "" {
+			host = "github.com"
+		}
+		switch {
+		case githubOrg != "" && gitlabGroup != "":
+			return fmt.Errorf("--github-org and --gitlab-group are mutually exclusive")
+		case gitlabGroup != "":
+			name = gitlabGroup
+			if !cmd.Flags().Changed("host") {
+				host = "gitlab.com"
+			}
+		case githubOrg == "":
+			return fmt.Errorf("no organization given, set --github-org or --gitlab-group")
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		cfg := config.NewConfig()
+		cfg.Init()
+		repositories, err := org.Discover(ctx, forge.NewClient(nil, cfg.GetForgeConfig()), host, name, includeArchived)
+		if err != nil {
+			return err
+		}
+		if len(repositories) == 0 {
+			return fmt.Errorf("no Go repositories found in %s/%s", host, name)
+		}
+		eslog.Logger.Info("Found Go repositories", slog.Int("repositories", len(repositories)), slog.String("org", host+"/"+name))
+
+		targets := make([]string, 0, len(repositories))
+		for _, repository := range repositories {
+			targets = append(targets, repository.Target())
+		}
+		// The report goes to stdout, so the totals are logged
+		index, err := runBatch(ctx, cmd, os.Stderr, targets)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		results, err := batchResults(cmd, index, targets)
+		if err != nil {
+			return err
+		}
+		risks := org.Rank(results)
+		if top > 0 && len(risks) > top {
+			risks = risks[:top]
+		}
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(risks)
+		} else {
+			err = org.Write(os.Stdout, risks)
+		}
+		if err != nil {
+			return err
+		}
+		return batchFailures(index)
+	},
+}
+
+// batchResults loads the results of the targets which succeeded, the index
+// may list further modules of earlier runs
+func batchResults(cmd *cobra.Command, index *batch.Index, targets []string) ([]*scanner.ScanResult, error) {
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		wanted[target] = true
+	}
+
+	var results []*scanner.ScanResult
+	for _, entry := range index.Entries {
+		if !wanted[entry.Target] || entry.State != batch.Succeeded {
+			continue
+		}
+		result, err := scanner.LoadResults(filepath.Join(outputDir, filepath.FromSlash(entry.File)))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func init() {
+	rootCmd.AddCommand(orgCmd)
+	orgCmd.AddCommand(orgScanCmd)
+
+	addScannerFlags(orgScanCmd)
+	addPublishFlags(orgScanCmd)
+	// The modules are discovered from the organization
+	_ = orgScanCmd.Flags().MarkHidden("project-path")
+	orgScanCmd.Flags().String("github-org", "", "GitHub organization to scan")
+	orgScanCmd.Flags().String("gitlab-group", "", "GitLab group to scan, including its subgroups")
+	orgScanCmd.Flags().String("host", "", "Host of the forge, e.g. of a GitHub Enterprise Server (default github.com or gitlab.com)")
+	orgScanCmd.Flags().Bool("include-archived", false, "Scan archived repositories as well")
+	orgScanCmd.Flags().Int("top", 20, "Number of risky dependencies to report, 0 for all")
+	orgScanCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	addBatchFlags(orgScanCmd, "govital-org")
+}
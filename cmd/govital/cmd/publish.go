@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/publish"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/store"
+)
+
+// addPublishFlags registers the flags selecting where results are published
+func addPublishFlags(cmd *cobra.Command) {
+	cmd.Flags().String("elasticsearch-url", "", "Bulk-index the scanned dependencies into this Elasticsearch/OpenSearch endpoint")
+	cmd.Flags().Bool("save-history", false, "Record the scan summary in the history shown by 'govital history' and 'govital trend'")
+}
+
+// publishers returns the publishers configured by flags or config file
+func publishers(cmd *cobra.Command) ([]publish.Publisher, error) {
+	cfg := config.NewConfig()
+	cfg.Init()
+	if cmd.Flags().Changed("elasticsearch-url") {
+		url, err := cmd.Flags().GetString("elasticsearch-url")
+		if err != nil {
+			return nil, err
+		}
+		cfg.SetElasticsearchURL(url)
+	}
+	if cmd.Flags().Changed("save-history") {
+		saveHistory, err := cmd.Flags().GetBool("save-history")
+		if err != nil {
+			return nil, err
+		}
+		cfg.SetHistoryEnabled(saveHistory)
+	}
+
+	var configured []publish.Publisher
+	if esConfig := cfg.GetElasticsearchConfig(); esConfig.URL != "" {
+		es, err := publish.NewElasticsearch(esConfig)
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, es)
+	}
+	if historyConfig := cfg.GetHistoryConfig(); historyConfig.Enabled {
+		// The store stays open for the lifetime of the command, daemons
+		// publish every scan to it
+		history, err := openStore(cmd.Context(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, store.NewPublisher(history, historyConfig.Retention))
+	}
+	return configured, nil
+}
+
+// openStore opens the storage backend configured in the config file, the
+// history file by default
+func openStore(ctx context.Context, cfg *config.Config) (store.Store, error) {
+	return store.Open(ctx, cfg.GetStorageConfig(), cfg.GetHistoryConfig().Path)
+}
+
+// publishResults sends the result to all publishers
+func publishResults(ctx context.Context, targets []publish.Publisher, result *scanner.ScanResult) error {
+	for _, target := range targets {
+		if err := target.Publish(ctx, result); err != nil {
+			return err
+		}
+	}
+	if len(targets) > 0 {
+		eslog.Logger.Info("Published the scan", slog.Int("dependencies", len(result.Dependencies)), slog.Int("targets", len(targets)))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/report"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render saved scan results in another output format",
+	Long: `Read the result of a previous scan, saved with 'govital scan --save-results'
+or written with --output json, and render it in any output format without
+scanning again. Archived scans can this way be turned into HTML reports,
+SARIF files or annotations later.`,
+	Example: `  govital scan --save-results results.json
+  govital report --from results.json --output html > report.html
+  govital report --from results.json --output template --template-file report.tmpl`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+		if from == "" {
+			return fmt.Errorf("--from is required")
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		templateFile, err := cmd.Flags().GetString("template-file")
+		if err != nil {
+			return err
+		}
+		if templateFile != "" && output != "template" {
+			return fmt.Errorf("--template-file requires --output template")
+		}
+		showErrors, err := cmd.Flags().GetBool("show-errors")
+		if err != nil {
+			return err
+		}
+		if showErrors && output != "text" {
+			return fmt.Errorf("--show-errors requires --output text, the JSON output always holds the errors")
+		}
+		renderer, err := report.Get(output, report.Options{TemplateFile: templateFile, ShowErrors: showErrors})
+		if err != nil {
+			return err
+		}
+
+		result, err := scanner.LoadResults(from)
+		if err != nil {
+			return err
+		}
+		return renderer.Render(os.Stdout, result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().String("from", "", "Scan result saved with --save-results or --output json")
+	reportCmd.Flags().StringP("output", "o", "text", "Output format, see 'govital formats' for all available formats")
+	reportCmd.Flags().String("template-file", "", "Go text/template rendering the scan result with --output template")
+	reportCmd.Flags().Bool("show-errors", false, "List the failed checks of each dependency with their stage in the text report")
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 	"github.com/steffakasid/eslog"
@@ -16,9 +20,20 @@ dependencies are actively maintained and if the used versions are up to date.`,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Interrupting the process cancels the context of the running command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		eslog.Errorf("Failed to execute root command: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
+
+		// The command already reported why it failed
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		eslog.Logger.Error("Failed to execute root command", slog.Any("error", err))
 		os.Exit(1)
 	}
 }
@@ -29,10 +44,17 @@ func init() {
 		cfg.Init()
 		logLevel := cfg.GetLogLevelString()
 		if err := eslog.Logger.SetLogLevel(logLevel); err != nil {
-			eslog.Warnf("Failed to set log level: %v", err)
+			eslog.Logger.Warn("Failed to set log level", slog.Any("error", err))
+		}
+		// The structured attributes of the records, like the module and
+		// duration of dependency lookups, become JSON fields
+		if cfg.GetLogFormat() == config.LogFormatJSON {
+			eslog.Logger.Logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.GetLogLevel()}))
 		}
 	})
 
 	rootCmd.PersistentFlags().StringP("log-level", "l", "info", "Set log level (debug, info, warn, error)")
 	_ = config.Viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
+	rootCmd.PersistentFlags().String("log-format", config.LogFormatText, "Set log format (text, json)")
+	_ = config.Viper.BindPFlag("log_format", rootCmd.PersistentFlags().Lookup("log-format"))
 }
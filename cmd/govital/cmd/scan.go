@@ -1,84 +1,846 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/cache"
 	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/diff"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/progress"
+	"github.com/steffakasid/govital/pkg/prompt"
+	"github.com/steffakasid/govital/pkg/record"
+	"github.com/steffakasid/govital/pkg/report"
 	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/transport"
 )
 
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Scan Go project dependencies for maintenance status",
 	Long: `Scan all dependencies of a Go project and check if they are 
-actively maintained and if the used versions are up to date.`,
+actively maintained and if the used versions are up to date.
+
+With --offline nothing is requested from the network. Release times are
+read from the local module cache and updates are only found among the cached
+versions, e.g. in air-gapped environments.
+
+With --recursive every module below the project path is scanned, e.g. in
+monorepos without go.work. The report holds a summary per module besides the
+aggregated one.
+
+With --remote the go.mod of a published module is fetched from the Go proxy,
+so projects can be audited without checking them out.
+
+With --from-list or --from-gosum an explicit list of module versions is
+scanned instead of a Go project, e.g. a go.sum extracted from a container
+image or the output of go list -m all. Module lists hold one path@version or
+"path version" per line.`,
+	Example: `  govital scan
+  govital scan --recursive
+  govital scan --offline
+  govital scan --dry-run
+  govital scan --remote github.com/org/repo
+  govital scan --remote github.com/org/repo@main
+  govital scan --compare-with previous.json --notify slack
+  govital scan --from-gosum extracted/go.sum
+  go list -m all > modules.txt && govital scan --from-list modules.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectPath, err := cmd.Flags().GetString("project-path")
 		if err != nil {
 			return err
 		}
 
-		staleThreshold, err := cmd.Flags().GetInt("stale-threshold")
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		interactivePrompt, err := cmd.Flags().GetBool("prompt")
+		if err != nil {
+			return err
+		}
+		remote, err := cmd.Flags().GetString("remote")
+		if err != nil {
+			return err
+		}
+		recursive, err := cmd.Flags().GetBool("recursive")
+		if err != nil {
+			return err
+		}
+		offline, err := cmd.Flags().GetBool("offline")
+		if err != nil {
+			return err
+		}
+		stream, err := cmd.Flags().GetBool("stream")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		if dryRun && output != "text" && output != "json" {
+			return fmt.Errorf("--dry-run only supports --output text and json")
+		}
+		compareWith, err := cmd.Flags().GetString("compare-with")
+		if err != nil {
+			return err
+		}
+		saveResults, err := cmd.Flags().GetString("save-results")
+		if err != nil {
+			return err
+		}
+		var previous *scanner.ScanResult
+		if compareWith != "" {
+			if previous, err = scanner.LoadResults(compareWith); err != nil {
+				return err
+			}
+		}
+		templateFile, err := cmd.Flags().GetString("template-file")
+		if err != nil {
+			return err
+		}
+		if templateFile != "" && output != "template" {
+			return fmt.Errorf("--template-file requires --output template")
+		}
+		showErrors, err := cmd.Flags().GetBool("show-errors")
+		if err != nil {
+			return err
+		}
+		if showErrors && output != "text" {
+			return fmt.Errorf("--show-errors requires --output text, the JSON output always holds the errors")
+		}
+		renderer, err := report.Get(output, report.Options{TemplateFile: templateFile, ShowErrors: showErrors})
 		if err != nil {
 			return err
 		}
 
-		includeIndirect, err := cmd.Flags().GetBool("include-indirect")
+		conditions, err := failOnConditions(cmd, nil)
 		if err != nil {
 			return err
 		}
 
-		workers, err := cmd.Flags().GetInt("workers")
+		targets, err := publishers(cmd)
+		if err != nil {
+			return err
+		}
+		router, err := notifyRouter(cmd, false)
 		if err != nil {
 			return err
 		}
 
-		eslog.Infof("Starting dependency scan: %s", projectPath)
+		listPath, listDeps, err := moduleList(cmd)
+		if err != nil {
+			return err
+		}
+		if listPath != "" && remote != "" {
+			return fmt.Errorf("--remote can't be combined with a module list")
+		}
+		if recursive && (remote != "" || listPath != "") {
+			return fmt.Errorf("--recursive only applies to local projects")
+		}
+		if offline && remote != "" {
+			return fmt.Errorf("--remote can't be combined with --offline")
+		}
+		if dryRun && (remote != "" || listPath != "" || recursive) {
+			return fmt.Errorf("--dry-run only applies to a single local project")
+		}
+		incremental, err := cmd.Flags().GetBool("incremental")
+		if err != nil {
+			return err
+		}
+		if incremental && (remote != "" || listPath != "") {
+			return fmt.Errorf("--incremental only applies to local projects")
+		}
 
-		s := scanner.NewScanner(projectPath)
+		if remote != "" {
+			projectPath = remote
+		}
+		if listPath != "" {
+			projectPath = listPath
+		}
+		eslog.Logger.Info("Starting dependency scan", slog.String("project", projectPath))
 
-		// Use CLI flag if provided, otherwise use config
-		if cmd.Flags().Changed("stale-threshold") {
-			s.SetStaleThreshold(staleThreshold)
-		} else {
-			cfg := config.NewConfig()
-			s.SetStaleThreshold(cfg.GetStaleThresholdDays())
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return printPlan(cmd, s, output)
+		}
+		s.SetRecursive(recursive)
+		if stream {
+			s.SetProgress(nil)
+			s.OnDependencyScanned(func(dep scanner.Dependency) {
+				fmt.Fprintln(os.Stderr, streamLine(dep))
+			})
+		}
+		finishRecording, err := setupRecording(cmd, s)
+		if err != nil {
+			return err
+		}
+		finishIncremental, err := setupIncremental(cmd, s, projectPath)
+		if err != nil {
+			return err
 		}
 
-		if cmd.Flags().Changed("include-indirect") {
-			s.SetIncludeIndirectDependencies(includeIndirect)
-		} else {
-			cfg := config.NewConfig()
-			s.SetIncludeIndirectDependencies(cfg.GetIncludeIndirectDependencies())
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
 		}
+		defer cancel()
 
-		if cmd.Flags().Changed("workers") {
-			s.SetWorkers(workers)
+		switch {
+		case remote != "":
+			err = s.ScanRemote(ctx, remote)
+		case listPath != "":
+			err = s.ScanModuleList(ctx, listPath, listDeps)
+		default:
+			err = s.Scan(ctx)
+		}
+		if err != nil {
+			eslog.Logger.Error("Scan failed", slog.Any("error", err))
+			return err
+		}
+		if err := finishRecording(s.GetResults()); err != nil {
+			return err
+		}
+		if err := finishIncremental(s.GetResults()); err != nil {
+			return err
+		}
+		if saveResults != "" {
+			if err := s.SaveResults(saveResults); err != nil {
+				return err
+			}
+			eslog.Logger.Info("Saved scan results", slog.String("file", saveResults))
 		}
 
-		// Load acknowledged dependencies from config
-		cfg := config.NewConfig()
-		cfg.Init()
-		acknowledgedDeps := cfg.GetAcknowledgedDependencies()
-		if len(acknowledgedDeps) > 0 {
-			s.SetAcknowledgedDependencies(acknowledgedDeps)
+		if interactivePrompt {
+			if err := prompt.NewBrowser(s.GetResults()).Run(os.Stdin, os.Stdout); err != nil {
+				return err
+			}
+		} else if err := renderer.Render(os.Stdout, s.GetResults()); err != nil {
+			return err
 		}
 
-		if err := s.Scan(); err != nil {
-			eslog.Errorf("Scan failed: %v", err)
+		if previous != nil {
+			// The diff only goes to stdout if it can't break a machine
+			// readable report
+			w := os.Stderr
+			if output == "text" && !interactivePrompt {
+				w = os.Stdout
+			}
+			if err := diff.CheckComparable(previous, s.GetResults()); err != nil {
+				return err
+			}
+			report := diff.Compare(previous, s.GetResults())
+			report.Base = compareWith
+			report.Head = projectPath
+			report.Print(w)
+		}
+
+		if err := publishResults(ctx, targets, s.GetResults()); err != nil {
 			return err
 		}
+		if router != nil {
+			project := projectPath
+			if remote != "" {
+				project = remote
+			} else if fingerprint := s.GetResults().Fingerprint; fingerprint != nil && fingerprint.Module != "" {
+				project = fingerprint.Module
+			}
+			if err := notifyFindings(ctx, router, project, previous, s.GetResults()); err != nil {
+				return err
+			}
+		}
 
-		s.PrintResults()
-		return nil
+		// Violations go to stderr to keep machine readable output intact
+		cmd.SilenceUsage = true
+		return enforcePolicy(os.Stderr, s.GetResults(), conditions)
 	},
 }
 
+// moduleList reads the modules of --from-list or --from-gosum. The path is
+// empty if neither is set.
+func moduleList(cmd *cobra.Command) (string, []scanner.Dependency, error) {
+	listPath, err := cmd.Flags().GetString("from-list")
+	if err != nil {
+		return "", nil, err
+	}
+	goSumPath, err := cmd.Flags().GetString("from-gosum")
+	if err != nil {
+		return "", nil, err
+	}
+
+	parse := scanner.ParseModuleList
+	switch {
+	case listPath != "" && goSumPath != "":
+		return "", nil, fmt.Errorf("--from-list and --from-gosum can't be combined")
+	case goSumPath != "":
+		listPath, parse = goSumPath, scanner.ParseGoSum
+	case listPath == "":
+		return "", nil, nil
+	}
+
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read module list: %w", err)
+	}
+	deps, err := parse(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", listPath, err)
+	}
+	if len(deps) == 0 {
+		return "", nil, fmt.Errorf("no modules found in %s", listPath)
+	}
+	return listPath, deps, nil
+}
+
+// streamLine describes a dependency scanned with --stream in a single line
+func streamLine(dep scanner.Dependency) string {
+	line := dep.Path + "@" + dep.Version
+	if dep.Module != "" {
+		line += " (" + dep.Module + ")"
+	}
+	findings := report.Findings(dep)
+	if len(findings) == 0 {
+		return line + ": ok"
+	}
+	return line + ": " + strings.Join(findings, ", ")
+}
+
+// addScannerFlags registers the flags shared by all commands running a scan
+func addScannerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project-path", "p", ".", "Path to the Go project to scan")
+	cmd.Flags().IntP("stale-threshold", "t", 180, "Number of days a dependency can be inactive before marked as stale")
+	cmd.Flags().Int("release-threshold", 0, "Number of days without a tagged release before a dependency is marked as stale, even if it has newer commits (0 disables the check)")
+	cmd.Flags().BoolP("include-indirect", "i", false, "Include indirect (transitive) dependencies in the scan")
+	cmd.Flags().StringP("workers", "w", "4", "Number of parallel workers for proxy lookups, or auto to adapt to the network")
+	cmd.Flags().Int("git-workers", 2, "Number of parallel lookups of private modules fetched directly with git")
+	cmd.Flags().Int("forge-workers", 4, "Number of parallel repository lookups on source forges")
+	cmd.Flags().Bool("check-vulnerabilities", false, "Look up known vulnerabilities of the used versions in the OSV database")
+	cmd.Flags().Bool("check-licenses", false, "Look up the licenses of the used versions on deps.dev")
+	cmd.Flags().Bool("check-repositories", false, "Look up whether the source repositories on GitHub, GitLab, Bitbucket, Gitea/Forgejo and sourcehut are archived and count their contributors")
+	cmd.Flags().Bool("check-responsiveness", false, "Measure the median response time to recent issues and count recently merged pull requests on GitHub, GitLab, Bitbucket and Gitea/Forgejo, implies --check-repositories")
+	cmd.Flags().Bool("release-notes", false, "Collect the release notes between the used and the available version of outdated dependencies from forge releases or CHANGELOG.md for the markdown and html output, implies --check-repositories")
+	cmd.Flags().Bool("security-signals", false, "Look up whether the source repositories have a security policy, signed release tags and a protected default branch on GitHub, GitLab and Gitea/Forgejo, implies --check-repositories")
+	cmd.Flags().Bool("check-toolchain", false, "Report outdated and end of life go and toolchain directives of the project and end of life go directives of the dependencies")
+	cmd.Flags().Bool("count-imports", false, "Count the packages of the project importing each dependency with go list, so heavily used inactive dependencies are reported first")
+	cmd.Flags().Int("max-response-days", 0, "Median days maintainers may take to respond to issues before a dependency is marked as inactive, requires --check-responsiveness (0 disables the check)")
+	cmd.Flags().Int("min-contributors", scanner.DefaultMinContributors, "Number of contributors in the last 12 months below which a dependency is a bus factor risk, requires --check-repositories (0 disables the check)")
+	cmd.Flags().String("baseline", "", "JSON result of a previous scan to detect license changes against")
+	cmd.Flags().StringSlice("ignore", nil, "Exclude the modules matching the glob pattern from the scan, e.g. github.com/mycorp/* (repeatable, overrides ignore of the config file)")
+	cmd.Flags().Bool("quick", false, "Only check the release times of the used versions for results within seconds, e.g. in pre-commit hooks")
+	cmd.Flags().Bool("offline", false, "Don't access the network, read the release times from the local module cache, e.g. in air-gapped environments")
+	cmd.Flags().Bool("cache", false, "Answer the proxy and forge lookups from the response cache on disk and fill it, see 'govital cache warm' (overrides cache.enabled of the config file)")
+	cmd.Flags().Bool("refresh-cache", false, "Ignore the cached responses and replace them with fresh ones, requires the cache")
+	cmd.Flags().BoolP("quiet", "q", false, "Don't show scan progress on stderr (progress is only shown on terminals)")
+	cmd.Flags().Duration("timeout", 0, "Abort the scan after the given duration, e.g. 5m (0 means no timeout)")
+	cmd.Flags().Duration("git-timeout", scanner.DefaultGitTimeout, "Abort fetching a private module from its repository with git after the given duration (0 means no timeout, overrides scanner.git_timeout)")
+	cmd.Flags().Duration("http-timeout", transport.DefaultHTTPTimeout, "Abort each HTTP request after the given duration, retries get their own (0 means no timeout, overrides scanner.http_timeout)")
+	cmd.Flags().Duration("per-dependency-timeout", scanner.DefaultDependencyTimeout, "Report a dependency with a timeout error once its lookups take longer than the given duration (0 means no timeout, overrides scanner.per_dependency_timeout)")
+}
+
+// scanContext derives the context for a scan from the command context,
+// applying the --timeout flag if set
+func scanContext(cmd *cobra.Command) (context.Context, context.CancelFunc, error) {
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel, nil
+}
+
+// newScanner creates a scanner configured from the scanner flags of cmd,
+// falling back to the config file for flags which were not set
+func newScanner(cmd *cobra.Command, projectPath string) (*scanner.Scanner, error) {
+	staleThreshold, err := cmd.Flags().GetInt("stale-threshold")
+	if err != nil {
+		return nil, err
+	}
+
+	releaseThreshold, err := cmd.Flags().GetInt("release-threshold")
+	if err != nil {
+		return nil, err
+	}
+
+	minContributors, err := cmd.Flags().GetInt("min-contributors")
+	if err != nil {
+		return nil, err
+	}
+
+	includeIndirect, err := cmd.Flags().GetBool("include-indirect")
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := cmd.Flags().GetString("workers")
+	if err != nil {
+		return nil, err
+	}
+
+	checkVulnerabilities, err := cmd.Flags().GetBool("check-vulnerabilities")
+	if err != nil {
+		return nil, err
+	}
+
+	checkLicenses, err := cmd.Flags().GetBool("check-licenses")
+	if err != nil {
+		return nil, err
+	}
+
+	checkRepositories, err := cmd.Flags().GetBool("check-repositories")
+	if err != nil {
+		return nil, err
+	}
+
+	checkResponsiveness, err := cmd.Flags().GetBool("check-responsiveness")
+	if err != nil {
+		return nil, err
+	}
+
+	releaseNotes, err := cmd.Flags().GetBool("release-notes")
+	if err != nil {
+		return nil, err
+	}
+
+	securitySignals, err := cmd.Flags().GetBool("security-signals")
+	if err != nil {
+		return nil, err
+	}
+
+	countImports, err := cmd.Flags().GetBool("count-imports")
+	if err != nil {
+		return nil, err
+	}
+
+	checkToolchain, err := cmd.Flags().GetBool("check-toolchain")
+	if err != nil {
+		return nil, err
+	}
+
+	maxResponseDays, err := cmd.Flags().GetInt("max-response-days")
+	if err != nil {
+		return nil, err
+	}
+
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return nil, err
+	}
+	quick, err := cmd.Flags().GetBool("quick")
+	if err != nil {
+		return nil, err
+	}
+	offline, err := cmd.Flags().GetBool("offline")
+	if err != nil {
+		return nil, err
+	}
+
+	s := scanner.NewScanner(projectPath)
+	if !quiet && progress.IsTerminal(os.Stderr) {
+		s.SetProgress(progress.NewBar(os.Stderr).Update)
+	}
+	s.SetQuick(quick)
+	s.SetOffline(offline)
+
+	// Use CLI flag if provided, otherwise use config
+	cfg := config.NewConfig()
+	if cmd.Flags().Changed("stale-threshold") {
+		s.SetStaleThreshold(staleThreshold)
+	} else {
+		s.SetStaleThreshold(cfg.GetStaleThresholdDays())
+	}
+
+	if cmd.Flags().Changed("release-threshold") {
+		s.SetReleaseThreshold(releaseThreshold)
+	} else {
+		s.SetReleaseThreshold(cfg.GetReleaseThresholdDays())
+	}
+
+	if cmd.Flags().Changed("min-contributors") {
+		s.SetMinContributors(minContributors)
+	} else {
+		s.SetMinContributors(cfg.GetMinContributors())
+	}
+
+	if cmd.Flags().Changed("include-indirect") {
+		s.SetIncludeIndirectDependencies(includeIndirect)
+	} else {
+		s.SetIncludeIndirectDependencies(cfg.GetIncludeIndirectDependencies())
+	}
+
+	for flag, set := range map[string]func(int){"git-workers": s.SetGitWorkers, "forge-workers": s.SetForgeWorkers} {
+		if cmd.Flags().Changed(flag) {
+			count, err := cmd.Flags().GetInt(flag)
+			if err != nil {
+				return nil, err
+			}
+			set(count)
+		}
+	}
+
+	if cmd.Flags().Changed("workers") {
+		if workers == "auto" {
+			s.SetAutoWorkers()
+		} else {
+			count, err := strconv.Atoi(workers)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --workers %q, expected a number or auto", workers)
+			}
+			s.SetWorkers(count)
+		}
+	}
+
+	if cmd.Flags().Changed("check-vulnerabilities") {
+		s.SetCheckVulnerabilities(checkVulnerabilities)
+	} else {
+		s.SetCheckVulnerabilities(cfg.GetCheckVulnerabilities())
+	}
+
+	if cmd.Flags().Changed("check-licenses") {
+		s.SetCheckLicenses(checkLicenses)
+	} else {
+		s.SetCheckLicenses(cfg.GetCheckLicenses())
+	}
+
+	if !cmd.Flags().Changed("check-repositories") {
+		checkRepositories = cfg.GetCheckRepositories()
+	}
+	if !cmd.Flags().Changed("check-responsiveness") {
+		checkResponsiveness = cfg.GetCheckResponsiveness()
+	}
+	if !cmd.Flags().Changed("max-response-days") {
+		maxResponseDays = cfg.GetMaxResponseDays()
+	}
+	if !cmd.Flags().Changed("release-notes") {
+		releaseNotes = cfg.GetFetchReleaseNotes()
+	}
+	if !cmd.Flags().Changed("security-signals") {
+		securitySignals = cfg.GetCheckSecuritySignals()
+	}
+	// Responsiveness, release notes and security signals are looked up on
+	// the forges as part of the repository checks
+	s.SetCheckRepositories(checkRepositories || checkResponsiveness || releaseNotes || securitySignals, cfg.GetForgeConfig())
+	s.SetCheckResponsiveness(checkResponsiveness)
+	s.SetFetchReleaseNotes(releaseNotes)
+	s.SetCheckSecuritySignals(securitySignals)
+	if !cmd.Flags().Changed("count-imports") {
+		countImports = cfg.GetCountImports()
+	}
+	s.SetCountImports(countImports)
+	if !cmd.Flags().Changed("check-toolchain") {
+		checkToolchain = cfg.GetCheckToolchain()
+	}
+	s.SetCheckToolchain(checkToolchain)
+	s.SetMaxResponseDays(maxResponseDays)
+
+	// Load acknowledged dependencies from config
+	cfg.Init()
+	s.SetScoreWeights(cfg.GetScoreWeights())
+
+	ownerRules, err := cfg.GetOwnerRules()
+	if err != nil {
+		return nil, err
+	}
+	ownerMatcher, err := owners.NewMatcher(ownerRules)
+	if err != nil {
+		return nil, err
+	}
+	s.SetOwners(ownerMatcher)
+
+	severityConfig, err := cfg.GetSeverityConfig()
+	if err != nil {
+		return nil, err
+	}
+	s.SetSeverityConfig(severityConfig)
+
+	rateLimits, err := cfg.GetRateLimits()
+	if err != nil {
+		return nil, err
+	}
+	s.SetRateLimits(rateLimits)
+	retries, err := cfg.GetRetryConfig()
+	if err != nil {
+		return nil, err
+	}
+	s.SetRetries(retries)
+
+	timeouts := cfg.GetTimeouts()
+	for flag, timeout := range map[string]*time.Duration{
+		"git-timeout":            &timeouts.Git,
+		"http-timeout":           &timeouts.HTTP,
+		"per-dependency-timeout": &timeouts.PerDependency,
+	} {
+		if cmd.Flags().Changed(flag) {
+			if *timeout, err = cmd.Flags().GetDuration(flag); err != nil {
+				return nil, err
+			}
+		}
+	}
+	s.SetTimeouts(timeouts)
+
+	overrides, err := cfg.GetDependencyOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetOverrides(overrides); err != nil {
+		return nil, err
+	}
+	tierPolicies, err := cfg.GetTierPolicies()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetTierPolicies(tierPolicies); err != nil {
+		return nil, err
+	}
+	ignorePatterns, err := cmd.Flags().GetStringSlice("ignore")
+	if err != nil {
+		return nil, err
+	}
+	if !cmd.Flags().Changed("ignore") {
+		ignorePatterns = cfg.GetIgnorePatterns()
+	}
+	if err := s.SetIgnorePatterns(ignorePatterns); err != nil {
+		return nil, err
+	}
+
+	acknowledgedDeps := cfg.GetAcknowledgedDependencies()
+	if len(acknowledgedDeps) > 0 {
+		s.SetAcknowledgedDependencies(acknowledgedDeps)
+	}
+
+	baselinePath, err := cmd.Flags().GetString("baseline")
+	if err != nil {
+		return nil, err
+	}
+	if baselinePath != "" {
+		baseline, err := scanner.LoadResults(baselinePath)
+		if err != nil {
+			return nil, err
+		}
+		s.SetBaseline(baseline)
+	}
+
+	if err := setupCache(cmd, cfg, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// scanModule scans a single dependency with s and returns its result, an
+// error if an override or an ignore pattern excludes the module
+func scanModule(ctx context.Context, s *scanner.Scanner, dep scanner.Dependency) (scanner.Dependency, error) {
+	if err := s.ScanDependencies(ctx, []scanner.Dependency{dep}); err != nil {
+		return scanner.Dependency{}, err
+	}
+	deps := s.GetResults().Dependencies
+	if len(deps) == 0 {
+		return scanner.Dependency{}, fmt.Errorf("module %s is ignored by the configuration", dep.Path)
+	}
+	return deps[0], nil
+}
+
+// setupCache answers the lookups of the scan from the response cache if it
+// is enabled by --cache or the config file. It has to wrap the transport
+// after the worker configuration, --replay replaces it including the cache.
+func setupCache(cmd *cobra.Command, cfg *config.Config, s *scanner.Scanner) error {
+	useCache, err := cmd.Flags().GetBool("cache")
+	if err != nil {
+		return err
+	}
+	refresh, err := cmd.Flags().GetBool("refresh-cache")
+	if err != nil {
+		return err
+	}
+
+	cacheConfig := cfg.GetCacheConfig()
+	if cmd.Flags().Changed("cache") {
+		cacheConfig.Enabled = useCache
+	}
+	if !cacheConfig.Enabled {
+		if refresh {
+			return fmt.Errorf("--refresh-cache requires the cache, enable it with --cache or cache.enabled")
+		}
+		return nil
+	}
+	eslog.Logger.Debug("Using the response cache", slog.String("dir", cacheConfig.Dir))
+	s.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		responses := cache.New(next, cacheConfig.Dir, cacheConfig.TTL)
+		responses.SetRefresh(refresh)
+		return responses
+	})
+	return nil
+}
+
+// setupIncremental reuses the dependencies of the last scan of the project
+// with --incremental, if their version didn't change and they were looked
+// up within the cache TTL. The snapshot of the scan is kept in the cache
+// directory. The returned function saves the snapshot once the scan
+// finished.
+func setupIncremental(cmd *cobra.Command, s *scanner.Scanner, projectPath string) (func(result *scanner.ScanResult) error, error) {
+	incremental, err := cmd.Flags().GetBool("incremental")
+	if err != nil {
+		return nil, err
+	}
+	if !incremental {
+		return func(*scanner.ScanResult) error { return nil }, nil
+	}
+	// Quick and offline results lack most lookups, recordings have to hold
+	// all of them
+	for _, flag := range []string{"quick", "offline"} {
+		set, err := cmd.Flags().GetBool(flag)
+		if err != nil {
+			return nil, err
+		}
+		if set {
+			return nil, fmt.Errorf("--incremental can't be combined with --%s", flag)
+		}
+	}
+	for _, flag := range []string{"record", "replay"} {
+		path, err := cmd.Flags().GetString(flag)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			return nil, fmt.Errorf("--incremental can't be combined with --%s", flag)
+		}
+	}
+	refresh, err := cmd.Flags().GetBool("refresh-cache")
+	if err != nil {
+		return nil, err
+	}
+
+	cacheConfig := config.NewConfig().GetCacheConfig()
+	path := cache.SnapshotPath(cacheConfig.Dir, projectPath)
+	snapshot, err := cache.LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	var reused []scanner.Dependency
+	if !refresh {
+		reused = snapshot.Fresh(s.Lookups(), time.Now(), cacheConfig.TTL)
+	}
+	s.SetReusable(reused)
+
+	return func(result *scanner.ScanResult) error {
+		count := snapshot.Update(s.Lookups(), result, reused, time.Now())
+		eslog.Logger.Info("Reused dependencies of the last scan", slog.Int("dependencies", count))
+		return snapshot.Save(path)
+	}, nil
+}
+
+// printPlan prints the dependencies a scan would check and the services it
+// would query, without network access
+func printPlan(cmd *cobra.Command, s *scanner.Scanner, output string) error {
+	ctx, cancel, err := scanContext(cmd)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	plan, err := s.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+	report.WritePlan(os.Stdout, plan)
+	return nil
+}
+
+// setupRecording records the upstream responses of the scan with --record
+// or answers them from a recording with --replay. Either way release ages
+// are computed relative to the time of the recording. The returned function
+// saves the recording once the scan finished.
+func setupRecording(cmd *cobra.Command, s *scanner.Scanner) (func(result *scanner.ScanResult) error, error) {
+	recordPath, err := cmd.Flags().GetString("record")
+	if err != nil {
+		return nil, err
+	}
+	replayPath, err := cmd.Flags().GetString("replay")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, fmt.Errorf("--record and --replay can't be combined")
+	case recordPath != "":
+		recordedAt := time.Now()
+		var recorder *record.Recorder
+		s.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			recorder = record.NewRecorder(next, recordedAt)
+			return recorder
+		})
+		s.SetClock(func() time.Time { return recordedAt })
+		return func(result *scanner.ScanResult) error {
+			eslog.Logger.Info("Recorded the scan inputs", slog.String("file", recordPath))
+			return recorder.Save(recordPath, result.Fingerprint)
+		}, nil
+	case replayPath != "":
+		inputs, err := record.Load(replayPath)
+		if err != nil {
+			return nil, err
+		}
+		s.WrapTransport(func(http.RoundTripper) http.RoundTripper {
+			return record.NewReplayer(inputs)
+		})
+		s.SetClock(func() time.Time { return inputs.RecordedAt })
+		return func(result *scanner.ScanResult) error {
+			if err := inputs.CheckSource(result.Fingerprint); err != nil {
+				eslog.Logger.Warn("Replayed scan may differ", slog.Any("error", err))
+			}
+			return nil
+		}, nil
+	}
+	return func(*scanner.ScanResult) error { return nil }, nil
+}
+
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
-	scanCmd.Flags().StringP("project-path", "p", ".", "Path to the Go project to scan")
-	scanCmd.Flags().IntP("stale-threshold", "t", 180, "Number of days a dependency can be inactive before marked as stale")
-	scanCmd.Flags().BoolP("include-indirect", "i", false, "Include indirect (transitive) dependencies in the scan")
-	scanCmd.Flags().IntP("workers", "w", 4, "Number of parallel workers for scanning dependencies")
+	addScannerFlags(scanCmd)
+	scanCmd.Flags().StringP("output", "o", "text", "Output format, see 'govital formats' for all available formats")
+	scanCmd.Flags().String("template-file", "", "Go text/template rendering the scan result with --output template")
+	scanCmd.Flags().Bool("show-errors", false, "List the failed checks of each dependency with their stage in the text report")
+	scanCmd.Flags().Bool("prompt", false, "Browse the results at an interactive prompt instead of printing a report")
+	scanCmd.Flags().Bool("dry-run", false, "List the dependencies, their repositories and the services a scan would query for each without network access, e.g. to verify the routing of private modules")
+	scanCmd.Flags().Bool("stream", false, "Print each dependency to stderr as soon as it is scanned, instead of the progress")
+	addNotifyFlag(scanCmd, "Send the findings which are new since --compare-with, or all findings, to these notify targets of the config file")
+	scanCmd.Flags().String("compare-with", "", "JSON result of a previous scan to report added, removed, newly inactive and newly outdated dependencies against")
+	scanCmd.Flags().String("save-results", "", "Save the JSON result of the scan to this file, e.g. to render it later with 'govital report'")
+	scanCmd.Flags().String("record", "", "Record all upstream responses of the scan to this file to reproduce it with --replay")
+	scanCmd.Flags().String("replay", "", "Answer all upstream requests from a file written with --record instead of the network")
+	scanCmd.Flags().String("from-list", "", "Scan the module versions listed in this file, one path@version per line, instead of a Go project")
+	scanCmd.Flags().String("from-gosum", "", "Scan the module versions of this go.sum file instead of a Go project")
+	scanCmd.Flags().Bool("incremental", false, "Reuse the dependencies of the last scan of the project whose version didn't change and which were looked up within cache.ttl, only new and updated modules are looked up")
+	scanCmd.Flags().Bool("recursive", false, "Scan every module below the project path, e.g. in monorepos without go.work")
+	scanCmd.Flags().String("remote", "", "Scan a published module fetched from the Go proxy instead of a local project, e.g. github.com/org/repo@v1.2.0")
+	addFailOnFlag(scanCmd)
+	addPublishFlags(scanCmd)
 }
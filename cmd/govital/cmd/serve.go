@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/server"
+)
+
+// shutdownTimeout bounds the time open requests get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// scannerMutex serializes the scanner creation of concurrent scan jobs
+var scannerMutex sync.Mutex
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the scanner as HTTP API",
+	Long: `Run the scanner as shared service with a JSON REST API. Scans are submitted
+with POST /scan and run in the background by a bounded number of workers;
+their ScanResult is fetched with GET /results/{id}.
+
+  POST   /scan          {"project_path": "/src/app"} or
+                        {"modules": ["github.com/org/repo@v1.2.0"]}
+  GET    /results       list the scans, newest first, paged with
+                        ?offset= and ?limit=
+  GET    /results/{id}  state of a scan and its result once it succeeded
+  DELETE /results/{id}  cancel a queued or running scan
+  GET    /metrics       Prometheus metrics of the latest scan of each project
+
+The API listens on the loopback interface by default. Anyone who can reach
+it can scan paths of the server host, so set a token with server.token in
+the config file or GOVITAL_SERVER_TOKEN before listening on other
+interfaces; clients then send it as "Authorization: Bearer <token>". Badges
+are served without the token.
+
+Project paths are resolved on the server host. The scanner flags apply to
+all scans, --timeout limits each scan. Finished scans are kept in memory up
+to --max-finished-jobs and for --job-retention, stored scans are pruned the
+same way. Scans this instance left queued or running in the store when it
+stopped are marked failed on start. The instance is named by --instance-id,
+the host name by default; it must be unique and stable across restarts.
+
+With storage.type sqlite, bolt or postgres in the config file the scans are
+stored there as well and survive restarts. Several instances sharing a
+postgres database serve the scans of each other.`,
+	Example: `  govital serve
+  curl -X POST localhost:8080/scan -d '{"modules":["github.com/pkg/errors@v0.9.1"]}'
+
+  GOVITAL_SERVER_TOKEN=secret govital serve --listen :8080
+  curl -H "Authorization: Bearer secret" host:8080/results`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, err := cmd.Flags().GetString("listen")
+		if err != nil {
+			return err
+		}
+		concurrentScans, err := cmd.Flags().GetInt("concurrent-scans")
+		if err != nil {
+			return err
+		}
+		queueSize, err := cmd.Flags().GetInt("queue-size")
+		if err != nil {
+			return err
+		}
+		maxFinished, err := cmd.Flags().GetInt("max-finished-jobs")
+		if err != nil {
+			return err
+		}
+		jobRetention, err := cmd.Flags().GetDuration("job-retention")
+		if err != nil {
+			return err
+		}
+		instance, err := cmd.Flags().GetString("instance-id")
+		if err != nil {
+			return err
+		}
+		if instance == "" {
+			if instance, err = os.Hostname(); err != nil {
+				return fmt.Errorf("naming the instance, set --instance-id: %w", err)
+			}
+		}
+
+		cfg := config.NewConfig()
+		cfg.Init()
+		store, err := openStore(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		eslog.Logger.Debug("Storing scans", slog.String("store", store.Location()))
+		// Jobs left queued or running when this instance stopped never finish
+		if count, err := jobs.FailOrphaned(cmd.Context(), store, instance, time.Now()); err != nil {
+			eslog.Logger.Warn("Failed to fail orphaned jobs", slog.Any("error", err))
+		} else if count > 0 {
+			eslog.Logger.Info("Marked orphaned jobs as failed", slog.Int("jobs", count))
+		}
+
+		queue := jobs.NewQueue(concurrentScans, queueSize, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+			return runScanJob(ctx, cmd, request)
+		})
+		queue.SetStore(store)
+		queue.SetInstance(instance)
+		queue.SetRetention(maxFinished, jobRetention)
+		defer queue.Close()
+
+		api := server.NewServer(queue)
+		token := cfg.GetServerToken()
+		api.SetToken(token)
+		if token == "" && !isLoopback(listen) {
+			eslog.Logger.Warn("Serving the govital API without a token, anyone who can reach it can scan paths of this host. Set server.token or GOVITAL_SERVER_TOKEN", slog.String("listen", listen))
+		}
+
+		httpServer := &http.Server{
+			Addr:              listen,
+			Handler:           api,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-cmd.Context().Done()
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			_ = httpServer.Shutdown(ctx)
+		}()
+
+		eslog.Logger.Info("Serving the govital API", slog.String("listen", listen), slog.String("instance", instance))
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		eslog.Logger.Info("Server stopped")
+		return nil
+	},
+}
+
+// isLoopback reports whether the listen address only accepts connections
+// from the local host
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// runScanJob scans the project or the modules of a request
+func runScanJob(ctx context.Context, cmd *cobra.Command, request jobs.Request) (*scanner.ScanResult, error) {
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// newScanner reads and initializes the shared config, the workers of
+	// the queue create their scanners one at a time
+	scannerMutex.Lock()
+	s, err := newScanner(cmd, request.ProjectPath)
+	scannerMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// Concurrent scans must not draw over each other
+	s.SetProgress(nil)
+
+	if len(request.Modules) > 0 {
+		deps, err := server.ParseModules(request.Modules)
+		if err != nil {
+			return nil, err
+		}
+		err = s.ScanDependencies(ctx, deps)
+		return s.GetResults(), err
+	}
+	if err := s.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetResults(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	addScannerFlags(serveCmd)
+	// Each request selects the project to scan
+	_ = serveCmd.Flags().MarkHidden("project-path")
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "Address the API listens on, e.g. :8080 for all interfaces")
+	serveCmd.Flags().Int("concurrent-scans", 2, "Number of scans running at the same time")
+	serveCmd.Flags().Int("queue-size", 10, "Number of submitted scans which may wait for a free worker before new ones are rejected")
+	serveCmd.Flags().Int("max-finished-jobs", jobs.DefaultMaxFinished, "Number of finished scans kept in memory, the oldest are dropped (0 keeps all)")
+	serveCmd.Flags().Duration("job-retention", jobs.DefaultRetention, "Time finished scans are kept in memory (0 keeps them)")
+	serveCmd.Flags().String("instance-id", "", "Name of this instance in the stored scans, unique among the instances sharing a store and stable across restarts (default the host name)")
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentScanJobs runs scan jobs like the workers of the queue, with
+// -race it finds unsynchronized access to the shared config
+func TestConcurrentScanJobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var wg sync.WaitGroup
+	for range 16 {
+		dir := t.TempDir()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The directories have no go.mod, the scans fail once the
+			// scanner is created
+			_, err := runScanJob(context.Background(), serveCmd, jobs.Request{ProjectPath: dir})
+			assert.Error(t, err)
+		}()
+	}
+	wg.Wait()
+}
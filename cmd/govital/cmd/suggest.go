@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/suggest"
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest [result.json]",
+	Short: "Suggest maintained alternatives for inactive and archived dependencies",
+	Long: `Scan the project, or read the JSON result of a previous scan, and suggest
+replacements for every inactive, archived or deprecated dependency which is
+not acknowledged. Suggestions come from
+
+  known     a built-in table of successors, e.g. github.com/golang/mock
+            is continued as go.uber.org/mock
+  deps.dev  source repositories deps.dev relates to the module, which
+            differ from its own, e.g. after a move
+  fork      starred forks on GitHub, GitLab and Gitea which were pushed
+            within the stale threshold and are not archived
+
+Archived repositories are only known with --check-repositories. The forge
+tokens of the config file or GITHUB_TOKEN and GITLAB_TOKEN raise the rate
+limits of the fork lookups. Modules matching GOPRIVATE or GONOSUMDB are not
+looked up on deps.dev.`,
+	Example: `  govital suggest --check-repositories
+  govital scan -o json > result.json && govital suggest result.json --max-forks 5`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+		maxForks, err := cmd.Flags().GetInt("max-forks")
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		// The scanner also knows the private modules of loaded results
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		var result *scanner.ScanResult
+		if len(args) == 1 {
+			result, err = scanner.LoadResults(args[0])
+			if err != nil {
+				return err
+			}
+		} else {
+			if err := s.Scan(ctx); err != nil {
+				eslog.Logger.Error("Scan failed", slog.Any("error", err))
+				return err
+			}
+			result = s.GetResults()
+		}
+
+		advisor := suggest.NewAdvisor(config.NewConfig().GetForgeConfig())
+		advisor.MaxForks = maxForks
+		advisor.IsPrivate = func(modulePath string) bool {
+			return s.IsPrivate(ctx, modulePath)
+		}
+		if result.Summary.StaleThresholdDays > 0 {
+			advisor.ActiveDays = result.Summary.StaleThresholdDays
+		}
+		entries, err := advisor.Suggest(ctx, result)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(entries)
+		}
+		return suggest.Write(os.Stdout, entries)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+
+	addScannerFlags(suggestCmd)
+	suggestCmd.Flags().Int("max-forks", 3, "Number of active forks suggested per dependency, 0 skips the fork lookup")
+	suggestCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
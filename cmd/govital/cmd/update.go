@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/config"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/suggest"
+	"github.com/steffakasid/govital/pkg/tool"
+	"github.com/steffakasid/govital/pkg/update"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [result.json]",
+	Short: "Print or apply the go commands updating outdated dependencies",
+	Long: `Scan the project, or read the JSON result of a previous scan, and print the
+go get commands moving every outdated dependency to the latest version of its
+major version. Replaced dependencies are skipped.
+
+With --forks-only inactive, archived and deprecated dependencies are swapped
+for their most starred active fork instead, like suggested by govital
+suggest: a go mod edit -replace command per dependency pinning the latest
+version of the fork, followed by go mod tidy.
+
+The commands are printed as shell script, --apply runs them in the project
+directory and stops at the first failing one.`,
+	Example: `  govital update
+  govital update --apply
+  govital scan -o json > result.json && govital update result.json --forks-only`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+		apply, err := cmd.Flags().GetBool("apply")
+		if err != nil {
+			return err
+		}
+		forksOnly, err := cmd.Flags().GetBool("forks-only")
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		var result *scanner.ScanResult
+		if len(args) == 1 {
+			result, err = scanner.LoadResults(args[0])
+			if err != nil {
+				return err
+			}
+		} else {
+			if err := s.Scan(ctx); err != nil {
+				eslog.Logger.Error("Scan failed", slog.Any("error", err))
+				return err
+			}
+			result = s.GetResults()
+		}
+
+		var steps []update.Step
+		if forksOnly {
+			advisor := suggest.NewAdvisor(config.NewConfig().GetForgeConfig())
+			if result.Summary.StaleThresholdDays > 0 {
+				advisor.ActiveDays = result.Summary.StaleThresholdDays
+			}
+			entries, err := advisor.Suggest(ctx, result)
+			if err != nil {
+				return err
+			}
+			steps, err = update.ForkSwaps(ctx, entries, s.LatestVersion)
+			if err != nil {
+				return err
+			}
+		} else {
+			steps = update.Upgrades(result)
+		}
+
+		if apply {
+			for _, step := range steps {
+				eslog.Logger.Info("Running update", slog.String("command", step.Command()))
+				command := tool.CommandContext(ctx, projectPath, tool.Go, step.Args...)
+				command.Stdout = os.Stderr
+				command.Stderr = os.Stderr
+				if err := command.Run(); err != nil {
+					return fmt.Errorf("%s failed: %w", step.Command(), err)
+				}
+			}
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(steps)
+		}
+		if apply {
+			return nil
+		}
+		return update.Write(os.Stdout, steps)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	addScannerFlags(updateCmd)
+	updateCmd.Flags().Bool("apply", false, "Run the commands in the project directory instead of printing them")
+	updateCmd.Flags().Bool("forks-only", false, "Only swap inactive, archived and deprecated dependencies for their most starred active fork")
+	updateCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
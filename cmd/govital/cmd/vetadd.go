@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vet"
+)
+
+// defaultVetFailOn is used by govital vet-add if no condition is configured
+var defaultVetFailOn = []string{"inactive", "vulnerable", "retracted", "deprecated"}
+
+var vetAddCmd = &cobra.Command{
+	Use:   "vet-add <module>[@version]",
+	Short: "Evaluate a module before adding it as a dependency",
+	Long: `Scan a module which is not required yet and print a go/no-go recommendation
+against the fail-on conditions of the project. The report shows the
+maintenance status, health score, licenses, known vulnerabilities and the
+modules its go.mod would add to the project. Exits with code 2 on no-go.
+
+License and vulnerability checks are enabled unless turned off explicitly.
+Without --fail-on the policy.fail_on list of the config file is used,
+falling back to inactive, vulnerable, retracted and deprecated.`,
+	Example: `  govital vet-add github.com/new/lib@latest
+  govital vet-add github.com/new/lib@v1.4.0 --fail-on "score<60"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q, expected text or json", output)
+		}
+
+		conditions, err := failOnConditions(cmd, defaultVetFailOn)
+		if err != nil {
+			return err
+		}
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("check-licenses") {
+			s.SetCheckLicenses(true)
+		}
+		if !cmd.Flags().Changed("check-vulnerabilities") {
+			s.SetCheckVulnerabilities(true)
+		}
+
+		ctx, cancel, err := scanContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		candidate, requirements, err := s.ResolveRemote(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		candidate, err = scanModule(ctx, s, candidate)
+		if err != nil {
+			return err
+		}
+
+		project, err := projectRequirements(s, projectPath)
+		if err != nil {
+			return err
+		}
+
+		report := vet.Evaluate(candidate, requirements, project, conditions)
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			vet.Write(os.Stdout, report)
+		}
+
+		if !report.Go {
+			cmd.SilenceUsage = true
+			return &exitError{
+				code: exitCodePolicyViolation,
+				err:  fmt.Errorf("%s meets a fail-on condition", candidate.Path),
+			}
+		}
+		return nil
+	},
+}
+
+// projectRequirements returns all modules the go.mod of the project
+// requires, including indirect ones. Without go.mod every module is new.
+func projectRequirements(s *scanner.Scanner, projectPath string) ([]scanner.Dependency, error) {
+	goMod, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		eslog.Logger.Warn("No go.mod, counting all requirements as new", slog.String("project", projectPath), slog.Any("error", err))
+		return nil, nil
+	}
+	s.SetIncludeIndirectDependencies(true)
+	return s.ParseGoMod(goMod)
+}
+
+func init() {
+	rootCmd.AddCommand(vetAddCmd)
+
+	addScannerFlags(vetAddCmd)
+	addFailOnFlag(vetAddCmd)
+	vetAddCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
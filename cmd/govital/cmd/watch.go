@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/watch"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Rescan changed dependencies whenever go.mod or go.sum change",
+	Long: `Scan the project once and watch its go.mod and go.sum for changes, e.g. by
+go get or go mod tidy during development. After every change only the
+requirements which were added or are required at another version are scanned,
+and the dependency changes are printed in the compact format of 'govital diff':
+added (+), removed (-) and changed (~) dependencies with their status.
+
+Rescans wait until the files stayed unchanged for --debounce. A go.mod which
+can't be parsed, e.g. in the middle of an edit, keeps the previous result.
+Workspaces (go.work) are not watched.`,
+	Example: `  govital watch
+  govital watch -p ./service --include-indirect --check-vulnerabilities`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, err := cmd.Flags().GetString("project-path")
+		if err != nil {
+			return err
+		}
+		debounce, err := cmd.Flags().GetDuration("debounce")
+		if err != nil {
+			return err
+		}
+
+		s, err := newScanner(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+		scan := func(ctx context.Context, deps []scanner.Dependency) (*scanner.ScanResult, error) {
+			return scanDependencies(ctx, cmd, projectPath, deps, nil)
+		}
+		watcher := watch.New(projectPath, s.ParseGoMod, scan, os.Stdout)
+		watcher.Debounce = debounce
+		return watcher.Run(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	addScannerFlags(watchCmd)
+	watchCmd.Flags().Duration("debounce", watch.DefaultDebounce, "How long go.mod and go.sum need to stay unchanged before a rescan")
+}
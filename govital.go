@@ -0,0 +1,202 @@
+// Package govital analyzes the health of the dependencies of Go projects.
+// It wires the scanner, the enrichment by vulnerability, license and
+// repository lookups, the health score and the fail-on policy together the
+// way the govital command does, so Go programs can embed it with one call:
+//
+//	report, err := govital.Analyze(ctx, "./myproject", govital.Options{
+//		CheckVulnerabilities: true,
+//		FailOn:               []string{"inactive", "vulnerable"},
+//	})
+//
+// Analyze only reads the project, it never modifies files. The packages
+// below pkg/ remain available for finer control.
+package govital
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/score"
+	"github.com/steffakasid/govital/pkg/transport"
+)
+
+// Options configure an analysis. The zero value scans the direct
+// dependencies of a local project like 'govital scan' without config file.
+type Options struct {
+	// Remote analyzes a published module fetched from the Go proxy instead
+	// of a local project. The path is a module path with optional version
+	// query then, e.g. github.com/org/repo@v1.2.0.
+	Remote bool
+	// StaleThresholdDays is the number of days a dependency can be inactive
+	// before it is stale. Default: 180
+	StaleThresholdDays int
+	// ReleaseThresholdDays is the number of days a dependency can go without
+	// a tagged release before it is stale, even if it has newer commits.
+	// Default: 0, disabled
+	ReleaseThresholdDays int
+	// IncludeIndirect includes indirect dependencies
+	IncludeIndirect bool
+	// Workers is the number of dependencies looked up in parallel, 0 keeps
+	// the default and a negative value adapts the concurrency to the network
+	Workers int
+	// Quick only checks the release times of the used versions
+	Quick bool
+	// Recursive scans every module found below the project path
+	Recursive bool
+	// Offline reads the release times from the local module cache instead
+	// of the network
+	Offline bool
+
+	// CheckVulnerabilities looks up known vulnerabilities in the OSV database
+	CheckVulnerabilities bool
+	// CheckLicenses looks up the licenses on deps.dev
+	CheckLicenses bool
+	// CheckRepositories looks up archived repositories on GitHub, GitLab, Bitbucket, Gitea/Forgejo and sourcehut
+	// and counts their contributors, with the tokens of Forge
+	CheckRepositories bool
+	Forge             forge.Config
+	// CheckResponsiveness measures the median response time to recent
+	// issues and counts recently merged pull requests, implies
+	// CheckRepositories
+	CheckResponsiveness bool
+	// MaxResponseDays is the median number of days maintainers may take to
+	// respond to issues before a dependency is inactive. Default: 0, disabled
+	MaxResponseDays int
+	// MinContributors is the number of contributors in the last 12 months
+	// below which a dependency is a bus factor risk. Default: 2, negative
+	// values disable the check
+	MinContributors int
+
+	// Acknowledged are module paths known to be inactive, which are not
+	// reported as such
+	Acknowledged []string
+	// Overrides adjust the checks per module path
+	Overrides []scanner.Override
+	// Owners annotate dependencies with the teams owning them
+	Owners []owners.Rule
+	// ScoreWeights weight the signals of the health score, nil keeps the
+	// default weights
+	ScoreWeights *score.Weights
+	// RateLimits override the request rate of hosts
+	RateLimits []transport.HostLimit
+	// Retries configure the retries of failed requests and the circuit
+	// breaker, nil keeps transport.DefaultRetryConfig
+	Retries *transport.RetryConfig
+	// Baseline is a previous result of the project to detect license
+	// changes against
+	Baseline *scanner.ScanResult
+
+	// FailOn are conditions like inactive or score<50, see 'govital check'.
+	// Dependencies meeting any of them are reported as violations.
+	FailOn []string
+
+	// OnDependencyScanned receives each dependency as soon as it is scanned
+	OnDependencyScanned func(scanner.Dependency)
+}
+
+// Report is the outcome of an analysis: the scan result with all
+// enrichments and the violations of the fail-on conditions
+type Report struct {
+	scanner.ScanResult
+	Violations []policy.Violation `json:"violations,omitempty"`
+}
+
+// Passed returns whether no dependency meets a fail-on condition
+func (r *Report) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Analyze scans the dependencies of the Go project at path, or of the
+// published module path if Options.Remote is set, and evaluates the fail-on
+// conditions. Cancelling ctx aborts the analysis.
+func Analyze(ctx context.Context, path string, opts Options) (*Report, error) {
+	conditions, err := policy.ParseConditions(opts.FailOn)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newScanner(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Remote {
+		err = s.ScanRemote(ctx, path)
+	} else {
+		err = s.Scan(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.GetResults()
+	return &Report{
+		ScanResult: *result,
+		Violations: policy.Evaluate(result, conditions),
+	}, nil
+}
+
+// newScanner configures a scanner from the options
+func newScanner(path string, opts Options) (*scanner.Scanner, error) {
+	if opts.StaleThresholdDays < 0 {
+		return nil, fmt.Errorf("invalid stale threshold %d, expected a number of days", opts.StaleThresholdDays)
+	}
+	if opts.ReleaseThresholdDays < 0 {
+		return nil, fmt.Errorf("invalid release threshold %d, expected a number of days", opts.ReleaseThresholdDays)
+	}
+	if opts.MaxResponseDays < 0 {
+		return nil, fmt.Errorf("invalid maximum response time %d, expected a number of days", opts.MaxResponseDays)
+	}
+
+	s := scanner.NewScanner(path)
+	if opts.StaleThresholdDays > 0 {
+		s.SetStaleThreshold(opts.StaleThresholdDays)
+	}
+	s.SetReleaseThreshold(opts.ReleaseThresholdDays)
+	s.SetIncludeIndirectDependencies(opts.IncludeIndirect)
+	switch {
+	case opts.Workers < 0:
+		s.SetAutoWorkers()
+	case opts.Workers > 0:
+		s.SetWorkers(opts.Workers)
+	}
+	s.SetQuick(opts.Quick)
+	s.SetRecursive(opts.Recursive)
+	s.SetOffline(opts.Offline)
+
+	s.SetCheckVulnerabilities(opts.CheckVulnerabilities)
+	s.SetCheckLicenses(opts.CheckLicenses)
+	s.SetCheckRepositories(opts.CheckRepositories || opts.CheckResponsiveness, opts.Forge)
+	s.SetCheckResponsiveness(opts.CheckResponsiveness)
+	s.SetMaxResponseDays(opts.MaxResponseDays)
+	if opts.MinContributors != 0 {
+		s.SetMinContributors(max(opts.MinContributors, 0))
+	}
+
+	if len(opts.Acknowledged) > 0 {
+		s.SetAcknowledgedDependencies(opts.Acknowledged)
+	}
+	if err := s.SetOverrides(opts.Overrides); err != nil {
+		return nil, err
+	}
+	matcher, err := owners.NewMatcher(opts.Owners)
+	if err != nil {
+		return nil, err
+	}
+	s.SetOwners(matcher)
+	if opts.ScoreWeights != nil {
+		s.SetScoreWeights(*opts.ScoreWeights)
+	}
+	s.SetRateLimits(opts.RateLimits)
+	if opts.Retries != nil {
+		s.SetRetries(*opts.Retries)
+	}
+	if opts.Baseline != nil {
+		s.SetBaseline(opts.Baseline)
+	}
+	s.OnDependencyScanned(opts.OnDependencyScanned)
+	return s, nil
+}
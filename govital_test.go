@@ -0,0 +1,52 @@
+package govital
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0o644))
+
+	report, err := Analyze(context.Background(), dir, Options{StaleThresholdDays: 30, FailOn: []string{"inactive"}})
+	require.NoError(t, err)
+	assert.Equal(t, dir, report.ProjectPath)
+	assert.Empty(t, report.Dependencies)
+	assert.Equal(t, 30, report.Summary.StaleThresholdDays)
+	assert.True(t, report.Passed())
+	require.NotNil(t, report.Fingerprint)
+	assert.Equal(t, "example.com/app", report.Fingerprint.Module)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Analyze(context.Background(), dir, Options{FailOn: []string{"sometimes"}})
+	assert.Error(t, err, "invalid condition")
+
+	_, err = Analyze(context.Background(), dir, Options{StaleThresholdDays: -1})
+	assert.ErrorContains(t, err, "invalid stale threshold")
+
+	_, err = Analyze(context.Background(), dir, Options{Overrides: []scanner.Override{{Ignore: true}}})
+	assert.ErrorContains(t, err, "has no path")
+
+	_, err = Analyze(context.Background(), dir, Options{})
+	assert.ErrorContains(t, err, "go.mod not found")
+}
+
+func TestReportPassed(t *testing.T) {
+	dep := scanner.Dependency{Path: "example.com/stale"}
+	report := &Report{ScanResult: scanner.ScanResult{Dependencies: []scanner.Dependency{dep}}}
+	assert.True(t, report.Passed())
+
+	report.Violations = append(report.Violations, policy.Violation{Dependency: dep, Conditions: []string{"inactive"}})
+	assert.False(t, report.Passed())
+}
@@ -0,0 +1,28 @@
+// Package dirs locates the files govital keeps for the user
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Home returns elem joined to the govital directory in the home directory of
+// the user, ~/.govital. Without a known home directory, e.g. if $HOME is
+// unset, it is .govital in the working directory.
+func Home(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(append([]string{home, ".govital"}, elem...)...)
+}
+
+// Cache returns elem joined to the govital directory in the cache directory
+// of the user, e.g. ~/.cache/govital on Linux, falling back to Home("cache")
+func Cache(elem ...string) string {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return Home(append([]string{"cache"}, elem...)...)
+	}
+	return filepath.Join(append([]string{cache, "govital"}, elem...)...)
+}
@@ -0,0 +1,33 @@
+package dirs
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("home directory not read from $HOME")
+	}
+	t.Setenv("HOME", "/home/user")
+	assert.Equal(t, filepath.FromSlash("/home/user/.govital"), Home())
+	assert.Equal(t, filepath.FromSlash("/home/user/.govital/history.jsonl"), Home("history.jsonl"))
+
+	t.Setenv("HOME", "")
+	assert.Equal(t, filepath.FromSlash(".govital/govital.db"), Home("govital.db"), "no home directory")
+}
+
+func TestCache(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CACHE_HOME is only read on Unix")
+	}
+	t.Setenv("XDG_CACHE_HOME", "/var/cache/user")
+	assert.Equal(t, "/var/cache/user/govital", Cache())
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+	assert.Equal(t, ".govital/cache/responses", Cache("responses"), "no cache directory")
+}
@@ -1,7 +0,0 @@
-package version
-
-var (
-	Version   = "dev"
-	BuildDate = "unknown"
-	GitCommit = "unknown"
-)
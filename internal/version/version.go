@@ -0,0 +1,13 @@
+package version
+
+// Set via ldflags by goreleaser
+var (
+	Version   = "dev"
+	BuildDate = "unknown"
+	GitCommit = "unknown"
+)
+
+// UserAgent identifies govital in requests to proxies and public APIs
+func UserAgent() string {
+	return "govital/" + Version + " (+https://github.com/steffakasid/govital)"
+}
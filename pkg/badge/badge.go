@@ -0,0 +1,143 @@
+package badge
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Kind selects what a badge shows
+type Kind string
+
+const (
+	// Inactive shows the number of inactive dependencies, e.g. "3 inactive"
+	Inactive Kind = "inactive"
+	// Grade shows an overall health grade from A to F
+	Grade Kind = "grade"
+)
+
+// Shield colors
+const (
+	colorBrightGreen = "#4c1"
+	colorGreen       = "#97ca00"
+	colorYellow      = "#dfb317"
+	colorOrange      = "#fe7d37"
+	colorRed         = "#e05d44"
+	colorGrey        = "#9f9f9f"
+)
+
+// Badge is a shield with a label on the left and a message on the right
+type Badge struct {
+	Label   string
+	Message string
+	// Color is the background color of the message
+	Color string
+}
+
+// ParseKind validates the kind of a badge
+func ParseKind(kind string) (Kind, error) {
+	switch Kind(kind) {
+	case Inactive, Grade:
+		return Kind(kind), nil
+	}
+	return "", fmt.Errorf("invalid badge kind %q, expected inactive or grade", kind)
+}
+
+// ForResult returns the badge of the given kind for a scan result. The label
+// defaults to "deps" for inactive and to "health" for grade badges.
+func ForResult(result *scanner.ScanResult, kind Kind, label string) Badge {
+	switch kind {
+	case Grade:
+		if label == "" {
+			label = "health"
+		}
+		grade, color := HealthGrade(result)
+		return Badge{Label: label, Message: grade, Color: color}
+	default:
+		if label == "" {
+			label = "deps"
+		}
+		inactive := result.Summary.Inactive
+		switch {
+		case result.Summary.Total == 0:
+			return Badge{Label: label, Message: "none", Color: colorGrey}
+		case inactive == 0:
+			return Badge{Label: label, Message: "all active", Color: colorBrightGreen}
+		case inactive*10 < result.Summary.Total:
+			return Badge{Label: label, Message: fmt.Sprintf("%d inactive", inactive), Color: colorYellow}
+		default:
+			return Badge{Label: label, Message: fmt.Sprintf("%d inactive", inactive), Color: colorRed}
+		}
+	}
+}
+
+// HealthGrade grades the result by the mean health score of its
+// dependencies, or by the share of active dependencies if no score is
+// known: A from 90, B from 75, C from 60, D from 40, F below. Known
+// vulnerabilities cap the grade at C.
+func HealthGrade(result *scanner.ScanResult) (string, string) {
+	var sum, count int
+	for _, dep := range result.Dependencies {
+		if dep.Score != nil {
+			sum += *dep.Score
+			count++
+		}
+	}
+	var value int
+	switch {
+	case count > 0:
+		value = sum / count
+	case result.Summary.Total > 0:
+		value = 100 * (result.Summary.Total - result.Summary.Inactive) / result.Summary.Total
+	default:
+		return "unknown", colorGrey
+	}
+	if result.Summary.Vulnerable > 0 {
+		value = min(value, 74)
+	}
+
+	switch {
+	case value >= 90:
+		return "A", colorBrightGreen
+	case value >= 75:
+		return "B", colorGreen
+	case value >= 60:
+		return "C", colorYellow
+	case value >= 40:
+		return "D", colorOrange
+	default:
+		return "F", colorRed
+	}
+}
+
+// textWidth estimates the width of text in the 11px Verdana of shields.
+// Badges only hold short ASCII texts, so an average width suffices.
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)*7 + 10
+}
+
+// WriteSVG writes the badge as flat shield in the style of shields.io
+func (b Badge) WriteSVG(w io.Writer) error {
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	labelWidth, messageWidth := textWidth(b.Label), textWidth(b.Message)
+	width := labelWidth + messageWidth
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, message)
+	fmt.Fprintf(&svg, `<title>%s: %s</title>`, label, message)
+	svg.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&svg, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&svg, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		labelWidth, labelWidth, messageWidth, b.Color, width)
+	svg.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&svg, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, labelWidth/2, label, labelWidth/2, label)
+	fmt.Fprintf(&svg, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, message, labelWidth+messageWidth/2, message)
+	svg.WriteString("</g></svg>\n")
+
+	_, err := io.WriteString(w, svg.String())
+	return err
+}
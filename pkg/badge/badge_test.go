@@ -0,0 +1,69 @@
+package badge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestForResultInactive(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  scanner.Summary
+		expected Badge
+	}{
+		{"no dependencies", scanner.Summary{}, Badge{Label: "deps", Message: "none", Color: colorGrey}},
+		{"all active", scanner.Summary{Total: 10}, Badge{Label: "deps", Message: "all active", Color: colorBrightGreen}},
+		{"few inactive", scanner.Summary{Total: 20, Inactive: 1}, Badge{Label: "deps", Message: "1 inactive", Color: colorYellow}},
+		{"many inactive", scanner.Summary{Total: 20, Inactive: 3}, Badge{Label: "deps", Message: "3 inactive", Color: colorRed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ForResult(&scanner.ScanResult{Summary: tt.summary}, Inactive, ""))
+		})
+	}
+}
+
+func TestHealthGrade(t *testing.T) {
+	scored := &scanner.ScanResult{Dependencies: []scanner.Dependency{{Score: intPtr(95)}, {Score: intPtr(85)}}}
+	grade, color := HealthGrade(scored)
+	assert.Equal(t, "A", grade)
+	assert.Equal(t, colorBrightGreen, color)
+
+	scored.Summary.Vulnerable = 1
+	grade, _ = HealthGrade(scored)
+	assert.Equal(t, "C", grade, "vulnerabilities cap the grade")
+
+	unscored := &scanner.ScanResult{Summary: scanner.Summary{Total: 4, Inactive: 2}}
+	grade, _ = HealthGrade(unscored)
+	assert.Equal(t, "D", grade)
+
+	grade, _ = HealthGrade(&scanner.ScanResult{})
+	assert.Equal(t, "unknown", grade)
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Badge{Label: "deps", Message: "3 <inactive>", Color: colorRed}.WriteSVG(&buf))
+
+	svg := buf.String()
+	assert.Contains(t, svg, `<svg xmlns="http://www.w3.org/2000/svg" width="132" height="20"`)
+	assert.Contains(t, svg, `<title>deps: 3 &lt;inactive&gt;</title>`)
+	assert.Contains(t, svg, `fill="#e05d44"`)
+}
+
+func TestParseKind(t *testing.T) {
+	kind, err := ParseKind("grade")
+	require.NoError(t, err)
+	assert.Equal(t, Grade, kind)
+
+	_, err = ParseKind("stars")
+	assert.Error(t, err)
+}
@@ -0,0 +1,341 @@
+// Package batch scans many published modules, e.g. all repositories of an
+// organization, and writes a result file per module plus an index. Runs
+// are resumable: modules with a result from a previous run are skipped.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// IndexFile is the name of the index in the output directory
+const IndexFile = "index.json"
+
+// ResultsDir is the directory of the result files in the output directory
+const ResultsDir = "results"
+
+// States of an index entry
+const (
+	Succeeded = "succeeded"
+	Failed    = "failed"
+)
+
+// ScanFunc scans a module with optional version query, e.g.
+// github.com/org/repo@latest
+type ScanFunc func(ctx context.Context, target string) (*scanner.ScanResult, error)
+
+// Entry is the outcome of the scan of a module
+type Entry struct {
+	Target string `json:"target"`
+	// Forge is the host of the module path, e.g. github.com
+	Forge string `json:"forge"`
+	State string `json:"state"`
+	// File is the result file relative to the output directory, with
+	// forward slashes
+	File      string           `json:"file,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	ScannedAt time.Time        `json:"scanned_at"`
+	Duration  string           `json:"duration"`
+	Summary   *scanner.Summary `json:"summary,omitempty"`
+}
+
+// Index rolls up the scans of a batch, sorted by target
+type Index struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Failed returns the entries of failed scans
+func (i *Index) Failed() []Entry {
+	var failed []Entry
+	for _, entry := range i.Entries {
+		if entry.State == Failed {
+			failed = append(failed, entry)
+		}
+	}
+	return failed
+}
+
+// Total sums up the summaries of all successful scans
+func (i *Index) Total() scanner.Summary {
+	var total scanner.Summary
+	for _, entry := range i.Entries {
+		if entry.Summary == nil {
+			continue
+		}
+		total.Total += entry.Summary.Total
+		total.Updated += entry.Summary.Updated
+		total.Inactive += entry.Summary.Inactive
+		total.Outdated += entry.Summary.Outdated
+		total.Vulnerable += entry.Summary.Vulnerable
+		total.Vulnerabilities += entry.Summary.Vulnerabilities
+		total.Errors += entry.Summary.Errors
+	}
+	return total
+}
+
+// Forge returns the host of the module path of target, which identifies
+// the forge serving the module
+func Forge(target string) string {
+	modulePath, _, _ := strings.Cut(target, "@")
+	host, _, _ := strings.Cut(modulePath, "/")
+	return host
+}
+
+// ResultFile returns the result file of target relative to the output
+// directory, with forward slashes. The module path is kept as directories,
+// so the files of an organization are grouped.
+func ResultFile(target string) string {
+	modulePath, _, _ := strings.Cut(target, "@")
+	return path.Join(ResultsDir, modulePath+".json")
+}
+
+// Runner scans modules concurrently, limited in total and per forge to
+// stay within the rate limits of the forges
+type Runner struct {
+	dir         string
+	scan        ScanFunc
+	concurrency int
+	perForge    int
+	now         func() time.Time
+
+	mutex sync.Mutex
+	index *Index
+}
+
+// NewRunner creates a runner writing to dir. By default 4 modules are
+// scanned at once, at most 2 of the same forge.
+func NewRunner(dir string, scan ScanFunc) *Runner {
+	return &Runner{dir: dir, scan: scan, concurrency: 4, perForge: 2, now: time.Now}
+}
+
+// SetConcurrency sets the number of modules scanned at once
+func (r *Runner) SetConcurrency(concurrency int) {
+	if concurrency > 0 {
+		r.concurrency = concurrency
+	}
+}
+
+// SetPerForgeLimit sets the number of modules of the same forge scanned at
+// once
+func (r *Runner) SetPerForgeLimit(limit int) {
+	if limit > 0 {
+		r.perForge = limit
+	}
+}
+
+// Run scans the targets and returns the updated index. With resume,
+// targets which succeeded in a previous run and whose result file still
+// exists are skipped, failed ones are retried. The index is saved after
+// every scan, so an interrupted run continues where it stopped. Failed
+// scans are recorded in the index and don't stop the others.
+func (r *Runner) Run(ctx context.Context, targets []string, resume bool) (*Index, error) {
+	if err := validateTargets(targets); err != nil {
+		return nil, err
+	}
+
+	r.index = &Index{}
+	if resume {
+		previous, err := LoadIndex(r.dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if previous != nil {
+			r.index = previous
+		}
+	}
+
+	var pending []string
+	for _, target := range targets {
+		if resume && r.done(target) {
+			continue
+		}
+		pending = append(pending, target)
+	}
+	eslog.Infof("Scanning %d of %d modules, %d done in a previous run", len(pending), len(targets), len(targets)-len(pending))
+
+	all := make(chan struct{}, r.concurrency)
+	forges := make(map[string]chan struct{})
+	for _, target := range pending {
+		if forges[Forge(target)] == nil {
+			forges[Forge(target)] = make(chan struct{}, r.perForge)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var saveErr error
+	var saveOnce sync.Once
+	for _, target := range pending {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			forge := forges[Forge(target)]
+			if !acquire(ctx, forge) {
+				return
+			}
+			defer func() { <-forge }()
+			if !acquire(ctx, all) {
+				return
+			}
+			defer func() { <-all }()
+
+			if err := r.scanOne(ctx, target); err != nil {
+				saveOnce.Do(func() { saveErr = err })
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	if saveErr != nil {
+		return r.index, saveErr
+	}
+	return r.index, ctx.Err()
+}
+
+func acquire(ctx context.Context, semaphore chan struct{}) bool {
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func validateTargets(targets []string) error {
+	seen := make(map[string]string)
+	for _, target := range targets {
+		modulePath, _, _ := strings.Cut(target, "@")
+		if modulePath == "" || strings.Contains(modulePath, "..") || path.IsAbs(modulePath) {
+			return fmt.Errorf("invalid module %q", target)
+		}
+		if other, ok := seen[modulePath]; ok {
+			return fmt.Errorf("module %s is listed twice, as %s and %s", modulePath, other, target)
+		}
+		seen[modulePath] = target
+	}
+	return nil
+}
+
+// done returns whether target succeeded and its result file exists
+func (r *Runner) done(target string) bool {
+	for _, entry := range r.index.Entries {
+		if entry.Target != target {
+			continue
+		}
+		if entry.State != Succeeded {
+			return false
+		}
+		_, err := os.Stat(filepath.Join(r.dir, filepath.FromSlash(entry.File)))
+		return err == nil
+	}
+	return false
+}
+
+// scanOne scans target and records the outcome, it only fails if the
+// result or the index can't be saved
+func (r *Runner) scanOne(ctx context.Context, target string) error {
+	started := r.now()
+	result, err := r.scan(ctx, target)
+	if ctx.Err() != nil {
+		// Interrupted scans are retried by the next run
+		return nil
+	}
+
+	entry := Entry{
+		Target:    target,
+		Forge:     Forge(target),
+		State:     Succeeded,
+		ScannedAt: started.UTC(),
+		Duration:  r.now().Sub(started).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		eslog.Warnf("Scan of %s failed: %v", target, err)
+		entry.State = Failed
+		entry.Error = err.Error()
+	} else {
+		entry.File = ResultFile(target)
+		entry.Summary = &result.Summary
+		if err := writeJSON(filepath.Join(r.dir, filepath.FromSlash(entry.File)), result); err != nil {
+			return err
+		}
+		eslog.Infof("Scanned %s, %d dependencies", target, result.Summary.Total)
+	}
+	return r.record(entry)
+}
+
+// record replaces the entry of the target in the index and saves it
+func (r *Runner) record(entry Entry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := r.index.Entries[:0]
+	for _, existing := range r.index.Entries {
+		if existing.Target != entry.Target {
+			entries = append(entries, existing)
+		}
+	}
+	r.index.Entries = append(entries, entry)
+	sort.Slice(r.index.Entries, func(i, j int) bool {
+		return r.index.Entries[i].Target < r.index.Entries[j].Target
+	})
+	r.index.UpdatedAt = r.now().UTC()
+	return writeJSON(filepath.Join(r.dir, IndexFile), r.index)
+}
+
+// LoadIndex reads the index of the output directory dir
+func LoadIndex(dir string) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, IndexFile), err)
+	}
+	return &index, nil
+}
+
+// writeJSON replaces the file atomically, so an interrupted run never
+// leaves a truncated file behind
+func writeJSON(name string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory of %s: %w", name, err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(file.Name(), name); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
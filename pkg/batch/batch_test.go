@@ -0,0 +1,133 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgeAndResultFile(t *testing.T) {
+	assert.Equal(t, "github.com", Forge("github.com/org/repo@main"))
+	assert.Equal(t, "golang.org", Forge("golang.org/x/mod"))
+	assert.Equal(t, "results/github.com/org/repo.json", ResultFile("github.com/org/repo@v1.2.0"))
+	assert.Equal(t, "results/github.com/org/repo/v2.json", ResultFile("github.com/org/repo/v2"))
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	var mutex sync.Mutex
+	var scanned []string
+	scan := func(ctx context.Context, target string) (*scanner.ScanResult, error) {
+		mutex.Lock()
+		scanned = append(scanned, target)
+		mutex.Unlock()
+		if target == "gitlab.com/org/broken" {
+			return nil, errors.New("no go.mod")
+		}
+		return &scanner.ScanResult{ProjectPath: target, Summary: scanner.Summary{Total: 2, Inactive: 1}}, nil
+	}
+	targets := []string{"github.com/org/b@latest", "github.com/org/a", "gitlab.com/org/broken"}
+
+	runner := NewRunner(dir, scan)
+	index, err := runner.Run(context.Background(), targets, true)
+	require.NoError(t, err)
+	require.Len(t, index.Entries, 3)
+	assert.Equal(t, "github.com/org/a", index.Entries[0].Target, "sorted by target")
+	assert.Equal(t, Succeeded, index.Entries[0].State)
+	assert.Equal(t, "results/github.com/org/a.json", index.Entries[0].File)
+	assert.Equal(t, Failed, index.Entries[2].State)
+	assert.Equal(t, "no go.mod", index.Entries[2].Error)
+	assert.Len(t, index.Failed(), 1)
+	assert.Equal(t, scanner.Summary{Total: 4, Inactive: 2}, index.Total())
+
+	saved, err := LoadIndex(dir)
+	require.NoError(t, err)
+	assert.Equal(t, index.Entries, saved.Entries)
+	_, err = os.Stat(filepath.Join(dir, "results", "github.com", "org", "b.json"))
+	assert.NoError(t, err)
+
+	t.Run("resume retries failed and missing results", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "results", "github.com", "org", "b.json")))
+		scanned = nil
+		index, err := NewRunner(dir, scan).Run(context.Background(), targets, true)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"github.com/org/b@latest", "gitlab.com/org/broken"}, scanned)
+		assert.Len(t, index.Entries, 3)
+	})
+
+	t.Run("without resume everything is scanned", func(t *testing.T) {
+		scanned = nil
+		index, err := NewRunner(dir, scan).Run(context.Background(), targets[:1], false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"github.com/org/b@latest"}, scanned)
+		assert.Len(t, index.Entries, 1)
+	})
+}
+
+func TestRunLimitsConcurrency(t *testing.T) {
+	var running, maxRunning, maxGitHub, runningGitHub atomic.Int32
+	scan := func(ctx context.Context, target string) (*scanner.ScanResult, error) {
+		github := Forge(target) == "github.com"
+		updateMax(&maxRunning, running.Add(1))
+		if github {
+			updateMax(&maxGitHub, runningGitHub.Add(1))
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		if github {
+			runningGitHub.Add(-1)
+		}
+		return &scanner.ScanResult{}, nil
+	}
+	targets := []string{
+		"github.com/org/a", "github.com/org/b", "github.com/org/c", "github.com/org/d",
+		"gitlab.com/org/a", "gitlab.com/org/b", "bitbucket.org/org/a",
+	}
+
+	runner := NewRunner(t.TempDir(), scan)
+	runner.SetConcurrency(3)
+	runner.SetPerForgeLimit(1)
+	index, err := runner.Run(context.Background(), targets, false)
+	require.NoError(t, err)
+	assert.Len(t, index.Entries, len(targets))
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Equal(t, int32(1), maxGitHub.Load())
+}
+
+func updateMax(max *atomic.Int32, value int32) {
+	for {
+		current := max.Load()
+		if value <= current || max.CompareAndSwap(current, value) {
+			return
+		}
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scan := func(ctx context.Context, target string) (*scanner.ScanResult, error) {
+		cancel()
+		return nil, ctx.Err()
+	}
+
+	index, err := NewRunner(t.TempDir(), scan).Run(ctx, []string{"github.com/org/a"}, false)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, index.Entries, "interrupted scans are retried by the next run")
+}
+
+func TestRunInvalidTargets(t *testing.T) {
+	runner := NewRunner(t.TempDir(), nil)
+	_, err := runner.Run(context.Background(), []string{"github.com/org/a@v1", "github.com/org/a@main"}, false)
+	assert.ErrorContains(t, err, "listed twice")
+	_, err = runner.Run(context.Background(), []string{"github.com/../etc"}, false)
+	assert.ErrorContains(t, err, "invalid module")
+}
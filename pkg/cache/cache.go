@@ -0,0 +1,205 @@
+// Package cache keeps the upstream responses of scans on disk, so repeated
+// scans of the same dependencies don't query the Go proxy and the forges
+// again. A nightly 'govital cache warm' keeps it filled for interactive and
+// CI scans.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/internal/dirs"
+)
+
+// DefaultTTL is how long cached responses are used
+const DefaultTTL = 24 * time.Hour
+
+// Config configures the response cache
+type Config struct {
+	// Enabled makes scans read and fill the cache
+	Enabled bool `mapstructure:"enabled"`
+	// Dir holds one file per cached response
+	Dir string `mapstructure:"dir"`
+	// TTL is how long responses are used, DefaultTTL if zero
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// DefaultDir is the govital directory in the cache directory of the user
+func DefaultDir() string {
+	return dirs.Cache()
+}
+
+// entry is a cached response
+type entry struct {
+	URL        string      `json:"url"`
+	StoredAt   time.Time   `json:"stored_at"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// Stats counts the requests answered from the cache and those sent
+// upstream
+type Stats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Transport is an http.RoundTripper answering GET requests from the cache
+// while the cached response is fresh. Successful and not found responses
+// are cached, other responses and all other methods, like the batch
+// queries of the vulnerability database, always go upstream.
+type Transport struct {
+	next http.RoundTripper
+	dir  string
+	ttl  time.Duration
+	now  func() time.Time
+	// refresh sends all requests upstream and caches their responses
+	refresh bool
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New creates a cache in dir in front of next. Responses are used for ttl,
+// DefaultTTL if zero.
+func New(next http.RoundTripper, dir string, ttl time.Duration) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Transport{next: next, dir: dir, ttl: ttl, now: time.Now}
+}
+
+// SetRefresh makes the cache ignore cached responses and replace them with
+// fresh ones, which warms the cache
+func (t *Transport) SetRefresh(refresh bool) {
+	t.refresh = refresh
+}
+
+// Stats returns the cache hits and misses so far
+func (t *Transport) Stats() Stats {
+	return Stats{Hits: t.hits.Load(), Misses: t.misses.Load()}
+}
+
+// RoundTrip answers the request from the cache or forwards it
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+	path := t.path(req)
+	if !t.refresh {
+		if cached, ok := t.load(path); ok {
+			t.hits.Add(1)
+			return cached.response(req), nil
+		}
+	}
+	t.misses.Add(1)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !cacheable(resp.StatusCode) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", req.URL.Redacted(), err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	stored := entry{URL: req.URL.Redacted(), StoredAt: t.now().UTC(), StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: body}
+	if err := t.store(path, stored); err != nil {
+		// A failing cache only costs the next scan time
+		eslog.Debugf("Failed to cache %s: %v", stored.URL, err)
+	}
+	return resp, nil
+}
+
+func cacheable(status int) bool {
+	return status == http.StatusOK || status == http.StatusNotFound || status == http.StatusGone
+}
+
+// path is the file of the request, named by the hash of its URL. Request
+// headers are not part of the key, a cache is used by one user.
+func (t *Transport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(t.dir, name[:2], name+".json")
+}
+
+func (t *Transport) load(path string) (entry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			eslog.Debugf("Failed to read cached response %s: %v", path, err)
+		}
+		return entry{}, false
+	}
+	var cached entry
+	if err := json.Unmarshal(data, &cached); err != nil {
+		eslog.Debugf("Ignoring invalid cached response %s: %v", path, err)
+		return entry{}, false
+	}
+	if t.now().Sub(cached.StoredAt) >= t.ttl {
+		return entry{}, false
+	}
+	return cached, true
+}
+
+func (t *Transport) store(path string, stored entry) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// writeFile writes to a temporary file first, so concurrent scans never
+// read a partial file
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (e entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, requests *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := requests.Add(1)
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"request":%d}`, count)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestTransportCachesResponses(t *testing.T) {
+	var requests atomic.Int64
+	server := newServer(t, &requests)
+	cache := New(http.DefaultTransport, t.TempDir(), time.Hour)
+	client := &http.Client{Transport: cache}
+
+	status, body := get(t, client, server.URL+"/mod/@latest")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"request":1}`, body)
+
+	status, body = get(t, client, server.URL+"/mod/@latest")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"request":1}`, body)
+	assert.Equal(t, int64(1), requests.Load())
+
+	status, _ = get(t, client, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = get(t, client, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, int64(2), requests.Load())
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 2}, cache.Stats())
+}
+
+func TestTransportSkipsErrorsAndOtherMethods(t *testing.T) {
+	var requests atomic.Int64
+	server := newServer(t, &requests)
+	client := &http.Client{Transport: New(http.DefaultTransport, t.TempDir(), time.Hour)}
+
+	get(t, client, server.URL+"/error")
+	status, _ := get(t, client, server.URL+"/error")
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Equal(t, int64(2), requests.Load())
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Post(server.URL+"/v1/querybatch", "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int64(4), requests.Load())
+}
+
+func TestTransportExpiresAndRefreshes(t *testing.T) {
+	var requests atomic.Int64
+	server := newServer(t, &requests)
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := New(http.DefaultTransport, dir, time.Hour)
+	cache.now = func() time.Time { return now }
+	client := &http.Client{Transport: cache}
+
+	get(t, client, server.URL+"/mod/@latest")
+	now = now.Add(2 * time.Hour)
+	_, body := get(t, client, server.URL+"/mod/@latest")
+	assert.Equal(t, `{"request":2}`, body)
+
+	// A second cache on the same directory, like the next scan, uses the
+	// stored response
+	next := New(http.DefaultTransport, dir, time.Hour)
+	next.now = cache.now
+	_, body = get(t, &http.Client{Transport: next}, server.URL+"/mod/@latest")
+	assert.Equal(t, `{"request":2}`, body)
+
+	next.SetRefresh(true)
+	_, body = get(t, &http.Client{Transport: next}, server.URL+"/mod/@latest")
+	assert.Equal(t, `{"request":3}`, body)
+	next.SetRefresh(false)
+	_, body = get(t, &http.Client{Transport: next}, server.URL+"/mod/@latest")
+	assert.Equal(t, `{"request":3}`, body)
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Snapshot is the last scan of a project kept next to the cached
+// responses. Incremental scans reuse its dependencies whose version didn't
+// change, so only new and updated modules are looked up.
+type Snapshot struct {
+	// Lookups are the lookups the dependencies were scanned with
+	Lookups      string          `json:"lookups"`
+	Dependencies []SnapshotEntry `json:"dependencies"`
+}
+
+// SnapshotEntry is a dependency with the time it was looked up
+type SnapshotEntry struct {
+	CheckedAt  time.Time          `json:"checked_at"`
+	Dependency scanner.Dependency `json:"dependency"`
+}
+
+// SnapshotPath is the snapshot file of the project in the cache dir, named
+// by the hash of the project path
+func SnapshotPath(dir, project string) string {
+	if abs, err := filepath.Abs(project); err == nil {
+		project = abs
+	}
+	sum := sha256.Sum256([]byte(project))
+	return filepath.Join(dir, "snapshots", hex.EncodeToString(sum[:])+".json")
+}
+
+// LoadSnapshot reads the snapshot at path. Without snapshot the project
+// wasn't scanned incrementally yet and the snapshot is empty, an invalid
+// one is ignored.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		eslog.Warnf("Ignoring invalid snapshot %s: %v", path, err)
+		return &Snapshot{}, nil
+	}
+	return &snapshot, nil
+}
+
+// Fresh returns the dependencies looked up with the given lookups less
+// than ttl, DefaultTTL if zero, before now
+func (s *Snapshot) Fresh(lookups string, now time.Time, ttl time.Duration) []scanner.Dependency {
+	if s.Lookups != lookups {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	var deps []scanner.Dependency
+	for _, entry := range s.Dependencies {
+		if now.Sub(entry.CheckedAt) < ttl {
+			deps = append(deps, entry.Dependency)
+		}
+	}
+	return deps
+}
+
+// Update replaces the dependencies of the snapshot with the ones of result
+// and returns how many of them were reused. Reused dependencies keep the
+// time they were looked up, so they expire and get looked up again.
+// Dependencies with errors are left out to be retried by the next scan.
+func (s *Snapshot) Update(lookups string, result *scanner.ScanResult, reused []scanner.Dependency, now time.Time) int {
+	checked := make(map[string]time.Time)
+	if s.Lookups == lookups {
+		for _, entry := range s.Dependencies {
+			checked[snapshotKey(entry.Dependency)] = entry.CheckedAt
+		}
+	}
+	reusable := make(map[string]bool, len(reused))
+	for _, dep := range reused {
+		reusable[snapshotKey(dep)] = true
+	}
+
+	count := 0
+	seen := make(map[string]bool)
+	s.Lookups = lookups
+	s.Dependencies = nil
+	for _, dep := range result.Dependencies {
+		key := snapshotKey(dep)
+		if seen[key] || dep.Error != nil || len(dep.Errors) > 0 || dep.LastReleaseTime.IsZero() {
+			continue
+		}
+		seen[key] = true
+		entry := SnapshotEntry{CheckedAt: now.UTC(), Dependency: dep}
+		if at, ok := checked[key]; ok && reusable[key] {
+			entry.CheckedAt = at
+			count++
+		}
+		s.Dependencies = append(s.Dependencies, entry)
+	}
+	return count
+}
+
+// Save writes the snapshot to path
+func (s *Snapshot) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotKey identifies the requirement of a dependency like the
+// scanner does, by its version and replacement
+func snapshotKey(dep scanner.Dependency) string {
+	key := dep.Path + "@" + dep.Version
+	if dep.Replace != nil {
+		key += "=>" + dep.Replace.Path + "@" + dep.Replace.Version
+	}
+	return key
+}
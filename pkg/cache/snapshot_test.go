@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := SnapshotPath(dir, "project")
+	assert.Equal(t, SnapshotPath(dir, "./project"), path)
+	assert.NotEqual(t, SnapshotPath(dir, "other"), path)
+
+	snapshot, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Fresh("", time.Now(), time.Hour))
+
+	released := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstScan := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/a", Version: "v1.0.0", LastReleaseTime: released},
+		{Path: "github.com/example/a", Version: "v1.0.0", LastReleaseTime: released, Module: "example.com/other"},
+		{Path: "github.com/example/b", Version: "v1.0.0", LastReleaseTime: released},
+		{Path: "github.com/example/failed", Version: "v1.0.0", Error: &scanner.ScanError{Message: "timeout"}},
+	}}
+	assert.Equal(t, 0, snapshot.Update("licenses", result, nil, firstScan))
+	require.NoError(t, snapshot.Save(path))
+
+	snapshot, err = LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Dependencies, 2)
+	assert.Empty(t, snapshot.Fresh("", firstScan, time.Hour), "other lookups")
+	assert.Empty(t, snapshot.Fresh("licenses", firstScan.Add(time.Hour), time.Hour), "expired")
+	reused := snapshot.Fresh("licenses", firstScan.Add(30*time.Minute), time.Hour)
+	assert.Len(t, reused, 2)
+
+	// Reused dependencies keep the time they were looked up
+	secondScan := firstScan.Add(30 * time.Minute)
+	result.Dependencies[2].Version = "v1.1.0"
+	assert.Equal(t, 1, snapshot.Update("licenses", result, reused, secondScan))
+	require.Len(t, snapshot.Dependencies, 2)
+	assert.Equal(t, firstScan, snapshot.Dependencies[0].CheckedAt)
+	assert.Equal(t, secondScan, snapshot.Dependencies[1].CheckedAt)
+}
+
+func TestLoadInvalidSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	snapshot, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Dependencies)
+}
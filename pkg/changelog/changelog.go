@@ -0,0 +1,114 @@
+// Package changelog collects the release notes between the used and the
+// available version of a dependency, from forge releases or a CHANGELOG.md
+package changelog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// maxNoteLength bounds the notes per version, long release notes are
+// truncated to keep reports readable
+const maxNoteLength = 2000
+
+// Note holds the release notes of a single version
+type Note struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+	// URL is the web page of the release, empty for CHANGELOG.md sections
+	URL string `json:"url,omitempty"`
+}
+
+// TagPrefix returns the prefix of the release tags of a module in its
+// repository. Modules in subdirectories tag like sub/v1.2.0, major version
+// suffixes are not part of the tag.
+func TagPrefix(modulePath, repositoryRoot string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	subdirectory, found := strings.CutPrefix(prefix, repositoryRoot+"/")
+	if !found {
+		return ""
+	}
+	return subdirectory + "/"
+}
+
+// FromReleases returns the notes of the releases after from up to and
+// including to, the newest first. Only tags with the tag prefix of the
+// module are considered.
+func FromReleases(releases []forge.Release, tagPrefix, from, to string) []Note {
+	var notes []Note
+	for _, release := range releases {
+		version, ok := strings.CutPrefix(release.Tag, tagPrefix)
+		if !ok || !inRange(version, from, to) {
+			continue
+		}
+		notes = append(notes, Note{Version: version, Notes: truncate(release.Notes), URL: release.URL})
+	}
+	sortNewestFirst(notes)
+	return notes
+}
+
+// headingVersion matches Markdown headings naming a version, like
+// "## [1.2.0] - 2024-01-02" or "# v1.2.0"
+var headingVersion = regexp.MustCompile(`^#{1,4}\s.*?\bv?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)`)
+
+// ParseChangelog returns the sections of a CHANGELOG.md for the versions
+// after from up to and including to, the newest first. Every heading
+// naming a version starts a section, other headings belong to it.
+func ParseChangelog(content, from, to string) []Note {
+	var notes []Note
+	var current *Note
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.Notes = truncate(strings.TrimSpace(strings.Join(body, "\n")))
+			notes = append(notes, *current)
+		}
+		current, body = nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if match := headingVersion.FindStringSubmatch(line); match != nil {
+			flush()
+			if version := "v" + match[1]; inRange(version, from, to) {
+				current = &Note{Version: version}
+			}
+			continue
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+
+	sortNewestFirst(notes)
+	return notes
+}
+
+func inRange(version, from, to string) bool {
+	return semver.IsValid(version) && semver.Compare(version, from) > 0 && semver.Compare(version, to) <= 0
+}
+
+func truncate(notes string) string {
+	if len(notes) <= maxNoteLength {
+		return notes
+	}
+	cut := strings.LastIndex(notes[:maxNoteLength], "\n")
+	if cut <= 0 {
+		cut = maxNoteLength
+	}
+	return notes[:cut] + "\n…"
+}
+
+func sortNewestFirst(notes []Note) {
+	sort.SliceStable(notes, func(i, j int) bool {
+		return semver.Compare(notes[i].Version, notes[j].Version) > 0
+	})
+}
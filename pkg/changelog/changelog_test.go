@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagPrefix(t *testing.T) {
+	tests := []struct {
+		modulePath string
+		root       string
+		expected   string
+	}{
+		{"github.com/example/mod", "github.com/example/mod", ""},
+		{"github.com/example/mod/v2", "github.com/example/mod", ""},
+		{"github.com/example/mod/sub", "github.com/example/mod", "sub/"},
+		{"github.com/example/mod/sub/v3", "github.com/example/mod", "sub/"},
+		{"gopkg.in/yaml.v3", "github.com/go-yaml/yaml", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TagPrefix(tt.modulePath, tt.root))
+		})
+	}
+}
+
+func TestFromReleases(t *testing.T) {
+	releases := []forge.Release{
+		{Tag: "sub/v1.3.0", Notes: "other module"},
+		{Tag: "v1.3.0", Notes: "too new"},
+		{Tag: "v1.1.0", Notes: "first", URL: "https://example.com/v1.1.0"},
+		{Tag: "v1.2.0", Notes: "second"},
+		{Tag: "v1.0.0", Notes: "used"},
+		{Tag: "nightly", Notes: "not a version"},
+	}
+
+	notes := FromReleases(releases, "", "v1.0.0", "v1.2.0")
+
+	assert.Equal(t, []Note{
+		{Version: "v1.2.0", Notes: "second"},
+		{Version: "v1.1.0", Notes: "first", URL: "https://example.com/v1.1.0"},
+	}, notes)
+
+	notes = FromReleases(releases, "sub/", "v1.0.0", "v1.3.0")
+	assert.Equal(t, []Note{{Version: "v1.3.0", Notes: "other module"}}, notes)
+}
+
+func TestParseChangelog(t *testing.T) {
+	content := `# Changelog
+
+## [Unreleased]
+
+- pending
+
+## [1.2.0] - 2024-03-01
+
+### Fixed
+
+- crash on empty input
+
+## v1.1.0
+
+- new option
+
+## 1.0.0
+
+- initial release
+`
+
+	notes := ParseChangelog(content, "v1.0.0", "v1.2.0")
+
+	require.Len(t, notes, 2)
+	assert.Equal(t, "v1.2.0", notes[0].Version)
+	assert.Equal(t, "### Fixed\n\n- crash on empty input", notes[0].Notes, "subheadings belong to the version")
+	assert.Equal(t, "v1.1.0", notes[1].Version)
+	assert.Equal(t, "- new option", notes[1].Notes)
+	assert.Empty(t, notes[1].URL)
+}
+
+func TestTruncate(t *testing.T) {
+	long := strings.Repeat("line of release notes\n", 200)
+
+	truncated := truncate(long)
+
+	assert.LessOrEqual(t, len(truncated), maxNoteLength+len("\n…"))
+	assert.True(t, strings.HasSuffix(truncated, "notes\n…"), "cut at a line break")
+	assert.Equal(t, "short", truncate("short"))
+}
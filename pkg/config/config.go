@@ -1,15 +1,40 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/internal/dirs"
+	"github.com/steffakasid/govital/pkg/cache"
+	"github.com/steffakasid/govital/pkg/daemon"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/publish"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/score"
+	"github.com/steffakasid/govital/pkg/store"
+	"github.com/steffakasid/govital/pkg/transport"
 )
 
 var Viper *viper.Viper
 
+// Log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// DefaultDaemonSchedule rescans the projects every morning
+const DefaultDaemonSchedule = "0 6 * * *"
+
 type Config struct {
 	viper *viper.Viper
 }
@@ -29,15 +54,53 @@ func (c *Config) Init() {
 	c.viper.SetConfigType("yaml")
 	c.viper.AddConfigPath(".")
 	c.viper.AddConfigPath("/etc/govital/")
-	c.viper.AddConfigPath(os.ExpandEnv("$HOME/.config/govital"))
-	c.viper.AddConfigPath(os.ExpandEnv("$HOME/.govital"))
+	if home, err := os.UserHomeDir(); err == nil {
+		c.viper.AddConfigPath(filepath.Join(home, ".config", "govital"))
+	}
+	c.viper.AddConfigPath(dirs.Home())
 
 	// Set defaults
 	c.viper.SetDefault("log_level", "info")
+	c.viper.SetDefault("log_format", LogFormatText)
 	c.viper.SetDefault("scanner.stale_threshold_days", 180)
 	c.viper.SetDefault("scanner.active_threshold_days", 90)
+	c.viper.SetDefault("scanner.release_threshold_days", 0)
+	c.viper.SetDefault("scanner.min_contributors", 2)
 	c.viper.SetDefault("scanner.include_indirect_dependencies", false)
 	c.viper.SetDefault("scanner.acknowledged_dependencies", []string{})
+	c.viper.SetDefault("scanner.check_vulnerabilities", false)
+	c.viper.SetDefault("scanner.check_licenses", false)
+	c.viper.SetDefault("scanner.check_repositories", false)
+	c.viper.SetDefault("scanner.check_responsiveness", false)
+	c.viper.SetDefault("scanner.max_response_days", 0)
+	c.viper.SetDefault("scanner.fetch_release_notes", false)
+	c.viper.SetDefault("scanner.check_security_signals", false)
+	c.viper.SetDefault("scanner.count_imports", false)
+	c.viper.SetDefault("scanner.check_toolchain", false)
+	defaultTimeouts := scanner.DefaultTimeouts()
+	c.viper.SetDefault("scanner.git_timeout", defaultTimeouts.Git)
+	c.viper.SetDefault("scanner.http_timeout", defaultTimeouts.HTTP)
+	c.viper.SetDefault("scanner.per_dependency_timeout", defaultTimeouts.PerDependency)
+	c.viper.SetDefault("dependencies", []scanner.Override{})
+	c.viper.SetDefault("ignore", []string{})
+	c.viper.SetDefault("policy.fail_on", []string{})
+	c.viper.SetDefault("policy.strict", false)
+	c.viper.SetDefault("owners", []owners.Rule{})
+	c.viper.SetDefault("network.rate_limits", []transport.HostLimit{})
+	defaultRetries := transport.DefaultRetryConfig()
+	c.viper.SetDefault("network.retry.max_retries", defaultRetries.MaxRetries)
+	c.viper.SetDefault("network.retry.breaker_threshold", defaultRetries.BreakerThreshold)
+	c.viper.SetDefault("network.retry.breaker_cooldown", defaultRetries.BreakerCooldown)
+	c.viper.SetDefault("publish.elasticsearch.index", publish.DefaultIndex)
+	c.viper.SetDefault("daemon.schedule", DefaultDaemonSchedule)
+
+	defaultWeights := score.DefaultWeights()
+	c.viper.SetDefault("scoring.weights.recency", defaultWeights.Recency)
+	c.viper.SetDefault("scoring.weights.cadence", defaultWeights.Cadence)
+	c.viper.SetDefault("scoring.weights.archived", defaultWeights.Archived)
+	c.viper.SetDefault("scoring.weights.issues", defaultWeights.Issues)
+	c.viper.SetDefault("scoring.weights.contributors", defaultWeights.Contributors)
+	c.viper.SetDefault("scoring.weights.security", defaultWeights.Security)
 
 	// Read config file
 	if err := c.viper.ReadInConfig(); err != nil {
@@ -71,6 +134,21 @@ func (c *Config) GetLogLevelString() string {
 	return levelStr
 }
 
+// GetLogFormat returns the format of the log records, text or json. Unknown formats fall back to text.
+// Default: text
+func (c *Config) GetLogFormat() string {
+	format := c.viper.GetString("log_format")
+	switch format {
+	case LogFormatText, LogFormatJSON:
+		return format
+	case "":
+		return LogFormatText
+	default:
+		eslog.Warnf("Unknown log format %q, using %s", format, LogFormatText)
+		return LogFormatText
+	}
+}
+
 // Scanner configuration
 
 // GetStaleThresholdDays returns the number of days a dependency can be inactive before being marked as stale.
@@ -90,6 +168,30 @@ func (c *Config) SetStaleThresholdDays(days int) {
 	c.viper.Set("scanner.stale_threshold_days", days)
 }
 
+// GetReleaseThresholdDays returns the number of days a dependency can go without a tagged release before being
+// marked as stale, even if it has newer commits. 0 disables the check.
+// Default: 0
+func (c *Config) GetReleaseThresholdDays() int {
+	return c.viper.GetInt("scanner.release_threshold_days")
+}
+
+// SetReleaseThresholdDays sets the release threshold in the config.
+func (c *Config) SetReleaseThresholdDays(days int) {
+	c.viper.Set("scanner.release_threshold_days", days)
+}
+
+// GetMinContributors returns the number of distinct commit authors in the last 12 months below which a
+// dependency is flagged as bus factor risk. Contributors are only counted with repository checks. 0 disables the flag.
+// Default: 2
+func (c *Config) GetMinContributors() int {
+	return c.viper.GetInt("scanner.min_contributors")
+}
+
+// SetMinContributors sets the minimum number of contributors in the config.
+func (c *Config) SetMinContributors(contributors int) {
+	c.viper.Set("scanner.min_contributors", contributors)
+}
+
 // SetActiveThresholdDays sets the active threshold in the config.
 func (c *Config) SetActiveThresholdDays(days int) {
 	c.viper.Set("scanner.active_threshold_days", days)
@@ -121,3 +223,483 @@ func (c *Config) GetAcknowledgedDependencies() []string {
 func (c *Config) SetAcknowledgedDependencies(deps []string) {
 	c.viper.Set("scanner.acknowledged_dependencies", deps)
 }
+
+// GetDependencyOverrides returns the per module overrides of the scanner settings,
+// e.g. to ignore a module or to allow a longer stale threshold for it.
+// Default: empty list
+func (c *Config) GetDependencyOverrides() ([]scanner.Override, error) {
+	var overrides []scanner.Override
+	if err := c.viper.UnmarshalKey("dependencies", &overrides); err != nil {
+		return nil, fmt.Errorf("invalid dependencies configuration: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetDependencyOverrides sets the per module overrides of the scanner settings.
+func (c *Config) SetDependencyOverrides(overrides []scanner.Override) {
+	c.viper.Set("dependencies", overrides)
+}
+
+// GetTierPolicies returns the stale and release thresholds of the dependency tiers
+// critical, normal and low, which the dependency overrides assign.
+// Default: critical 90 days, low 730 days, normal the global thresholds
+func (c *Config) GetTierPolicies() (map[scanner.Tier]scanner.TierPolicy, error) {
+	var policies map[scanner.Tier]scanner.TierPolicy
+	if err := c.viper.UnmarshalKey("tiers", &policies); err != nil {
+		return nil, fmt.Errorf("invalid tiers configuration: %w", err)
+	}
+	return policies, nil
+}
+
+// SetTierPolicies sets the thresholds of the dependency tiers.
+func (c *Config) SetTierPolicies(policies map[scanner.Tier]scanner.TierPolicy) {
+	c.viper.Set("tiers", policies)
+}
+
+// GetIgnorePatterns returns the module patterns excluded from the scan, e.g.
+// github.com/mycorp/*.
+// Default: empty list
+func (c *Config) GetIgnorePatterns() []string {
+	patterns := c.viper.GetStringSlice("ignore")
+	if patterns == nil {
+		return []string{}
+	}
+	return patterns
+}
+
+// SetIgnorePatterns sets the module patterns excluded from the scan.
+func (c *Config) SetIgnorePatterns(patterns []string) {
+	c.viper.Set("ignore", patterns)
+}
+
+// GetCheckVulnerabilities returns whether to look up known vulnerabilities in the OSV database.
+// Default: false
+func (c *Config) GetCheckVulnerabilities() bool {
+	return c.viper.GetBool("scanner.check_vulnerabilities")
+}
+
+// SetCheckVulnerabilities sets whether to look up known vulnerabilities.
+func (c *Config) SetCheckVulnerabilities(check bool) {
+	c.viper.Set("scanner.check_vulnerabilities", check)
+}
+
+// GetCheckLicenses returns whether to look up the licenses of dependencies on deps.dev.
+// Default: false
+func (c *Config) GetCheckLicenses() bool {
+	return c.viper.GetBool("scanner.check_licenses")
+}
+
+// SetCheckLicenses sets whether to look up the licenses of dependencies.
+func (c *Config) SetCheckLicenses(check bool) {
+	c.viper.Set("scanner.check_licenses", check)
+}
+
+// GetCheckResponsiveness returns whether to measure how maintainers respond to issues and pull requests.
+// Default: false
+func (c *Config) GetCheckResponsiveness() bool {
+	return c.viper.GetBool("scanner.check_responsiveness")
+}
+
+// SetCheckResponsiveness sets whether to measure the responsiveness of the maintainers.
+func (c *Config) SetCheckResponsiveness(check bool) {
+	c.viper.Set("scanner.check_responsiveness", check)
+}
+
+// GetCheckSecuritySignals returns whether to look up the security policy, signed tags and branch protection of
+// the source repositories.
+// Default: false
+func (c *Config) GetCheckSecuritySignals() bool {
+	return c.viper.GetBool("scanner.check_security_signals")
+}
+
+// SetCheckSecuritySignals sets whether to look up the security signals of the source repositories.
+func (c *Config) SetCheckSecuritySignals(check bool) {
+	c.viper.Set("scanner.check_security_signals", check)
+}
+
+// GetMaxResponseDays returns the median number of days maintainers may take to respond to issues before
+// a dependency is marked as inactive. 0 disables the check.
+// Default: 0
+func (c *Config) GetMaxResponseDays() int {
+	return c.viper.GetInt("scanner.max_response_days")
+}
+
+// SetMaxResponseDays sets the maximum median response time in the config.
+func (c *Config) SetMaxResponseDays(days int) {
+	c.viper.Set("scanner.max_response_days", days)
+}
+
+// GetFetchReleaseNotes returns whether to collect the release notes between the used and the
+// available version of outdated dependencies.
+// Default: false
+func (c *Config) GetFetchReleaseNotes() bool {
+	return c.viper.GetBool("scanner.fetch_release_notes")
+}
+
+// SetFetchReleaseNotes sets whether to collect the release notes of outdated dependencies.
+func (c *Config) SetFetchReleaseNotes(fetch bool) {
+	c.viper.Set("scanner.fetch_release_notes", fetch)
+}
+
+// GetCountImports returns whether to count the packages of the project importing each
+// dependency.
+// Default: false
+func (c *Config) GetCountImports() bool {
+	return c.viper.GetBool("scanner.count_imports")
+}
+
+// SetCountImports sets whether to count the packages importing each dependency.
+func (c *Config) SetCountImports(count bool) {
+	c.viper.Set("scanner.count_imports", count)
+}
+
+// GetCheckToolchain returns whether to check the go and toolchain directives of the project
+// and the go directives of the dependencies against the supported Go releases.
+// Default: false
+func (c *Config) GetCheckToolchain() bool {
+	return c.viper.GetBool("scanner.check_toolchain")
+}
+
+// SetCheckToolchain sets whether to check the declared Go versions.
+func (c *Config) SetCheckToolchain(check bool) {
+	c.viper.Set("scanner.check_toolchain", check)
+}
+
+// GetTimeouts returns the timeouts of the git commands fetching private modules, of each HTTP
+// request and of all lookups of a dependency. 0 disables a timeout.
+// Default: 2m, 30s and 5m
+func (c *Config) GetTimeouts() scanner.Timeouts {
+	timeouts := scanner.DefaultTimeouts()
+	for key, timeout := range map[string]*time.Duration{
+		"scanner.git_timeout":            &timeouts.Git,
+		"scanner.http_timeout":           &timeouts.HTTP,
+		"scanner.per_dependency_timeout": &timeouts.PerDependency,
+	} {
+		if c.viper.IsSet(key) {
+			*timeout = c.viper.GetDuration(key)
+		}
+	}
+	return timeouts
+}
+
+// SetTimeouts sets the timeouts of the external operations.
+func (c *Config) SetTimeouts(timeouts scanner.Timeouts) {
+	c.viper.Set("scanner.git_timeout", timeouts.Git)
+	c.viper.Set("scanner.http_timeout", timeouts.HTTP)
+	c.viper.Set("scanner.per_dependency_timeout", timeouts.PerDependency)
+}
+
+// GetCheckRepositories returns whether to look up the metadata of the source repositories
+// on the supported forges.
+// Default: false
+func (c *Config) GetCheckRepositories() bool {
+	return c.viper.GetBool("scanner.check_repositories")
+}
+
+// SetCheckRepositories sets whether to look up the metadata of the source repositories.
+func (c *Config) SetCheckRepositories(check bool) {
+	c.viper.Set("scanner.check_repositories", check)
+}
+
+// GetFailOn returns the conditions which make a scan fail, e.g. "inactive" or "score<50".
+// Default: empty list
+func (c *Config) GetFailOn() []string {
+	conditions := c.viper.GetStringSlice("policy.fail_on")
+	if conditions == nil {
+		return []string{}
+	}
+	return conditions
+}
+
+// SetFailOn sets the conditions which make a scan fail.
+func (c *Config) SetFailOn(conditions []string) {
+	c.viper.Set("policy.fail_on", conditions)
+}
+
+// GetStrict returns whether dependencies with an unknown maintenance status,
+// including failed lookups, make a scan fail.
+// Default: false
+func (c *Config) GetStrict() bool {
+	return c.viper.GetBool("policy.strict")
+}
+
+// SetStrict sets whether an unknown maintenance status makes a scan fail.
+func (c *Config) SetStrict(strict bool) {
+	c.viper.Set("policy.strict", strict)
+}
+
+// GetPolicyRules returns the named rules whose expressions fail or warn
+// about dependencies.
+// Default: empty list
+func (c *Config) GetPolicyRules() ([]policy.Rule, error) {
+	var rules []policy.Rule
+	if err := c.viper.UnmarshalKey("policy.rules", &rules); err != nil {
+		return nil, fmt.Errorf("invalid policy rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SetPolicyRules sets the named rules whose expressions fail or warn about
+// dependencies.
+func (c *Config) SetPolicyRules(rules []policy.Rule) {
+	c.viper.Set("policy.rules", rules)
+}
+
+// GetOwnerRules returns the rules mapping module patterns to owning teams.
+// Default: empty list
+func (c *Config) GetOwnerRules() ([]owners.Rule, error) {
+	var rules []owners.Rule
+	if err := c.viper.UnmarshalKey("owners", &rules); err != nil {
+		return nil, fmt.Errorf("invalid owners configuration: %w", err)
+	}
+	return rules, nil
+}
+
+// SetOwnerRules sets the rules mapping module patterns to owning teams.
+func (c *Config) SetOwnerRules(rules []owners.Rule) {
+	c.viper.Set("owners", rules)
+}
+
+// GetSeverityConfig returns how the findings of dependencies are classified.
+// Keys which are not set keep their default, severity.stale replaces the
+// default age ranges.
+// Default: scanner.DefaultSeverityConfig
+func (c *Config) GetSeverityConfig() (scanner.SeverityConfig, error) {
+	severityConfig := scanner.DefaultSeverityConfig()
+	if c.viper.IsSet("severity.stale") {
+		severityConfig.Stale = nil
+	}
+	if err := c.viper.UnmarshalKey("severity", &severityConfig); err != nil {
+		return scanner.SeverityConfig{}, fmt.Errorf("invalid severity configuration: %w", err)
+	}
+	if err := severityConfig.Validate(); err != nil {
+		return scanner.SeverityConfig{}, fmt.Errorf("invalid severity configuration: %w", err)
+	}
+	return severityConfig, nil
+}
+
+// GetRateLimits returns the configured request rates per host. They override the
+// courtesy limits of public hosts like proxy.golang.org.
+// Default: empty list
+func (c *Config) GetRateLimits() ([]transport.HostLimit, error) {
+	var limits []transport.HostLimit
+	if err := c.viper.UnmarshalKey("network.rate_limits", &limits); err != nil {
+		return nil, fmt.Errorf("invalid network.rate_limits configuration: %w", err)
+	}
+	return limits, nil
+}
+
+// SetRateLimits sets the request rates per host.
+func (c *Config) SetRateLimits(limits []transport.HostLimit) {
+	c.viper.Set("network.rate_limits", limits)
+}
+
+// GetRetryConfig returns the retries of failed requests and the circuit breaker of hosts which keep failing.
+// Default: 3 retries, circuits open after 5 consecutive failures for 30s
+func (c *Config) GetRetryConfig() (transport.RetryConfig, error) {
+	var retries transport.RetryConfig
+	if err := c.viper.UnmarshalKey("network.retry", &retries); err != nil {
+		return transport.RetryConfig{}, fmt.Errorf("invalid network.retry configuration: %w", err)
+	}
+	if retries.MaxRetries < 0 || retries.BreakerThreshold < 0 || retries.BreakerCooldown < 0 {
+		return transport.RetryConfig{}, fmt.Errorf("invalid network.retry configuration: values must not be negative")
+	}
+	return retries, nil
+}
+
+// SetRetryConfig sets the retries and the circuit breaker.
+func (c *Config) SetRetryConfig(retries transport.RetryConfig) {
+	c.viper.Set("network.retry.max_retries", retries.MaxRetries)
+	c.viper.Set("network.retry.breaker_threshold", retries.BreakerThreshold)
+	c.viper.Set("network.retry.breaker_cooldown", retries.BreakerCooldown)
+}
+
+// Scoring configuration
+
+// GetScoreWeights returns the weights of the signals of the health score.
+// Weights which are not configured keep their default value.
+func (c *Config) GetScoreWeights() score.Weights {
+	weights := score.DefaultWeights()
+	if err := c.viper.UnmarshalKey("scoring.weights", &weights); err != nil {
+		eslog.Warnf("Invalid scoring weights, using defaults: %v", err)
+		return score.DefaultWeights()
+	}
+	return weights
+}
+
+// SetScoreWeights sets the weights of the signals of the health score.
+func (c *Config) SetScoreWeights(weights score.Weights) {
+	c.viper.Set("scoring.weights.recency", weights.Recency)
+	c.viper.Set("scoring.weights.cadence", weights.Cadence)
+	c.viper.Set("scoring.weights.archived", weights.Archived)
+	c.viper.Set("scoring.weights.issues", weights.Issues)
+	c.viper.Set("scoring.weights.contributors", weights.Contributors)
+	c.viper.Set("scoring.weights.security", weights.Security)
+}
+
+// Publishing configuration
+
+// GetElasticsearchConfig returns the Elasticsearch/OpenSearch endpoint scan results are published to.
+// Publishing is disabled if no URL is configured.
+func (c *Config) GetElasticsearchConfig() publish.ElasticsearchConfig {
+	var esConfig publish.ElasticsearchConfig
+	if err := c.viper.UnmarshalKey("publish.elasticsearch", &esConfig); err != nil {
+		eslog.Warnf("Invalid elasticsearch configuration: %v", err)
+		return publish.ElasticsearchConfig{}
+	}
+	return esConfig
+}
+
+// SetElasticsearchURL sets the Elasticsearch/OpenSearch endpoint scan results are published to.
+func (c *Config) SetElasticsearchURL(url string) {
+	c.viper.Set("publish.elasticsearch.url", url)
+}
+
+// Forge configuration
+
+// GetForgeConfig returns the API tokens and self-hosted APIs of the forges. Tokens
+// which are not configured are taken from the GITHUB_TOKEN, GITLAB_TOKEN,
+// BITBUCKET_TOKEN, GITEA_TOKEN and SRHT_TOKEN environment variables. Invalid
+// forge.hosts entries are skipped with a warning.
+func (c *Config) GetForgeConfig() forge.Config {
+	forgeConfig := forge.Config{
+		GitHubToken:    c.viper.GetString("forge.github_token"),
+		GitLabToken:    c.viper.GetString("forge.gitlab_token"),
+		BitbucketToken: c.viper.GetString("forge.bitbucket_token"),
+		GiteaToken:     c.viper.GetString("forge.gitea_token"),
+		SourcehutToken: c.viper.GetString("forge.sourcehut_token"),
+		GitHubURL:      c.viper.GetString("forge.github_url"),
+		GitLabURL:      c.viper.GetString("forge.gitlab_url"),
+		GiteaURL:       c.viper.GetString("forge.gitea_url"),
+	}
+	for token, env := range map[*string]string{
+		&forgeConfig.GitHubToken:    "GITHUB_TOKEN",
+		&forgeConfig.GitLabToken:    "GITLAB_TOKEN",
+		&forgeConfig.BitbucketToken: "BITBUCKET_TOKEN",
+		&forgeConfig.GiteaToken:     "GITEA_TOKEN",
+		&forgeConfig.SourcehutToken: "SRHT_TOKEN",
+	} {
+		if *token == "" {
+			*token = os.Getenv(env)
+		}
+	}
+
+	var hosts []forge.Host
+	if err := c.viper.UnmarshalKey("forge.hosts", &hosts); err != nil {
+		eslog.Warnf("Invalid forge.hosts configuration: %v", err)
+	}
+	for _, host := range hosts {
+		if err := host.Validate(); err != nil {
+			eslog.Warnf("Skipping forge host: %v", err)
+			continue
+		}
+		forgeConfig.Hosts = append(forgeConfig.Hosts, host)
+	}
+	return forgeConfig
+}
+
+// History configuration
+
+// GetHistoryConfig returns whether scans are recorded, the path of the history file and the retention.
+// Default: disabled, stored in $HOME/.govital/history.jsonl, kept forever
+func (c *Config) GetHistoryConfig() history.Config {
+	var historyConfig history.Config
+	if err := c.viper.UnmarshalKey("history", &historyConfig); err != nil {
+		eslog.Warnf("Invalid history configuration: %v", err)
+	}
+	if historyConfig.Path == "" {
+		historyConfig.Path = history.DefaultPath()
+	}
+	return historyConfig
+}
+
+// SetHistoryEnabled sets whether scans are recorded in the history.
+func (c *Config) SetHistoryEnabled(enabled bool) {
+	c.viper.Set("history.enabled", enabled)
+}
+
+// Server configuration
+
+// GetServerToken returns the bearer token clients of govital serve must
+// send, from server.token or the GOVITAL_SERVER_TOKEN environment variable.
+// Default: none, requests are not authenticated
+func (c *Config) GetServerToken() string {
+	if token := c.viper.GetString("server.token"); token != "" {
+		return token
+	}
+	return os.Getenv("GOVITAL_SERVER_TOKEN")
+}
+
+// Storage configuration
+
+// GetStorageConfig returns the backend storing the history and the jobs of the server: file, sqlite, bolt
+// or postgres, and its database file or connection string.
+// Default: the history file, jobs are kept in memory
+func (c *Config) GetStorageConfig() store.Config {
+	var storageConfig store.Config
+	if err := c.viper.UnmarshalKey("storage", &storageConfig); err != nil {
+		eslog.Warnf("Invalid storage configuration: %v", err)
+	}
+	return storageConfig
+}
+
+// SetStorageConfig sets the storage backend in the config.
+func (c *Config) SetStorageConfig(storageConfig store.Config) {
+	c.viper.Set("storage.type", storageConfig.Type)
+	c.viper.Set("storage.dsn", storageConfig.DSN)
+}
+
+// Cache configuration
+
+// GetCacheConfig returns whether scans use the response cache, its directory and how long responses are used.
+// Default: disabled, stored in $HOME/.govital/cache, responses are used for 24h
+func (c *Config) GetCacheConfig() cache.Config {
+	var cacheConfig cache.Config
+	if err := c.viper.UnmarshalKey("cache", &cacheConfig); err != nil {
+		eslog.Warnf("Invalid cache configuration: %v", err)
+	}
+	if cacheConfig.Dir == "" {
+		cacheConfig.Dir = cache.DefaultDir()
+	}
+	if cacheConfig.TTL <= 0 {
+		cacheConfig.TTL = cache.DefaultTTL
+	}
+	return cacheConfig
+}
+
+// SetCacheEnabled sets whether scans use the response cache.
+func (c *Config) SetCacheEnabled(enabled bool) {
+	c.viper.Set("cache.enabled", enabled)
+}
+
+// Daemon configuration
+
+// GetDaemonConfig returns the projects the daemon rescans and its cron schedule.
+// Default: no projects, every day at 06:00
+func (c *Config) GetDaemonConfig() (daemon.Config, error) {
+	var daemonConfig daemon.Config
+	if err := c.viper.UnmarshalKey("daemon", &daemonConfig); err != nil {
+		return daemon.Config{}, fmt.Errorf("invalid daemon configuration: %w", err)
+	}
+	if daemonConfig.Schedule == "" {
+		daemonConfig.Schedule = DefaultDaemonSchedule
+	}
+	return daemonConfig, nil
+}
+
+// SetDaemonSchedule sets the cron schedule of the daemon.
+func (c *Config) SetDaemonSchedule(schedule string) {
+	c.viper.Set("daemon.schedule", schedule)
+}
+
+// Notification configuration
+
+// GetNotifyConfig returns the channels notifications are sent to. Channels
+// without URL or SMTP host are disabled.
+func (c *Config) GetNotifyConfig() (notify.Config, error) {
+	var notifyConfig notify.Config
+	if err := c.viper.UnmarshalKey("notify", &notifyConfig); err != nil {
+		return notify.Config{}, fmt.Errorf("invalid notify configuration: %w", err)
+	}
+	return notifyConfig, nil
+}
@@ -5,8 +5,20 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/steffakasid/govital/pkg/cache"
+	"github.com/steffakasid/govital/pkg/daemon"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/score"
+	"github.com/steffakasid/govital/pkg/store"
+	"github.com/steffakasid/govital/pkg/transport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -136,6 +148,17 @@ func TestGetLogLevelString(t *testing.T) {
 	}
 }
 
+func TestGetLogFormat(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+	assert.Equal(t, LogFormatText, cfg.GetLogFormat())
+
+	cfg.viper.Set("log_format", "json")
+	assert.Equal(t, LogFormatJSON, cfg.GetLogFormat())
+
+	cfg.viper.Set("log_format", "yaml")
+	assert.Equal(t, LogFormatText, cfg.GetLogFormat())
+}
+
 func TestConfigViper(t *testing.T) {
 	assert.NotNil(t, Viper)
 }
@@ -228,6 +251,79 @@ func TestSetActiveThresholdDays(t *testing.T) {
 	assert.Equal(t, 60, result)
 }
 
+func TestReleaseThresholdDays(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+	cfg.viper.SetDefault("scanner.release_threshold_days", 0)
+	assert.Equal(t, 0, cfg.GetReleaseThresholdDays())
+
+	cfg.SetReleaseThresholdDays(365)
+
+	assert.Equal(t, 365, cfg.GetReleaseThresholdDays())
+}
+
+func TestMinContributors(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.Equal(t, 2, cfg.GetMinContributors())
+
+	cfg.SetMinContributors(3)
+
+	assert.Equal(t, 3, cfg.GetMinContributors())
+}
+
+func TestResponsiveness(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.False(t, cfg.GetCheckResponsiveness())
+	assert.Equal(t, 0, cfg.GetMaxResponseDays())
+
+	cfg.SetCheckResponsiveness(true)
+	cfg.SetMaxResponseDays(30)
+
+	assert.True(t, cfg.GetCheckResponsiveness())
+	assert.Equal(t, 30, cfg.GetMaxResponseDays())
+}
+
+func TestFetchReleaseNotes(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.False(t, cfg.GetFetchReleaseNotes())
+
+	cfg.SetFetchReleaseNotes(true)
+
+	assert.True(t, cfg.GetFetchReleaseNotes())
+}
+
+func TestCheckSecuritySignals(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.False(t, cfg.GetCheckSecuritySignals())
+
+	cfg.SetCheckSecuritySignals(true)
+
+	assert.True(t, cfg.GetCheckSecuritySignals())
+}
+
+func TestCountImports(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.False(t, cfg.GetCountImports())
+
+	cfg.SetCountImports(true)
+
+	assert.True(t, cfg.GetCountImports())
+}
+
+func TestCheckToolchain(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.False(t, cfg.GetCheckToolchain())
+
+	cfg.SetCheckToolchain(true)
+
+	assert.True(t, cfg.GetCheckToolchain())
+}
+
 func TestGetIncludeIndirectDependencies(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -266,3 +362,415 @@ func TestSetIncludeIndirectDependencies(t *testing.T) {
 
 	assert.True(t, result)
 }
+
+func TestCheckVulnerabilities(t *testing.T) {
+	cfg := NewConfig()
+
+	cfg.SetCheckVulnerabilities(true)
+	assert.True(t, cfg.GetCheckVulnerabilities())
+
+	cfg.SetCheckVulnerabilities(false)
+	assert.False(t, cfg.GetCheckVulnerabilities())
+}
+
+func TestCheckLicenses(t *testing.T) {
+	cfg := NewConfig()
+
+	cfg.SetCheckLicenses(true)
+	assert.True(t, cfg.GetCheckLicenses())
+
+	cfg.SetCheckLicenses(false)
+	assert.False(t, cfg.GetCheckLicenses())
+}
+
+func TestFailOn(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	assert.Equal(t, []string{}, cfg.GetFailOn())
+
+	cfg.SetFailOn([]string{"inactive", "score<50"})
+	assert.Equal(t, []string{"inactive", "score<50"}, cfg.GetFailOn())
+
+	assert.False(t, cfg.GetStrict())
+	cfg.SetStrict(true)
+	assert.True(t, cfg.GetStrict())
+}
+
+func TestScoreWeights(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	assert.Equal(t, score.DefaultWeights(), cfg.GetScoreWeights())
+
+	cfg.viper.Set("scoring.weights.recency", 0.9)
+	weights := cfg.GetScoreWeights()
+	assert.InDelta(t, 0.9, weights.Recency, 0.001)
+	assert.InDelta(t, score.DefaultWeights().Cadence, weights.Cadence, 0.001)
+
+	custom := score.Weights{Recency: 1, Cadence: 2, Archived: 3, Issues: 4, Contributors: 5}
+	cfg.SetScoreWeights(custom)
+	assert.Equal(t, custom, cfg.GetScoreWeights())
+}
+
+func TestElasticsearchConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	assert.Empty(t, cfg.GetElasticsearchConfig().URL)
+
+	cfg.SetElasticsearchURL("https://search.example.com:9200")
+	cfg.viper.Set("publish.elasticsearch.index", "deps")
+	cfg.viper.Set("publish.elasticsearch.api_key", "secret")
+
+	esConfig := cfg.GetElasticsearchConfig()
+	assert.Equal(t, "https://search.example.com:9200", esConfig.URL)
+	assert.Equal(t, "deps", esConfig.Index)
+	assert.Equal(t, "secret", esConfig.APIKey)
+}
+
+func TestCheckRepositories(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	assert.False(t, cfg.GetCheckRepositories())
+	cfg.SetCheckRepositories(true)
+	assert.True(t, cfg.GetCheckRepositories())
+}
+
+func TestForgeConfig(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-github")
+	t.Setenv("GITLAB_TOKEN", "env-gitlab")
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("GITEA_TOKEN", "")
+	t.Setenv("SRHT_TOKEN", "env-sourcehut")
+	cfg := &Config{viper: viper.New()}
+
+	assert.Equal(t, forge.Config{GitHubToken: "env-github", GitLabToken: "env-gitlab", SourcehutToken: "env-sourcehut"}, cfg.GetForgeConfig())
+
+	cfg.viper.Set("forge.github_token", "config-github")
+	forgeConfig := cfg.GetForgeConfig()
+	assert.Equal(t, "config-github", forgeConfig.GitHubToken)
+	assert.Equal(t, "env-gitlab", forgeConfig.GitLabToken)
+
+	cfg.viper.Set("forge.gitlab_url", "https://gitlab.example.com/api/v4")
+	assert.Equal(t, "https://gitlab.example.com/api/v4", cfg.GetForgeConfig().GitLabURL)
+
+	cfg.viper.Set("forge.gitea_url", "https://git.example.org/api/v1")
+	cfg.viper.Set("forge.gitea_token", "config-gitea")
+	assert.Equal(t, "https://git.example.org/api/v1", cfg.GetForgeConfig().GiteaURL)
+	assert.Equal(t, "config-gitea", cfg.GetForgeConfig().GiteaToken)
+}
+
+func TestForgeHosts(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+	assert.Empty(t, cfg.GetForgeConfig().Hosts)
+
+	cfg.viper.Set("forge.hosts", []map[string]any{
+		{"pattern": "git.corp.example.com", "type": "gitlab", "url": "https://git.corp.example.com/api/v4", "token": "secret"},
+		{"pattern": "svn.corp.example.com", "type": "subversion", "url": "https://svn.corp.example.com"},
+		{"pattern": "*.ghe.example.com", "type": "github", "url": "https://ghe.example.com/api/v3"},
+	})
+	assert.Equal(t, []forge.Host{
+		{Pattern: "git.corp.example.com", Type: forge.TypeGitLab, URL: "https://git.corp.example.com/api/v4", Token: "secret"},
+		{Pattern: "*.ghe.example.com", Type: forge.TypeGitHub, URL: "https://ghe.example.com/api/v3"},
+	}, cfg.GetForgeConfig().Hosts)
+}
+
+func TestHistoryConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	historyConfig := cfg.GetHistoryConfig()
+	assert.False(t, historyConfig.Enabled)
+	assert.Equal(t, history.DefaultPath(), historyConfig.Path)
+
+	cfg.SetHistoryEnabled(true)
+	cfg.viper.Set("history.path", "/var/lib/govital/history.jsonl")
+	historyConfig = cfg.GetHistoryConfig()
+	assert.True(t, historyConfig.Enabled)
+	assert.Equal(t, "/var/lib/govital/history.jsonl", historyConfig.Path)
+	assert.False(t, historyConfig.Retention.Enabled())
+
+	cfg.viper.Set("history.retention", map[string]any{"max_scans": 100, "max_age_days": 365})
+	historyConfig = cfg.GetHistoryConfig()
+	assert.Equal(t, history.Retention{MaxScans: 100, MaxAgeDays: 365}, historyConfig.Retention)
+}
+
+func TestServerToken(t *testing.T) {
+	t.Setenv("GOVITAL_SERVER_TOKEN", "")
+	cfg := &Config{viper: viper.New()}
+	assert.Empty(t, cfg.GetServerToken())
+
+	t.Setenv("GOVITAL_SERVER_TOKEN", "env-token")
+	assert.Equal(t, "env-token", cfg.GetServerToken())
+	cfg.viper.Set("server.token", "config-token")
+	assert.Equal(t, "config-token", cfg.GetServerToken())
+}
+
+func TestStorageConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+	assert.Equal(t, store.Config{}, cfg.GetStorageConfig())
+
+	cfg.viper.Set("storage", map[string]any{"type": "postgres", "dsn": "postgres://govital@db/govital"})
+	assert.Equal(t, store.Config{Type: store.TypePostgres, DSN: "postgres://govital@db/govital"}, cfg.GetStorageConfig())
+
+	cfg.SetStorageConfig(store.Config{Type: store.TypeSQLite})
+	assert.Equal(t, store.Config{Type: store.TypeSQLite}, cfg.GetStorageConfig())
+}
+
+func TestTimeouts(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+	assert.Equal(t, scanner.DefaultTimeouts(), cfg.GetTimeouts())
+
+	cfg.viper.Set("scanner.git_timeout", "30s")
+	cfg.viper.Set("scanner.per_dependency_timeout", 0)
+	assert.Equal(t, scanner.Timeouts{Git: 30 * time.Second, HTTP: transport.DefaultHTTPTimeout}, cfg.GetTimeouts())
+
+	cfg.SetTimeouts(scanner.Timeouts{Git: time.Minute, HTTP: 10 * time.Second, PerDependency: time.Hour})
+	assert.Equal(t, scanner.Timeouts{Git: time.Minute, HTTP: 10 * time.Second, PerDependency: time.Hour}, cfg.GetTimeouts())
+}
+
+func TestCacheConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	cacheConfig := cfg.GetCacheConfig()
+	assert.False(t, cacheConfig.Enabled)
+	assert.Equal(t, cache.DefaultDir(), cacheConfig.Dir)
+	assert.Equal(t, cache.DefaultTTL, cacheConfig.TTL)
+
+	cfg.SetCacheEnabled(true)
+	cfg.viper.Set("cache.dir", "/var/cache/govital")
+	cfg.viper.Set("cache.ttl", "6h")
+	cacheConfig = cfg.GetCacheConfig()
+	assert.Equal(t, cache.Config{Enabled: true, Dir: "/var/cache/govital", TTL: 6 * time.Hour}, cacheConfig)
+}
+
+func TestDaemonConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	daemonConfig, err := cfg.GetDaemonConfig()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDaemonSchedule, daemonConfig.Schedule)
+	assert.Empty(t, daemonConfig.Projects)
+
+	cfg.SetDaemonSchedule("@hourly")
+	cfg.viper.Set("daemon.projects", []map[string]any{
+		{"path": "/src/app"},
+		{"remote": "github.com/org/repo@latest"},
+	})
+	daemonConfig, err = cfg.GetDaemonConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "@hourly", daemonConfig.Schedule)
+	assert.Equal(t, []daemon.Project{{Path: "/src/app"}, {Remote: "github.com/org/repo@latest"}}, daemonConfig.Projects)
+}
+
+func TestNotifyConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	notifyConfig, err := cfg.GetNotifyConfig()
+	require.NoError(t, err)
+	assert.Empty(t, notify.New(notifyConfig.ChannelConfig))
+
+	cfg.viper.Set("notify.slack.webhook_url", "https://hooks.slack.com/services/T/B/X")
+	cfg.viper.Set("notify.teams.webhook_url", "https://example.webhook.office.com/webhookb2/X")
+	cfg.viper.Set("notify.email", map[string]any{"host": "smtp.example.com", "port": 25, "to": []string{"team@example.com"}})
+	notifyConfig, err = cfg.GetNotifyConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/X", notifyConfig.Slack.WebhookURL)
+	assert.Equal(t, "https://example.webhook.office.com/webhookb2/X", notifyConfig.Teams.WebhookURL)
+	assert.Equal(t, 25, notifyConfig.Email.Port)
+	assert.Len(t, notify.New(notifyConfig.ChannelConfig), 3)
+
+	cfg.viper.Set("notify.channels", map[string]any{
+		"pager": map[string]any{"webhook": map[string]any{"url": "https://pager.example.com/hook"}},
+	})
+	cfg.viper.Set("notify.rules", []map[string]any{
+		{"severity": "critical", "channels": []string{"pager"}},
+	})
+	notifyConfig, err = cfg.GetNotifyConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://pager.example.com/hook", notifyConfig.Channels["pager"].Webhook.URL)
+	assert.Equal(t, []notify.Rule{{Severity: "critical", Channels: []string{"pager"}}}, notifyConfig.Rules)
+}
+
+func TestPolicyRules(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	rules, err := cfg.GetPolicyRules()
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	cfg.viper.Set("policy.rules", []map[string]any{
+		{"name": "abandoned-direct", "fail_when": "days_since_release > 365 and direct"},
+		{"name": "archived", "warn_when": "archived"},
+	})
+	rules, err = cfg.GetPolicyRules()
+	require.NoError(t, err)
+	assert.Equal(t, []policy.Rule{
+		{Name: "abandoned-direct", FailWhen: "days_since_release > 365 and direct"},
+		{Name: "archived", WarnWhen: "archived"},
+	}, rules)
+
+	cfg.SetPolicyRules([]policy.Rule{{Name: "vulnerable", FailWhen: "vulnerable"}})
+	rules, err = cfg.GetPolicyRules()
+	require.NoError(t, err)
+	assert.Equal(t, "vulnerable", rules[0].Name)
+
+	cfg.viper.Set("policy.rules", "not a list")
+	_, err = cfg.GetPolicyRules()
+	assert.Error(t, err)
+}
+
+func TestOwnerRules(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	rules, err := cfg.GetOwnerRules()
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	cfg.viper.Set("owners", []map[string]any{
+		{"pattern": "github.com/aws/...", "owners": []string{"@cloud"}},
+	})
+	rules, err = cfg.GetOwnerRules()
+	require.NoError(t, err)
+	assert.Equal(t, []owners.Rule{{Pattern: "github.com/aws/...", Owners: []string{"@cloud"}}}, rules)
+
+	cfg.SetOwnerRules([]owners.Rule{{Pattern: "golang.org/x/*", Owners: []string{"@go-team"}}})
+	rules, err = cfg.GetOwnerRules()
+	require.NoError(t, err)
+	assert.Equal(t, "golang.org/x/*", rules[0].Pattern)
+
+	cfg.viper.Set("owners", "not a list")
+	_, err = cfg.GetOwnerRules()
+	assert.Error(t, err)
+}
+
+func TestSeverityConfig(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	severityConfig, err := cfg.GetSeverityConfig()
+	require.NoError(t, err)
+	assert.Equal(t, scanner.DefaultSeverityConfig(), severityConfig)
+
+	cfg.viper.Set("severity.archived", "warning")
+	cfg.viper.Set("severity.outdated", "info")
+	severityConfig, err = cfg.GetSeverityConfig()
+	require.NoError(t, err)
+	assert.Equal(t, scanner.SeverityWarn, severityConfig.Archived)
+	assert.Equal(t, scanner.SeverityInfo, severityConfig.Outdated)
+	assert.Equal(t, scanner.SeverityCritical, severityConfig.Vulnerable, "other keys keep their default")
+	assert.Len(t, severityConfig.Stale, 3)
+
+	cfg.viper.Set("severity.stale", []map[string]any{{"min_days": 540, "severity": "critical"}})
+	severityConfig, err = cfg.GetSeverityConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []scanner.StaleSeverity{{MinDays: 540, Severity: scanner.SeverityCritical}}, severityConfig.Stale)
+
+	cfg.viper.Set("severity.deprecated", "high")
+	_, err = cfg.GetSeverityConfig()
+	assert.ErrorContains(t, err, "severity.deprecated")
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+	assert.Empty(t, cfg.GetIgnorePatterns())
+
+	cfg.SetIgnorePatterns([]string{"github.com/mycorp/*", "golang.org/x/*"})
+	assert.Equal(t, []string{"github.com/mycorp/*", "golang.org/x/*"}, cfg.GetIgnorePatterns())
+}
+
+func TestDependencyOverrides(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	overrides, err := cfg.GetDependencyOverrides()
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	cfg.viper.Set("dependencies", []map[string]any{
+		{"path": "github.com/pkg/errors", "stale_threshold_days": 730, "reason": "intentionally pinned"},
+		{"path": "github.com/internal/tool", "ignore": true},
+	})
+	overrides, err = cfg.GetDependencyOverrides()
+	require.NoError(t, err)
+	assert.Equal(t, []scanner.Override{
+		{Path: "github.com/pkg/errors", StaleThresholdDays: 730, Reason: "intentionally pinned"},
+		{Path: "github.com/internal/tool", Ignore: true},
+	}, overrides)
+
+	cfg.SetDependencyOverrides([]scanner.Override{{Path: "golang.org/x/net", Ignore: true}})
+	overrides, err = cfg.GetDependencyOverrides()
+	require.NoError(t, err)
+	assert.True(t, overrides[0].Ignore)
+
+	cfg.viper.Set("dependencies", "not a list")
+	_, err = cfg.GetDependencyOverrides()
+	assert.Error(t, err)
+}
+
+func TestTierPolicies(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	policies, err := cfg.GetTierPolicies()
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	cfg.viper.Set("tiers", map[string]any{
+		"critical": map[string]any{"stale_threshold_days": 60, "release_threshold_days": 120},
+		"low":      map[string]any{"stale_threshold_days": 1000},
+	})
+	policies, err = cfg.GetTierPolicies()
+	require.NoError(t, err)
+	assert.Equal(t, map[scanner.Tier]scanner.TierPolicy{
+		scanner.TierCritical: {StaleThresholdDays: 60, ReleaseThresholdDays: 120},
+		scanner.TierLow:      {StaleThresholdDays: 1000},
+	}, policies)
+
+	cfg.viper.Set("tiers", "not a map")
+	_, err = cfg.GetTierPolicies()
+	assert.Error(t, err)
+}
+
+func TestRateLimits(t *testing.T) {
+	cfg := &Config{viper: viper.New()}
+
+	limits, err := cfg.GetRateLimits()
+	require.NoError(t, err)
+	assert.Empty(t, limits)
+
+	cfg.viper.Set("network.rate_limits", []map[string]any{
+		{"host": "proxy.golang.org", "requests_per_second": 50},
+		{"host": "goproxy.internal", "requests_per_second": 0},
+	})
+	limits, err = cfg.GetRateLimits()
+	require.NoError(t, err)
+	assert.Equal(t, []transport.HostLimit{
+		{Host: "proxy.golang.org", RequestsPerSecond: 50},
+		{Host: "goproxy.internal", RequestsPerSecond: 0},
+	}, limits)
+
+	cfg.SetRateLimits([]transport.HostLimit{{Host: "api.osv.dev", RequestsPerSecond: 2.5}})
+	limits, err = cfg.GetRateLimits()
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5, limits[0].RequestsPerSecond, 0.001)
+}
+
+func TestRetryConfig(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Init()
+
+	retries, err := cfg.GetRetryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, transport.DefaultRetryConfig(), retries)
+
+	cfg = &Config{viper: viper.New()}
+	cfg.viper.Set("network.retry", map[string]any{"max_retries": 5, "breaker_threshold": 0, "breaker_cooldown": "1m"})
+	retries, err = cfg.GetRetryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, transport.RetryConfig{MaxRetries: 5, BreakerCooldown: time.Minute}, retries)
+
+	cfg.SetRetryConfig(transport.RetryConfig{MaxRetries: 1, BreakerThreshold: 2, BreakerCooldown: time.Second})
+	retries, err = cfg.GetRetryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, transport.RetryConfig{MaxRetries: 1, BreakerThreshold: 2, BreakerCooldown: time.Second}, retries)
+
+	cfg.viper.Set("network.retry.max_retries", -1)
+	_, err = cfg.GetRetryConfig()
+	assert.Error(t, err)
+}
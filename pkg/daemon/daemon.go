@@ -0,0 +1,133 @@
+// Package daemon rescans projects on a schedule and notifies about
+// dependencies whose health regressed since the previous scan
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/schedule"
+)
+
+// Project is a project directory or a published module to rescan
+type Project struct {
+	Path string `mapstructure:"path"`
+	// Remote is a module with optional version query scanned from the Go
+	// proxy, e.g. github.com/org/repo@latest
+	Remote string `mapstructure:"remote"`
+}
+
+// Name identifies the project in logs and notifications
+func (p Project) Name() string {
+	if p.Remote != "" {
+		return p.Remote
+	}
+	return p.Path
+}
+
+// Config selects the rescanned projects and when they are scanned
+type Config struct {
+	// Schedule is a cron expression like "0 6 * * *", see schedule.Parse
+	Schedule string    `mapstructure:"schedule"`
+	Projects []Project `mapstructure:"projects"`
+}
+
+// ScanFunc scans a single project
+type ScanFunc func(ctx context.Context, project Project) (*scanner.ScanResult, error)
+
+// Daemon runs the scans. The first scan of each project is the baseline
+// for the following ones and never notifies.
+type Daemon struct {
+	schedule  schedule.Schedule
+	projects  []Project
+	scan      ScanFunc
+	notifiers []notify.Notifier
+	previous  map[string]*scanner.ScanResult
+	now       func() time.Time
+}
+
+// New creates a daemon for the configured projects
+func New(config Config, scan ScanFunc, notifiers []notify.Notifier) (*Daemon, error) {
+	if len(config.Projects) == 0 {
+		return nil, errors.New("no projects configured to rescan")
+	}
+	for _, project := range config.Projects {
+		if (project.Path == "") == (project.Remote == "") {
+			return nil, fmt.Errorf("project %q needs either a path or a remote module", project.Name())
+		}
+	}
+	parsed, err := schedule.Parse(config.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &Daemon{
+		schedule:  parsed,
+		projects:  config.Projects,
+		scan:      scan,
+		notifiers: notifiers,
+		previous:  make(map[string]*scanner.ScanResult),
+		now:       time.Now,
+	}, nil
+}
+
+// Run scans all projects right away and then on every scheduled time until
+// the context is cancelled
+func (d *Daemon) Run(ctx context.Context) error {
+	for {
+		d.RunOnce(ctx)
+
+		next := d.schedule.Next(d.now())
+		if next.IsZero() {
+			return errors.New("the schedule has no further runs")
+		}
+		eslog.Infof("Next scan at %s", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// RunOnce scans all projects and notifies about new findings. Failures are
+// logged, so a single broken project doesn't stop the others.
+func (d *Daemon) RunOnce(ctx context.Context) {
+	for _, project := range d.projects {
+		if ctx.Err() != nil {
+			return
+		}
+		name := project.Name()
+		result, err := d.scan(ctx, project)
+		if err != nil {
+			eslog.Errorf("Scan of %s failed: %v", name, err)
+			continue
+		}
+
+		previous, scanned := d.previous[name]
+		d.previous[name] = result
+		if !scanned {
+			eslog.Infof("Scanned %s, %d dependencies", name, result.Summary.Total)
+			continue
+		}
+
+		findings := notify.Changes(previous, result)
+		eslog.Infof("Scanned %s, %d new findings", name, len(findings))
+		if len(findings) == 0 {
+			continue
+		}
+		event := notify.Event{Project: name, ScannedAt: d.now(), Findings: findings}
+		for _, notifier := range d.notifiers {
+			if err := notifier.Notify(ctx, event); err != nil {
+				eslog.Errorf("Failed to notify about %s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/notify"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	events []notify.Event
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, event notify.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestRunOnce(t *testing.T) {
+	active := true
+	scan := func(ctx context.Context, project Project) (*scanner.ScanResult, error) {
+		if project.Path == "/broken" {
+			return nil, errors.New("no go.mod")
+		}
+		return &scanner.ScanResult{Dependencies: []scanner.Dependency{{Path: "example.com/mod", Version: "v1.0.0", IsActive: active}}}, nil
+	}
+	notifier := &recordingNotifier{}
+	daemon, err := New(Config{Schedule: "@daily", Projects: []Project{{Path: "/broken"}, {Path: "/src/app"}}}, scan, []notify.Notifier{notifier})
+	require.NoError(t, err)
+
+	daemon.RunOnce(context.Background())
+	assert.Empty(t, notifier.events, "the first scan is the baseline")
+
+	active = false
+	daemon.RunOnce(context.Background())
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "/src/app", notifier.events[0].Project)
+	assert.Equal(t, notify.BecameInactive, notifier.events[0].Findings[0].Kind)
+
+	daemon.RunOnce(context.Background())
+	assert.Len(t, notifier.events, 1, "known findings are not repeated")
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(Config{Schedule: "@daily"}, nil, nil)
+	assert.ErrorContains(t, err, "no projects")
+
+	_, err = New(Config{Schedule: "@daily", Projects: []Project{{Path: "/a", Remote: "example.com/a"}}}, nil, nil)
+	assert.ErrorContains(t, err, "either a path or a remote module")
+
+	_, err = New(Config{Schedule: "daily", Projects: []Project{{Path: "/a"}}}, nil, nil)
+	assert.ErrorContains(t, err, "invalid schedule")
+}
@@ -0,0 +1,284 @@
+// Package detect inspects a directory and reports which manifests a scan
+// would cover, so scans can explain precisely why a directory can't be
+// scanned
+package detect
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Kinds of projects
+const (
+	// KindModule is a single Go module with go.mod at the top
+	KindModule = "module"
+	// KindWorkspace is a go.work workspace of several modules
+	KindWorkspace = "workspace"
+	// KindNone is a directory without go.mod or go.work at the top
+	KindNone = "none"
+)
+
+// VendorManifest lists the vendored modules of a module
+const VendorManifest = "vendor/modules.txt"
+
+// foreignManifests maps manifests of other ecosystems to their ecosystem.
+// They are reported so users learn why a directory has nothing to scan.
+var foreignManifests = map[string]string{
+	"package.json":     "npm",
+	"requirements.txt": "Python",
+	"pyproject.toml":   "Python",
+	"Pipfile":          "Python",
+	"Cargo.toml":       "Rust",
+	"pom.xml":          "Maven",
+	"build.gradle":     "Gradle",
+	"build.gradle.kts": "Gradle",
+	"Gemfile":          "Ruby",
+	"composer.json":    "PHP",
+	"mix.exs":          "Elixir",
+	"Package.swift":    "Swift",
+	"pubspec.yaml":     "Dart",
+}
+
+// Module is a Go module found in the directory
+type Module struct {
+	// Path is the module path declared in go.mod, empty if it has none
+	Path string `json:"path"`
+	// Dir is the module directory relative to the inspected directory,
+	// with forward slashes
+	Dir string `json:"dir"`
+	// Vendored is set if the module vendors its dependencies
+	Vendored bool `json:"vendored,omitempty"`
+}
+
+// Manifest is a manifest of another ecosystem, which govital doesn't scan
+type Manifest struct {
+	// File is relative to the inspected directory, with forward slashes
+	File      string `json:"file"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// Project describes what a scan of the directory covers
+type Project struct {
+	Dir  string `json:"dir"`
+	Kind string `json:"kind"`
+	// Modules are the modules a scan covers: the module at the top or the
+	// members of the workspace
+	Modules []Module `json:"modules,omitempty"`
+	// Nested are modules in subdirectories a scan doesn't cover, they
+	// have to be scanned separately
+	Nested []Module `json:"nested,omitempty"`
+	// Foreign are the manifests of other ecosystems
+	Foreign []Manifest `json:"foreign,omitempty"`
+}
+
+// Scannable returns whether a scan of the directory covers any module
+func (p *Project) Scannable() bool {
+	return len(p.Modules) > 0
+}
+
+// Detect inspects dir and its subdirectories. Like the go command it skips
+// vendor and testdata directories and those starting with . or _.
+func Detect(dir string) (*Project, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is no directory", dir)
+	}
+
+	project := &Project{Dir: dir, Kind: KindNone}
+	covered := make(map[string]bool)
+	if work, err := readWork(dir); err != nil {
+		return nil, err
+	} else if work != nil {
+		project.Kind = KindWorkspace
+		for _, use := range work.Use {
+			rel := filepath.Clean(use.Path)
+			if filepath.IsAbs(rel) {
+				if rel, err = filepath.Rel(dir, rel); err != nil {
+					continue
+				}
+			}
+			rel = filepath.ToSlash(rel)
+			module, ok := readModule(dir, rel)
+			if !ok {
+				continue
+			}
+			covered[rel] = true
+			project.Modules = append(project.Modules, module)
+		}
+	} else if module, ok := readModule(dir, "."); ok {
+		project.Kind = KindModule
+		covered["."] = true
+		project.Modules = append(project.Modules, module)
+	}
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if rel != "." && skipDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch name := entry.Name(); {
+		case name == "go.mod":
+			moduleDir := filepath.ToSlash(filepath.Dir(rel))
+			if covered[moduleDir] {
+				return nil
+			}
+			if module, ok := readModule(dir, moduleDir); ok {
+				project.Nested = append(project.Nested, module)
+			}
+		case foreignManifests[name] != "":
+			project.Foreign = append(project.Foreign, Manifest{File: rel, Ecosystem: foreignManifests[name]})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", dir, err)
+	}
+
+	sort.Slice(project.Nested, func(i, j int) bool { return project.Nested[i].Dir < project.Nested[j].Dir })
+	sort.Slice(project.Foreign, func(i, j int) bool { return project.Foreign[i].File < project.Foreign[j].File })
+	return project, nil
+}
+
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || name == "node_modules" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func readWork(dir string) (*modfile.WorkFile, error) {
+	goWorkPath := filepath.Join(dir, "go.work")
+	data, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, nil
+	}
+	work, err := modfile.ParseWork(goWorkPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", goWorkPath, err)
+	}
+	return work, nil
+}
+
+// readModule returns the module in the directory rel of dir, false if it
+// has no go.mod
+func readModule(dir, rel string) (Module, bool) {
+	moduleDir := filepath.Join(dir, filepath.FromSlash(rel))
+	goMod, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return Module{}, false
+	}
+	return Module{
+		Path:     modfile.ModulePath(goMod),
+		Dir:      rel,
+		Vendored: IsVendored(moduleDir),
+	}, true
+}
+
+// IsVendored returns whether the module in dir vendors its dependencies
+func IsVendored(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(VendorManifest)))
+	return err == nil
+}
+
+// Explain returns why the project can't be scanned and what to do instead,
+// empty if it is scannable
+func (p *Project) Explain() string {
+	if p.Scannable() {
+		return ""
+	}
+
+	reason := "neither go.mod nor go.work found"
+	if p.Kind == KindWorkspace {
+		reason = "go.work uses no module with a go.mod"
+	}
+	return strings.Join(append([]string{reason}, p.Hints()...), "; ")
+}
+
+// Hints point to the nested modules and the manifests of other ecosystems,
+// which explain what the directory contains instead of a scannable module
+func (p *Project) Hints() []string {
+	var hints []string
+	if len(p.Nested) > 0 {
+		dirs := make([]string, len(p.Nested))
+		for i, module := range p.Nested {
+			dirs[i] = module.Dir
+		}
+		hints = append(hints, fmt.Sprintf("found modules in %s, scan them with --project-path or --recursive", strings.Join(dirs, ", ")))
+	}
+	if len(p.Foreign) > 0 {
+		ecosystems := make(map[string]bool)
+		var names []string
+		for _, manifest := range p.Foreign {
+			if !ecosystems[manifest.Ecosystem] {
+				ecosystems[manifest.Ecosystem] = true
+				names = append(names, manifest.Ecosystem)
+			}
+		}
+		hints = append(hints, fmt.Sprintf("found %s manifests, govital only scans Go modules", strings.Join(names, ", ")))
+	}
+	return hints
+}
+
+// Write prints what a scan of the project covers
+func Write(w io.Writer, p *Project) {
+	fmt.Fprintf(w, "Directory: %s\n", p.Dir)
+	switch p.Kind {
+	case KindWorkspace:
+		fmt.Fprintf(w, "Project:   go.work workspace of %d modules\n", len(p.Modules))
+	case KindModule:
+		fmt.Fprintln(w, "Project:   Go module")
+	default:
+		fmt.Fprintln(w, "Project:   none")
+	}
+
+	if len(p.Modules) > 0 {
+		fmt.Fprintln(w, "\nScanned modules:")
+		writeModules(w, p.Modules)
+	}
+	if len(p.Nested) > 0 {
+		fmt.Fprintln(w, "\nNested modules, not covered by a scan of this directory:")
+		writeModules(w, p.Nested)
+	}
+	if len(p.Foreign) > 0 {
+		fmt.Fprintln(w, "\nManifests of other ecosystems, not scanned:")
+		for _, manifest := range p.Foreign {
+			fmt.Fprintf(w, "  - %s (%s)\n", manifest.File, manifest.Ecosystem)
+		}
+	}
+	if explanation := p.Explain(); explanation != "" {
+		fmt.Fprintf(w, "\nNothing to scan: %s\n", explanation)
+	}
+}
+
+func writeModules(w io.Writer, modules []Module) {
+	for _, module := range modules {
+		path := module.Path
+		if path == "" {
+			path = "(no module directive)"
+		}
+		fmt.Fprintf(w, "  - %s in %s", path, module.Dir)
+		if module.Vendored {
+			fmt.Fprintf(w, " (vendored, %s)", VendorManifest)
+		}
+		fmt.Fprintln(w)
+	}
+}
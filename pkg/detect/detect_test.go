@@ -0,0 +1,87 @@
+package detect
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestDetectModule(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                  "module example.com/app\n",
+		"vendor/modules.txt":      "",
+		"vendor/x/go.mod":         "module example.com/x\n",
+		"tools/go.mod":            "module example.com/app/tools\n",
+		"testdata/fixture/go.mod": "module example.com/fixture\n",
+		"web/package.json":        "{}",
+	})
+
+	project, err := Detect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, KindModule, project.Kind)
+	assert.True(t, project.Scannable())
+	assert.Equal(t, []Module{{Path: "example.com/app", Dir: ".", Vendored: true}}, project.Modules)
+	assert.Equal(t, []Module{{Path: "example.com/app/tools", Dir: "tools"}}, project.Nested, "vendor and testdata are skipped")
+	assert.Equal(t, []Manifest{{File: "web/package.json", Ecosystem: "npm"}}, project.Foreign)
+	assert.Empty(t, project.Explain())
+
+	var out bytes.Buffer
+	Write(&out, project)
+	assert.Contains(t, out.String(), "example.com/app in . (vendored, vendor/modules.txt)")
+	assert.Contains(t, out.String(), "Nested modules, not covered by a scan of this directory:\n  - example.com/app/tools in tools")
+	assert.Contains(t, out.String(), "web/package.json (npm)")
+}
+
+func TestDetectWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":         "go 1.22\n\nuse (\n\t./api\n\t./missing\n)\n",
+		"api/go.mod":      "module example.com/api\n",
+		"worker/go.mod":   "module example.com/worker\n",
+		"api/.git/go.mod": "module example.com/hidden\n",
+	})
+
+	project, err := Detect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, KindWorkspace, project.Kind)
+	assert.Equal(t, []Module{{Path: "example.com/api", Dir: "api"}}, project.Modules)
+	assert.Equal(t, []Module{{Path: "example.com/worker", Dir: "worker"}}, project.Nested)
+}
+
+func TestDetectNothingToScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"backend/go.mod":    "module example.com/backend\n",
+		"requirements.txt":  "",
+		"ml/pyproject.toml": "",
+		"ui/package.json":   "{}",
+	})
+
+	project, err := Detect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, KindNone, project.Kind)
+	assert.False(t, project.Scannable())
+	assert.Equal(t, "neither go.mod nor go.work found; found modules in backend, scan them with --project-path or --recursive; "+
+		"found Python, npm manifests, govital only scans Go modules", project.Explain())
+
+	var out bytes.Buffer
+	Write(&out, project)
+	assert.Contains(t, out.String(), "Nothing to scan: neither go.mod nor go.work found")
+
+	_, err = Detect(filepath.Join(dir, "requirements.txt"))
+	assert.Error(t, err)
+}
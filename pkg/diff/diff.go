@@ -0,0 +1,224 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/semver"
+)
+
+// ChangeKind describes how a dependency changed between two scans
+type ChangeKind string
+
+const (
+	Added      ChangeKind = "added"
+	Removed    ChangeKind = "removed"
+	Upgraded   ChangeKind = "upgraded"
+	Downgraded ChangeKind = "downgraded"
+	// HealthChanged is used when the version is unchanged but the
+	// maintenance status or the availability of an update differs, e.g.
+	// between two stored scans
+	HealthChanged ChangeKind = "health-changed"
+)
+
+// Change is a single dependency difference between base and head
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Base *scanner.Dependency
+	Head *scanner.Dependency
+}
+
+// BecameInactive reports whether the change introduces an inactive dependency
+func (c Change) BecameInactive() bool {
+	return c.Head != nil && isInactive(*c.Head) && (c.Base == nil || !isInactive(*c.Base))
+}
+
+// BecameActive reports whether the change resolves an inactive dependency
+func (c Change) BecameActive() bool {
+	return c.Base != nil && isInactive(*c.Base) && (c.Head == nil || !isInactive(*c.Head))
+}
+
+// BecameOutdated reports whether the change introduces a dependency with a
+// newer version available
+func (c Change) BecameOutdated() bool {
+	return c.Head != nil && isOutdated(*c.Head) && (c.Base == nil || !isOutdated(*c.Base))
+}
+
+// Report holds all dependency changes between a base and a head scan
+type Report struct {
+	Base    string
+	Head    string
+	Changes []Change
+	Summary struct {
+		Added              int
+		Removed            int
+		Upgraded           int
+		Downgraded         int
+		InactiveIntroduced int
+		InactiveResolved   int
+		OutdatedIntroduced int
+	}
+}
+
+// CheckComparable returns an error if base and head were scanned from
+// different projects according to their fingerprints. Results without a
+// fingerprint, e.g. from older govital versions, are always comparable.
+func CheckComparable(base, head *scanner.ScanResult) error {
+	if base.Fingerprint.SameProject(head.Fingerprint) {
+		return nil
+	}
+	return fmt.Errorf("refusing to compare different projects: base is %s, head is %s",
+		base.Fingerprint.Module, head.Fingerprint.Module)
+}
+
+// Compare returns the dependency changes between the base and head results.
+// Dependencies are matched by module path; unchanged dependencies are omitted.
+func Compare(base, head *scanner.ScanResult) *Report {
+	report := &Report{}
+
+	baseDeps := indexByPath(base.Dependencies)
+	headDeps := indexByPath(head.Dependencies)
+
+	for path, headDep := range headDeps {
+		baseDep, ok := baseDeps[path]
+		if !ok {
+			report.add(Change{Path: path, Kind: Added, Head: headDep})
+			continue
+		}
+
+		switch cmp := semver.Compare(baseDep.Version, headDep.Version); {
+		case cmp < 0:
+			report.add(Change{Path: path, Kind: Upgraded, Base: baseDep, Head: headDep})
+		case cmp > 0:
+			report.add(Change{Path: path, Kind: Downgraded, Base: baseDep, Head: headDep})
+		case isInactive(*baseDep) != isInactive(*headDep), isOutdated(*baseDep) != isOutdated(*headDep):
+			report.add(Change{Path: path, Kind: HealthChanged, Base: baseDep, Head: headDep})
+		}
+	}
+
+	for path, baseDep := range baseDeps {
+		if _, ok := headDeps[path]; !ok {
+			report.add(Change{Path: path, Kind: Removed, Base: baseDep})
+		}
+	}
+
+	sort.Slice(report.Changes, func(i, j int) bool {
+		return report.Changes[i].Path < report.Changes[j].Path
+	})
+	return report
+}
+
+func (r *Report) add(change Change) {
+	r.Changes = append(r.Changes, change)
+
+	switch change.Kind {
+	case Added:
+		r.Summary.Added++
+	case Removed:
+		r.Summary.Removed++
+	case Upgraded:
+		r.Summary.Upgraded++
+	case Downgraded:
+		r.Summary.Downgraded++
+	}
+
+	if change.BecameInactive() {
+		r.Summary.InactiveIntroduced++
+	}
+	if change.BecameActive() {
+		r.Summary.InactiveResolved++
+	}
+	if change.BecameOutdated() {
+		r.Summary.OutdatedIntroduced++
+	}
+}
+
+// Print writes a human readable diff report to w
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "\n=== Govital Dependency Diff ===\n")
+	if r.Base != "" || r.Head != "" {
+		fmt.Fprintf(w, "Base: %s\nHead: %s\n", r.Base, r.Head)
+	}
+
+	fmt.Fprintf(w, "\nSummary:\n")
+	fmt.Fprintf(w, "  Added:                     %d\n", r.Summary.Added)
+	fmt.Fprintf(w, "  Removed:                   %d\n", r.Summary.Removed)
+	fmt.Fprintf(w, "  Upgraded:                  %d\n", r.Summary.Upgraded)
+	fmt.Fprintf(w, "  Downgraded:                %d\n", r.Summary.Downgraded)
+	fmt.Fprintf(w, "  Inactive Introduced:       %d\n", r.Summary.InactiveIntroduced)
+	fmt.Fprintf(w, "  Inactive Resolved:         %d\n", r.Summary.InactiveResolved)
+	fmt.Fprintf(w, "  Outdated Introduced:       %d\n", r.Summary.OutdatedIntroduced)
+
+	if len(r.Changes) == 0 {
+		fmt.Fprintf(w, "\nNo dependency changes.\n\n")
+		return
+	}
+
+	fmt.Fprintf(w, "\nChanges:\n")
+	r.printChanges(w)
+	fmt.Fprintf(w, "\n")
+}
+
+// PrintCompact writes only a line per change, without header and summary,
+// e.g. for the repeated reports of watch mode
+func (r *Report) PrintCompact(w io.Writer) {
+	if len(r.Changes) == 0 {
+		fmt.Fprintf(w, "No dependency changes.\n")
+		return
+	}
+	r.printChanges(w)
+}
+
+func (r *Report) printChanges(w io.Writer) {
+	for _, change := range r.Changes {
+		switch change.Kind {
+		case Added:
+			fmt.Fprintf(w, "  + %s@%s [%s]\n", change.Path, change.Head.Version, statusLabel(*change.Head))
+		case Removed:
+			fmt.Fprintf(w, "  - %s@%s [%s]\n", change.Path, change.Base.Version, statusLabel(*change.Base))
+		case Upgraded, Downgraded, HealthChanged:
+			fmt.Fprintf(w, "  ~ %s %s -> %s [%s -> %s]\n", change.Path, change.Base.Version, change.Head.Version,
+				statusLabel(*change.Base), statusLabel(*change.Head))
+		}
+	}
+}
+
+func indexByPath(deps []scanner.Dependency) map[string]*scanner.Dependency {
+	index := make(map[string]*scanner.Dependency, len(deps))
+	for i := range deps {
+		index[deps[i].Path] = &deps[i]
+	}
+	return index
+}
+
+func isInactive(dep scanner.Dependency) bool {
+	return dep.IsInactive() && !dep.IsAcknowledged
+}
+
+// isOutdated reports whether a newer version of the dependency is available
+func isOutdated(dep scanner.Dependency) bool {
+	return dep.Update != ""
+}
+
+func statusLabel(dep scanner.Dependency) string {
+	status := "✓ Active"
+	if dep.IsInactive() {
+		if dep.IsAcknowledged {
+			status = "⊘ Acknowledged"
+		} else {
+			status = "✗ Inactive"
+		}
+	} else if dep.Status == scanner.StatusUnknown {
+		status = "? Unknown"
+	}
+	if !dep.LastReleaseTime.IsZero() {
+		status += fmt.Sprintf(", %d days", dep.DaysSinceLastRelease)
+	}
+	if isOutdated(dep) {
+		status += ", update " + dep.Update
+	}
+	return status
+}
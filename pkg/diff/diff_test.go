@@ -0,0 +1,221 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	base := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/kept", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/upgraded", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/downgraded", Version: "v2.0.0", IsActive: false},
+		{Path: "github.com/example/removed", Version: "v1.0.0", IsActive: false},
+	}}
+	head := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/kept", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/upgraded", Version: "v1.1.0", IsActive: false},
+		{Path: "github.com/example/downgraded", Version: "v1.9.0", IsActive: false},
+		{Path: "github.com/example/added", Version: "v0.1.0", IsActive: true},
+	}}
+
+	report := Compare(base, head)
+
+	require.Len(t, report.Changes, 4)
+	assert.Equal(t, "github.com/example/added", report.Changes[0].Path)
+	assert.Equal(t, Added, report.Changes[0].Kind)
+	assert.Equal(t, Downgraded, report.Changes[1].Kind)
+	assert.Equal(t, Removed, report.Changes[2].Kind)
+	assert.Equal(t, Upgraded, report.Changes[3].Kind)
+
+	assert.Equal(t, 1, report.Summary.Added)
+	assert.Equal(t, 1, report.Summary.Removed)
+	assert.Equal(t, 1, report.Summary.Upgraded)
+	assert.Equal(t, 1, report.Summary.Downgraded)
+	assert.Equal(t, 1, report.Summary.InactiveIntroduced)
+	assert.Equal(t, 1, report.Summary.InactiveResolved)
+}
+
+func TestCompareHealthChanged(t *testing.T) {
+	base := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/aging", Version: "v1.0.0", IsActive: true},
+	}}
+	head := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/aging", Version: "v1.0.0", IsActive: false},
+	}}
+
+	report := Compare(base, head)
+
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, HealthChanged, report.Changes[0].Kind)
+	assert.True(t, report.Changes[0].BecameInactive())
+}
+
+func TestCompareOutdated(t *testing.T) {
+	base := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/behind", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/bumped", Version: "v1.0.0", IsActive: true, Update: "v1.1.0"},
+	}}
+	head := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/behind", Version: "v1.0.0", IsActive: true, Update: "v1.1.0"},
+		{Path: "github.com/example/bumped", Version: "v1.1.0", IsActive: true},
+		{Path: "github.com/example/added", Version: "v0.1.0", IsActive: true, Update: "v0.2.0"},
+	}}
+
+	report := Compare(base, head)
+
+	require.Len(t, report.Changes, 3)
+	assert.True(t, report.Changes[0].BecameOutdated())
+	assert.Equal(t, HealthChanged, report.Changes[1].Kind)
+	assert.True(t, report.Changes[1].BecameOutdated())
+	assert.False(t, report.Changes[2].BecameOutdated())
+	assert.Equal(t, 2, report.Summary.OutdatedIntroduced)
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	assert.Contains(t, buf.String(), "~ github.com/example/behind v1.0.0 -> v1.0.0 [✓ Active -> ✓ Active, update v1.1.0]")
+}
+
+func TestCheckComparable(t *testing.T) {
+	service := &scanner.ScanResult{Fingerprint: scanner.NewFingerprint([]byte("module example.com/service\n"), nil)}
+	other := &scanner.ScanResult{Fingerprint: scanner.NewFingerprint([]byte("module example.com/other\n"), nil)}
+	unknown := &scanner.ScanResult{}
+
+	assert.NoError(t, CheckComparable(service, service))
+	assert.NoError(t, CheckComparable(service, unknown))
+	assert.NoError(t, CheckComparable(unknown, other))
+	assert.ErrorContains(t, CheckComparable(service, other), "example.com/service")
+}
+
+func TestReportPrint(t *testing.T) {
+	report := Compare(&scanner.ScanResult{}, &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/added", Version: "v0.1.0", IsActive: false},
+	}})
+	report.Base = "main"
+	report.Head = "feature"
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+
+	assert.Contains(t, buf.String(), "Base: main")
+	assert.Contains(t, buf.String(), "+ github.com/example/added@v0.1.0 [✗ Inactive]")
+}
+
+func TestReportPrintCompact(t *testing.T) {
+	report := Compare(&scanner.ScanResult{}, &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/added", Version: "v0.1.0", IsActive: false},
+	}})
+
+	var buf bytes.Buffer
+	report.PrintCompact(&buf)
+	assert.Equal(t, "  + github.com/example/added@v0.1.0 [✗ Inactive]\n", buf.String())
+
+	buf.Reset()
+	Compare(&scanner.ScanResult{}, &scanner.ScanResult{}).PrintCompact(&buf)
+	assert.Equal(t, "No dependency changes.\n", buf.String())
+}
+
+func TestParseRefRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		expectedBase  string
+		expectedHead  string
+		symmetric     bool
+		expectedError bool
+	}{
+		{"two dots", "main..feature", "main", "feature", false, false},
+		{"three dots", "main...feature", "main", "feature", true, false},
+		{"head defaults to HEAD", "main..", "main", "HEAD", false, false},
+		{"missing separator", "main", "", "", false, true},
+		{"missing base", "..feature", "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, head, symmetric, err := ParseRefRange(tt.spec)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBase, base)
+			assert.Equal(t, tt.expectedHead, head)
+			assert.Equal(t, tt.symmetric, symmetric)
+		})
+	}
+}
+
+func TestReadFileAtRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	gitRun := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	projectDir := filepath.Join(repoDir, "service")
+	require.NoError(t, os.MkdirAll(projectDir, 0o755))
+	goModPath := filepath.Join(projectDir, "go.mod")
+
+	gitRun("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/service\n"), 0o600))
+	gitRun("add", ".")
+	gitRun("commit", "-q", "-m", "base")
+	gitRun("checkout", "-q", "-b", "feature")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/service\n\nrequire github.com/example/dep v1.0.0\n"), 0o600))
+	gitRun("commit", "-q", "-am", "feature")
+
+	baseContent, err := ReadFileAtRef(projectDir, "main", "go.mod")
+	require.NoError(t, err)
+	headContent, err := ReadFileAtRef(projectDir, "feature", "go.mod")
+	require.NoError(t, err)
+	mergeBase, err := MergeBase(projectDir, "main", "feature")
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(baseContent), "github.com/example/dep")
+	assert.Contains(t, string(headContent), "github.com/example/dep v1.0.0")
+	assert.Len(t, mergeBase, 40)
+
+	mainRevision, err := ResolveRef(projectDir, "main")
+	require.NoError(t, err)
+	assert.Equal(t, mergeBase, mainRevision)
+	_, err = ResolveRef(projectDir, "does-not-exist")
+	assert.Error(t, err)
+
+	_, err = ReadFileAtRef(projectDir, "does-not-exist", "go.mod")
+	assert.Error(t, err)
+
+	changed, err := ChangedFiles(repoDir, "main", "feature", "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service/go.mod"}, changed)
+	changed, err = ChangedFiles(repoDir, "feature", "", "go.mod")
+	require.NoError(t, err)
+	assert.Empty(t, changed, "nothing staged")
+
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/service\n\nrequire github.com/example/dep v1.1.0\n"), 0o600))
+	gitRun("add", ".")
+	changed, err = ChangedFiles(repoDir, "HEAD", "", "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service/go.mod"}, changed)
+	staged, err := ReadFileAtRef(projectDir, "", "go.mod")
+	require.NoError(t, err)
+	assert.Contains(t, string(staged), "v1.1.0")
+
+	changed, err = ChangedFiles(repoDir, "", "main", "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service/go.mod"}, changed)
+}
@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/tool"
+)
+
+// ParseRefRange splits a git revision range like "main..feature" into its
+// base and head. With three dots the base is the merge base of both refs,
+// which is resolved by the caller via MergeBase.
+func ParseRefRange(spec string) (base, head string, symmetric bool, err error) {
+	sep := ".."
+	if strings.Contains(spec, "...") {
+		sep = "..."
+		symmetric = true
+	}
+
+	parts := strings.SplitN(spec, sep, 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false, fmt.Errorf("invalid git ref range %q, expected <base>..<head>", spec)
+	}
+
+	base = parts[0]
+	head = parts[1]
+	if head == "" {
+		head = "HEAD"
+	}
+	return base, head, symmetric, nil
+}
+
+// MergeBase returns the best common ancestor of the two refs
+func MergeBase(repoDir, base, head string) (string, error) {
+	out, err := runGit(repoDir, "merge-base", base, head)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ResolveRef returns the commit hash the ref points to
+func ResolveRef(repoDir, ref string) (string, error) {
+	out, err := runGit(repoDir, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReadFileAtRef returns the content of a file relative to repoDir as it is
+// stored at the given ref, or in the index if ref is empty. It uses git
+// plumbing only, the working tree is left untouched.
+func ReadFileAtRef(repoDir, ref, name string) ([]byte, error) {
+	prefix, err := runGit(repoDir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	// Object names use forward slashes on all systems
+	object := fmt.Sprintf("%s:%s", ref, path.Join(strings.TrimSpace(string(prefix)), filepath.ToSlash(name)))
+	content, err := runGit(repoDir, "cat-file", "blob", object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	return content, nil
+}
+
+// ChangedFiles returns the files named name which were added or modified
+// between base and head, relative to the top level of the repository. An
+// empty head compares the index, an empty base lists all files of head.
+func ChangedFiles(repoDir, base, head, name string) ([]string, error) {
+	var args []string
+	switch {
+	case base == "" && head == "":
+		args = []string{"ls-files", "--full-name"}
+	case base == "":
+		args = []string{"ls-tree", "-r", "--name-only", "--full-tree", head}
+	case head == "":
+		args = []string{"diff", "--cached", "--name-only", "--diff-filter=ACMR", base}
+	default:
+		args = []string{"diff", "--name-only", "--diff-filter=ACMR", base, head}
+	}
+	out, err := runGit(repoDir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if file != "" && path.Base(file) == name {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := tool.Command(dir, tool.Git, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
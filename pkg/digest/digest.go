@@ -0,0 +1,258 @@
+// Package digest summarizes how the dependencies of a project changed over
+// a period of recorded scans, as low-noise alternative to notifications
+// about every scan
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/semver"
+)
+
+// ErrNoScans is returned if no scan of the project was recorded
+var ErrNoScans = errors.New("no recorded scans")
+
+// Change is a dependency whose health changed in the period
+type Change struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	// Detail lists vulnerability IDs for vulnerability changes
+	Detail string `json:"detail,omitempty"`
+}
+
+// Upgrade is a dependency whose version changed in the period
+type Upgrade struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Digest summarizes the changes between the first and the last scan of a
+// period
+type Digest struct {
+	Project string    `json:"project"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	// Scans counts the scans recorded in the period
+	Scans  int             `json:"scans"`
+	Before scanner.Summary `json:"before"`
+	After  scanner.Summary `json:"after"`
+
+	NewlyStale              []Change  `json:"newly_stale"`
+	Recovered               []Change  `json:"recovered"`
+	NewlyArchived           []Change  `json:"newly_archived"`
+	NewVulnerabilities      []Change  `json:"new_vulnerabilities"`
+	ResolvedVulnerabilities []Change  `json:"resolved_vulnerabilities"`
+	Upgrades                []Upgrade `json:"upgrades"`
+	Downgrades              []Upgrade `json:"downgrades"`
+	Added                   []string  `json:"added"`
+	Removed                 []string  `json:"removed"`
+	// Incomplete is set if the compared records carry no dependency states,
+	// only the summaries are compared then
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// ParsePeriod parses a period like 7d, 2w or a Go duration like 36h
+func ParsePeriod(period string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(period, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(period, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		count, err := strconv.Atoi(period[:len(period)-1])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid period %q, expected e.g. 7d, 2w or 36h", period)
+		}
+		return time.Duration(count) * unit, nil
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid period %q, expected e.g. 7d, 2w or 36h", period)
+	}
+	return duration, nil
+}
+
+// Build compares the last scan before since, or the first one after it if
+// there is none, with the latest scan. The records are sorted oldest first
+// like history.Store.Load returns them.
+func Build(project string, records []history.Record, since time.Time) (*Digest, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w of %s", ErrNoScans, project)
+	}
+
+	baseline := 0
+	scans := 0
+	for i, record := range records {
+		if record.ScannedAt.After(since) {
+			scans++
+		} else {
+			baseline = i
+		}
+	}
+	before, after := records[baseline], records[len(records)-1]
+
+	digest := &Digest{
+		Project:    project,
+		From:       before.ScannedAt,
+		To:         after.ScannedAt,
+		Scans:      scans,
+		Before:     before.Summary,
+		After:      after.Summary,
+		Incomplete: before.Dependencies == nil || after.Dependencies == nil,
+	}
+	if !digest.Incomplete {
+		digest.compare(before.Dependencies, after.Dependencies)
+	}
+	return digest, nil
+}
+
+func (d *Digest) compare(before, after []history.DependencyState) {
+	old := make(map[string]history.DependencyState, len(before))
+	for _, state := range before {
+		old[state.Path] = state
+	}
+
+	for _, state := range after {
+		previous, known := old[state.Path]
+		delete(old, state.Path)
+		change := Change{Module: state.Path, Version: state.Version}
+		if !known {
+			d.Added = append(d.Added, state.Path+"@"+state.Version)
+		} else if state.Version != previous.Version {
+			upgrade := Upgrade{Module: state.Path, From: previous.Version, To: state.Version}
+			if semver.Compare(state.Version, previous.Version) > 0 {
+				d.Upgrades = append(d.Upgrades, upgrade)
+			} else {
+				d.Downgrades = append(d.Downgrades, upgrade)
+			}
+		}
+
+		switch {
+		case state.Inactive && (!known || !previous.Inactive):
+			d.NewlyStale = append(d.NewlyStale, change)
+		case !state.Inactive && known && previous.Inactive:
+			d.Recovered = append(d.Recovered, change)
+		}
+		if state.Archived && (!known || !previous.Archived) {
+			d.NewlyArchived = append(d.NewlyArchived, change)
+		}
+		if added := missing(state.Vulnerabilities, previous.Vulnerabilities); len(added) > 0 {
+			d.NewVulnerabilities = append(d.NewVulnerabilities, Change{Module: state.Path, Version: state.Version, Detail: strings.Join(added, ", ")})
+		}
+		if resolved := missing(previous.Vulnerabilities, state.Vulnerabilities); len(resolved) > 0 {
+			d.ResolvedVulnerabilities = append(d.ResolvedVulnerabilities, Change{Module: state.Path, Version: state.Version, Detail: strings.Join(resolved, ", ")})
+		}
+	}
+
+	for path, state := range old {
+		d.Removed = append(d.Removed, path+"@"+state.Version)
+		if len(state.Vulnerabilities) > 0 {
+			d.ResolvedVulnerabilities = append(d.ResolvedVulnerabilities, Change{Module: path, Version: state.Version, Detail: strings.Join(state.Vulnerabilities, ", ")})
+		}
+	}
+	sort.Strings(d.Removed)
+	sort.Slice(d.ResolvedVulnerabilities, func(i, j int) bool {
+		return d.ResolvedVulnerabilities[i].Module < d.ResolvedVulnerabilities[j].Module
+	})
+}
+
+// missing returns the IDs of ids which are not in other
+func missing(ids, other []string) []string {
+	var result []string
+	for _, id := range ids {
+		if !slices.Contains(other, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Empty returns whether nothing changed in the period
+func (d *Digest) Empty() bool {
+	return len(d.NewlyStale) == 0 && len(d.Recovered) == 0 && len(d.NewlyArchived) == 0 &&
+		len(d.NewVulnerabilities) == 0 && len(d.ResolvedVulnerabilities) == 0 &&
+		len(d.Upgrades) == 0 && len(d.Downgrades) == 0 && len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Subject is a one line summary of the digest
+func (d *Digest) Subject() string {
+	return fmt.Sprintf("govital digest for %s: %d newly stale, %d new vulnerabilities, %d upgrades",
+		d.Project, len(d.NewlyStale), len(d.NewVulnerabilities), len(d.Upgrades))
+}
+
+// Write renders the digest as plain text, which reads well in mails and
+// chats
+func Write(w io.Writer, d *Digest) {
+	fmt.Fprintf(w, "Dependency digest for %s\n", d.Project)
+	fmt.Fprintf(w, "%s to %s, %d scans\n\n", d.From.UTC().Format("2006-01-02"), d.To.UTC().Format("2006-01-02"), d.Scans)
+
+	fmt.Fprintf(w, "Total:      %d (%+d)\n", d.After.Total, d.After.Total-d.Before.Total)
+	fmt.Fprintf(w, "Inactive:   %d (%+d)\n", d.After.Inactive, d.After.Inactive-d.Before.Inactive)
+	fmt.Fprintf(w, "Outdated:   %d (%+d)\n", d.After.Outdated, d.After.Outdated-d.Before.Outdated)
+	fmt.Fprintf(w, "Vulnerable: %d (%+d)\n", d.After.Vulnerable, d.After.Vulnerable-d.Before.Vulnerable)
+
+	if d.Incomplete {
+		fmt.Fprintln(w, "\nThe scans were recorded without dependency details, only the counts are compared.")
+		return
+	}
+	if d.Empty() {
+		fmt.Fprintln(w, "\nNo dependency changed.")
+		return
+	}
+
+	writeChanges(w, "Newly stale", d.NewlyStale)
+	writeChanges(w, "Recovered", d.Recovered)
+	writeChanges(w, "Newly archived", d.NewlyArchived)
+	writeChanges(w, "New vulnerabilities", d.NewVulnerabilities)
+	writeChanges(w, "Resolved vulnerabilities", d.ResolvedVulnerabilities)
+	writeUpgrades(w, "Upgrades", d.Upgrades)
+	writeUpgrades(w, "Downgrades", d.Downgrades)
+	writeModules(w, "Added", d.Added)
+	writeModules(w, "Removed", d.Removed)
+}
+
+func writeChanges(w io.Writer, title string, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(w, "  - %s@%s", change.Module, change.Version)
+		if change.Detail != "" {
+			fmt.Fprintf(w, ": %s", change.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func writeUpgrades(w io.Writer, title string, upgrades []Upgrade) {
+	if len(upgrades) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(upgrades))
+	for _, upgrade := range upgrades {
+		fmt.Fprintf(w, "  - %s %s -> %s\n", upgrade.Module, upgrade.From, upgrade.To)
+	}
+}
+
+func writeModules(w io.Writer, title string, modules []string) {
+	if len(modules) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(modules))
+	for _, module := range modules {
+		fmt.Fprintf(w, "  - %s\n", module)
+	}
+}
@@ -0,0 +1,139 @@
+package digest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	for period, expected := range map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+	} {
+		duration, err := ParsePeriod(period)
+		require.NoError(t, err, period)
+		assert.Equal(t, expected, duration, period)
+	}
+	for _, period := range []string{"", "d", "0d", "-1w", "soon"} {
+		_, err := ParsePeriod(period)
+		assert.Error(t, err, period)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []history.Record{
+		{ScannedAt: day, Summary: scanner.Summary{Total: 99}},
+		{
+			ScannedAt: day.AddDate(0, 0, 2),
+			Summary:   scanner.Summary{Total: 4, Inactive: 1, Vulnerable: 1},
+			Dependencies: []history.DependencyState{
+				{Path: "example.com/recovers", Version: "v1.0.0", Inactive: true},
+				{Path: "example.com/upgraded", Version: "v1.0.0", Vulnerabilities: []string{"GO-1"}},
+				{Path: "example.com/removed", Version: "v0.1.0", Vulnerabilities: []string{"GO-2"}},
+				{Path: "example.com/stales", Version: "v2.0.0"},
+			},
+		},
+		{ScannedAt: day.AddDate(0, 0, 5), Summary: scanner.Summary{Total: 50}},
+		{
+			ScannedAt: day.AddDate(0, 0, 9),
+			Summary:   scanner.Summary{Total: 4, Inactive: 1, Vulnerable: 1},
+			Dependencies: []history.DependencyState{
+				{Path: "example.com/recovers", Version: "v1.0.0"},
+				{Path: "example.com/upgraded", Version: "v1.1.0"},
+				{Path: "example.com/stales", Version: "v2.0.0", Inactive: true, Archived: true, Vulnerabilities: []string{"GO-3"}},
+				{Path: "example.com/added", Version: "v0.0.1"},
+			},
+		},
+	}
+
+	t.Run("compares the last scan before the period", func(t *testing.T) {
+		digest, err := Build("example.com/app", records[:2], day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 1, digest.Scans)
+		assert.True(t, digest.Incomplete, "first record has no dependencies")
+		assert.Equal(t, 99, digest.Before.Total)
+
+		digest, err = Build("example.com/app", append(records[1:2:2], records[3]), day.AddDate(0, 0, 3))
+		require.NoError(t, err)
+		assert.Equal(t, 1, digest.Scans)
+		assert.False(t, digest.Incomplete)
+		assert.Equal(t, day.AddDate(0, 0, 2), digest.From)
+		assert.Equal(t, day.AddDate(0, 0, 9), digest.To)
+
+		assert.Equal(t, []Change{{Module: "example.com/stales", Version: "v2.0.0"}}, digest.NewlyStale)
+		assert.Equal(t, []Change{{Module: "example.com/recovers", Version: "v1.0.0"}}, digest.Recovered)
+		assert.Equal(t, []Change{{Module: "example.com/stales", Version: "v2.0.0"}}, digest.NewlyArchived)
+		assert.Equal(t, []Change{{Module: "example.com/stales", Version: "v2.0.0", Detail: "GO-3"}}, digest.NewVulnerabilities)
+		assert.Equal(t, []Change{
+			{Module: "example.com/removed", Version: "v0.1.0", Detail: "GO-2"},
+			{Module: "example.com/upgraded", Version: "v1.1.0", Detail: "GO-1"},
+		}, digest.ResolvedVulnerabilities)
+		assert.Equal(t, []Upgrade{{Module: "example.com/upgraded", From: "v1.0.0", To: "v1.1.0"}}, digest.Upgrades)
+		assert.Empty(t, digest.Downgrades)
+		assert.Equal(t, []string{"example.com/added@v0.0.1"}, digest.Added)
+		assert.Equal(t, []string{"example.com/removed@v0.1.0"}, digest.Removed)
+		assert.Equal(t, "govital digest for example.com/app: 1 newly stale, 1 new vulnerabilities, 1 upgrades", digest.Subject())
+	})
+
+	t.Run("starts at the first scan if none is before the period", func(t *testing.T) {
+		digest, err := Build("example.com/app", records[1:2], day)
+		require.NoError(t, err)
+		assert.Equal(t, 1, digest.Scans)
+		assert.True(t, digest.Empty())
+
+		var out bytes.Buffer
+		Write(&out, digest)
+		assert.Contains(t, out.String(), "No dependency changed.")
+	})
+
+	t.Run("no scans", func(t *testing.T) {
+		_, err := Build("example.com/app", nil, day)
+		assert.ErrorIs(t, err, ErrNoScans)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	digest := &Digest{
+		Project:            "example.com/app",
+		From:               time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:                 time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Scans:              7,
+		Before:             scanner.Summary{Total: 10, Inactive: 1},
+		After:              scanner.Summary{Total: 11, Inactive: 2, Vulnerable: 1},
+		NewlyStale:         []Change{{Module: "example.com/stale", Version: "v1.0.0"}},
+		NewVulnerabilities: []Change{{Module: "example.com/vulnerable", Version: "v1.2.0", Detail: "GO-1, GO-2"}},
+		Upgrades:           []Upgrade{{Module: "example.com/upgraded", From: "v1.0.0", To: "v1.1.0"}},
+	}
+
+	var out bytes.Buffer
+	Write(&out, digest)
+	assert.Equal(t, `Dependency digest for example.com/app
+2024-01-01 to 2024-01-08, 7 scans
+
+Total:      11 (+1)
+Inactive:   2 (+1)
+Outdated:   0 (+0)
+Vulnerable: 1 (+1)
+
+Newly stale (1):
+  - example.com/stale@v1.0.0
+
+New vulnerabilities (1):
+  - example.com/vulnerable@v1.2.0: GO-1, GO-2
+
+Upgrades (1):
+  - example.com/upgraded v1.0.0 -> v1.1.0
+`, out.String())
+
+	out.Reset()
+	Write(&out, &Digest{Project: "example.com/app", Incomplete: true})
+	assert.Contains(t, out.String(), "only the counts are compared")
+}
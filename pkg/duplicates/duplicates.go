@@ -0,0 +1,179 @@
+// Package duplicates finds modules used by many projects at inconsistent
+// versions, so platform teams can coordinate upgrades across projects
+package duplicates
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/semver"
+)
+
+// Duplicate is a module used at more than one version across projects
+type Duplicate struct {
+	Path string `json:"path"`
+	// Versions are the used versions, newest first
+	Versions []Version `json:"versions"`
+	// Projects counts the projects using the module
+	Projects int `json:"projects"`
+	// Target is the version to converge on, the latest known release or
+	// else the newest used version
+	Target string `json:"target"`
+	// Benefit counts the projects using an older version than Target,
+	// which would benefit from a coordinated upgrade
+	Benefit int `json:"benefit"`
+	// MixedHealth is set if some of the used versions are inactive or
+	// vulnerable while others are not
+	MixedHealth bool `json:"mixed_health"`
+}
+
+// Version is one of the used versions of a duplicate
+type Version struct {
+	Version    string   `json:"version"`
+	IsActive   bool     `json:"is_active"`
+	Vulnerable bool     `json:"vulnerable"`
+	Projects   []string `json:"projects"`
+}
+
+func (v Version) healthy() bool {
+	return v.IsActive && !v.Vulnerable
+}
+
+// ProjectName identifies the project of a dependency: the workspace module
+// requiring it, else the module path of the scanned project, else its path
+func ProjectName(result *scanner.ScanResult, dep scanner.Dependency) string {
+	if dep.Module != "" {
+		return dep.Module
+	}
+	if result.Fingerprint != nil && result.Fingerprint.Module != "" {
+		return result.Fingerprint.Module
+	}
+	return result.ProjectPath
+}
+
+// Find returns the modules used at more than one version by the projects of
+// the results, ranked by the number of projects benefiting from an upgrade
+func Find(results []*scanner.ScanResult) []Duplicate {
+	type usage struct {
+		versions map[string]*Version
+		latest   string
+	}
+	byPath := make(map[string]*usage)
+	for _, result := range results {
+		for _, dep := range result.Dependencies {
+			use, ok := byPath[dep.Path]
+			if !ok {
+				use = &usage{versions: make(map[string]*Version)}
+				byPath[dep.Path] = use
+			}
+			if semver.Compare(dep.Latest, use.latest) > 0 {
+				use.latest = dep.Latest
+			}
+			version, ok := use.versions[dep.Version]
+			if !ok {
+				version = &Version{Version: dep.Version, IsActive: true}
+				use.versions[dep.Version] = version
+			}
+			version.IsActive = version.IsActive && (!dep.IsInactive() || dep.IsAcknowledged)
+			version.Vulnerable = version.Vulnerable || len(dep.Vulnerabilities) > 0
+			version.Projects = appendUnique(version.Projects, ProjectName(result, dep))
+		}
+	}
+
+	var duplicates []Duplicate
+	for path, use := range byPath {
+		if len(use.versions) < 2 {
+			continue
+		}
+
+		duplicate := Duplicate{Path: path}
+		healthy, unhealthy := false, false
+		projects := make(map[string]bool)
+		for _, version := range use.versions {
+			sort.Strings(version.Projects)
+			duplicate.Versions = append(duplicate.Versions, *version)
+			healthy = healthy || version.healthy()
+			unhealthy = unhealthy || !version.healthy()
+			for _, project := range version.Projects {
+				projects[project] = true
+			}
+		}
+		sort.Slice(duplicate.Versions, func(i, j int) bool {
+			return semver.Compare(duplicate.Versions[i].Version, duplicate.Versions[j].Version) > 0
+		})
+
+		duplicate.Projects = len(projects)
+		duplicate.MixedHealth = healthy && unhealthy
+		duplicate.Target = duplicate.Versions[0].Version
+		if semver.Compare(use.latest, duplicate.Target) > 0 {
+			duplicate.Target = use.latest
+		}
+		for _, version := range duplicate.Versions {
+			if semver.Compare(version.Version, duplicate.Target) < 0 {
+				duplicate.Benefit += len(version.Projects)
+			}
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Benefit != duplicates[j].Benefit {
+			return duplicates[i].Benefit > duplicates[j].Benefit
+		}
+		if duplicates[i].MixedHealth != duplicates[j].MixedHealth {
+			return duplicates[i].MixedHealth
+		}
+		return duplicates[i].Path < duplicates[j].Path
+	})
+	return duplicates
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// Write prints the duplicates as table with the projects per version
+func Write(w io.Writer, duplicates []Duplicate) error {
+	if len(duplicates) == 0 {
+		_, err := fmt.Fprintln(w, "No module is used at different versions")
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "MODULE\tPROJECTS\tVERSIONS\tUPGRADE TO\tBENEFIT\tHEALTH")
+	for _, duplicate := range duplicates {
+		health := "consistent"
+		if duplicate.MixedHealth {
+			health = "mixed"
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%d\t%s\n", duplicate.Path, duplicate.Projects, len(duplicate.Versions),
+			duplicate.Target, duplicate.Benefit, health)
+		for _, version := range duplicate.Versions {
+			fmt.Fprintf(table, "  %s%s\t\t\t\t\t%s\n", version.Version, versionMarkers(version), strings.Join(version.Projects, ", "))
+		}
+	}
+	return table.Flush()
+}
+
+func versionMarkers(version Version) string {
+	var markers []string
+	if !version.IsActive {
+		markers = append(markers, "inactive")
+	}
+	if version.Vulnerable {
+		markers = append(markers, "vulnerable")
+	}
+	if len(markers) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(markers, ", ") + "]"
+}
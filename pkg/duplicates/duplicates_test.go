@@ -0,0 +1,62 @@
+package duplicates
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func project(name string, deps ...scanner.Dependency) *scanner.ScanResult {
+	return &scanner.ScanResult{ProjectPath: "/src/" + name, Fingerprint: &scanner.Fingerprint{Module: "example.com/" + name}, Dependencies: deps}
+}
+
+func TestFind(t *testing.T) {
+	results := []*scanner.ScanResult{
+		project("billing",
+			scanner.Dependency{Path: "github.com/example/log", Version: "v1.0.0", Latest: "v1.3.0"},
+			scanner.Dependency{Path: "github.com/example/http", Version: "v2.0.0", IsActive: true},
+			scanner.Dependency{Path: "github.com/example/same", Version: "v1.0.0", IsActive: true}),
+		project("payments",
+			scanner.Dependency{Path: "github.com/example/log", Version: "v1.2.0", Latest: "v1.3.0", IsActive: true},
+			scanner.Dependency{Path: "github.com/example/http", Version: "v2.1.0", IsActive: true},
+			scanner.Dependency{Path: "github.com/example/same", Version: "v1.0.0", IsActive: true}),
+		project("shipping",
+			scanner.Dependency{Path: "github.com/example/log", Version: "v1.2.0", IsActive: true, Vulnerabilities: []vuln.Vulnerability{{ID: "GO-1"}}}),
+		{ProjectPath: "/src/workspace", Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/log", Version: "v1.3.0", IsActive: true, Module: "example.com/workspace/api"},
+		}},
+	}
+
+	duplicates := Find(results)
+
+	require.Len(t, duplicates, 2, "same is used at a single version")
+	log := duplicates[0]
+	assert.Equal(t, "github.com/example/log", log.Path)
+	assert.Equal(t, 4, log.Projects)
+	assert.Equal(t, "v1.3.0", log.Target)
+	assert.Equal(t, 3, log.Benefit)
+	assert.True(t, log.MixedHealth)
+	assert.Equal(t, []Version{
+		{Version: "v1.3.0", IsActive: true, Projects: []string{"example.com/workspace/api"}},
+		{Version: "v1.2.0", IsActive: true, Vulnerable: true, Projects: []string{"example.com/payments", "example.com/shipping"}},
+		{Version: "v1.0.0", Projects: []string{"example.com/billing"}},
+	}, log.Versions)
+
+	http := duplicates[1]
+	assert.Equal(t, "v2.1.0", http.Target, "newest used version without known latest")
+	assert.Equal(t, 1, http.Benefit)
+	assert.False(t, http.MixedHealth)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, duplicates))
+	assert.Contains(t, out.String(), "github.com/example/log   4         3         v1.3.0      3        mixed")
+	assert.Contains(t, out.String(), "  v1.0.0 [inactive]")
+
+	out.Reset()
+	require.NoError(t, Write(&out, nil))
+	assert.Equal(t, "No module is used at different versions\n", out.String())
+}
@@ -0,0 +1,183 @@
+// Package explain breaks down everything govital knows about a single
+// dependency
+package explain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// shownVersions bounds the versions listed in the text output, the JSON
+// output holds all of them
+const shownVersions = 10
+
+// Explanation is the breakdown of a single dependency
+type Explanation struct {
+	// Dependency is the dependency scanned with all enrichments
+	Dependency scanner.Dependency `json:"dependency"`
+	// Versions are the versions listed by the proxy, the newest first
+	Versions []string `json:"versions"`
+	// Violations are the fail-on conditions the dependency meets
+	Violations []string `json:"violations"`
+	// Warnings are the warn rules the dependency meets
+	Warnings []string `json:"warnings"`
+}
+
+// Explain evaluates the scanned dependency against the conditions
+func Explain(dep scanner.Dependency, versions []string, conditions []policy.Condition) Explanation {
+	explanation := Explanation{Dependency: dep, Versions: versions, Violations: []string{}, Warnings: []string{}}
+	if explanation.Versions == nil {
+		explanation.Versions = []string{}
+	}
+	for _, condition := range conditions {
+		switch {
+		case !condition.Matches(dep):
+		case condition.Warn:
+			explanation.Warnings = append(explanation.Warnings, condition.Name)
+		default:
+			explanation.Violations = append(explanation.Violations, condition.Name)
+		}
+	}
+	return explanation
+}
+
+// Write prints the breakdown grouped into maintenance, repository,
+// security and policy
+func Write(w io.Writer, explanation Explanation) {
+	dep := explanation.Dependency
+	fmt.Fprintf(w, "%s@%s\n", dep.Path, dep.Version)
+
+	fmt.Fprintf(w, "\nMaintenance\n")
+	status := string(dep.Status)
+	if dep.IsAcknowledged {
+		status += " (acknowledged)"
+	}
+	field(w, "Status", status)
+	if dep.Error != nil {
+		field(w, "Error", dep.Error.String())
+	}
+	if dep.Note != "" {
+		field(w, "Note", dep.Note)
+	}
+	field(w, "Used version", dateText(dep.LastReleaseTime, dep.DaysSinceLastRelease))
+	switch {
+	case dep.Unreleased:
+		field(w, "Latest release", "none, only commits are used as pseudo-versions")
+	case dep.Latest != "":
+		latest := dep.Latest
+		if dep.DaysSinceLatestRelease != nil {
+			latest += ", " + dateText(dep.LatestReleaseTime, *dep.DaysSinceLatestRelease)
+		}
+		field(w, "Latest release", latest)
+		field(w, "Releases last year", fmt.Sprintf("%d", dep.ReleasesLastYear))
+	}
+	if dep.Update != "" {
+		field(w, "Update", dep.Update+" is available")
+	}
+	if dep.NewerMajorAvailable != nil {
+		field(w, "Newer major", dep.NewerMajorAvailable.String())
+	}
+	if dep.Score != nil {
+		field(w, "Health score", fmt.Sprintf("%d", *dep.Score))
+	}
+	if len(explanation.Versions) > 0 {
+		versions := explanation.Versions[:min(len(explanation.Versions), shownVersions)]
+		text := strings.Join(versions, ", ")
+		if len(explanation.Versions) > shownVersions {
+			text += fmt.Sprintf(" and %d older", len(explanation.Versions)-shownVersions)
+		}
+		field(w, "Versions", text)
+	}
+
+	fmt.Fprintf(w, "\nRepository\n")
+	if dep.Repository == "" {
+		field(w, "URL", "unknown")
+	} else {
+		field(w, "URL", dep.Repository)
+	}
+	if dep.Archived != nil {
+		field(w, "Archived", fmt.Sprintf("%t", *dep.Archived))
+	}
+	if dep.Upstream != nil {
+		upstream := dep.Upstream.Repository
+		if !dep.Upstream.PushedAt.IsZero() {
+			upstream += ", last push " + dep.Upstream.PushedAt.Format("2006-01-02")
+		}
+		if dep.Upstream.Archived {
+			upstream += ", archived"
+		}
+		if dep.StaleFork {
+			upstream += " (stale fork of an active upstream)"
+		}
+		field(w, "Fork of", upstream)
+	}
+	if dep.ContributorCount != nil {
+		maintainers := fmt.Sprintf("%d contributors in 12 months", *dep.ContributorCount)
+		if dep.BusFactorRisk {
+			maintainers += " (bus factor risk)"
+		}
+		field(w, "Maintainers", maintainers)
+	}
+	if dep.OpenIssues != nil {
+		field(w, "Open issues", fmt.Sprintf("%d", *dep.OpenIssues))
+	}
+	if dep.MedianResponseHours != nil {
+		field(w, "Median response", fmt.Sprintf("%d hours", *dep.MedianResponseHours))
+	}
+	if dep.MergedPullRequests != nil {
+		field(w, "Merged PRs (90 days)", fmt.Sprintf("%d", *dep.MergedPullRequests))
+	}
+	if len(dep.Owners) > 0 {
+		field(w, "Owners", strings.Join(dep.Owners, ", "))
+	}
+
+	fmt.Fprintf(w, "\nSecurity and Licensing\n")
+	if len(dep.Vulnerabilities) == 0 {
+		field(w, "Vulnerabilities", "none known")
+	}
+	for _, vulnerability := range dep.Vulnerabilities {
+		text := vulnerability.ID
+		if vulnerability.Severity != "" {
+			text += " (" + vulnerability.Severity + ")"
+		}
+		if vulnerability.Summary != "" {
+			text += ": " + vulnerability.Summary
+		}
+		field(w, "Vulnerability", text)
+	}
+	if len(dep.Licenses) > 0 {
+		field(w, "Licenses", strings.Join(dep.Licenses, ", "))
+	}
+	if dep.Retracted != nil {
+		field(w, "Retracted", dep.Retracted.String())
+	}
+	if dep.Deprecated != "" {
+		field(w, "Deprecated", dep.Deprecated)
+	}
+
+	fmt.Fprintf(w, "\nPolicy\n")
+	if len(explanation.Violations) == 0 {
+		field(w, "Violations", "none")
+	} else {
+		field(w, "Violations", strings.Join(explanation.Violations, ", "))
+	}
+	if len(explanation.Warnings) > 0 {
+		field(w, "Warnings", strings.Join(explanation.Warnings, ", "))
+	}
+}
+
+func field(w io.Writer, label, value string) {
+	fmt.Fprintf(w, "  %-22s%s\n", label+":", value)
+}
+
+func dateText(t time.Time, days int) string {
+	if t.IsZero() {
+		return "release time unknown"
+	}
+	return fmt.Sprintf("released %s (%d days ago)", t.Format("2006-01-02"), days)
+}
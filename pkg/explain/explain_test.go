@@ -0,0 +1,69 @@
+package explain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestExplain(t *testing.T) {
+	conditions, err := policy.ParseConditions([]string{"inactive,vulnerable,score<50"})
+	require.NoError(t, err)
+	archived := true
+	dep := scanner.Dependency{
+		Path:                   "example.com/lib",
+		Version:                "v1.0.0",
+		Status:                 scanner.StatusArchived,
+		LastReleaseTime:        time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+		DaysSinceLastRelease:   700,
+		Latest:                 "v1.1.0",
+		Update:                 "v1.1.0",
+		LatestReleaseTime:      time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		DaysSinceLatestRelease: intPtr(400),
+		Score:                  intPtr(60),
+		Repository:             "https://github.com/example/lib",
+		Archived:               &archived,
+		ContributorCount:       intPtr(1),
+		BusFactorRisk:          true,
+		OpenIssues:             intPtr(12),
+		Vulnerabilities:        []vuln.Vulnerability{{ID: "GO-2024-0001", Severity: "HIGH", Summary: "Something bad"}},
+		Licenses:               []string{"MIT"},
+	}
+	versions := []string{"v1.1.0", "v1.0.0", "v0.12.0", "v0.11.0", "v0.10.0", "v0.9.0", "v0.8.0", "v0.7.0", "v0.6.0", "v0.5.0", "v0.4.0", "v0.3.0"}
+
+	explanation := Explain(dep, versions, conditions)
+
+	assert.Equal(t, []string{"inactive", "vulnerable"}, explanation.Violations)
+
+	var out bytes.Buffer
+	Write(&out, explanation)
+	assert.Contains(t, out.String(), "Status:               archived")
+	assert.Contains(t, out.String(), "Used version:         released 2022-01-02 (700 days ago)")
+	assert.Contains(t, out.String(), "Latest release:       v1.1.0, released 2023-01-02 (400 days ago)")
+	assert.Contains(t, out.String(), "v0.5.0 and 2 older")
+	assert.Contains(t, out.String(), "Maintainers:          1 contributors in 12 months (bus factor risk)")
+	assert.Contains(t, out.String(), "Open issues:          12")
+	assert.Contains(t, out.String(), "Vulnerability:        GO-2024-0001 (HIGH): Something bad")
+	assert.Contains(t, out.String(), "Violations:           inactive, vulnerable")
+}
+
+func TestExplainWithoutViolations(t *testing.T) {
+	explanation := Explain(scanner.Dependency{Path: "example.com/lib", Version: "v1.0.0", Status: scanner.StatusUnknown}, nil, nil)
+
+	assert.Equal(t, []string{}, explanation.Versions)
+	assert.Equal(t, []string{}, explanation.Violations)
+
+	var out bytes.Buffer
+	Write(&out, explanation)
+	assert.Contains(t, out.String(), "Used version:         release time unknown")
+	assert.Contains(t, out.String(), "URL:                  unknown")
+	assert.Contains(t, out.String(), "Violations:           none")
+}
@@ -0,0 +1,207 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// bitbucketPageSize is the largest page of Bitbucket listings except
+// commits
+const bitbucketPageSize = 50
+
+// bitbucketPage is a page of a Bitbucket listing. Next is the URL of the
+// next page, empty on the last page.
+type bitbucketPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+	// Size is the total number of values, not returned by every listing
+	Size int `json:"size"`
+}
+
+// bitbucketUser is the user of a Bitbucket Cloud account
+type bitbucketUser struct {
+	AccountID string `json:"account_id"`
+}
+
+// bitbucketProvider reads repositories on bitbucket.org
+type bitbucketProvider struct {
+	client *Client
+	host   string
+	// url is the API URL of the repository
+	url string
+}
+
+func newBitbucketProvider(client *Client, apiURL, host, workspace, name string) *bitbucketProvider {
+	return &bitbucketProvider{
+		client: client,
+		host:   host,
+		url:    fmt.Sprintf("%s/repositories/%s/%s", apiURL, url.PathEscape(workspace), url.PathEscape(name)),
+	}
+}
+
+// Repository reports Bitbucket repositories as not archived, Bitbucket
+// can't archive them. Open issues are counted if the issue tracker is
+// enabled.
+func (p *bitbucketProvider) Repository(ctx context.Context) (*RepositoryInfo, error) {
+	var response struct {
+		UpdatedOn time.Time `json:"updated_on"`
+		HasIssues bool      `json:"has_issues"`
+		Parent    *struct {
+			FullName string `json:"full_name"`
+		} `json:"parent"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &response); err != nil {
+		return nil, err
+	}
+	info := &RepositoryInfo{PushedAt: response.UpdatedOn}
+	if response.Parent != nil && response.Parent.FullName != "" {
+		info.Parent = p.host + "/" + response.Parent.FullName
+	}
+	if !response.HasIssues {
+		return info, nil
+	}
+
+	var issues bitbucketPage[struct{}]
+	query := url.QueryEscape(`state="new" OR state="open" OR state="on hold"`)
+	if err := p.client.getJSON(ctx, fmt.Sprintf("%s/issues?q=%s&pagelen=1", p.url, query), &issues); err != nil {
+		return nil, err
+	}
+	info.OpenIssues = &issues.Size
+	return info, nil
+}
+
+// Forks returns the most recently updated forks, Bitbucket has no stars
+func (p *bitbucketProvider) Forks(ctx context.Context, limit int) ([]Fork, error) {
+	var response bitbucketPage[struct {
+		FullName  string    `json:"full_name"`
+		UpdatedOn time.Time `json:"updated_on"`
+	}]
+	requestURL := fmt.Sprintf("%s/forks?sort=-updated_on&pagelen=%d", p.url, min(limit, bitbucketPageSize))
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	forks := make([]Fork, 0, len(response.Values))
+	for _, fork := range response.Values {
+		forks = append(forks, Fork{Root: p.host + "/" + fork.FullName, PushedAt: fork.UpdatedOn})
+	}
+	return forks, nil
+}
+
+// CommitAuthors follows the commit listing, the newest first, until it
+// reaches commits older than since. Bitbucket can't filter commits by date.
+func (p *bitbucketProvider) CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	var authors []string
+	next := fmt.Sprintf("%s/commits?pagelen=%d", p.url, commitsPerPage)
+	for next != "" && len(authors) < limit {
+		var response bitbucketPage[struct {
+			Date   time.Time `json:"date"`
+			Author struct {
+				// Raw is the author of the commit like "Name <email>"
+				Raw  string         `json:"raw"`
+				User *bitbucketUser `json:"user"`
+			} `json:"author"`
+		}]
+		if err := p.client.getJSON(ctx, next, &response); err != nil {
+			return nil, err
+		}
+		for _, commit := range response.Values {
+			if commit.Date.Before(since) {
+				return authors, nil
+			}
+			if commit.Author.User != nil && commit.Author.User.AccountID != "" {
+				authors = append(authors, commit.Author.User.AccountID)
+			} else {
+				authors = append(authors, rawAuthorEmail(commit.Author.Raw))
+			}
+		}
+		next = response.Next
+	}
+	return authors[:min(len(authors), limit)], nil
+}
+
+// rawAuthorEmail returns the lowercase email address of a git author like
+// "Name <email>", or the whole author if it has none
+func rawAuthorEmail(raw string) string {
+	if address, err := mail.ParseAddress(raw); err == nil {
+		return strings.ToLower(address.Address)
+	}
+	return strings.ToLower(raw)
+}
+
+// RecentIssues returns no issues if the issue tracker is disabled
+func (p *bitbucketProvider) RecentIssues(ctx context.Context, since time.Time) ([]Issue, error) {
+	var response bitbucketPage[struct {
+		ID        int            `json:"id"`
+		Reporter  *bitbucketUser `json:"reporter"`
+		CreatedOn time.Time      `json:"created_on"`
+	}]
+	query := url.QueryEscape("created_on >= " + since.UTC().Format(time.RFC3339))
+	requestURL := fmt.Sprintf("%s/issues?q=%s&sort=-created_on&pagelen=%d", p.url, query, maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(response.Values))
+	for _, issue := range response.Values {
+		var author string
+		if issue.Reporter != nil {
+			author = issue.Reporter.AccountID
+		}
+		issues = append(issues, Issue{Number: issue.ID, Author: author, Created: issue.CreatedOn})
+	}
+	return issues, nil
+}
+
+func (p *bitbucketProvider) Comments(ctx context.Context, number int) ([]Comment, error) {
+	var response bitbucketPage[struct {
+		User      *bitbucketUser `json:"user"`
+		CreatedOn time.Time      `json:"created_on"`
+	}]
+	requestURL := fmt.Sprintf("%s/issues/%d/comments?sort=created_on&pagelen=%d", p.url, number, maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0, len(response.Values))
+	for _, comment := range response.Values {
+		var author string
+		if comment.User != nil {
+			author = comment.User.AccountID
+		}
+		comments = append(comments, Comment{Author: author, Created: comment.CreatedOn})
+	}
+	return comments, nil
+}
+
+// MergedPullRequests counts the merged pull requests updated since the
+// given time, Bitbucket doesn't expose the merge time
+func (p *bitbucketProvider) MergedPullRequests(ctx context.Context, since time.Time) (int, error) {
+	var response bitbucketPage[struct{}]
+	query := url.QueryEscape("updated_on >= " + since.UTC().Format(time.RFC3339))
+	requestURL := fmt.Sprintf("%s/pullrequests?state=MERGED&q=%s&pagelen=%d", p.url, query, bitbucketPageSize)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return 0, err
+	}
+	return len(response.Values), nil
+}
+
+func (p *bitbucketProvider) Releases(context.Context, int) ([]Release, error) {
+	return nil, fmt.Errorf("%w: Bitbucket has no releases", ErrUnsupported)
+}
+
+func (p *bitbucketProvider) File(context.Context, string) ([]byte, error) {
+	return nil, fmt.Errorf("%w: Bitbucket serves no file contents as JSON", ErrUnsupported)
+}
+
+func (p *bitbucketProvider) TagSigned(context.Context, string) (bool, error) {
+	return false, fmt.Errorf("%w: Bitbucket doesn't verify tag signatures", ErrUnsupported)
+}
+
+func (p *bitbucketProvider) DefaultBranchProtected(context.Context) (bool, error) {
+	return false, fmt.Errorf("%w: Bitbucket shows branch restrictions to admins only", ErrUnsupported)
+}
@@ -0,0 +1,112 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bitbucketTestRepository = repo.Repository{Root: "bitbucket.org/team/mod", URL: "https://bitbucket.org/team/mod"}
+
+func TestRepositoryBitbucket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/bitbucket/repositories/team/mod":
+			_, _ = w.Write([]byte(`{"updated_on":"2024-01-02T03:04:05Z","has_issues":true}`))
+		case "/bitbucket/repositories/team/mod/issues":
+			assert.Contains(t, r.URL.Query().Get("q"), `state="open"`)
+			_, _ = w.Write([]byte(`{"values":[{}],"size":4}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{BitbucketToken: "secret"})
+
+	info, err := client.Repository(context.Background(), bitbucketTestRepository)
+
+	require.NoError(t, err)
+	assert.False(t, info.Archived)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), info.PushedAt)
+	require.NotNil(t, info.OpenIssues)
+	assert.Equal(t, 4, *info.OpenIssues)
+}
+
+func TestContributorsBitbucket(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bitbucket/repositories/team/mod/commits", r.URL.Path)
+		if r.URL.Query().Get("page") == "" {
+			_, _ = w.Write([]byte(`{"values":[
+				{"date":"2024-03-01T00:00:00Z","author":{"raw":"Alice <alice@example.com>","user":{"account_id":"a1"}}},
+				{"date":"2024-02-01T00:00:00Z","author":{"raw":"Alice <Alice@Example.com>","user":{"account_id":"a1"}}},
+				{"date":"2024-02-01T00:00:00Z","author":{"raw":"Bob <Bob@example.com>"}}
+			],"next":"` + server.URL + `/bitbucket/repositories/team/mod/commits?page=abc"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[
+			{"date":"2024-01-15T00:00:00Z","author":{"raw":"bob@example.com"}},
+			{"date":"2023-06-01T00:00:00Z","author":{"raw":"Carol <carol@example.com>"}}
+		],"next":"` + server.URL + `/bitbucket/repositories/team/mod/commits?page=def"}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	count, err := client.Contributors(context.Background(), bitbucketTestRepository, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "commits before since end the listing")
+}
+
+func TestResponsivenessBitbucket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bitbucket/repositories/team/mod/issues":
+			assert.Equal(t, "created_on >= 2024-01-01T00:00:00Z", r.URL.Query().Get("q"))
+			_, _ = w.Write([]byte(`{"values":[{"id":3,"reporter":{"account_id":"a1"},"created_on":"2024-03-01T00:00:00Z"}]}`))
+		case "/bitbucket/repositories/team/mod/issues/3/comments":
+			_, _ = w.Write([]byte(`{"values":[{"user":{"account_id":"m1"},"created_on":"2024-03-01T12:00:00Z"}]}`))
+		case "/bitbucket/repositories/team/mod/pullrequests":
+			assert.Equal(t, "MERGED", r.URL.Query().Get("state"))
+			_, _ = w.Write([]byte(`{"values":[{},{},{}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	responsiveness, err := client.Responsiveness(context.Background(), bitbucketTestRepository, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.NotNil(t, responsiveness.MedianFirstResponse)
+	assert.Equal(t, 12*time.Hour, *responsiveness.MedianFirstResponse)
+	assert.Equal(t, 3, responsiveness.MergedPullRequests)
+}
+
+func TestResponsivenessBitbucketWithoutIssueTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bitbucket/repositories/team/mod/pullrequests" {
+			_, _ = w.Write([]byte(`{"values":[]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	responsiveness, err := client.Responsiveness(context.Background(), bitbucketTestRepository, time.Now())
+
+	require.NoError(t, err)
+	assert.Nil(t, responsiveness.MedianFirstResponse)
+	assert.Zero(t, responsiveness.Unanswered)
+}
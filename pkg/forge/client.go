@@ -0,0 +1,283 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultGitHubURL is the API of github.com
+	DefaultGitHubURL = "https://api.github.com"
+	// DefaultGitLabURL is the API of gitlab.com
+	DefaultGitLabURL = "https://gitlab.com/api/v4"
+	// DefaultBitbucketURL is the API of bitbucket.org
+	DefaultBitbucketURL = "https://api.bitbucket.org/2.0"
+	// DefaultSourcehutURL is the GraphQL API of git.sr.ht
+	DefaultSourcehutURL = "https://git.sr.ht/query"
+
+	// maxRetries is the number of retries of failed or throttled requests
+	maxRetries = 3
+	// baseBackoff is the wait before the first retry, doubled per retry
+	baseBackoff = 500 * time.Millisecond
+	// maxRateLimitWait is the longest wait for a rate limit reset. Longer
+	// resets, like the hourly GitHub limit, fail the request instead of
+	// stalling the scan.
+	maxRateLimitWait = time.Minute
+)
+
+// Config holds the API tokens of the forges. Without a token the anonymous
+// rate limits apply, e.g. 60 requests per hour for GitHub. sourcehut
+// doesn't answer anonymous requests at all.
+type Config struct {
+	GitHubToken    string `mapstructure:"github_token"`
+	GitLabToken    string `mapstructure:"gitlab_token"`
+	BitbucketToken string `mapstructure:"bitbucket_token"`
+	// GiteaToken authenticates at the Gitea or Forgejo of GiteaURL. Public
+	// instances like codeberg.org are queried anonymously.
+	GiteaToken     string `mapstructure:"gitea_token"`
+	SourcehutToken string `mapstructure:"sourcehut_token"`
+	// GitHubURL is the API of a GitHub Enterprise Server, e.g.
+	// https://github.example.com/api/v3. Repositories on its host are
+	// looked up there, authenticated with GitHubToken.
+	GitHubURL string `mapstructure:"github_url"`
+	// GitLabURL is the API of a self-hosted GitLab, e.g.
+	// https://gitlab.example.com/api/v4. Repositories on its host are
+	// looked up there, authenticated with GitLabToken.
+	GitLabURL string `mapstructure:"gitlab_url"`
+	// GiteaURL is the API of a self-hosted Gitea or Forgejo, e.g.
+	// https://git.example.com/api/v1. Repositories on its host are looked
+	// up there, authenticated with GiteaToken.
+	GiteaURL string `mapstructure:"gitea_url"`
+	// Hosts maps host patterns to self-hosted forges, for more than one
+	// self-hosted forge or hosts whose forge type can't be detected. They
+	// take precedence over the URLs above.
+	Hosts []Host `mapstructure:"hosts"`
+}
+
+// RateLimitError is returned if a forge rate limit is exhausted for longer
+// than the client is willing to wait
+type RateLimitError struct {
+	Host  string
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("rate limit of %s exceeded", e.Host)
+	}
+	return fmt.Sprintf("rate limit of %s exceeded until %s", e.Host, e.Reset.Format(time.RFC3339))
+}
+
+// StatusError is returned for unexpected response status codes
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.URL, e.StatusCode)
+}
+
+// Client is the shared HTTP client for forge APIs. It authenticates with
+// the configured tokens, waits for rate limit resets and retries server
+// errors with exponential backoff.
+type Client struct {
+	GitHubURL    string
+	GitLabURL    string
+	BitbucketURL string
+	SourcehutURL string
+	// GiteaHosts maps public Gitea and Forgejo hosts to their API
+	GiteaHosts map[string]string
+
+	httpClient *http.Client
+	config     Config
+	// sleep waits for the duration or until ctx is done
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+// NewClient creates a forge client sending requests with httpClient
+func NewClient(httpClient *http.Client, config Config) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{
+		GitHubURL:    DefaultGitHubURL,
+		GitLabURL:    DefaultGitLabURL,
+		BitbucketURL: DefaultBitbucketURL,
+		SourcehutURL: DefaultSourcehutURL,
+		GiteaHosts: map[string]string{
+			"codeberg.org": "https://codeberg.org/api/v1",
+			"gitea.com":    "https://gitea.com/api/v1",
+		},
+		httpClient: httpClient,
+		config:     config,
+		sleep:      sleepContext,
+		now:        time.Now,
+	}
+}
+
+// getJSON fetches the URL and decodes the JSON response into target
+func (c *Client) getJSON(ctx context.Context, requestURL string, target any) error {
+	return c.doJSON(ctx, http.MethodGet, requestURL, nil, target)
+}
+
+// postJSON posts the JSON encoded body to the URL and decodes the JSON
+// response into target, e.g. for GraphQL queries
+func (c *Client) postJSON(ctx context.Context, requestURL string, body, target any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request for %s: %w", requestURL, err)
+	}
+	return c.doJSON(ctx, http.MethodPost, requestURL, data, target)
+}
+
+// doJSON sends the request and decodes the JSON response into target,
+// retrying throttled and failed requests
+func (c *Client) doJSON(ctx context.Context, method, requestURL string, body []byte, target any) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := c.sleep(ctx, backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		request, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+		if err != nil {
+			return fmt.Errorf("failed to create request for %s: %w", requestURL, err)
+		}
+		c.authenticate(request)
+		request.Header.Set("Accept", "application/json")
+		if body != nil {
+			request.Header.Set("Content-Type", "application/json")
+		}
+
+		response, err := c.httpClient.Do(request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		retry, err := c.handleResponse(ctx, response, target)
+		if !retry {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("giving up after %d retries: %w", maxRetries, lastErr)
+}
+
+// handleResponse decodes a successful response and reports whether a
+// failed request should be retried
+func (c *Client) handleResponse(ctx context.Context, response *http.Response, target any) (bool, error) {
+	defer response.Body.Close()
+	requestURL := response.Request.URL.String()
+
+	if wait, limited := c.rateLimitWait(response); limited {
+		_, _ = io.Copy(io.Discard, response.Body)
+		if wait > maxRateLimitWait {
+			return false, &RateLimitError{Host: response.Request.URL.Host, Reset: c.now().Add(wait)}
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return false, err
+		}
+		return true, &RateLimitError{Host: response.Request.URL.Host}
+	}
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+		if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+			return false, fmt.Errorf("failed to decode response of %s: %w", requestURL, err)
+		}
+		return false, nil
+	case response.StatusCode >= 500:
+		return true, &StatusError{URL: requestURL, StatusCode: response.StatusCode}
+	default:
+		return false, &StatusError{URL: requestURL, StatusCode: response.StatusCode}
+	}
+}
+
+// authenticate adds the token of the forge the request goes to
+func (c *Client) authenticate(request *http.Request) {
+	requestURL := request.URL.String()
+	if c.authenticateHost(requestURL, request.Header.Set) {
+		return
+	}
+	switch {
+	case c.config.GitHubToken != "" && (hasURLPrefix(requestURL, c.GitHubURL) || hasURLPrefix(requestURL, c.config.GitHubURL)):
+		request.Header.Set("Authorization", "Bearer "+c.config.GitHubToken)
+	case c.config.GitLabToken != "" && (hasURLPrefix(requestURL, c.GitLabURL) || hasURLPrefix(requestURL, c.config.GitLabURL)):
+		request.Header.Set("PRIVATE-TOKEN", c.config.GitLabToken)
+	case c.config.BitbucketToken != "" && hasURLPrefix(requestURL, c.BitbucketURL):
+		request.Header.Set("Authorization", "Bearer "+c.config.BitbucketToken)
+	case c.config.GiteaToken != "" && hasURLPrefix(requestURL, c.config.GiteaURL):
+		request.Header.Set("Authorization", "token "+c.config.GiteaToken)
+	case c.config.SourcehutToken != "" && hasURLPrefix(requestURL, c.SourcehutURL):
+		request.Header.Set("Authorization", "Bearer "+c.config.SourcehutToken)
+	}
+}
+
+func hasURLPrefix(requestURL, prefix string) bool {
+	return prefix != "" && strings.HasPrefix(requestURL, strings.TrimSuffix(prefix, "/"))
+}
+
+// rateLimitWait returns how long to wait if the response reports an
+// exhausted rate limit. GitHub answers 403 or 429 with X-RateLimit headers,
+// GitLab 429 with RateLimit headers, both may send Retry-After.
+func (c *Client) rateLimitWait(response *http.Response) (time.Duration, bool) {
+	exhausted := response.Header.Get("X-RateLimit-Remaining") == "0" || response.Header.Get("RateLimit-Remaining") == "0"
+	if response.StatusCode != http.StatusTooManyRequests && !(response.StatusCode == http.StatusForbidden && exhausted) {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if reset, err := strconv.ParseInt(response.Header.Get(name), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(c.now()), 0), true
+		}
+	}
+	return backoff(1), true
+}
+
+// backoff returns the jittered exponential wait before the given retry
+func backoff(attempt int) time.Duration {
+	wait := baseBackoff << (attempt - 1)
+	return wait/2 + rand.N(wait/2+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsNotFound reports whether err is a 404 response, e.g. for repositories
+// which were deleted or are private
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,284 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client for the test server which records the
+// waits instead of sleeping
+func newTestClient(server *httptest.Server, config Config) (*Client, *[]time.Duration) {
+	var waits []time.Duration
+	client := NewClient(server.Client(), config)
+	client.GitHubURL = server.URL + "/github"
+	client.GitLabURL = server.URL + "/gitlab"
+	client.BitbucketURL = server.URL + "/bitbucket"
+	client.SourcehutURL = server.URL + "/sourcehut/query"
+	client.GiteaHosts = map[string]string{"codeberg.org": server.URL + "/gitea"}
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return client, &waits
+}
+
+func TestRepositoryGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/github/repos/example/mod", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`{"archived":true,"pushed_at":"2024-01-02T03:04:05Z","open_issues_count":7}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GitHubToken: "secret", GitLabToken: "other"})
+
+	info, err := client.Repository(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"})
+
+	require.NoError(t, err)
+	assert.True(t, info.Archived)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), info.PushedAt)
+	require.NotNil(t, info.OpenIssues)
+	assert.Equal(t, 7, *info.OpenIssues)
+	assert.Empty(t, info.Parent)
+}
+
+func TestRepositoryFork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/github/"):
+			_, _ = w.Write([]byte(`{"fork":true,"parent":{"full_name":"upstream/mod"}}`))
+		case strings.HasPrefix(r.URL.Path, "/gitlab/"):
+			_, _ = w.Write([]byte(`{"forked_from_project":{"path_with_namespace":"group/sub/mod"}}`))
+		case strings.HasPrefix(r.URL.Path, "/bitbucket/"):
+			_, _ = w.Write([]byte(`{"parent":{"full_name":"team/mod"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"fork":true,"parent":{"full_name":"owner/mod"}}`))
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	for url, parent := range map[string]string{
+		"https://github.com/someone/mod":    "github.com/upstream/mod",
+		"https://gitlab.com/someone/mod":    "gitlab.com/group/sub/mod",
+		"https://bitbucket.org/someone/mod": "bitbucket.org/team/mod",
+		"https://codeberg.org/someone/mod":  "codeberg.org/owner/mod",
+	} {
+		info, err := client.Repository(context.Background(), repo.Repository{URL: url})
+		require.NoError(t, err, url)
+		assert.Equal(t, parent, info.Parent, url)
+	}
+}
+
+func TestRepositorySelfHosted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fmod", r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`{"archived":true}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GitLabToken: "secret", GitLabURL: server.URL + "/api/v4/"})
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	info, err := client.Repository(context.Background(), repo.Repository{Root: host + "/group/mod", URL: server.URL + "/group/mod"})
+
+	require.NoError(t, err)
+	assert.True(t, info.Archived)
+
+	_, err = client.Repository(context.Background(), repo.Repository{Root: "git.example.com/group/mod", URL: "https://git.example.com/group/mod"})
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestForksGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/github/repos/example/mod/forks", r.URL.Path)
+		assert.Equal(t, "stargazers", r.URL.Query().Get("sort"))
+		assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+		_, _ = w.Write([]byte(`[{"full_name":"fork/mod","stargazers_count":42,"archived":false,"pushed_at":"2024-01-02T03:04:05Z"}]`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	forks, err := client.Forks(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"}, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Fork{{Root: "github.com/fork/mod", Stars: 42, PushedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}}, forks)
+}
+
+func TestContributorsGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/github/repos/example/mod/commits", r.URL.Path)
+		assert.Equal(t, "2024-01-01T00:00:00Z", r.URL.Query().Get("since"))
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		_, _ = w.Write([]byte(`[
+			{"author":{"login":"alice"},"commit":{"author":{"email":"alice@example.com"}}},
+			{"author":{"login":"alice"},"commit":{"author":{"email":"alice@work.example.com"}}},
+			{"author":null,"commit":{"author":{"email":"Bob@example.com"}}},
+			{"author":null,"commit":{"author":{"email":"bob@example.com"}}}
+		]`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	count, err := client.Contributors(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"},
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestContributorsGitLabPages(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gitlab/projects/group%2Fmod/repository/commits", r.URL.EscapedPath())
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		commits := make([]string, commitsPerPage)
+		for i := range commits {
+			commits[i] = fmt.Sprintf(`{"author_email":"dev%s-%d@example.com"}`, page, i%2)
+		}
+		if page == "2" {
+			commits = commits[:1]
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(commits, ",") + "]"))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	count, err := client.Contributors(context.Background(), repo.Repository{Root: "gitlab.com/group/mod", URL: "https://gitlab.com/group/mod"}, time.Now())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, pages)
+	assert.Equal(t, 3, count)
+}
+
+func TestRepositoryGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gitlab/projects/group%2Fmod", r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"archived":false,"last_activity_at":"2024-01-02T03:04:05Z"}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GitLabToken: "secret"})
+
+	info, err := client.Repository(context.Background(), repo.Repository{Root: "gitlab.com/group/mod", URL: "https://gitlab.com/group/mod"})
+
+	require.NoError(t, err)
+	assert.False(t, info.Archived)
+}
+
+func TestRepositoryUnsupported(t *testing.T) {
+	client := NewClient(nil, Config{})
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "go.googlesource.com/mod", URL: "https://go.googlesource.com/mod"})
+
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestRateLimitWaitsForReset(t *testing.T) {
+	var requests atomic.Int32
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(20*time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"archived":false}`))
+	}))
+	defer server.Close()
+	client, waits := newTestClient(server, Config{})
+	client.now = func() time.Time { return now }
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	require.NotEmpty(t, *waits)
+	assert.Equal(t, 20*time.Second, (*waits)[0])
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client, waits := newTestClient(server, Config{})
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "gitlab.com/group/mod", URL: "https://gitlab.com/group/mod"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, (*waits)[0])
+}
+
+func TestRateLimitResetTooFarAway(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	client, waits := newTestClient(server, Config{})
+	client.now = func() time.Time { return now }
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"})
+
+	var rateLimitErr *RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, now.Add(time.Hour), rateLimitErr.Reset)
+	assert.Empty(t, *waits)
+}
+
+func TestServerErrorsAreRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client, waits := newTestClient(server, Config{})
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"})
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	assert.Equal(t, int32(maxRetries+1), requests.Load())
+	require.Len(t, *waits, maxRetries)
+	assert.Less(t, (*waits)[0], (*waits)[2], "backoff grows")
+}
+
+func TestNotFoundIsNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	_, err := client.Repository(context.Background(), repo.Repository{Root: "github.com/example/gone", URL: "https://github.com/example/gone"})
+
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,40 @@
+package forge
+
+import (
+	"context"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+const (
+	// commitsPerPage is the page size of commit listings, the maximum of
+	// GitHub, GitLab and Bitbucket
+	commitsPerPage = 100
+	// maxCommits bounds the commits read per repository. Busy repositories
+	// reach the limit with many contributors anyway, so counting further
+	// doesn't change the assessment.
+	maxCommits = 3 * commitsPerPage
+)
+
+// Contributors returns the number of distinct commit authors of the
+// default branch since the given time, counting at most maxCommits
+// commits. Authors are told apart by their forge account, or by their
+// email address for commits which aren't linked to one. Other hosts return
+// ErrUnsupported.
+func (c *Client) Contributors(ctx context.Context, repository repo.Repository, since time.Time) (int, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return 0, err
+	}
+	keys, err := provider.CommitAuthors(ctx, since, maxCommits)
+	if err != nil {
+		return 0, err
+	}
+
+	authors := make(map[string]bool)
+	for _, key := range keys {
+		authors[key] = true
+	}
+	return len(authors), nil
+}
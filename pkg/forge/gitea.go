@@ -0,0 +1,237 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// giteaPageSize is the default maximum page size of Gitea and Forgejo
+const giteaPageSize = 50
+
+// giteaUser is the account of a Gitea or Forgejo user
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+// giteaProvider reads repositories on Gitea and Forgejo instances like
+// codeberg.org, their API follows GitHub's closely
+type giteaProvider struct {
+	client *Client
+	host   string
+	// url is the API URL of the repository
+	url string
+}
+
+func newGiteaProvider(client *Client, apiURL, host, owner, name string) *giteaProvider {
+	return &giteaProvider{
+		client: client,
+		host:   host,
+		url:    fmt.Sprintf("%s/repos/%s/%s", apiURL, url.PathEscape(owner), url.PathEscape(name)),
+	}
+}
+
+func (p *giteaProvider) Repository(ctx context.Context) (*RepositoryInfo, error) {
+	var response struct {
+		Archived   bool      `json:"archived"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		HasIssues  bool      `json:"has_issues"`
+		OpenIssues int       `json:"open_issues_count"`
+		Parent     *struct {
+			FullName string `json:"full_name"`
+		} `json:"parent"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &response); err != nil {
+		return nil, err
+	}
+	info := &RepositoryInfo{Archived: response.Archived, PushedAt: response.UpdatedAt}
+	if response.HasIssues {
+		info.OpenIssues = &response.OpenIssues
+	}
+	if response.Parent != nil && response.Parent.FullName != "" {
+		info.Parent = p.host + "/" + response.Parent.FullName
+	}
+	return info, nil
+}
+
+// Forks sorts the forks by stars itself, the API can't
+func (p *giteaProvider) Forks(ctx context.Context, limit int) ([]Fork, error) {
+	var response []struct {
+		FullName  string    `json:"full_name"`
+		Stars     int       `json:"stars_count"`
+		Archived  bool      `json:"archived"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	requestURL := fmt.Sprintf("%s/forks?limit=%d", p.url, giteaPageSize)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	forks := make([]Fork, 0, len(response))
+	for _, fork := range response {
+		forks = append(forks, Fork{Root: p.host + "/" + fork.FullName, Stars: fork.Stars, Archived: fork.Archived, PushedAt: fork.UpdatedAt})
+	}
+	sort.SliceStable(forks, func(i, j int) bool { return forks[i].Stars > forks[j].Stars })
+	return forks[:min(len(forks), limit)], nil
+}
+
+func (p *giteaProvider) CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return collectPages(limit, giteaPageSize, func(page int) ([]string, error) {
+		var response []struct {
+			Author *giteaUser `json:"author"`
+			Commit struct {
+				Author struct {
+					Email string `json:"email"`
+				} `json:"author"`
+			} `json:"commit"`
+		}
+		requestURL := fmt.Sprintf("%s/commits?since=%s&limit=%d&page=%d&stat=false&verification=false&files=false", p.url,
+			url.QueryEscape(since.UTC().Format(time.RFC3339)), giteaPageSize, page)
+		if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+			return nil, err
+		}
+		authors := make([]string, 0, len(response))
+		for _, commit := range response {
+			if commit.Author != nil && commit.Author.Login != "" {
+				authors = append(authors, commit.Author.Login)
+			} else {
+				authors = append(authors, strings.ToLower(commit.Commit.Author.Email))
+			}
+		}
+		return authors, nil
+	})
+}
+
+func (p *giteaProvider) RecentIssues(ctx context.Context, since time.Time) ([]Issue, error) {
+	var response []struct {
+		Number    int       `json:"number"`
+		User      giteaUser `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	// since filters by the last update, issues created earlier are dropped
+	requestURL := fmt.Sprintf("%s/issues?state=all&type=issues&since=%s&limit=%d", p.url,
+		url.QueryEscape(since.UTC().Format(time.RFC3339)), giteaPageSize)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, issue := range response {
+		if len(issues) == maxResponseSamples {
+			break
+		}
+		if !issue.CreatedAt.Before(since) {
+			issues = append(issues, Issue{Number: issue.Number, Author: issue.User.Login, Created: issue.CreatedAt})
+		}
+	}
+	return issues, nil
+}
+
+func (p *giteaProvider) Comments(ctx context.Context, number int) ([]Comment, error) {
+	var response []struct {
+		User      giteaUser `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	requestURL := fmt.Sprintf("%s/issues/%d/comments", p.url, number)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0, len(response))
+	for _, c := range response {
+		comments = append(comments, Comment{Author: c.User.Login, Created: c.CreatedAt})
+	}
+	return comments, nil
+}
+
+func (p *giteaProvider) MergedPullRequests(ctx context.Context, since time.Time) (int, error) {
+	var response []struct {
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	requestURL := fmt.Sprintf("%s/pulls?state=closed&sort=recentupdate&limit=%d", p.url, giteaPageSize)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return 0, err
+	}
+	merged := 0
+	for _, pull := range response {
+		if pull.MergedAt != nil && !pull.MergedAt.Before(since) {
+			merged++
+		}
+	}
+	return merged, nil
+}
+
+func (p *giteaProvider) Releases(ctx context.Context, limit int) ([]Release, error) {
+	var response []struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		Draft       bool      `json:"draft"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := p.client.getJSON(ctx, fmt.Sprintf("%s/releases?limit=%d", p.url, limit), &response); err != nil {
+		return nil, err
+	}
+	releases := make([]Release, 0, len(response))
+	for _, release := range response {
+		if !release.Draft {
+			releases = append(releases, Release{Tag: release.TagName, Name: release.Name, Notes: release.Body, URL: release.HTMLURL, Published: release.PublishedAt})
+		}
+	}
+	return releases, nil
+}
+
+func (p *giteaProvider) File(ctx context.Context, path string) ([]byte, error) {
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/contents/"+path, &response); err != nil {
+		return nil, err
+	}
+	return decodeContent(response.Content, response.Encoding)
+}
+
+// TagSigned reads the tag object, lightweight tags point to the commit
+// directly and are unsigned
+func (p *giteaProvider) TagSigned(ctx context.Context, tag string) (bool, error) {
+	var ref struct {
+		ID     string `json:"id"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/tags/"+url.PathEscape(tag), &ref); err != nil {
+		return false, err
+	}
+	if ref.ID == "" || ref.ID == ref.Commit.SHA {
+		return false, nil
+	}
+	var response struct {
+		Verification struct {
+			Verified bool `json:"verified"`
+		} `json:"verification"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/git/tags/"+ref.ID, &response); err != nil {
+		return false, err
+	}
+	return response.Verification.Verified, nil
+}
+
+func (p *giteaProvider) DefaultBranchProtected(ctx context.Context) (bool, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &repository); err != nil {
+		return false, err
+	}
+	var response struct {
+		Protected bool `json:"protected"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/branches/"+url.PathEscape(repository.DefaultBranch), &response); err != nil {
+		return false, err
+	}
+	return response.Protected, nil
+}
@@ -0,0 +1,96 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var giteaTestRepository = repo.Repository{Root: "codeberg.org/owner/mod", URL: "https://codeberg.org/owner/mod"}
+
+func TestRepositoryGitea(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gitea/repos/owner/mod", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"), "the token only applies to the self-hosted instance")
+		_, _ = w.Write([]byte(`{"archived":true,"updated_at":"2024-01-02T03:04:05Z","has_issues":true,"open_issues_count":3}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GiteaToken: "secret", GiteaURL: "https://git.example.org/api/v1"})
+
+	info, err := client.Repository(context.Background(), giteaTestRepository)
+
+	require.NoError(t, err)
+	assert.True(t, info.Archived)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), info.PushedAt)
+	require.NotNil(t, info.OpenIssues)
+	assert.Equal(t, 3, *info.OpenIssues)
+}
+
+func TestRepositoryGiteaSelfHosted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/owner/mod", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"archived":false,"has_issues":false}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GiteaToken: "secret", GiteaURL: server.URL + "/api/v1"})
+
+	info, err := client.Repository(context.Background(), repo.Repository{URL: server.URL + "/owner/mod"})
+
+	require.NoError(t, err)
+	assert.Nil(t, info.OpenIssues, "the issue tracker is disabled")
+}
+
+func TestForksGitea(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gitea/repos/owner/mod/forks", r.URL.Path)
+		_, _ = w.Write([]byte(`[
+			{"full_name":"a/mod","stars_count":1},
+			{"full_name":"b/mod","stars_count":9},
+			{"full_name":"c/mod","stars_count":5}
+		]`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	forks, err := client.Forks(context.Background(), giteaTestRepository, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Fork{{Root: "codeberg.org/b/mod", Stars: 9}, {Root: "codeberg.org/c/mod", Stars: 5}}, forks)
+}
+
+func TestResponsivenessGitea(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gitea/repos/owner/mod/issues":
+			assert.Equal(t, "issues", r.URL.Query().Get("type"))
+			_, _ = w.Write([]byte(`[
+				{"number":5,"user":{"login":"alice"},"created_at":"2024-03-01T00:00:00Z"},
+				{"number":1,"user":{"login":"bob"},"created_at":"2023-01-01T00:00:00Z"}
+			]`))
+		case "/gitea/repos/owner/mod/issues/5/comments":
+			_, _ = w.Write([]byte(`[{"user":{"login":"maintainer"},"created_at":"2024-03-01T02:00:00Z"}]`))
+		case "/gitea/repos/owner/mod/pulls":
+			_, _ = w.Write([]byte(`[{"merged_at":"2024-02-01T00:00:00Z"},{"merged_at":null}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	responsiveness, err := client.Responsiveness(context.Background(), giteaTestRepository, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.NotNil(t, responsiveness.MedianFirstResponse)
+	assert.Equal(t, 2*time.Hour, *responsiveness.MedianFirstResponse, "issues updated but not created since are ignored")
+	assert.Zero(t, responsiveness.Unanswered)
+	assert.Equal(t, 1, responsiveness.MergedPullRequests)
+}
@@ -0,0 +1,229 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// githubProvider reads repositories on github.com or a GitHub Enterprise
+// Server
+type githubProvider struct {
+	client *Client
+	host   string
+	// url is the API URL of the repository
+	url string
+}
+
+func newGitHubProvider(client *Client, apiURL, host, owner, name string) *githubProvider {
+	return &githubProvider{
+		client: client,
+		host:   host,
+		url:    fmt.Sprintf("%s/repos/%s/%s", apiURL, url.PathEscape(owner), url.PathEscape(name)),
+	}
+}
+
+func (p *githubProvider) Repository(ctx context.Context) (*RepositoryInfo, error) {
+	var response struct {
+		Archived   bool      `json:"archived"`
+		PushedAt   time.Time `json:"pushed_at"`
+		OpenIssues int       `json:"open_issues_count"`
+		Parent     *struct {
+			FullName string `json:"full_name"`
+		} `json:"parent"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &response); err != nil {
+		return nil, err
+	}
+	info := &RepositoryInfo{Archived: response.Archived, PushedAt: response.PushedAt, OpenIssues: &response.OpenIssues}
+	if response.Parent != nil && response.Parent.FullName != "" {
+		info.Parent = p.host + "/" + response.Parent.FullName
+	}
+	return info, nil
+}
+
+func (p *githubProvider) Forks(ctx context.Context, limit int) ([]Fork, error) {
+	var response []struct {
+		FullName string    `json:"full_name"`
+		Stars    int       `json:"stargazers_count"`
+		Archived bool      `json:"archived"`
+		PushedAt time.Time `json:"pushed_at"`
+	}
+	requestURL := fmt.Sprintf("%s/forks?sort=stargazers&per_page=%d", p.url, limit)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	forks := make([]Fork, 0, len(response))
+	for _, fork := range response {
+		forks = append(forks, Fork{Root: p.host + "/" + fork.FullName, Stars: fork.Stars, Archived: fork.Archived, PushedAt: fork.PushedAt})
+	}
+	return forks, nil
+}
+
+func (p *githubProvider) CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return collectPages(limit, commitsPerPage, func(page int) ([]string, error) {
+		var response []struct {
+			Author *struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			Commit struct {
+				Author struct {
+					Email string `json:"email"`
+				} `json:"author"`
+			} `json:"commit"`
+		}
+		requestURL := fmt.Sprintf("%s/commits?since=%s&per_page=%d&page=%d", p.url,
+			url.QueryEscape(since.UTC().Format(time.RFC3339)), commitsPerPage, page)
+		if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+			return nil, err
+		}
+		authors := make([]string, 0, len(response))
+		for _, commit := range response {
+			if commit.Author != nil && commit.Author.Login != "" {
+				authors = append(authors, commit.Author.Login)
+			} else {
+				authors = append(authors, strings.ToLower(commit.Commit.Author.Email))
+			}
+		}
+		return authors, nil
+	})
+}
+
+func (p *githubProvider) RecentIssues(ctx context.Context, since time.Time) ([]Issue, error) {
+	var response []struct {
+		Number int `json:"number"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt   time.Time `json:"created_at"`
+		PullRequest *struct{} `json:"pull_request"`
+	}
+	// The issues endpoint includes pull requests, fetch more to keep enough
+	// issues
+	requestURL := fmt.Sprintf("%s/issues?state=all&sort=created&direction=desc&per_page=%d", p.url, 3*maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, issue := range response {
+		if issue.CreatedAt.Before(since) || len(issues) == maxResponseSamples {
+			break
+		}
+		if issue.PullRequest == nil {
+			issues = append(issues, Issue{Number: issue.Number, Author: issue.User.Login, Created: issue.CreatedAt})
+		}
+	}
+	return issues, nil
+}
+
+func (p *githubProvider) Comments(ctx context.Context, number int) ([]Comment, error) {
+	var response []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	requestURL := fmt.Sprintf("%s/issues/%d/comments?per_page=%d", p.url, number, maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0, len(response))
+	for _, c := range response {
+		comments = append(comments, Comment{Author: c.User.Login, Created: c.CreatedAt})
+	}
+	return comments, nil
+}
+
+func (p *githubProvider) MergedPullRequests(ctx context.Context, since time.Time) (int, error) {
+	var response []struct {
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	requestURL := fmt.Sprintf("%s/pulls?state=closed&sort=updated&direction=desc&per_page=%d", p.url, pullRequestsPerPage)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return 0, err
+	}
+	merged := 0
+	for _, pull := range response {
+		if pull.MergedAt != nil && !pull.MergedAt.Before(since) {
+			merged++
+		}
+	}
+	return merged, nil
+}
+
+func (p *githubProvider) Releases(ctx context.Context, limit int) ([]Release, error) {
+	var response []struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		Draft       bool      `json:"draft"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := p.client.getJSON(ctx, fmt.Sprintf("%s/releases?per_page=%d", p.url, limit), &response); err != nil {
+		return nil, err
+	}
+	releases := make([]Release, 0, len(response))
+	for _, release := range response {
+		if !release.Draft {
+			releases = append(releases, Release{Tag: release.TagName, Name: release.Name, Notes: release.Body, URL: release.HTMLURL, Published: release.PublishedAt})
+		}
+	}
+	return releases, nil
+}
+
+func (p *githubProvider) File(ctx context.Context, path string) ([]byte, error) {
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/contents/"+path, &response); err != nil {
+		return nil, err
+	}
+	return decodeContent(response.Content, response.Encoding)
+}
+
+// TagSigned reads the tag object, lightweight tags have none and are
+// unsigned
+func (p *githubProvider) TagSigned(ctx context.Context, tag string) (bool, error) {
+	var ref struct {
+		Object struct {
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/git/ref/tags/"+url.PathEscape(tag), &ref); err != nil {
+		return false, err
+	}
+	if ref.Object.Type != "tag" {
+		return false, nil
+	}
+	var response struct {
+		Verification struct {
+			Verified bool `json:"verified"`
+		} `json:"verification"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/git/tags/"+ref.Object.SHA, &response); err != nil {
+		return false, err
+	}
+	return response.Verification.Verified, nil
+}
+
+func (p *githubProvider) DefaultBranchProtected(ctx context.Context) (bool, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &repository); err != nil {
+		return false, err
+	}
+	var response struct {
+		Protected bool `json:"protected"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/branches/"+url.PathEscape(repository.DefaultBranch), &response); err != nil {
+		return false, err
+	}
+	return response.Protected, nil
+}
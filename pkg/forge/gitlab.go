@@ -0,0 +1,210 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gitlabProvider reads projects on gitlab.com or a self-hosted GitLab
+type gitlabProvider struct {
+	client *Client
+	host   string
+	// url is the API URL of the project
+	url string
+}
+
+func newGitLabProvider(client *Client, apiURL, host, namespace, name string) *gitlabProvider {
+	return &gitlabProvider{
+		client: client,
+		host:   host,
+		url:    fmt.Sprintf("%s/projects/%s", apiURL, url.PathEscape(namespace+"/"+name)),
+	}
+}
+
+func (p *gitlabProvider) Repository(ctx context.Context) (*RepositoryInfo, error) {
+	var response struct {
+		Archived       bool      `json:"archived"`
+		LastActivityAt time.Time `json:"last_activity_at"`
+		OpenIssues     *int      `json:"open_issues_count"`
+		ForkedFrom     *struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"forked_from_project"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &response); err != nil {
+		return nil, err
+	}
+	// open_issues_count is missing if the issue tracker is disabled
+	info := &RepositoryInfo{Archived: response.Archived, PushedAt: response.LastActivityAt, OpenIssues: response.OpenIssues}
+	if response.ForkedFrom != nil && response.ForkedFrom.PathWithNamespace != "" {
+		info.Parent = p.host + "/" + response.ForkedFrom.PathWithNamespace
+	}
+	return info, nil
+}
+
+func (p *gitlabProvider) Forks(ctx context.Context, limit int) ([]Fork, error) {
+	var response []struct {
+		PathWithNamespace string    `json:"path_with_namespace"`
+		Stars             int       `json:"star_count"`
+		Archived          bool      `json:"archived"`
+		LastActivityAt    time.Time `json:"last_activity_at"`
+	}
+	requestURL := fmt.Sprintf("%s/forks?order_by=star_count&sort=desc&per_page=%d", p.url, limit)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	forks := make([]Fork, 0, len(response))
+	for _, fork := range response {
+		forks = append(forks, Fork{Root: p.host + "/" + fork.PathWithNamespace, Stars: fork.Stars, Archived: fork.Archived, PushedAt: fork.LastActivityAt})
+	}
+	return forks, nil
+}
+
+func (p *gitlabProvider) CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return collectPages(limit, commitsPerPage, func(page int) ([]string, error) {
+		var response []struct {
+			AuthorEmail string `json:"author_email"`
+		}
+		requestURL := fmt.Sprintf("%s/repository/commits?since=%s&per_page=%d&page=%d", p.url,
+			url.QueryEscape(since.UTC().Format(time.RFC3339)), commitsPerPage, page)
+		if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+			return nil, err
+		}
+		authors := make([]string, 0, len(response))
+		for _, commit := range response {
+			authors = append(authors, strings.ToLower(commit.AuthorEmail))
+		}
+		return authors, nil
+	})
+}
+
+func (p *gitlabProvider) RecentIssues(ctx context.Context, since time.Time) ([]Issue, error) {
+	var response []struct {
+		IID    int `json:"iid"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	requestURL := fmt.Sprintf("%s/issues?created_after=%s&order_by=created_at&sort=desc&per_page=%d", p.url,
+		url.QueryEscape(since.UTC().Format(time.RFC3339)), maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(response))
+	for _, issue := range response {
+		issues = append(issues, Issue{Number: issue.IID, Author: issue.Author.Username, Created: issue.CreatedAt})
+	}
+	return issues, nil
+}
+
+func (p *gitlabProvider) Comments(ctx context.Context, number int) ([]Comment, error) {
+	var response []struct {
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		CreatedAt time.Time `json:"created_at"`
+		// System notes record events like label changes, no responses
+		System bool `json:"system"`
+	}
+	requestURL := fmt.Sprintf("%s/issues/%d/notes?sort=asc&order_by=created_at&per_page=%d", p.url, number, maxResponseSamples)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0, len(response))
+	for _, note := range response {
+		if !note.System {
+			comments = append(comments, Comment{Author: note.Author.Username, Created: note.CreatedAt})
+		}
+	}
+	return comments, nil
+}
+
+func (p *gitlabProvider) MergedPullRequests(ctx context.Context, since time.Time) (int, error) {
+	var response []struct {
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	requestURL := fmt.Sprintf("%s/merge_requests?state=merged&updated_after=%s&per_page=%d", p.url,
+		url.QueryEscape(since.UTC().Format(time.RFC3339)), pullRequestsPerPage)
+	if err := p.client.getJSON(ctx, requestURL, &response); err != nil {
+		return 0, err
+	}
+	merged := 0
+	for _, request := range response {
+		if request.MergedAt != nil && !request.MergedAt.Before(since) {
+			merged++
+		}
+	}
+	return merged, nil
+}
+
+// Releases reads the releases, which GitLab lists by release date
+func (p *gitlabProvider) Releases(ctx context.Context, limit int) ([]Release, error) {
+	var response []struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		ReleasedAt  time.Time `json:"released_at"`
+		Links       struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	if err := p.client.getJSON(ctx, fmt.Sprintf("%s/releases?per_page=%d", p.url, limit), &response); err != nil {
+		return nil, err
+	}
+	releases := make([]Release, 0, len(response))
+	for _, release := range response {
+		releases = append(releases, Release{Tag: release.TagName, Name: release.Name, Notes: release.Description, URL: release.Links.Self, Published: release.ReleasedAt})
+	}
+	return releases, nil
+}
+
+func (p *gitlabProvider) File(ctx context.Context, path string) ([]byte, error) {
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := p.client.getJSON(ctx, fmt.Sprintf("%s/repository/files/%s?ref=HEAD", p.url, url.PathEscape(path)), &response); err != nil {
+		return nil, err
+	}
+	return decodeContent(response.Content, response.Encoding)
+}
+
+// TagSigned reads the signature of the tag, unsigned tags have none
+func (p *gitlabProvider) TagSigned(ctx context.Context, tag string) (bool, error) {
+	tagURL := p.url + "/repository/tags/" + url.PathEscape(tag)
+	var ref struct {
+		Name string `json:"name"`
+	}
+	if err := p.client.getJSON(ctx, tagURL, &ref); err != nil {
+		return false, err
+	}
+	var response struct {
+		VerificationStatus string `json:"verification_status"`
+	}
+	if err := p.client.getJSON(ctx, tagURL+"/signature", &response); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return response.VerificationStatus == "verified", nil
+}
+
+func (p *gitlabProvider) DefaultBranchProtected(ctx context.Context) (bool, error) {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.client.getJSON(ctx, p.url, &project); err != nil {
+		return false, err
+	}
+	var response struct {
+		Protected bool `json:"protected"`
+	}
+	if err := p.client.getJSON(ctx, p.url+"/repository/branches/"+url.PathEscape(project.DefaultBranch), &response); err != nil {
+		return false, err
+	}
+	return response.Protected, nil
+}
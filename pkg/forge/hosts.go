@@ -0,0 +1,127 @@
+package forge
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Types of self-hosted forges
+const (
+	TypeGitHub = "github"
+	TypeGitLab = "gitlab"
+	// TypeGitea also covers Forgejo, which serves the Gitea API
+	TypeGitea = "gitea"
+)
+
+// Host maps the repositories of matching hosts to the API of a self-hosted
+// forge, e.g. git.corp.example.com to the GitLab API at
+// https://git.corp.example.com/api/v4
+type Host struct {
+	// Pattern is a host like git.corp.example.com or a glob like
+	// *.corp.example.com
+	Pattern string `mapstructure:"pattern"`
+	// Type is the forge type: github, gitlab or gitea
+	Type string `mapstructure:"type"`
+	// URL is the base URL of the API
+	URL string `mapstructure:"url"`
+	// Token authenticates at the API, the token of the forge type like
+	// GitLabToken if empty
+	Token string `mapstructure:"token"`
+}
+
+// Validate checks the pattern, the type and the URL of the host
+func (h Host) Validate() error {
+	if h.Pattern == "" {
+		return fmt.Errorf("forge host without pattern")
+	}
+	if _, err := path.Match(h.Pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q of forge host: %w", h.Pattern, err)
+	}
+	switch h.Type {
+	case TypeGitHub, TypeGitLab, TypeGitea:
+	default:
+		return fmt.Errorf("unknown type %q of forge host %s, use github, gitlab or gitea", h.Type, h.Pattern)
+	}
+	if urlHost(h.URL) == "" {
+		return fmt.Errorf("invalid API URL %q of forge host %s", h.URL, h.Pattern)
+	}
+	return nil
+}
+
+// matches reports whether the repository host matches the pattern
+func (h Host) matches(host string) bool {
+	matched, err := path.Match(h.Pattern, host)
+	return err == nil && matched
+}
+
+// token returns the token of the host, else the one of its forge type
+func (h Host) token(config Config) string {
+	if h.Token != "" {
+		return h.Token
+	}
+	switch h.Type {
+	case TypeGitHub:
+		return config.GitHubToken
+	case TypeGitLab:
+		return config.GitLabToken
+	case TypeGitea:
+		return config.GiteaToken
+	}
+	return ""
+}
+
+// apiURL returns the API URL without trailing slash
+func (h Host) apiURL() string {
+	return strings.TrimSuffix(h.URL, "/")
+}
+
+// mappedHost returns the first configured host matching the repository
+// host
+func (c *Client) mappedHost(host string) (Host, bool) {
+	if host == "" {
+		return Host{}, false
+	}
+	for _, mapped := range c.config.Hosts {
+		if mapped.matches(host) {
+			return mapped, true
+		}
+	}
+	return Host{}, false
+}
+
+// hostProvider returns the provider of a repository on a configured host.
+// Only GitLab nests projects in groups.
+func (c *Client) hostProvider(mapped Host, host, owner, name string) (ForgeProvider, bool) {
+	nested := strings.Contains(owner, "/")
+	switch {
+	case mapped.Type == TypeGitHub && !nested:
+		return newGitHubProvider(c, mapped.apiURL(), host, owner, name), true
+	case mapped.Type == TypeGitLab:
+		return newGitLabProvider(c, mapped.apiURL(), host, owner, name), true
+	case mapped.Type == TypeGitea && !nested:
+		return newGiteaProvider(c, mapped.apiURL(), host, owner, name), true
+	}
+	return nil, false
+}
+
+// authenticateHost adds the token of the configured host whose API the
+// request goes to
+func (c *Client) authenticateHost(requestURL string, setHeader func(key, value string)) bool {
+	for _, mapped := range c.config.Hosts {
+		token := mapped.token(c.config)
+		if token == "" || !hasURLPrefix(requestURL, mapped.URL) {
+			continue
+		}
+		switch mapped.Type {
+		case TypeGitHub:
+			setHeader("Authorization", "Bearer "+token)
+		case TypeGitLab:
+			setHeader("PRIVATE-TOKEN", token)
+		case TypeGitea:
+			setHeader("Authorization", "token "+token)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostValidate(t *testing.T) {
+	assert.NoError(t, Host{Pattern: "*.corp.example.com", Type: TypeGitLab, URL: "https://git.corp.example.com/api/v4"}.Validate())
+
+	for _, invalid := range []Host{
+		{Type: TypeGitLab, URL: "https://git.corp.example.com/api/v4"},
+		{Pattern: "[corp", Type: TypeGitLab, URL: "https://git.corp.example.com/api/v4"},
+		{Pattern: "git.corp.example.com", Type: "bitbucket", URL: "https://git.corp.example.com/rest/api/1.0"},
+		{Pattern: "git.corp.example.com", Type: TypeGitHub},
+	} {
+		assert.Error(t, invalid.Validate(), invalid.Pattern)
+	}
+}
+
+func TestProviderMappedHosts(t *testing.T) {
+	client := NewClient(nil, Config{
+		GitHubURL: "https://github.example.com/api/v3",
+		Hosts: []Host{
+			{Pattern: "git.corp.example.com", Type: TypeGitLab, URL: "https://git.corp.example.com/api/v4/"},
+			{Pattern: "*.ghe.example.com", Type: TypeGitHub, URL: "https://eu.ghe.example.com/api/v3"},
+			// Mapped hosts take precedence over the single self-hosted URLs
+			{Pattern: "github.example.com", Type: TypeGitea, URL: "https://github.example.com/api/v1"},
+		},
+	})
+
+	tests := []struct {
+		url      string
+		expected ForgeProvider
+	}{
+		{"https://git.corp.example.com/team/sub/lib", &gitlabProvider{client: client, host: "git.corp.example.com", url: "https://git.corp.example.com/api/v4/projects/team%2Fsub%2Flib"}},
+		{"https://eu.ghe.example.com/owner/name", &githubProvider{client: client, host: "eu.ghe.example.com", url: "https://eu.ghe.example.com/api/v3/repos/owner/name"}},
+		{"https://github.example.com/owner/name", &giteaProvider{client: client, host: "github.example.com", url: "https://github.example.com/api/v1/repos/owner/name"}},
+		{"https://github.com/owner/name", &githubProvider{client: client, host: "github.com", url: DefaultGitHubURL + "/repos/owner/name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			provider, err := client.Provider(repo.Repository{URL: tt.url})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, provider)
+		})
+	}
+
+	// Only GitLab nests projects in groups
+	_, err := client.Provider(repo.Repository{URL: "https://eu.ghe.example.com/group/sub/name"})
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestRepositoryMappedHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/gitlab/api/v4/projects/team%2Flib":
+			assert.Equal(t, "host-secret", r.Header.Get("PRIVATE-TOKEN"))
+		case "/github/api/v3/repos/owner/name":
+			assert.Equal(t, "Bearer github-secret", r.Header.Get("Authorization"))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"archived":true}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{
+		GitHubToken: "github-secret",
+		GitLabToken: "other",
+		Hosts: []Host{
+			{Pattern: "git.corp.example.com", Type: TypeGitLab, URL: server.URL + "/gitlab/api/v4", Token: "host-secret"},
+			{Pattern: "ghe.corp.example.com", Type: TypeGitHub, URL: server.URL + "/github/api/v3"},
+		},
+	})
+
+	info, err := client.Repository(context.Background(), repo.Repository{Root: "git.corp.example.com/team/lib", URL: "https://git.corp.example.com/team/lib"})
+	require.NoError(t, err)
+	assert.True(t, info.Archived)
+
+	info, err = client.Repository(context.Background(), repo.Repository{Root: "ghe.corp.example.com/owner/name", URL: "https://ghe.corp.example.com/owner/name"})
+	require.NoError(t, err)
+	assert.True(t, info.Archived)
+}
@@ -0,0 +1,112 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// reposPerPage is the page size of organization repository listings
+	reposPerPage = 100
+	// maxOrgRepositories bounds the repositories listed of an organization
+	maxOrgRepositories = 5000
+)
+
+// OrgRepository is a repository of an organization or group
+type OrgRepository struct {
+	// Name is the path of the repository below the host, like org/name or
+	// group/subgroup/name
+	Name string
+	// URL is the web URL of the repository
+	URL           string
+	DefaultBranch string
+	Archived      bool
+	// Language is the primary language detected by the forge, empty if the
+	// forge doesn't tell
+	Language string
+}
+
+// OrgRepositories lists the repositories of a GitHub organization or a
+// GitLab group including its subgroups, sorted by the forge. host is
+// github.com, gitlab.com, the host of the configured GitHub Enterprise
+// Server or self-hosted GitLab or matches a configured GitHub or GitLab
+// host. Other hosts return ErrUnsupported.
+func (c *Client) OrgRepositories(ctx context.Context, host, org string) ([]OrgRepository, error) {
+	if mapped, ok := c.mappedHost(host); ok {
+		switch mapped.Type {
+		case TypeGitHub:
+			return c.githubOrgRepositories(ctx, mapped.apiURL(), org)
+		case TypeGitLab:
+			return c.gitlabGroupRepositories(ctx, mapped.apiURL(), org)
+		}
+		return nil, fmt.Errorf("%w: organizations on %s", ErrUnsupported, host)
+	}
+	switch {
+	case host == "github.com":
+		return c.githubOrgRepositories(ctx, c.GitHubURL, org)
+	case host == "gitlab.com":
+		return c.gitlabGroupRepositories(ctx, c.GitLabURL, org)
+	case host != "" && host == urlHost(c.config.GitHubURL):
+		return c.githubOrgRepositories(ctx, strings.TrimSuffix(c.config.GitHubURL, "/"), org)
+	case host != "" && host == urlHost(c.config.GitLabURL):
+		return c.gitlabGroupRepositories(ctx, strings.TrimSuffix(c.config.GitLabURL, "/"), org)
+	}
+	return nil, fmt.Errorf("%w: organizations on %s", ErrUnsupported, host)
+}
+
+func (c *Client) githubOrgRepositories(ctx context.Context, apiURL, org string) ([]OrgRepository, error) {
+	return collectPages(maxOrgRepositories, reposPerPage, func(page int) ([]OrgRepository, error) {
+		var response []struct {
+			FullName      string `json:"full_name"`
+			HTMLURL       string `json:"html_url"`
+			DefaultBranch string `json:"default_branch"`
+			Archived      bool   `json:"archived"`
+			Language      string `json:"language"`
+		}
+		requestURL := fmt.Sprintf("%s/orgs/%s/repos?type=all&per_page=%d&page=%d", apiURL, url.PathEscape(org), reposPerPage, page)
+		if err := c.getJSON(ctx, requestURL, &response); err != nil {
+			return nil, err
+		}
+		repositories := make([]OrgRepository, 0, len(response))
+		for _, repository := range response {
+			repositories = append(repositories, OrgRepository{
+				Name:          repository.FullName,
+				URL:           repository.HTMLURL,
+				DefaultBranch: repository.DefaultBranch,
+				Archived:      repository.Archived,
+				Language:      repository.Language,
+			})
+		}
+		return repositories, nil
+	})
+}
+
+// gitlabGroupRepositories lists the projects of the group, GitLab has no
+// primary language in project listings
+func (c *Client) gitlabGroupRepositories(ctx context.Context, apiURL, group string) ([]OrgRepository, error) {
+	return collectPages(maxOrgRepositories, reposPerPage, func(page int) ([]OrgRepository, error) {
+		var response []struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			WebURL            string `json:"web_url"`
+			DefaultBranch     string `json:"default_branch"`
+			Archived          bool   `json:"archived"`
+		}
+		requestURL := fmt.Sprintf("%s/groups/%s/projects?include_subgroups=true&order_by=path&sort=asc&per_page=%d&page=%d",
+			apiURL, url.PathEscape(group), reposPerPage, page)
+		if err := c.getJSON(ctx, requestURL, &response); err != nil {
+			return nil, err
+		}
+		repositories := make([]OrgRepository, 0, len(response))
+		for _, project := range response {
+			repositories = append(repositories, OrgRepository{
+				Name:          project.PathWithNamespace,
+				URL:           project.WebURL,
+				DefaultBranch: project.DefaultBranch,
+				Archived:      project.Archived,
+			})
+		}
+		return repositories, nil
+	})
+}
@@ -0,0 +1,55 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgRepositoriesGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/github/orgs/example/repos", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`[{"full_name":"example/last","html_url":"https://github.com/example/last","default_branch":"main","archived":true}]`))
+			return
+		}
+		// A full first page makes the client ask for the next one
+		repositories := make([]string, reposPerPage)
+		for i := range repositories {
+			repositories[i] = fmt.Sprintf(`{"full_name":"example/repo%d","html_url":"https://github.com/example/repo%d","default_branch":"main","language":"Go"}`, i, i)
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(repositories, ",") + "]"))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{GitHubToken: "secret"})
+
+	repositories, err := client.OrgRepositories(context.Background(), "github.com", "example")
+	require.NoError(t, err)
+	require.Len(t, repositories, reposPerPage+1)
+	assert.Equal(t, OrgRepository{Name: "example/repo0", URL: "https://github.com/example/repo0", DefaultBranch: "main", Language: "Go"}, repositories[0])
+	assert.True(t, repositories[reposPerPage].Archived)
+}
+
+func TestOrgRepositoriesGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gitlab/groups/example%2Fplatform/projects", r.URL.EscapedPath())
+		assert.Equal(t, "true", r.URL.Query().Get("include_subgroups"))
+		_, _ = w.Write([]byte(`[{"path_with_namespace":"example/platform/api","web_url":"https://gitlab.com/example/platform/api","default_branch":"master"}]`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	repositories, err := client.OrgRepositories(context.Background(), "gitlab.com", "example/platform")
+	require.NoError(t, err)
+	assert.Equal(t, []OrgRepository{{Name: "example/platform/api", URL: "https://gitlab.com/example/platform/api", DefaultBranch: "master"}}, repositories)
+
+	_, err = client.OrgRepositories(context.Background(), "bitbucket.org", "example")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,172 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// ForgeProvider reads the metadata of one repository from the API of the
+// forge hosting it. Lookups the forge has no API for return ErrUnsupported.
+type ForgeProvider interface {
+	// Repository returns the metadata of the repository
+	Repository(ctx context.Context) (*RepositoryInfo, error)
+	// Forks returns up to limit forks, the most starred first
+	Forks(ctx context.Context, limit int) ([]Fork, error)
+	// CommitAuthors returns the authors of up to limit commits of the
+	// default branch since the given time, identified by their forge
+	// account or lowercase email address
+	CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error)
+	// RecentIssues returns up to maxResponseSamples issues opened since
+	// the given time, pull requests excluded, the newest first
+	RecentIssues(ctx context.Context, since time.Time) ([]Issue, error)
+	// Comments returns the first comments of an issue in creation order
+	Comments(ctx context.Context, number int) ([]Comment, error)
+	// MergedPullRequests counts the pull requests merged since the time
+	MergedPullRequests(ctx context.Context, since time.Time) (int, error)
+	// Releases returns up to limit releases, the newest first, drafts
+	// excluded
+	Releases(ctx context.Context, limit int) ([]Release, error)
+	// File returns the content of a file in the default branch
+	File(ctx context.Context, path string) ([]byte, error)
+	// TagSigned returns whether the tag is an annotated tag with a
+	// signature the forge verified
+	TagSigned(ctx context.Context, tag string) (bool, error)
+	// DefaultBranchProtected returns whether the default branch is
+	// protected against force pushes and unreviewed changes
+	DefaultBranchProtected(ctx context.Context) (bool, error)
+}
+
+// Issue is an issue of a repository
+type Issue struct {
+	// Number is the issue number, the iid on GitLab
+	Number  int
+	Author  string
+	Created time.Time
+}
+
+// Comment is a comment on an issue
+type Comment struct {
+	Author  string
+	Created time.Time
+}
+
+// Provider returns the provider for the forge hosting the repository,
+// detected from the host of its URL: github.com, gitlab.com, bitbucket.org,
+// git.sr.ht, the public Gitea and Forgejo hosts like codeberg.org or the
+// configured self-hosted forges. Hosts matching a configured host pattern
+// use its forge type and API. Other hosts return ErrUnsupported.
+func (c *Client) Provider(repository repo.Repository) (ForgeProvider, error) {
+	owner, name, ok := repositoryPath(repository)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, repository.URL)
+	}
+	// Only GitLab nests projects in groups
+	nested := strings.Contains(owner, "/")
+
+	host := repository.Host()
+	if mapped, ok := c.mappedHost(host); ok {
+		if provider, ok := c.hostProvider(mapped, host, owner, name); ok {
+			return provider, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, repository.URL)
+	}
+	switch {
+	case host == "github.com" && !nested:
+		return newGitHubProvider(c, c.GitHubURL, host, owner, name), nil
+	case host == "gitlab.com":
+		return newGitLabProvider(c, c.GitLabURL, host, owner, name), nil
+	case host == "bitbucket.org" && !nested:
+		return newBitbucketProvider(c, c.BitbucketURL, host, owner, name), nil
+	case host == "git.sr.ht" && !nested:
+		if c.config.SourcehutToken == "" {
+			return nil, fmt.Errorf("%w: %s, the sourcehut API requires a token", ErrUnsupported, repository.URL)
+		}
+		return newSourcehutProvider(c, c.SourcehutURL, owner, name), nil
+	case c.GiteaHosts[host] != "" && !nested:
+		return newGiteaProvider(c, c.GiteaHosts[host], host, owner, name), nil
+	case host != "" && host == urlHost(c.config.GitHubURL) && !nested:
+		return newGitHubProvider(c, strings.TrimSuffix(c.config.GitHubURL, "/"), host, owner, name), nil
+	case host != "" && host == urlHost(c.config.GitLabURL):
+		return newGitLabProvider(c, strings.TrimSuffix(c.config.GitLabURL, "/"), host, owner, name), nil
+	case host != "" && host == urlHost(c.config.GiteaURL) && !nested:
+		return newGiteaProvider(c, strings.TrimSuffix(c.config.GiteaURL, "/"), host, owner, name), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, repository.URL)
+}
+
+// APIHost returns the host of the forge API the lookups of the repository
+// go to, e.g. api.github.com. Other hosts return ErrUnsupported.
+func (c *Client) APIHost(repository repo.Repository) (string, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return "", err
+	}
+	var apiURL string
+	switch provider := provider.(type) {
+	case *githubProvider:
+		apiURL = provider.url
+	case *gitlabProvider:
+		apiURL = provider.url
+	case *bitbucketProvider:
+		apiURL = provider.url
+	case *giteaProvider:
+		apiURL = provider.url
+	case *sourcehutProvider:
+		apiURL = provider.url
+	}
+	return urlHost(apiURL), nil
+}
+
+func urlHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// repositoryPath splits the path of the repository URL, like
+// https://github.com/owner/name, into the owner and the name. The owner
+// of GitLab projects in subgroups contains slashes.
+func repositoryPath(repository repo.Repository) (string, string, bool) {
+	parsed, err := url.Parse(repository.URL)
+	if err != nil {
+		return "", "", false
+	}
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	owner, name, found := cutLast(path, "/")
+	if !found || owner == "" || name == "" {
+		return "", "", false
+	}
+	return owner, name, true
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// collectPages reads pages of up to perPage items until limit items were
+// read or a page is incomplete. Every call of fetch returns the next page.
+func collectPages[T any](limit, perPage int, fetch func(page int) ([]T, error)) ([]T, error) {
+	var items []T
+	for page := 1; len(items) < limit; page++ {
+		batch, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return items[:min(len(items), limit)], nil
+}
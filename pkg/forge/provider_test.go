@@ -0,0 +1,73 @@
+package forge
+
+import (
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	client := NewClient(nil, Config{
+		SourcehutToken: "secret",
+		GitHubURL:      "https://github.example.com/api/v3",
+		GiteaURL:       "https://git.example.org/api/v1/",
+	})
+
+	tests := []struct {
+		url      string
+		expected ForgeProvider
+	}{
+		{"https://github.com/owner/name", &githubProvider{client: client, host: "github.com", url: DefaultGitHubURL + "/repos/owner/name"}},
+		{"https://github.com/owner/name.git", &githubProvider{client: client, host: "github.com", url: DefaultGitHubURL + "/repos/owner/name"}},
+		{"https://gitlab.com/group/sub/name", &gitlabProvider{client: client, host: "gitlab.com", url: DefaultGitLabURL + "/projects/group%2Fsub%2Fname"}},
+		{"https://bitbucket.org/team/name", &bitbucketProvider{client: client, host: "bitbucket.org", url: DefaultBitbucketURL + "/repositories/team/name"}},
+		{"https://codeberg.org/owner/name", &giteaProvider{client: client, host: "codeberg.org", url: "https://codeberg.org/api/v1/repos/owner/name"}},
+		{"https://git.sr.ht/~owner/name", &sourcehutProvider{client: client, url: DefaultSourcehutURL, owner: "owner", name: "name"}},
+		{"https://github.example.com/owner/name", &githubProvider{client: client, host: "github.example.com", url: "https://github.example.com/api/v3/repos/owner/name"}},
+		{"https://git.example.org/owner/name", &giteaProvider{client: client, host: "git.example.org", url: "https://git.example.org/api/v1/repos/owner/name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			provider, err := client.Provider(repo.Repository{URL: tt.url})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, provider)
+		})
+	}
+
+	for _, unsupported := range []string{
+		"https://go.googlesource.com/mod",
+		"https://github.com/owner",
+		"https://github.com/group/sub/name",
+	} {
+		t.Run(unsupported, func(t *testing.T) {
+			_, err := client.Provider(repo.Repository{URL: unsupported})
+
+			assert.ErrorIs(t, err, ErrUnsupported)
+		})
+	}
+}
+
+func TestAPIHost(t *testing.T) {
+	client := NewClient(nil, Config{GitHubURL: "https://github.example.com/api/v3"})
+
+	host, err := client.APIHost(repo.Repository{URL: "https://github.com/owner/name"})
+	require.NoError(t, err)
+	assert.Equal(t, "api.github.com", host)
+
+	host, err = client.APIHost(repo.Repository{URL: "https://github.example.com/owner/name"})
+	require.NoError(t, err)
+	assert.Equal(t, "github.example.com", host)
+
+	_, err = client.APIHost(repo.Repository{URL: "https://go.googlesource.com/mod"})
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestProviderSourcehutRequiresToken(t *testing.T) {
+	_, err := NewClient(nil, Config{}).Provider(repo.Repository{URL: "https://git.sr.ht/~owner/name"})
+
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorContains(t, err, "requires a token")
+}
@@ -0,0 +1,52 @@
+package forge
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// Release is a release published on the forge for a tag
+type Release struct {
+	Tag  string
+	Name string
+	// Notes are the release notes in Markdown
+	Notes string
+	// URL is the web page of the release
+	URL       string
+	Published time.Time
+}
+
+// Releases returns up to limit releases of a repository, the newest first.
+// Drafts are skipped. Forges without releases return ErrUnsupported.
+func (c *Client) Releases(ctx context.Context, repository repo.Repository, limit int) ([]Release, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Releases(ctx, limit)
+}
+
+// File returns the content of a file in the default branch of a
+// repository. Forges without a contents API return ErrUnsupported.
+func (c *Client) File(ctx context.Context, repository repo.Repository, path string) ([]byte, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+	return provider.File(ctx, path)
+}
+
+// decodeContent decodes the content of a file of the contents APIs
+func decodeContent(content, encoding string) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.DecodeString(content)
+	case "", "text", "utf-8":
+		return []byte(content), nil
+	}
+	return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+}
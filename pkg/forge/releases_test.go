@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/github/repos/example/mod/releases":
+			assert.Equal(t, "10", r.URL.Query().Get("per_page"))
+			_, _ = w.Write([]byte(`[
+				{"tag_name":"v1.2.0","body":"draft","draft":true},
+				{"tag_name":"v1.1.0","name":"Feature release","body":"Adds things","html_url":"https://github.com/example/mod/releases/tag/v1.1.0","published_at":"2024-01-02T03:04:05Z"}
+			]`))
+		case "/gitlab/projects/example%2Fmod/releases":
+			_, _ = w.Write([]byte(`[{"tag_name":"v1.1.0","description":"Adds things","_links":{"self":"https://gitlab.com/example/mod/-/releases/v1.1.0"}}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	releases, err := client.Releases(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"}, 10)
+	require.NoError(t, err)
+	require.Len(t, releases, 1, "drafts are skipped")
+	assert.Equal(t, "v1.1.0", releases[0].Tag)
+	assert.Equal(t, "Adds things", releases[0].Notes)
+	assert.Equal(t, "https://github.com/example/mod/releases/tag/v1.1.0", releases[0].URL)
+	assert.False(t, releases[0].Published.IsZero())
+
+	releases, err = client.Releases(context.Background(), repo.Repository{Root: "gitlab.com/example/mod", URL: "https://gitlab.com/example/mod"}, 10)
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "https://gitlab.com/example/mod/-/releases/v1.1.0", releases[0].URL)
+
+	_, err = client.Releases(context.Background(), repo.Repository{Root: "bitbucket.org/example/mod", URL: "https://bitbucket.org/example/mod"}, 10)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/github/repos/example/mod/contents/sub/CHANGELOG.md":
+			_, _ = w.Write([]byte(`{"content":"IyMgdjEuMS4wCg==\n","encoding":"base64"}`))
+		case "/gitlab/projects/example%2Fmod/repository/files/sub%2FCHANGELOG.md":
+			assert.Equal(t, "HEAD", r.URL.Query().Get("ref"))
+			_, _ = w.Write([]byte(`{"content":"IyMgdjEuMS4wCg==","encoding":"base64"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	content, err := client.File(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"}, "sub/CHANGELOG.md")
+	require.NoError(t, err)
+	assert.Equal(t, "## v1.1.0\n", string(content))
+
+	content, err = client.File(context.Background(), repo.Repository{Root: "gitlab.com/example/mod", URL: "https://gitlab.com/example/mod"}, "sub/CHANGELOG.md")
+	require.NoError(t, err)
+	assert.Equal(t, "## v1.1.0\n", string(content))
+
+	_, err = client.File(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"}, "CHANGELOG.md")
+	assert.True(t, IsNotFound(err))
+}
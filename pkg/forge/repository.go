@@ -0,0 +1,58 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// ErrUnsupported is returned for repositories on forges without API support
+var ErrUnsupported = errors.New("unsupported forge")
+
+// RepositoryInfo is the metadata of a source repository
+type RepositoryInfo struct {
+	Archived bool
+	// PushedAt is the time of the last push, zero if unknown
+	PushedAt time.Time
+	// OpenIssues is the number of open issues, nil if the forge doesn't
+	// track issues for the repository. GitHub counts open pull requests as
+	// well.
+	OpenIssues *int
+	// Parent is the root of the repository this one was forked from, like
+	// github.com/owner/name, empty if it is no fork or the forge doesn't
+	// tell
+	Parent string
+}
+
+// Repository looks up the metadata of a repository on a supported forge.
+// Other hosts return ErrUnsupported.
+func (c *Client) Repository(ctx context.Context, repository repo.Repository) (*RepositoryInfo, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Repository(ctx)
+}
+
+// Fork is a fork of a repository
+type Fork struct {
+	// Root is the repository root like github.com/owner/name
+	Root string
+	// Stars is zero on forges without stars, e.g. Bitbucket
+	Stars    int
+	Archived bool
+	// PushedAt is the time of the last push, zero if unknown
+	PushedAt time.Time
+}
+
+// Forks returns up to limit forks of a repository on a supported forge,
+// the most starred first. Other hosts return ErrUnsupported.
+func (c *Client) Forks(ctx context.Context, repository repo.Repository, limit int) ([]Fork, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Forks(ctx, limit)
+}
@@ -0,0 +1,93 @@
+package forge
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+const (
+	// maxResponseSamples is the number of recent issues whose comments are
+	// read to measure the time to first response, each costs a request
+	maxResponseSamples = 10
+	// pullRequestsPerPage bounds the merged pull requests counted
+	pullRequestsPerPage = 100
+)
+
+// Responsiveness describes how maintainers reacted to issues and pull
+// requests within a period
+type Responsiveness struct {
+	// MedianFirstResponse is the median time until someone other than the
+	// author commented on the sampled issues opened in the period, nil if
+	// no sampled issue was answered
+	MedianFirstResponse *time.Duration
+	// Sampled is the number of sampled issues opened in the period
+	Sampled int
+	// Unanswered is the number of sampled issues without any response
+	Unanswered int
+	// MergedPullRequests is the number of pull or merge requests merged in
+	// the period, at most pullRequestsPerPage
+	MergedPullRequests int
+}
+
+// Responsiveness samples up to maxResponseSamples issues opened since the
+// given time to measure the median time to first response and counts the
+// pull requests merged since then. Other hosts, and forges without issues
+// like sourcehut, return ErrUnsupported.
+func (c *Client) Responsiveness(ctx context.Context, repository repo.Repository, since time.Time) (*Responsiveness, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	responsiveness := &Responsiveness{}
+	issues, err := provider.RecentIssues(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	responsiveness.Sampled = len(issues)
+	var responseTimes []time.Duration
+	for _, issue := range issues {
+		comments, err := provider.Comments(ctx, issue.Number)
+		if err != nil {
+			return nil, err
+		}
+		if response, ok := firstResponse(issue, comments); ok {
+			responseTimes = append(responseTimes, response)
+		} else {
+			responsiveness.Unanswered++
+		}
+	}
+	responsiveness.MedianFirstResponse = median(responseTimes)
+
+	if responsiveness.MergedPullRequests, err = provider.MergedPullRequests(ctx, since); err != nil {
+		return nil, err
+	}
+	return responsiveness, nil
+}
+
+// firstResponse returns the time from opening the issue to the first
+// comment of someone else
+func firstResponse(issue Issue, comments []Comment) (time.Duration, bool) {
+	for _, comment := range comments {
+		if comment.Author != issue.Author && !comment.Created.Before(issue.Created) {
+			return comment.Created.Sub(issue.Created), true
+		}
+	}
+	return 0, false
+}
+
+func median(durations []time.Duration) *time.Duration {
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	middle := len(durations) / 2
+	value := durations[middle]
+	if len(durations)%2 == 0 {
+		value = (durations[middle-1] + durations[middle]) / 2
+	}
+	return &value
+}
@@ -0,0 +1,93 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsivenessGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github/repos/example/mod/issues":
+			assert.Equal(t, "created", r.URL.Query().Get("sort"))
+			_, _ = w.Write([]byte(`[
+				{"number":4,"user":{"login":"alice"},"created_at":"2024-03-01T00:00:00Z"},
+				{"number":3,"user":{"login":"bob"},"created_at":"2024-02-20T00:00:00Z","pull_request":{}},
+				{"number":2,"user":{"login":"carol"},"created_at":"2024-02-10T00:00:00Z"},
+				{"number":1,"user":{"login":"dave"},"created_at":"2023-01-01T00:00:00Z"}
+			]`))
+		case "/github/repos/example/mod/issues/4/comments":
+			_, _ = w.Write([]byte(`[
+				{"user":{"login":"alice"},"created_at":"2024-03-01T01:00:00Z"},
+				{"user":{"login":"maintainer"},"created_at":"2024-03-01T06:00:00Z"}
+			]`))
+		case "/github/repos/example/mod/issues/2/comments":
+			_, _ = w.Write([]byte(`[]`))
+		case "/github/repos/example/mod/pulls":
+			assert.Equal(t, "closed", r.URL.Query().Get("state"))
+			_, _ = w.Write([]byte(`[
+				{"merged_at":"2024-03-02T00:00:00Z"},
+				{"merged_at":null},
+				{"merged_at":"2023-06-01T00:00:00Z"}
+			]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	responsiveness, err := client.Responsiveness(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"},
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.NotNil(t, responsiveness.MedianFirstResponse)
+	assert.Equal(t, 6*time.Hour, *responsiveness.MedianFirstResponse, "comments of the author are no response")
+	assert.Equal(t, 2, responsiveness.Sampled)
+	assert.Equal(t, 1, responsiveness.Unanswered)
+	assert.Equal(t, 1, responsiveness.MergedPullRequests)
+}
+
+func TestResponsivenessGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/gitlab/projects/group%2Fmod/issues":
+			assert.Equal(t, "2024-01-01T00:00:00Z", r.URL.Query().Get("created_after"))
+			_, _ = w.Write([]byte(`[{"iid":9,"author":{"username":"alice"},"created_at":"2024-03-01T00:00:00Z"}]`))
+		case "/gitlab/projects/group%2Fmod/issues/9/notes":
+			_, _ = w.Write([]byte(`[
+				{"author":{"username":"bot"},"created_at":"2024-03-01T00:01:00Z","system":true},
+				{"author":{"username":"maintainer"},"created_at":"2024-03-03T00:00:00Z","system":false}
+			]`))
+		case "/gitlab/projects/group%2Fmod/merge_requests":
+			_, _ = w.Write([]byte(`[{"merged_at":"2024-02-01T00:00:00Z"},{"merged_at":"2024-02-02T00:00:00Z"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	responsiveness, err := client.Responsiveness(context.Background(), repo.Repository{Root: "gitlab.com/group/mod", URL: "https://gitlab.com/group/mod"},
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.NotNil(t, responsiveness.MedianFirstResponse)
+	assert.Equal(t, 48*time.Hour, *responsiveness.MedianFirstResponse, "system notes are no response")
+	assert.Equal(t, 2, responsiveness.MergedPullRequests)
+}
+
+func TestMedian(t *testing.T) {
+	assert.Nil(t, median(nil))
+	assert.Equal(t, 2*time.Hour, *median([]time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour}))
+	assert.Equal(t, 90*time.Minute, *median([]time.Duration{2 * time.Hour, time.Hour}))
+}
@@ -0,0 +1,86 @@
+package forge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// securityPolicyFiles are the paths of the security policy, in the order
+// GitHub looks them up
+var securityPolicyFiles = []string{"SECURITY.md", ".github/SECURITY.md", "docs/SECURITY.md"}
+
+// SecuritySignals describe the security practices of a repository. Nil
+// signals are unknown, because the forge doesn't expose them.
+type SecuritySignals struct {
+	// SecurityPolicy is whether the repository has a SECURITY.md
+	SecurityPolicy *bool
+	// SignedTags is the number of the checked tags with a verified
+	// signature
+	SignedTags int
+	// CheckedTags is the number of the given tags found in the repository
+	CheckedTags int
+	// BranchProtected is whether the default branch is protected
+	BranchProtected *bool
+}
+
+// SecuritySignals looks up the security policy and the protection of the
+// default branch of a repository and checks the signatures of the tags.
+// Tags missing in the repository are skipped. Lookups the forge doesn't
+// support leave their signal unknown, other hosts return ErrUnsupported.
+func (c *Client) SecuritySignals(ctx context.Context, repository repo.Repository, tags []string) (*SecuritySignals, error) {
+	provider, err := c.Provider(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	signals := &SecuritySignals{}
+	policy, err := securityPolicy(ctx, provider)
+	switch {
+	case err == nil:
+		signals.SecurityPolicy = &policy
+	case !errors.Is(err, ErrUnsupported):
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		signed, err := provider.TagSigned(ctx, tag)
+		if err != nil {
+			if errors.Is(err, ErrUnsupported) {
+				break
+			}
+			if IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		signals.CheckedTags++
+		if signed {
+			signals.SignedTags++
+		}
+	}
+
+	protected, err := provider.DefaultBranchProtected(ctx)
+	switch {
+	case err == nil:
+		signals.BranchProtected = &protected
+	case !errors.Is(err, ErrUnsupported):
+		return nil, err
+	}
+	return signals, nil
+}
+
+// securityPolicy returns whether one of the securityPolicyFiles exists
+func securityPolicy(ctx context.Context, provider ForgeProvider) (bool, error) {
+	for _, path := range securityPolicyFiles {
+		_, err := provider.File(ctx, path)
+		if err == nil {
+			return true, nil
+		}
+		if !IsNotFound(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
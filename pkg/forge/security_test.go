@@ -0,0 +1,100 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySignalsGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github/repos/example/mod/contents/SECURITY.md":
+			w.WriteHeader(http.StatusNotFound)
+		case "/github/repos/example/mod/contents/.github/SECURITY.md":
+			_, _ = w.Write([]byte(`{"content":"UmVwb3J0IHRvIHNlY3VyaXR5QGV4YW1wbGUuY29t","encoding":"base64"}`))
+		case "/github/repos/example/mod/git/ref/tags/v1.2.0":
+			_, _ = w.Write([]byte(`{"object":{"type":"tag","sha":"abc"}}`))
+		case "/github/repos/example/mod/git/tags/abc":
+			_, _ = w.Write([]byte(`{"verification":{"verified":true}}`))
+		case "/github/repos/example/mod/git/ref/tags/v1.1.0":
+			_, _ = w.Write([]byte(`{"object":{"type":"commit","sha":"def"}}`))
+		case "/github/repos/example/mod/git/ref/tags/v1.0.0":
+			w.WriteHeader(http.StatusNotFound)
+		case "/github/repos/example/mod":
+			_, _ = w.Write([]byte(`{"default_branch":"main"}`))
+		case "/github/repos/example/mod/branches/main":
+			_, _ = w.Write([]byte(`{"name":"main","protected":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	signals, err := client.SecuritySignals(context.Background(), repo.Repository{Root: "github.com/example/mod", URL: "https://github.com/example/mod"},
+		[]string{"v1.2.0", "v1.1.0", "v1.0.0"})
+
+	require.NoError(t, err)
+	require.NotNil(t, signals.SecurityPolicy)
+	assert.True(t, *signals.SecurityPolicy)
+	assert.Equal(t, 1, signals.SignedTags)
+	assert.Equal(t, 2, signals.CheckedTags, "missing tags are skipped")
+	require.NotNil(t, signals.BranchProtected)
+	assert.True(t, *signals.BranchProtected)
+}
+
+func TestSecuritySignalsGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/gitlab/projects/example%2Fmod/repository/files/SECURITY.md",
+			"/gitlab/projects/example%2Fmod/repository/files/.github%2FSECURITY.md",
+			"/gitlab/projects/example%2Fmod/repository/files/docs%2FSECURITY.md":
+			w.WriteHeader(http.StatusNotFound)
+		case "/gitlab/projects/example%2Fmod/repository/tags/v1.2.0":
+			_, _ = w.Write([]byte(`{"name":"v1.2.0"}`))
+		case "/gitlab/projects/example%2Fmod/repository/tags/v1.2.0/signature":
+			w.WriteHeader(http.StatusNotFound)
+		case "/gitlab/projects/example%2Fmod":
+			_, _ = w.Write([]byte(`{"default_branch":"main"}`))
+		case "/gitlab/projects/example%2Fmod/repository/branches/main":
+			_, _ = w.Write([]byte(`{"name":"main","protected":false}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	signals, err := client.SecuritySignals(context.Background(), repo.Repository{Root: "gitlab.com/example/mod", URL: "https://gitlab.com/example/mod"},
+		[]string{"v1.2.0"})
+
+	require.NoError(t, err)
+	require.NotNil(t, signals.SecurityPolicy)
+	assert.False(t, *signals.SecurityPolicy)
+	assert.Equal(t, 0, signals.SignedTags)
+	assert.Equal(t, 1, signals.CheckedTags)
+	require.NotNil(t, signals.BranchProtected)
+	assert.False(t, *signals.BranchProtected)
+}
+
+func TestSecuritySignalsUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{})
+
+	signals, err := client.SecuritySignals(context.Background(), repo.Repository{Root: "bitbucket.org/example/mod", URL: "https://bitbucket.org/example/mod"},
+		[]string{"v1.0.0"})
+
+	require.NoError(t, err)
+	assert.Equal(t, &SecuritySignals{}, signals)
+}
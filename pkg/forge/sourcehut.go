@@ -0,0 +1,147 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sourcehutRepositoryQuery reads the last update and a page of the commit
+// log of a repository on git.sr.ht
+const sourcehutRepositoryQuery = `query($owner: String!, $name: String!, $cursor: Cursor) {
+  user(username: $owner) {
+    repository(name: $name) {
+      updated
+      log(cursor: $cursor) {
+        results { author { email time } }
+        cursor
+      }
+    }
+  }
+}`
+
+// sourcehutRepository is the repository returned for
+// sourcehutRepositoryQuery
+type sourcehutRepository struct {
+	Updated time.Time `json:"updated"`
+	Log     struct {
+		Results []struct {
+			Author struct {
+				Email string    `json:"email"`
+				Time  time.Time `json:"time"`
+			} `json:"author"`
+		} `json:"results"`
+		// Cursor points to the next page, nil on the last page
+		Cursor *string `json:"cursor"`
+	} `json:"log"`
+}
+
+// sourcehutProvider reads repositories on git.sr.ht with its GraphQL API.
+// sourcehut has no forks and manages issues and patches in separate
+// services, the repository and its commits are all it provides.
+type sourcehutProvider struct {
+	client *Client
+	url    string
+	// owner is the user name without the leading ~
+	owner string
+	name  string
+}
+
+func newSourcehutProvider(client *Client, apiURL, owner, name string) *sourcehutProvider {
+	return &sourcehutProvider{client: client, url: apiURL, owner: strings.TrimPrefix(owner, "~"), name: name}
+}
+
+// query returns the repository with the commit log page at cursor
+func (p *sourcehutProvider) query(ctx context.Context, cursor *string) (*sourcehutRepository, error) {
+	request := map[string]any{
+		"query":     sourcehutRepositoryQuery,
+		"variables": map[string]any{"owner": p.owner, "name": p.name, "cursor": cursor},
+	}
+	var response struct {
+		Data struct {
+			User *struct {
+				Repository *sourcehutRepository `json:"repository"`
+			} `json:"user"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := p.client.postJSON(ctx, p.url, request, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("query of ~%s/%s failed: %s", p.owner, p.name, response.Errors[0].Message)
+	}
+	if response.Data.User == nil || response.Data.User.Repository == nil {
+		return nil, &StatusError{URL: fmt.Sprintf("%s ~%s/%s", p.url, p.owner, p.name), StatusCode: http.StatusNotFound}
+	}
+	return response.Data.User.Repository, nil
+}
+
+// Repository reports sourcehut repositories as not archived, sourcehut
+// can't archive them. Open issues are unknown.
+func (p *sourcehutProvider) Repository(ctx context.Context) (*RepositoryInfo, error) {
+	repository, err := p.query(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryInfo{PushedAt: repository.Updated}, nil
+}
+
+func (p *sourcehutProvider) Forks(context.Context, int) ([]Fork, error) {
+	return nil, fmt.Errorf("%w: sourcehut has no forks", ErrUnsupported)
+}
+
+// CommitAuthors follows the commit log, the newest first, until it reaches
+// commits older than since
+func (p *sourcehutProvider) CommitAuthors(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	var authors []string
+	var cursor *string
+	for len(authors) < limit {
+		repository, err := p.query(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range repository.Log.Results {
+			if commit.Author.Time.Before(since) {
+				return authors, nil
+			}
+			authors = append(authors, strings.ToLower(commit.Author.Email))
+		}
+		if cursor = repository.Log.Cursor; cursor == nil {
+			break
+		}
+	}
+	return authors[:min(len(authors), limit)], nil
+}
+
+func (p *sourcehutProvider) RecentIssues(context.Context, time.Time) ([]Issue, error) {
+	return nil, fmt.Errorf("%w: sourcehut tracks issues outside of repositories", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) Comments(context.Context, int) ([]Comment, error) {
+	return nil, fmt.Errorf("%w: sourcehut tracks issues outside of repositories", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) MergedPullRequests(context.Context, time.Time) (int, error) {
+	return 0, fmt.Errorf("%w: sourcehut has no pull requests", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) Releases(context.Context, int) ([]Release, error) {
+	return nil, fmt.Errorf("%w: sourcehut has no releases", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) File(context.Context, string) ([]byte, error) {
+	return nil, fmt.Errorf("%w: sourcehut has no contents API", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) TagSigned(context.Context, string) (bool, error) {
+	return false, fmt.Errorf("%w: sourcehut doesn't verify tag signatures", ErrUnsupported)
+}
+
+func (p *sourcehutProvider) DefaultBranchProtected(context.Context) (bool, error) {
+	return false, fmt.Errorf("%w: sourcehut has no branch protection", ErrUnsupported)
+}
@@ -0,0 +1,87 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sourcehutTestRepository = repo.Repository{Root: "git.sr.ht/~owner/mod", URL: "https://git.sr.ht/~owner/mod"}
+
+// sourcehutRequest is the GraphQL request sent to the test server
+type sourcehutRequest struct {
+	Query     string `json:"query"`
+	Variables struct {
+		Owner  string  `json:"owner"`
+		Name   string  `json:"name"`
+		Cursor *string `json:"cursor"`
+	} `json:"variables"`
+}
+
+func TestContributorsSourcehut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/sourcehut/query", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var request sourcehutRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "owner", request.Variables.Owner)
+		assert.Equal(t, "mod", request.Variables.Name)
+
+		if request.Variables.Cursor == nil {
+			_, _ = w.Write([]byte(`{"data":{"user":{"repository":{"updated":"2024-03-01T00:00:00Z","log":{"results":[
+				{"author":{"email":"alice@example.com","time":"2024-03-01T00:00:00Z"}},
+				{"author":{"email":"Alice@example.com","time":"2024-02-01T00:00:00Z"}}
+			],"cursor":"next"}}}}}`))
+			return
+		}
+		assert.Equal(t, "next", *request.Variables.Cursor)
+		_, _ = w.Write([]byte(`{"data":{"user":{"repository":{"updated":"2024-03-01T00:00:00Z","log":{"results":[
+			{"author":{"email":"bob@example.com","time":"2024-01-15T00:00:00Z"}},
+			{"author":{"email":"carol@example.com","time":"2023-01-01T00:00:00Z"}}
+		],"cursor":"more"}}}}}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{SourcehutToken: "secret"})
+
+	count, err := client.Contributors(context.Background(), sourcehutTestRepository, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestRepositorySourcehut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"repository":{"updated":"2024-03-01T00:00:00Z","log":{"results":[]}}}}}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{SourcehutToken: "secret"})
+
+	info, err := client.Repository(context.Background(), sourcehutTestRepository)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), info.PushedAt)
+	assert.Nil(t, info.OpenIssues)
+
+	_, err = client.Responsiveness(context.Background(), sourcehutTestRepository, time.Now())
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestRepositorySourcehutNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"repository":null}}}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(server, Config{SourcehutToken: "secret"})
+
+	_, err := client.Repository(context.Background(), sourcehutTestRepository)
+
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,313 @@
+// Package history stores the summaries of past scans to show how the
+// dependency health of a project evolves
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steffakasid/govital/internal/dirs"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Config selects whether and where scans are recorded
+type Config struct {
+	Enabled   bool      `mapstructure:"enabled"`
+	Path      string    `mapstructure:"path"`
+	Retention Retention `mapstructure:"retention"`
+}
+
+// Retention limits the recorded scans per project, so the history of
+// long-running servers doesn't grow unbounded. Zero values don't limit. The
+// latest scan of each project is always kept.
+type Retention struct {
+	// MaxScans is the number of scans kept per project
+	MaxScans int `mapstructure:"max_scans"`
+	// MaxAgeDays is the number of days scans are kept
+	MaxAgeDays int `mapstructure:"max_age_days"`
+}
+
+// Enabled returns whether the retention limits the history
+func (r Retention) Enabled() bool {
+	return r.MaxScans > 0 || r.MaxAgeDays > 0
+}
+
+// Record is the stored summary of a single scan
+type Record struct {
+	ScannedAt time.Time `json:"scanned_at"`
+	// Project identifies the scanned project, see ProjectKey
+	Project  string          `json:"project"`
+	Revision string          `json:"revision,omitempty"`
+	Summary  scanner.Summary `json:"summary"`
+	// Dependencies is the state of every dependency, which digests compare
+	// between scans. Records written by older versions have none.
+	Dependencies []DependencyState `json:"dependencies,omitempty"`
+}
+
+// DependencyState is the recorded health of a dependency
+type DependencyState struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Inactive is set for stale dependencies which are not acknowledged
+	Inactive bool `json:"inactive,omitempty"`
+	Archived bool `json:"archived,omitempty"`
+	// Vulnerabilities are the IDs of the known vulnerabilities
+	Vulnerabilities []string `json:"vulnerabilities,omitempty"`
+}
+
+// NewRecord creates the record of a scan result
+func NewRecord(result *scanner.ScanResult, scannedAt time.Time) Record {
+	record := Record{
+		ScannedAt: scannedAt.UTC(),
+		Project:   ProjectKey(result.ProjectPath, result.Fingerprint),
+		Summary:   result.Summary,
+	}
+	if result.Fingerprint != nil {
+		record.Revision = result.Fingerprint.Revision
+	}
+
+	// Workspace modules may require the same dependency, it is kept once
+	seen := make(map[string]bool)
+	for _, dep := range result.Dependencies {
+		if seen[dep.Path] {
+			continue
+		}
+		seen[dep.Path] = true
+		state := DependencyState{
+			Path:     dep.Path,
+			Version:  dep.Version,
+			Inactive: dep.IsInactive() && !dep.IsAcknowledged,
+			Archived: dep.Archived != nil && *dep.Archived,
+		}
+		for _, vulnerability := range dep.Vulnerabilities {
+			state.Vulnerabilities = append(state.Vulnerabilities, vulnerability.ID)
+		}
+		record.Dependencies = append(record.Dependencies, state)
+	}
+	return record
+}
+
+// ProjectKey identifies a project by its module path, so the history is
+// shared by all checkouts of a project. Projects without module path, like
+// workspaces, are identified by their absolute directory.
+func ProjectKey(projectPath string, fingerprint *scanner.Fingerprint) string {
+	if fingerprint != nil && fingerprint.Module != "" {
+		return fingerprint.Module
+	}
+	if abs, err := filepath.Abs(projectPath); err == nil {
+		return abs
+	}
+	return projectPath
+}
+
+// Store appends records to a JSON lines file. It implements
+// publish.Publisher, so scans are recorded like any other publish target.
+type Store struct {
+	path      string
+	retention Retention
+	now       func() time.Time
+}
+
+// NewStore creates a store writing to the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// SetRetention prunes the store after each published scan
+func (s *Store) SetRetention(retention Retention) {
+	s.retention = retention
+}
+
+// DefaultPath is the history file in the govital directory of the user
+func DefaultPath() string {
+	return dirs.Home("history.jsonl")
+}
+
+// Publish appends the summary of the scan result and prunes the store if a
+// retention is set
+func (s *Store) Publish(ctx context.Context, result *scanner.ScanResult) error {
+	if err := s.Append(NewRecord(result, s.now())); err != nil {
+		return err
+	}
+	if !s.retention.Enabled() {
+		return nil
+	}
+	_, err := s.Prune(s.retention)
+	return err
+}
+
+// Append adds a record to the store, creating the file if necessary
+func (s *Store) Append(record Record) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history %s: %w", s.path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write history %s: %w", s.path, err)
+	}
+	return file.Close()
+}
+
+// Load returns the records of the project, oldest first. A missing store
+// has no records.
+func (s *Store) Load(project string) ([]Record, error) {
+	all, err := s.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, record := range all {
+		if record.Project == project {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ScannedAt.Before(records[j].ScannedAt)
+	})
+	return records, nil
+}
+
+// loadAll returns the records of all projects in the order of the file
+func (s *Store) loadAll() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	var records []Record
+	lines := bufio.NewScanner(file)
+	lines.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for lineNumber := 1; lines.Scan(); lineNumber++ {
+		if len(lines.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(lines.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid record in %s line %d: %w", s.path, lineNumber, err)
+		}
+		records = append(records, record)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+// PruneResult counts the records of a prune
+type PruneResult struct {
+	Kept    int `json:"kept"`
+	Removed int `json:"removed"`
+}
+
+// Prune removes the records of all projects exceeding the retention. The
+// store is only rewritten if records are removed, it is replaced atomically
+// so readers never see a partial file.
+func (s *Store) Prune(retention Retention) (PruneResult, error) {
+	records, err := s.loadAll()
+	if err != nil {
+		return PruneResult{}, err
+	}
+	kept := retain(records, retention, s.now())
+	result := PruneResult{Kept: len(kept), Removed: len(records) - len(kept)}
+	if result.Removed == 0 {
+		return result, nil
+	}
+	return result, s.rewrite(kept)
+}
+
+// retain returns the records within the retention, in their original order
+func retain(records []Record, retention Retention, now time.Time) []Record {
+	expired := make(map[int]bool)
+	for _, index := range Expired(records, retention, now) {
+		expired[index] = true
+	}
+
+	var kept []Record
+	for i, record := range records {
+		if !expired[i] {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// Expired returns the indexes of the records exceeding the retention, in
+// ascending order. The latest record of each project never expires.
+func Expired(records []Record, retention Retention, now time.Time) []int {
+	byProject := make(map[string][]int)
+	for i, record := range records {
+		byProject[record.Project] = append(byProject[record.Project], i)
+	}
+
+	cutoff := now.AddDate(0, 0, -retention.MaxAgeDays)
+	var expired []int
+	for _, indexes := range byProject {
+		// newest first, so the first indexes are kept
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return records[indexes[i]].ScannedAt.After(records[indexes[j]].ScannedAt)
+		})
+		for rank, index := range indexes {
+			tooMany := retention.MaxScans > 0 && rank >= retention.MaxScans
+			tooOld := retention.MaxAgeDays > 0 && records[index].ScannedAt.Before(cutoff)
+			if rank > 0 && (tooMany || tooOld) {
+				expired = append(expired, index)
+			}
+		}
+	}
+	sort.Ints(expired)
+	return expired
+}
+
+func (s *Store) rewrite(records []Record) error {
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to prune history %s: %w", s.path, err)
+	}
+	defer os.Remove(file.Name())
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to prune history %s: %w", s.path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to encode history record: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to prune history %s: %w", s.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to prune history %s: %w", s.path, err)
+	}
+	if err := os.Rename(file.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to prune history %s: %w", s.path, err)
+	}
+	return nil
+}
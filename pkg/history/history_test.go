@@ -0,0 +1,153 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectKey(t *testing.T) {
+	assert.Equal(t, "example.com/app", ProjectKey(".", &scanner.Fingerprint{Module: "example.com/app"}))
+
+	abs, err := filepath.Abs(".")
+	require.NoError(t, err)
+	assert.Equal(t, abs, ProjectKey(".", nil))
+	assert.Equal(t, abs, ProjectKey(".", &scanner.Fingerprint{}))
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "nested", "history.jsonl"))
+
+	records, err := store.Load("example.com/app")
+	require.NoError(t, err)
+	assert.Empty(t, records, "missing store")
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(Record{ScannedAt: day.AddDate(0, 0, 1), Project: "example.com/app", Summary: scanner.Summary{Inactive: 2}}))
+	require.NoError(t, store.Append(Record{ScannedAt: day, Project: "example.com/app", Summary: scanner.Summary{Inactive: 3}}))
+	require.NoError(t, store.Append(Record{ScannedAt: day, Project: "example.com/other"}))
+
+	store.now = func() time.Time { return day.AddDate(0, 0, 2) }
+	err = store.Publish(context.Background(), &scanner.ScanResult{
+		Fingerprint: &scanner.Fingerprint{Module: "example.com/app", Revision: "abc"},
+		Summary:     scanner.Summary{Inactive: 1},
+		Dependencies: []scanner.Dependency{
+			{Path: "example.com/stale", Version: "v1.0.0", Module: "example.com/app/a"},
+			{Path: "example.com/stale", Version: "v1.0.0", Module: "example.com/app/b"},
+			{Path: "example.com/vulnerable", Version: "v1.2.0", IsActive: true, Vulnerabilities: []vuln.Vulnerability{{ID: "GO-1"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	records, err = store.Load("example.com/app")
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, 3, records[0].Summary.Inactive, "sorted by scan time")
+	assert.Equal(t, 2, records[1].Summary.Inactive)
+	assert.Equal(t, "abc", records[2].Revision)
+	assert.Equal(t, []DependencyState{
+		{Path: "example.com/stale", Version: "v1.0.0", Inactive: true},
+		{Path: "example.com/vulnerable", Version: "v1.2.0", Vulnerabilities: []string{"GO-1"}},
+	}, records[2].Dependencies)
+}
+
+func TestStoreInvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{}\nnot json\n"), 0o644))
+
+	_, err := NewStore(path).Load("example.com/app")
+
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestWriteHistoryAndTrend(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{ScannedAt: day, Revision: "0123456789abcdef", Summary: scanner.Summary{Total: 10, Inactive: 4, Outdated: 5}},
+		{ScannedAt: day.AddDate(0, 0, 7), Summary: scanner.Summary{Total: 11, Inactive: 2, Outdated: 6}},
+		{ScannedAt: day.AddDate(0, 0, 14), Summary: scanner.Summary{Total: 11, Inactive: 1, Outdated: 3}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteHistory(&out, records))
+	assert.Contains(t, out.String(), "2024-01-01 00:00  0123456789ab  10     4")
+
+	out.Reset()
+	require.NoError(t, WriteTrend(&out, records))
+	assert.Contains(t, out.String(), "Trend of 3 scans from 2024-01-01 to 2024-01-15")
+	assert.Contains(t, out.String(), "Inactive    4 -> 1    (-3)  █▃▁")
+	assert.Contains(t, out.String(), "Vulnerable  0 -> 0    (+0)  ▁▁▁")
+
+	out.Reset()
+	require.NoError(t, WriteTrend(&out, nil))
+	assert.Equal(t, "No scans recorded\n", out.String())
+}
+
+func TestPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store := NewStore(path)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return day.AddDate(0, 0, 10) }
+
+	result, err := store.Prune(Retention{MaxScans: 1})
+	require.NoError(t, err, "missing store")
+	assert.Equal(t, PruneResult{}, result)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.Append(Record{ScannedAt: day.AddDate(0, 0, i*2), Project: "example.com/app", Summary: scanner.Summary{Total: i}}))
+	}
+	require.NoError(t, store.Append(Record{ScannedAt: day, Project: "example.com/old"}))
+
+	result, err = store.Prune(Retention{})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Kept: 6}, result, "no retention")
+
+	result, err = store.Prune(Retention{MaxScans: 4, MaxAgeDays: 7})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Kept: 4, Removed: 2}, result)
+
+	records, err := store.Load("example.com/app")
+	require.NoError(t, err)
+	require.Len(t, records, 3, "scans of the last 7 days")
+	assert.Equal(t, 2, records[0].Summary.Total)
+	records, err = store.Load("example.com/old")
+	require.NoError(t, err)
+	assert.Len(t, records, 1, "latest scan of a project is kept")
+
+	result, err = store.Prune(Retention{MaxScans: 2})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Kept: 3, Removed: 1}, result)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files left")
+}
+
+func TestPublishPrunes(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	store.SetRetention(Retention{MaxScans: 2})
+	result := &scanner.ScanResult{Fingerprint: &scanner.Fingerprint{Module: "example.com/app"}}
+	for i := 0; i < 3; i++ {
+		store.now = func() time.Time { return time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC) }
+		require.NoError(t, store.Publish(context.Background(), result))
+	}
+
+	records, err := store.Load("example.com/app")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].ScannedAt.Day())
+}
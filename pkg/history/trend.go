@@ -0,0 +1,96 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// maxSparklinePoints limits the sparklines to the most recent scans
+const maxSparklinePoints = 30
+
+// Metric is a summary counter whose trend is reported
+type Metric struct {
+	Name  string
+	value func(summary scanner.Summary) int
+}
+
+// Metrics are the counters shown by WriteTrend
+var Metrics = []Metric{
+	{Name: "Total", value: func(summary scanner.Summary) int { return summary.Total }},
+	{Name: "Inactive", value: func(summary scanner.Summary) int { return summary.Inactive }},
+	{Name: "Outdated", value: func(summary scanner.Summary) int { return summary.Outdated }},
+	{Name: "Vulnerable", value: func(summary scanner.Summary) int { return summary.Vulnerable }},
+	{Name: "Errors", value: func(summary scanner.Summary) int { return summary.Errors }},
+}
+
+// WriteHistory prints one line per recorded scan
+func WriteHistory(w io.Writer, records []Record) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "No scans recorded")
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SCANNED\tREVISION\tTOTAL\tINACTIVE\tOUTDATED\tVULNERABLE\tERRORS")
+	for _, record := range records {
+		revision := record.Revision
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		if revision == "" {
+			revision = "-"
+		}
+		summary := record.Summary
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\t%d\t%d\n", record.ScannedAt.Format("2006-01-02 15:04"), revision,
+			summary.Total, summary.Inactive, summary.Outdated, summary.Vulnerable, summary.Errors)
+	}
+	return table.Flush()
+}
+
+// WriteTrend prints the change of each metric between the first and the
+// last recorded scan with a sparkline of the recent scans
+func WriteTrend(w io.Writer, records []Record) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "No scans recorded")
+		return err
+	}
+
+	first, last := records[0], records[len(records)-1]
+	fmt.Fprintf(w, "Trend of %d scans from %s to %s\n\n", len(records),
+		first.ScannedAt.Format("2006-01-02"), last.ScannedAt.Format("2006-01-02"))
+
+	recent := records[max(len(records)-maxSparklinePoints, 0):]
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, metric := range Metrics {
+		values := make([]int, len(recent))
+		for i, record := range recent {
+			values[i] = metric.value(record.Summary)
+		}
+		from, to := metric.value(first.Summary), metric.value(last.Summary)
+		fmt.Fprintf(table, "%s\t%d -> %d\t(%+d)\t%s\n", metric.Name, from, to, to-from, sparkline(values))
+	}
+	return table.Flush()
+}
+
+// sparkline draws the values with block characters scaled between the
+// smallest and the largest value
+func sparkline(values []int) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low, high = min(low, value), max(high, value)
+	}
+
+	line := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if high > low {
+			level = (value - low) * (len(blocks) - 1) / (high - low)
+		}
+		line[i] = blocks[level]
+	}
+	return string(line)
+}
@@ -0,0 +1,97 @@
+// Package hook installs git hooks running govital before commits or pushes
+package hook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/tool"
+)
+
+// Kinds of hooks which can be installed
+const (
+	PreCommit = "pre-commit"
+	PrePush   = "pre-push"
+)
+
+// marker identifies hooks written by govital, they may be overwritten
+const marker = "# Installed by govital hook install"
+
+var scripts = map[string]string{
+	// The staged go.mod files are compared with HEAD
+	PreCommit: `#!/bin/sh
+` + marker + `
+exec govital hook run --base HEAD
+`,
+	// The pushed commit is compared with the upstream branch
+	PrePush: `#!/bin/sh
+` + marker + `
+base=$(git rev-parse --verify --quiet '@{upstream}') || base=""
+exec govital hook run --base "$base" --head HEAD
+`,
+}
+
+// Script returns the hook script of the given kind
+func Script(kind string) (string, error) {
+	script, ok := scripts[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown hook type %q, expected %s or %s", kind, PreCommit, PrePush)
+	}
+	return script, nil
+}
+
+// Install writes the hook script into the hooks directory of the git
+// repository at repoDir and returns its path. Existing hooks not written
+// by govital are only replaced with force.
+func Install(repoDir, kind string, force bool) (string, error) {
+	script, err := Script(kind)
+	if err != nil {
+		return "", err
+	}
+
+	hooksDir, err := hooksPath(repoDir)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(hooksDir, kind)
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return "", fmt.Errorf("failed to read existing hook: %w", err)
+	case !force && !bytes.Contains(existing, []byte(marker)):
+		return "", fmt.Errorf("%s already exists and was not installed by govital, use --force to replace it", path)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %w", err)
+	}
+	return path, nil
+}
+
+// hooksPath returns the hooks directory, honoring core.hooksPath and
+// worktrees
+func hooksPath(repoDir string) (string, error) {
+	cmd := tool.Command(repoDir, tool.Git, "rev-parse", "--git-path", "hooks")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	path := filepath.FromSlash(strings.TrimSpace(string(out)))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoDir, path)
+	}
+	return path, nil
+}
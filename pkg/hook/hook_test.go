@@ -0,0 +1,64 @@
+package hook
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "--quiet", dir)
+	require.NoError(t, cmd.Run())
+	return dir
+}
+
+func TestInstall(t *testing.T) {
+	dir := initRepo(t)
+
+	path, err := Install(dir, PreCommit, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks", "pre-commit"), path)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "govital hook run --base HEAD")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	// Windows has no executable bit, git runs hooks via its bundled shell
+	if runtime.GOOS != "windows" {
+		assert.NotZero(t, info.Mode()&0o100, "hook is executable")
+	}
+
+	_, err = Install(dir, PreCommit, false)
+	assert.NoError(t, err, "own hooks are replaced")
+}
+
+func TestInstallKeepsForeignHooks(t *testing.T) {
+	dir := initRepo(t)
+	path := filepath.Join(dir, ".git", "hooks", "pre-push")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nmake lint\n"), 0o755))
+
+	_, err := Install(dir, PrePush, false)
+	assert.ErrorContains(t, err, "--force")
+
+	_, err = Install(dir, PrePush, true)
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "--head HEAD")
+}
+
+func TestInstallErrors(t *testing.T) {
+	_, err := Install(t.TempDir(), PreCommit, false)
+	assert.ErrorContains(t, err, "not a git repository")
+
+	_, err = Script("post-merge")
+	assert.ErrorContains(t, err, "unknown hook type")
+}
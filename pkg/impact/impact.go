@@ -0,0 +1,268 @@
+// Package impact estimates what changes if a dependency is removed from a
+// project or replaced by another module
+package impact
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/tool"
+	"golang.org/x/mod/modfile"
+)
+
+// Graph is the module requirement graph as printed by go mod graph. Nodes
+// are module paths, all versions of a module are merged into one node.
+type Graph struct {
+	Main  string
+	edges map[string][]string
+}
+
+// ParseGraph parses the output of go mod graph. The first module of the
+// output is the main module.
+func ParseGraph(r io.Reader) (*Graph, error) {
+	graph := &Graph{edges: make(map[string][]string)}
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid module graph line %q", lines.Text())
+		}
+
+		from, _, _ := strings.Cut(fields[0], "@")
+		to, _, _ := strings.Cut(fields[1], "@")
+		if graph.Main == "" {
+			graph.Main = from
+		}
+		graph.edges[from] = append(graph.edges[from], to)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read module graph: %w", err)
+	}
+	if graph.Main == "" {
+		return nil, fmt.Errorf("module graph is empty")
+	}
+	return graph, nil
+}
+
+// LoadGraph runs go mod graph in the project directory. Since Go 1.17 the
+// go.mod of the main module lists every module of the build, so only its
+// direct requirements are kept as edges of the main module.
+func LoadGraph(ctx context.Context, dir string) (*Graph, error) {
+	out, err := runGo(ctx, dir, "mod", "graph")
+	if err != nil {
+		return nil, err
+	}
+	graph, err := ParseGraph(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	file, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+	var direct []string
+	for _, req := range file.Require {
+		if !req.Indirect {
+			direct = append(direct, req.Mod.Path)
+		}
+	}
+	graph.restrictRoots(direct)
+	return graph, nil
+}
+
+// restrictRoots drops the edges of the main module to modules which are not
+// direct requirements
+func (g *Graph) restrictRoots(direct []string) {
+	isDirect := make(map[string]bool, len(direct))
+	for _, module := range direct {
+		isDirect[module] = true
+	}
+
+	var roots []string
+	for _, module := range g.edges[g.Main] {
+		if isDirect[module] {
+			roots = append(roots, module)
+		}
+	}
+	g.edges[g.Main] = roots
+}
+
+// Removed returns the modules which are no longer reachable from the main
+// module if modulePath is dropped, without modulePath itself
+func (g *Graph) Removed(modulePath string) []string {
+	before := g.reachable("")
+	after := g.reachable(modulePath)
+
+	var removed []string
+	for module := range before {
+		if module != modulePath && !after[module] {
+			removed = append(removed, module)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// reachable returns the modules reachable from the main module, skipping
+// the excluded module and everything only it requires
+func (g *Graph) reachable(excluded string) map[string]bool {
+	seen := map[string]bool{g.Main: true}
+	queue := []string{g.Main}
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+		for _, next := range g.edges[module] {
+			if next == excluded || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	delete(seen, g.Main)
+	return seen
+}
+
+// ImportingPackages returns the packages of the project which import a
+// package of the module
+func ImportingPackages(ctx context.Context, dir, modulePath string) ([]string, error) {
+	out, err := runGo(ctx, dir, "list", "-f", `{{.ImportPath}}{{range .Imports}} {{.}}{{end}}`, "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	var importers []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, imported := range fields[1:] {
+			if imported == modulePath || strings.HasPrefix(imported, modulePath+"/") {
+				importers = append(importers, fields[0])
+				break
+			}
+		}
+	}
+	sort.Strings(importers)
+	return importers, nil
+}
+
+// Report describes the effect of removing or replacing a dependency
+type Report struct {
+	Module string `json:"module"`
+	// Replacement is the module replacing the dependency, nil for a removal
+	Replacement *scanner.Dependency `json:"replacement,omitempty"`
+	// ImportedBy are the packages of the project importing the module,
+	// they have to be migrated
+	ImportedBy []string `json:"imported_by"`
+	// Removed are the modules which are only required through the module
+	// and disappear with it
+	Removed []string `json:"removed"`
+	// ScoreBefore and ScoreAfter are the average health scores of all
+	// dependencies, 0 if no score is known
+	ScoreBefore float64 `json:"score_before"`
+	ScoreAfter  float64 `json:"score_after"`
+	ScoreDelta  float64 `json:"score_delta"`
+}
+
+// Analyze computes the impact of dropping modulePath from the scanned
+// dependencies. A replacement is counted with its own score, its
+// dependencies are not known and not included.
+func Analyze(result *scanner.ScanResult, graph *Graph, modulePath string, importedBy []string, replacement *scanner.Dependency) Report {
+	removed := graph.Removed(modulePath)
+	gone := map[string]bool{modulePath: true}
+	for _, module := range removed {
+		gone[module] = true
+	}
+
+	before := result.Dependencies
+	var after []scanner.Dependency
+	for _, dep := range result.Dependencies {
+		if !gone[dep.Path] {
+			after = append(after, dep)
+		}
+	}
+	if replacement != nil {
+		after = append(after, *replacement)
+	}
+
+	report := Report{
+		Module:      modulePath,
+		Replacement: replacement,
+		ImportedBy:  importedBy,
+		Removed:     removed,
+		ScoreBefore: averageScore(before),
+		ScoreAfter:  averageScore(after),
+	}
+	report.ScoreDelta = round(report.ScoreAfter - report.ScoreBefore)
+	return report
+}
+
+// averageScore returns the average of the known dependency scores rounded
+// to one decimal
+func averageScore(deps []scanner.Dependency) float64 {
+	var sum, count int
+	for _, dep := range deps {
+		if dep.Score != nil {
+			sum += *dep.Score
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return round(float64(sum) / float64(count))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// Write prints the report in human readable form
+func Write(w io.Writer, report Report) {
+	if report.Replacement != nil {
+		fmt.Fprintf(w, "Impact of replacing %s with %s@%s\n\n", report.Module, report.Replacement.Path, report.Replacement.Version)
+	} else {
+		fmt.Fprintf(w, "Impact of removing %s\n\n", report.Module)
+	}
+
+	fmt.Fprintf(w, "Packages importing it (%d):\n", len(report.ImportedBy))
+	for _, pkg := range report.ImportedBy {
+		fmt.Fprintf(w, "  - %s\n", pkg)
+	}
+	fmt.Fprintf(w, "\nModules dropped with it (%d):\n", len(report.Removed))
+	for _, module := range report.Removed {
+		fmt.Fprintf(w, "  - %s\n", module)
+	}
+	fmt.Fprintf(w, "\nAverage health score: %.1f -> %.1f (%+.1f)\n", report.ScoreBefore, report.ScoreAfter, report.ScoreDelta)
+}
+
+func runGo(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := tool.CommandContext(ctx, dir, tool.Go, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package impact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGraph = `example.com/app example.com/stale@v1.0.0
+example.com/app example.com/shared@v1.0.0
+example.com/app example.com/other@v1.0.0
+example.com/stale@v1.0.0 example.com/only-stale@v1.0.0
+example.com/stale@v1.0.0 example.com/shared@v1.1.0
+example.com/only-stale@v1.0.0 example.com/deep@v1.0.0
+example.com/other@v1.0.0 example.com/shared@v1.0.0
+`
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestParseGraph(t *testing.T) {
+	graph, err := ParseGraph(strings.NewReader(testGraph))
+
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/app", graph.Main)
+	assert.ElementsMatch(t, []string{"example.com/only-stale", "example.com/shared"}, graph.edges["example.com/stale"])
+}
+
+func TestParseGraphInvalid(t *testing.T) {
+	_, err := ParseGraph(strings.NewReader("a b c\n"))
+	assert.Error(t, err)
+
+	_, err = ParseGraph(strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestRemoved(t *testing.T) {
+	graph, err := ParseGraph(strings.NewReader(testGraph))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com/deep", "example.com/only-stale"}, graph.Removed("example.com/stale"))
+	assert.Empty(t, graph.Removed("example.com/shared"), "shared is still required by other")
+}
+
+func TestRemovedWithIndirectRequirements(t *testing.T) {
+	// The main module requires every module of the build like go.mod since Go 1.17
+	graph, err := ParseGraph(strings.NewReader(testGraph + "example.com/app example.com/only-stale@v1.0.0\n"))
+	require.NoError(t, err)
+	assert.Empty(t, graph.Removed("example.com/stale"), "everything is required by the main module")
+
+	graph.restrictRoots([]string{"example.com/stale", "example.com/shared", "example.com/other"})
+	assert.Equal(t, []string{"example.com/deep", "example.com/only-stale"}, graph.Removed("example.com/stale"))
+}
+
+func TestAnalyze(t *testing.T) {
+	graph, err := ParseGraph(strings.NewReader(testGraph))
+	require.NoError(t, err)
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "example.com/stale", Score: intPtr(10)},
+		{Path: "example.com/only-stale", Score: intPtr(20)},
+		{Path: "example.com/shared", Score: intPtr(90)},
+		{Path: "example.com/other", Score: intPtr(80)},
+		{Path: "example.com/deep"},
+	}}
+
+	report := Analyze(result, graph, "example.com/stale", []string{"example.com/app/cmd"}, nil)
+
+	assert.Equal(t, []string{"example.com/deep", "example.com/only-stale"}, report.Removed)
+	assert.Equal(t, 50.0, report.ScoreBefore)
+	assert.Equal(t, 85.0, report.ScoreAfter)
+	assert.Equal(t, 35.0, report.ScoreDelta)
+
+	replacement := &scanner.Dependency{Path: "example.com/fresh", Version: "v2.0.0", Score: intPtr(70)}
+	report = Analyze(result, graph, "example.com/stale", nil, replacement)
+	assert.Equal(t, 80.0, report.ScoreAfter)
+
+	var out bytes.Buffer
+	Write(&out, report)
+	assert.Contains(t, out.String(), "Impact of replacing example.com/stale with example.com/fresh@v2.0.0")
+	assert.Contains(t, out.String(), "Average health score: 50.0 -> 80.0 (+30.0)")
+}
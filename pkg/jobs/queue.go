@@ -0,0 +1,476 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// State is the lifecycle state of a scan job
+type State string
+
+const (
+	Queued    State = "queued"
+	Running   State = "running"
+	Succeeded State = "succeeded"
+	Failed    State = "failed"
+	Cancelled State = "cancelled"
+)
+
+// Done reports whether the job has finished, successfully or not
+func (s State) Done() bool {
+	return s == Succeeded || s == Failed || s == Cancelled
+}
+
+var (
+	// ErrQueueFull is returned by Submit if all workers are busy and the
+	// backlog is at capacity, so clients can retry later
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrClosed is returned by Submit after the queue was closed
+	ErrClosed = errors.New("job queue is closed")
+	// ErrNotFound is returned for unknown job IDs
+	ErrNotFound = errors.New("job not found")
+)
+
+// Defaults of the finished jobs kept in memory and in the store, see
+// SetRetention
+const (
+	DefaultMaxFinished = 100
+	DefaultRetention   = 24 * time.Hour
+)
+
+// DefaultPageSize is the number of jobs List returns
+const DefaultPageSize = 100
+
+// Request describes the scan to run, either of a project directory or of
+// a list of modules
+type Request struct {
+	ProjectPath string `json:"project_path,omitempty"`
+	// Modules are module versions like github.com/org/repo@v1.2.0 to scan
+	// instead of the requirements of a project
+	Modules []string `json:"modules,omitempty"`
+}
+
+// RunFunc runs a scan. It must return when ctx is cancelled.
+type RunFunc func(ctx context.Context, request Request) (*scanner.ScanResult, error)
+
+// Job is a snapshot of a submitted scan
+type Job struct {
+	ID       string              `json:"id"`
+	Request  Request             `json:"request"`
+	State    State               `json:"state"`
+	Error    string              `json:"error,omitempty"`
+	Result   *scanner.ScanResult `json:"result,omitempty"`
+	Created  time.Time           `json:"created"`
+	Started  time.Time           `json:"started,omitzero"`
+	Finished time.Time           `json:"finished,omitzero"`
+	// Instance names the server instance which runs the job
+	Instance string `json:"instance,omitempty"`
+}
+
+// Store persists jobs, so they survive restarts and server instances
+// sharing the store see the jobs of each other
+type Store interface {
+	// SaveJob inserts the job or replaces the job with the same ID
+	SaveJob(ctx context.Context, job Job) error
+	// Job returns the job with the ID, ErrNotFound if there is none
+	Job(ctx context.Context, id string) (Job, error)
+	// Jobs returns limit jobs, newest first, skipping the first offset.
+	// A limit of 0 returns all.
+	Jobs(ctx context.Context, offset, limit int) ([]Job, error)
+	// PruneJobs removes the finished jobs which finished before the given
+	// time and all but the keep latest finished, 0 keeps all, like the
+	// retention of the Queue. It returns how many were removed.
+	PruneJobs(ctx context.Context, keep int, finishedBefore time.Time) (int, error)
+}
+
+type job struct {
+	Job
+	// ctx is cancelled to stop the job
+	ctx    context.Context
+	cancel context.CancelFunc
+	// version counts the state changes. The store is written outside the
+	// queue mutex, saved is the latest version stored, so an older state
+	// written late can't replace a newer one.
+	version   uint64
+	saveMutex sync.Mutex
+	saved     uint64
+}
+
+// changed returns a snapshot of the job after a state change. The caller
+// holds the queue mutex.
+func (j *job) changed() (Job, uint64) {
+	j.version++
+	return j.Job, j.version
+}
+
+// Queue runs submitted scans with a bounded number of workers. Submissions
+// beyond the backlog capacity are rejected instead of piling up, so bursts
+// can't exhaust the server.
+type Queue struct {
+	run   RunFunc
+	store Store
+	// instance is stamped on the submitted jobs
+	instance string
+
+	mutex   sync.Mutex
+	jobs    map[string]*job
+	pending chan *job
+	closed  bool
+
+	// maxFinished and retention bound the finished jobs kept in memory
+	maxFinished int
+	retention   time.Duration
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	clock    func() time.Time
+	newJobID func() string
+}
+
+// NewQueue starts workers running scans with run. Up to capacity jobs wait
+// for a free worker.
+func NewQueue(workers, capacity int, run RunFunc) *Queue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		run:         run,
+		jobs:        make(map[string]*job),
+		pending:     make(chan *job, max(capacity, 0)),
+		maxFinished: DefaultMaxFinished,
+		retention:   DefaultRetention,
+		ctx:         ctx,
+		cancel:      cancel,
+		clock:       time.Now,
+		newJobID:    randomID,
+	}
+	for i := 0; i < max(workers, 1); i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// SetStore persists every state change of the jobs in store. Jobs of the
+// store which this queue doesn't run are returned by Get and List as well.
+// It must be called before jobs are submitted.
+func (q *Queue) SetStore(store Store) {
+	q.store = store
+}
+
+// SetInstance names the server instance running the submitted jobs, so
+// FailOrphaned of a restarted instance only fails its own jobs. It must be
+// called before jobs are submitted.
+func (q *Queue) SetInstance(instance string) {
+	q.instance = instance
+}
+
+// SetRetention bounds the finished jobs with their results kept in
+// memory: the oldest are dropped beyond maxFinished and once they finished
+// longer than retention ago. 0 disables the limit. Queued and running jobs
+// are always kept.
+func (q *Queue) SetRetention(maxFinished int, retention time.Duration) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.maxFinished = max(maxFinished, 0)
+	q.retention = max(retention, 0)
+	q.evict()
+}
+
+// persist saves the snapshot of the job in the store unless a later one
+// was saved already. Failures only lose the job for other instances, so
+// they are logged.
+func (q *Queue) persist(j *job, snapshot Job, version uint64) {
+	if q.store == nil {
+		return
+	}
+	j.saveMutex.Lock()
+	defer j.saveMutex.Unlock()
+	if version <= j.saved {
+		return
+	}
+	if err := q.store.SaveJob(context.Background(), snapshot); err != nil {
+		eslog.Logger.Warn("Failed to store job", slog.String("job", snapshot.ID), slog.Any("error", err))
+		return
+	}
+	j.saved = version
+}
+
+// pruneStore removes the stored jobs exceeding the retention
+func (q *Queue) pruneStore() {
+	if q.store == nil {
+		return
+	}
+	q.mutex.Lock()
+	keep, retention, now := q.maxFinished, q.retention, q.clock()
+	q.mutex.Unlock()
+	if keep == 0 && retention == 0 {
+		return
+	}
+	var before time.Time
+	if retention > 0 {
+		before = now.Add(-retention)
+	}
+	if _, err := q.store.PruneJobs(context.Background(), keep, before); err != nil {
+		eslog.Logger.Warn("Failed to prune stored jobs", slog.Any("error", err))
+	}
+}
+
+// Submit queues a scan and returns the queued job
+func (q *Queue) Submit(request Request) (Job, error) {
+	q.mutex.Lock()
+	if q.closed {
+		q.mutex.Unlock()
+		return Job{}, ErrClosed
+	}
+
+	ctx, cancel := context.WithCancel(q.ctx)
+	j := &job{
+		Job:    Job{ID: q.newJobID(), Request: request, State: Queued, Created: q.clock(), Instance: q.instance},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	select {
+	case q.pending <- j:
+	default:
+		q.mutex.Unlock()
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	q.jobs[j.ID] = j
+	snapshot, version := j.changed()
+	q.mutex.Unlock()
+
+	q.persist(j, snapshot, version)
+	return snapshot, nil
+}
+
+// Get returns the current state of a job
+func (q *Queue) Get(id string) (Job, error) {
+	q.mutex.Lock()
+	j, ok := q.jobs[id]
+	var local Job
+	if ok {
+		local = j.Job
+	}
+	q.mutex.Unlock()
+
+	if ok {
+		return local, nil
+	}
+	if q.store != nil {
+		return q.store.Job(context.Background(), id)
+	}
+	return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// List returns the newest jobs, at most DefaultPageSize
+func (q *Queue) List() []Job {
+	return q.Page(0, DefaultPageSize)
+}
+
+// Page returns limit jobs, newest first, skipping the first offset
+func (q *Queue) Page(offset, limit int) []Job {
+	offset, limit = max(offset, 0), max(limit, 1)
+	q.mutex.Lock()
+	list := make([]Job, 0, len(q.jobs))
+	local := make(map[string]bool, len(q.jobs))
+	for _, j := range q.jobs {
+		list = append(list, j.Job)
+		local[j.ID] = true
+	}
+	q.mutex.Unlock()
+
+	if q.store != nil {
+		// The jobs of the page are among the first offset+limit of both
+		stored, err := q.store.Jobs(context.Background(), 0, offset+limit)
+		if err != nil {
+			eslog.Logger.Warn("Failed to list stored jobs", slog.Any("error", err))
+		}
+		// The jobs this queue runs are more recent than their stored state
+		for _, job := range stored {
+			if !local[job.ID] {
+				list = append(list, job)
+			}
+		}
+	}
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].Created.After(list[k].Created)
+	})
+	if offset >= len(list) {
+		return []Job{}
+	}
+	return list[offset:min(offset+limit, len(list))]
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job is a
+// no-op returning its final state. Jobs of other instances can't be
+// cancelled, their stored state is returned.
+func (q *Queue) Cancel(id string) (Job, error) {
+	q.mutex.Lock()
+	j, ok := q.jobs[id]
+	if !ok {
+		q.mutex.Unlock()
+		if q.store != nil {
+			return q.store.Job(context.Background(), id)
+		}
+		return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if j.State.Done() {
+		job := j.Job
+		q.mutex.Unlock()
+		return job, nil
+	}
+
+	j.cancel()
+	if j.State != Queued {
+		// The worker stores the final state once the scan returns
+		job := j.Job
+		q.mutex.Unlock()
+		return job, nil
+	}
+	// The worker skips it when dequeued
+	q.finish(j, Cancelled, nil, context.Canceled)
+	snapshot, version := j.changed()
+	q.mutex.Unlock()
+
+	q.persist(j, snapshot, version)
+	return snapshot, nil
+}
+
+// Close rejects new jobs, cancels queued and running jobs and waits for
+// the workers to stop
+func (q *Queue) Close() {
+	q.mutex.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.pending)
+	}
+	q.mutex.Unlock()
+
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for j := range q.pending {
+		ctx := j.ctx
+
+		q.mutex.Lock()
+		if j.State != Queued || ctx.Err() != nil {
+			if j.State.Done() {
+				q.mutex.Unlock()
+				continue
+			}
+			q.finish(j, Cancelled, nil, ctx.Err())
+			snapshot, version := j.changed()
+			q.mutex.Unlock()
+			q.persist(j, snapshot, version)
+			continue
+		}
+		j.State = Running
+		j.Started = q.clock()
+		snapshot, version := j.changed()
+		q.mutex.Unlock()
+		q.persist(j, snapshot, version)
+
+		result, err := q.run(ctx, j.Request)
+
+		q.mutex.Lock()
+		switch {
+		case ctx.Err() != nil:
+			q.finish(j, Cancelled, nil, ctx.Err())
+		case err != nil:
+			q.finish(j, Failed, nil, err)
+		default:
+			q.finish(j, Succeeded, result, nil)
+		}
+		snapshot, version = j.changed()
+		q.mutex.Unlock()
+		q.persist(j, snapshot, version)
+		q.pruneStore()
+		j.cancel()
+	}
+}
+
+// finish records the final state of a job. The caller holds the mutex.
+func (q *Queue) finish(j *job, state State, result *scanner.ScanResult, err error) {
+	j.State = state
+	j.Result = result
+	j.Finished = q.clock()
+	if err != nil {
+		j.Error = err.Error()
+	}
+	q.evict()
+}
+
+// evict drops the finished jobs exceeding the retention. The caller holds
+// the mutex.
+func (q *Queue) evict() {
+	now := q.clock()
+	var finished []*job
+	for id, j := range q.jobs {
+		if !j.State.Done() {
+			continue
+		}
+		if q.retention > 0 && now.Sub(j.Finished) > q.retention {
+			delete(q.jobs, id)
+			continue
+		}
+		finished = append(finished, j)
+	}
+	if q.maxFinished == 0 || len(finished) <= q.maxFinished {
+		return
+	}
+	sort.Slice(finished, func(i, k int) bool {
+		return finished[i].Finished.After(finished[k].Finished)
+	})
+	for _, j := range finished[q.maxFinished:] {
+		delete(q.jobs, j.ID)
+	}
+}
+
+// FailOrphaned marks the jobs of the instance in the store which are still
+// queued or running but were created before the given time as failed. They
+// were left when the instance crashed or restarted and would never finish
+// otherwise. Jobs of other instances sharing the store are kept, they may
+// still run; jobs without instance are failed. It returns the number of
+// failed jobs.
+func FailOrphaned(ctx context.Context, store Store, instance string, createdBefore time.Time) (int, error) {
+	stored, err := store.Jobs(ctx, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, job := range stored {
+		if job.State.Done() || !job.Created.Before(createdBefore) || (job.Instance != "" && job.Instance != instance) {
+			continue
+		}
+		job.State = Failed
+		job.Error = "orphaned: the server running the job stopped"
+		job.Finished = createdBefore
+		if err := store.SaveJob(ctx, job); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func randomID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
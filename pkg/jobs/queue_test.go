@@ -0,0 +1,235 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitFor polls the job until it reaches the state
+func waitFor(t *testing.T, q *Queue, id string, state State) Job {
+	t.Helper()
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = q.Get(id)
+		require.NoError(t, err)
+		return job.State == state
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestQueueRunsJobs(t *testing.T) {
+	q := NewQueue(2, 10, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		if request.ProjectPath == "broken" {
+			return nil, errors.New("go.mod not found")
+		}
+		return &scanner.ScanResult{ProjectPath: request.ProjectPath}, nil
+	})
+	defer q.Close()
+
+	ok, err := q.Submit(Request{ProjectPath: "/project"})
+	require.NoError(t, err)
+	assert.Equal(t, Queued, ok.State)
+	broken, err := q.Submit(Request{ProjectPath: "broken"})
+	require.NoError(t, err)
+
+	job := waitFor(t, q, ok.ID, Succeeded)
+	require.NotNil(t, job.Result)
+	assert.Equal(t, "/project", job.Result.ProjectPath)
+	assert.False(t, job.Started.IsZero())
+	assert.False(t, job.Finished.IsZero())
+
+	job = waitFor(t, q, broken.ID, Failed)
+	assert.Equal(t, "go.mod not found", job.Error)
+	assert.Len(t, q.List(), 2)
+	assert.Len(t, q.Page(1, 5), 1)
+	assert.Empty(t, q.Page(2, 5))
+}
+
+func TestQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(1, 1, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		<-release
+		return &scanner.ScanResult{}, nil
+	})
+	defer q.Close()
+	defer close(release)
+
+	running, err := q.Submit(Request{})
+	require.NoError(t, err)
+	waitFor(t, q, running.ID, Running)
+
+	_, err = q.Submit(Request{})
+	require.NoError(t, err, "one job may wait")
+	_, err = q.Submit(Request{})
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestQueueCancel(t *testing.T) {
+	q := NewQueue(1, 5, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	defer q.Close()
+
+	running, err := q.Submit(Request{})
+	require.NoError(t, err)
+	waitFor(t, q, running.ID, Running)
+	queued, err := q.Submit(Request{})
+	require.NoError(t, err)
+
+	job, err := q.Cancel(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, Cancelled, job.State, "queued jobs are cancelled immediately")
+
+	_, err = q.Cancel(running.ID)
+	require.NoError(t, err)
+	job = waitFor(t, q, running.ID, Cancelled)
+	assert.Equal(t, "context canceled", job.Error)
+
+	_, err = q.Cancel("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQueueClose(t *testing.T) {
+	q := NewQueue(1, 5, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	running, err := q.Submit(Request{})
+	require.NoError(t, err)
+	waitFor(t, q, running.ID, Running)
+
+	q.Close()
+
+	job, err := q.Get(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, Cancelled, job.State)
+	_, err = q.Submit(Request{})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestQueueRetention(t *testing.T) {
+	q := NewQueue(1, 10, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{ProjectPath: request.ProjectPath}, nil
+	})
+	defer q.Close()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.mutex.Lock()
+	q.clock = func() time.Time { return now }
+	q.mutex.Unlock()
+	q.SetRetention(2, time.Hour)
+
+	var ids []string
+	for _, project := range []string{"/a", "/b", "/c"} {
+		job, err := q.Submit(Request{ProjectPath: project})
+		require.NoError(t, err)
+		waitFor(t, q, job.ID, Succeeded)
+		ids = append(ids, job.ID)
+		q.mutex.Lock()
+		now = now.Add(time.Minute)
+		q.mutex.Unlock()
+	}
+
+	_, err := q.Get(ids[0])
+	assert.ErrorIs(t, err, ErrNotFound, "the oldest job exceeds the maximum")
+	assert.Len(t, q.List(), 2)
+
+	q.mutex.Lock()
+	now = now.Add(2 * time.Hour)
+	q.mutex.Unlock()
+	q.SetRetention(2, time.Hour)
+	assert.Empty(t, q.List(), "expired jobs are dropped")
+}
+
+func TestQueueRetentionLongJob(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(1, 10, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		<-release
+		return &scanner.ScanResult{}, nil
+	})
+	defer q.Close()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.mutex.Lock()
+	q.clock = func() time.Time { return now }
+	q.mutex.Unlock()
+	q.SetRetention(0, time.Hour)
+
+	job, err := q.Submit(Request{ProjectPath: "/project"})
+	require.NoError(t, err)
+	waitFor(t, q, job.ID, Running)
+	q.mutex.Lock()
+	now = now.Add(2 * time.Hour)
+	q.mutex.Unlock()
+	close(release)
+	waitFor(t, q, job.ID, Succeeded)
+
+	q.SetRetention(0, time.Hour)
+	_, err = q.Get(job.ID)
+	assert.NoError(t, err, "the retention starts when the job finished")
+}
+
+// blockingStore blocks SaveJob until release is closed
+type blockingStore struct {
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) SaveJob(context.Context, Job) error {
+	select {
+	case s.saving <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return nil
+}
+
+func (s *blockingStore) Job(context.Context, string) (Job, error) {
+	return Job{}, ErrNotFound
+}
+
+func (s *blockingStore) Jobs(context.Context, int, int) ([]Job, error) {
+	return nil, nil
+}
+
+func (s *blockingStore) PruneJobs(context.Context, int, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestQueueStoresOutsideLock(t *testing.T) {
+	q := NewQueue(1, 5, func(ctx context.Context, request Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{}, nil
+	})
+	defer q.Close()
+	store := &blockingStore{saving: make(chan struct{}, 1), release: make(chan struct{})}
+	q.SetStore(store)
+	q.SetInstance("govital-0")
+
+	submitted := make(chan Job)
+	go func() {
+		job, _ := q.Submit(Request{ProjectPath: "/project"})
+		submitted <- job
+	}()
+	<-store.saving
+
+	// A slow store doesn't block the other calls
+	listed := make(chan []Job)
+	go func() { listed <- q.List() }()
+	select {
+	case list := <-listed:
+		assert.Len(t, list, 1)
+	case <-time.After(2 * time.Second):
+		t.Fatal("List blocked by the store")
+	}
+	close(store.release)
+	job := <-submitted
+	assert.Equal(t, "govital-0", job.Instance)
+	waitFor(t, q, job.ID, Succeeded)
+}
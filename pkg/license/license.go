@@ -0,0 +1,161 @@
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DefaultBaseURL is the public deps.dev API
+const DefaultBaseURL = "https://api.deps.dev"
+
+// Kind classifies licenses by how much they restrict the use of a module
+type Kind int
+
+const (
+	Unknown Kind = iota
+	Permissive
+	// Copyleft licenses require derived works to use the same license
+	Copyleft
+	// SourceAvailable licenses like BUSL or SSPL restrict commercial use
+	// and are no open source licenses
+	SourceAvailable
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Permissive:
+		return "permissive"
+	case Copyleft:
+		return "copyleft"
+	case SourceAvailable:
+		return "source-available"
+	}
+	return "unknown"
+}
+
+var (
+	permissive = []string{"MIT", "APACHE-2.0", "BSD-2-CLAUSE", "BSD-3-CLAUSE", "ISC", "0BSD", "UNLICENSE", "CC0-1.0", "ZLIB", "BSL-1.0"}
+	copyleft   = []string{"GPL", "LGPL", "AGPL", "MPL", "EPL", "CDDL"}
+	// sourceAvailable also matches the non-standard names used before the
+	// SPDX identifiers were assigned
+	sourceAvailable = []string{"BUSL", "BSL-1.1", "SSPL", "ELASTIC", "COMMONS-CLAUSE", "CONFLUENT"}
+)
+
+// Classify returns the kind of an SPDX license identifier
+func Classify(license string) Kind {
+	id := strings.ToUpper(strings.TrimSpace(license))
+	for _, prefix := range sourceAvailable {
+		if strings.HasPrefix(id, prefix) {
+			return SourceAvailable
+		}
+	}
+	for _, prefix := range copyleft {
+		if strings.HasPrefix(id, prefix) {
+			return Copyleft
+		}
+	}
+	if slices.Contains(permissive, id) {
+		return Permissive
+	}
+	return Unknown
+}
+
+// Change is a license change of a dependency between two scans
+type Change struct {
+	From []string `json:"from"`
+	To   []string `json:"to"`
+	// Restrictive is set if a new license is more restrictive than all of
+	// the previous ones, e.g. from MIT to BUSL-1.1
+	Restrictive bool `json:"restrictive"`
+}
+
+// String describes the change, e.g. "MIT -> BUSL-1.1 (more restrictive)"
+func (c *Change) String() string {
+	description := describe(c.From) + " -> " + describe(c.To)
+	if c.Restrictive {
+		description += " (more restrictive)"
+	}
+	return description
+}
+
+// Compare returns the change between the previous and the current licenses
+// of a dependency, nil if they are the same. Unknown licenses on either
+// side are no change, the lookup may just have failed.
+func Compare(previous, current []string) *Change {
+	if len(previous) == 0 || len(current) == 0 || sameLicenses(previous, current) {
+		return nil
+	}
+	return &Change{
+		From:        previous,
+		To:          current,
+		Restrictive: mostRestrictive(current) > mostRestrictive(previous),
+	}
+}
+
+func sameLicenses(a, b []string) bool {
+	normalize := func(licenses []string) []string {
+		normalized := make([]string, len(licenses))
+		for i, license := range licenses {
+			normalized[i] = strings.ToUpper(strings.TrimSpace(license))
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+func mostRestrictive(licenses []string) Kind {
+	kind := Unknown
+	for _, license := range licenses {
+		kind = max(kind, Classify(license))
+	}
+	return kind
+}
+
+func describe(licenses []string) string {
+	return strings.Join(licenses, " AND ")
+}
+
+// Client looks up the licenses of Go module versions on deps.dev
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the public deps.dev API
+func NewClient() *Client {
+	return &Client{BaseURL: DefaultBaseURL, HTTPClient: &http.Client{}}
+}
+
+// Lookup returns the SPDX license identifiers of a module version as
+// detected by deps.dev. Modules without a detected license return none.
+func (c *Client) Lookup(ctx context.Context, modulePath, version string) ([]string, error) {
+	path := fmt.Sprintf("/v3/systems/go/packages/%s/versions/%s", url.PathEscape(modulePath), url.PathEscape(version))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deps.dev request: %w", err)
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("deps.dev request for %s@%s failed: %w", modulePath, version, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("deps.dev returned status %d", response.StatusCode)
+	}
+
+	var result struct {
+		Licenses []string `json:"licenses"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode deps.dev response for %s@%s: %w", modulePath, version, err)
+	}
+	return result.Licenses, nil
+}
@@ -0,0 +1,84 @@
+package license
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, Permissive, Classify("MIT"))
+	assert.Equal(t, Permissive, Classify("apache-2.0"))
+	assert.Equal(t, Permissive, Classify("BSL-1.0"))
+	assert.Equal(t, Copyleft, Classify("GPL-3.0-only"))
+	assert.Equal(t, Copyleft, Classify("MPL-2.0"))
+	assert.Equal(t, SourceAvailable, Classify("BUSL-1.1"))
+	assert.Equal(t, SourceAvailable, Classify("SSPL-1.0"))
+	assert.Equal(t, SourceAvailable, Classify("Elastic-2.0"))
+	assert.Equal(t, Unknown, Classify("non-standard"))
+}
+
+func TestSPDXID(t *testing.T) {
+	id, ok := SPDXID("apache-2.0")
+	assert.True(t, ok)
+	assert.Equal(t, "Apache-2.0", id)
+	_, ok = SPDXID("non-standard")
+	assert.False(t, ok)
+	_, ok = SPDXID("MIT OR Apache-2.0")
+	assert.False(t, ok)
+
+	assert.True(t, IsExpression("MIT OR Apache-2.0"))
+	assert.True(t, IsExpression("(MIT AND BSD-3-Clause)"))
+	assert.True(t, IsExpression("GPL-2.0-only WITH Classpath-exception-2.0"))
+	assert.False(t, IsExpression("MIT"))
+	assert.False(t, IsExpression("Unicode-DFS-2016"))
+}
+
+func TestCompare(t *testing.T) {
+	assert.Nil(t, Compare([]string{"MIT"}, []string{"mit"}))
+	assert.Nil(t, Compare([]string{"MIT", "Apache-2.0"}, []string{"Apache-2.0", "MIT"}))
+	assert.Nil(t, Compare(nil, []string{"MIT"}), "no previous license known")
+	assert.Nil(t, Compare([]string{"MIT"}, nil), "lookup may have failed")
+
+	change := Compare([]string{"MPL-2.0"}, []string{"BUSL-1.1"})
+	require.NotNil(t, change)
+	assert.True(t, change.Restrictive)
+	assert.Equal(t, "MPL-2.0 -> BUSL-1.1 (more restrictive)", change.String())
+
+	change = Compare([]string{"GPL-3.0-only"}, []string{"MIT"})
+	require.NotNil(t, change)
+	assert.False(t, change.Restrictive)
+	assert.Equal(t, "GPL-3.0-only -> MIT", change.String())
+}
+
+func TestLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/systems/go/packages/github.com%2Fexample%2Fmod/versions/v1.0.0", r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{"versionKey":{"system":"GO"},"licenses":["Apache-2.0"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.BaseURL = server.URL
+
+	licenses, err := client.Lookup(context.Background(), "github.com/example/mod", "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Apache-2.0"}, licenses)
+}
+
+func TestLookupError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.BaseURL = server.URL
+
+	_, err := client.Lookup(context.Background(), "github.com/example/mod", "v1.0.0")
+	assert.EqualError(t, err, "deps.dev returned status 404")
+}
@@ -0,0 +1,44 @@
+package license
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// spdxList holds the identifiers of the SPDX license list, one per line
+//
+//go:embed spdx.txt
+var spdxList string
+
+// spdxIDs maps the upper case SPDX identifiers to their spelling in the list
+var spdxIDs = func() map[string]string {
+	ids := make(map[string]string)
+	for _, line := range strings.Split(spdxList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids[strings.ToUpper(line)] = line
+	}
+	return ids
+}()
+
+// SPDXID returns the identifier of the SPDX license list matching license,
+// which is compared case-insensitively. ok is false for names and
+// expressions.
+func SPDXID(license string) (id string, ok bool) {
+	id, ok = spdxIDs[strings.ToUpper(strings.TrimSpace(license))]
+	return id, ok
+}
+
+// IsExpression reports whether license is an SPDX license expression
+// combining licenses, e.g. "MIT OR Apache-2.0"
+func IsExpression(license string) bool {
+	for _, field := range strings.Fields(license) {
+		switch strings.Trim(field, "()") {
+		case "AND", "OR", "WITH":
+			return true
+		}
+	}
+	return false
+}
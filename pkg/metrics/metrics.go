@@ -0,0 +1,119 @@
+// Package metrics exposes scan results in the Prometheus text format, so
+// dependency health can be alerted on
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label is a name value pair of a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a gauge
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Gauge is a metric family whose samples can go up and down
+type Gauge struct {
+	Name    string
+	Help    string
+	Samples []Sample
+}
+
+// Snapshot is the latest scan result of a project
+type Snapshot struct {
+	Project   string
+	ScannedAt time.Time
+	Result    *scanner.ScanResult
+}
+
+// ResultGauges returns the summary and per-dependency gauges of the
+// snapshots, labeled with their project
+func ResultGauges(snapshots []Snapshot) []Gauge {
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Project < snapshots[j].Project })
+
+	summary := func(name, help string, value func(scanner.Summary) int) Gauge {
+		gauge := Gauge{Name: name, Help: help}
+		for _, snapshot := range snapshots {
+			gauge.Samples = append(gauge.Samples, Sample{
+				Labels: []Label{{"project", snapshot.Project}},
+				Value:  float64(value(snapshot.Result.Summary)),
+			})
+		}
+		return gauge
+	}
+	gauges := []Gauge{
+		summary("govital_dependencies_total", "Number of scanned dependencies.", func(s scanner.Summary) int { return s.Total }),
+		summary("govital_dependencies_inactive", "Number of dependencies without release within the stale threshold.", func(s scanner.Summary) int { return s.Inactive }),
+		summary("govital_dependencies_outdated", "Number of dependencies with a newer version available.", func(s scanner.Summary) int { return s.Outdated }),
+		summary("govital_dependencies_vulnerable", "Number of dependencies with known vulnerabilities.", func(s scanner.Summary) int { return s.Vulnerable }),
+		summary("govital_dependencies_errors", "Number of dependencies which could not be checked.", func(s scanner.Summary) int { return s.Errors }),
+	}
+
+	lastScan := Gauge{Name: "govital_last_scan_timestamp_seconds", Help: "Unix time of the latest successful scan."}
+	daysSinceActivity := Gauge{Name: "govital_dependency_days_since_activity", Help: "Days since the release of the used version of a dependency."}
+	health := Gauge{Name: "govital_dependency_score", Help: "Health score of a dependency from 0 to 100."}
+	for _, snapshot := range snapshots {
+		lastScan.Samples = append(lastScan.Samples, Sample{
+			Labels: []Label{{"project", snapshot.Project}},
+			Value:  float64(snapshot.ScannedAt.Unix()),
+		})
+		// Workspace scans list a version once per workspace module requiring it
+		seen := make(map[string]bool)
+		for _, dep := range snapshot.Result.Dependencies {
+			if dep.Error != nil || seen[dep.Path+"@"+dep.Version] {
+				continue
+			}
+			seen[dep.Path+"@"+dep.Version] = true
+			labels := []Label{{"project", snapshot.Project}, {"module", dep.Path}, {"version", dep.Version}}
+			daysSinceActivity.Samples = append(daysSinceActivity.Samples, Sample{Labels: labels, Value: float64(dep.DaysSinceLastRelease)})
+			if dep.Score != nil {
+				health.Samples = append(health.Samples, Sample{Labels: labels, Value: float64(*dep.Score)})
+			}
+		}
+	}
+	return append(gauges, lastScan, daysSinceActivity, health)
+}
+
+// WriteGauges writes the gauges in the Prometheus text format
+func WriteGauges(w io.Writer, gauges []Gauge) error {
+	for _, gauge := range gauges {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", gauge.Name, gauge.Help, gauge.Name); err != nil {
+			return err
+		}
+		for _, sample := range gauge.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", gauge.Name, formatLabels(sample.Labels), strconv.FormatFloat(sample.Value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = label.Name + `="` + labelEscaper.Replace(label.Value) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultGauges(t *testing.T) {
+	score := 42
+	snapshots := []Snapshot{{
+		Project:   "/src/app",
+		ScannedAt: time.Unix(1700000000, 0),
+		Result: &scanner.ScanResult{
+			Dependencies: []scanner.Dependency{
+				{Path: "github.com/example/mod", Version: "v1.0.0", DaysSinceLastRelease: 400, Score: &score},
+				{Path: "github.com/example/broken", Version: "v0.1.0", Error: &scanner.ScanError{Message: "not found"}},
+			},
+			Summary: scanner.Summary{Total: 2, Inactive: 1, Errors: 1},
+		},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteGauges(&out, ResultGauges(snapshots)))
+
+	assert.Contains(t, out.String(), "# TYPE govital_dependencies_total gauge\ngovital_dependencies_total{project=\"/src/app\"} 2\n")
+	assert.Contains(t, out.String(), `govital_dependencies_inactive{project="/src/app"} 1`)
+	assert.Contains(t, out.String(), `govital_last_scan_timestamp_seconds{project="/src/app"} 1.7e+09`)
+	assert.Contains(t, out.String(), `govital_dependency_days_since_activity{project="/src/app",module="github.com/example/mod",version="v1.0.0"} 400`)
+	assert.Contains(t, out.String(), `govital_dependency_score{project="/src/app",module="github.com/example/mod",version="v1.0.0"} 42`)
+	assert.NotContains(t, out.String(), "github.com/example/broken", "failed checks have no activity")
+}
+
+func TestResultGaugesWorkspace(t *testing.T) {
+	score := 42
+	dep := scanner.Dependency{Path: "github.com/example/mod", Version: "v1.0.0", DaysSinceLastRelease: 400, Score: &score}
+	a, b := dep, dep
+	a.Module = "example.com/app/a"
+	b.Module = "example.com/app/b"
+	snapshots := []Snapshot{{Project: "/src/app", Result: &scanner.ScanResult{Dependencies: []scanner.Dependency{a, b}}}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteGauges(&out, ResultGauges(snapshots)))
+	assert.Equal(t, 1, strings.Count(out.String(), `govital_dependency_days_since_activity{project="/src/app",module="github.com/example/mod",version="v1.0.0"}`))
+	assert.Equal(t, 1, strings.Count(out.String(), `govital_dependency_score{project="/src/app",module="github.com/example/mod",version="v1.0.0"}`))
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, "", formatLabels(nil))
+	assert.Equal(t, `{path="C:\\src\\\"app\"\n"}`, formatLabels([]Label{{"path", "C:\\src\\\"app\"\n"}}))
+}
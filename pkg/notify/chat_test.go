@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chatServer records the JSON payloads posted to it
+func chatServer(t *testing.T, payloads *[]map[string]any) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		*payloads = append(*payloads, payload)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSlackBlocks(t *testing.T) {
+	event := testEvent()
+	event.Findings = append(event.Findings, Finding{Module: "example.com/<vuln>", Version: "v0.1.0", Kind: BecameVulnerable,
+		Severity: SeverityCritical, Owners: []string{"@payments"}, Detail: "GO-4"})
+
+	payload := slackEventPayload(event)
+
+	blocks := payload["blocks"].([]map[string]any)
+	require.Len(t, blocks, 5)
+	assert.Equal(t, "header", blocks[0]["type"])
+	assert.Equal(t, Subject(event), blocks[0]["text"].(map[string]any)["text"])
+	assert.Equal(t, ":warning: *example.com/stale*@v1.0.0 became *inactive*\nlast release 200 days ago", blocks[3]["text"].(map[string]any)["text"])
+	assert.Equal(t, ":rotating_light: *example.com/&lt;vuln&gt;*@v0.1.0 became *vulnerable*\nGO-4\nOwners: @payments", blocks[4]["text"].(map[string]any)["text"])
+	assert.Equal(t, Text(event), payload["text"], "the text is the fallback")
+
+	event.Findings = make([]Finding, maxSlackFindings+5)
+	blocks = slackEventPayload(event)["blocks"].([]map[string]any)
+	assert.Len(t, blocks, 3+maxSlackFindings+1)
+	assert.Equal(t, "_and 5 more findings_", blocks[len(blocks)-1]["text"].(map[string]any)["text"])
+}
+
+func TestTeams(t *testing.T) {
+	var payloads []map[string]any
+	server := chatServer(t, &payloads)
+
+	notifiers := New(ChannelConfig{Teams: TeamsConfig{WebhookURL: server.URL}})
+	require.Len(t, notifiers, 1)
+	require.NoError(t, notifiers[0].Notify(context.Background(), testEvent()))
+	require.NoError(t, Send(context.Background(), ChannelConfig{Teams: TeamsConfig{WebhookURL: server.URL}}, Message{Subject: "Digest", Text: "3 scans"}))
+
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "message", payloads[0]["type"])
+	attachment := payloads[0]["attachments"].([]any)[0].(map[string]any)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]any)
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	body := card["body"].([]any)
+	require.Len(t, body, 3)
+	assert.Equal(t, Subject(testEvent()), body[0].(map[string]any)["text"])
+	facts := body[2].(map[string]any)["facts"].([]any)
+	assert.Equal(t, map[string]any{"title": "example.com/stale@v1.0.0", "value": "became inactive, last release 200 days ago"}, facts[0])
+
+	digest := payloads[1]["attachments"].([]any)[0].(map[string]any)["content"].(map[string]any)["body"].([]any)
+	assert.Equal(t, "3 scans", digest[1].(map[string]any)["text"])
+}
+
+func TestSelect(t *testing.T) {
+	config := Config{
+		ChannelConfig: ChannelConfig{Slack: SlackConfig{WebhookURL: "https://slack"}, Email: EmailConfig{Host: "smtp", To: []string{"team@example.com"}}},
+		Channels:      map[string]ChannelConfig{"pager": {Webhook: WebhookConfig{URL: "https://pager"}, Teams: TeamsConfig{WebhookURL: "https://teams"}}},
+	}
+
+	selected, err := config.Select([]string{"slack", "Teams"})
+	require.NoError(t, err)
+	assert.Equal(t, ChannelConfig{Slack: SlackConfig{WebhookURL: "https://slack"}}, selected.ChannelConfig)
+	assert.Equal(t, ChannelConfig{Teams: TeamsConfig{WebhookURL: "https://teams"}}, selected.Channels["pager"])
+
+	_, err = config.Select([]string{"discord"})
+	assert.ErrorContains(t, err, `unknown notify target "discord"`)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// EmailConfig configures the SMTP server and the recipients
+type EmailConfig struct {
+	Host string `mapstructure:"host"`
+	// Port defaults to 587
+	Port int `mapstructure:"port"`
+	// Username and Password enable PLAIN authentication
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+// Email sends the event as plain text mail
+type Email struct {
+	config   EmailConfig
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail creates a notifier sending mails via the configured server
+func NewEmail(config EmailConfig) *Email {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.From == "" {
+		config.From = "govital@localhost"
+	}
+	return &Email{config: config, sendMail: smtp.SendMail}
+}
+
+// Notify sends the event as mail
+func (e *Email) Notify(ctx context.Context, event Event) error {
+	return e.Send(ctx, Message{Subject: Subject(event), Text: Text(event), Payload: event})
+}
+
+// Send mails the text of the message. smtp.SendMail uses STARTTLS if the
+// server supports it.
+func (e *Email) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+
+	mail := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		e.config.From, strings.Join(e.config.To, ", "), message.Subject, strings.ReplaceAll(message.Text, "\n", "\r\n"))
+	addr := net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port))
+	if err := e.sendMail(addr, auth, e.config.From, e.config.To, []byte(mail)); err != nil {
+		return fmt.Errorf("failed to send notification mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Package notify detects dependency health regressions between two scans
+// of a project and sends notifications about them
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Kinds of findings which trigger a notification
+const (
+	BecameInactive   = "inactive"
+	BecameArchived   = "archived"
+	BecameVulnerable = "vulnerable"
+)
+
+// Severities of findings, used to route them to different channels
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Finding is a dependency which regressed since the previous scan
+type Finding struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// Severity is critical for new high or critical vulnerabilities,
+	// warning otherwise
+	Severity string `json:"severity"`
+	// Owners are the teams owning the dependency
+	Owners []string `json:"owners,omitempty"`
+	// Detail describes the finding, e.g. the new vulnerability IDs
+	Detail string `json:"detail,omitempty"`
+}
+
+// Event holds the new findings of a scan of a project
+type Event struct {
+	Project   string    `json:"project"`
+	ScannedAt time.Time `json:"scanned_at"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Notifier sends events to a channel like a chat or mail
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Message is a notification other than an event, like a digest. Chats and
+// mails get the text, webhooks the payload as JSON.
+type Message struct {
+	Subject string
+	Text    string
+	Payload any
+}
+
+// Sender sends messages, it is implemented by all notifiers of New
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Send sends the message to all targets of the channel. All targets are
+// tried even if one fails.
+func Send(ctx context.Context, config ChannelConfig, message Message) error {
+	var errs []error
+	for _, notifier := range New(config) {
+		if sender, ok := notifier.(Sender); ok {
+			if err := sender.Send(ctx, message); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ChannelConfig selects the targets of a channel, unconfigured ones are
+// disabled
+type ChannelConfig struct {
+	Webhook WebhookConfig `mapstructure:"webhook"`
+	Slack   SlackConfig   `mapstructure:"slack"`
+	Teams   TeamsConfig   `mapstructure:"teams"`
+	Email   EmailConfig   `mapstructure:"email"`
+}
+
+// Targets are the names of the kinds of targets of a channel
+var Targets = []string{"webhook", "slack", "teams", "email"}
+
+// Select keeps only the named kinds of targets of the channel, e.g. slack
+func (c ChannelConfig) Select(targets []string) (ChannelConfig, error) {
+	var selected ChannelConfig
+	for _, target := range targets {
+		switch strings.ToLower(target) {
+		case "webhook":
+			selected.Webhook = c.Webhook
+		case "slack":
+			selected.Slack = c.Slack
+		case "teams":
+			selected.Teams = c.Teams
+		case "email":
+			selected.Email = c.Email
+		default:
+			return ChannelConfig{}, fmt.Errorf("unknown notify target %q, expected one of %s", target, strings.Join(Targets, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// Config selects the notification channels. The targets at the top level
+// are the default channel, which receives all findings no rule routes
+// elsewhere.
+type Config struct {
+	ChannelConfig `mapstructure:",squash"`
+	// Channels are additional named channels rules route findings to
+	Channels map[string]ChannelConfig `mapstructure:"channels"`
+	Rules    []Rule                   `mapstructure:"rules"`
+}
+
+// Select keeps only the named kinds of targets of the default channel and
+// all named channels, see ChannelConfig.Select
+func (c Config) Select(targets []string) (Config, error) {
+	selected := Config{Rules: c.Rules, Channels: make(map[string]ChannelConfig, len(c.Channels))}
+	var err error
+	if selected.ChannelConfig, err = c.ChannelConfig.Select(targets); err != nil {
+		return Config{}, err
+	}
+	for name, channel := range c.Channels {
+		if selected.Channels[name], err = channel.Select(targets); err != nil {
+			return Config{}, err
+		}
+	}
+	return selected, nil
+}
+
+// New creates the notifiers of all configured targets of the channel
+func New(config ChannelConfig) []Notifier {
+	var notifiers []Notifier
+	if config.Webhook.URL != "" {
+		notifiers = append(notifiers, NewWebhook(config.Webhook))
+	}
+	if config.Slack.WebhookURL != "" {
+		notifiers = append(notifiers, NewSlack(config.Slack))
+	}
+	if config.Teams.WebhookURL != "" {
+		notifiers = append(notifiers, NewTeams(config.Teams))
+	}
+	if config.Email.Host != "" && len(config.Email.To) > 0 {
+		notifiers = append(notifiers, NewEmail(config.Email))
+	}
+	return notifiers
+}
+
+// Changes returns the dependencies which became inactive, archived or
+// vulnerable since the previous scan. Dependencies are matched by module
+// path, so an upgrade to an inactive version is reported as well.
+// Dependencies added since the previous scan are reported if they have a
+// finding.
+func Changes(previous, current *scanner.ScanResult) []Finding {
+	before := make(map[string]scanner.Dependency, len(previous.Dependencies))
+	for _, dep := range previous.Dependencies {
+		before[dep.Path] = dep
+	}
+
+	var findings []Finding
+	for _, dep := range current.Dependencies {
+		old, known := before[dep.Path]
+		add := func(kind, severity, detail string) {
+			findings = append(findings, Finding{
+				Module:   dep.Path,
+				Version:  dep.Version,
+				Kind:     kind,
+				Severity: severity,
+				Owners:   dep.Owners,
+				Detail:   detail,
+			})
+		}
+
+		if dep.IsInactive() && !dep.IsAcknowledged && (!known || !old.IsInactive()) {
+			add(BecameInactive, SeverityWarning, fmt.Sprintf("last release %d days ago", dep.DaysSinceLastRelease))
+		}
+		if isArchived(dep) && (!known || !isArchived(old)) {
+			add(BecameArchived, SeverityWarning, "the source repository was archived")
+		}
+
+		seen := make(map[string]bool)
+		for _, vulnerability := range old.Vulnerabilities {
+			seen[vulnerability.ID] = true
+		}
+		var newIDs []string
+		severity := SeverityWarning
+		for _, vulnerability := range dep.Vulnerabilities {
+			if !seen[vulnerability.ID] {
+				newIDs = append(newIDs, vulnerability.ID)
+				if vulnerability.Severity == "CRITICAL" || vulnerability.Severity == "HIGH" {
+					severity = SeverityCritical
+				}
+			}
+		}
+		if len(newIDs) > 0 {
+			add(BecameVulnerable, severity, strings.Join(newIDs, ", "))
+		}
+	}
+	return findings
+}
+
+func isArchived(dep scanner.Dependency) bool {
+	return dep.Archived != nil && *dep.Archived
+}
+
+// Subject is a one line summary of the event
+func Subject(event Event) string {
+	return fmt.Sprintf("govital: %d new dependency findings in %s", len(event.Findings), event.Project)
+}
+
+// Text renders the event as plain text with one line per finding
+func Text(event Event) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s (scanned %s)\n", Subject(event), event.ScannedAt.UTC().Format("2006-01-02 15:04 MST"))
+	for _, finding := range event.Findings {
+		fmt.Fprintf(&text, "- %s@%s became %s", finding.Module, finding.Version, finding.Kind)
+		if finding.Detail != "" {
+			fmt.Fprintf(&text, ": %s", finding.Detail)
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestChanges(t *testing.T) {
+	previous := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "example.com/stale", Version: "v1.0.0", IsActive: true},
+		{Path: "example.com/still-stale", Version: "v1.0.0"},
+		{Path: "example.com/vulnerable", Version: "v1.0.0", IsActive: true, Vulnerabilities: []vuln.Vulnerability{{ID: "GO-1"}}},
+		{Path: "example.com/archived", Version: "v1.0.0", IsActive: true, Archived: boolPtr(false)},
+	}}
+	current := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "example.com/stale", Version: "v1.0.0", DaysSinceLastRelease: 200},
+		{Path: "example.com/still-stale", Version: "v1.0.0"},
+		{Path: "example.com/vulnerable", Version: "v1.0.0", IsActive: true, Vulnerabilities: []vuln.Vulnerability{{ID: "GO-1"}, {ID: "GO-2"}}},
+		{Path: "example.com/archived", Version: "v1.0.0", IsActive: true, Archived: boolPtr(true)},
+		{Path: "example.com/acknowledged", Version: "v1.0.0", IsAcknowledged: true},
+		{Path: "example.com/new", Version: "v0.1.0", IsActive: true, Owners: []string{"@payments"},
+			Vulnerabilities: []vuln.Vulnerability{{ID: "GO-3", Severity: "LOW"}, {ID: "GO-4", Severity: "CRITICAL"}}},
+	}}
+
+	findings := Changes(previous, current)
+
+	assert.Equal(t, []Finding{
+		{Module: "example.com/stale", Version: "v1.0.0", Kind: BecameInactive, Severity: SeverityWarning, Detail: "last release 200 days ago"},
+		{Module: "example.com/vulnerable", Version: "v1.0.0", Kind: BecameVulnerable, Severity: SeverityWarning, Detail: "GO-2"},
+		{Module: "example.com/archived", Version: "v1.0.0", Kind: BecameArchived, Severity: SeverityWarning, Detail: "the source repository was archived"},
+		{Module: "example.com/new", Version: "v0.1.0", Kind: BecameVulnerable, Severity: SeverityCritical, Owners: []string{"@payments"}, Detail: "GO-3, GO-4"},
+	}, findings)
+	assert.Empty(t, Changes(current, current))
+}
+
+func testEvent() Event {
+	return Event{
+		Project:   "example.com/app",
+		ScannedAt: time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC),
+		Findings:  []Finding{{Module: "example.com/stale", Version: "v1.0.0", Kind: BecameInactive, Detail: "last release 200 days ago"}},
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "govital: 1 new dependency findings in example.com/app (scanned 2024-01-02 03:04 UTC)\n"+
+		"- example.com/stale@v1.0.0 became inactive: last release 200 days ago\n", Text(testEvent()))
+}
+
+func TestWebhookAndSlack(t *testing.T) {
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	notifiers := New(ChannelConfig{Webhook: WebhookConfig{URL: server.URL}, Slack: SlackConfig{WebhookURL: server.URL}})
+	require.Len(t, notifiers, 2)
+	for _, notifier := range notifiers {
+		require.NoError(t, notifier.Notify(context.Background(), testEvent()))
+	}
+
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "example.com/app", payloads[0]["project"])
+	assert.Contains(t, payloads[1]["text"], "example.com/stale@v1.0.0 became inactive")
+	assert.NotEmpty(t, payloads[1]["blocks"], "Slack gets a Block Kit message")
+}
+
+func TestWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(WebhookConfig{URL: server.URL}).Notify(context.Background(), testEvent())
+
+	assert.ErrorContains(t, err, "status 403: invalid token")
+}
+
+func TestEmail(t *testing.T) {
+	email := NewEmail(EmailConfig{Host: "smtp.example.com", Username: "bot", Password: "secret", To: []string{"team@example.com"}})
+	var sentTo []string
+	var message string
+	email.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, auth)
+		sentTo, message = to, string(msg)
+		return nil
+	}
+
+	require.NoError(t, email.Notify(context.Background(), testEvent()))
+
+	assert.Equal(t, []string{"team@example.com"}, sentTo)
+	assert.Contains(t, message, "Subject: govital: 1 new dependency findings in example.com/app\r\n")
+	assert.Contains(t, message, "- example.com/stale@v1.0.0 became inactive")
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/owners"
+)
+
+// Rule routes the findings matching all of its criteria to channels. A
+// criterion which is not set matches all findings, a list matches if any
+// of its entries does.
+type Rule struct {
+	// Severity is the minimum severity, warning or critical
+	Severity string `mapstructure:"severity"`
+	// Kinds are finding kinds like inactive, archived or vulnerable
+	Kinds []string `mapstructure:"kinds"`
+	// Owners are teams owning the dependency, as assigned by owner rules
+	Owners []string `mapstructure:"owners"`
+	// Modules are module patterns like in owner rules, e.g.
+	// github.com/aws/...
+	Modules []string `mapstructure:"modules"`
+	// Channels are the names of the channels receiving the findings
+	Channels []string `mapstructure:"channels"`
+}
+
+func (r Rule) matches(finding Finding) bool {
+	if r.Severity == SeverityCritical && finding.Severity != SeverityCritical {
+		return false
+	}
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, finding.Kind) {
+		return false
+	}
+	if len(r.Owners) > 0 && !slices.ContainsFunc(r.Owners, func(owner string) bool {
+		return slices.Contains(finding.Owners, owner)
+	}) {
+		return false
+	}
+	if len(r.Modules) > 0 && !slices.ContainsFunc(r.Modules, func(pattern string) bool {
+		return owners.Match(pattern, finding.Module)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Router sends each finding to the channels of all matching rules, or to
+// the default channel if no rule matches
+type Router struct {
+	rules []Rule
+	// channels maps the lower case channel names to their notifiers, the
+	// default channel has the empty name
+	channels map[string][]Notifier
+}
+
+// NewRouter validates the rules and creates the notifiers of all channels.
+// Channel names are case insensitive.
+func NewRouter(config Config) (*Router, error) {
+	router := &Router{
+		rules:    config.Rules,
+		channels: map[string][]Notifier{"": New(config.ChannelConfig)},
+	}
+	for name, channel := range config.Channels {
+		router.channels[strings.ToLower(name)] = New(channel)
+	}
+
+	for i, rule := range config.Rules {
+		if rule.Severity != "" && rule.Severity != SeverityWarning && rule.Severity != SeverityCritical {
+			return nil, fmt.Errorf("notify rule %d: invalid severity %q, expected warning or critical", i+1, rule.Severity)
+		}
+		for _, pattern := range rule.Modules {
+			if err := owners.ValidatePattern(pattern); err != nil {
+				return nil, fmt.Errorf("notify rule %d: invalid module pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		if len(rule.Channels) == 0 {
+			return nil, fmt.Errorf("notify rule %d has no channels", i+1)
+		}
+		for j, name := range rule.Channels {
+			name = strings.ToLower(name)
+			if _, ok := router.channels[name]; !ok || name == "" {
+				return nil, fmt.Errorf("notify rule %d: unknown channel %q", i+1, rule.Channels[j])
+			}
+			rule.Channels[j] = name
+		}
+	}
+	return router, nil
+}
+
+// Enabled returns whether any channel has a target
+func (r *Router) Enabled() bool {
+	for _, notifiers := range r.channels {
+		if len(notifiers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Route returns the findings of the event per channel name, in the order of
+// the event. The default channel has the empty name.
+func (r *Router) Route(event Event) map[string][]Finding {
+	routed := make(map[string][]Finding)
+	for _, finding := range event.Findings {
+		channels := make(map[string]bool)
+		for _, rule := range r.rules {
+			if !rule.matches(finding) {
+				continue
+			}
+			for _, name := range rule.Channels {
+				channels[name] = true
+			}
+		}
+		if len(channels) == 0 {
+			channels[""] = true
+		}
+		for name := range channels {
+			routed[name] = append(routed[name], finding)
+		}
+	}
+	return routed
+}
+
+// Notify sends every channel an event with the findings routed to it. All
+// channels are notified even if one fails.
+func (r *Router) Notify(ctx context.Context, event Event) error {
+	var errs []error
+	for name, findings := range r.Route(event) {
+		routed := event
+		routed.Findings = findings
+		for _, notifier := range r.channels[name] {
+			if err := notifier.Notify(ctx, routed); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	events []Event
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event Event) error {
+	n.events = append(n.events, event)
+	return n.err
+}
+
+func TestRouter(t *testing.T) {
+	router, err := NewRouter(Config{
+		Channels: map[string]ChannelConfig{"pager": {}, "payments": {}},
+		Rules: []Rule{
+			{Severity: SeverityCritical, Channels: []string{"Pager"}},
+			{Owners: []string{"@payments"}, Channels: []string{"payments"}},
+			{Modules: []string{"github.com/stripe/..."}, Kinds: []string{BecameInactive}, Channels: []string{"payments"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, router.Enabled(), "no channel has a target")
+
+	defaults, pager, payments := &recordingNotifier{}, &recordingNotifier{}, &recordingNotifier{err: errors.New("mail server down")}
+	router.channels[""] = []Notifier{defaults}
+	router.channels["pager"] = []Notifier{pager}
+	router.channels["payments"] = []Notifier{payments}
+	assert.True(t, router.Enabled())
+
+	critical := Finding{Module: "example.com/crypto", Kind: BecameVulnerable, Severity: SeverityCritical, Owners: []string{"@payments"}}
+	owned := Finding{Module: "example.com/ledger", Kind: BecameArchived, Severity: SeverityWarning, Owners: []string{"@security", "@payments"}}
+	stripe := Finding{Module: "github.com/stripe/stripe-go", Kind: BecameInactive, Severity: SeverityWarning}
+	stripeVulnerable := Finding{Module: "github.com/stripe/stripe-go", Kind: BecameVulnerable, Severity: SeverityWarning}
+	event := testEvent()
+	event.Findings = []Finding{critical, owned, stripe, stripeVulnerable}
+
+	err = router.Notify(context.Background(), event)
+	assert.ErrorContains(t, err, "mail server down", "errors of a channel are returned")
+
+	require.Len(t, pager.events, 1)
+	assert.Equal(t, []Finding{critical}, pager.events[0].Findings)
+	assert.Equal(t, event.Project, pager.events[0].Project)
+	require.Len(t, payments.events, 1)
+	assert.Equal(t, []Finding{critical, owned, stripe}, payments.events[0].Findings)
+	require.Len(t, defaults.events, 1)
+	assert.Equal(t, []Finding{stripeVulnerable}, defaults.events[0].Findings, "unrouted findings go to the default channel")
+}
+
+func TestNewRouterValidatesRules(t *testing.T) {
+	channels := map[string]ChannelConfig{"pager": {}}
+	for name, rule := range map[string]Rule{
+		"unknown channel":  {Channels: []string{"oncall"}},
+		"no channels":      {Severity: SeverityCritical},
+		"invalid severity": {Severity: "urgent", Channels: []string{"pager"}},
+		"invalid pattern":  {Modules: []string{"github.com/[/..."}, Channels: []string{"pager"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRouter(Config{Channels: channels, Rules: []Rule{rule}})
+			assert.Error(t, err)
+		})
+	}
+}
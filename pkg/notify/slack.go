@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxSlackFindings limits the findings listed in a message, Slack
+	// accepts at most 50 blocks
+	maxSlackFindings = 40
+	// maxSlackText is the limit of the text of a section block
+	maxSlackText = 3000
+)
+
+// SlackConfig configures the Slack incoming webhook
+type SlackConfig struct {
+	WebhookURL string `mapstructure:"webhook_url"`
+}
+
+// Slack posts the event as Block Kit message to an incoming webhook
+type Slack struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlack creates a notifier for the configured incoming webhook
+func NewSlack(config SlackConfig) *Slack {
+	return &Slack{url: config.WebhookURL, httpClient: &http.Client{}}
+}
+
+// Notify posts the event as message with a section per finding
+func (s *Slack) Notify(ctx context.Context, event Event) error {
+	return postJSON(ctx, s.httpClient, s.url, slackEventPayload(event))
+}
+
+// Send posts the text of the message
+func (s *Slack) Send(ctx context.Context, message Message) error {
+	blocks := []map[string]any{
+		slackHeader(message.Subject),
+		slackSection(message.Text),
+	}
+	return postJSON(ctx, s.httpClient, s.url, map[string]any{"text": message.Text, "blocks": blocks})
+}
+
+// slackEventPayload renders the event as Block Kit message. The text is the
+// fallback of notifications and clients without Block Kit support.
+func slackEventPayload(event Event) map[string]any {
+	blocks := []map[string]any{
+		slackHeader(Subject(event)),
+		{
+			"type": "context",
+			"elements": []map[string]any{
+				{"type": "mrkdwn", "text": "Scanned " + event.ScannedAt.UTC().Format("2006-01-02 15:04 MST")},
+			},
+		},
+		{"type": "divider"},
+	}
+	for i, finding := range event.Findings {
+		if i == maxSlackFindings {
+			blocks = append(blocks, slackSection(fmt.Sprintf("_and %d more findings_", len(event.Findings)-maxSlackFindings)))
+			break
+		}
+		blocks = append(blocks, slackSection(slackFinding(finding)))
+	}
+	return map[string]any{"text": Text(event), "blocks": blocks}
+}
+
+func slackFinding(finding Finding) string {
+	var text strings.Builder
+	icon := ":warning:"
+	if finding.Severity == SeverityCritical {
+		icon = ":rotating_light:"
+	}
+	fmt.Fprintf(&text, "%s *%s*@%s became *%s*", icon, slackEscape(finding.Module), slackEscape(finding.Version), finding.Kind)
+	if finding.Detail != "" {
+		fmt.Fprintf(&text, "\n%s", slackEscape(finding.Detail))
+	}
+	if len(finding.Owners) > 0 {
+		fmt.Fprintf(&text, "\nOwners: %s", slackEscape(strings.Join(finding.Owners, ", ")))
+	}
+	return text.String()
+}
+
+// slackHeader is a header block, which only takes plain text of up to 150
+// characters
+func slackHeader(text string) map[string]any {
+	return map[string]any{
+		"type": "header",
+		"text": map[string]any{"type": "plain_text", "text": truncate(text, 150)},
+	}
+}
+
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": truncate(text, maxSlackText)},
+	}
+}
+
+// slackEscape escapes the control characters of Slack's mrkdwn
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// truncate shortens text to at most limit characters
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
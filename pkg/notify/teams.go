@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxTeamsFindings limits the findings listed in a card, Teams rejects
+// messages larger than 28 KB
+const maxTeamsFindings = 40
+
+// TeamsConfig configures the Microsoft Teams webhook, either an incoming
+// webhook or a workflow triggered by a webhook request
+type TeamsConfig struct {
+	WebhookURL string `mapstructure:"webhook_url"`
+}
+
+// Teams posts the event as Adaptive Card to a Microsoft Teams webhook
+type Teams struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewTeams creates a notifier for the configured webhook
+func NewTeams(config TeamsConfig) *Teams {
+	return &Teams{url: config.WebhookURL, httpClient: &http.Client{}}
+}
+
+// Notify posts the event as card with a fact per finding
+func (t *Teams) Notify(ctx context.Context, event Event) error {
+	body := []map[string]any{
+		teamsTitle(Subject(event)),
+		{
+			"type":     "TextBlock",
+			"text":     "Scanned " + event.ScannedAt.UTC().Format("2006-01-02 15:04 MST"),
+			"isSubtle": true,
+			"spacing":  "None",
+			"wrap":     true,
+		},
+	}
+	facts := make([]map[string]string, 0, len(event.Findings))
+	for i, finding := range event.Findings {
+		if i == maxTeamsFindings {
+			facts = append(facts, map[string]string{"title": "…", "value": fmt.Sprintf("and %d more findings", len(event.Findings)-maxTeamsFindings)})
+			break
+		}
+		facts = append(facts, map[string]string{"title": finding.Module + "@" + finding.Version, "value": teamsFinding(finding)})
+	}
+	body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	return postJSON(ctx, t.httpClient, t.url, adaptiveCardMessage(body))
+}
+
+// Send posts the text of the message
+func (t *Teams) Send(ctx context.Context, message Message) error {
+	body := []map[string]any{
+		teamsTitle(message.Subject),
+		{"type": "TextBlock", "text": message.Text, "wrap": true},
+	}
+	return postJSON(ctx, t.httpClient, t.url, adaptiveCardMessage(body))
+}
+
+func teamsFinding(finding Finding) string {
+	parts := []string{"became " + finding.Kind}
+	if finding.Severity == SeverityCritical {
+		parts[0] = "**" + parts[0] + " (critical)**"
+	}
+	if finding.Detail != "" {
+		parts = append(parts, finding.Detail)
+	}
+	if len(finding.Owners) > 0 {
+		parts = append(parts, "owners: "+strings.Join(finding.Owners, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func teamsTitle(text string) map[string]any {
+	return map[string]any{"type": "TextBlock", "text": text, "size": "Medium", "weight": "Bolder", "wrap": true}
+}
+
+// adaptiveCardMessage wraps the card body in the message format both
+// incoming webhooks and workflows accept
+func adaptiveCardMessage(body []map[string]any) map[string]any {
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+					"msteams": map[string]string{"width": "Full"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookConfig configures the generic webhook
+type WebhookConfig struct {
+	URL string `mapstructure:"url"`
+}
+
+// Webhook posts the event as JSON to a URL
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhook creates a notifier for the configured URL
+func NewWebhook(config WebhookConfig) *Webhook {
+	return &Webhook{url: config.URL, httpClient: &http.Client{}}
+}
+
+// Notify posts the event
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	return w.Send(ctx, Message{Subject: Subject(event), Text: Text(event), Payload: event})
+}
+
+// Send posts the payload of the message
+func (w *Webhook) Send(ctx context.Context, message Message) error {
+	return postJSON(ctx, w.httpClient, w.url, message.Payload)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("notification webhook returned status %d: %s", response.StatusCode, bytes.TrimSpace(message))
+	}
+	return nil
+}
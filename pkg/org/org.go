@@ -0,0 +1,214 @@
+// Package org discovers the Go repositories of a forge organization and
+// ranks the risky dependencies shared by them, so platform teams see which
+// dependency problems affect the most repositories
+package org
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/duplicates"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Reasons of a risky dependency
+const (
+	Inactive   = "inactive"
+	Vulnerable = "vulnerable"
+	Archived   = "archived"
+	Deprecated = "deprecated"
+	Retracted  = "retracted"
+)
+
+// Repository is a Go repository of the organization
+type Repository struct {
+	forge.OrgRepository
+	// Module is the module path declared by the go.mod of the repository
+	// root
+	Module string
+}
+
+// Target returns the module at the default branch, as scanned by
+// 'govital batch'
+func (r Repository) Target() string {
+	return r.Module + "@" + r.DefaultBranch
+}
+
+// Discover lists the repositories of the organization on host which have a
+// go.mod in their root. Archived repositories are skipped unless
+// includeArchived is set, as are repositories the forge detected another
+// primary language for. Repositories sharing a module path, e.g. forks,
+// are scanned once.
+func Discover(ctx context.Context, client *forge.Client, host, org string, includeArchived bool) ([]Repository, error) {
+	listed, err := client.OrgRepositories(ctx, host, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s/%s: %w", host, org, err)
+	}
+
+	var repositories []Repository
+	modules := make(map[string]string)
+	for _, listedRepo := range listed {
+		switch {
+		case listedRepo.Archived && !includeArchived:
+			eslog.Debugf("Skipping archived repository %s", listedRepo.Name)
+			continue
+		case listedRepo.Language != "" && listedRepo.Language != "Go":
+			eslog.Debugf("Skipping %s repository %s", listedRepo.Language, listedRepo.Name)
+			continue
+		case listedRepo.DefaultBranch == "":
+			eslog.Debugf("Skipping empty repository %s", listedRepo.Name)
+			continue
+		}
+
+		source := repo.Repository{Root: host + "/" + listedRepo.Name, URL: listedRepo.URL}
+		data, err := client.File(ctx, source, "go.mod")
+		if forge.IsNotFound(err) {
+			eslog.Debugf("Skipping %s without go.mod", listedRepo.Name)
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			eslog.Warnf("Skipping %s, failed to read its go.mod: %v", listedRepo.Name, err)
+			continue
+		}
+		modulePath := modfile.ModulePath(data)
+		if modulePath == "" {
+			eslog.Warnf("Skipping %s, its go.mod declares no module", listedRepo.Name)
+			continue
+		}
+		if other, ok := modules[modulePath]; ok {
+			eslog.Warnf("Skipping %s, module %s is scanned from %s", listedRepo.Name, modulePath, other)
+			continue
+		}
+		modules[modulePath] = listedRepo.Name
+		repositories = append(repositories, Repository{OrgRepository: listedRepo, Module: modulePath})
+	}
+	return repositories, nil
+}
+
+// Risk is a risky dependency and the repositories using it
+type Risk struct {
+	Path string `json:"path"`
+	// Reasons are why the dependency is risky in at least one repository,
+	// in the order of the reason constants
+	Reasons []string `json:"reasons"`
+	// Repositories are the modules of the repositories using the
+	// dependency at a risky version, sorted
+	Repositories []string `json:"repositories"`
+	// Versions are the risky versions in use, newest first
+	Versions []string `json:"versions"`
+}
+
+// Reasons returns why the dependency is risky, nil if it is not.
+// Acknowledged inactive dependencies are not risky.
+func Reasons(dep scanner.Dependency) []string {
+	var reasons []string
+	if dep.IsInactive() && !dep.IsAcknowledged {
+		reasons = append(reasons, Inactive)
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		reasons = append(reasons, Vulnerable)
+	}
+	if dep.Archived != nil && *dep.Archived {
+		reasons = append(reasons, Archived)
+	}
+	if dep.Deprecated != "" {
+		reasons = append(reasons, Deprecated)
+	}
+	if dep.Retracted != nil {
+		reasons = append(reasons, Retracted)
+	}
+	return reasons
+}
+
+// Rank returns the risky dependencies of the results, used by the most
+// repositories first and on ties the one with the most reasons
+func Rank(results []*scanner.ScanResult) []Risk {
+	type usage struct {
+		reasons      map[string]bool
+		repositories map[string]bool
+		versions     map[string]bool
+	}
+	byPath := make(map[string]*usage)
+	for _, result := range results {
+		for _, dep := range result.Dependencies {
+			reasons := Reasons(dep)
+			if len(reasons) == 0 {
+				continue
+			}
+			use, ok := byPath[dep.Path]
+			if !ok {
+				use = &usage{reasons: make(map[string]bool), repositories: make(map[string]bool), versions: make(map[string]bool)}
+				byPath[dep.Path] = use
+			}
+			for _, reason := range reasons {
+				use.reasons[reason] = true
+			}
+			use.repositories[duplicates.ProjectName(result, dep)] = true
+			use.versions[dep.Version] = true
+		}
+	}
+
+	risks := make([]Risk, 0, len(byPath))
+	for path, use := range byPath {
+		risk := Risk{Path: path}
+		for _, reason := range []string{Inactive, Vulnerable, Archived, Deprecated, Retracted} {
+			if use.reasons[reason] {
+				risk.Reasons = append(risk.Reasons, reason)
+			}
+		}
+		for repository := range use.repositories {
+			risk.Repositories = append(risk.Repositories, repository)
+		}
+		sort.Strings(risk.Repositories)
+		for version := range use.versions {
+			risk.Versions = append(risk.Versions, version)
+		}
+		sort.Slice(risk.Versions, func(i, j int) bool {
+			return semver.Compare(risk.Versions[i], risk.Versions[j]) > 0
+		})
+		risks = append(risks, risk)
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		if len(risks[i].Repositories) != len(risks[j].Repositories) {
+			return len(risks[i].Repositories) > len(risks[j].Repositories)
+		}
+		if len(risks[i].Reasons) != len(risks[j].Reasons) {
+			return len(risks[i].Reasons) > len(risks[j].Reasons)
+		}
+		return risks[i].Path < risks[j].Path
+	})
+	return risks
+}
+
+// Write prints the risky dependencies as table with the repositories
+// using them
+func Write(w io.Writer, risks []Risk) error {
+	if len(risks) == 0 {
+		_, err := fmt.Fprintln(w, "No risky dependencies found")
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "MODULE\tREPOSITORIES\tVERSIONS\tRISKS")
+	for _, risk := range risks {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", risk.Path, len(risk.Repositories),
+			strings.Join(risk.Versions, ", "), strings.Join(risk.Reasons, ", "))
+		for _, repository := range risk.Repositories {
+			fmt.Fprintf(table, "  %s\t\t\t\n", repository)
+		}
+	}
+	return table.Flush()
+}
@@ -0,0 +1,101 @@
+package org
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	goMod := func(module string) string {
+		content := base64.StdEncoding.EncodeToString([]byte("module " + module + "\n\ngo 1.22\n"))
+		return fmt.Sprintf(`{"content":%q,"encoding":"base64"}`, content)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/example/repos":
+			_, _ = w.Write([]byte(`[
+				{"full_name":"example/api","html_url":"https://github.com/example/api","default_branch":"main","language":"Go"},
+				{"full_name":"example/api-fork","html_url":"https://github.com/example/api-fork","default_branch":"main","language":"Go"},
+				{"full_name":"example/old","html_url":"https://github.com/example/old","default_branch":"main","language":"Go","archived":true},
+				{"full_name":"example/web","html_url":"https://github.com/example/web","default_branch":"main","language":"TypeScript"},
+				{"full_name":"example/docs","html_url":"https://github.com/example/docs","default_branch":"main"},
+				{"full_name":"example/tools","html_url":"https://github.com/example/tools","default_branch":"develop"}
+			]`))
+		case "/repos/example/api/contents/go.mod", "/repos/example/api-fork/contents/go.mod":
+			_, _ = w.Write([]byte(goMod("github.com/example/api")))
+		case "/repos/example/old/contents/go.mod":
+			_, _ = w.Write([]byte(goMod("github.com/example/old")))
+		case "/repos/example/tools/contents/go.mod":
+			_, _ = w.Write([]byte(goMod("example.com/tools")))
+		case "/repos/example/docs/contents/go.mod":
+			http.NotFound(w, r)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := forge.NewClient(server.Client(), forge.Config{})
+	client.GitHubURL = server.URL
+
+	repositories, err := Discover(context.Background(), client, "github.com", "example", false)
+	require.NoError(t, err)
+	require.Len(t, repositories, 2)
+	assert.Equal(t, "github.com/example/api@main", repositories[0].Target())
+	assert.Equal(t, "example.com/tools@develop", repositories[1].Target(), "the module path of go.mod is scanned")
+
+	repositories, err = Discover(context.Background(), client, "github.com", "example", true)
+	require.NoError(t, err)
+	assert.Len(t, repositories, 3, "archived repositories are included on request")
+}
+
+func TestRank(t *testing.T) {
+	archived := true
+	result := func(module string, deps ...scanner.Dependency) *scanner.ScanResult {
+		return &scanner.ScanResult{Fingerprint: &scanner.Fingerprint{Module: module}, Dependencies: deps}
+	}
+	results := []*scanner.ScanResult{
+		result("github.com/example/api",
+			scanner.Dependency{Path: "github.com/old/lib", Version: "v1.0.0", Status: scanner.StatusStale},
+			scanner.Dependency{Path: "github.com/vuln/lib", Version: "v0.1.0", Status: scanner.StatusActive, IsActive: true,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+			scanner.Dependency{Path: "github.com/fine/lib", Version: "v1.0.0", Status: scanner.StatusActive, IsActive: true},
+		),
+		result("github.com/example/web",
+			scanner.Dependency{Path: "github.com/old/lib", Version: "v1.1.0", Status: scanner.StatusStale, Archived: &archived},
+			scanner.Dependency{Path: "github.com/known/lib", Version: "v1.0.0", Status: scanner.StatusStale, IsAcknowledged: true},
+		),
+	}
+
+	risks := Rank(results)
+	require.Len(t, risks, 2)
+	assert.Equal(t, Risk{
+		Path:         "github.com/old/lib",
+		Reasons:      []string{Inactive, Archived},
+		Repositories: []string{"github.com/example/api", "github.com/example/web"},
+		Versions:     []string{"v1.1.0", "v1.0.0"},
+	}, risks[0])
+	assert.Equal(t, "github.com/vuln/lib", risks[1].Path)
+	assert.Equal(t, []string{Vulnerable}, risks[1].Reasons)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, risks))
+	assert.Contains(t, out.String(), "github.com/old/lib")
+	assert.Contains(t, out.String(), "inactive, archived")
+	assert.Contains(t, out.String(), "  github.com/example/web")
+
+	out.Reset()
+	require.NoError(t, Write(&out, nil))
+	assert.Equal(t, "No risky dependencies found\n", out.String())
+}
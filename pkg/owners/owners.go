@@ -0,0 +1,73 @@
+package owners
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Rule assigns owners to all modules matching the pattern. Patterns are
+// module paths which may contain path.Match wildcards like
+// golang.org/x/* or end in /... to match a path and everything below it,
+// e.g. github.com/aws/...
+type Rule struct {
+	Pattern string   `mapstructure:"pattern"`
+	Owners  []string `mapstructure:"owners"`
+}
+
+// Matcher resolves the owners of module paths. Like in CODEOWNERS files the
+// last matching rule wins, so specific rules go after general ones.
+type Matcher struct {
+	rules []Rule
+}
+
+// NewMatcher validates the rules and creates a matcher for them
+func NewMatcher(rules []Rule) (*Matcher, error) {
+	for i, rule := range rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("owner rule %d has no pattern", i+1)
+		}
+		if err := ValidatePattern(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid owner pattern %q: %w", rule.Pattern, err)
+		}
+	}
+	return &Matcher{rules: rules}, nil
+}
+
+// Owners returns the owners of the module path, nil if no rule matches or
+// the matcher is nil. An empty owner list in a matching rule explicitly
+// leaves a module unowned.
+func (m *Matcher) Owners(modulePath string) []string {
+	if m == nil {
+		return nil
+	}
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		if Match(m.rules[i].Pattern, modulePath) {
+			return m.rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// ValidatePattern returns an error if the module pattern is malformed
+func ValidatePattern(pattern string) error {
+	_, err := path.Match(strings.TrimSuffix(pattern, "/..."), "")
+	return err
+}
+
+// Match returns whether the module path matches the pattern, a module path
+// with path.Match wildcards which may end in /... to match everything below
+func Match(pattern, modulePath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		// Match the prefix against the same number of path elements
+		elements := strings.Count(prefix, "/") + 1
+		parts := strings.SplitN(modulePath, "/", elements+1)
+		if len(parts) < elements {
+			return false
+		}
+		matched, _ := path.Match(prefix, strings.Join(parts[:elements], "/"))
+		return matched
+	}
+	matched, _ := path.Match(pattern, modulePath)
+	return matched
+}
@@ -0,0 +1,56 @@
+package owners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	matcher, err := NewMatcher([]Rule{
+		{Pattern: "*/...", Owners: []string{"@platform"}},
+		{Pattern: "github.com/aws/...", Owners: []string{"@cloud"}},
+		{Pattern: "golang.org/x/*", Owners: []string{"@go-team", "@security"}},
+		{Pattern: "github.com/aws/smithy-go", Owners: []string{}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		modulePath string
+		expected   []string
+	}{
+		{"github.com/aws/aws-sdk-go-v2", []string{"@cloud"}},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", []string{"@cloud"}},
+		{"github.com/aws", []string{"@cloud"}},
+		{"github.com/awslabs/tool", []string{"@platform"}},
+		{"golang.org/x/mod", []string{"@go-team", "@security"}},
+		{"golang.org/x/tools/gopls", []string{"@platform"}},
+		{"github.com/aws/smithy-go", []string{}},
+		{"example.com", []string{"@platform"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matcher.Owners(tt.modulePath))
+		})
+	}
+}
+
+func TestOwnersWithoutMatch(t *testing.T) {
+	matcher, err := NewMatcher([]Rule{{Pattern: "github.com/aws/...", Owners: []string{"@cloud"}}})
+	require.NoError(t, err)
+
+	assert.Nil(t, matcher.Owners("golang.org/x/mod"))
+
+	var noMatcher *Matcher
+	assert.Nil(t, noMatcher.Owners("golang.org/x/mod"))
+}
+
+func TestNewMatcherInvalid(t *testing.T) {
+	_, err := NewMatcher([]Rule{{Owners: []string{"@cloud"}}})
+	assert.Error(t, err)
+
+	_, err = NewMatcher([]Rule{{Pattern: "github.com/[aws", Owners: []string{"@cloud"}}})
+	assert.Error(t, err)
+}
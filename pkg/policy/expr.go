@@ -0,0 +1,420 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// kind is the type of an expression or field
+type kind int
+
+const (
+	boolKind kind = iota
+	numberKind
+	stringKind
+)
+
+func (k kind) String() string {
+	switch k {
+	case boolKind:
+		return "bool"
+	case numberKind:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// value is the result of an expression. Null values are unknown numbers,
+// like the score of a dependency which couldn't be scored.
+type value struct {
+	b    bool
+	n    float64
+	s    string
+	null bool
+}
+
+// field is a property of a dependency usable in rule expressions
+type field struct {
+	kind kind
+	get  func(dep scanner.Dependency) value
+}
+
+func boolField(get func(dep scanner.Dependency) bool) field {
+	return field{kind: boolKind, get: func(dep scanner.Dependency) value { return value{b: get(dep)} }}
+}
+
+func numberField(get func(dep scanner.Dependency) int) field {
+	return field{kind: numberKind, get: func(dep scanner.Dependency) value { return value{n: float64(get(dep))} }}
+}
+
+// optionalField is a number which is unknown if get returns nil
+func optionalField(get func(dep scanner.Dependency) *int) field {
+	return field{kind: numberKind, get: func(dep scanner.Dependency) value {
+		if n := get(dep); n != nil {
+			return value{n: float64(*n)}
+		}
+		return value{null: true}
+	}}
+}
+
+func stringField(get func(dep scanner.Dependency) string) field {
+	return field{kind: stringKind, get: func(dep scanner.Dependency) value { return value{s: get(dep)} }}
+}
+
+// fields are the dependency properties rule expressions can refer to
+var fields = map[string]field{
+	"path":         stringField(func(dep scanner.Dependency) string { return dep.Path }),
+	"version":      stringField(func(dep scanner.Dependency) string { return dep.Version }),
+	"status":       stringField(func(dep scanner.Dependency) string { return string(dep.Status) }),
+	"severity":     stringField(func(dep scanner.Dependency) string { return string(dep.Severity) }),
+	"direct":       boolField(func(dep scanner.Dependency) bool { return !dep.IsIndirect }),
+	"indirect":     boolField(func(dep scanner.Dependency) bool { return dep.IsIndirect }),
+	"active":       boolField(func(dep scanner.Dependency) bool { return dep.IsActive }),
+	"inactive":     boolField(func(dep scanner.Dependency) bool { return dep.IsInactive() }),
+	"unknown":      boolField(func(dep scanner.Dependency) bool { return dep.Status.IsUnknown() }),
+	"acknowledged": boolField(func(dep scanner.Dependency) bool { return dep.IsAcknowledged }),
+	"archived":     boolField(func(dep scanner.Dependency) bool { return dep.Archived != nil && *dep.Archived }),
+	"outdated":     boolField(func(dep scanner.Dependency) bool { return dep.Update != "" }),
+	"vulnerable":   boolField(func(dep scanner.Dependency) bool { return len(dep.Vulnerabilities) > 0 }),
+	"retracted":    boolField(func(dep scanner.Dependency) bool { return dep.Retracted != nil }),
+	"deprecated":   boolField(func(dep scanner.Dependency) bool { return dep.Deprecated != "" }),
+	"unreleased":   boolField(func(dep scanner.Dependency) bool { return dep.Unreleased }),
+	"error":        boolField(func(dep scanner.Dependency) bool { return dep.Error != nil }),
+	"bus_factor":   boolField(func(dep scanner.Dependency) bool { return dep.BusFactorRisk }),
+	"unresponsive": boolField(func(dep scanner.Dependency) bool { return dep.Unresponsive }),
+	"new_major":    boolField(func(dep scanner.Dependency) bool { return dep.NewerMajorAvailable != nil }),
+	"fork":         boolField(func(dep scanner.Dependency) bool { return dep.Upstream != nil }),
+	"stale_fork":   boolField(func(dep scanner.Dependency) bool { return dep.StaleFork }),
+	"license_changed": boolField(func(dep scanner.Dependency) bool {
+		return dep.LicenseChange != nil
+	}),
+	"tier": stringField(func(dep scanner.Dependency) string {
+		if dep.Tier == "" {
+			return string(scanner.TierNormal)
+		}
+		return string(dep.Tier)
+	}),
+	"transitive_risk": stringField(func(dep scanner.Dependency) string {
+		if dep.TransitiveRisk == nil {
+			return ""
+		}
+		return string(dep.TransitiveRisk.Status)
+	}),
+	"days_since_release": {kind: numberKind, get: func(dep scanner.Dependency) value {
+		if dep.LastReleaseTime.IsZero() {
+			return value{null: true}
+		}
+		return value{n: float64(dep.DaysSinceLastRelease)}
+	}},
+	"days_since_latest_release": optionalField(func(dep scanner.Dependency) *int { return dep.DaysSinceLatestRelease }),
+	"releases_last_year":        numberField(func(dep scanner.Dependency) int { return dep.ReleasesLastYear }),
+	"vulnerabilities":           numberField(func(dep scanner.Dependency) int { return len(dep.Vulnerabilities) }),
+	"score":                     optionalField(func(dep scanner.Dependency) *int { return dep.Score }),
+	"contributors":              optionalField(func(dep scanner.Dependency) *int { return dep.ContributorCount }),
+	"open_issues":               optionalField(func(dep scanner.Dependency) *int { return dep.OpenIssues }),
+	"median_response_hours":     optionalField(func(dep scanner.Dependency) *int { return dep.MedianResponseHours }),
+	"merged_pull_requests":      optionalField(func(dep scanner.Dependency) *int { return dep.MergedPullRequests }),
+}
+
+// Fields returns the names of the fields rule expressions can refer to
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expr is a compiled, type checked expression
+type expr struct {
+	kind kind
+	eval func(dep scanner.Dependency) value
+}
+
+// Compile parses a rule expression like
+// "days_since_release > 365 and direct" into a predicate on dependencies.
+// Expressions combine fields, numbers, quoted strings, true and false with
+// the comparisons ==, !=, <, <=, > and >=, the operators and, or and not
+// (or &&, || and !) and parentheses. Comparisons with an unknown number,
+// like the score of an unscored dependency, are false.
+func Compile(source string) (func(dep scanner.Dependency) bool, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err == nil && p.peek().kind != endToken {
+		err = fmt.Errorf("unexpected %s at position %d", p.peek(), p.peek().pos)
+	}
+	if err == nil && e.kind != boolKind {
+		err = fmt.Errorf("expression is a %s, expected a condition", e.kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return func(dep scanner.Dependency) bool {
+		return e.eval(dep).b
+	}, nil
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identToken
+	numberToken
+	stringToken
+	operatorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators are sorted so two character operators are matched first
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: identToken, text: source[start:i], pos: start})
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: source[start:i], pos: start})
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(source[i+1:], source[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: stringToken, text: source[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(source[i:], operator) {
+					tokens = append(tokens, token{kind: operatorToken, text: operator, pos: i})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: endToken, pos: len(source)}), nil
+}
+
+// parser is a recursive descent parser, binding not tighter than and and
+// and tighter than or
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// accept consumes the next token if it is one of the operators or keywords
+func (p *parser) accept(texts ...string) bool {
+	t := p.peek()
+	if t.kind != operatorToken && t.kind != identToken {
+		return false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.next++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return expr{}, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept("or", "||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return expr{}, err
+		}
+		if err := requireBool(pos, "or", left, right); err != nil {
+			return expr{}, err
+		}
+		l, r := left.eval, right.eval
+		left = expr{kind: boolKind, eval: func(dep scanner.Dependency) value {
+			return value{b: l(dep).b || r(dep).b}
+		}}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return expr{}, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept("and", "&&") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return expr{}, err
+		}
+		if err := requireBool(pos, "and", left, right); err != nil {
+			return expr{}, err
+		}
+		l, r := left.eval, right.eval
+		left = expr{kind: boolKind, eval: func(dep scanner.Dependency) value {
+			return value{b: l(dep).b && r(dep).b}
+		}}
+	}
+}
+
+func (p *parser) parseNot() (expr, error) {
+	pos := p.peek().pos
+	if !p.accept("not", "!") {
+		return p.parseComparison()
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return expr{}, err
+	}
+	if err := requireBool(pos, "not", operand); err != nil {
+		return expr{}, err
+	}
+	return expr{kind: boolKind, eval: func(dep scanner.Dependency) value {
+		return value{b: !operand.eval(dep).b}
+	}}, nil
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return expr{}, err
+	}
+	t := p.peek()
+	if !p.accept("==", "!=", "<", "<=", ">", ">=") {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return expr{}, err
+	}
+	if left.kind != right.kind {
+		return expr{}, fmt.Errorf("cannot compare %s with %s at position %d", left.kind, right.kind, t.pos)
+	}
+	if left.kind != numberKind && t.text != "==" && t.text != "!=" {
+		return expr{}, fmt.Errorf("%s requires numbers at position %d", t.text, t.pos)
+	}
+
+	compare := comparisons[t.text]
+	l, r := left.eval, right.eval
+	return expr{kind: boolKind, eval: func(dep scanner.Dependency) value {
+		a, b := l(dep), r(dep)
+		if a.null || b.null {
+			return value{}
+		}
+		return value{b: compare(a, b)}
+	}}, nil
+}
+
+var comparisons = map[string]func(a, b value) bool{
+	"==": func(a, b value) bool { return a == b },
+	"!=": func(a, b value) bool { return a != b },
+	"<":  func(a, b value) bool { return a.n < b.n },
+	"<=": func(a, b value) bool { return a.n <= b.n },
+	">":  func(a, b value) bool { return a.n > b.n },
+	">=": func(a, b value) bool { return a.n >= b.n },
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	p.next++
+	switch t.kind {
+	case numberToken:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return expr{}, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return constant(numberKind, value{n: n}), nil
+	case stringToken:
+		return constant(stringKind, value{s: t.text}), nil
+	case identToken:
+		switch t.text {
+		case "true", "false":
+			return constant(boolKind, value{b: t.text == "true"}), nil
+		case "and", "or", "not":
+			return expr{}, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+		}
+		f, ok := fields[t.text]
+		if !ok {
+			return expr{}, fmt.Errorf("unknown field %q at position %d, expected one of %s", t.text, t.pos, strings.Join(Fields(), ", "))
+		}
+		return expr{kind: f.kind, eval: f.get}, nil
+	case operatorToken:
+		if t.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return expr{}, err
+			}
+			if !p.accept(")") {
+				return expr{}, fmt.Errorf("expected \")\" at position %d", p.peek().pos)
+			}
+			return inner, nil
+		}
+	}
+	p.next--
+	return expr{}, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+func constant(k kind, v value) expr {
+	return expr{kind: k, eval: func(scanner.Dependency) value { return v }}
+}
+
+func requireBool(pos int, operator string, operands ...expr) error {
+	for _, operand := range operands {
+		if operand.kind != boolKind {
+			return fmt.Errorf("%s requires conditions at position %d, got a %s", operator, pos, operand.kind)
+		}
+	}
+	return nil
+}
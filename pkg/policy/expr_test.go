@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	archived := true
+	released := time.Now().AddDate(-2, 0, 0)
+	tests := []struct {
+		name     string
+		source   string
+		dep      scanner.Dependency
+		expected bool
+	}{
+		{"old direct", "days_since_release > 365 and direct == true", scanner.Dependency{LastReleaseTime: released, DaysSinceLastRelease: 730}, true},
+		{"old indirect", "days_since_release > 365 and direct", scanner.Dependency{LastReleaseTime: released, DaysSinceLastRelease: 730, IsIndirect: true}, false},
+		{"unknown release time", "days_since_release > 365", scanner.Dependency{}, false},
+		{"archived", "archived", scanner.Dependency{Archived: &archived}, true},
+		{"not archived", "not archived", scanner.Dependency{}, true},
+		{"unknown score", "score < 50", scanner.Dependency{}, false},
+		{"low score", "score<50 || vulnerable", scanner.Dependency{Score: intPtr(20)}, true},
+		{"precedence", "archived or outdated and vulnerable", scanner.Dependency{Update: "v1.1.0"}, false},
+		{"parentheses", "!(archived or outdated) && true", scanner.Dependency{Update: "v1.1.0"}, false},
+		{"string", "path == 'github.com/foo/bar' and status != \"active\"", scanner.Dependency{Path: "github.com/foo/bar", Status: scanner.StatusStale}, true},
+		{"float", "contributors <= 1.5", scanner.Dependency{ContributorCount: intPtr(1)}, true},
+		{"tier", "tier == 'critical' and days_since_release > 90", scanner.Dependency{Tier: scanner.TierCritical, LastReleaseTime: released, DaysSinceLastRelease: 730}, true},
+		{"default tier", "tier == 'normal'", scanner.Dependency{}, true},
+		{"transitive risk", "direct and transitive_risk == 'archived'", scanner.Dependency{TransitiveRisk: &scanner.TransitiveRisk{Status: scanner.StatusArchived}}, true},
+		{"no transitive risk", "transitive_risk == 'active'", scanner.Dependency{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := Compile(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, match(tt.dep))
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"score",
+		"bogus > 1",
+		"score > 'high'",
+		"path < 'a'",
+		"archived and score",
+		"not score",
+		"(archived",
+		"archived)",
+		"path == 'open",
+		"score > 1 > 2",
+		"archived and",
+		"days_since_release # 3",
+	} {
+		_, err := Compile(source)
+		assert.Error(t, err, source)
+	}
+}
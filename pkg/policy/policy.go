@@ -0,0 +1,264 @@
+package policy
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Condition is a named predicate on a scanned dependency, e.g. "inactive"
+// or "score<50"
+type Condition struct {
+	Name string
+	// Warn is set for warn rules, which are reported but don't fail
+	Warn  bool
+	match func(dep scanner.Dependency) bool
+}
+
+// Matches reports whether the dependency meets the condition
+func (c Condition) Matches(dep scanner.Dependency) bool {
+	return c.match(dep)
+}
+
+// Violation is a dependency meeting at least one fail-on condition or
+// warn rule
+type Violation struct {
+	Dependency scanner.Dependency
+	// Conditions are the names of all failing conditions the dependency meets
+	Conditions []string
+	// Warnings are the names of all warn rules the dependency meets
+	Warnings []string
+}
+
+// Fails reports whether the dependency meets a failing condition
+func (v Violation) Fails() bool {
+	return len(v.Conditions) > 0
+}
+
+// Rule is a named expression from the policy.rules list of the config
+// file. Exactly one of FailWhen and WarnWhen is set.
+type Rule struct {
+	Name     string `mapstructure:"name"`
+	FailWhen string `mapstructure:"fail_when"`
+	WarnWhen string `mapstructure:"warn_when"`
+}
+
+var namedConditions = map[string]func(dep scanner.Dependency) bool{
+	// inactive matches stale dependencies which are not acknowledged
+	"inactive": func(dep scanner.Dependency) bool {
+		return dep.IsInactive() && !dep.IsAcknowledged
+	},
+	// unknown matches dependencies whose maintenance status couldn't be
+	// determined, failed lookups included
+	"unknown": func(dep scanner.Dependency) bool {
+		return dep.Status.IsUnknown()
+	},
+	"outdated": func(dep scanner.Dependency) bool {
+		return dep.Update != ""
+	},
+	"vulnerable": func(dep scanner.Dependency) bool {
+		return len(dep.Vulnerabilities) > 0
+	},
+	"error": func(dep scanner.Dependency) bool {
+		return dep.Error != nil
+	},
+	// license-changed requires a baseline scan to compare with
+	"license-changed": func(dep scanner.Dependency) bool {
+		return dep.LicenseChange != nil
+	},
+	"retracted": func(dep scanner.Dependency) bool {
+		return dep.Retracted != nil
+	},
+	"deprecated": func(dep scanner.Dependency) bool {
+		return dep.Deprecated != ""
+	},
+	// bus-factor requires repository checks to count the contributors
+	"bus-factor": func(dep scanner.Dependency) bool {
+		return dep.BusFactorRisk
+	},
+	// stale-fork requires repository checks to detect forks
+	"stale-fork": func(dep scanner.Dependency) bool {
+		return dep.StaleFork
+	},
+}
+
+// ParseCondition parses a single fail-on condition. Supported conditions
+// are inactive, unknown, outdated, vulnerable, error, license-changed, retracted,
+// deprecated, bus-factor, stale-fork, score comparisons like score<50 or score<=50, release
+// ages like release-age>365 and minimum severities like severity>=critical.
+// Dependencies without a score never match a score comparison.
+// release-age>N matches dependencies whose newest tagged release is older
+// than N days or which have no tagged release at all, regardless of newer
+// commits.
+func ParseCondition(spec string) (Condition, error) {
+	spec = strings.ToLower(strings.ReplaceAll(spec, " ", ""))
+
+	if match, ok := namedConditions[spec]; ok {
+		return Condition{Name: spec, match: match}, nil
+	}
+
+	if threshold, ok := strings.CutPrefix(spec, "score<"); ok {
+		inclusive := false
+		if rest, found := strings.CutPrefix(threshold, "="); found {
+			threshold = rest
+			inclusive = true
+		}
+		limit, err := strconv.Atoi(threshold)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid score threshold in fail-on condition %q", spec)
+		}
+		return Condition{Name: spec, match: func(dep scanner.Dependency) bool {
+			if dep.Score == nil {
+				return false
+			}
+			if inclusive {
+				return *dep.Score <= limit
+			}
+			return *dep.Score < limit
+		}}, nil
+	}
+
+	if threshold, ok := strings.CutPrefix(spec, "release-age>"); ok {
+		limit, err := strconv.Atoi(threshold)
+		if err != nil || limit < 0 {
+			return Condition{}, fmt.Errorf("invalid release age in fail-on condition %q, expected a number of days", spec)
+		}
+		return Condition{Name: spec, match: func(dep scanner.Dependency) bool {
+			if dep.Unreleased {
+				return true
+			}
+			return dep.DaysSinceLatestRelease != nil && *dep.DaysSinceLatestRelease > limit
+		}}, nil
+	}
+
+	if name, ok := strings.CutPrefix(spec, "severity>="); ok {
+		minimum, err := scanner.ParseSeverity(name)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid fail-on condition %q: %w", spec, err)
+		}
+		return Condition{Name: spec, match: func(dep scanner.Dependency) bool {
+			return dep.Severity.Rank() >= minimum.Rank()
+		}}, nil
+	}
+
+	return Condition{}, fmt.Errorf("unknown fail-on condition %q, expected inactive, unknown, outdated, vulnerable, error, license-changed, score<N, release-age>N or severity>=LEVEL", spec)
+}
+
+// ParseConditions parses all conditions. Each spec may hold several
+// comma separated conditions.
+func ParseConditions(specs []string) ([]Condition, error) {
+	var conditions []Condition
+	for _, spec := range specs {
+		for _, part := range strings.Split(spec, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			condition, err := ParseCondition(part)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions, nil
+}
+
+// ParseRule compiles the expression of the rule into a condition named
+// like the rule
+func ParseRule(rule Rule) (Condition, error) {
+	if rule.Name == "" {
+		return Condition{}, fmt.Errorf("policy rule without name")
+	}
+	if (rule.FailWhen == "") == (rule.WarnWhen == "") {
+		return Condition{}, fmt.Errorf("policy rule %q needs exactly one of fail_when and warn_when", rule.Name)
+	}
+
+	source, warn := rule.FailWhen, false
+	if source == "" {
+		source, warn = rule.WarnWhen, true
+	}
+	match, err := Compile(source)
+	if err != nil {
+		return Condition{}, fmt.Errorf("policy rule %q: %w", rule.Name, err)
+	}
+	return Condition{Name: rule.Name, Warn: warn, match: match}, nil
+}
+
+// ParseRules compiles all rules, whose names must be unique
+func ParseRules(rules []Rule) ([]Condition, error) {
+	var conditions []Condition
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate policy rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+		condition, err := ParseRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// Evaluate returns a violation for every dependency of the result which
+// meets at least one of the conditions, in the order of the result
+func Evaluate(result *scanner.ScanResult, conditions []Condition) []Violation {
+	var violations []Violation
+	for _, dep := range result.Dependencies {
+		violation := Violation{Dependency: dep}
+		for _, condition := range conditions {
+			if !condition.Matches(dep) {
+				continue
+			}
+			if condition.Warn {
+				violation.Warnings = append(violation.Warnings, condition.Name)
+			} else {
+				violation.Conditions = append(violation.Conditions, condition.Name)
+			}
+		}
+		if len(violation.Conditions) > 0 || len(violation.Warnings) > 0 {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// Failures counts the violations meeting a failing condition
+func Failures(violations []Violation) int {
+	failures := 0
+	for _, violation := range violations {
+		if violation.Fails() {
+			failures++
+		}
+	}
+	return failures
+}
+
+// WriteViolations writes a human readable list of the violations to w
+func WriteViolations(w io.Writer, violations []Violation) {
+	failures := Failures(violations)
+	switch {
+	case len(violations) == 0:
+		fmt.Fprintf(w, "Policy check passed: no dependency meets a fail-on condition.\n")
+		return
+	case failures == 0:
+		fmt.Fprintf(w, "Policy check passed with warnings: %d dependencies meet a warn rule\n", len(violations))
+	default:
+		fmt.Fprintf(w, "Policy check failed: %d dependencies meet a fail-on condition\n", failures)
+	}
+
+	for _, violation := range violations {
+		dep := violation.Dependency
+		if violation.Fails() {
+			fmt.Fprintf(w, "  ✗ %s@%s: %s\n", dep.Path, dep.Version, strings.Join(violation.Conditions, ", "))
+		}
+		if len(violation.Warnings) > 0 {
+			fmt.Fprintf(w, "  ! %s@%s: %s\n", dep.Path, dep.Version, strings.Join(violation.Warnings, ", "))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package policy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/license"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		dep         scanner.Dependency
+		expected    bool
+		expectedErr bool
+	}{
+		{"inactive matches stale", "inactive", scanner.Dependency{IsActive: false}, true, false},
+		{"inactive ignores acknowledged", "inactive", scanner.Dependency{IsActive: false, IsAcknowledged: true}, false, false},
+		{"inactive ignores active", "inactive", scanner.Dependency{IsActive: true}, false, false},
+		{"inactive ignores failed lookups", "inactive", scanner.Dependency{Status: scanner.StatusError}, false, false},
+		{"unknown status", "unknown", scanner.Dependency{Status: scanner.StatusUnknown}, true, false},
+		{"unknown matches failed lookups", "unknown", scanner.Dependency{Status: scanner.StatusError}, true, false},
+		{"known status", "unknown", scanner.Dependency{Status: scanner.StatusStale}, false, false},
+		{"outdated", "outdated", scanner.Dependency{Update: "v1.1.0"}, true, false},
+		{"up to date", "outdated", scanner.Dependency{}, false, false},
+		{"vulnerable", "vulnerable", scanner.Dependency{Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}}, true, false},
+		{"error", "error", scanner.Dependency{Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}}, true, false},
+		{"license changed", "license-changed", scanner.Dependency{LicenseChange: &license.Change{From: []string{"MIT"}, To: []string{"BUSL-1.1"}}}, true, false},
+		{"license unchanged", "license-changed", scanner.Dependency{Licenses: []string{"MIT"}}, false, false},
+		{"retracted", "retracted", scanner.Dependency{Retracted: &scanner.Retraction{Rationale: "broken build"}}, true, false},
+		{"deprecated", "deprecated", scanner.Dependency{Deprecated: "use example.com/v2"}, true, false},
+		{"not deprecated", "deprecated", scanner.Dependency{}, false, false},
+		{"single maintainer", "bus-factor", scanner.Dependency{ContributorCount: intPtr(1), BusFactorRisk: true}, true, false},
+		{"several maintainers", "bus-factor", scanner.Dependency{ContributorCount: intPtr(4)}, false, false},
+		{"stale fork", "stale-fork", scanner.Dependency{Upstream: &scanner.Upstream{Repository: "github.com/upstream/mod"}, StaleFork: true}, true, false},
+		{"maintained fork", "stale-fork", scanner.Dependency{Upstream: &scanner.Upstream{Repository: "github.com/upstream/mod"}}, false, false},
+		{"score below", "score<50", scanner.Dependency{Score: intPtr(49)}, true, false},
+		{"score at limit", "score<50", scanner.Dependency{Score: intPtr(50)}, false, false},
+		{"score at inclusive limit", "score <= 50", scanner.Dependency{Score: intPtr(50)}, true, false},
+		{"unknown score", "score<50", scanner.Dependency{}, false, false},
+		{"old release", "release-age>365", scanner.Dependency{DaysSinceLatestRelease: intPtr(400)}, true, false},
+		{"recent release", "release-age>365", scanner.Dependency{DaysSinceLatestRelease: intPtr(30)}, false, false},
+		{"unreleased", "release-age>365", scanner.Dependency{Unreleased: true}, true, false},
+		{"unknown release age", "release-age>365", scanner.Dependency{}, false, false},
+		{"invalid release age", "release-age>year", scanner.Dependency{}, false, true},
+		{"critical severity", "severity>=critical", scanner.Dependency{Severity: scanner.SeverityCritical}, true, false},
+		{"severity below minimum", "severity>=critical", scanner.Dependency{Severity: scanner.SeverityWarn}, false, false},
+		{"severity above minimum", "severity>=warning", scanner.Dependency{Severity: scanner.SeverityCritical}, true, false},
+		{"no severity", "severity>=info", scanner.Dependency{}, false, false},
+		{"invalid severity", "severity>=high", scanner.Dependency{}, false, true},
+		{"case insensitive", "Inactive", scanner.Dependency{}, true, false},
+		{"unknown condition", "abandoned", scanner.Dependency{}, false, true},
+		{"invalid score", "score<high", scanner.Dependency{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := ParseCondition(tt.spec)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, condition.Matches(tt.dep))
+		})
+	}
+}
+
+func TestParseConditions(t *testing.T) {
+	conditions, err := ParseConditions([]string{"inactive,outdated", " ", "score<30"})
+
+	require.NoError(t, err)
+	names := make([]string, len(conditions))
+	for i, condition := range conditions {
+		names[i] = condition.Name
+	}
+	assert.Equal(t, []string{"inactive", "outdated", "score<30"}, names)
+
+	_, err = ParseConditions([]string{"inactive,bogus"})
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/healthy", Version: "v1.0.0", IsActive: true, Score: intPtr(90)},
+		{Path: "github.com/example/stale", Version: "v0.1.0", IsActive: false, Update: "v0.2.0", Score: intPtr(10)},
+		{Path: "github.com/example/old", Version: "v2.0.0", IsActive: true, Update: "v2.1.0"},
+	}}
+	conditions, err := ParseConditions([]string{"inactive", "outdated", "score<50"})
+	require.NoError(t, err)
+
+	violations := Evaluate(result, conditions)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, "github.com/example/stale", violations[0].Dependency.Path)
+	assert.Equal(t, []string{"inactive", "outdated", "score<50"}, violations[0].Conditions)
+	assert.Equal(t, "github.com/example/old", violations[1].Dependency.Path)
+	assert.Equal(t, []string{"outdated"}, violations[1].Conditions)
+
+	assert.Empty(t, Evaluate(result, nil))
+}
+
+func TestParseRules(t *testing.T) {
+	conditions, err := ParseRules([]Rule{
+		{Name: "abandoned-direct", FailWhen: "days_since_release > 365 and direct"},
+		{Name: "archived", WarnWhen: "archived"},
+	})
+	require.NoError(t, err)
+	require.Len(t, conditions, 2)
+	assert.Equal(t, "abandoned-direct", conditions[0].Name)
+	assert.False(t, conditions[0].Warn)
+	assert.Equal(t, "archived", conditions[1].Name)
+	assert.True(t, conditions[1].Warn)
+
+	for _, rules := range [][]Rule{
+		{{FailWhen: "archived"}},
+		{{Name: "both", FailWhen: "archived", WarnWhen: "outdated"}},
+		{{Name: "none"}},
+		{{Name: "invalid", FailWhen: "archived >"}},
+		{{Name: "twice", FailWhen: "archived"}, {Name: "twice", WarnWhen: "outdated"}},
+	} {
+		_, err := ParseRules(rules)
+		assert.Error(t, err)
+	}
+}
+
+func TestEvaluateRules(t *testing.T) {
+	archived := true
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/archived", Version: "v1.0.0", IsActive: true, Archived: &archived},
+		{Path: "github.com/example/old", Version: "v2.0.0", IsActive: true, Update: "v2.1.0", Archived: &archived},
+		{Path: "github.com/example/healthy", Version: "v1.0.0", IsActive: true},
+	}}
+	conditions, err := ParseRules([]Rule{
+		{Name: "outdated-direct", FailWhen: "outdated and direct"},
+		{Name: "archived", WarnWhen: "archived"},
+	})
+	require.NoError(t, err)
+
+	violations := Evaluate(result, conditions)
+
+	require.Len(t, violations, 2)
+	assert.False(t, violations[0].Fails())
+	assert.Equal(t, []string{"archived"}, violations[0].Warnings)
+	assert.True(t, violations[1].Fails())
+	assert.Equal(t, []string{"outdated-direct"}, violations[1].Conditions)
+	assert.Equal(t, 1, Failures(violations))
+
+	var buf bytes.Buffer
+	WriteViolations(&buf, violations)
+	assert.Contains(t, buf.String(), "Policy check failed: 1 dependencies")
+	assert.Contains(t, buf.String(), "✗ github.com/example/old@v2.0.0: outdated-direct")
+	assert.Contains(t, buf.String(), "! github.com/example/archived@v1.0.0: archived")
+
+	buf.Reset()
+	WriteViolations(&buf, violations[:1])
+	assert.Contains(t, buf.String(), "Policy check passed with warnings: 1 dependencies meet a warn rule")
+}
+
+func TestWriteViolations(t *testing.T) {
+	var buf bytes.Buffer
+	WriteViolations(&buf, nil)
+	assert.Contains(t, buf.String(), "Policy check passed")
+
+	buf.Reset()
+	WriteViolations(&buf, []Violation{{
+		Dependency: scanner.Dependency{Path: "github.com/example/stale", Version: "v0.1.0"},
+		Conditions: []string{"inactive", "score<50"},
+	}})
+	assert.Contains(t, buf.String(), "Policy check failed: 1 dependencies")
+	assert.Contains(t, buf.String(), "github.com/example/stale@v0.1.0: inactive, score<50")
+}
@@ -0,0 +1,64 @@
+// Package progress renders the progress of a running scan to a terminal
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// width is the number of characters of the bar itself
+const width = 30
+
+// Bar redraws a single status line with the number of scanned
+// dependencies, the last scanned module and the estimated remaining time
+type Bar struct {
+	w     io.Writer
+	start time.Time
+	now   func() time.Time
+	mutex sync.Mutex
+}
+
+// NewBar creates a bar writing to w, usually os.Stderr
+func NewBar(w io.Writer) *Bar {
+	return &Bar{w: w, start: time.Now(), now: time.Now}
+}
+
+// Update redraws the bar. The line is cleared once all dependencies are
+// done, so following output starts on a clean line.
+func (b *Bar) Update(done, total int, current string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if total <= 0 || done >= total {
+		fmt.Fprint(b.w, "\r\033[K")
+		return
+	}
+
+	filled := done * width / total
+	bar := strings.Repeat("#", filled) + strings.Repeat(".", width-filled)
+	fmt.Fprintf(b.w, "\r\033[K[%s] %d/%d%s %s", bar, done, total, b.eta(done, total), current)
+}
+
+// eta extrapolates the remaining time from the average time per dependency
+func (b *Bar) eta(done, total int) string {
+	if done == 0 {
+		return ""
+	}
+	elapsed := b.now().Sub(b.start)
+	remaining := elapsed / time.Duration(done) * time.Duration(total-done)
+	return fmt.Sprintf(" ETA %s", remaining.Round(time.Second))
+}
+
+// IsTerminal reports whether f is an interactive terminal. Progress is
+// suppressed if stderr is redirected, e.g. in CI logs.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
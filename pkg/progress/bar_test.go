@@ -0,0 +1,42 @@
+package progress
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBar(t *testing.T) {
+	var out bytes.Buffer
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bar := NewBar(&out)
+	bar.start = start
+	bar.now = func() time.Time { return start.Add(10 * time.Second) }
+
+	bar.Update(0, 4, "")
+	assert.Equal(t, "\r\033[K["+dots(30)+"] 0/4 ", out.String())
+
+	out.Reset()
+	bar.Update(1, 4, "golang.org/x/mod")
+	assert.Contains(t, out.String(), "] 1/4 ETA 30s golang.org/x/mod")
+	assert.Contains(t, out.String(), "[#######.......")
+
+	out.Reset()
+	bar.Update(4, 4, "golang.org/x/text")
+	assert.Equal(t, "\r\033[K", out.String(), "the line is cleared when done")
+}
+
+func TestIsTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	assert.False(t, IsTerminal(file))
+}
+
+func dots(n int) string {
+	return string(bytes.Repeat([]byte("."), n))
+}
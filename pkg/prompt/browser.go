@@ -0,0 +1,346 @@
+// Package prompt browses scan results at an interactive command prompt
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/semver"
+)
+
+// Columns are the sortable columns of the dependency table
+var Columns = []string{"path", "version", "status", "age", "latest", "score"}
+
+// Filters are the status filters of the dependency table
+var Filters = []string{"all", "active", "inactive", "acknowledged", "unknown", "outdated", "vulnerable", "error"}
+
+var filterFuncs = map[string]func(dep scanner.Dependency) bool{
+	"all":          func(scanner.Dependency) bool { return true },
+	"active":       func(dep scanner.Dependency) bool { return !dep.IsInactive() && !dep.Status.IsUnknown() },
+	"inactive":     func(dep scanner.Dependency) bool { return dep.IsInactive() && !dep.IsAcknowledged },
+	"acknowledged": func(dep scanner.Dependency) bool { return dep.IsAcknowledged },
+	"unknown":      func(dep scanner.Dependency) bool { return dep.Status == scanner.StatusUnknown },
+	"outdated":     func(dep scanner.Dependency) bool { return dep.Update != "" },
+	"vulnerable":   func(dep scanner.Dependency) bool { return len(dep.Vulnerabilities) > 0 },
+	"error":        func(dep scanner.Dependency) bool { return dep.Error != nil },
+}
+
+const help = `Commands:
+  <n>, show <n>         show the details of dependency n
+  sort <column> [desc]  sort by path, version, status, age, latest or score
+  filter <status>       show all, active, inactive, acknowledged, unknown,
+                        outdated, vulnerable or error dependencies
+  list                  show the dependency table again
+  help                  show this help
+  quit                  leave the browser
+`
+
+// Browser is an interactive, line based command prompt for browsing a scan
+// result. It works on any terminal and in pipes, so sessions can be
+// scripted as well.
+type Browser struct {
+	result     *scanner.ScanResult
+	sortColumn string
+	descending bool
+	filter     string
+	// visible are the dependencies of the table in display order
+	visible []scanner.Dependency
+}
+
+// NewBrowser creates a browser showing all dependencies, least healthy first
+func NewBrowser(result *scanner.ScanResult) *Browser {
+	b := &Browser{result: result, sortColumn: "score", filter: "all"}
+	b.refresh()
+	return b
+}
+
+// Run shows the dependency table and executes commands read from in until
+// quit or the end of input
+func (b *Browser) Run(in io.Reader, out io.Writer) error {
+	b.writeTable(out)
+
+	lines := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "govital> ")
+		if !lines.Scan() {
+			fmt.Fprintln(out)
+			return lines.Err()
+		}
+		if quit := b.Execute(out, lines.Text()); quit {
+			return nil
+		}
+	}
+}
+
+// Execute runs a single command and reports whether the browser should quit
+func (b *Browser) Execute(out io.Writer, line string) bool {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 0 {
+		return false
+	}
+
+	if _, err := strconv.Atoi(fields[0]); err == nil {
+		fields = append([]string{"show"}, fields...)
+	}
+
+	var err error
+	switch fields[0] {
+	case "quit", "q", "exit":
+		return true
+	case "help", "?":
+		fmt.Fprint(out, help)
+	case "list", "ls":
+		b.writeTable(out)
+	case "show":
+		err = b.show(out, fields[1:])
+	case "sort":
+		err = b.sort(fields[1:])
+		if err == nil {
+			b.writeTable(out)
+		}
+	case "filter":
+		err = b.setFilter(fields[1:])
+		if err == nil {
+			b.writeTable(out)
+		}
+	default:
+		err = fmt.Errorf("unknown command %q, type help for all commands", fields[0])
+	}
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", err)
+	}
+	return false
+}
+
+func (b *Browser) show(out io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: show <n>")
+	}
+	index, err := strconv.Atoi(args[0])
+	if err != nil || index < 1 || index > len(b.visible) {
+		return fmt.Errorf("no dependency %s, expected 1 to %d", args[0], len(b.visible))
+	}
+	writeDetails(out, b.visible[index-1])
+	return nil
+}
+
+func (b *Browser) sort(args []string) error {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && args[1] != "desc" && args[1] != "asc") {
+		return fmt.Errorf("usage: sort <column> [desc]")
+	}
+	if !contains(Columns, args[0]) {
+		return fmt.Errorf("unknown column %q, expected one of %s", args[0], strings.Join(Columns, ", "))
+	}
+	b.sortColumn = args[0]
+	b.descending = len(args) == 2 && args[1] == "desc"
+	b.refresh()
+	return nil
+}
+
+func (b *Browser) setFilter(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: filter <status>")
+	}
+	if !contains(Filters, args[0]) {
+		return fmt.Errorf("unknown filter %q, expected one of %s", args[0], strings.Join(Filters, ", "))
+	}
+	b.filter = args[0]
+	b.refresh()
+	return nil
+}
+
+// refresh applies the filter and the sort order to the dependencies
+func (b *Browser) refresh() {
+	matches := filterFuncs[b.filter]
+	b.visible = b.visible[:0]
+	for _, dep := range b.result.Dependencies {
+		if matches(dep) {
+			b.visible = append(b.visible, dep)
+		}
+	}
+
+	if b.sortColumn == "score" {
+		// Keep the least healthy first order of the reports
+		scanner.SortByScore(b.visible)
+		if b.descending {
+			reverse(b.visible)
+		}
+		return
+	}
+
+	less := lessFuncs[b.sortColumn]
+	sort.SliceStable(b.visible, func(i, j int) bool {
+		if b.descending {
+			return less(b.visible[j], b.visible[i])
+		}
+		return less(b.visible[i], b.visible[j])
+	})
+}
+
+var lessFuncs = map[string]func(a, b scanner.Dependency) bool{
+	"path":    func(a, b scanner.Dependency) bool { return a.Path < b.Path },
+	"version": func(a, b scanner.Dependency) bool { return semver.Compare(a.Version, b.Version) < 0 },
+	"status":  func(a, b scanner.Dependency) bool { return status(a) < status(b) },
+	"age":     func(a, b scanner.Dependency) bool { return a.DaysSinceLastRelease < b.DaysSinceLastRelease },
+	"latest":  func(a, b scanner.Dependency) bool { return a.LatestReleaseTime.Before(b.LatestReleaseTime) },
+}
+
+func (b *Browser) writeTable(out io.Writer) {
+	direction := "ascending"
+	if b.descending {
+		direction = "descending"
+	}
+	fmt.Fprintf(out, "\n%s: %d of %d dependencies (filter: %s, sorted by %s %s)\n\n",
+		b.result.ProjectPath, len(b.visible), len(b.result.Dependencies), b.filter, b.sortColumn, direction)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tPATH\tVERSION\tSTATUS\tAGE\tLATEST\tSCORE")
+	for i, dep := range b.visible {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, dep.Path, dep.Version, status(dep), age(dep), latest(dep), scoreText(dep))
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\nType a number for details or help for all commands.\n")
+}
+
+func writeDetails(out io.Writer, dep scanner.Dependency) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "\n%s@%s\n", dep.Path, dep.Version)
+	fmt.Fprintf(w, "  Status:\t%s\n", status(dep))
+	if dep.IsIndirect {
+		fmt.Fprintf(w, "  Kind:\tindirect\n")
+	} else {
+		fmt.Fprintf(w, "  Kind:\tdirect\n")
+	}
+	if dep.Module != "" {
+		fmt.Fprintf(w, "  Required by:\t%s\n", dep.Module)
+	}
+	if dep.Replace != nil {
+		fmt.Fprintf(w, "  Replaced by:\t%s\n", strings.TrimSuffix(dep.Replace.Path+"@"+dep.Replace.Version, "@"))
+	}
+	if len(dep.IntroducedBy) > 0 {
+		fmt.Fprintf(w, "  Introduced by:\t%s\n", strings.Join(dep.IntroducedBy, " > "))
+	}
+	fmt.Fprintf(w, "  Score:\t%s\n", scoreText(dep))
+	fmt.Fprintf(w, "  Used version released:\t%s\n", releaseText(dep.LastReleaseTime, dep.DaysSinceLastRelease))
+
+	// Release history
+	latestVersion := dep.Latest
+	if latestVersion == "" {
+		latestVersion = dep.Version
+	}
+	fmt.Fprintf(w, "  Latest version:\t%s\n", latestVersion)
+	if !dep.LatestReleaseTime.IsZero() {
+		days := int(time.Since(dep.LatestReleaseTime).Hours() / 24)
+		fmt.Fprintf(w, "  Latest release:\t%s\n", releaseText(dep.LatestReleaseTime, days))
+		fmt.Fprintf(w, "  Releases last year:\t%d\n", dep.ReleasesLastYear)
+	}
+	if dep.Update != "" {
+		fmt.Fprintf(w, "  Update available:\t%s\n", dep.Update)
+	}
+
+	// Repository metadata
+	if dep.Repository != "" {
+		fmt.Fprintf(w, "  Repository:\t%s\n", dep.Repository)
+	}
+	if len(dep.Owners) > 0 {
+		fmt.Fprintf(w, "  Owners:\t%s\n", strings.Join(dep.Owners, ", "))
+	}
+	if len(dep.Licenses) > 0 {
+		fmt.Fprintf(w, "  Licenses:\t%s\n", strings.Join(dep.Licenses, ", "))
+	}
+	if dep.LicenseChange != nil {
+		fmt.Fprintf(w, "  License changed:\t%s\n", dep.LicenseChange)
+	}
+	if dep.Retracted != nil {
+		fmt.Fprintf(w, "  Retracted:\t%s\n", dep.Retracted)
+	}
+	if dep.Deprecated != "" {
+		fmt.Fprintf(w, "  Deprecated:\t%s\n", dep.Deprecated)
+	}
+	if dep.Note != "" {
+		fmt.Fprintf(w, "  Note:\t%s\n", dep.Note)
+	}
+	if dep.Error != nil {
+		fmt.Fprintf(w, "  Error:\t%s\n", dep.Error)
+	}
+	w.Flush()
+
+	if len(dep.Vulnerabilities) > 0 {
+		fmt.Fprintf(out, "  Vulnerabilities (%d):\n", len(dep.Vulnerabilities))
+		for _, v := range dep.Vulnerabilities {
+			fmt.Fprintf(out, "    - %s [%s] %s\n", v.ID, v.Severity, v.Summary)
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// status returns the status label of a dependency. Labels sort from
+// failed to healthy.
+func status(dep scanner.Dependency) string {
+	switch {
+	case dep.Error != nil:
+		return "error"
+	case dep.Replace.IsLocal():
+		return "not checked"
+	case dep.Status == scanner.StatusUnknown:
+		return "unknown"
+	case dep.IsInactive() && !dep.IsAcknowledged:
+		return "inactive"
+	case dep.IsAcknowledged:
+		return "acknowledged"
+	case dep.Update != "":
+		return "outdated"
+	default:
+		return "up to date"
+	}
+}
+
+func age(dep scanner.Dependency) string {
+	if dep.LastReleaseTime.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%dd", dep.DaysSinceLastRelease)
+}
+
+func latest(dep scanner.Dependency) string {
+	if dep.Latest == "" {
+		return "-"
+	}
+	return dep.Latest
+}
+
+func scoreText(dep scanner.Dependency) string {
+	if dep.Score == nil {
+		return "-"
+	}
+	return strconv.Itoa(*dep.Score)
+}
+
+func releaseText(t time.Time, days int) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%d days ago)", t.Format("2006-01-02"), days)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(deps []scanner.Dependency) {
+	for i, j := 0, len(deps)-1; i < j; i, j = i+1, j-1 {
+		deps[i], deps[j] = deps[j], deps[i]
+	}
+}
@@ -0,0 +1,111 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func testResult() *scanner.ScanResult {
+	return &scanner.ScanResult{
+		ProjectPath: "/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true, Score: intPtr(90), DaysSinceLastRelease: 10,
+				LastReleaseTime: time.Now().AddDate(0, 0, -10), Repository: "https://github.com/example/healthy", Owners: []string{"team-a"}},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Score: intPtr(20), DaysSinceLastRelease: 700, Update: "v0.2.0", Latest: "v0.2.0",
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001", Severity: "HIGH", Summary: "Something bad"}}},
+			{Path: "github.com/example/broken", Version: "v1.0.0", Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "not found"}},
+		},
+	}
+}
+
+func TestBrowserDefaultOrder(t *testing.T) {
+	b := NewBrowser(testResult())
+
+	require.Len(t, b.visible, 3)
+	assert.Equal(t, "github.com/example/stale", b.visible[0].Path)
+	assert.Equal(t, "github.com/example/healthy", b.visible[1].Path)
+	assert.Equal(t, "github.com/example/broken", b.visible[2].Path, "unscored dependencies go last")
+}
+
+func TestBrowserSortAndFilter(t *testing.T) {
+	b := NewBrowser(testResult())
+	var out bytes.Buffer
+
+	b.Execute(&out, "sort path")
+	assert.Equal(t, "github.com/example/broken", b.visible[0].Path)
+
+	b.Execute(&out, "sort age desc")
+	assert.Equal(t, "github.com/example/stale", b.visible[0].Path)
+
+	b.Execute(&out, "filter vulnerable")
+	require.Len(t, b.visible, 1)
+	assert.Equal(t, "github.com/example/stale", b.visible[0].Path)
+
+	b.Execute(&out, "filter error")
+	require.Len(t, b.visible, 1)
+	assert.Equal(t, "github.com/example/broken", b.visible[0].Path)
+
+	out.Reset()
+	b.Execute(&out, "sort size")
+	assert.Contains(t, out.String(), `unknown column "size"`)
+	b.Execute(&out, "filter broken")
+	assert.Contains(t, out.String(), `unknown filter "broken"`)
+}
+
+func TestBrowserSortByVersion(t *testing.T) {
+	b := NewBrowser(&scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/a", Version: "v1.10.0"},
+		{Path: "github.com/example/b", Version: "v1.9.0"},
+		{Path: "github.com/example/c", Version: "v1.10.0-rc.1"},
+		{Path: "github.com/example/d", Version: "v0.0.0-20240101000000-abcdefabcdef"},
+	}})
+	var out bytes.Buffer
+
+	b.Execute(&out, "sort version")
+	var paths []string
+	for _, dep := range b.visible {
+		paths = append(paths, dep.Path)
+	}
+	assert.Equal(t, []string{"github.com/example/d", "github.com/example/b", "github.com/example/c", "github.com/example/a"}, paths)
+}
+
+func TestBrowserDetails(t *testing.T) {
+	b := NewBrowser(testResult())
+	var out bytes.Buffer
+
+	b.Execute(&out, "1")
+	assert.Contains(t, out.String(), "github.com/example/stale@v0.1.0")
+	assert.Contains(t, out.String(), "Update available:")
+	assert.Contains(t, out.String(), "GO-2024-0001 [HIGH] Something bad")
+
+	out.Reset()
+	b.Execute(&out, "show 2")
+	assert.Contains(t, out.String(), "https://github.com/example/healthy")
+	assert.Contains(t, out.String(), "team-a")
+
+	out.Reset()
+	b.Execute(&out, "show 4")
+	assert.Contains(t, out.String(), "no dependency 4, expected 1 to 3")
+}
+
+func TestBrowserRun(t *testing.T) {
+	var out bytes.Buffer
+	err := NewBrowser(testResult()).Run(strings.NewReader("help\nfilter inactive\nquit\nshow 1\n"), &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "3 of 3 dependencies (filter: all, sorted by score ascending)")
+	assert.Contains(t, out.String(), "Commands:")
+	assert.Contains(t, out.String(), "1 of 3 dependencies (filter: inactive", "failed lookups are not inactive")
+	assert.NotContains(t, out.String(), "Latest version:", "commands after quit are not executed")
+}
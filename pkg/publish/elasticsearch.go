@@ -0,0 +1,178 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// DefaultIndex is the index dependency documents are written to if none
+// is configured
+const DefaultIndex = "govital-dependencies"
+
+// bulkSize is the number of documents sent per bulk request
+const bulkSize = 500
+
+// ElasticsearchConfig configures the Elasticsearch or OpenSearch endpoint
+type ElasticsearchConfig struct {
+	URL   string `mapstructure:"url"`
+	Index string `mapstructure:"index"`
+	// Username and Password enable basic authentication
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// APIKey is the base64 encoded Elasticsearch API key. It takes
+	// precedence over basic authentication.
+	APIKey string `mapstructure:"api_key"`
+}
+
+// Elasticsearch bulk-indexes one document per scanned dependency, so the
+// dependency health of many projects can be analyzed with Kibana or
+// OpenSearch Dashboards
+type Elasticsearch struct {
+	config     ElasticsearchConfig
+	httpClient *http.Client
+	// now returns the timestamp of the published documents
+	now func() time.Time
+}
+
+// DependencyDocument is the indexed document of a single dependency
+type DependencyDocument struct {
+	Timestamp   time.Time `json:"@timestamp"`
+	ProjectPath string    `json:"project_path"`
+	// Project is the module path of the scanned project, if known
+	Project  string `json:"project,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	scanner.Dependency
+	// Vulnerable is set for easier filtering than on the vulnerability list
+	Vulnerable bool `json:"vulnerable"`
+}
+
+// NewElasticsearch creates a publisher for the configured endpoint
+func NewElasticsearch(config ElasticsearchConfig) (*Elasticsearch, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("elasticsearch URL is not configured")
+	}
+	if config.Index == "" {
+		config.Index = DefaultIndex
+	}
+	return &Elasticsearch{
+		config:     config,
+		httpClient: &http.Client{},
+		now:        time.Now,
+	}, nil
+}
+
+// Publish indexes all dependencies of the result. Documents rejected by
+// the cluster are reported as error after all batches were sent.
+func (e *Elasticsearch) Publish(ctx context.Context, result *scanner.ScanResult) error {
+	documents := Documents(result, e.now())
+
+	var rejected []string
+	for start := 0; start < len(documents); start += bulkSize {
+		end := min(start+bulkSize, len(documents))
+		failures, err := e.bulk(ctx, documents[start:end])
+		if err != nil {
+			return err
+		}
+		rejected = append(rejected, failures...)
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf("elasticsearch rejected %d of %d documents: %s", len(rejected), len(documents), rejected[0])
+	}
+	return nil
+}
+
+// Documents converts a scan result to one document per dependency
+func Documents(result *scanner.ScanResult, timestamp time.Time) []DependencyDocument {
+	documents := make([]DependencyDocument, len(result.Dependencies))
+	for i, dep := range result.Dependencies {
+		documents[i] = DependencyDocument{
+			Timestamp:   timestamp.UTC(),
+			ProjectPath: result.ProjectPath,
+			Dependency:  dep,
+			Vulnerable:  len(dep.Vulnerabilities) > 0,
+		}
+		if result.Fingerprint != nil {
+			documents[i].Project = result.Fingerprint.Module
+			documents[i].Revision = result.Fingerprint.Revision
+		}
+	}
+	return documents
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends the documents in a single bulk request and returns the
+// reasons of rejected documents
+func (e *Elasticsearch) bulk(ctx context.Context, documents []DependencyDocument) ([]string, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	action := map[string]map[string]string{"index": {"_index": e.config.Index}}
+	for _, document := range documents {
+		if err := encoder.Encode(action); err != nil {
+			return nil, fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("failed to encode document for %s: %w", document.Path, err)
+		}
+	}
+
+	url := strings.TrimSuffix(e.config.URL, "/") + "/_bulk"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bulk request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	switch {
+	case e.config.APIKey != "":
+		request.Header.Set("Authorization", "ApiKey "+e.config.APIKey)
+	case e.config.Username != "":
+		request.SetBasicAuth(e.config.Username, e.config.Password)
+	}
+
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("bulk request to %s failed: %w", e.config.URL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, fmt.Errorf("bulk request to %s returned status %d: %s", e.config.URL, response.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result bulkResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil, nil
+	}
+
+	var rejected []string
+	for i, item := range result.Items {
+		for _, outcome := range item {
+			if outcome.Error != nil && i < len(documents) {
+				rejected = append(rejected, fmt.Sprintf("%s: %s: %s", documents[i].Path, outcome.Error.Type, outcome.Error.Reason))
+			}
+		}
+	}
+	return rejected, nil
+}
@@ -0,0 +1,141 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResult(count int) *scanner.ScanResult {
+	result := &scanner.ScanResult{
+		ProjectPath: "/src/service",
+		Fingerprint: &scanner.Fingerprint{Module: "example.com/service", Revision: "abc123"},
+	}
+	for i := 0; i < count; i++ {
+		result.Dependencies = append(result.Dependencies, scanner.Dependency{
+			Path:    fmt.Sprintf("github.com/example/dep%d", i),
+			Version: "v1.0.0",
+		})
+	}
+	return result
+}
+
+// bulkServer records the indexed documents and rejects documents of the
+// given module paths
+func bulkServer(t *testing.T, documents *[]map[string]any, reject map[string]bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, "ApiKey secret", r.Header.Get("Authorization"))
+
+		errors := false
+		var items []map[string]any
+		lines := bufio.NewScanner(r.Body)
+		for lines.Scan() {
+			var action map[string]map[string]string
+			require.NoError(t, json.Unmarshal(lines.Bytes(), &action))
+			assert.Equal(t, "deps", action["index"]["_index"])
+
+			require.True(t, lines.Scan())
+			var document map[string]any
+			require.NoError(t, json.Unmarshal(lines.Bytes(), &document))
+			*documents = append(*documents, document)
+
+			outcome := map[string]any{"status": http.StatusCreated}
+			if reject[document["path"].(string)] {
+				errors = true
+				outcome = map[string]any{
+					"status": http.StatusBadRequest,
+					"error":  map[string]string{"type": "mapper_parsing_exception", "reason": "failed to parse"},
+				}
+			}
+			items = append(items, map[string]any{"index": outcome})
+		}
+		response := map[string]any{"errors": errors, "items": items}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func TestElasticsearchPublish(t *testing.T) {
+	var documents []map[string]any
+	server := bulkServer(t, &documents, nil)
+	defer server.Close()
+
+	publisher, err := NewElasticsearch(ElasticsearchConfig{URL: server.URL + "/", Index: "deps", APIKey: "secret"})
+	require.NoError(t, err)
+	publisher.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	err = publisher.Publish(context.Background(), testResult(bulkSize+1))
+
+	require.NoError(t, err)
+	require.Len(t, documents, bulkSize+1)
+	assert.Equal(t, "github.com/example/dep0", documents[0]["path"])
+	assert.Equal(t, "v1.0.0", documents[0]["version"])
+	assert.Equal(t, "example.com/service", documents[0]["project"])
+	assert.Equal(t, "abc123", documents[0]["revision"])
+	assert.Equal(t, "/src/service", documents[0]["project_path"])
+	assert.Equal(t, "2024-05-01T12:00:00Z", documents[0]["@timestamp"])
+}
+
+func TestElasticsearchPublishRejected(t *testing.T) {
+	var documents []map[string]any
+	server := bulkServer(t, &documents, map[string]bool{"github.com/example/dep1": true})
+	defer server.Close()
+
+	publisher, err := NewElasticsearch(ElasticsearchConfig{URL: server.URL, Index: "deps", APIKey: "secret"})
+	require.NoError(t, err)
+
+	err = publisher.Publish(context.Background(), testResult(3))
+
+	assert.ErrorContains(t, err, "rejected 1 of 3 documents: github.com/example/dep1: mapper_parsing_exception")
+}
+
+func TestElasticsearchPublishStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "elastic", user)
+		assert.Equal(t, "changeme", password)
+		http.Error(w, "security_exception", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	publisher, err := NewElasticsearch(ElasticsearchConfig{URL: server.URL, Username: "elastic", Password: "changeme"})
+	require.NoError(t, err)
+
+	err = publisher.Publish(context.Background(), testResult(1))
+
+	assert.ErrorContains(t, err, "returned status 401: security_exception")
+}
+
+func TestNewElasticsearch(t *testing.T) {
+	_, err := NewElasticsearch(ElasticsearchConfig{})
+	assert.Error(t, err)
+
+	publisher, err := NewElasticsearch(ElasticsearchConfig{URL: "http://localhost:9200"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultIndex, publisher.config.Index)
+}
+
+func TestDocuments(t *testing.T) {
+	result := testResult(1)
+	result.Dependencies[0].Vulnerabilities = []vuln.Vulnerability{{ID: "GO-2024-0001"}}
+	result.Fingerprint = nil
+
+	documents := Documents(result, time.Now())
+
+	require.Len(t, documents, 1)
+	assert.True(t, documents[0].Vulnerable)
+	assert.Empty(t, documents[0].Project)
+}
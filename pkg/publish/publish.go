@@ -0,0 +1,12 @@
+package publish
+
+import (
+	"context"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Publisher sends scan results to an external system
+type Publisher interface {
+	Publish(ctx context.Context, result *scanner.ScanResult) error
+}
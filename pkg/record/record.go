@@ -0,0 +1,209 @@
+// Package record captures the upstream responses of a scan and replays
+// them, so a scan can be reproduced exactly for audits and tests
+package record
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Inputs are the recorded upstream responses of a scan
+type Inputs struct {
+	// RecordedAt is the reference time of the scan, release ages are
+	// computed relative to it on replay
+	RecordedAt time.Time `json:"recorded_at"`
+	// Fingerprint identifies the scanned source state
+	Fingerprint *scanner.Fingerprint `json:"fingerprint,omitempty"`
+	Exchanges   []Exchange           `json:"exchanges"`
+}
+
+// Exchange is a request with the response or transport error it got.
+// Request headers are not recorded, they may hold credentials.
+type Exchange struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// BodyHash is the SHA-256 of the request body, empty without body
+	BodyHash   string      `json:"body_hash,omitempty"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+func (e Exchange) key() string {
+	return e.Method + " " + e.URL + " " + e.BodyHash
+}
+
+// Load reads inputs written by Recorder.Save
+func Load(path string) (*Inputs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded inputs: %w", err)
+	}
+	var inputs Inputs
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("failed to parse recorded inputs %s: %w", path, err)
+	}
+	return &inputs, nil
+}
+
+// Recorder is an http.RoundTripper recording all exchanges with next
+type Recorder struct {
+	next       http.RoundTripper
+	recordedAt time.Time
+
+	mutex     sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder records the exchanges of a scan started at recordedAt
+func NewRecorder(next http.RoundTripper, recordedAt time.Time) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, recordedAt: recordedAt}
+}
+
+// RoundTrip forwards the request and records its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange, err := newExchange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = err.Error()
+		r.add(exchange)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", req.URL, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.StatusCode = resp.StatusCode
+	exchange.Header = resp.Header.Clone()
+	exchange.Body = body
+	r.add(exchange)
+	return resp, nil
+}
+
+func (r *Recorder) add(exchange Exchange) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+// Save writes the recorded exchanges with the fingerprint of the scan
+func (r *Recorder) Save(path string, fingerprint *scanner.Fingerprint) error {
+	r.mutex.Lock()
+	inputs := Inputs{RecordedAt: r.recordedAt.UTC(), Fingerprint: fingerprint, Exchanges: r.exchanges}
+	data, err := json.MarshalIndent(inputs, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded inputs: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write recorded inputs: %w", err)
+	}
+	return nil
+}
+
+// ErrNotRecorded is returned on replay for requests without recording
+var ErrNotRecorded = errors.New("request was not recorded")
+
+// Replayer is an http.RoundTripper answering requests with recorded
+// exchanges instead of the network. Repeated requests get the recorded
+// responses in order, the last one is repeated once all were served.
+type Replayer struct {
+	mutex     sync.Mutex
+	exchanges map[string][]Exchange
+	served    map[string]int
+}
+
+// NewReplayer replays the exchanges of the inputs
+func NewReplayer(inputs *Inputs) *Replayer {
+	r := &Replayer{exchanges: make(map[string][]Exchange), served: make(map[string]int)}
+	for _, exchange := range inputs.Exchanges {
+		r.exchanges[exchange.key()] = append(r.exchanges[exchange.key()], exchange)
+	}
+	return r
+}
+
+// RoundTrip returns the recorded response of the request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	lookup, err := newExchange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	recorded := r.exchanges[lookup.key()]
+	index := min(r.served[lookup.key()], len(recorded)-1)
+	r.served[lookup.key()]++
+	r.mutex.Unlock()
+
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+	}
+	exchange := recorded[index]
+	if exchange.Error != "" {
+		return nil, errors.New(exchange.Error)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.StatusCode, http.StatusText(exchange.StatusCode)),
+		StatusCode:    exchange.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        exchange.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(exchange.Body)),
+		ContentLength: int64(len(exchange.Body)),
+		Request:       req,
+	}, nil
+}
+
+// newExchange identifies the request by method, URL and body. The body is
+// restored for the next transport.
+func newExchange(req *http.Request) (Exchange, error) {
+	exchange := Exchange{Method: req.Method, URL: req.URL.String()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return exchange, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return Exchange{}, fmt.Errorf("failed to read request body of %s: %w", req.URL, err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		exchange.BodyHash = hex.EncodeToString(sum[:])
+	}
+	return exchange, nil
+}
+
+// CheckSource returns an error if the recorded scan was made from another
+// go.mod, whose dependencies need other responses than were recorded
+func (i *Inputs) CheckSource(fingerprint *scanner.Fingerprint) error {
+	if i.Fingerprint == nil || fingerprint == nil || i.Fingerprint.GoModSHA256 == fingerprint.GoModSHA256 {
+		return nil
+	}
+	return fmt.Errorf("the inputs were recorded for another go.mod (sha256 %s, now %s)", i.Fingerprint.GoModSHA256, fingerprint.GoModSHA256)
+}
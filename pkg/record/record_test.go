@@ -0,0 +1,81 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "%s %s %s #%d", r.Method, r.URL.Path, body, calls)
+	}))
+
+	recordedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	recorder := NewRecorder(nil, recordedAt)
+	client := &http.Client{Transport: recorder}
+	get := func(client *http.Client, url string) string {
+		response, err := client.Get(url)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "GET /a  #1", get(client, server.URL+"/a"))
+	assert.Equal(t, "GET /a  #2", get(client, server.URL+"/a"))
+	response, err := client.Post(server.URL+"/query", "application/json", strings.NewReader(`{"q":1}`))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	path := filepath.Join(t.TempDir(), "inputs.json")
+	require.NoError(t, recorder.Save(path, &scanner.Fingerprint{Module: "example.com/app"}))
+	server.Close()
+
+	inputs, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, recordedAt, inputs.RecordedAt)
+	assert.Equal(t, "example.com/app", inputs.Fingerprint.Module)
+	require.Len(t, inputs.Exchanges, 3)
+
+	client = &http.Client{Transport: NewReplayer(inputs)}
+	assert.Equal(t, "GET /a  #1", get(client, server.URL+"/a"))
+	assert.Equal(t, "GET /a  #2", get(client, server.URL+"/a"))
+	assert.Equal(t, "GET /a  #2", get(client, server.URL+"/a"), "last response is repeated")
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/query", strings.NewReader(`{"q":1}`))
+	require.NoError(t, err)
+	response, err = client.Do(request)
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, `POST /query {"q":1} #3`, string(body))
+	assert.Equal(t, "text/plain", response.Header.Get("Content-Type"))
+
+	_, err = client.Post(server.URL+"/query", "application/json", strings.NewReader(`{"q":2}`))
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestCheckSource(t *testing.T) {
+	inputs := &Inputs{Fingerprint: &scanner.Fingerprint{GoModSHA256: "a"}}
+
+	assert.NoError(t, inputs.CheckSource(&scanner.Fingerprint{GoModSHA256: "a"}))
+	assert.NoError(t, inputs.CheckSource(nil))
+	assert.ErrorContains(t, inputs.CheckSource(&scanner.Fingerprint{GoModSHA256: "b"}), "another go.mod")
+}
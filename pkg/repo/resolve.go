@@ -0,0 +1,210 @@
+package repo
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Repository is the source repository hosting a module
+type Repository struct {
+	// Root is the import path prefix corresponding to the repository root
+	Root string `json:"root"`
+	VCS  string `json:"vcs"`
+	// URL is the clone URL of the repository
+	URL string `json:"url"`
+}
+
+// Host returns the host name of the repository URL, e.g. github.com
+func (r Repository) Host() string {
+	parsed, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// knownHosts are code hosts whose import paths map to repositories
+// without a lookup: host/owner/name
+var knownHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// Resolver maps module paths to their source repositories. Vanity import
+// paths like golang.org/x/mod or gopkg.in/yaml.v3 are resolved with the
+// go-import meta tag served for ?go-get=1, like the go command does.
+// Results are cached per repository root.
+type Resolver struct {
+	HTTPClient *http.Client
+
+	mutex sync.Mutex
+	cache map[string]Repository
+}
+
+// NewResolver creates a resolver with an empty cache
+func NewResolver(client *http.Client) *Resolver {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Resolver{HTTPClient: client, cache: make(map[string]Repository)}
+}
+
+// Resolve returns the repository of the module path
+func (r *Resolver) Resolve(ctx context.Context, modulePath string) (Repository, error) {
+	if repository, ok := knownHostRepository(modulePath); ok {
+		return repository, nil
+	}
+	if repository, ok := r.cached(modulePath); ok {
+		return repository, nil
+	}
+
+	repository, err := r.discover(ctx, modulePath)
+	if err != nil {
+		return Repository{}, err
+	}
+
+	r.mutex.Lock()
+	r.cache[repository.Root] = repository
+	r.mutex.Unlock()
+	return repository, nil
+}
+
+// Known returns the repository of the module path if it is known without
+// a network lookup: on the known code hosts or resolved before
+func (r *Resolver) Known(modulePath string) (Repository, bool) {
+	if repository, ok := knownHostRepository(modulePath); ok {
+		return repository, true
+	}
+	return r.cached(modulePath)
+}
+
+// cached returns the repository of a cached root the module path is part of
+func (r *Resolver) cached(modulePath string) (Repository, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for prefix := modulePath; prefix != "."; prefix = pathDir(prefix) {
+		if repository, ok := r.cache[prefix]; ok {
+			return repository, true
+		}
+	}
+	return Repository{}, false
+}
+
+func knownHostRepository(modulePath string) (Repository, bool) {
+	parts := strings.Split(modulePath, "/")
+	if len(parts) < 3 {
+		return Repository{}, false
+	}
+	for _, host := range knownHosts {
+		if parts[0] == host {
+			root := strings.Join(parts[:3], "/")
+			return Repository{Root: root, VCS: "git", URL: "https://" + root}, true
+		}
+	}
+	return Repository{}, false
+}
+
+// discover fetches the go-import meta tags of the module path
+func (r *Resolver) discover(ctx context.Context, modulePath string) (Repository, error) {
+	requestURL := "https://" + modulePath + "?go-get=1"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to create request for %s: %w", requestURL, err)
+	}
+
+	response, err := r.HTTPClient.Do(request)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to resolve %s: %w", modulePath, err)
+	}
+	defer response.Body.Close()
+
+	// Like the go command, meta tags are also accepted from error pages
+	imports, err := parseMetaGoImports(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to parse go-import meta tags of %s: %w", modulePath, err)
+	}
+	return matchGoImport(imports, modulePath)
+}
+
+// matchGoImport picks the meta tag whose prefix matches the module path.
+// "mod" entries only point to a module proxy and are skipped.
+func matchGoImport(imports []Repository, modulePath string) (Repository, error) {
+	var match *Repository
+	for i := range imports {
+		candidate := imports[i]
+		if candidate.VCS == "mod" {
+			continue
+		}
+		if modulePath != candidate.Root && !strings.HasPrefix(modulePath, candidate.Root+"/") {
+			continue
+		}
+		if match != nil {
+			return Repository{}, fmt.Errorf("multiple go-import meta tags match %s", modulePath)
+		}
+		match = &candidate
+	}
+	if match == nil {
+		return Repository{}, fmt.Errorf("no go-import meta tag found for %s", modulePath)
+	}
+	return *match, nil
+}
+
+// parseMetaGoImports returns the go-import meta tags of an HTML document.
+// It is lenient like the parser of the go command and stops at the body.
+func parseMetaGoImports(r io.Reader) ([]Repository, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var imports []Repository
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			if err == io.EOF || len(imports) > 0 {
+				return imports, nil
+			}
+			return nil, err
+		}
+
+		if end, ok := token.(xml.EndElement); ok && strings.EqualFold(end.Name.Local, "head") {
+			return imports, nil
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(start.Name.Local, "body") {
+			return imports, nil
+		}
+		if !strings.EqualFold(start.Name.Local, "meta") || attrValue(start.Attr, "name") != "go-import" {
+			continue
+		}
+
+		fields := strings.Fields(attrValue(start.Attr, "content"))
+		if len(fields) == 3 {
+			imports = append(imports, Repository{Root: fields[0], VCS: fields[1], URL: fields[2]})
+		}
+	}
+}
+
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// pathDir returns the parent of an import path, "." for a single element
+func pathDir(importPath string) string {
+	if i := strings.LastIndex(importPath, "/"); i >= 0 {
+		return importPath[:i]
+	}
+	return "."
+}
@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveKnownHost(t *testing.T) {
+	resolver := NewResolver(nil)
+
+	repository, err := resolver.Resolve(context.Background(), "github.com/spf13/cobra/v2")
+
+	require.NoError(t, err)
+	assert.Equal(t, Repository{Root: "github.com/spf13/cobra", VCS: "git", URL: "https://github.com/spf13/cobra"}, repository)
+	assert.Equal(t, "github.com", repository.Host())
+}
+
+func TestKnown(t *testing.T) {
+	resolver := NewResolver(nil)
+
+	repository, ok := resolver.Known("gitlab.com/group/project/sub")
+	require.True(t, ok)
+	assert.Equal(t, "https://gitlab.com/group/project", repository.URL)
+
+	_, ok = resolver.Known("golang.org/x/mod")
+	assert.False(t, ok)
+
+	resolver.cache["golang.org/x/mod"] = Repository{Root: "golang.org/x/mod", VCS: "git", URL: "https://go.googlesource.com/mod"}
+	repository, ok = resolver.Known("golang.org/x/mod/semver")
+	require.True(t, ok)
+	assert.Equal(t, "https://go.googlesource.com/mod", repository.URL)
+}
+
+func TestResolveVanityImport(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "1", r.URL.Query().Get("go-get"))
+		host := r.Host
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<meta http-equiv="Content-Type" content="text/html; charset=utf-8"/>
+<meta name="go-import" content="%s/x/mod mod https://proxy.example.com">
+<meta name="go-import" content="%s/x/mod git https://go.googlesource.com/mod">
+<meta name="go-source" content="%s/x/mod https://github.com/golang/mod/ https://github.com/golang/mod/tree/master{/dir}">
+</head>
+<body>Nothing to see here.</body>
+</html>`, host, host, host)
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(server.Client())
+	modulePath := strings.TrimPrefix(server.URL, "https://") + "/x/mod"
+
+	repository, err := resolver.Resolve(context.Background(), modulePath+"/sumdb")
+	require.NoError(t, err)
+	assert.Equal(t, modulePath, repository.Root)
+	assert.Equal(t, "git", repository.VCS)
+	assert.Equal(t, "https://go.googlesource.com/mod", repository.URL)
+	assert.Equal(t, "go.googlesource.com", repository.Host())
+
+	// Other modules of the same repository are served from the cache
+	_, err = resolver.Resolve(context.Background(), modulePath)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestResolveWithoutMetaTag(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html><body>not found</body></html>", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(server.Client())
+
+	_, err := resolver.Resolve(context.Background(), strings.TrimPrefix(server.URL, "https://")+"/missing")
+
+	assert.ErrorContains(t, err, "no go-import meta tag found")
+}
+
+func TestParseMetaGoImports(t *testing.T) {
+	html := `<html><head>
+<META NAME="go-import" CONTENT="gopkg.in/yaml.v3 git https://gopkg.in/yaml.v3">
+<meta name="go-import" content="invalid">
+<meta name="description" content="yaml">
+</head><body>
+<meta name="go-import" content="ignored git https://example.com/ignored">
+</body></html>`
+
+	imports, err := parseMetaGoImports(strings.NewReader(html))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Repository{{Root: "gopkg.in/yaml.v3", VCS: "git", URL: "https://gopkg.in/yaml.v3"}}, imports)
+}
+
+func TestMatchGoImport(t *testing.T) {
+	imports := []Repository{
+		{Root: "k8s.io/api", VCS: "git", URL: "https://github.com/kubernetes/api"},
+		{Root: "k8s.io/apimachinery", VCS: "git", URL: "https://github.com/kubernetes/apimachinery"},
+	}
+
+	repository, err := matchGoImport(imports, "k8s.io/apimachinery/pkg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/kubernetes/apimachinery", repository.URL)
+
+	_, err = matchGoImport(imports, "k8s.io/client-go")
+	assert.Error(t, err)
+
+	_, err = matchGoImport(append(imports, Repository{Root: "k8s.io/api", VCS: "hg", URL: "https://example.com"}), "k8s.io/api")
+	assert.ErrorContains(t, err, "multiple go-import meta tags")
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("text", "Human readable report (default)", func(opts Options) (Renderer, error) {
+		return RendererFunc(func(w io.Writer, result *scanner.ScanResult) error {
+			return renderText(w, result, opts)
+		}), nil
+	})
+	MustRegister("json", "Full scan result as JSON", func(Options) (Renderer, error) {
+		return RendererFunc(renderJSON), nil
+	})
+}
+
+func renderJSON(w io.Writer, result *scanner.ScanResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
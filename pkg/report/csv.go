@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("csv", "CSV with a row per dependency for spreadsheets", func(Options) (Renderer, error) {
+		return RendererFunc(renderCSV), nil
+	})
+}
+
+// csvHeader names the columns of the CSV report
+var csvHeader = []string{
+	"module", "path", "version", "indirect", "status", "severity", "last_release", "days_since_last_release",
+	"update", "latest", "score", "licenses", "owners", "findings", "error",
+}
+
+// renderCSV writes a header and a row per dependency. Lists like the
+// licenses and findings are joined with semicolons, unknown values are
+// empty.
+func renderCSV(w io.Writer, result *scanner.ScanResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	for _, dep := range result.Dependencies {
+		lastRelease := ""
+		if !dep.LastReleaseTime.IsZero() {
+			lastRelease = dep.LastReleaseTime.Format(time.DateOnly)
+		}
+		score := ""
+		if dep.Score != nil {
+			score = strconv.Itoa(*dep.Score)
+		}
+		errorMessage := ""
+		if dep.Error != nil {
+			errorMessage = dep.Error.String()
+		}
+		row := []string{
+			dep.Module,
+			dep.Path,
+			dep.Version,
+			strconv.FormatBool(dep.IsIndirect),
+			cycloneDXStatus(dep),
+			string(dep.Severity),
+			lastRelease,
+			strconv.Itoa(dep.DaysSinceLastRelease),
+			dep.Update,
+			dep.Latest,
+			score,
+			strings.Join(dep.Licenses, ";"),
+			strings.Join(dep.Owners, ";"),
+			strings.Join(Findings(dep), ";"),
+			errorMessage,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV report: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
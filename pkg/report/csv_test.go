@@ -0,0 +1,44 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCSV(t *testing.T) {
+	score := 42
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true, Score: &score,
+				LastReleaseTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), DaysSinceLastRelease: 30,
+				Licenses: []string{"MIT", "Apache-2.0"}},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", DaysSinceLastRelease: 400,
+				IsIndirect: true, Severity: scanner.SeverityWarn, Owners: []string{"@team, platform"}},
+			{Path: "github.com/example/broken", Version: "v1.0.0", Module: "example.com/tools",
+				Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderCSV(&buf, result))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"", "github.com/example/healthy", "v1.2.0", "false", "active", "", "2024-05-01", "30", "", "", "42", "MIT;Apache-2.0", "", "", ""}, records[1])
+	assert.Equal(t, "inactive", records[2][4])
+	assert.Equal(t, "warn", records[2][5])
+	assert.Equal(t, "@team, platform", records[2][12])
+	assert.Equal(t, "inactive for 400 days;update to v0.3.0", records[2][13])
+	assert.Equal(t, "example.com/tools", records[3][0])
+	assert.Equal(t, "error", records[3][4])
+	assert.NotEmpty(t, records[3][14])
+}
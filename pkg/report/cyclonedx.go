@@ -0,0 +1,328 @@
+package report
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/internal/version"
+	"github.com/steffakasid/govital/pkg/license"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("cyclonedx", "CycloneDX JSON SBOM with the health data as component properties", func(Options) (Renderer, error) {
+		return RendererFunc(renderCycloneDX), nil
+	})
+}
+
+// cycloneDXSpecVersion is the CycloneDX specification the BOM conforms to
+const cycloneDXSpecVersion = "1.5"
+
+// cycloneDXPropertyPrefix namespaces the health data in component properties
+const cycloneDXPropertyPrefix = "govital:"
+
+type cycloneDXBOM struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber"`
+	Version         int                      `json:"version"`
+	Metadata        cycloneDXMetadata        `json:"metadata"`
+	Components      []cycloneDXComponent     `json:"components"`
+	Vulnerabilities []cycloneDXVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp"`
+	Tools      cycloneDXTools      `json:"tools"`
+	Component  *cycloneDXComponent `json:"component,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Scope              string                       `json:"scope,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	Licenses           []cycloneDXLicense           `json:"licenses,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty          `json:"properties,omitempty"`
+}
+
+// cycloneDXLicense holds either a license or an SPDX expression
+type cycloneDXLicense struct {
+	License    *cycloneDXLicenseID `json:"license,omitempty"`
+	Expression string              `json:"expression,omitempty"`
+}
+
+// cycloneDXLicenseID names a license by its SPDX identifier or, if it has
+// none, by its name
+type cycloneDXLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type cycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXVulnerability struct {
+	ID          string              `json:"id"`
+	Source      cycloneDXSource     `json:"source"`
+	Ratings     []cycloneDXRating   `json:"ratings,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Affects     []cycloneDXAffected `json:"affects"`
+}
+
+type cycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cycloneDXRating struct {
+	Severity string `json:"severity"`
+}
+
+type cycloneDXAffected struct {
+	Ref string `json:"ref"`
+}
+
+// renderCycloneDX writes a CycloneDX BOM listing every scanned module as
+// library component. The health data is embedded as govital:* properties
+// and known vulnerabilities reference the affected components.
+func renderCycloneDX(w io.Writer, result *scanner.ScanResult) error {
+	serial, err := cycloneDXSerialNumber()
+	if err != nil {
+		return err
+	}
+
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{
+				{Type: "application", Name: "govital", Version: version.Version},
+			}},
+			Properties: []cycloneDXProperty{
+				{Name: cycloneDXPropertyPrefix + "stale_threshold_days", Value: strconv.Itoa(result.Summary.StaleThresholdDays)},
+			},
+		},
+		Components: make([]cycloneDXComponent, 0, len(result.Dependencies)),
+	}
+	if result.Fingerprint != nil && result.Fingerprint.Module != "" {
+		bom.Metadata.Component = &cycloneDXComponent{
+			Type:   "application",
+			BOMRef: result.Fingerprint.Module,
+			Name:   result.Fingerprint.Module,
+		}
+		if result.Fingerprint.Revision != "" {
+			bom.Metadata.Component.Properties = []cycloneDXProperty{
+				{Name: cycloneDXPropertyPrefix + "revision", Value: result.Fingerprint.Revision},
+			}
+		}
+	}
+
+	// Workspace modules may require the same version, it is listed once
+	seen := make(map[string]bool)
+	for _, dep := range result.Dependencies {
+		purl := packageURL(dep.Path, dep.Version)
+		if seen[purl] {
+			continue
+		}
+		seen[purl] = true
+
+		bom.Components = append(bom.Components, cycloneDXComponentOf(dep, purl))
+		for _, v := range dep.Vulnerabilities {
+			vulnerability := cycloneDXVulnerability{
+				ID:          v.ID,
+				Source:      cycloneDXSource{Name: "OSV", URL: "https://osv.dev/vulnerability/" + v.ID},
+				Description: v.Summary,
+				Affects:     []cycloneDXAffected{{Ref: purl}},
+			}
+			if v.Severity != "" {
+				vulnerability.Ratings = []cycloneDXRating{{Severity: strings.ToLower(v.Severity)}}
+			}
+			bom.Vulnerabilities = append(bom.Vulnerabilities, vulnerability)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bom)
+}
+
+// cycloneDXLicensesOf converts the licenses reported by deps.dev. The
+// schema only accepts identifiers of the SPDX license list as id, others
+// are given as name. A BOM may not mix licenses and expressions, so
+// expressions are joined with the other licenses into a single one, unless
+// one of them has no SPDX identifier.
+func cycloneDXLicensesOf(licenses []string) []cycloneDXLicense {
+	if slices.ContainsFunc(licenses, license.IsExpression) {
+		var terms []string
+		for _, l := range licenses {
+			if license.IsExpression(l) {
+				terms = append(terms, "("+strings.TrimSpace(l)+")")
+			} else if id, ok := license.SPDXID(l); ok {
+				terms = append(terms, id)
+			} else {
+				terms = nil
+				break
+			}
+		}
+		if len(terms) == 1 {
+			return []cycloneDXLicense{{Expression: strings.TrimSpace(licenses[0])}}
+		}
+		if terms != nil {
+			return []cycloneDXLicense{{Expression: strings.Join(terms, " AND ")}}
+		}
+	}
+
+	var converted []cycloneDXLicense
+	for _, l := range licenses {
+		if id, ok := license.SPDXID(l); ok {
+			converted = append(converted, cycloneDXLicense{License: &cycloneDXLicenseID{ID: id}})
+		} else {
+			converted = append(converted, cycloneDXLicense{License: &cycloneDXLicenseID{Name: l}})
+		}
+	}
+	return converted
+}
+
+func cycloneDXComponentOf(dep scanner.Dependency, purl string) cycloneDXComponent {
+	component := cycloneDXComponent{
+		Type:    "library",
+		BOMRef:  purl,
+		Name:    dep.Path,
+		Version: dep.Version,
+		Scope:   "required",
+		PURL:    purl,
+	}
+	component.Licenses = cycloneDXLicensesOf(dep.Licenses)
+	if dep.Repository != "" {
+		component.ExternalReferences = []cycloneDXExternalReference{{Type: "vcs", URL: dep.Repository}}
+	}
+
+	property := func(name, value string) {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: cycloneDXPropertyPrefix + name, Value: value})
+	}
+	property("status", cycloneDXStatus(dep))
+	property("indirect", strconv.FormatBool(dep.IsIndirect))
+	if dep.Severity != "" {
+		property("severity", string(dep.Severity))
+	}
+	if !dep.LastReleaseTime.IsZero() {
+		property("last_release", dep.LastReleaseTime.UTC().Format(time.RFC3339))
+		property("days_since_last_release", strconv.Itoa(dep.DaysSinceLastRelease))
+	}
+	if dep.Score != nil {
+		property("score", strconv.Itoa(*dep.Score))
+	}
+	if dep.Latest != "" {
+		property("latest", dep.Latest)
+	}
+	if dep.Deprecated != "" {
+		property("deprecated", dep.Deprecated)
+	}
+	if dep.Retracted != nil {
+		property("retracted", dep.Retracted.String())
+	}
+	if dep.Archived != nil {
+		property("archived", strconv.FormatBool(*dep.Archived))
+	}
+	if dep.ContributorCount != nil {
+		property("contributors", strconv.Itoa(*dep.ContributorCount))
+	}
+	if dep.OpenIssues != nil {
+		property("open_issues", strconv.Itoa(*dep.OpenIssues))
+	}
+	if dep.MedianResponseHours != nil {
+		property("median_response_hours", strconv.Itoa(*dep.MedianResponseHours))
+	}
+	if dep.MergedPullRequests != nil {
+		property("merged_pull_requests", strconv.Itoa(*dep.MergedPullRequests))
+	}
+	if dep.Security != nil {
+		if dep.Security.SecurityPolicy != nil {
+			property("security_policy", strconv.FormatBool(*dep.Security.SecurityPolicy))
+		}
+		if dep.Security.CheckedTags > 0 {
+			property("signed_tags", strconv.Itoa(dep.Security.SignedTags)+"/"+strconv.Itoa(dep.Security.CheckedTags))
+		}
+		if dep.Security.BranchProtected != nil {
+			property("branch_protected", strconv.FormatBool(*dep.Security.BranchProtected))
+		}
+	}
+	if len(dep.Owners) > 0 {
+		property("owners", strings.Join(dep.Owners, ","))
+	}
+	if dep.Error != nil {
+		property("error", dep.Error.String())
+	}
+	return component
+}
+
+// cycloneDXStatus returns the maintenance status stored in the
+// govital:status property
+func cycloneDXStatus(dep scanner.Dependency) string {
+	switch {
+	case dep.Error != nil:
+		return "error"
+	case dep.Replace.IsLocal():
+		return "not-checked"
+	case dep.Status == scanner.StatusUnknown:
+		return "unknown"
+	case !dep.IsInactive():
+		return "active"
+	case dep.IsAcknowledged:
+		return "acknowledged"
+	default:
+		return "inactive"
+	}
+}
+
+// packageURL returns the purl of a Go module version, e.g.
+// pkg:golang/github.com/foo/bar@v1.2.0
+func packageURL(modulePath, moduleVersion string) string {
+	segments := strings.Split(modulePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	purl := "pkg:golang/" + strings.Join(segments, "/")
+	if moduleVersion != "" {
+		// + is reserved in purls, e.g. in v2.0.0+incompatible
+		purl += "@" + strings.ReplaceAll(url.PathEscape(moduleVersion), "+", "%2B")
+	}
+	return purl
+}
+
+// cycloneDXSerialNumber returns a random UUID URN identifying the BOM
+func cycloneDXSerialNumber() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", fmt.Errorf("failed to generate BOM serial number: %w", err)
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
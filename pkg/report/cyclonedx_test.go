@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCycloneDX(t *testing.T) {
+	score := 42
+	released := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	result := &scanner.ScanResult{
+		Fingerprint: &scanner.Fingerprint{Module: "example.com/app", Revision: "abc123"},
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/stale", Version: "v1.0.0", Latest: "v1.1.0", LastReleaseTime: released,
+				DaysSinceLastRelease: 400, Score: &score, Licenses: []string{"MIT"}, Repository: "https://github.com/example/stale",
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001", Summary: "Crash", Severity: "HIGH"}}},
+			{Path: "github.com/example/Legacy", Version: "v2.0.0+incompatible", IsActive: true, IsIndirect: true, Module: "example.com/app/a"},
+			{Path: "github.com/example/Legacy", Version: "v2.0.0+incompatible", IsActive: true, IsIndirect: true, Module: "example.com/app/b"},
+		},
+	}
+	result.Summary.StaleThresholdDays = 180
+
+	var buf bytes.Buffer
+	require.NoError(t, renderCycloneDX(&buf, result))
+
+	var bom cycloneDXBOM
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, bom.SerialNumber)
+	assert.Equal(t, "example.com/app", bom.Metadata.Component.Name)
+
+	require.Len(t, bom.Components, 2, "versions required by several workspace modules are listed once")
+	stale := bom.Components[0]
+	assert.Equal(t, "pkg:golang/github.com/example/stale@v1.0.0", stale.PURL)
+	assert.Equal(t, stale.PURL, stale.BOMRef)
+	assert.Equal(t, []cycloneDXLicense{{License: &cycloneDXLicenseID{ID: "MIT"}}}, stale.Licenses)
+	assert.Equal(t, []cycloneDXExternalReference{{Type: "vcs", URL: "https://github.com/example/stale"}}, stale.ExternalReferences)
+	assert.Subset(t, stale.Properties, []cycloneDXProperty{
+		{Name: "govital:status", Value: "inactive"},
+		{Name: "govital:last_release", Value: "2024-03-01T12:00:00Z"},
+		{Name: "govital:days_since_last_release", Value: "400"},
+		{Name: "govital:score", Value: "42"},
+		{Name: "govital:latest", Value: "v1.1.0"},
+	})
+
+	legacy := bom.Components[1]
+	assert.Equal(t, "pkg:golang/github.com/example/Legacy@v2.0.0%2Bincompatible", legacy.PURL)
+	assert.Contains(t, legacy.Properties, cycloneDXProperty{Name: "govital:status", Value: "active"})
+	assert.Contains(t, legacy.Properties, cycloneDXProperty{Name: "govital:indirect", Value: "true"})
+
+	require.Len(t, bom.Vulnerabilities, 1)
+	assert.Equal(t, "GO-2024-0001", bom.Vulnerabilities[0].ID)
+	assert.Equal(t, []cycloneDXRating{{Severity: "high"}}, bom.Vulnerabilities[0].Ratings)
+	assert.Equal(t, []cycloneDXAffected{{Ref: stale.BOMRef}}, bom.Vulnerabilities[0].Affects)
+}
+
+func TestCycloneDXLicenses(t *testing.T) {
+	assert.Equal(t, []cycloneDXLicense{
+		{License: &cycloneDXLicenseID{ID: "Apache-2.0"}},
+		{License: &cycloneDXLicenseID{Name: "non-standard"}},
+	}, cycloneDXLicensesOf([]string{"apache-2.0", "non-standard"}))
+	assert.Equal(t, []cycloneDXLicense{{Expression: "MIT OR Apache-2.0"}}, cycloneDXLicensesOf([]string{"MIT OR Apache-2.0"}))
+	assert.Equal(t, []cycloneDXLicense{{Expression: "(MIT OR Apache-2.0) AND BSD-3-Clause"}},
+		cycloneDXLicensesOf([]string{"MIT OR Apache-2.0", "bsd-3-clause"}))
+	// Names can't be part of an expression
+	assert.Equal(t, []cycloneDXLicense{
+		{License: &cycloneDXLicenseID{Name: "MIT OR Apache-2.0"}},
+		{License: &cycloneDXLicenseID{Name: "non-standard"}},
+	}, cycloneDXLicensesOf([]string{"MIT OR Apache-2.0", "non-standard"}))
+	assert.Nil(t, cycloneDXLicensesOf(nil))
+}
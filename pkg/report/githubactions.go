@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// stepSummaryEnv names the file GitHub Actions renders as job summary
+const stepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+func init() {
+	MustRegister("github-actions", "GitHub Actions annotations on go.mod lines and a job summary", func(Options) (Renderer, error) {
+		return RendererFunc(renderGitHubActions), nil
+	})
+}
+
+// renderGitHubActions writes a workflow command per dependency with
+// findings, which GitHub shows as annotation on the declaring go.mod line
+// in pull requests. Critical dependencies are errors, warn ones warnings
+// and info ones notices. Results without severities fall back to errors for
+// vulnerable and retracted dependencies and warnings for all others. The
+// Markdown report is appended to the job summary if the step summary file
+// is set.
+func renderGitHubActions(w io.Writer, result *scanner.ScanResult) error {
+	for _, dep := range result.Dependencies {
+		findings := Findings(dep)
+		if len(findings) == 0 {
+			continue
+		}
+
+		level := "warning"
+		switch {
+		case dep.Severity == scanner.SeverityCritical:
+			level = "error"
+		case dep.Severity == scanner.SeverityInfo:
+			level = "notice"
+		case dep.Severity == "" && (len(dep.Vulnerabilities) > 0 || dep.Retracted != nil):
+			level = "error"
+		}
+		var properties []string
+		if dep.Location != nil {
+			properties = append(properties,
+				"file="+escapeProperty(annotationPath(result.ProjectPath, dep.Location.File)),
+				fmt.Sprintf("line=%d", dep.Location.Line),
+				fmt.Sprintf("endLine=%d", dep.Location.EndLine))
+		}
+		properties = append(properties, "title="+escapeProperty("govital: "+dep.Path))
+		message := fmt.Sprintf("module %s@%s %s", dep.Path, dep.Version, strings.Join(findings, ", "))
+		fmt.Fprintf(w, "::%s %s::%s\n", level, strings.Join(properties, ","), escapeData(message))
+	}
+
+	summaryFile := os.Getenv(stepSummaryEnv)
+	if summaryFile == "" {
+		return nil
+	}
+	file, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	if err := renderMarkdown(file, result); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return nil
+}
+
+// annotationPath returns the path of a file of the project relative to the
+// working directory, which is the repository root in GitHub Actions
+func annotationPath(projectPath, file string) string {
+	path := filepath.Join(projectPath, filepath.FromSlash(file))
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// escapeData escapes the message of a workflow command
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
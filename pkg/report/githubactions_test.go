@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderGitHubActions(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(summaryFile, []byte("# Previous step\n"), 0o600))
+	t.Setenv(stepSummaryEnv, summaryFile)
+	result := &scanner.ScanResult{
+		ProjectPath: "services/api",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true},
+			{Path: "github.com/example/stale", Version: "v0.1.0", DaysSinceLastRelease: 400,
+				Location: &scanner.Location{File: "go.mod", Line: 7, Column: 2, EndLine: 7, EndColumn: 33}},
+			{Path: "github.com/example/vulnerable", Version: "v1.0.0", IsActive: true, IsIndirect: true,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderGitHubActions(&buf, result))
+
+	assert.Equal(t, "::warning file=services/api/go.mod,line=7,endLine=7,title=govital%3A github.com/example/stale::module github.com/example/stale@v0.1.0 inactive for 400 days\n"+
+		"::error title=govital%3A github.com/example/vulnerable::module github.com/example/vulnerable@v1.0.0 1 known vulnerabilities\n", buf.String())
+
+	summary, err := os.ReadFile(summaryFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "# Previous step\n## Govital Dependency Report")
+}
+
+func TestEscapeWorkflowCommand(t *testing.T) {
+	assert.Equal(t, "100%25 done%0Anext", escapeData("100% done\nnext"))
+	assert.Equal(t, "a%3Ab%2Cc", escapeProperty("a:b,c"))
+}
+
+func TestAnnotationPath(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	assert.Equal(t, "go.mod", annotationPath(".", "go.mod"))
+	assert.Equal(t, "sub/go.mod", annotationPath(filepath.Join(wd, "sub"), "go.mod"))
+	assert.Equal(t, "/elsewhere/go.mod", annotationPath("/elsewhere", "go.mod"))
+}
+
+func TestRenderGitHubActionsSeverity(t *testing.T) {
+	t.Setenv(stepSummaryEnv, "")
+	result := &scanner.ScanResult{
+		ProjectPath: ".",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/old", Version: "v0.1.0", DaysSinceLastRelease: 800, Severity: scanner.SeverityCritical},
+			{Path: "github.com/example/recent", Version: "v0.1.0", DaysSinceLastRelease: 200, Severity: scanner.SeverityInfo},
+			{Path: "github.com/example/vulnerable", Version: "v1.0.0", IsActive: true, Severity: scanner.SeverityWarn,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderGitHubActions(&buf, result))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "::error "))
+	assert.True(t, strings.HasPrefix(lines[1], "::notice "))
+	assert.True(t, strings.HasPrefix(lines[2], "::warning "), "the configured severity overrides the default level")
+}
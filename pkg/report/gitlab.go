@@ -0,0 +1,96 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("gitlab", "GitLab Code Quality report for the merge request widget", func(Options) (Renderer, error) {
+		return RendererFunc(renderGitLab), nil
+	})
+}
+
+// gitLabIssue is an entry of a GitLab Code Quality report
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+// gitLabSeverities map the most severe finding of a dependency to the Code
+// Quality severities info, minor, major, critical and blocker
+var gitLabSeverities = map[string]string{
+	"error":           "minor",
+	"vulnerable":      "critical",
+	"inactive":        "major",
+	"retracted":       "major",
+	"deprecated":      "major",
+	"license-changed": "major",
+	"bus-factor":      "minor",
+	"outdated":        "info",
+}
+
+// gitLabSeverityLevels map the classified severity of a dependency to the
+// Code Quality severities, they take precedence over gitLabSeverities
+var gitLabSeverityLevels = map[scanner.Severity]string{
+	scanner.SeverityCritical: "critical",
+	scanner.SeverityWarn:     "major",
+	scanner.SeverityInfo:     "info",
+}
+
+// renderGitLab writes a Code Quality report with an issue per dependency
+// with findings, pointing at its go.mod line. GitLab shows the issues new
+// to a merge request in its widget, matched by fingerprint, so the
+// fingerprint covers the module, the version and the kind of finding.
+// Dependencies without a known line point at the first line of go.mod.
+func renderGitLab(w io.Writer, result *scanner.ScanResult) error {
+	issues := []gitLabIssue{}
+	for _, dep := range result.Dependencies {
+		findings := Findings(dep)
+		if len(findings) == 0 {
+			continue
+		}
+
+		check := junitFailureType(dep)
+		if dep.Error != nil {
+			check = "error"
+		}
+		location := gitLabLocation{Path: annotationPath(result.ProjectPath, "go.mod"), Lines: gitLabLines{Begin: 1}}
+		if dep.Location != nil {
+			location = gitLabLocation{Path: annotationPath(result.ProjectPath, dep.Location.File), Lines: gitLabLines{Begin: dep.Location.Line}}
+		}
+		severity := gitLabSeverities[check]
+		if level, ok := gitLabSeverityLevels[dep.Severity]; ok {
+			severity = level
+		}
+		sum := sha256.Sum256([]byte(strings.Join([]string{location.Path, dep.Path, dep.Version, check}, "\x00")))
+		issues = append(issues, gitLabIssue{
+			Description: dep.Path + "@" + dep.Version + ": " + strings.Join(findings, ", "),
+			CheckName:   "govital/" + check,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    severity,
+			Location:    location,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderGitLab(t *testing.T) {
+	result := &scanner.ScanResult{
+		ProjectPath: ".",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", DaysSinceLastRelease: 400,
+				Location: &scanner.Location{File: "go.mod", Line: 7}},
+			{Path: "github.com/example/vulnerable", Version: "v1.0.0", IsActive: true, IsIndirect: true,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+			{Path: "github.com/example/broken", Version: "v1.0.0", Status: scanner.StatusError,
+				Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderGitLab(&buf, result))
+
+	var issues []gitLabIssue
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	require.Len(t, issues, 3)
+
+	assert.Equal(t, "github.com/example/stale@v0.1.0: inactive for 400 days, update to v0.3.0", issues[0].Description)
+	assert.Equal(t, "govital/inactive", issues[0].CheckName)
+	assert.Equal(t, "major", issues[0].Severity)
+	assert.Equal(t, gitLabLocation{Path: "go.mod", Lines: gitLabLines{Begin: 7}}, issues[0].Location)
+	assert.Len(t, issues[0].Fingerprint, 64)
+
+	assert.Equal(t, "critical", issues[1].Severity)
+	assert.Equal(t, gitLabLocation{Path: "go.mod", Lines: gitLabLines{Begin: 1}}, issues[1].Location, "unknown lines point at go.mod")
+	assert.Equal(t, "govital/error", issues[2].CheckName)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	result.Dependencies[1].Severity = scanner.SeverityInfo
+	buf.Reset()
+	require.NoError(t, renderGitLab(&buf, result))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	assert.Equal(t, "info", issues[0].Severity, "the configured severity takes precedence")
+}
+
+func TestRenderGitLabWithoutFindings(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, renderGitLab(&buf, &scanner.ScanResult{ProjectPath: "."}))
+
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": func(values []string) string { return strings.Join(values, ", ") },
+}).Parse(htmlTemplateText))
+
+// donutRadius is chosen so the circumference is 100 and segment lengths
+// are percentages
+const donutRadius = 100 / (2 * math.Pi)
+
+func init() {
+	MustRegister("html", "Standalone HTML report with charts for sharing", func(Options) (Renderer, error) {
+		return RendererFunc(renderHTML), nil
+	})
+}
+
+// htmlSegment is a slice of the summary donut chart
+type htmlSegment struct {
+	Label string
+	Class string
+	Count int
+	// Length and Gap are the percentages of the circumference covered and
+	// not covered by the segment
+	Length float64
+	Gap    float64
+	// Offset is the dash offset placing the segment after the previous ones
+	Offset float64
+}
+
+type htmlDependency struct {
+	scanner.Dependency
+	// Anchor links the table row to the detail section
+	Anchor      string
+	Status      string
+	StatusClass string
+	Findings    []string
+}
+
+type htmlReport struct {
+	Result       *scanner.ScanResult
+	Generated    string
+	Radius       float64
+	Segments     []htmlSegment
+	Dependencies []htmlDependency
+	// Severities counts the dependencies per severity, e.g. "1 critical, 3
+	// warn", empty if no dependency has a severity
+	Severities string
+}
+
+// renderHTML writes a single HTML document without external resources, so
+// it can be attached to mails or tickets as is
+func renderHTML(w io.Writer, result *scanner.ScanResult) error {
+	deps := make([]scanner.Dependency, len(result.Dependencies))
+	copy(deps, result.Dependencies)
+	scanner.SortByScore(deps)
+
+	report := htmlReport{
+		Result:    result,
+		Generated: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		Radius:    donutRadius,
+		Segments:  donutSegments(deps),
+	}
+	var severities []string
+	for i := len(scanner.Severities) - 1; i >= 0; i-- {
+		if count := result.Summary.Severities[scanner.Severities[i]]; count > 0 {
+			severities = append(severities, fmt.Sprintf("%d %s", count, scanner.Severities[i]))
+		}
+	}
+	report.Severities = strings.Join(severities, ", ")
+	for i, dep := range deps {
+		status, class := htmlStatus(dep)
+		report.Dependencies = append(report.Dependencies, htmlDependency{
+			Dependency:  dep,
+			Anchor:      fmt.Sprintf("dep-%d", i+1),
+			Status:      status,
+			StatusClass: class,
+			Findings:    Findings(dep),
+		})
+	}
+	return htmlTemplate.Execute(w, report)
+}
+
+// donutSegments splits the dependencies into disjoint groups for the chart
+func donutSegments(deps []scanner.Dependency) []htmlSegment {
+	segments := []htmlSegment{
+		{Label: "Up to date", Class: "active"},
+		{Label: "Update available", Class: "outdated"},
+		{Label: "Inactive", Class: "inactive"},
+		{Label: "Acknowledged", Class: "acknowledged"},
+		{Label: "Unknown", Class: "unknown"},
+		{Label: "Error", Class: "error"},
+	}
+	for _, dep := range deps {
+		_, class := htmlStatus(dep)
+		for i := range segments {
+			if segments[i].Class == class {
+				segments[i].Count++
+			}
+		}
+	}
+
+	offset := 0.0
+	var visible []htmlSegment
+	for _, segment := range segments {
+		if segment.Count == 0 {
+			continue
+		}
+		segment.Length = 100 * float64(segment.Count) / float64(len(deps))
+		segment.Gap = 100 - segment.Length
+		segment.Offset = -offset
+		offset += segment.Length
+		visible = append(visible, segment)
+	}
+	return visible
+}
+
+// htmlStatus returns the status label and CSS class of a dependency
+func htmlStatus(dep scanner.Dependency) (string, string) {
+	switch {
+	case dep.Error != nil:
+		return "Error", "error"
+	case dep.IsInactive() && dep.IsAcknowledged:
+		return "Acknowledged", "acknowledged"
+	case dep.IsInactive():
+		return "Inactive", "inactive"
+	case dep.Status == scanner.StatusUnknown:
+		return "Unknown", "unknown"
+	case dep.Update != "":
+		return "Update available", "outdated"
+	default:
+		return "Up to date", "active"
+	}
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	low, high := 20, 90
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Fingerprint: &scanner.Fingerprint{Revision: "abc123"},
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", Latest: "v1.2.0", IsActive: true,
+				LastReleaseTime: time.Now(), DaysSinceLastRelease: 3, Score: &high, Repository: "https://github.com/example/healthy"},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", Latest: "v0.3.0",
+				LastReleaseTime: time.Now(), DaysSinceLastRelease: 400, Score: &low,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001", Severity: "HIGH", Summary: "<script>alert(1)</script>"}}},
+			{Path: "github.com/example/outdated", Version: "v1.0.0", Update: "v1.1.0", Latest: "v1.1.0", IsActive: true,
+				ReleaseNotes: []changelog.Note{{Version: "v1.1.0", Notes: "<b>new</b>", URL: "https://example.com/v1.1.0"}}},
+			{Path: "github.com/example/broken", Version: "v1.0.0", IsIndirect: true, Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}},
+		},
+	}
+	result.Summary.Total = 4
+
+	var buf bytes.Buffer
+	require.NoError(t, renderHTML(&buf, result))
+	output := buf.String()
+
+	assert.Contains(t, output, "<title>Govital Dependency Report - /test/project</title>")
+	assert.Contains(t, output, "at <code>abc123</code>")
+	assert.Contains(t, output, `stroke-dasharray="25.00 75.00"`, "each status is a quarter of the donut")
+	assert.Contains(t, output, `<li><span class="swatch inactive"></span>Inactive: 1</li>`)
+	assert.NotContains(t, output, "Acknowledged:", "empty segments are omitted")
+	assert.Contains(t, output, `<a href="#dep-1"><code>github.com/example/stale</code></a>`, "least healthy first")
+	assert.Contains(t, output, `<section class="detail" id="dep-1">`)
+	assert.Contains(t, output, "error: not-found: lookup failed")
+	assert.Contains(t, output, "&lt;script&gt;alert(1)&lt;/script&gt;", "free text is escaped")
+	assert.Contains(t, output, `<td class="num" data-value="90">90</td>`)
+	assert.Contains(t, output, "<summary>Release notes up to v1.1.0</summary>")
+	assert.Contains(t, output, `<h4><a href="https://example.com/v1.1.0">v1.1.0</a></h4>`)
+	assert.Contains(t, output, "<pre>&lt;b&gt;new&lt;/b&gt;</pre>", "release notes are escaped")
+	assert.Contains(t, output, `document.querySelectorAll("#dependencies th")`)
+}
+
+func TestDonutSegments(t *testing.T) {
+	segments := donutSegments([]scanner.Dependency{
+		{IsActive: true},
+		{IsActive: true},
+		{IsActive: false},
+	})
+
+	require.Len(t, segments, 2)
+	assert.Equal(t, "active", segments[0].Class)
+	assert.InDelta(t, 66.67, segments[0].Length, 0.01)
+	assert.Equal(t, 0.0, segments[0].Offset)
+	assert.Equal(t, "inactive", segments[1].Class)
+	assert.InDelta(t, -66.67, segments[1].Offset, 0.01)
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("junit", "JUnit XML with a test case per dependency for CI test reports", func(Options) (Renderer, error) {
+		return RendererFunc(renderJUnit), nil
+	})
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// renderJUnit writes one test suite per workspace module, or a single one
+// for the project, with a test case per dependency. Dependencies with
+// findings, e.g. inactive, outdated or vulnerable ones, fail, dependencies
+// which couldn't be scanned are errors and acknowledged inactive ones
+// without other findings are skipped.
+func renderJUnit(w io.Writer, result *scanner.ScanResult) error {
+	suites := &junitTestSuites{Name: "govital"}
+	index := make(map[string]int)
+	for _, dep := range result.Dependencies {
+		name := dep.Module
+		if name == "" {
+			name = result.ProjectPath
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(suites.Suites)
+			index[name] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: name})
+		}
+
+		suite := &suites.Suites[i]
+		testCase := junitTestCase{Name: dep.Path + "@" + dep.Version, ClassName: junitClassName(name, dep)}
+		findings := Findings(dep)
+		switch {
+		case dep.Error != nil:
+			testCase.Error = &junitProblem{Message: dep.Error.String(), Type: string(dep.Error.Category), Text: dep.Error.String()}
+			suite.Errors++
+		case len(findings) > 0:
+			message := strings.Join(findings, ", ")
+			if dep.Severity != "" {
+				message = string(dep.Severity) + ": " + message
+			}
+			testCase.Failure = &junitProblem{
+				Message: message,
+				Type:    junitFailureType(dep),
+				Text:    strings.Join(findings, "\n"),
+			}
+			suite.Failures++
+		case dep.IsInactive() && dep.IsAcknowledged:
+			testCase.Skipped = &junitSkipped{Message: "acknowledged"}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitClassName groups direct and indirect dependencies of a suite, CI
+// systems show the class names as tree below the suite
+func junitClassName(suite string, dep scanner.Dependency) string {
+	if dep.IsIndirect {
+		return suite + ".indirect"
+	}
+	return suite + ".direct"
+}
+
+// junitFailureType names the most severe finding of the dependency
+func junitFailureType(dep scanner.Dependency) string {
+	switch {
+	case len(dep.Vulnerabilities) > 0:
+		return "vulnerable"
+	case dep.IsInactive() && !dep.IsAcknowledged:
+		return "inactive"
+	case dep.Retracted != nil:
+		return "retracted"
+	case dep.Deprecated != "":
+		return "deprecated"
+	case dep.LicenseChange != nil:
+		return "license-changed"
+	case dep.BusFactorRisk:
+		return "bus-factor"
+	default:
+		return "outdated"
+	}
+}
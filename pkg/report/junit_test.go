@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", DaysSinceLastRelease: 400},
+			{Path: "github.com/example/vulnerable", Version: "v1.0.0", IsActive: true, IsIndirect: true,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+			{Path: "github.com/example/known", Version: "v1.0.0", IsAcknowledged: true},
+			{Path: "github.com/example/broken", Version: "v1.0.0", Module: "example.com/tools",
+				Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderJUnit(&buf, result))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 5, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Equal(t, 1, suites.Errors)
+	require.Len(t, suites.Suites, 2)
+
+	project := suites.Suites[0]
+	assert.Equal(t, "/test/project", project.Name)
+	assert.Equal(t, 1, project.Skipped)
+	require.Len(t, project.TestCases, 4)
+	assert.Nil(t, project.TestCases[0].Failure)
+	assert.Equal(t, "github.com/example/stale@v0.1.0", project.TestCases[1].Name)
+	assert.Equal(t, "/test/project.direct", project.TestCases[1].ClassName)
+	require.NotNil(t, project.TestCases[1].Failure)
+	assert.Equal(t, "inactive", project.TestCases[1].Failure.Type)
+	assert.Equal(t, "inactive for 400 days, update to v0.3.0", project.TestCases[1].Failure.Message)
+	assert.Equal(t, "/test/project.indirect", project.TestCases[2].ClassName)
+	assert.Equal(t, "vulnerable", project.TestCases[2].Failure.Type)
+	assert.NotNil(t, project.TestCases[3].Skipped)
+
+	tools := suites.Suites[1]
+	assert.Equal(t, "example.com/tools", tools.Name)
+	require.NotNil(t, tools.TestCases[0].Error)
+	assert.Equal(t, "not-found", tools.TestCases[0].Error.Type)
+}
@@ -0,0 +1,207 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("markdown", "Markdown tables for pull request comments", func(Options) (Renderer, error) {
+		return RendererFunc(renderMarkdown), nil
+	})
+}
+
+// renderMarkdown writes a summary block and one table per dependency kind,
+// least healthy dependencies first
+func renderMarkdown(w io.Writer, result *scanner.ScanResult) error {
+	var direct, indirect []scanner.Dependency
+	for _, dep := range result.Dependencies {
+		if dep.IsIndirect {
+			indirect = append(indirect, dep)
+		} else {
+			direct = append(direct, dep)
+		}
+	}
+	scanner.SortByScore(direct)
+	scanner.SortByScore(indirect)
+
+	summary := result.Summary
+	fmt.Fprintf(w, "## Govital Dependency Report\n\n")
+	fmt.Fprintf(w, "**Project:** `%s`", result.ProjectPath)
+	if result.Fingerprint != nil && result.Fingerprint.Revision != "" {
+		fmt.Fprintf(w, " at `%s`", result.Fingerprint.Revision)
+	}
+	fmt.Fprintf(w, " · **Stale threshold:** %d days\n\n", summary.StaleThresholdDays)
+
+	fmt.Fprintf(w, "| | Count |\n|---|---:|\n")
+	fmt.Fprintf(w, "| 📦 Total | %d |\n", summary.Total)
+	fmt.Fprintf(w, "| 🔴 Inactive | %d |\n", summary.Inactive)
+	fmt.Fprintf(w, "| ⬆️ Update available | %d |\n", summary.Outdated)
+	fmt.Fprintf(w, "| ✅ Up to date | %d |\n", summary.Updated)
+	if summary.Vulnerable > 0 {
+		fmt.Fprintf(w, "| 🛡️ Vulnerable | %d (%d known vulnerabilities) |\n", summary.Vulnerable, summary.Vulnerabilities)
+	}
+	if summary.Errors > 0 {
+		fmt.Fprintf(w, "| ⚠️ Errors | %d |\n", summary.Errors)
+	}
+	for i := len(scanner.Severities) - 1; i >= 0; i-- {
+		if count := summary.Severities[scanner.Severities[i]]; count > 0 {
+			fmt.Fprintf(w, "| %s findings | %d |\n", markdownSeverity(scanner.Severities[i]), count)
+		}
+	}
+
+	if len(result.ToolchainFindings) > 0 {
+		fmt.Fprintf(w, "\n### Go Toolchain\n\n")
+		for _, finding := range result.ToolchainFindings {
+			fmt.Fprintf(w, "- %s\n", finding)
+		}
+	}
+
+	writeMarkdownTable(w, "Direct Dependencies", direct, false)
+	// Indirect dependencies are collapsed to keep the comment short
+	writeMarkdownTable(w, "Indirect Dependencies", indirect, true)
+	writeMarkdownReleaseNotes(w, append(direct, indirect...))
+	return nil
+}
+
+// writeMarkdownReleaseNotes adds a collapsed section per dependency with
+// the release notes between the used and the available version
+func writeMarkdownReleaseNotes(w io.Writer, deps []scanner.Dependency) {
+	var withNotes []scanner.Dependency
+	for _, dep := range deps {
+		if len(dep.ReleaseNotes) > 0 {
+			withNotes = append(withNotes, dep)
+		}
+	}
+	if len(withNotes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n### Release Notes\n")
+	for _, dep := range withNotes {
+		fmt.Fprintf(w, "\n<details>\n<summary><code>%s</code> %s → %s</summary>\n", dep.Path, dep.Version, dep.Update)
+		for _, note := range dep.ReleaseNotes {
+			if note.URL != "" {
+				fmt.Fprintf(w, "\n#### [%s](%s)\n", note.Version, note.URL)
+			} else {
+				fmt.Fprintf(w, "\n#### %s\n", note.Version)
+			}
+			if note.Notes != "" {
+				fmt.Fprintf(w, "\n%s\n", note.Notes)
+			}
+		}
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+}
+
+func writeMarkdownTable(w io.Writer, title string, deps []scanner.Dependency, collapsed bool) {
+	if len(deps) == 0 {
+		return
+	}
+
+	if collapsed {
+		fmt.Fprintf(w, "\n<details>\n<summary>%s (%d)</summary>\n\n", title, len(deps))
+	} else {
+		fmt.Fprintf(w, "\n### %s (%d)\n\n", title, len(deps))
+	}
+
+	// The import counts are only shown if they were counted
+	imports := false
+	for _, dep := range deps {
+		imports = imports || dep.ImportedByPackages != nil
+	}
+	if imports {
+		fmt.Fprintf(w, "| Status | Dependency | Version | Latest | Days since release | Score | Imported by |\n")
+		fmt.Fprintf(w, "|---|---|---|---|---:|---:|---:|\n")
+	} else {
+		fmt.Fprintf(w, "| Status | Dependency | Version | Latest | Days since release | Score |\n")
+		fmt.Fprintf(w, "|---|---|---|---|---:|---:|\n")
+	}
+	for _, dep := range deps {
+		days := "–"
+		if !dep.LastReleaseTime.IsZero() {
+			days = fmt.Sprintf("%d", dep.DaysSinceLastRelease)
+		}
+		score := "–"
+		if dep.Score != nil {
+			score = fmt.Sprintf("%d", *dep.Score)
+		}
+		fmt.Fprintf(w, "| %s | `%s` | `%s` | %s | %s | %s |",
+			markdownStatus(dep), dep.Path, dep.Version, markdownLatest(dep), days, score)
+		if imports {
+			importedBy := "–"
+			if dep.ImportedByPackages != nil {
+				importedBy = fmt.Sprintf("%d", *dep.ImportedByPackages)
+			}
+			fmt.Fprintf(w, " %s |", importedBy)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if collapsed {
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+}
+
+func markdownStatus(dep scanner.Dependency) string {
+	var status string
+	switch {
+	case dep.Error != nil:
+		status = "⚠️ Error: " + escapeMarkdownCell(dep.Error.String())
+	case dep.Status == scanner.StatusUnknown:
+		status = "❔ Unknown"
+	case !dep.IsInactive():
+		status = "🟢 Active"
+	case dep.IsAcknowledged:
+		status = "⚪ Acknowledged"
+	default:
+		status = "🔴 Inactive"
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		status += fmt.Sprintf(" 🛡️ %d vulnerabilities", len(dep.Vulnerabilities))
+	}
+	if dep.LicenseChange != nil {
+		status += " 📜 License changed: " + escapeMarkdownCell(dep.LicenseChange.String())
+	}
+	if dep.Retracted != nil {
+		status += " ⛔ Retracted: " + escapeMarkdownCell(dep.Retracted.String())
+	}
+	if dep.Deprecated != "" {
+		status += " 🚫 Deprecated: " + escapeMarkdownCell(dep.Deprecated)
+	}
+	if dep.Severity != "" {
+		status += " · " + markdownSeverity(dep.Severity)
+	}
+	return status
+}
+
+func markdownSeverity(severity scanner.Severity) string {
+	switch severity {
+	case scanner.SeverityCritical:
+		return "🚨 Critical"
+	case scanner.SeverityWarn:
+		return "🟠 Warn"
+	default:
+		return "ℹ️ Info"
+	}
+}
+
+func markdownLatest(dep scanner.Dependency) string {
+	switch {
+	case dep.Update != "":
+		return fmt.Sprintf("⬆️ `%s`", dep.Update)
+	case dep.Latest != "":
+		return "✅ latest"
+	default:
+		return "–"
+	}
+}
+
+// escapeMarkdownCell keeps free text from breaking the table layout
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
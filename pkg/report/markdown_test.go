@@ -0,0 +1,89 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	low, high := 20, 90
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Fingerprint: &scanner.Fingerprint{Revision: "abc123"},
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", Latest: "v1.2.0", IsActive: true,
+				LastReleaseTime: time.Now(), DaysSinceLastRelease: 3, Score: &high},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", Latest: "v0.3.0",
+				LastReleaseTime: time.Now(), DaysSinceLastRelease: 400, Score: &low,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}},
+				ReleaseNotes:    []changelog.Note{{Version: "v0.3.0", Notes: "- faster", URL: "https://example.com/v0.3.0"}, {Version: "v0.2.0"}}},
+			{Path: "github.com/example/broken", Version: "v1.0.0", IsIndirect: true, Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup | failed\nbadly"}},
+		},
+	}
+	result.Summary.Total = 3
+	result.Summary.Inactive = 1
+	result.Summary.Outdated = 1
+	result.Summary.Vulnerable = 1
+	result.Summary.Vulnerabilities = 1
+
+	var buf bytes.Buffer
+	require.NoError(t, renderMarkdown(&buf, result))
+	output := buf.String()
+
+	assert.Contains(t, output, "**Project:** `/test/project` at `abc123`")
+	assert.Contains(t, output, "| 🔴 Inactive | 1 |")
+	assert.Contains(t, output, "| 🛡️ Vulnerable | 1 (1 known vulnerabilities) |")
+	assert.NotContains(t, output, "⚠️ Errors")
+	assert.Contains(t, output, "### Direct Dependencies (2)")
+	assert.Contains(t, output, "| 🔴 Inactive 🛡️ 1 vulnerabilities | `github.com/example/stale` | `v0.1.0` | ⬆️ `v0.3.0` | 400 | 20 |")
+	assert.Contains(t, output, "| 🟢 Active | `github.com/example/healthy` | `v1.2.0` | ✅ latest | 3 | 90 |")
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("example/stale")), bytes.Index(buf.Bytes(), []byte("example/healthy")),
+		"least healthy dependencies come first")
+	assert.Contains(t, output, "<summary>Indirect Dependencies (1)</summary>")
+	assert.Contains(t, output, "| ⚠️ Error: not-found: lookup \\| failed badly | `github.com/example/broken` | `v1.0.0` | – | – | – |")
+	assert.Contains(t, output, "### Release Notes\n\n<details>\n<summary><code>github.com/example/stale</code> v0.1.0 → v0.3.0</summary>\n")
+	assert.Contains(t, output, "\n#### [v0.3.0](https://example.com/v0.3.0)\n\n- faster\n\n#### v0.2.0\n\n</details>\n")
+}
+
+func TestRenderMarkdownImportedBy(t *testing.T) {
+	imports := 7
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/used", Version: "v1.0.0", IsActive: true, ImportedByPackages: &imports},
+			{Path: "github.com/example/other", Version: "v1.0.0", IsActive: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderMarkdown(&buf, result))
+	output := buf.String()
+
+	assert.Contains(t, output, "| Status | Dependency | Version | Latest | Days since release | Score | Imported by |")
+	assert.Contains(t, output, "| `github.com/example/used` | `v1.0.0` | – | – | – | 7 |")
+	assert.Contains(t, output, "| `github.com/example/other` | `v1.0.0` | – | – | – | – |")
+}
+
+func TestRenderMarkdownSeverity(t *testing.T) {
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Summary:     scanner.Summary{Severities: map[scanner.Severity]int{scanner.SeverityCritical: 1}},
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/old", Version: "v1.0.0", Status: scanner.StatusStale, Severity: scanner.SeverityCritical},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderMarkdown(&buf, result))
+	output := buf.String()
+
+	assert.Contains(t, output, "| 🚨 Critical findings | 1 |")
+	assert.Contains(t, output, "| 🔴 Inactive · 🚨 Critical | `github.com/example/old` |")
+}
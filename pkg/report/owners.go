@@ -0,0 +1,115 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Unowned is the group name of findings no owner rule matches
+const Unowned = "(unowned)"
+
+func init() {
+	MustRegister("owners", "Findings grouped by owning team", func(Options) (Renderer, error) {
+		return RendererFunc(renderOwners), nil
+	})
+}
+
+// OwnerGroup holds the findings one owner is responsible for
+type OwnerGroup struct {
+	Owner    string
+	Findings []scanner.Dependency
+}
+
+// Findings returns why a dependency needs attention: inactive, outdated,
+// vulnerable, relicensed or failed. Healthy dependencies have no findings.
+func Findings(dep scanner.Dependency) []string {
+	var findings []string
+	if dep.Error != nil {
+		findings = append(findings, "error: "+dep.Error.String())
+	} else if dep.Unresponsive && !dep.IsAcknowledged {
+		findings = append(findings, fmt.Sprintf("unresponsive for %d days in median", *dep.MedianResponseHours/24))
+	} else if dep.StaleFork && !dep.IsAcknowledged {
+		findings = append(findings, "stale fork of active "+dep.Upstream.Repository)
+	} else if dep.IsInactive() && !dep.IsAcknowledged {
+		findings = append(findings, fmt.Sprintf("inactive for %d days", dep.DaysSinceLastRelease))
+	}
+	if dep.Update != "" {
+		findings = append(findings, "update to "+dep.Update)
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		findings = append(findings, fmt.Sprintf("%d known vulnerabilities", len(dep.Vulnerabilities)))
+	}
+	if dep.LicenseChange != nil {
+		findings = append(findings, "license changed: "+dep.LicenseChange.String())
+	}
+	if dep.Retracted != nil {
+		findings = append(findings, "retracted: "+dep.Retracted.String())
+	}
+	if dep.Deprecated != "" {
+		findings = append(findings, "deprecated: "+dep.Deprecated)
+	}
+	if dep.BusFactorRisk {
+		findings = append(findings, fmt.Sprintf("bus factor: %d contributors in 12 months", *dep.ContributorCount))
+	}
+	return findings
+}
+
+// GroupByOwner groups all dependencies with findings by owner, so every
+// team can be notified about its own dependencies only. Dependencies with
+// several owners are part of each group. Groups are sorted by owner with
+// unowned findings last.
+func GroupByOwner(result *scanner.ScanResult) []OwnerGroup {
+	groups := make(map[string][]scanner.Dependency)
+	for _, dep := range result.Dependencies {
+		if len(Findings(dep)) == 0 {
+			continue
+		}
+		depOwners := dep.Owners
+		if len(depOwners) == 0 {
+			depOwners = []string{Unowned}
+		}
+		for _, owner := range depOwners {
+			groups[owner] = append(groups[owner], dep)
+		}
+	}
+
+	sorted := make([]OwnerGroup, 0, len(groups))
+	for owner, findings := range groups {
+		sorted = append(sorted, OwnerGroup{Owner: owner, Findings: findings})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if (sorted[i].Owner == Unowned) != (sorted[j].Owner == Unowned) {
+			return sorted[j].Owner == Unowned
+		}
+		return sorted[i].Owner < sorted[j].Owner
+	})
+	return sorted
+}
+
+func renderOwners(w io.Writer, result *scanner.ScanResult) error {
+	fmt.Fprintf(w, "\n=== Govital Findings by Owner ===\n")
+	fmt.Fprintf(w, "Project: %s\n", result.ProjectPath)
+
+	groups := GroupByOwner(result)
+	if len(groups) == 0 {
+		fmt.Fprintf(w, "\nNo findings.\n\n")
+		return nil
+	}
+
+	for _, group := range groups {
+		fmt.Fprintf(w, "\n%s (%d):\n", group.Owner, len(group.Findings))
+		for _, dep := range group.Findings {
+			severity := ""
+			if dep.Severity != "" {
+				severity = " [" + string(dep.Severity) + "]"
+			}
+			fmt.Fprintf(w, "  - %s@%s%s: %s\n", dep.Path, dep.Version, severity, strings.Join(Findings(dep), ", "))
+		}
+	}
+	fmt.Fprintf(w, "\n")
+	return nil
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ownedResult() *scanner.ScanResult {
+	return &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.0.0", IsActive: true, Owners: []string{"@cloud"}},
+			{Path: "github.com/example/stale", Version: "v0.1.0", DaysSinceLastRelease: 400, Owners: []string{"@cloud", "@security"}},
+			{Path: "github.com/example/acknowledged", Version: "v0.1.0", IsAcknowledged: true, Owners: []string{"@cloud"}},
+			{Path: "github.com/example/orphan", Version: "v1.0.0", IsActive: true, Update: "v1.1.0",
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+		},
+	}
+}
+
+func TestGroupByOwner(t *testing.T) {
+	groups := GroupByOwner(ownedResult())
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "@cloud", groups[0].Owner)
+	assert.Equal(t, "@security", groups[1].Owner)
+	assert.Equal(t, Unowned, groups[2].Owner)
+	require.Len(t, groups[0].Findings, 1)
+	assert.Equal(t, "github.com/example/stale", groups[0].Findings[0].Path)
+	assert.Equal(t, "github.com/example/orphan", groups[2].Findings[0].Path)
+}
+
+func TestFindings(t *testing.T) {
+	assert.Empty(t, Findings(scanner.Dependency{IsActive: true}))
+	assert.Empty(t, Findings(scanner.Dependency{IsAcknowledged: true}))
+	assert.Equal(t, []string{"inactive for 400 days"}, Findings(scanner.Dependency{DaysSinceLastRelease: 400}))
+	assert.Equal(t, []string{"error: not-found: lookup failed", "update to v2.0.0"},
+		Findings(scanner.Dependency{Error: &scanner.ScanError{Category: scanner.ErrorNotFound, Message: "lookup failed"}, Update: "v2.0.0"}))
+}
+
+func TestRenderOwners(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, renderOwners(&buf, ownedResult()))
+
+	assert.Contains(t, buf.String(), "@cloud (1):\n  - github.com/example/stale@v0.1.0: inactive for 400 days\n")
+	assert.Contains(t, buf.String(), "(unowned) (1):\n  - github.com/example/orphan@v1.0.0: update to v1.1.0, 1 known vulnerabilities\n")
+	assert.NotContains(t, buf.String(), "healthy")
+
+	buf.Reset()
+	require.NoError(t, renderOwners(&buf, &scanner.ScanResult{}))
+	assert.Contains(t, buf.String(), "No findings.")
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// WritePlan writes the plan of a dry run as text, one dependency per line
+// followed by the services queried for it
+func WritePlan(w io.Writer, plan *scanner.Plan) {
+	fmt.Fprintf(w, "Dry run of %s, no network access\n", plan.ProjectPath)
+	fmt.Fprintf(w, "GOPROXY: %s\n\n", plan.GoProxy)
+	for _, dep := range plan.Dependencies {
+		line := dep.Path + "@" + dep.Version
+		if dep.Replace != nil {
+			line += " => " + dep.Replace.Path
+			if dep.Replace.Version != "" {
+				line += "@" + dep.Replace.Version
+			}
+		}
+		if dep.IsIndirect {
+			line += " (indirect)"
+		}
+		if dep.Private {
+			line += " [private]"
+		}
+		fmt.Fprintln(w, line)
+		if dep.Repository != "" {
+			fmt.Fprintf(w, "    repository: %s\n", dep.Repository)
+		}
+		for _, source := range dep.Sources {
+			fmt.Fprintf(w, "    -> %s\n", source)
+		}
+		if dep.Note != "" {
+			fmt.Fprintf(w, "    %s\n", dep.Note)
+		}
+	}
+	fmt.Fprintf(w, "\n%d dependencies would be scanned\n", len(plan.Dependencies))
+}
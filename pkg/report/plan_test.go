@@ -0,0 +1,29 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePlan(t *testing.T) {
+	plan := &scanner.Plan{
+		ProjectPath: "example.com/app",
+		GoProxy:     "https://proxy.golang.org,direct",
+		Dependencies: []scanner.PlannedDependency{
+			{Path: "github.com/example/mod", Version: "v1.0.0", Repository: "https://github.com/example/mod", Sources: []string{"proxy https://proxy.golang.org", "licenses api.deps.dev"}},
+			{Path: "git.corp.example.com/team/lib", Version: "v0.3.0", IsIndirect: true, Private: true, Sources: []string{"git git.corp.example.com/team/lib"}},
+			{Path: "example.com/local", Version: "v1.0.0", Replace: &scanner.Replacement{Path: "../local"}, Note: "replaced by local directory ../local, not checked"},
+		},
+	}
+
+	var out bytes.Buffer
+	WritePlan(&out, plan)
+	assert.Contains(t, out.String(), "Dry run of example.com/app, no network access\nGOPROXY: https://proxy.golang.org,direct\n")
+	assert.Contains(t, out.String(), "github.com/example/mod@v1.0.0\n    repository: https://github.com/example/mod\n    -> proxy https://proxy.golang.org\n    -> licenses api.deps.dev\n")
+	assert.Contains(t, out.String(), "git.corp.example.com/team/lib@v0.3.0 (indirect) [private]\n")
+	assert.Contains(t, out.String(), "example.com/local@v1.0.0 => ../local\n    replaced by local directory ../local, not checked\n")
+	assert.Contains(t, out.String(), "3 dependencies would be scanned")
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// PluginPrefix is the executable name prefix of renderer plugins. A plugin
+// named govital-render-foo on the PATH provides the output format "foo".
+// It receives the scan result as JSON on stdin and writes the rendered
+// report to stdout.
+const PluginPrefix = "govital-render-"
+
+type pluginRenderer struct {
+	path string
+}
+
+func (p *pluginRenderer) Render(w io.Writer, result *scanner.ScanResult) error {
+	input, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan result for plugin %s: %w", p.path, err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(p.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("renderer plugin %s failed: %w: %s", p.path, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// findPlugin looks up the plugin executable for the given format name
+func findPlugin(name string) (string, bool) {
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// discoverPlugins lists all renderer plugins on the PATH. If the same plugin
+// exists in several directories the first one wins, like for exec.LookPath.
+func discoverPlugins() []Format {
+	seen := make(map[string]bool)
+	var plugins []Format
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if entry.IsDir() || !strings.HasPrefix(name, PluginPrefix) {
+				continue
+			}
+			format := strings.TrimPrefix(name, PluginPrefix)
+			if format == "" || seen[format] {
+				continue
+			}
+			path, found := findPlugin(format)
+			if !found {
+				continue
+			}
+			seen[format] = true
+			plugins = append(plugins, Format{Name: format, Description: "plugin", Plugin: path})
+		}
+	}
+	return plugins
+}
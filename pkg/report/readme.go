@@ -0,0 +1,84 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Markers delimit the generated section in a README. Everything between
+// them is replaced when the section is updated.
+const (
+	ReadmeStartMarker = "<!-- govital:start -->"
+	ReadmeEndMarker   = "<!-- govital:end -->"
+)
+
+// ReadmeSection returns a Markdown snippet with a health badge, the summary
+// table and the scan date, enclosed in the README markers
+func ReadmeSection(result *scanner.ScanResult, scannedAt time.Time) string {
+	summary := result.Summary
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", ReadmeStartMarker)
+	fmt.Fprintf(&b, "![Dependency health](%s)\n\n", badgeURL(summary))
+	fmt.Fprintf(&b, "| Dependencies | Count |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Total | %d |\n", summary.Total)
+	fmt.Fprintf(&b, "| Inactive | %d |\n", summary.Inactive)
+	fmt.Fprintf(&b, "| Update available | %d |\n", summary.Outdated)
+	fmt.Fprintf(&b, "| Up to date | %d |\n", summary.Updated)
+	if summary.Vulnerable > 0 {
+		fmt.Fprintf(&b, "| Vulnerable | %d |\n", summary.Vulnerable)
+	}
+	fmt.Fprintf(&b, "\n_Last scanned by [govital](https://github.com/steffakasid/govital) on %s._\n", scannedAt.UTC().Format("2006-01-02"))
+	fmt.Fprintf(&b, "%s\n", ReadmeEndMarker)
+	return b.String()
+}
+
+// badgeURL returns a shields.io badge with the number of inactive
+// dependencies, green if there are none
+func badgeURL(summary scanner.Summary) string {
+	message, color := "all active", "brightgreen"
+	switch {
+	case summary.Inactive > 0 && summary.Inactive*10 >= summary.Total:
+		message, color = fmt.Sprintf("%d inactive", summary.Inactive), "red"
+	case summary.Inactive > 0:
+		message, color = fmt.Sprintf("%d inactive", summary.Inactive), "yellow"
+	}
+	// Dashes separate the badge parts and have to be doubled in the text
+	escape := func(text string) string {
+		return url.PathEscape(strings.ReplaceAll(text, "-", "--"))
+	}
+	return fmt.Sprintf("https://img.shields.io/badge/%s-%s-%s", escape("dependencies"), escape(message), color)
+}
+
+// UpdateReadme replaces the region between the README markers with the
+// section. The markers must be present exactly once and in order.
+func UpdateReadme(readme []byte, section string) ([]byte, error) {
+	start := bytes.Index(readme, []byte(ReadmeStartMarker))
+	end := bytes.Index(readme, []byte(ReadmeEndMarker))
+	switch {
+	case start < 0 || end < 0:
+		return nil, fmt.Errorf("README has no %s ... %s region to update", ReadmeStartMarker, ReadmeEndMarker)
+	case end < start:
+		return nil, fmt.Errorf("%s comes before %s in README", ReadmeEndMarker, ReadmeStartMarker)
+	case bytes.Count(readme, []byte(ReadmeStartMarker)) > 1 || bytes.Count(readme, []byte(ReadmeEndMarker)) > 1:
+		return nil, fmt.Errorf("README contains more than one govital region")
+	}
+
+	// The section ends with the end marker and a newline, which replaces
+	// the newline after the existing end marker if there is one
+	end += len(ReadmeEndMarker)
+	if end < len(readme) && readme[end] == '\n' {
+		end++
+	}
+
+	var updated bytes.Buffer
+	updated.Write(readme[:start])
+	updated.WriteString(section)
+	updated.Write(readme[end:])
+	return updated.Bytes(), nil
+}
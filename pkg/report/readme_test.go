@@ -0,0 +1,63 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadmeSection(t *testing.T) {
+	result := &scanner.ScanResult{}
+	result.Summary.Total = 20
+	result.Summary.Inactive = 1
+	result.Summary.Outdated = 4
+	result.Summary.Updated = 16
+
+	section := ReadmeSection(result, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, `<!-- govital:start -->
+![Dependency health](https://img.shields.io/badge/dependencies-1%20inactive-yellow)
+
+| Dependencies | Count |
+|---|---:|
+| Total | 20 |
+| Inactive | 1 |
+| Update available | 4 |
+| Up to date | 16 |
+
+_Last scanned by [govital](https://github.com/steffakasid/govital) on 2024-03-01._
+<!-- govital:end -->
+`, section)
+}
+
+func TestBadgeURL(t *testing.T) {
+	assert.Equal(t, "https://img.shields.io/badge/dependencies-all%20active-brightgreen", badgeURL(scanner.Summary{Total: 5}))
+	assert.Equal(t, "https://img.shields.io/badge/dependencies-3%20inactive-red", badgeURL(scanner.Summary{Total: 5, Inactive: 3}))
+}
+
+func TestUpdateReadme(t *testing.T) {
+	readme := []byte("# Project\n\n<!-- govital:start -->\nold\n<!-- govital:end -->\n\n## Usage\n")
+
+	updated, err := UpdateReadme(readme, "<!-- govital:start -->\nnew\n<!-- govital:end -->\n")
+
+	require.NoError(t, err)
+	assert.Equal(t, "# Project\n\n<!-- govital:start -->\nnew\n<!-- govital:end -->\n\n## Usage\n", string(updated))
+}
+
+func TestUpdateReadmeInvalidRegion(t *testing.T) {
+	tests := map[string]string{
+		"no markers":       "# Project\n",
+		"missing end":      "<!-- govital:start -->\n",
+		"reversed markers": "<!-- govital:end -->\n<!-- govital:start -->\n",
+		"two regions":      "<!-- govital:start --><!-- govital:end --><!-- govital:start --><!-- govital:end -->",
+	}
+	for name, readme := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := UpdateReadme([]byte(readme), "")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Renderer writes a scan result in a specific output format
+type Renderer interface {
+	Render(w io.Writer, result *scanner.ScanResult) error
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(w io.Writer, result *scanner.ScanResult) error
+
+// Render calls f(w, result)
+func (f RendererFunc) Render(w io.Writer, result *scanner.ScanResult) error {
+	return f(w, result)
+}
+
+// Options carries format specific settings from the command line to a
+// renderer factory. Renderers ignore options they don't use.
+type Options struct {
+	// TemplateFile is the Go text/template of the template format
+	TemplateFile string
+	// ShowErrors lists all failed checks per dependency in the text format
+	ShowErrors bool
+}
+
+// Factory creates a renderer for the given options
+type Factory func(opts Options) (Renderer, error)
+
+// Format describes a registered output format
+type Format struct {
+	Name        string
+	Description string
+	// Plugin is the path of the external executable for plugin formats
+	Plugin string
+}
+
+type registration struct {
+	format  Format
+	factory Factory
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]registration)
+)
+
+// Register adds an output format to the registry. Registering the same name
+// twice is an error so that formats can't silently replace each other.
+func Register(name, description string, factory Factory) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("output format %q is already registered", name)
+	}
+	registry[name] = registration{
+		format:  Format{Name: name, Description: description},
+		factory: factory,
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant for
+// registering built-in formats from init functions.
+func MustRegister(name, description string, factory Factory) {
+	if err := Register(name, description, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns a renderer for the named format. Built-in formats take
+// precedence over plugins found on the PATH.
+func Get(name string, opts Options) (Renderer, error) {
+	registryMutex.RLock()
+	reg, ok := registry[name]
+	registryMutex.RUnlock()
+	if ok {
+		return reg.factory(opts)
+	}
+
+	if plugin, found := findPlugin(name); found {
+		return &pluginRenderer{path: plugin}, nil
+	}
+	return nil, fmt.Errorf("unsupported output format %q (see 'govital formats')", name)
+}
+
+// Formats returns all registered formats and discovered plugins sorted by name
+func Formats() []Format {
+	registryMutex.RLock()
+	formats := make([]Format, 0, len(registry))
+	for _, reg := range registry {
+		formats = append(formats, reg.format)
+	}
+	registryMutex.RUnlock()
+
+	for _, plugin := range discoverPlugins() {
+		if !isRegistered(plugin.Name) {
+			formats = append(formats, plugin)
+		}
+	}
+
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+	return formats
+}
+
+func isRegistered(name string) bool {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
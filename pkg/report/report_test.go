@@ -0,0 +1,114 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResult() *scanner.ScanResult {
+	result := &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/active", Version: "v1.0.0", IsActive: true},
+		},
+	}
+	result.Summary.Total = 1
+	result.Summary.StaleThresholdDays = 180
+	return result
+}
+
+func TestBuiltinFormats(t *testing.T) {
+	for _, name := range []string{"text", "json", "markdown", "html", "csv"} {
+		t.Run(name, func(t *testing.T) {
+			renderer, err := Get(name, Options{})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, renderer.Render(&buf, testResult()))
+			assert.Contains(t, buf.String(), "github.com/example/active")
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	renderer, err := Get("json", Options{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testResult()))
+
+	var decoded scanner.ScanResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "/test/project", decoded.ProjectPath)
+	assert.Equal(t, 1, decoded.Summary.Total)
+}
+
+func TestRegister(t *testing.T) {
+	factory := func(Options) (Renderer, error) {
+		return RendererFunc(func(w io.Writer, result *scanner.ScanResult) error {
+			_, err := io.WriteString(w, "custom:"+result.ProjectPath)
+			return err
+		}), nil
+	}
+
+	require.NoError(t, Register("test-custom", "Custom test format", factory))
+	assert.Error(t, Register("test-custom", "Duplicate", factory))
+	assert.Error(t, Register("text", "Replacing a built-in", factory))
+
+	renderer, err := Get("test-custom", Options{})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testResult()))
+	assert.Equal(t, "custom:/test/project", buf.String())
+
+	names := make([]string, 0)
+	for _, format := range Formats() {
+		names = append(names, format.Name)
+	}
+	assert.Contains(t, names, "test-custom")
+	assert.Contains(t, names, "text")
+}
+
+func TestGetUnknownFormat(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := Get("does-not-exist", Options{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported output format")
+}
+
+func TestPluginFormat(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test uses a shell script")
+	}
+
+	pluginDir := t.TempDir()
+	script := "#!/bin/sh\nwhile read -r line; do :; done\necho plugin-output\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, PluginPrefix+"fancy"), []byte(script), 0o755))
+	t.Setenv("PATH", pluginDir)
+
+	var found bool
+	for _, format := range Formats() {
+		if format.Name == "fancy" {
+			found = true
+			assert.NotEmpty(t, format.Plugin)
+		}
+	}
+	assert.True(t, found, "plugin format should be listed")
+
+	renderer, err := Get("fancy", Options{})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, testResult()))
+	assert.Equal(t, "plugin-output\n", buf.String())
+}
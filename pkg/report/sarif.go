@@ -0,0 +1,150 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/internal/version"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("sarif", "SARIF 2.1.0 log for GitHub code scanning and other static analysis viewers", func(Options) (Renderer, error) {
+		return RendererFunc(renderSARIF), nil
+	})
+}
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRules describe the kinds of findings, named like the checks of the
+// gitlab format
+var sarifRules = map[string]string{
+	"error":           "Dependency couldn't be scanned",
+	"vulnerable":      "Dependency has known vulnerabilities",
+	"inactive":        "Dependency is no longer maintained",
+	"retracted":       "Used version is retracted",
+	"deprecated":      "Module is deprecated",
+	"license-changed": "License changed since the previous scan",
+	"bus-factor":      "Dependency has too few maintainers",
+	"outdated":        "Newer version available",
+}
+
+// renderSARIF writes a SARIF log with a result per dependency with
+// findings, located at its go.mod line, with the levels of the annotations
+// of the github-actions format. Code scanning matches results across runs
+// by the fingerprint of module, version and kind of finding.
+func renderSARIF(w io.Writer, result *scanner.ScanResult) error {
+	run := sarifRun{Results: []sarifResult{}}
+	used := make(map[string]bool)
+	for _, dep := range result.Dependencies {
+		findings := Findings(dep)
+		if len(findings) == 0 {
+			continue
+		}
+
+		check := junitFailureType(dep)
+		if dep.Error != nil {
+			check = "error"
+		}
+		used[check] = true
+		level := "warning"
+		switch {
+		case dep.Severity == scanner.SeverityCritical:
+			level = "error"
+		case dep.Severity == scanner.SeverityInfo:
+			level = "note"
+		case dep.Severity == "" && (len(dep.Vulnerabilities) > 0 || dep.Retracted != nil):
+			level = "error"
+		}
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: annotationPath(result.ProjectPath, "go.mod")},
+			Region:           sarifRegion{StartLine: 1},
+		}
+		if dep.Location != nil {
+			location = sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: annotationPath(result.ProjectPath, dep.Location.File)},
+				Region:           sarifRegion{StartLine: dep.Location.Line},
+			}
+		}
+		sum := sha256.Sum256([]byte(strings.Join([]string{dep.Path, dep.Version, check}, "\x00")))
+		run.Results = append(run.Results, sarifResult{
+			RuleID:              "govital/" + check,
+			Level:               level,
+			Message:             sarifMessage{Text: "module " + dep.Path + "@" + dep.Version + " " + strings.Join(findings, ", ")},
+			Locations:           []sarifLocation{{PhysicalLocation: location}},
+			PartialFingerprints: map[string]string{"govital/v1": hex.EncodeToString(sum[:])},
+		})
+	}
+
+	run.Tool.Driver = sarifDriver{Name: "govital", Version: version.Version, InformationURI: "https://github.com/steffakasid/govital", Rules: []sarifRule{}}
+	for check := range used {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: "govital/" + check, ShortDescription: sarifMessage{Text: sarifRules[check]}})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}})
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	result := &scanner.ScanResult{
+		ProjectPath: "testdata/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true},
+			{Path: "github.com/example/stale", Version: "v0.1.0", DaysSinceLastRelease: 400,
+				Location: &scanner.Location{File: "go.mod", Line: 7, EndLine: 7}},
+			{Path: "github.com/example/vulnerable", Version: "v1.0.0", IsActive: true,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001"}}},
+			{Path: "github.com/example/outdated", Version: "v1.0.0", IsActive: true, Update: "v1.1.0", Severity: scanner.SeverityInfo},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderSARIF(&buf, result))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "govital", run.Tool.Driver.Name)
+	var rules []string
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+		assert.NotEmpty(t, rule.ShortDescription.Text)
+	}
+	assert.Equal(t, []string{"govital/inactive", "govital/outdated", "govital/vulnerable"}, rules)
+
+	require.Len(t, run.Results, 3)
+	stale := run.Results[0]
+	assert.Equal(t, "govital/inactive", stale.RuleID)
+	assert.Equal(t, "warning", stale.Level)
+	assert.Equal(t, "module github.com/example/stale@v0.1.0 inactive for 400 days", stale.Message.Text)
+	assert.Equal(t, "testdata/project/go.mod", stale.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 7, stale.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.NotEmpty(t, stale.PartialFingerprints["govital/v1"])
+
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, 1, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "note", run.Results[2].Level)
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+func init() {
+	MustRegister("template", "Custom format from a Go text/template given with --template-file", newTemplateRenderer)
+}
+
+// templateFuncs are the functions available to user templates in addition
+// to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":     func(values []string, sep string) string { return strings.Join(values, sep) },
+	"findings": Findings,
+	"direct":   func(deps []scanner.Dependency) []scanner.Dependency { return filterDependencies(deps, false) },
+	"indirect": func(deps []scanner.Dependency) []scanner.Dependency { return filterDependencies(deps, true) },
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// newTemplateRenderer parses the template file of the options, so syntax
+// errors are reported before the scan starts
+func newTemplateRenderer(opts Options) (Renderer, error) {
+	if opts.TemplateFile == "" {
+		return nil, fmt.Errorf("the template output format requires a template file")
+	}
+	text, err := os.ReadFile(opts.TemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(opts.TemplateFile)).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", opts.TemplateFile, err)
+	}
+
+	return RendererFunc(func(w io.Writer, result *scanner.ScanResult) error {
+		if err := tmpl.Execute(w, result); err != nil {
+			return fmt.Errorf("failed to execute template %s: %w", opts.TemplateFile, err)
+		}
+		return nil
+	}), nil
+}
+
+func filterDependencies(deps []scanner.Dependency, indirect bool) []scanner.Dependency {
+	var filtered []scanner.Dependency
+	for _, dep := range deps {
+		if dep.IsIndirect == indirect {
+			filtered = append(filtered, dep)
+		}
+	}
+	return filtered
+}
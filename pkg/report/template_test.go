@@ -0,0 +1,52 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wiki.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`h1. {{ .ProjectPath }}
+{{ range direct .Dependencies }}|{{ .Path }}|{{ .Version }}|{{ join (findings .) "; " }}|
+{{ end }}{{ len (indirect .Dependencies) }} indirect`), 0o600))
+
+	renderer, err := Get("template", Options{TemplateFile: path})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.Render(&buf, &scanner.ScanResult{
+		ProjectPath: "/test/project",
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/healthy", Version: "v1.2.0", IsActive: true},
+			{Path: "github.com/example/stale", Version: "v0.1.0", Update: "v0.3.0", DaysSinceLastRelease: 400},
+			{Path: "github.com/example/indirect", Version: "v1.0.0", IsActive: true, IsIndirect: true},
+		},
+	}))
+
+	assert.Equal(t, `h1. /test/project
+|github.com/example/healthy|v1.2.0||
+|github.com/example/stale|v0.1.0|inactive for 400 days; update to v0.3.0|
+1 indirect`, buf.String())
+}
+
+func TestTemplateRendererErrors(t *testing.T) {
+	_, err := Get("template", Options{})
+	assert.ErrorContains(t, err, "requires a template file")
+
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{ .ProjectPath "), 0o600))
+	_, err = Get("template", Options{TemplateFile: path})
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	require.NoError(t, os.WriteFile(path, []byte("{{ .Unknown }}"), 0o600))
+	renderer, err := Get("template", Options{TemplateFile: path})
+	require.NoError(t, err)
+	assert.ErrorContains(t, renderer.Render(&bytes.Buffer{}, &scanner.ScanResult{}), "failed to execute template")
+}
@@ -0,0 +1,309 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+)
+
+// renderText writes the human readable report of result, direct and
+// indirect dependencies each least healthy first. With ShowErrors all
+// failed checks are listed below each dependency.
+func renderText(w io.Writer, result *scanner.ScanResult, opts Options) error {
+	fmt.Fprintf(w, "\n=== Govital Dependency Scan Results ===\n")
+	fmt.Fprintf(w, "Project: %s\n", result.ProjectPath)
+	if result.Fingerprint != nil && result.Fingerprint.Revision != "" {
+		fmt.Fprintf(w, "Revision: %s\n", result.Fingerprint.Revision)
+	}
+	fmt.Fprintf(w, "Stale Threshold: %d days\n", result.Summary.StaleThresholdDays)
+	if result.Quick {
+		fmt.Fprintf(w, "Quick Scan: only release times were checked, results have lower confidence\n")
+	}
+	if result.Offline {
+		fmt.Fprintf(w, "Offline Scan: only the local module cache was read, updates may be missing\n")
+	}
+	fmt.Fprintf(w, "\n")
+
+	// Separate direct and indirect dependencies
+	var directDeps, indirectDeps []scanner.Dependency
+	for _, dep := range result.Dependencies {
+		if dep.IsIndirect {
+			indirectDeps = append(indirectDeps, dep)
+		} else {
+			directDeps = append(directDeps, dep)
+		}
+	}
+	// Least healthy dependencies first
+	scanner.SortByScore(directDeps)
+	scanner.SortByScore(indirectDeps)
+
+	// Count inactive dependencies by type
+	directInactive := 0
+	indirectInactive := 0
+	directUpdates := 0
+	indirectUpdates := 0
+	directAcknowledged := 0
+	indirectAcknowledged := 0
+	for _, dep := range directDeps {
+		if dep.IsInactive() {
+			if !dep.IsAcknowledged {
+				directInactive++
+			} else {
+				directAcknowledged++
+			}
+		}
+		if dep.Update != "" {
+			directUpdates++
+		}
+	}
+	for _, dep := range indirectDeps {
+		if dep.IsInactive() {
+			if !dep.IsAcknowledged {
+				indirectInactive++
+			} else {
+				indirectAcknowledged++
+			}
+		}
+		if dep.Update != "" {
+			indirectUpdates++
+		}
+	}
+
+	fmt.Fprintf(w, "Summary:\n")
+	fmt.Fprintf(w, "  Total Dependencies:        %d\n", result.Summary.Total)
+	fmt.Fprintf(w, "  Inactive Dependencies:     %d (Direct: %d, Indirect: %d)\n", result.Summary.Inactive, directInactive, indirectInactive)
+	if result.Summary.Unknown > 0 {
+		fmt.Fprintf(w, "  Unknown Status:            %d\n", result.Summary.Unknown)
+	}
+	fmt.Fprintf(w, "  Acknowledged:              %d (Direct: %d, Indirect: %d)\n", directAcknowledged+indirectAcknowledged, directAcknowledged, indirectAcknowledged)
+	fmt.Fprintf(w, "  Update Available:          %d (Direct: %d, Indirect: %d)\n", result.Summary.Outdated, directUpdates, indirectUpdates)
+	fmt.Fprintf(w, "  Up to Date:                %d\n", result.Summary.Updated)
+	fmt.Fprintf(w, "  Vulnerable:                %d (%d known vulnerabilities)\n", result.Summary.Vulnerable, result.Summary.Vulnerabilities)
+	if result.Summary.LicenseChanges > 0 {
+		fmt.Fprintf(w, "  License Changes:           %d\n", result.Summary.LicenseChanges)
+	}
+	if result.Summary.NewerMajor > 0 {
+		fmt.Fprintf(w, "  Newer Major Versions:      %d\n", result.Summary.NewerMajor)
+	}
+	if result.Summary.Retracted > 0 {
+		fmt.Fprintf(w, "  Retracted Versions:        %d\n", result.Summary.Retracted)
+	}
+	if result.Summary.Deprecated > 0 {
+		fmt.Fprintf(w, "  Deprecated Modules:        %d\n", result.Summary.Deprecated)
+	}
+	if result.Summary.BusFactorRisk > 0 {
+		fmt.Fprintf(w, "  Bus Factor Risk:           %d\n", result.Summary.BusFactorRisk)
+	}
+	if len(result.Summary.ErrorsByCategory) > 0 {
+		fmt.Fprintf(w, "  Errors:                    %d (%s)\n", result.Summary.Errors, errorBreakdown(result.Summary.ErrorsByCategory))
+	} else {
+		fmt.Fprintf(w, "  Errors:                    %d\n", result.Summary.Errors)
+	}
+	if len(result.Summary.Severities) > 0 {
+		fmt.Fprintf(w, "  Findings by Severity:      %s\n", severityBreakdown(result.Summary.Severities))
+	}
+
+	if len(result.Modules) > 0 {
+		heading := "Workspace Modules"
+		if result.Recursive {
+			heading = "Modules"
+		}
+		fmt.Fprintf(w, "\n%s (%d):\n", heading, len(result.Modules))
+		for _, mod := range result.Modules {
+			fmt.Fprintf(w, "  - %s: %d dependencies, %d inactive, %d updates available\n",
+				mod.Path, mod.Summary.Total, mod.Summary.Inactive, mod.Summary.Outdated)
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(w, "\nVersion Conflicts (%d):\n", len(result.Conflicts))
+		for _, conflict := range result.Conflicts {
+			note := ""
+			if conflict.MixedHealth {
+				note = " [MIXED: some versions inactive]"
+			}
+			fmt.Fprintf(w, "  - %s%s\n", conflict.Path, note)
+			for _, version := range conflict.Versions {
+				status := "✓ Active"
+				if !version.IsActive {
+					status = "✗ Inactive"
+				}
+				fmt.Fprintf(w, "      %s [%s] required by %s\n", version.Version, status, strings.Join(version.Modules, ", "))
+			}
+		}
+	}
+	if len(result.ToolchainFindings) > 0 {
+		fmt.Fprintf(w, "\nGo Toolchain (%d):\n", len(result.ToolchainFindings))
+		for _, finding := range result.ToolchainFindings {
+			fmt.Fprintf(w, "  - %s\n", finding)
+		}
+	}
+	fmt.Fprintf(w, "\nDependencies:\n")
+
+	if len(directDeps) > 0 {
+		fmt.Fprintf(w, "\nDirect Dependencies (%d):\n", len(directDeps))
+		writeTextDependencies(w, directDeps, opts.ShowErrors)
+	}
+	if len(indirectDeps) > 0 {
+		fmt.Fprintf(w, "\nIndirect Dependencies (%d):\n", len(indirectDeps))
+		writeTextDependencies(w, indirectDeps, opts.ShowErrors)
+	}
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// writeTextDependencies writes a line per dependency with its status and
+// findings, followed by its failed checks if showErrors is set
+func writeTextDependencies(w io.Writer, deps []scanner.Dependency, showErrors bool) {
+	for _, dep := range deps {
+		details := textDetails(dep)
+		if dep.Error != nil {
+			fmt.Fprintf(w, "  - %s@%s [ERROR: %s]%s\n", dep.Path, dep.Version, dep.Error, details)
+		} else if !dep.LastReleaseTime.IsZero() {
+			fmt.Fprintf(w, "  - %s@%s [%s] (last release: %d days ago)%s\n",
+				dep.Path, dep.Version, textStatus(dep), dep.DaysSinceLastRelease, details)
+		} else {
+			fmt.Fprintf(w, "  - %s@%s [%s]%s\n", dep.Path, dep.Version, textStatus(dep), details)
+		}
+		if showErrors {
+			writeTextErrors(w, dep.Errors)
+		}
+	}
+}
+
+// textDetails lists the score and findings of a dependency behind its
+// status. Only direct dependencies carry the risk of the indirect ones they
+// pull in, only indirect ones the chain they were introduced by.
+func textDetails(dep scanner.Dependency) string {
+	details := ""
+	if dep.Score != nil {
+		details = fmt.Sprintf(" (score: %d)", *dep.Score)
+	}
+	if dep.Severity != "" {
+		details += fmt.Sprintf(" [SEVERITY: %s]", dep.Severity)
+	}
+	if dep.Update != "" {
+		details += fmt.Sprintf(" [UPDATE: %s]", dep.Update)
+	} else if dep.Latest != "" {
+		details += " [Latest]"
+	}
+
+	if dep.NewerMajorAvailable != nil {
+		details += fmt.Sprintf(" [NEW MAJOR: %s]", dep.NewerMajorAvailable)
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		details += fmt.Sprintf(" [VULNERABLE: %s]", vulnerabilityIDs(dep.Vulnerabilities))
+	}
+	if dep.Module != "" {
+		details += fmt.Sprintf(" (module: %s)", dep.Module)
+	}
+	if len(dep.Owners) > 0 {
+		details += fmt.Sprintf(" (owners: %s)", strings.Join(dep.Owners, ", "))
+	}
+	if dep.ImportedByPackages != nil {
+		details += fmt.Sprintf(" (imported by %d packages)", *dep.ImportedByPackages)
+	}
+	if dep.Security != nil && dep.Security.String() != "" {
+		details += fmt.Sprintf(" (security: %s)", dep.Security)
+	}
+	if dep.Replace != nil {
+		details += fmt.Sprintf(" (replaced by %s)", strings.TrimSuffix(dep.Replace.Path+"@"+dep.Replace.Version, "@"))
+	}
+	if dep.LicenseChange != nil {
+		details += fmt.Sprintf(" [LICENSE CHANGED: %s]", dep.LicenseChange)
+	}
+	if dep.Retracted != nil {
+		details += fmt.Sprintf(" [RETRACTED: %s]", dep.Retracted)
+	}
+	if dep.Deprecated != "" {
+		details += fmt.Sprintf(" [DEPRECATED: %s]", dep.Deprecated)
+	}
+	if dep.BusFactorRisk {
+		details += fmt.Sprintf(" [BUS FACTOR: %d contributors in 12 months]", *dep.ContributorCount)
+	}
+	if dep.Unresponsive {
+		details += fmt.Sprintf(" [UNRESPONSIVE: %d days median response]", *dep.MedianResponseHours/24)
+	}
+	if dep.StaleFork {
+		details += fmt.Sprintf(" [STALE FORK: upstream %s is active]", dep.Upstream.Repository)
+	}
+	if !dep.IsIndirect && dep.TransitiveRisk != nil && len(dep.TransitiveRisk.Inactive) > 0 {
+		details += fmt.Sprintf(" [TRANSITIVE RISK: %s, pulls in %s]", dep.TransitiveRisk.Status, strings.Join(dep.TransitiveRisk.Inactive, ", "))
+	}
+	if dep.Note != "" {
+		details += fmt.Sprintf(" [NOTE: %s]", dep.Note)
+	}
+	if dep.IsIndirect && len(dep.IntroducedBy) > 0 {
+		details += fmt.Sprintf(" (introduced by %s)", strings.Join(dep.IntroducedBy, " > "))
+	}
+	return details
+}
+
+// textStatus returns the status shown in the text report
+func textStatus(dep scanner.Dependency) string {
+	switch {
+	case dep.IsInactive() && dep.IsAcknowledged:
+		return "⊘ Acknowledged"
+	case dep.IsInactive():
+		return "✗ Inactive"
+	case dep.Status.IsUnknown():
+		return "? Unknown"
+	default:
+		return "✓ Active"
+	}
+}
+
+// writeTextErrors lists the failed checks of a dependency with their stage
+func writeTextErrors(w io.Writer, errors []scanner.ScanError) {
+	for _, scanErr := range errors {
+		retry := ""
+		if scanErr.Retryable {
+			retry = " (retryable)"
+		}
+		fmt.Fprintf(w, "      ! %s: %s%s\n", scanErr.Stage, scanErr.String(), retry)
+	}
+}
+
+// vulnerabilityIDs returns a comma separated list of advisory IDs with severity
+func vulnerabilityIDs(vulnerabilities []vuln.Vulnerability) string {
+	ids := make([]string, len(vulnerabilities))
+	for i, v := range vulnerabilities {
+		ids[i] = v.ID
+		if v.Severity != vuln.SeverityUnknown {
+			ids[i] += " (" + v.Severity + ")"
+		}
+	}
+	return strings.Join(ids, ", ")
+}
+
+// errorBreakdown formats the error counts per category, e.g.
+// "not-found: 2, timeout: 1"
+func errorBreakdown(counts map[scanner.ErrorCategory]int) string {
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s: %d", category, counts[scanner.ErrorCategory(category)])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// severityBreakdown lists the counts from the highest severity down, e.g.
+// "critical: 1, warn: 3"
+func severityBreakdown(counts map[scanner.Severity]int) string {
+	var parts []string
+	for i := len(scanner.Severities) - 1; i >= 0; i-- {
+		if count := counts[scanner.Severities[i]]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", scanner.Severities[i], count))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
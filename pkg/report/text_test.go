@@ -0,0 +1,136 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderTextString(t *testing.T, result *scanner.ScanResult, opts Options) string {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, renderText(&out, result, opts))
+	return out.String()
+}
+
+func TestRenderText(t *testing.T) {
+	score := 42
+	contributors := 1
+	yes := true
+	result := &scanner.ScanResult{
+		ProjectPath: "example.com/app",
+		Summary: scanner.Summary{
+			Total: 3, Inactive: 1, Retracted: 1, StaleThresholdDays: 30,
+			Severities: map[scanner.Severity]int{scanner.SeverityCritical: 1},
+		},
+		Dependencies: []scanner.Dependency{
+			{
+				Path: "github.com/example/active", Version: "v1.0.0", Status: scanner.StatusActive, IsActive: true,
+				LastReleaseTime: time.Now().AddDate(0, 0, -10), DaysSinceLastRelease: 10, Latest: "v1.0.0",
+				NewerMajorAvailable: &scanner.MajorUpgrade{Path: "github.com/example/active/v3", Version: "v3.2.0"},
+				Replace:             &scanner.Replacement{Path: "github.com/fork/active", Version: "v1.0.0"},
+				Security:            &scanner.SecuritySignals{SecurityPolicy: &yes, CheckedTags: 1, BranchProtected: &yes},
+				TransitiveRisk:      &scanner.TransitiveRisk{Status: scanner.StatusStale, Dependencies: 2, Inactive: []string{"github.com/d/stale"}},
+			},
+			{
+				Path: "github.com/example/old", Version: "v1.0.0", Status: scanner.StatusStale, Score: &score,
+				Severity: scanner.SeverityCritical, Update: "v1.1.0", Retracted: &scanner.Retraction{Rationale: "Data corruption"},
+				Deprecated: "use example.com/mod/v2 instead.", BusFactorRisk: true, ContributorCount: &contributors,
+				Vulnerabilities: []vuln.Vulnerability{{ID: "GO-2024-0001", Severity: vuln.SeverityUnknown}},
+			},
+			{
+				Path: "github.com/example/local", Version: "v1.0.0", Status: scanner.StatusUnknown, IsActive: true, IsIndirect: true,
+				Replace: &scanner.Replacement{Path: "./local"}, Note: "replaced by local directory ./local, not checked",
+				IntroducedBy: []string{"github.com/example/active", "github.com/example/mid"},
+			},
+		},
+	}
+
+	out := renderTextString(t, result, Options{})
+	assert.Contains(t, out, "Project: example.com/app\nStale Threshold: 30 days\n")
+	assert.Contains(t, out, "Inactive Dependencies:     1 (Direct: 1, Indirect: 0)")
+	assert.Contains(t, out, "Retracted Versions:        1")
+	assert.Contains(t, out, "Findings by Severity:      critical: 1")
+	assert.Contains(t, out, "\nDirect Dependencies (2):\n  - github.com/example/old@v1.0.0 [✗ Inactive] (score: 42) [SEVERITY: critical] [UPDATE: v1.1.0] [VULNERABLE: GO-2024-0001]")
+	assert.Contains(t, out, "[RETRACTED: Data corruption] [DEPRECATED: use example.com/mod/v2 instead.] [BUS FACTOR: 1 contributors in 12 months]")
+	assert.Contains(t, out, "github.com/example/active@v1.0.0 [✓ Active] (last release: 10 days ago) [Latest] [NEW MAJOR: github.com/example/active/v3@v3.2.0]")
+	assert.Contains(t, out, "(security: security policy, 0/1 signed tags, protected branch) (replaced by github.com/fork/active@v1.0.0) [TRANSITIVE RISK: stale, pulls in github.com/d/stale]")
+	assert.Contains(t, out, "\nIndirect Dependencies (1):\n  - github.com/example/local@v1.0.0 [? Unknown] (replaced by ./local) [NOTE: replaced by local directory ./local, not checked] (introduced by github.com/example/active > github.com/example/mid)")
+}
+
+func TestRenderTextScanModes(t *testing.T) {
+	out := renderTextString(t, &scanner.ScanResult{Quick: true}, Options{})
+	assert.Contains(t, out, "Quick Scan: only release times were checked")
+
+	out = renderTextString(t, &scanner.ScanResult{Offline: true}, Options{})
+	assert.Contains(t, out, "Offline Scan: only the local module cache was read")
+
+	out = renderTextString(t, &scanner.ScanResult{
+		Recursive: true,
+		Modules: []scanner.ModuleResult{
+			{Path: "example.com/api", Summary: scanner.Summary{Total: 3}},
+			{Path: "example.com/worker", Summary: scanner.Summary{Total: 2, Inactive: 1}},
+		},
+	}, Options{})
+	assert.Contains(t, out, "\nModules (2):\n")
+	assert.Contains(t, out, "example.com/worker: 2 dependencies, 1 inactive, 0 updates available")
+}
+
+func TestRenderTextConflicts(t *testing.T) {
+	result := &scanner.ScanResult{Conflicts: []scanner.VersionConflict{{
+		Path: "github.com/example/mixed",
+		Versions: []scanner.ConflictVersion{
+			{Version: "v0.5.0", IsActive: true, Modules: []string{"example.com/alpha"}},
+			{Version: "v0.1.0", IsActive: false, Modules: []string{"example.com/beta", "example.com/gamma"}},
+		},
+		MixedHealth: true,
+	}}}
+
+	out := renderTextString(t, result, Options{})
+	assert.Contains(t, out, "Version Conflicts (1):\n  - github.com/example/mixed [MIXED: some versions inactive]\n")
+	assert.Contains(t, out, "      v0.1.0 [✗ Inactive] required by example.com/beta, example.com/gamma\n")
+}
+
+func TestRenderTextErrors(t *testing.T) {
+	scanErr := scanner.ScanError{Category: scanner.ErrorNotFound, Message: "returned status 404", Stage: scanner.StageProxy}
+	result := &scanner.ScanResult{
+		Summary: scanner.Summary{Errors: 2, ErrorsByCategory: map[scanner.ErrorCategory]int{scanner.ErrorNotFound: 2}},
+		Dependencies: []scanner.Dependency{
+			{Path: "github.com/example/missing", Version: "v1.0.0", Status: scanner.StatusError, Error: &scanErr, Errors: []scanner.ScanError{scanErr}},
+		},
+	}
+
+	out := renderTextString(t, result, Options{})
+	assert.Contains(t, out, "Errors:                    2 (not-found: 2)")
+	assert.Contains(t, out, "[ERROR: not-found: ")
+	assert.NotContains(t, out, "! proxy:")
+
+	out = renderTextString(t, result, Options{ShowErrors: true})
+	assert.Contains(t, out, "      ! proxy: not-found: ")
+}
+
+func TestBreakdowns(t *testing.T) {
+	assert.Equal(t, "critical: 1, warn: 2", severityBreakdown(map[scanner.Severity]int{scanner.SeverityWarn: 2, scanner.SeverityCritical: 1}))
+	assert.Equal(t, "not-found: 2, timeout: 1", errorBreakdown(map[scanner.ErrorCategory]int{scanner.ErrorTimeout: 1, scanner.ErrorNotFound: 2}))
+}
+
+// TestRenderTextWithRealData renders the scan of this repository
+func TestRenderTextWithRealData(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	s := scanner.NewScanner(filepath.Join("..", ".."))
+	s.SetStaleThreshold(30)
+	require.NoError(t, s.Scan(context.Background()))
+
+	out := renderTextString(t, s.GetResults(), Options{})
+	assert.Contains(t, out, "\nDirect Dependencies (")
+}
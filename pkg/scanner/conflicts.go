@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"sort"
+
+	"golang.org/x/mod/semver"
+)
+
+// VersionConflict is a dependency required at different versions by the
+// modules of a workspace
+type VersionConflict struct {
+	Path     string            `json:"path"`
+	Versions []ConflictVersion `json:"versions"`
+	// MixedHealth is set if some of the versions are inactive while others
+	// are maintained, i.e. converging on the maintained version helps
+	MixedHealth bool `json:"mixed_health"`
+}
+
+// ConflictVersion is one of the versions of a conflicting dependency
+type ConflictVersion struct {
+	Version  string `json:"version"`
+	IsActive bool   `json:"is_active"`
+	// Modules are the workspace modules requiring this version
+	Modules []string `json:"modules"`
+}
+
+// findConflicts returns the dependencies required at more than one version,
+// sorted by path with the newest version first
+func findConflicts(deps []Dependency) []VersionConflict {
+	byPath := make(map[string]map[string]*ConflictVersion)
+	for _, dep := range deps {
+		versions, ok := byPath[dep.Path]
+		if !ok {
+			versions = make(map[string]*ConflictVersion)
+			byPath[dep.Path] = versions
+		}
+		version, ok := versions[dep.Version]
+		if !ok {
+			version = &ConflictVersion{Version: dep.Version, IsActive: !dep.IsInactive() || dep.IsAcknowledged}
+			versions[dep.Version] = version
+		}
+		if dep.Module != "" {
+			version.Modules = append(version.Modules, dep.Module)
+		}
+	}
+
+	var conflicts []VersionConflict
+	for path, versions := range byPath {
+		if len(versions) < 2 {
+			continue
+		}
+
+		conflict := VersionConflict{Path: path}
+		active, inactive := false, false
+		for _, version := range versions {
+			sort.Strings(version.Modules)
+			conflict.Versions = append(conflict.Versions, *version)
+			active = active || version.IsActive
+			inactive = inactive || !version.IsActive
+		}
+		conflict.MixedHealth = active && inactive
+		sort.Slice(conflict.Versions, func(i, j int) bool {
+			return semver.Compare(conflict.Versions[i].Version, conflict.Versions[j].Version) > 0
+		})
+		conflicts = append(conflicts, conflict)
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	return conflicts
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConflicts(t *testing.T) {
+	deps := []Dependency{
+		{Path: "github.com/example/shared", Version: "v1.2.0", IsActive: true, Module: "example.com/alpha"},
+		{Path: "github.com/example/shared", Version: "v1.10.0", IsActive: true, Module: "example.com/beta"},
+		{Path: "github.com/example/shared", Version: "v1.2.0", IsActive: true, Module: "example.com/gamma"},
+		{Path: "github.com/example/mixed", Version: "v0.1.0", IsActive: false, Module: "example.com/beta"},
+		{Path: "github.com/example/mixed", Version: "v0.5.0", IsActive: true, Module: "example.com/alpha"},
+		{Path: "github.com/example/single", Version: "v1.0.0", IsActive: false, Module: "example.com/alpha"},
+		{Path: "github.com/example/single", Version: "v1.0.0", IsActive: false, Module: "example.com/beta"},
+	}
+
+	conflicts := findConflicts(deps)
+
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, VersionConflict{
+		Path: "github.com/example/mixed",
+		Versions: []ConflictVersion{
+			{Version: "v0.5.0", IsActive: true, Modules: []string{"example.com/alpha"}},
+			{Version: "v0.1.0", IsActive: false, Modules: []string{"example.com/beta"}},
+		},
+		MixedHealth: true,
+	}, conflicts[0])
+	assert.Equal(t, "github.com/example/shared", conflicts[1].Path)
+	assert.False(t, conflicts[1].MixedHealth)
+	require.Len(t, conflicts[1].Versions, 2)
+	assert.Equal(t, "v1.10.0", conflicts[1].Versions[0].Version)
+	assert.Equal(t, []string{"example.com/alpha", "example.com/gamma"}, conflicts[1].Versions[1].Modules)
+}
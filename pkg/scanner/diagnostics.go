@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/forge"
+)
+
+// maxLoggedModules limits the number of module paths listed in a summarized
+// log line. The full list is always kept in the diagnostics.
+const maxLoggedModules = 5
+
+// Diagnostic is a warning aggregated over all modules it occurred for
+type Diagnostic struct {
+	Level   string   `json:"level"`
+	Message string   `json:"message"`
+	Count   int      `json:"count"`
+	Modules []string `json:"modules"`
+}
+
+// proxyStatusError is returned when a proxy answers with a non-200 status
+type proxyStatusError struct {
+	Proxy      string
+	StatusCode int
+}
+
+func (e *proxyStatusError) Error() string {
+	return fmt.Sprintf("proxy %s returned status %d", e.Proxy, e.StatusCode)
+}
+
+// warningCollector aggregates warnings by message so that the same failure
+// for many modules is logged once at the end of a scan
+type warningCollector struct {
+	mutex    sync.Mutex
+	warnings map[string]*Diagnostic
+	order    []string
+}
+
+func newWarningCollector() *warningCollector {
+	return &warningCollector{warnings: make(map[string]*Diagnostic)}
+}
+
+// add records a warning for the given module
+func (c *warningCollector) add(message, modulePath string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	diagnostic, ok := c.warnings[message]
+	if !ok {
+		diagnostic = &Diagnostic{Level: "warn", Message: message}
+		c.warnings[message] = diagnostic
+		c.order = append(c.order, message)
+	}
+	diagnostic.Count++
+	diagnostic.Modules = append(diagnostic.Modules, modulePath)
+}
+
+// flush logs one summarized warning per message and returns the diagnostics
+func (c *warningCollector) flush() []Diagnostic {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	diagnostics := make([]Diagnostic, 0, len(c.order))
+	for _, message := range c.order {
+		diagnostic := *c.warnings[message]
+		sort.Strings(diagnostic.Modules)
+
+		if diagnostic.Count == 1 {
+			eslog.Logger.Warn(message, slog.String(logKeyModule, diagnostic.Modules[0]))
+		} else {
+			listed := diagnostic.Modules
+			suffix := ""
+			if len(listed) > maxLoggedModules {
+				suffix = fmt.Sprintf(" and %d more", len(listed)-maxLoggedModules)
+				listed = listed[:maxLoggedModules]
+			}
+			eslog.Logger.Warn(message, slog.Int("count", diagnostic.Count), slog.String("modules", strings.Join(listed, ", ")+suffix))
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	c.warnings = make(map[string]*Diagnostic)
+	c.order = nil
+	return diagnostics
+}
+
+// warningReason reduces an error to a module independent reason, so that
+// the same failure for different modules is aggregated into one warning
+func warningReason(err error) string {
+	var statusErr *proxyStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Error()
+	}
+
+	var forgeErr *forge.StatusError
+	if errors.As(err, &forgeErr) {
+		return fmt.Sprintf("%s returned status %d", requestHost(forgeErr.URL), forgeErr.StatusCode)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Sprintf("request to %s failed: %v", requestHost(urlErr.URL), urlErr.Err)
+	}
+	return err.Error()
+}
+
+func requestHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Host
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningCollector(t *testing.T) {
+	collector := newWarningCollector()
+
+	collector.add("proxy failed", "github.com/example/b")
+	collector.add("proxy failed", "github.com/example/a")
+	collector.add("other failure", "github.com/example/c")
+
+	diagnostics := collector.flush()
+
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, "proxy failed", diagnostics[0].Message)
+	assert.Equal(t, 2, diagnostics[0].Count)
+	assert.Equal(t, []string{"github.com/example/a", "github.com/example/b"}, diagnostics[0].Modules)
+	assert.Equal(t, "other failure", diagnostics[1].Message)
+	assert.Equal(t, 1, diagnostics[1].Count)
+
+	assert.Empty(t, collector.flush(), "flush should reset the collector")
+}
+
+func TestWarningReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "proxy status",
+			err:      fmt.Errorf("failed: %w", &proxyStatusError{Proxy: "https://proxy.example.com", StatusCode: 404}),
+			expected: "proxy https://proxy.example.com returned status 404",
+		},
+		{
+			name:     "network error without module path",
+			err:      &url.Error{Op: "Get", URL: "https://proxy.example.com/github.com/example/a/@v/list", Err: errors.New("connection refused")},
+			expected: "request to proxy.example.com failed: connection refused",
+		},
+		{
+			name:     "other errors are kept",
+			err:      errors.New("boom"),
+			expected: "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, warningReason(tt.err))
+		})
+	}
+}
+
+func TestScanDependenciesAggregatesWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/a", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/b", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/c", Version: "v1.0.0", IsActive: true},
+	})
+
+	require.NoError(t, err)
+	diagnostics := scanner.GetResults().Diagnostics
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, 3, diagnostics[0].Count)
+	assert.Contains(t, diagnostics[0].Message, "returned status 404")
+	assert.Len(t, diagnostics[0].Modules, 3)
+}
+
+func TestCheckVulnerabilitiesFailureIsWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetCheckVulnerabilities(true)
+	scanner.vulnClient.BaseURL = server.URL
+	scanner.SetRetries(transport.RetryConfig{})
+
+	deps := []*Dependency{{Path: "github.com/example/a", Version: "v1.0.0"}}
+	scanner.checkVulnerabilities(context.Background(), deps)
+
+	assert.Empty(t, deps[0].Vulnerabilities)
+	diagnostics := scanner.warnings.flush()
+	require.Len(t, diagnostics, 1)
+	assert.Contains(t, diagnostics[0].Message, "Failed to check vulnerabilities")
+}
@@ -0,0 +1,165 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/transport"
+	"golang.org/x/mod/module"
+)
+
+// ErrorCategory classifies why a dependency could not be checked, so
+// failures can be triaged without parsing messages
+type ErrorCategory string
+
+const (
+	// ErrorAuthFailure means the server rejected the credentials (401, 403)
+	ErrorAuthFailure ErrorCategory = "auth-failure"
+	// ErrorNotFound means the module or version is unknown (404, 410)
+	ErrorNotFound ErrorCategory = "not-found"
+	// ErrorTimeout means the request timed out
+	ErrorTimeout ErrorCategory = "timeout"
+	// ErrorRateLimited means the server throttled the requests (429)
+	ErrorRateLimited ErrorCategory = "rate-limited"
+	// ErrorParse means a module path, version or response was malformed
+	ErrorParse ErrorCategory = "parse-error"
+	// ErrorNetwork means the server could not be reached
+	ErrorNetwork ErrorCategory = "network"
+	// ErrorUnknown is used for all other failures, e.g. server errors
+	ErrorUnknown ErrorCategory = "unknown"
+)
+
+// ErrorStage names the step of the dependency check which failed
+type ErrorStage string
+
+const (
+	// StageProxy is the version lookup on the Go proxy, or in the module
+	// cache for offline scans
+	StageProxy ErrorStage = "proxy"
+	// StageGit is the version lookup of private modules, which the go
+	// command fetches from their version control system
+	StageGit ErrorStage = "git"
+	// StageResolve is the resolution of the source repository
+	StageResolve ErrorStage = "resolve"
+	// StageForge are the repository lookups on the forge API
+	StageForge ErrorStage = "forge"
+	// StageLicense is the license lookup
+	StageLicense ErrorStage = "license"
+	// StageVulnerability is the vulnerability lookup
+	StageVulnerability ErrorStage = "vulnerability"
+)
+
+// ScanError is the categorized failure of a dependency check
+type ScanError struct {
+	Category ErrorCategory `json:"category"`
+	Message  string        `json:"message"`
+	// Stage is the step which failed, empty for results saved before
+	// stages were recorded
+	Stage ErrorStage `json:"stage,omitempty"`
+	// Retryable is set for failures which may pass on another attempt,
+	// like timeouts, rate limits, network and server errors
+	Retryable bool `json:"retryable"`
+}
+
+// String returns the category and the message, e.g.
+// "not-found: proxy https://proxy.golang.org returned status 404"
+func (e *ScanError) String() string {
+	return string(e.Category) + ": " + e.Message
+}
+
+// newScanError categorizes err of the given stage
+func newScanError(stage ErrorStage, err error) *ScanError {
+	category := errorCategory(err)
+	return &ScanError{Category: category, Message: err.Error(), Stage: stage, Retryable: isRetryable(err, category)}
+}
+
+// addError records a failed check of the dependency and returns it
+func (d *Dependency) addError(stage ErrorStage, err error) *ScanError {
+	scanErr := newScanError(stage, err)
+	d.Errors = append(d.Errors, *scanErr)
+	return scanErr
+}
+
+// errorCategory determines the category of an error returned by a lookup
+func errorCategory(err error) ErrorCategory {
+	if code, ok := errorStatusCode(err); ok {
+		switch code {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ErrorAuthFailure
+		case http.StatusNotFound, http.StatusGone:
+			return ErrorNotFound
+		case http.StatusTooManyRequests:
+			return ErrorRateLimited
+		case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+			return ErrorTimeout
+		}
+		return ErrorUnknown
+	}
+
+	// Files missing from the module cache of offline scans
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrorNotFound
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorTimeout
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var versionErr *module.InvalidVersionError
+	var pathErr *module.InvalidPathError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.As(err, &versionErr) || errors.As(err, &pathErr) {
+		return ErrorParse
+	}
+
+	// Open circuits reject requests to hosts which kept failing
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var circuitErr *transport.CircuitOpenError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.As(err, &circuitErr) {
+		return ErrorNetwork
+	}
+	return ErrorUnknown
+}
+
+// errorStatusCode returns the HTTP status of a failed proxy or forge request
+func errorStatusCode(err error) (int, bool) {
+	var statusErr *proxyStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode, true
+	}
+	var forgeErr *forge.StatusError
+	if errors.As(err, &forgeErr) {
+		return forgeErr.StatusCode, true
+	}
+	return 0, false
+}
+
+// isRetryable reports whether a failure of the category may pass on
+// another attempt. Of the uncategorized failures only server errors do.
+func isRetryable(err error, category ErrorCategory) bool {
+	switch category {
+	case ErrorTimeout, ErrorRateLimited, ErrorNetwork:
+		return true
+	case ErrorUnknown:
+		code, ok := errorStatusCode(err)
+		return ok && code >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// addError counts a failure of the given category
+func (s *Summary) addError(category ErrorCategory) {
+	s.Errors++
+	if s.ErrorsByCategory == nil {
+		s.ErrorsByCategory = make(map[ErrorCategory]int)
+	}
+	s.ErrorsByCategory[category]++
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+)
+
+func TestErrorCategory(t *testing.T) {
+	var syntaxErr error = &json.SyntaxError{}
+	_, versionErr := module.EscapeVersion("v1.0.0!")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorCategory
+	}{
+		{"unauthorized", &proxyStatusError{StatusCode: 401}, ErrorAuthFailure},
+		{"forbidden", &proxyStatusError{StatusCode: 403}, ErrorAuthFailure},
+		{"not found", fmt.Errorf("all proxies failed: %w", &proxyStatusError{StatusCode: 404}), ErrorNotFound},
+		{"gone", &proxyStatusError{StatusCode: 410}, ErrorNotFound},
+		{"too many requests", &proxyStatusError{StatusCode: 429}, ErrorRateLimited},
+		{"gateway timeout", &proxyStatusError{StatusCode: 504}, ErrorTimeout},
+		{"server error", &proxyStatusError{StatusCode: 500}, ErrorUnknown},
+		{"deadline", fmt.Errorf("lookup: %w", context.DeadlineExceeded), ErrorTimeout},
+		{"malformed response", fmt.Errorf("decode: %w", syntaxErr), ErrorParse},
+		{"invalid version", versionErr, ErrorParse},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrorNetwork},
+		{"open circuit", &url.Error{Op: "Get", URL: "https://proxy.golang.org/x", Err: &transport.CircuitOpenError{Host: "proxy.golang.org"}}, ErrorNetwork},
+		{"forge rate limit", fmt.Errorf("contributors: %w", &forge.StatusError{URL: "https://api.github.com/x", StatusCode: 429}), ErrorRateLimited},
+		{"other", errors.New("boom"), ErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errorCategory(tt.err))
+		})
+	}
+}
+
+func TestScanDependenciesErrorCategories(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/mod", Version: "v0.9.0", IsActive: true},
+		{Path: "github.com/example/missing", Version: "v1.0.0", IsActive: true},
+	})
+	require.NoError(t, err)
+
+	result := scanner.GetResults()
+	require.Len(t, result.Dependencies, 3)
+	assert.Nil(t, result.Dependencies[0].Error)
+	require.NotNil(t, result.Dependencies[1].Error)
+	assert.Equal(t, ErrorNotFound, result.Dependencies[1].Error.Category)
+	assert.Contains(t, result.Dependencies[1].Error.Message, "returned status 404")
+	assert.Equal(t, 2, result.Summary.Errors)
+	assert.Equal(t, map[ErrorCategory]int{ErrorNotFound: 2}, result.Summary.ErrorsByCategory)
+
+	require.Len(t, result.Dependencies[1].Errors, 1)
+	assert.Equal(t, *result.Dependencies[1].Error, result.Dependencies[1].Errors[0])
+	assert.Equal(t, StageProxy, result.Dependencies[1].Errors[0].Stage)
+	assert.False(t, result.Dependencies[1].Errors[0].Retryable)
+}
+
+func TestNewScanErrorRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"not found", &proxyStatusError{StatusCode: 404}, false},
+		{"unauthorized", &forge.StatusError{StatusCode: 401}, false},
+		{"rate limited", &proxyStatusError{StatusCode: 429}, true},
+		{"server error", &proxyStatusError{StatusCode: 502}, true},
+		{"forge server error", &forge.StatusError{StatusCode: 500}, true},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanErr := newScanError(StageForge, tt.err)
+			assert.Equal(t, StageForge, scanErr.Stage)
+			assert.Equal(t, tt.retryable, scanErr.Retryable)
+		})
+	}
+}
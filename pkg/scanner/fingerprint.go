@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/tool"
+	"golang.org/x/mod/modfile"
+)
+
+// Fingerprint ties a scan result to the exact source state it was created
+// from. Results with different module paths belong to different projects.
+type Fingerprint struct {
+	// Module is the module path declared in go.mod, empty for a workspace
+	Module string `json:"module,omitempty"`
+	// GoModSHA256 is the hex encoded SHA-256 of go.mod, or of go.work when
+	// a workspace was scanned
+	GoModSHA256 string `json:"go_mod_sha256"`
+	// GoSumSHA256 is the hex encoded SHA-256 of go.sum (go.work.sum for a
+	// workspace), empty if the file doesn't exist
+	GoSumSHA256 string `json:"go_sum_sha256,omitempty"`
+	// Revision is the VCS commit of the project, empty if unknown
+	Revision string `json:"revision,omitempty"`
+}
+
+// NewFingerprint creates the fingerprint of the given go.mod and go.sum
+// content. goSum may be nil if the project has no go.sum.
+func NewFingerprint(goMod, goSum []byte) *Fingerprint {
+	fingerprint := &Fingerprint{
+		Module:      modfile.ModulePath(goMod),
+		GoModSHA256: sha256Hex(goMod),
+	}
+	if goSum != nil {
+		fingerprint.GoSumSHA256 = sha256Hex(goSum)
+	}
+	return fingerprint
+}
+
+// SameProject reports whether both fingerprints belong to the same project.
+// Fingerprints without a module path can't be told apart and always match.
+func (f *Fingerprint) SameProject(other *Fingerprint) bool {
+	if f == nil || other == nil || f.Module == "" || other.Module == "" {
+		return true
+	}
+	return f.Module == other.Module
+}
+
+// SetFingerprint overrides the fingerprint of the result, e.g. when the
+// dependencies were read from a git ref instead of the working tree
+func (s *Scanner) SetFingerprint(fingerprint *Fingerprint) {
+	s.result.Fingerprint = fingerprint
+}
+
+// fingerprintProject creates the fingerprint of the project directory.
+// Missing files or VCS information are not an error, the fingerprint is
+// just less specific then.
+func (s *Scanner) fingerprintProject(ctx context.Context, isWorkspace bool) *Fingerprint {
+	modName, sumName := "go.mod", "go.sum"
+	if isWorkspace {
+		modName, sumName = "go.work", "go.work.sum"
+	}
+
+	goMod, err := s.files.ReadFile(filepath.Join(s.projectPath, modName))
+	if err != nil {
+		logger(ctx).Debug("Failed to read the manifest for the fingerprint", slog.String("file", modName), slog.Any(logKeyError, err))
+		return nil
+	}
+	goSum, err := s.files.ReadFile(filepath.Join(s.projectPath, sumName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger(ctx).Debug("Failed to read the checksums for the fingerprint", slog.String("file", sumName), slog.Any(logKeyError, err))
+	}
+
+	fingerprint := NewFingerprint(goMod, goSum)
+	if !s.quick {
+		fingerprint.Revision = s.vcsRevision(ctx)
+	}
+	return fingerprint
+}
+
+// vcsRevision returns the git commit checked out in the project directory
+func (s *Scanner) vcsRevision(ctx context.Context) string {
+	out, err := s.executor.Execute(ctx, s.projectPath, nil, tool.Git, "rev-parse", "HEAD")
+	if err != nil {
+		logger(ctx).Debug("No VCS revision", slog.String("dir", s.projectPath), slog.Any(logKeyError, err))
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
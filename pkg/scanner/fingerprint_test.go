@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFingerprint(t *testing.T) {
+	goMod := []byte("module example.com/service\n\ngo 1.21\n")
+
+	fingerprint := NewFingerprint(goMod, nil)
+
+	assert.Equal(t, "example.com/service", fingerprint.Module)
+	assert.Len(t, fingerprint.GoModSHA256, 64)
+	assert.Empty(t, fingerprint.GoSumSHA256)
+	assert.Equal(t, fingerprint.GoModSHA256, NewFingerprint(goMod, nil).GoModSHA256)
+	assert.NotEqual(t, fingerprint.GoModSHA256, NewFingerprint(append(goMod, '\n'), nil).GoModSHA256)
+
+	withSum := NewFingerprint(goMod, []byte{})
+	assert.Len(t, withSum.GoSumSHA256, 64)
+}
+
+func TestFingerprintSameProject(t *testing.T) {
+	service := &Fingerprint{Module: "example.com/service", GoModSHA256: "a"}
+	changed := &Fingerprint{Module: "example.com/service", GoModSHA256: "b"}
+	other := &Fingerprint{Module: "example.com/other"}
+	workspace := &Fingerprint{}
+	var unknown *Fingerprint
+
+	assert.True(t, service.SameProject(changed))
+	assert.False(t, service.SameProject(other))
+	assert.True(t, service.SameProject(workspace))
+	assert.True(t, service.SameProject(unknown))
+	assert.True(t, unknown.SameProject(service))
+}
+
+func TestScanSetsFingerprint(t *testing.T) {
+	tmpDir := t.TempDir()
+	goMod := []byte("module example.com/service\n\ngo 1.21\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), goMod, 0o600))
+	scanner := NewScanner(tmpDir)
+
+	err := scanner.Scan(context.Background())
+
+	require.NoError(t, err)
+	fingerprint := scanner.GetResults().Fingerprint
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, "example.com/service", fingerprint.Module)
+	assert.Equal(t, sha256Hex(goMod), fingerprint.GoModSHA256)
+	assert.Empty(t, fingerprint.GoSumSHA256)
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// Upstream is the repository the source repository of a dependency was
+// forked from
+type Upstream struct {
+	// Repository is the repository root like github.com/owner/name
+	Repository string `json:"repository"`
+	Archived   bool   `json:"archived"`
+	// PushedAt is the time of the last push, zero if unknown
+	PushedAt time.Time `json:"pushed_at"`
+}
+
+// checkUpstream looks up the repository the source repository was forked
+// from. A dependency pinned to a fork nobody pushed to within the stale
+// threshold, while its upstream is still active, is a stale fork and
+// inactive, no matter how recent its releases are: fixes land upstream but
+// never reach the fork. Failures are reported as warning, the upstream is
+// just unknown then.
+func (s *Scanner) checkUpstream(ctx context.Context, dep *Dependency, info *forge.RepositoryInfo) {
+	upstream := &Upstream{Repository: info.Parent}
+	dep.Upstream = upstream
+
+	parent, err := s.forge.Repository(ctx, repo.Repository{Root: info.Parent, VCS: "git", URL: "https://" + info.Parent})
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, forge.ErrUnsupported) {
+			logFailure(ctx, StageForge, "Failed to look up upstream repository", err, "repository", info.Parent)
+			s.warnings.add("Failed to look up upstream repository: "+warningReason(err), dep.Path)
+			dep.addError(StageForge, err)
+		}
+		return
+	}
+	upstream.Archived = parent.Archived
+	upstream.PushedAt = parent.PushedAt
+
+	if info.PushedAt.IsZero() || parent.PushedAt.IsZero() || parent.Archived {
+		return
+	}
+	forkStale := s.isDependencyStale(dep.requirementPath(), int(s.now().Sub(info.PushedAt).Hours()/24))
+	upstreamStale := s.isDependencyStale(dep.requirementPath(), int(s.now().Sub(parent.PushedAt).Hours()/24))
+	if forkStale && !upstreamStale {
+		dep.StaleFork = true
+		dep.IsActive = false
+	}
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeForkForge serves github.com/example/mod as fork of
+// github.com/upstream/mod with the given days since the last pushes
+func newFakeForkForge(t *testing.T, forkPushed, upstreamPushed int) *httptest.Server {
+	t.Helper()
+	pushed := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			_, _ = w.Write([]byte(`[{"author":{"login":"alice"}},{"author":{"login":"bob"}}]`))
+		case r.URL.Path == "/repos/upstream/mod":
+			fmt.Fprintf(w, `{"pushed_at":%q}`, pushed(upstreamPushed))
+		default:
+			fmt.Fprintf(w, `{"fork":true,"pushed_at":%q,"parent":{"full_name":"upstream/mod"}}`, pushed(forkPushed))
+		}
+	}))
+}
+
+func TestScanDependenciesFork(t *testing.T) {
+	tests := []struct {
+		name           string
+		forkPushed     int
+		upstreamPushed int
+		expectedStale  bool
+	}{
+		{"stale fork of active upstream", 400, 10, true},
+		{"maintained fork", 10, 10, false},
+		{"fork of stale upstream", 400, 400, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+			defer server.Close()
+			t.Setenv("GOPROXY", server.URL)
+			github := newFakeForkForge(t, tt.forkPushed, tt.upstreamPushed)
+			defer github.Close()
+
+			scanner := NewScanner(".")
+			scanner.SetCheckRepositories(true, forge.Config{})
+			scanner.forge.GitHubURL = github.URL
+
+			err := scanner.ScanDependencies(context.Background(), []Dependency{
+				{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+			})
+
+			require.NoError(t, err)
+			dep := scanner.GetResults().Dependencies[0]
+			require.NotNil(t, dep.Upstream)
+			assert.Equal(t, "github.com/upstream/mod", dep.Upstream.Repository)
+			assert.False(t, dep.Upstream.PushedAt.IsZero())
+			assert.Equal(t, tt.expectedStale, dep.StaleFork)
+			assert.Equal(t, tt.expectedStale, dep.IsInactive(), "a stale fork is inactive despite a recent release")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/tool"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// gitEnv disables prompts, failing authentication must not block the scan
+var gitEnv = []string{"GIT_TERMINAL_PROMPT=0"}
+
+// directInfo answers the .info endpoint of a module fetched from its
+// repository without go list, which fetches the full tree of the version.
+// Pseudo-versions contain their commit time. For tags git ls-remote finds
+// the commit and a shallow treeless fetch of only that commit reads its
+// time, a few kilobytes even for large repositories. ok is false if the
+// version has to be looked up with go list instead.
+func (s *Scanner) directInfo(ctx context.Context, modulePath, version string) ([]byte, bool) {
+	if !semver.IsValid(version) {
+		return nil, false
+	}
+	if module.IsPseudoVersion(version) {
+		commitTime, err := module.PseudoVersionTime(version)
+		if err != nil {
+			return nil, false
+		}
+		body, err := json.Marshal(versionInfo{Version: version, Time: commitTime})
+		return body, err == nil
+	}
+
+	repository, err := s.resolver.Resolve(ctx, modulePath)
+	if err != nil || repository.VCS != "git" {
+		return nil, false
+	}
+	tag := changelog.TagPrefix(modulePath, repository.Root) + strings.TrimSuffix(version, "+incompatible")
+	commitTime, err := s.gitClient().GetCommitTime(ctx, repository.URL, tag)
+	if err != nil {
+		logFailure(ctx, StageGit, "Failed to read tag with git, using go list", err, "tag", tag, "repository", repository.URL)
+		return nil, false
+	}
+	body, err := json.Marshal(versionInfo{Version: version, Time: commitTime})
+	return body, err == nil
+}
+
+// commandGitClient is the GitClient running git with the CommandExecutor
+type commandGitClient struct {
+	executor CommandExecutor
+	files    FileReader
+}
+
+// GetCommitTime returns the commit time of a tag of a remote repository.
+// Only the commit object is transferred into a temporary bare repository.
+func (c *commandGitClient) GetCommitTime(ctx context.Context, repoURL, tag string) (time.Time, error) {
+	refs, err := c.run(ctx, "", "ls-remote", "--tags", repoURL, "refs/tags/"+tag, "refs/tags/"+tag+"^{}")
+	if err != nil {
+		return time.Time{}, err
+	}
+	commit := peeledCommit(refs)
+	if commit == "" {
+		return time.Time{}, fmt.Errorf("tag %s not found in %s", tag, repoURL)
+	}
+
+	dir, err := c.files.MkdirTemp("", "govital-git-")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create temporary repository: %w", err)
+	}
+	defer c.files.RemoveAll(dir)
+
+	if _, err := c.run(ctx, dir, "init", "--quiet", "--bare"); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := c.run(ctx, dir, "fetch", "--quiet", "--depth=1", "--filter=tree:0", "--no-tags", repoURL, "refs/tags/"+tag); err != nil {
+		return time.Time{}, err
+	}
+	output, err := c.run(ctx, dir, "log", "-1", "--format=%cI", commit)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
+// peeledCommit returns the commit a tag points to from the ls-remote
+// output. Annotated tags are listed twice, the peeled ^{} line names the
+// commit.
+func peeledCommit(refs []byte) string {
+	var commit string
+	for _, line := range strings.Split(strings.TrimSpace(string(refs)), "\n") {
+		hash, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(ref, "^{}") {
+			return hash
+		}
+		commit = hash
+	}
+	return commit
+}
+
+// run runs git in dir and returns its output. stderr is added to the
+// error.
+func (c *commandGitClient) run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	output, err := c.executor.Execute(ctx, dir, gitEnv, tool.Git, args...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return output, nil
+}
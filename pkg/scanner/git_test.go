@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTagRepository creates a git repository with a commit from 2024-01-02
+// tagged as v1.2.0 with an annotated tag
+func newTagRepository(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "release"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "tag", "-a", "-m", "v1.2.0", "v1.2.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(), "GIT_COMMITTER_DATE=2024-01-02T03:04:05Z", "GIT_AUTHOR_DATE=2024-01-02T03:04:05Z")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	return dir
+}
+
+func TestGitTagTime(t *testing.T) {
+	dir := newTagRepository(t)
+
+	commitTime, err := NewScanner(".").gitClient().GetCommitTime(context.Background(), "file://"+dir, "v1.2.0")
+
+	require.NoError(t, err)
+	assert.True(t, commitTime.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "got %v", commitTime)
+
+	_, err = NewScanner(".").gitClient().GetCommitTime(context.Background(), "file://"+dir, "v9.9.9")
+	assert.ErrorContains(t, err, "tag v9.9.9 not found")
+}
+
+func TestDirectInfoPseudoVersion(t *testing.T) {
+	scanner := NewScanner(".")
+
+	body, ok := scanner.directInfo(context.Background(), "git.example.com/private/mod", "v0.0.0-20240102030405-abcdefabcdef")
+
+	require.True(t, ok, "pseudo-versions need no lookup")
+	var info versionInfo
+	require.NoError(t, json.Unmarshal(body, &info))
+	assert.Equal(t, "v0.0.0-20240102030405-abcdefabcdef", info.Version)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), info.Time)
+
+	_, ok = scanner.directInfo(context.Background(), "git.example.com/private/mod", "latest")
+	assert.False(t, ok, "queries are resolved with go list")
+}
+
+func TestPeeledCommit(t *testing.T) {
+	assert.Equal(t, "bbb", peeledCommit([]byte("aaa\trefs/tags/v1.0.0\nbbb\trefs/tags/v1.0.0^{}\n")))
+	assert.Equal(t, "aaa", peeledCommit([]byte("aaa\trefs/tags/v1.0.0\n")))
+	assert.Empty(t, peeledCommit(nil))
+}
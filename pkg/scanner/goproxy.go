@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// defaultGoProxy is the GOPROXY of the go command if none is set
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// Special GOPROXY entries
+const (
+	// proxyDirect fetches modules from their repositories
+	proxyDirect = "direct"
+	// proxyOff disallows downloading modules
+	proxyOff = "off"
+)
+
+// errProxyOff is returned for lookups reaching GOPROXY=off
+var errProxyOff = errors.New("module lookup disabled by GOPROXY=off")
+
+// proxySpec is an entry of GOPROXY
+type proxySpec struct {
+	// url is the proxy URL without trailing slash, direct or off
+	url string
+	// fallBackOnError is set if the entry is followed by a pipe, the next
+	// entry is then tried after any error. After a comma the next entry is
+	// only tried if the module or version doesn't exist, 404 or 410.
+	fallBackOnError bool
+}
+
+// parseGoProxy parses a GOPROXY value like the go command: entries are
+// separated by commas or pipes, direct and off end the list, URLs without
+// scheme use https
+func parseGoProxy(value string) ([]proxySpec, error) {
+	if value == "" {
+		value = defaultGoProxy
+	}
+
+	var proxies []proxySpec
+	for value != "" {
+		var url string
+		fallBackOnError := false
+		if i := strings.IndexAny(value, ",|"); i >= 0 {
+			url = value[:i]
+			fallBackOnError = value[i] == '|'
+			value = value[i+1:]
+		} else {
+			url, value = value, ""
+		}
+
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		if url == proxyOff || url == proxyDirect {
+			// Both always end the lookup, later entries are ignored
+			proxies = append(proxies, proxySpec{url: url})
+			break
+		}
+		// Single words are reserved for built-in behaviors, everything
+		// else without scheme is a https URL
+		if strings.ContainsAny(url, ".:/") && !strings.Contains(url, ":/") && !filepath.IsAbs(url) && !path.IsAbs(url) {
+			url = "https://" + url
+		}
+		if !strings.Contains(url, "://") {
+			return nil, fmt.Errorf("invalid GOPROXY entry %q: must be a URL, direct or off", url)
+		}
+		proxies = append(proxies, proxySpec{url: strings.TrimSuffix(url, "/"), fallBackOnError: fallBackOnError})
+	}
+
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("GOPROXY list is not the empty string, but contains no entries")
+	}
+	return proxies, nil
+}
+
+// goProxyList returns the proxies of the GOPROXY environment variable,
+// https://proxy.golang.org,direct if it is not set
+func (s *Scanner) goProxyList() ([]proxySpec, error) {
+	return parseGoProxy(os.Getenv("GOPROXY"))
+}
+
+// isNotExist reports whether the error says the module or version doesn't
+// exist, after which the next proxy of a comma separated GOPROXY is tried
+func isNotExist(err error) bool {
+	var statusErr *proxyStatusError
+	return errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone)
+}
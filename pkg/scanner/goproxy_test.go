@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoProxy(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []proxySpec
+	}{
+		{"default", "", []proxySpec{{url: "https://proxy.golang.org"}, {url: proxyDirect}}},
+		{"single proxy", "https://custom.proxy.com/", []proxySpec{{url: "https://custom.proxy.com"}}},
+		{"comma and pipe", "https://first.proxy.com | https://second.proxy.com,https://third.proxy.com", []proxySpec{
+			{url: "https://first.proxy.com", fallBackOnError: true},
+			{url: "https://second.proxy.com"},
+			{url: "https://third.proxy.com"},
+		}},
+		{"scheme added", "proxy.example.com/go", []proxySpec{{url: "https://proxy.example.com/go"}}},
+		{"direct ends the list", "direct,https://ignored.proxy.com", []proxySpec{{url: proxyDirect}}},
+		{"off ends the list", "https://custom.proxy.com|off|direct", []proxySpec{{url: "https://custom.proxy.com", fallBackOnError: true}, {url: proxyOff}}},
+		{"empty entries are skipped", ",https://custom.proxy.com,,", []proxySpec{{url: "https://custom.proxy.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxies, err := parseGoProxy(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, proxies)
+		})
+	}
+
+	_, err := parseGoProxy(" , ")
+	assert.ErrorContains(t, err, "contains no entries")
+	_, err = parseGoProxy("nonsense")
+	assert.ErrorContains(t, err, "invalid GOPROXY entry")
+}
+
+func TestFetchFromProxyFallback(t *testing.T) {
+	var unavailableRequests int
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unavailableRequests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer unavailable.Close()
+	missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusGone)
+	}))
+	defer missing.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v1.0.0\n"))
+	}))
+	defer working.Close()
+
+	tests := []struct {
+		name        string
+		goproxy     string
+		expectedErr string
+	}{
+		{"comma falls back on not found", missing.URL + "," + working.URL, ""},
+		{"comma stops on other errors", unavailable.URL + "," + working.URL, "status 403"},
+		{"pipe falls back on any error", unavailable.URL + "|" + working.URL, ""},
+		{"off fails", missing.URL + ",off", "GOPROXY=off"},
+		{"more specific error is reported", unavailable.URL + "|" + missing.URL, "status 403"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOPROXY", tt.goproxy)
+			scanner := NewScanner(".")
+			scanner.SetPrivatePatterns("", "", "")
+
+			versions, err := scanner.getVersionListFromProxy(context.Background(), "github.com/example/mod")
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"v1.0.0"}, versions)
+		})
+	}
+	assert.Positive(t, unavailableRequests)
+}
@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// moduleGraph is the module requirement graph printed by go mod graph.
+// All versions of a module are merged into one node.
+type moduleGraph struct {
+	main  string
+	edges map[string][]string
+}
+
+// parseModuleGraph parses the output of go mod graph, the first module of
+// the output is the main module
+func parseModuleGraph(output []byte) (*moduleGraph, error) {
+	graph := &moduleGraph{edges: make(map[string][]string)}
+	lines := bufio.NewScanner(bytes.NewReader(output))
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid module graph line %q", lines.Text())
+		}
+
+		from, _, _ := strings.Cut(fields[0], "@")
+		to, _, _ := strings.Cut(fields[1], "@")
+		if graph.main == "" {
+			graph.main = from
+		}
+		graph.edges[from] = append(graph.edges[from], to)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read module graph: %w", err)
+	}
+	if graph.main == "" {
+		return nil, fmt.Errorf("module graph is empty")
+	}
+	return graph, nil
+}
+
+// chains returns the shortest requirement chain to each module reachable
+// from the main module, starting with the direct requirement pulling it in
+// and ending before the module itself. Since Go 1.17 the main module
+// requires every module of the build, so only its direct requirements are
+// followed. Direct requirements have no chain.
+func (g *moduleGraph) chains(direct map[string]bool) map[string][]string {
+	chains := make(map[string][]string)
+	var queue []string
+	for _, module := range g.edges[g.main] {
+		if direct[module] {
+			if _, seen := chains[module]; !seen {
+				chains[module] = nil
+				queue = append(queue, module)
+			}
+		}
+	}
+
+	// Breadth first, so the first chain found to a module is the shortest
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+		chain := append(slices.Clone(chains[module]), module)
+		for _, next := range g.edges[module] {
+			if _, seen := chains[next]; seen || next == g.main {
+				continue
+			}
+			chains[next] = chain
+			queue = append(queue, next)
+		}
+	}
+	return chains
+}
+
+// pulledIn returns the modules reachable from the module, sorted, without
+// the module itself and the main module
+func (g *moduleGraph) pulledIn(module string) []string {
+	seen := map[string]bool{module: true, g.main: true}
+	queue := []string{module}
+	var pulled []string
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, required := range g.edges[next] {
+			if !seen[required] {
+				seen[required] = true
+				pulled = append(pulled, required)
+				queue = append(queue, required)
+			}
+		}
+	}
+	slices.Sort(pulled)
+	return pulled
+}
+
+// setIntroducedBy records the requirement chain of every indirect
+// dependency, finishDependency keeps them for the inactive ones, and the
+// indirect dependencies each direct one pulls in for the transitive risk.
+// A failing go mod graph only loses the chains and the transitive risk.
+func (s *Scanner) setIntroducedBy(ctx context.Context, deps []Dependency, dir string, workspaceMember bool) {
+	direct := make(map[string]bool)
+	hasIndirect := false
+	for _, dep := range deps {
+		if dep.IsIndirect {
+			hasIndirect = true
+		} else {
+			direct[dep.Path] = true
+		}
+	}
+	if !hasIndirect {
+		return
+	}
+
+	output, err := s.runGo(ctx, dir, workspaceMember, "mod", "graph")
+	if err != nil {
+		logger(ctx).Warn("Failed to load the module graph (go mod graph)", slog.String("dir", dir), slog.Any(logKeyError, err))
+		return
+	}
+	graph, err := parseModuleGraph(output)
+	if err != nil {
+		logger(ctx).Warn("Failed to load the module graph", slog.String("dir", dir), slog.Any(logKeyError, err))
+		return
+	}
+
+	chains := graph.chains(direct)
+	indirect := make(map[string]bool)
+	for i := range deps {
+		if deps[i].IsIndirect {
+			deps[i].IntroducedBy = chains[deps[i].Path]
+			indirect[deps[i].Path] = true
+		}
+	}
+
+	if s.pulledIn == nil {
+		s.pulledIn = make(map[moduleDependency][]string)
+	}
+	for _, dep := range deps {
+		if dep.IsIndirect {
+			continue
+		}
+		var pulled []string
+		for _, module := range graph.pulledIn(dep.Path) {
+			if indirect[module] {
+				pulled = append(pulled, module)
+			}
+		}
+		s.pulledIn[moduleDependency{module: dep.Module, path: dep.Path}] = pulled
+	}
+}
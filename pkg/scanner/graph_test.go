@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleGraphChains(t *testing.T) {
+	graph, err := parseModuleGraph([]byte(`example.com/app github.com/a/direct@v1.0.0
+example.com/app github.com/b/direct@v1.0.0
+example.com/app github.com/c/indirect@v1.0.0
+example.com/app github.com/d/deep@v1.0.0
+github.com/a/direct@v1.0.0 github.com/c/indirect@v1.0.0
+github.com/b/direct@v1.0.0 github.com/e/mid@v1.0.0
+github.com/c/indirect@v1.0.0 github.com/f/mid@v1.0.0
+github.com/e/mid@v1.0.0 github.com/d/deep@v1.0.0
+github.com/f/mid@v1.0.0 github.com/d/deep@v1.1.0
+github.com/d/deep@v1.1.0 example.com/app@v0.1.0
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/app", graph.main)
+
+	chains := graph.chains(map[string]bool{"github.com/a/direct": true, "github.com/b/direct": true})
+	assert.Nil(t, chains["github.com/a/direct"], "direct requirements have no chain")
+	assert.Equal(t, []string{"github.com/a/direct"}, chains["github.com/c/indirect"], "not via the main module")
+	assert.Equal(t, []string{"github.com/b/direct", "github.com/e/mid"}, chains["github.com/d/deep"], "shortest chain")
+	assert.Equal(t, []string{"github.com/a/direct", "github.com/c/indirect"}, chains["github.com/f/mid"])
+	_, ok := chains["example.com/app"]
+	assert.False(t, ok, "main module")
+
+	assert.Equal(t, []string{"github.com/c/indirect", "github.com/d/deep", "github.com/f/mid"}, graph.pulledIn("github.com/a/direct"), "without the main module")
+	assert.Equal(t, []string{"github.com/d/deep", "github.com/e/mid"}, graph.pulledIn("github.com/b/direct"))
+
+	_, err = parseModuleGraph([]byte("a b c\n"))
+	assert.Error(t, err)
+	_, err = parseModuleGraph(nil)
+	assert.Error(t, err)
+}
+
+func TestFinishDependencyIntroducedBy(t *testing.T) {
+	scanner := NewScanner(".")
+	entry := Dependency{Path: "github.com/d/deep", IsIndirect: true, IntroducedBy: []string{"github.com/b/direct"}}
+
+	inactive := scanner.finishDependency(Dependency{Path: "github.com/d/deep"}, entry)
+	assert.Equal(t, []string{"github.com/b/direct"}, inactive.IntroducedBy)
+
+	active := scanner.finishDependency(Dependency{Path: "github.com/d/deep", IsActive: true, IntroducedBy: entry.IntroducedBy}, entry)
+	assert.Nil(t, active.IntroducedBy, "only inactive dependencies need a replacement")
+}
@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/steffakasid/govital/pkg/score"
+	"golang.org/x/mod/semver"
+)
+
+// maxCadenceLookups limits the number of release times fetched per module
+// to determine the release cadence
+const maxCadenceLookups = 5
+
+// SetScoreWeights sets the weights of the signals of the health score
+func (s *Scanner) SetScoreWeights(weights score.Weights) {
+	s.scoreEngine = score.NewEngine(weights)
+}
+
+// checkReleaseCadence sets LatestReleaseTime, DaysSinceLatestRelease and
+// ReleasesLastYear from the newest tagged releases, or Unreleased if the
+// listed versions contain none. Lookups stop at the first release older
+// than a year.
+func (s *Scanner) checkReleaseCadence(ctx context.Context, dep *Dependency, versions []string, listed bool) {
+	releases := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if semver.IsValid(v) && semver.Prerelease(v) == "" {
+			releases = append(releases, v)
+		}
+	}
+	dep.Unreleased = listed && len(releases) == 0
+	sort.Slice(releases, func(i, j int) bool {
+		return semver.Compare(releases[i], releases[j]) > 0
+	})
+
+	yearAgo := s.now().AddDate(-1, 0, 0)
+	for i, version := range releases {
+		if i == maxCadenceLookups {
+			break
+		}
+
+		releaseTime, err := s.getVersionInfoFromProxy(ctx, dep.Path, version)
+		if err != nil {
+			logFailure(ctx, s.lookupStage(ctx, dep.Path), "Failed to get release time", err, "release", version)
+			return
+		}
+		if dep.LatestReleaseTime.IsZero() {
+			dep.LatestReleaseTime = releaseTime
+			days := int(s.now().Sub(releaseTime).Hours() / 24)
+			dep.DaysSinceLatestRelease = &days
+		}
+		if releaseTime.Before(yearAgo) {
+			return
+		}
+		dep.ReleasesLastYear++
+	}
+}
+
+// scoreDependency computes the health score from the known signals
+func (s *Scanner) scoreDependency(dep *Dependency) {
+	var signals score.Signals
+
+	lastActivity := dep.LatestReleaseTime
+	if dep.LastReleaseTime.After(lastActivity) {
+		lastActivity = dep.LastReleaseTime
+	}
+	if !lastActivity.IsZero() {
+		days := int(s.now().Sub(lastActivity).Hours() / 24)
+		signals.DaysSinceLastActivity = &days
+	}
+	if !dep.LatestReleaseTime.IsZero() {
+		releases := dep.ReleasesLastYear
+		signals.ReleasesLastYear = &releases
+	}
+	signals.Archived = dep.Archived
+	signals.Contributors = dep.ContributorCount
+	if dep.Security != nil {
+		signals.Security = dep.Security.share()
+	}
+	signals.IssueResponse = issueResponse(dep)
+
+	if value, known := s.scoreEngine.Score(signals); known {
+		dep.Score = &value
+	}
+}
+
+// issueResponse rates how maintainers react to new issues: the share of
+// the sampled issues they answered at the speed of their median response.
+// Without new issues merged pull requests show maintainers at work, while
+// open issues with nothing merged count as ignored. nil if unknown.
+func issueResponse(dep *Dependency) *float64 {
+	var value float64
+	switch {
+	case dep.SampledIssues != nil && *dep.SampledIssues > 0:
+		if dep.MedianResponseHours != nil && dep.AnsweredIssues != nil {
+			answered := float64(*dep.AnsweredIssues) / float64(*dep.SampledIssues)
+			value = answered * score.ResponseSpeed(*dep.MedianResponseHours)
+		}
+	case dep.SampledIssues == nil && dep.MedianResponseHours != nil:
+		value = score.ResponseSpeed(*dep.MedianResponseHours)
+	case dep.MergedPullRequests != nil && *dep.MergedPullRequests > 0:
+		value = 1
+	case dep.MergedPullRequests != nil && dep.OpenIssues != nil && *dep.OpenIssues > 0:
+		value = 0
+	default:
+		return nil
+	}
+	return &value
+}
+
+// SortByScore orders dependencies from the lowest to the highest score.
+// Dependencies without score are kept at the end in their original order.
+// If imports were counted, inactive dependencies come first, the most
+// imported first, as they are the most expensive to replace.
+func SortByScore(deps []Dependency) {
+	sort.SliceStable(deps, func(i, j int) bool {
+		weightedI, weightedJ := usageWeighted(deps[i]), usageWeighted(deps[j])
+		if weightedI != weightedJ {
+			return weightedI
+		}
+		if weightedI && *deps[i].ImportedByPackages != *deps[j].ImportedByPackages {
+			return *deps[i].ImportedByPackages > *deps[j].ImportedByPackages
+		}
+		if deps[i].Score == nil || deps[j].Score == nil {
+			return deps[i].Score != nil
+		}
+		return *deps[i].Score < *deps[j].Score
+	})
+}
+
+// usageWeighted reports whether the dependency is prioritized by its
+// imports: it is inactive, not acknowledged and its imports were counted
+func usageWeighted(dep Dependency) bool {
+	return dep.ImportedByPackages != nil && dep.IsInactive() && !dep.IsAcknowledged
+}
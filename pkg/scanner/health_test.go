@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeProxy serves version infos for github.com/example/mod with the
+// given release ages in days
+func newFakeProxy(t *testing.T, releaseAges map[string]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/github.com/example/mod/@v/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		endpoint := strings.TrimPrefix(r.URL.Path, prefix)
+		if endpoint == "list" {
+			for version := range releaseAges {
+				fmt.Fprintln(w, version)
+			}
+			return
+		}
+		version := strings.TrimSuffix(endpoint, ".info")
+		age, ok := releaseAges[version]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		releaseTime := time.Now().AddDate(0, 0, -age).UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `{"Version":%q,"Time":%q}`, version, releaseTime)
+	}))
+}
+
+func TestCheckReleaseCadence(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{
+		"v1.3.0": 10,
+		"v1.2.0": 100,
+		"v1.1.0": 300,
+		"v1.0.0": 500,
+	})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	dep := &Dependency{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true}
+
+	err := scanner.checkMaintenanceStatus(context.Background(), dep)
+
+	require.NoError(t, err)
+	assert.Equal(t, "v1.3.0", dep.Latest)
+	assert.Equal(t, "v1.3.0", dep.Update)
+	assert.Equal(t, 3, dep.ReleasesLastYear)
+	assert.Equal(t, 10, int(time.Since(dep.LatestReleaseTime).Hours()/24))
+	assert.Equal(t, 500, dep.DaysSinceLastRelease)
+	assert.False(t, dep.IsActive)
+
+	scanner.scoreDependency(dep)
+	require.NotNil(t, dep.Score)
+	// Fresh latest release, three of four expected releases last year:
+	// (0.4*1 + 0.2*0.75) / 0.6
+	assert.Equal(t, 92, *dep.Score)
+}
+
+func TestReleaseThreshold(t *testing.T) {
+	const pseudo = "v1.0.1-0.20240101000000-abcdefabcdef"
+	tests := []struct {
+		name        string
+		releases    map[string]int
+		threshold   int
+		active      bool
+		unreleased  bool
+		releaseDays int
+	}{
+		{name: "disabled", releases: map[string]int{"v1.0.0": 500, pseudo: 10}, active: true, releaseDays: 500},
+		{name: "old release", releases: map[string]int{"v1.0.0": 500, pseudo: 10}, threshold: 365, active: false, releaseDays: 500},
+		{name: "recent release", releases: map[string]int{"v1.0.0": 100, pseudo: 10}, threshold: 365, active: true, releaseDays: 100},
+		{name: "unreleased", releases: map[string]int{pseudo: 10}, threshold: 365, active: false, unreleased: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeProxy(t, tt.releases)
+			defer server.Close()
+			t.Setenv("GOPROXY", server.URL)
+
+			scanner := NewScanner(".")
+			scanner.SetReleaseThreshold(tt.threshold)
+			dep := &Dependency{Path: "github.com/example/mod", Version: pseudo, IsActive: true}
+
+			err := scanner.checkMaintenanceStatus(context.Background(), dep)
+
+			require.NoError(t, err)
+			assert.Equal(t, 10, dep.DaysSinceLastRelease)
+			assert.Equal(t, tt.active, dep.IsActive)
+			assert.Equal(t, tt.unreleased, dep.Unreleased)
+			if tt.releaseDays == 0 {
+				assert.Nil(t, dep.DaysSinceLatestRelease)
+			} else {
+				require.NotNil(t, dep.DaysSinceLatestRelease)
+				assert.Equal(t, tt.releaseDays, *dep.DaysSinceLatestRelease)
+			}
+		})
+	}
+}
+
+func TestScoreDependencyUnknown(t *testing.T) {
+	scanner := NewScanner(".")
+	dep := &Dependency{Path: "github.com/example/unknown"}
+
+	scanner.scoreDependency(dep)
+
+	assert.Nil(t, dep.Score)
+}
+
+func TestScoreDependencyIssueResponse(t *testing.T) {
+	scanner := NewScanner(".")
+	prompt, ignored := 24, 60*24
+	responsive := &Dependency{Path: "github.com/example/responsive", MedianResponseHours: &prompt}
+	unresponsive := &Dependency{Path: "github.com/example/unresponsive", MedianResponseHours: &ignored}
+
+	scanner.scoreDependency(responsive)
+	scanner.scoreDependency(unresponsive)
+
+	require.NotNil(t, responsive.Score)
+	require.NotNil(t, unresponsive.Score)
+	assert.Equal(t, 100, *responsive.Score)
+	assert.Equal(t, 0, *unresponsive.Score)
+}
+
+func TestIssueResponse(t *testing.T) {
+	intPtr := func(value int) *int { return &value }
+	floatPtr := func(value float64) *float64 { return &value }
+	tests := []struct {
+		name     string
+		dep      Dependency
+		expected *float64
+	}{
+		{name: "unknown", dep: Dependency{OpenIssues: intPtr(3)}},
+		{name: "half answered promptly", dep: Dependency{SampledIssues: intPtr(4), AnsweredIssues: intPtr(2), MedianResponseHours: intPtr(5)}, expected: floatPtr(0.5)},
+		{name: "none answered", dep: Dependency{SampledIssues: intPtr(4), AnsweredIssues: intPtr(0), MergedPullRequests: intPtr(3)}, expected: floatPtr(0.0)},
+		{name: "no new issues, merging", dep: Dependency{SampledIssues: intPtr(0), MergedPullRequests: intPtr(3)}, expected: floatPtr(1.0)},
+		{name: "open issues, nothing merged", dep: Dependency{SampledIssues: intPtr(0), MergedPullRequests: intPtr(0), OpenIssues: intPtr(7)}, expected: floatPtr(0.0)},
+		{name: "quiet repository", dep: Dependency{SampledIssues: intPtr(0), MergedPullRequests: intPtr(0), OpenIssues: intPtr(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := issueResponse(&tt.dep)
+			if tt.expected == nil {
+				assert.Nil(t, value)
+				return
+			}
+			require.NotNil(t, value)
+			assert.InDelta(t, *tt.expected, *value, 0.001)
+		})
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	low, high := 10, 90
+	deps := []Dependency{
+		{Path: "unscored-1"},
+		{Path: "high", Score: &high},
+		{Path: "unscored-2"},
+		{Path: "low", Score: &low},
+	}
+
+	SortByScore(deps)
+
+	paths := make([]string, len(deps))
+	for i, dep := range deps {
+		paths[i] = dep.Path
+	}
+	assert.Equal(t, []string{"low", "high", "unscored-1", "unscored-2"}, paths)
+}
+
+func TestSortByScoreImports(t *testing.T) {
+	low, high := 10, 90
+	few, many := 1, 12
+	deps := []Dependency{
+		{Path: "active", Score: &low, Status: StatusActive, IsActive: true, ImportedByPackages: &many},
+		{Path: "inactive-few", Score: &low, Status: StatusStale, ImportedByPackages: &few},
+		{Path: "acknowledged", Score: &low, Status: StatusStale, IsAcknowledged: true, ImportedByPackages: &many},
+		{Path: "inactive-many", Score: &high, Status: StatusStale, ImportedByPackages: &many},
+	}
+
+	SortByScore(deps)
+
+	paths := make([]string, len(deps))
+	for i, dep := range deps {
+		paths[i] = dep.Path
+	}
+	assert.Equal(t, []string{"inactive-many", "inactive-few", "active", "acknowledged"}, paths)
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// packageListFormat prints the import path, the module and whether the
+// package is only a dependency of the listed packages, followed by all
+// packages it imports directly or transitively
+const packageListFormat = `{{.ImportPath}}	{{with .Module}}{{.Path}}{{end}}	{{if .DepOnly}}dep{{end}}	{{join .Deps " "}}`
+
+// SetCountImports enables counting the packages of the project which
+// import each dependency, directly or through other modules. It runs go
+// list -deps on the project, which needs the sources of all used modules,
+// and doesn't apply to quick scans.
+func (s *Scanner) SetCountImports(count bool) {
+	s.countImports = count
+}
+
+// parsePackageList counts the packages of the project importing a package
+// of each module in the output of go list -deps -f packageListFormat.
+// Standard library packages and the modules of the project are skipped.
+func parsePackageList(output []byte) (map[string]int, error) {
+	type listedPackage struct {
+		module string
+		deps   []string
+	}
+	packageModules := make(map[string]string)
+	var projectPackages []listedPackage
+	lines := bufio.NewScanner(bytes.NewReader(output))
+	lines.Buffer(nil, 16*1024*1024)
+	for lines.Scan() {
+		if lines.Text() == "" {
+			continue
+		}
+		fields := strings.Split(lines.Text(), "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid package list line %q", lines.Text())
+		}
+		importPath, module, depOnly := fields[0], fields[1], fields[2] == "dep"
+		packageModules[importPath] = module
+		if !depOnly {
+			projectPackages = append(projectPackages, listedPackage{module: module, deps: strings.Fields(fields[3])})
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read package list: %w", err)
+	}
+
+	projectModules := make(map[string]bool)
+	for _, pkg := range projectPackages {
+		projectModules[pkg.module] = true
+	}
+	counts := make(map[string]int)
+	for _, pkg := range projectPackages {
+		imported := make(map[string]bool)
+		for _, dep := range pkg.deps {
+			module := packageModules[dep]
+			if module != "" && !projectModules[module] {
+				imported[module] = true
+			}
+		}
+		for module := range imported {
+			counts[module]++
+		}
+	}
+	return counts, nil
+}
+
+// setImportedBy sets the number of packages of the project in dir
+// importing each dependency. Test files are not counted, so dependencies
+// only used by tests have no importing packages. A failing go list only
+// loses the counts.
+func (s *Scanner) setImportedBy(ctx context.Context, deps []Dependency, dir string, workspaceMember bool) {
+	// -e lists packages with errors as well instead of failing
+	output, err := s.runGo(ctx, dir, workspaceMember, "list", "-e", "-deps", "-f", packageListFormat, "./...")
+	if err != nil {
+		logger(ctx).Warn("Failed to list the packages (go list -deps)", slog.String("dir", dir), slog.Any(logKeyError, err))
+		return
+	}
+	counts, err := parsePackageList(output)
+	if err != nil {
+		logger(ctx).Warn("Failed to list the packages", slog.String("dir", dir), slog.Any(logKeyError, err))
+		return
+	}
+
+	for i := range deps {
+		count := counts[deps[i].Path]
+		deps[i].ImportedByPackages = &count
+	}
+}
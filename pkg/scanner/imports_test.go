@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageList(t *testing.T) {
+	counts, err := parsePackageList([]byte("fmt\t\tdep\terrors io\n" +
+		"github.com/a/lib\tgithub.com/a/lib\tdep\tfmt\n" +
+		"github.com/a/lib/sub\tgithub.com/a/lib\tdep\tfmt github.com/b/deep\n" +
+		"github.com/b/deep\tgithub.com/b/deep\tdep\t\n" +
+		"example.com/app\texample.com/app\t\tfmt github.com/a/lib github.com/a/lib/sub github.com/b/deep example.com/app/internal\n" +
+		"example.com/app/internal\texample.com/app\t\tgithub.com/a/lib fmt\n" +
+		"example.com/app/cmd\texample.com/app\t\texample.com/app/internal\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"github.com/a/lib": 2, "github.com/b/deep": 1}, counts,
+		"packages count once per module, the project and the standard library are skipped")
+
+	_, err = parsePackageList([]byte("fmt\n"))
+	assert.Error(t, err)
+}
+
+func TestSetImportedBy(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n\nrequire (\n\texample.com/lib v0.0.0\n\texample.com/unused v0.0.0\n)\n\nreplace example.com/lib => ./lib\n\nreplace example.com/unused => ./unused\n",
+		"main.go":       "package main\n\nimport \"example.com/lib\"\n\nfunc main() { lib.Hello() }\n",
+		"util/util.go":  "package util\n\nimport \"example.com/lib\"\n\nvar Hello = lib.Hello\n",
+		"plain/p.go":    "package plain\n",
+		"lib/go.mod":    "module example.com/lib\n\ngo 1.21\n",
+		"lib/lib.go":    "package lib\n\nfunc Hello() {}\n",
+		"unused/go.mod": "module example.com/unused\n\ngo 1.21\n",
+		"unused/u.go":   "package unused\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	scanner := NewScanner(dir)
+	deps := []Dependency{{Path: "example.com/lib"}, {Path: "example.com/unused"}}
+	scanner.setImportedBy(context.Background(), deps, dir, false)
+
+	require.NotNil(t, deps[0].ImportedByPackages)
+	assert.Equal(t, 2, *deps[0].ImportedByPackages)
+	require.NotNil(t, deps[1].ImportedByPackages)
+	assert.Equal(t, 0, *deps[1].ImportedByPackages)
+}
@@ -0,0 +1,90 @@
+package scanner
+
+import "strings"
+
+// SetReusable sets the dependencies of a previous scan whose lookups are
+// reused for requirements with the same version and replacement, so
+// incremental scans only look up new and updated modules. Dependencies
+// with errors or without release time are looked up again. The caller
+// makes sure they were scanned with the same Lookups and are recent
+// enough.
+func (s *Scanner) SetReusable(deps []Dependency) {
+	s.reusable = make(map[string]Dependency, len(deps))
+	for _, dep := range deps {
+		if dep.Error == nil && len(dep.Errors) == 0 && !dep.LastReleaseTime.IsZero() {
+			s.reusable[dep.scanKey()] = dep
+		}
+	}
+}
+
+// Lookups names the enabled lookups besides the release times and the
+// vulnerabilities, which decide the fields of a scanned dependency.
+// Dependencies of a scan with other lookups must not be reused.
+func (s *Scanner) Lookups() string {
+	var lookups []string
+	if s.licenseClient != nil {
+		lookups = append(lookups, "licenses")
+	}
+	if s.forge != nil {
+		lookups = append(lookups, "repositories")
+		if s.checkResponsiveness {
+			lookups = append(lookups, "responsiveness")
+		}
+		if s.fetchReleaseNotes {
+			lookups = append(lookups, "release-notes")
+		}
+		if s.checkSecuritySignals {
+			lookups = append(lookups, "security-signals")
+		}
+	}
+	return strings.Join(lookups, ",")
+}
+
+// reuseDependencies replaces the queued dependencies found among the
+// reusable ones with their previous lookups. It returns the dependencies
+// still to be looked up and the reused ones. Quick and offline scans
+// don't reuse anything, their results lack most lookups.
+func (s *Scanner) reuseDependencies(queue []*Dependency) ([]*Dependency, []*Dependency) {
+	if len(s.reusable) == 0 || s.quick || s.offline {
+		return queue, nil
+	}
+	var lookups, reused []*Dependency
+	for _, dep := range queue {
+		previous, ok := s.reusable[dep.scanKey()]
+		if !ok || dep.Replace.IsLocal() {
+			lookups = append(lookups, dep)
+			continue
+		}
+		s.reuse(dep, previous)
+		reused = append(reused, dep)
+	}
+	return lookups, reused
+}
+
+// reuse replaces dep with its previous lookups. The fields from the module
+// listing and the vulnerabilities, which are always looked up, are kept.
+// Ages, stale checks and the score follow the current time and thresholds,
+// a stale fork stays inactive until it is looked up again.
+func (s *Scanner) reuse(dep *Dependency, previous Dependency) {
+	listed := *dep
+	*dep = previous
+	dep.Module = listed.Module
+	dep.IsIndirect = listed.IsIndirect
+	dep.GoVersion = listed.GoVersion
+	dep.Location = listed.Location
+	dep.IntroducedBy = listed.IntroducedBy
+	dep.ImportedByPackages = listed.ImportedByPackages
+	dep.TransitiveRisk = listed.TransitiveRisk
+	dep.Vulnerabilities = listed.Vulnerabilities
+	dep.IsAcknowledged = s.acknowledgedDependencies[dep.Path]
+
+	dep.DaysSinceLastRelease = int(s.now().Sub(dep.LastReleaseTime).Hours() / 24)
+	if !dep.LatestReleaseTime.IsZero() {
+		days := int(s.now().Sub(dep.LatestReleaseTime).Hours() / 24)
+		dep.DaysSinceLatestRelease = &days
+	}
+	dep.Unresponsive = s.isUnresponsive(dep)
+	dep.IsActive = !dep.Unresponsive && !dep.StaleFork && !s.isDependencyStale(dep.requirementPath(), dep.DaysSinceLastRelease) && !s.isReleaseStale(dep)
+	dep.Score = nil
+	s.scoreDependency(dep)
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanReusesDependencies(t *testing.T) {
+	var mutex sync.Mutex
+	var requested []string
+	proxy := newFakeProxy(t, map[string]int{"v1.0.0": 30})
+	defer proxy.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requested = append(requested, r.URL.Path)
+		mutex.Unlock()
+		proxy.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	scanner.SetReusable([]Dependency{
+		{
+			Path:                 "github.com/example/reused",
+			Version:              "v1.0.0",
+			LastReleaseTime:      time.Now().AddDate(0, 0, -400),
+			DaysSinceLastRelease: 10,
+			IsActive:             true,
+			Licenses:             []string{"MIT"},
+		},
+		{Path: "github.com/example/failed", Version: "v1.0.0", LastReleaseTime: time.Now(), Error: &ScanError{Message: "timeout"}},
+		// Other versions are looked up again
+		{Path: "github.com/example/mod", Version: "v0.9.0", LastReleaseTime: time.Now(), IsActive: true},
+	})
+
+	location := &Location{File: "go.mod", Line: 5}
+	err := scanner.scanParallel(context.Background(), []Dependency{
+		{Path: "github.com/example/reused", Version: "v1.0.0", IsActive: true, Location: location},
+		{Path: "github.com/example/failed", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+	})
+	require.NoError(t, err)
+
+	for _, path := range requested {
+		assert.False(t, strings.HasPrefix(path, "/github.com/example/reused/"), "reused dependency looked up: %s", path)
+	}
+	assert.Contains(t, requested, "/github.com/example/failed/@v/v1.0.0.info")
+	assert.Contains(t, requested, "/github.com/example/mod/@v/v1.0.0.info")
+
+	deps := scanner.GetResults().Dependencies
+	require.Len(t, deps, 3)
+	reused := deps[0]
+	assert.Equal(t, []string{"MIT"}, reused.Licenses)
+	assert.Equal(t, location, reused.Location)
+	// Ages and stale checks follow the current time
+	assert.Equal(t, 400, reused.DaysSinceLastRelease)
+	assert.False(t, reused.IsActive)
+	assert.Equal(t, StatusStale, reused.Status)
+	assert.NotNil(t, reused.Score)
+
+	assert.Equal(t, 30, deps[2].DaysSinceLastRelease)
+	assert.True(t, deps[2].IsActive)
+}
+
+func TestReuseKeepsStaleForks(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.SetReusable([]Dependency{{
+		Path:            "github.com/fork/mod",
+		Version:         "v1.0.0",
+		LastReleaseTime: time.Now().AddDate(0, 0, -30),
+		StaleFork:       true,
+	}})
+
+	dep := &Dependency{Path: "github.com/fork/mod", Version: "v1.0.0", IsActive: true}
+	lookups, reused := scanner.reuseDependencies([]*Dependency{dep})
+	assert.Empty(t, lookups)
+	require.Len(t, reused, 1)
+	assert.True(t, dep.StaleFork)
+	assert.False(t, dep.IsActive)
+	assert.Equal(t, StatusStale, dep.maintenanceStatus())
+}
+
+func TestReuseSkipsQuickScans(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.SetQuick(true)
+	scanner.SetReusable([]Dependency{{Path: "github.com/example/mod", Version: "v1.0.0", LastReleaseTime: time.Now()}})
+
+	queue := []*Dependency{{Path: "github.com/example/mod", Version: "v1.0.0"}}
+	lookups, reused := scanner.reuseDependencies(queue)
+	assert.Equal(t, queue, lookups)
+	assert.Empty(t, reused)
+}
+
+func TestLookups(t *testing.T) {
+	scanner := NewScanner(".")
+	assert.Empty(t, scanner.Lookups())
+
+	scanner.SetCheckLicenses(true)
+	scanner.SetCheckRepositories(true, forge.Config{})
+	scanner.SetCheckResponsiveness(true)
+	assert.Equal(t, "licenses,repositories,responsiveness", scanner.Lookups())
+}
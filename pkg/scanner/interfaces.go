@@ -1,31 +1,134 @@
 package scanner
 
 import (
+	"context"
 	"io/fs"
+	"net/http"
+	"os"
 	"time"
+
+	"github.com/steffakasid/govital/pkg/tool"
 )
 
-// CommandExecutor executes shell commands
+// CommandExecutor runs the go and git commands of a scan. Consumers and
+// tests inject their own with SetCommandExecutor to scan without the
+// binaries.
 type CommandExecutor interface {
-	Execute(name string, args ...string) ([]byte, error)
-	ExecuteInDir(dir, name string, args ...string) ([]byte, error)
+	// Execute runs the command in dir, the current directory if empty, with
+	// env added to the environment and returns its standard output. Like
+	// exec.Cmd.Output a failing command returns an *exec.ExitError holding
+	// the standard error.
+	Execute(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error)
 }
 
-// FileReader reads files from the filesystem
+// FileReader reads the go.mod, go.sum and go.work files of the project and
+// the module cache, and creates the temporary repositories of git lookups
 type FileReader interface {
 	ReadFile(path string) ([]byte, error)
+	ReadDir(path string) ([]fs.DirEntry, error)
 	Stat(path string) (fs.FileInfo, error)
 	MkdirTemp(dir, pattern string) (string, error)
 	RemoveAll(path string) error
 }
 
-// GitClient interacts with git repositories
+// GitClient reads from the repositories of modules fetched directly
 type GitClient interface {
-	GetCommitTime(repoURL, commitHash string) (time.Time, error)
+	// GetCommitTime returns the commit time of the tag of the repository
+	GetCommitTime(ctx context.Context, repoURL, tag string) (time.Time, error)
 }
 
 // DefaultCommandExecutor is the default implementation using exec.Command
 type DefaultCommandExecutor struct{}
 
+// Execute runs the command found by the tool package
+func (DefaultCommandExecutor) Execute(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	cmd := tool.CommandContext(ctx, dir, name, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd.Output()
+}
+
 // DefaultFileReader is the default implementation using os functions
 type DefaultFileReader struct{}
+
+// ReadFile calls os.ReadFile
+func (DefaultFileReader) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// ReadDir calls os.ReadDir
+func (DefaultFileReader) ReadDir(path string) ([]fs.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+// Stat calls os.Stat
+func (DefaultFileReader) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(path)
+}
+
+// MkdirTemp calls os.MkdirTemp
+func (DefaultFileReader) MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+// RemoveAll calls os.RemoveAll
+func (DefaultFileReader) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// SetCommandExecutor sets the executor running the go and git commands,
+// DefaultCommandExecutor if nil. Unless a GitClient is set, git lookups run
+// through it as well.
+func (s *Scanner) SetCommandExecutor(executor CommandExecutor) {
+	if executor == nil {
+		executor = DefaultCommandExecutor{}
+	}
+	s.executor = executor
+}
+
+// SetFileReader sets the reader of the project files and the module cache,
+// DefaultFileReader if nil
+func (s *Scanner) SetFileReader(files FileReader) {
+	if files == nil {
+		files = DefaultFileReader{}
+	}
+	s.files = files
+}
+
+// SetGitClient sets the client reading the tags of modules fetched
+// directly from their repositories. Without one git runs through the
+// CommandExecutor.
+func (s *Scanner) SetGitClient(git GitClient) {
+	s.git = git
+}
+
+// SetHTTPClient sends all requests of the scan, to proxies, forges and
+// vulnerability databases, through client. Rate limits, retries, the
+// circuit breaker, .netrc logins and the request timeout still apply, the
+// client only replaces the connection to the servers, nil restores
+// http.DefaultTransport.
+func (s *Scanner) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		s.timeout.SetNext(nil)
+		return
+	}
+	s.timeout.SetNext(clientTransport{client: client})
+}
+
+// gitClient returns the GitClient of the scan
+func (s *Scanner) gitClient() GitClient {
+	if s.git != nil {
+		return s.git
+	}
+	return &commandGitClient{executor: s.executor, files: s.files}
+}
+
+// clientTransport sends the requests of the transport chain with a client
+type clientTransport struct {
+	client *http.Client
+}
+
+func (t clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.client.Do(req)
+}
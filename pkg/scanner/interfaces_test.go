@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitClient answers tags from a map
+type fakeGitClient map[string]time.Time
+
+func (c fakeGitClient) GetCommitTime(_ context.Context, repoURL, tag string) (time.Time, error) {
+	commitTime, ok := c[repoURL+"@"+tag]
+	if !ok {
+		return time.Time{}, fmt.Errorf("tag %s not found in %s", tag, repoURL)
+	}
+	return commitTime, nil
+}
+
+// fakeFiles serves the files of a MapFS at absolute paths
+type fakeFiles fstest.MapFS
+
+func (f fakeFiles) ReadFile(path string) ([]byte, error) {
+	return fs.ReadFile(fstest.MapFS(f), strings.TrimPrefix(path, "/"))
+}
+
+func (f fakeFiles) ReadDir(path string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(fstest.MapFS(f), strings.TrimPrefix(path, "/"))
+}
+
+func (f fakeFiles) Stat(path string) (fs.FileInfo, error) {
+	return fs.Stat(fstest.MapFS(f), strings.TrimPrefix(path, "/"))
+}
+
+func (f fakeFiles) MkdirTemp(string, string) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (f fakeFiles) RemoveAll(string) error {
+	return nil
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestScanWithInjectedDependencies(t *testing.T) {
+	t.Setenv("GOPROXY", "https://proxy.example.com")
+	released := time.Now().AddDate(0, 0, -10).UTC()
+
+	var mutex sync.Mutex
+	var commands []string
+	executor := &MockCommandExecutor{ExecuteFunc: func(_ context.Context, dir string, _ []string, name string, args ...string) ([]byte, error) {
+		command := name + " " + strings.Join(args, " ")
+		mutex.Lock()
+		commands = append(commands, command)
+		mutex.Unlock()
+		switch command {
+		case "go env -json GOPRIVATE GONOPROXY GONOSUMDB":
+			return []byte(`{"GOPRIVATE":"git.corp.example.com"}`), nil
+		case "go list -json -m all":
+			assert.Equal(t, "/project", dir)
+			return []byte(`{"Path":"example.com/app","Main":true}
+{"Path":"github.com/example/mod","Version":"v1.0.0"}
+{"Path":"git.corp.example.com/team/lib","Version":"v1.2.0"}`), nil
+		case "git rev-parse HEAD":
+			return []byte("0123abcd\n"), nil
+		case "go list -m -json git.corp.example.com/team/lib@v1.2.0":
+			return []byte(`{"Path":"git.corp.example.com/team/lib","Version":"v1.2.0","GoMod":"/cache/lib.mod"}`), nil
+		case "go list -m -json -versions git.corp.example.com/team/lib":
+			return []byte(`{"Path":"git.corp.example.com/team/lib","Versions":["v1.2.0"]}`), nil
+		}
+		return nil, fmt.Errorf("unexpected command %s", command)
+	}}
+
+	var requested []string
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		requested = append(requested, req.URL.Host+req.URL.Path)
+		mutex.Unlock()
+		body, status := "", http.StatusNotFound
+		switch req.URL.Host + req.URL.Path {
+		case "proxy.example.com/github.com/example/mod/@v/v1.0.0.info":
+			body, status = fmt.Sprintf(`{"Version":"v1.0.0","Time":%q}`, released.Format(time.RFC3339)), http.StatusOK
+		case "proxy.example.com/github.com/example/mod/@v/list":
+			body, status = "v1.0.0\n", http.StatusOK
+		case "git.corp.example.com/team/lib":
+			body, status = `<meta name="go-import" content="git.corp.example.com/team/lib git https://git.corp.example.com/team/lib.git">`, http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: req}, nil
+	})}
+
+	scanner := NewScanner("/project")
+	scanner.SetCommandExecutor(executor)
+	scanner.SetFileReader(fakeFiles{
+		"project/go.mod": {Data: []byte("module example.com/app\n\nrequire github.com/example/mod v1.0.0\n")},
+		"cache/lib.mod":  {Data: []byte("module git.corp.example.com/team/lib\n")},
+	})
+	scanner.SetGitClient(fakeGitClient{"https://git.corp.example.com/team/lib.git@v1.2.0": released})
+	scanner.SetHTTPClient(client)
+	require.NoError(t, scanner.Scan(context.Background()))
+
+	assert.Contains(t, requested, "proxy.example.com/github.com/example/mod/@v/v1.0.0.info")
+	assert.Contains(t, requested, "git.corp.example.com/team/lib")
+	// Tags are read with the GitClient instead of git
+	assert.Contains(t, commands, "git rev-parse HEAD")
+	for _, command := range commands {
+		assert.False(t, strings.HasPrefix(command, "git ") && command != "git rev-parse HEAD", command)
+	}
+
+	result := scanner.GetResults()
+	require.Len(t, result.Dependencies, 2)
+	for _, dep := range result.Dependencies {
+		assert.Nil(t, dep.Error, dep.Path)
+		assert.Equal(t, 10, dep.DaysSinceLastRelease, dep.Path)
+		assert.True(t, dep.IsActive, dep.Path)
+	}
+	require.NotNil(t, result.Fingerprint)
+	assert.Equal(t, "example.com/app", result.Fingerprint.Module)
+	assert.Equal(t, "0123abcd", result.Fingerprint.Revision)
+}
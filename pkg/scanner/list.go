@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ParseModuleList returns the modules of an explicit module list. Every
+// line holds a module path and version, either as path@version or
+// separated by whitespace like in the output of go list -m all. Empty lines
+// and lines starting with # are skipped, as are lines with a module path
+// only, like the main module in go list -m all.
+func ParseModuleList(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path, version, found := strings.Cut(line, "@")
+		if !found {
+			fields := strings.Fields(line)
+			if len(fields) == 1 {
+				continue
+			}
+			path, version = fields[0], fields[1]
+		}
+		// Drop replacements of go list -m all, e.g. "a v1.0.0 => b v1.1.0"
+		version, _, _ = strings.Cut(strings.TrimSpace(version), " ")
+		if err := module.Check(path, version); err != nil {
+			return nil, fmt.Errorf("invalid module on line %d: %w", i+1, err)
+		}
+
+		if key := path + "@" + version; !seen[key] {
+			seen[key] = true
+			deps = append(deps, Dependency{Path: path, Version: version, IsActive: true})
+		}
+	}
+	return deps, nil
+}
+
+// ParseGoSum returns the modules a go.sum file holds the content hash of,
+// which are the module versions used in the build. Versions only listed
+// with their go.mod hash were just needed for the version selection. go.sum
+// files may keep the hashes of versions which are no longer used, so only
+// the highest version of every module is returned.
+func ParseGoSum(data []byte) ([]Dependency, error) {
+	versions := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed go.sum line %d: %q", i+1, line)
+		}
+		path, version := fields[0], fields[1]
+		if strings.HasSuffix(version, "/go.mod") {
+			continue
+		}
+		if err := module.Check(path, version); err != nil {
+			return nil, fmt.Errorf("invalid module on go.sum line %d: %w", i+1, err)
+		}
+		if current, ok := versions[path]; !ok || semver.Compare(version, current) > 0 {
+			versions[path] = version
+		}
+	}
+
+	deps := make([]Dependency, 0, len(versions))
+	for path, version := range versions {
+		deps = append(deps, Dependency{Path: path, Version: version, IsActive: true})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Path < deps[j].Path
+	})
+	return deps, nil
+}
+
+// ScanModuleList scans an explicit list of modules which is not backed by
+// a Go project, e.g. parsed with ParseModuleList or ParseGoSum. The source
+// names the list in the result. Without a go.mod direct and indirect
+// dependencies can't be told apart, all modules are scanned as direct ones.
+func (s *Scanner) ScanModuleList(ctx context.Context, source string, deps []Dependency) error {
+	logger(ctx).Info("Scanning listed modules", slog.Int("modules", len(deps)), slog.String("source", source))
+	start := time.Now()
+	s.result.ProjectPath = source
+
+	if err := s.ScanDependencies(ctx, deps); err != nil {
+		logger(ctx).Error("Scan aborted", slog.Any(logKeyError, err))
+		return err
+	}
+	s.logScanned(ctx, start)
+	return nil
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleList(t *testing.T) {
+	deps, err := ParseModuleList([]byte(`# extracted from the image
+github.com/example/app
+github.com/example/mod@v1.0.0
+golang.org/x/mod v0.17.0
+
+github.com/example/old v1.0.0 => github.com/example/new v1.1.0
+github.com/example/mod@v1.0.0
+`))
+
+	require.NoError(t, err)
+	require.Len(t, deps, 3)
+	assert.Equal(t, Dependency{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true}, deps[0])
+	assert.Equal(t, "golang.org/x/mod", deps[1].Path)
+	assert.Equal(t, "v0.17.0", deps[1].Version)
+	assert.Equal(t, "github.com/example/old", deps[2].Path)
+
+	_, err = ParseModuleList([]byte("github.com/example/mod@latest\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestParseGoSum(t *testing.T) {
+	deps, err := ParseGoSum([]byte(`github.com/example/mod v1.0.0 h1:aaa=
+github.com/example/mod v1.0.0/go.mod h1:bbb=
+github.com/example/mod v1.2.0 h1:ccc=
+github.com/example/mod v1.2.0/go.mod h1:ddd=
+github.com/example/pruned v0.1.0/go.mod h1:eee=
+golang.org/x/mod v0.17.0 h1:fff=
+`))
+
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, "github.com/example/mod", deps[0].Path)
+	assert.Equal(t, "v1.2.0", deps[0].Version)
+	assert.Equal(t, "golang.org/x/mod", deps[1].Path)
+
+	_, err = ParseGoSum([]byte("github.com/example/mod v1.0.0\n"))
+	assert.ErrorContains(t, err, "malformed go.sum line 1")
+}
+
+func TestScanModuleList(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanModuleList(context.Background(), "modules.txt",
+		[]Dependency{{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true}})
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	assert.Equal(t, "modules.txt", result.ProjectPath)
+	require.Len(t, result.Dependencies, 1)
+	assert.True(t, result.Dependencies[0].IsActive)
+	assert.Equal(t, 1, result.Summary.Total)
+}
@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/steffakasid/eslog"
+	"golang.org/x/mod/modfile"
+)
+
+// Location points to the line declaring a dependency, so reports can
+// annotate it. Lines and columns are 1-based, columns count runes.
+type Location struct {
+	// File is slash separated and relative to the project path
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// EndLine and EndColumn point behind the last character of the
+	// declaration
+	EndLine   int `json:"end_line"`
+	EndColumn int `json:"end_column"`
+}
+
+// locationIndex maps module paths to their require directive in go.mod and
+// module versions to their go.sum line
+type locationIndex struct {
+	requires map[string]Location
+	sums     map[string]Location
+}
+
+// newLocationIndex indexes go.mod and go.sum of the module in dir. Files
+// which can't be read or parsed are skipped, locations are optional.
+func newLocationIndex(files FileReader, projectDir, dir string) *locationIndex {
+	index := &locationIndex{requires: make(map[string]Location), sums: make(map[string]Location)}
+	relDir, err := filepath.Rel(projectDir, dir)
+	if err != nil {
+		relDir = dir
+	}
+
+	goModFile := filepath.ToSlash(filepath.Join(relDir, "go.mod"))
+	if data, err := files.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		if err := index.addGoMod(goModFile, data); err != nil {
+			eslog.Logger.Debug("No go.mod locations", slog.String("dir", dir), slog.Any(logKeyError, err))
+		}
+	}
+	if data, err := files.ReadFile(filepath.Join(dir, "go.sum")); err == nil {
+		index.addGoSum(filepath.ToSlash(filepath.Join(relDir, "go.sum")), data)
+	}
+	return index
+}
+
+func (i *locationIndex) addGoMod(file string, data []byte) error {
+	parsed, err := modfile.ParseLax(file, data, nil)
+	if err != nil {
+		return err
+	}
+	for _, req := range parsed.Require {
+		if req.Syntax == nil {
+			continue
+		}
+		i.requires[req.Mod.Path] = Location{
+			File:      file,
+			Line:      req.Syntax.Start.Line,
+			Column:    req.Syntax.Start.LineRune,
+			EndLine:   req.Syntax.End.Line,
+			EndColumn: req.Syntax.End.LineRune,
+		}
+	}
+	return nil
+}
+
+// addGoSum indexes the hash lines of module contents, the hashes of go.mod
+// files are skipped
+func (i *locationIndex) addGoSum(file string, data []byte) {
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; lines.Scan(); number++ {
+		line := lines.Text()
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		key := fields[0] + "@" + fields[1]
+		if _, exists := i.sums[key]; !exists {
+			i.sums[key] = Location{File: file, Line: number, Column: 1, EndLine: number, EndColumn: len([]rune(line)) + 1}
+		}
+	}
+}
+
+// locate returns the require directive of the dependency, falling back to
+// its go.sum line for modules go.mod doesn't list
+func (i *locationIndex) locate(dep Dependency) *Location {
+	if location, ok := i.requires[dep.Path]; ok {
+		return &location
+	}
+	if location, ok := i.sums[dep.Path+"@"+dep.Version]; ok {
+		return &location
+	}
+	return nil
+}
+
+// setLocations sets the location of all dependencies listed from dir
+func (s *Scanner) setLocations(deps []Dependency, dir string) {
+	index := newLocationIndex(s.files, s.projectPath, dir)
+	for i := range deps {
+		deps[i].Location = index.locate(deps[i])
+	}
+}
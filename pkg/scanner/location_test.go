@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const locationGoMod = `module example.com/app
+
+go 1.22
+
+require github.com/example/single v1.0.0
+
+require (
+	github.com/example/mod v1.2.0
+	github.com/example/indirect v0.1.0 // indirect
+)
+`
+
+const locationGoSum = `github.com/example/mod v1.2.0 h1:abc=
+github.com/example/mod v1.2.0/go.mod h1:def=
+github.com/example/transitive v0.3.0/go.mod h1:ghi=
+github.com/example/transitive v0.3.0 h1:jkl=
+`
+
+func TestLocationIndex(t *testing.T) {
+	projectDir := t.TempDir()
+	dir := filepath.Join(projectDir, "service")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(locationGoMod), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte(locationGoSum), 0o600))
+
+	index := newLocationIndex(DefaultFileReader{}, projectDir, dir)
+
+	assert.Equal(t, &Location{File: "service/go.mod", Line: 5, Column: 1, EndLine: 5, EndColumn: 41},
+		index.locate(Dependency{Path: "github.com/example/single", Version: "v1.0.0"}))
+	assert.Equal(t, &Location{File: "service/go.mod", Line: 8, Column: 2, EndLine: 8, EndColumn: 31},
+		index.locate(Dependency{Path: "github.com/example/mod", Version: "v1.2.0"}))
+	assert.Equal(t, 9, index.locate(Dependency{Path: "github.com/example/indirect"}).Line)
+	assert.Equal(t, &Location{File: "service/go.sum", Line: 4, Column: 1, EndLine: 4, EndColumn: 45},
+		index.locate(Dependency{Path: "github.com/example/transitive", Version: "v0.3.0"}), "falls back to go.sum")
+	assert.Nil(t, index.locate(Dependency{Path: "github.com/example/unknown", Version: "v1.0.0"}))
+}
+
+func TestLocationIndexWithoutFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	index := newLocationIndex(DefaultFileReader{}, dir, dir)
+
+	assert.Nil(t, index.locate(Dependency{Path: "github.com/example/mod", Version: "v1.0.0"}))
+}
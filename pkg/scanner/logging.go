@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/steffakasid/eslog"
+)
+
+// Attribute keys of the structured scan logs
+const (
+	logKeyModule   = "module"
+	logKeyVersion  = "version"
+	logKeyTraceID  = "trace_id"
+	logKeyStage    = "stage"
+	logKeyDuration = "duration"
+	logKeyError    = "error"
+)
+
+type loggerKey struct{}
+
+// dependencyLogger returns the logger of the lookups of a dependency. Its
+// records carry the module, the version and a trace ID, so the records of
+// one dependency can be told apart from the concurrent lookups of others.
+func dependencyLogger(dep *Dependency) *slog.Logger {
+	return eslog.Logger.With(
+		slog.String(logKeyModule, dep.Path),
+		slog.String(logKeyVersion, dep.Version),
+		slog.String(logKeyTraceID, newTraceID()),
+	)
+}
+
+// withLogger returns a context whose lookups log to logger
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// logger returns the logger of the dependency looked up with ctx, or the
+// default logger outside of dependency lookups
+func logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return eslog.Logger.Logger
+}
+
+// logFailure logs a failed lookup of a stage at debug level with further
+// attributes in args
+func logFailure(ctx context.Context, stage ErrorStage, msg string, err error, args ...any) {
+	args = append([]any{slog.String(logKeyStage, string(stage)), slog.Any(logKeyError, err)}, args...)
+	logger(ctx).Debug(msg, args...)
+}
+
+// logStage logs the duration of a lookup stage at debug level, e.g.
+// defer logStage(ctx, StageForge, time.Now())
+func logStage(ctx context.Context, stage ErrorStage, start time.Time) {
+	logger(ctx).Debug("Stage finished", slog.String(logKeyStage, string(stage)), slog.Duration(logKeyDuration, time.Since(start)))
+}
+
+// logScanned logs the number of scanned dependencies, the worker setup and
+// the duration of the scan started at start with further attributes in args
+func (s *Scanner) logScanned(ctx context.Context, start time.Time, args ...any) {
+	args = append([]any{
+		slog.Int("dependencies", s.result.Summary.Total),
+		slog.String("workers", s.concurrencyDescription()),
+		slog.Duration(logKeyDuration, time.Since(start)),
+	}, args...)
+	logger(ctx).Info("Dependencies found", args...)
+}
+
+// newTraceID returns a random ID correlating the log records of a
+// dependency
+func newTraceID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := eslog.Logger.Logger
+	eslog.Logger.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { eslog.Logger.Logger = previous })
+	return &buf
+}
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDependencyLogger(t *testing.T) {
+	buf := captureLogs(t)
+
+	lib := withLogger(context.Background(), dependencyLogger(&Dependency{Path: "github.com/org/lib", Version: "v1.2.0"}))
+	other := withLogger(context.Background(), dependencyLogger(&Dependency{Path: "github.com/org/other", Version: "v0.1.0"}))
+	logStage(lib, StageForge, time.Now().Add(-2*time.Second))
+	logFailure(lib, StageProxy, "Failed to fetch from proxy", errors.New("status 404"), "endpoint", "@latest")
+	logFailure(other, StageLicense, "Failed to look up licenses", errors.New("timeout"))
+	logger(context.Background()).Debug("Outside of lookups")
+
+	records := decodeLogs(t, buf)
+	require.Len(t, records, 4)
+
+	assert.Equal(t, "Stage finished", records[0]["msg"])
+	assert.Equal(t, "github.com/org/lib", records[0][logKeyModule])
+	assert.Equal(t, "v1.2.0", records[0][logKeyVersion])
+	assert.Equal(t, "forge", records[0][logKeyStage])
+	assert.GreaterOrEqual(t, records[0][logKeyDuration], float64(2*time.Second))
+	assert.Len(t, records[0][logKeyTraceID], 16)
+
+	assert.Equal(t, "proxy", records[1][logKeyStage])
+	assert.Equal(t, "status 404", records[1][logKeyError])
+	assert.Equal(t, "@latest", records[1]["endpoint"])
+	assert.Equal(t, records[0][logKeyTraceID], records[1][logKeyTraceID])
+
+	assert.Equal(t, "github.com/org/other", records[2][logKeyModule])
+	assert.NotEqual(t, records[0][logKeyTraceID], records[2][logKeyTraceID])
+
+	assert.NotContains(t, records[3], logKeyModule)
+}
+
+func TestScanLogsAreStructured(t *testing.T) {
+	buf := captureLogs(t)
+
+	scanner := NewScanner(t.TempDir())
+	scanner.SetCommandExecutor(&MockCommandExecutor{ExecuteFunc: func(context.Context, string, []string, string, ...string) ([]byte, error) {
+		return []byte("go: missing go.sum entry"), errors.New("exit status 1")
+	}})
+	_, err := scanner.listDependencies(context.Background(), "/project", false)
+	require.Error(t, err)
+
+	collector := newWarningCollector()
+	collector.add("Failed to get version info from proxy: not found", "github.com/example/b")
+	collector.add("Failed to get version info from proxy: not found", "github.com/example/a")
+	collector.flush()
+
+	records := decodeLogs(t, buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Failed to list dependencies (go list -json -m all)", records[0]["msg"])
+	assert.Equal(t, "/project", records[0]["dir"])
+	assert.Equal(t, "exit status 1", records[0][logKeyError])
+	assert.Equal(t, "go: missing go.sum entry", records[0]["output"])
+
+	assert.Equal(t, "Failed to get version info from proxy: not found", records[1]["msg"])
+	assert.Equal(t, float64(2), records[1]["count"])
+	assert.Equal(t, "github.com/example/a, github.com/example/b", records[1]["modules"])
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// maxMajorProbes bounds the following major versions probed per module,
+// each costs a proxy request
+const maxMajorProbes = 5
+
+// MajorUpgrade is a newer major version of a module, published under a
+// different module path like github.com/foo/bar/v3
+type MajorUpgrade struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// String returns the module path and version like in go get
+func (m *MajorUpgrade) String() string {
+	return m.Path + "@" + m.Version
+}
+
+// checkNewerMajor sets NewerMajorAvailable to the newest release of the
+// following major versions. go list -u doesn't report them, since every
+// major version from v2 on has its own module path. The module paths are
+// probed in order up to the first one the proxy doesn't know, without
+// falling back to direct lookups. Failures are only logged, there is just
+// no newer major version then.
+func (s *Scanner) checkNewerMajor(ctx context.Context, dep *Dependency) {
+	prefix, pathMajor, ok := module.SplitPathVersion(dep.Path)
+	if !ok {
+		return
+	}
+
+	current := currentMajor(pathMajor, dep.Version)
+	for next := current + 1; next <= current+maxMajorProbes; next++ {
+		candidate := majorPath(prefix, pathMajor, next)
+		versions, err := s.probeVersionList(ctx, candidate)
+		if err != nil {
+			logger(ctx).Debug("No newer major version", "path", candidate, "error", err)
+			return
+		}
+		latest := latestFromVersionList(versions)
+		if latest == "" || semver.Prerelease(latest) != "" || semver.Major(latest) != fmt.Sprintf("v%d", next) {
+			return
+		}
+		dep.NewerMajorAvailable = &MajorUpgrade{Path: candidate, Version: latest}
+	}
+}
+
+// currentMajor returns the major version of the module path suffix, or of
+// the version for paths without suffix, e.g. v0, v1 or v3+incompatible
+func currentMajor(pathMajor, version string) int {
+	if pathMajor != "" {
+		major, err := strconv.Atoi(pathMajor[2:])
+		if err == nil {
+			return major
+		}
+	}
+	if major, err := strconv.Atoi(strings.TrimPrefix(semver.Major(version), "v")); err == nil && major > 1 {
+		return major
+	}
+	return 1
+}
+
+// majorPath returns the module path of the major version. gopkg.in paths
+// use a .vN suffix, all others /vN.
+func majorPath(prefix, pathMajor string, major int) string {
+	if strings.HasPrefix(pathMajor, ".") || strings.HasPrefix(prefix, "gopkg.in/") {
+		return fmt.Sprintf("%s.v%d", prefix, major)
+	}
+	return fmt.Sprintf("%s/v%d", prefix, major)
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrentMajor(t *testing.T) {
+	assert.Equal(t, 1, currentMajor("", "v0.3.0"))
+	assert.Equal(t, 1, currentMajor("", "v1.2.0"))
+	assert.Equal(t, 3, currentMajor("", "v3.1.0+incompatible"))
+	assert.Equal(t, 2, currentMajor("/v2", "v2.0.1"))
+	assert.Equal(t, 3, currentMajor(".v3", "v3.0.1"))
+}
+
+func TestMajorPath(t *testing.T) {
+	assert.Equal(t, "github.com/foo/bar/v3", majorPath("github.com/foo/bar", "/v2", 3))
+	assert.Equal(t, "github.com/foo/bar/v2", majorPath("github.com/foo/bar", "", 2))
+	assert.Equal(t, "gopkg.in/yaml.v3", majorPath("gopkg.in/yaml", ".v2", 3))
+}
+
+func TestScanDependenciesNewerMajor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/mod/v2/@v/list":
+			fmt.Fprintln(w, "v2.0.0")
+			fmt.Fprintln(w, "v2.1.0")
+		case "/example.com/mod/v3/@v/list":
+			fmt.Fprintln(w, "v3.0.0")
+			fmt.Fprintln(w, "v3.2.0")
+		case "/example.com/mod/v4/@v/list":
+			fmt.Fprintln(w, "v4.0.0-rc.1")
+		case "/example.com/other/@v/list":
+			fmt.Fprintln(w, "v1.0.0")
+		case "/example.com/mod/v2/@v/v2.1.0.info", "/example.com/other/@v/v1.0.0.info":
+			fmt.Fprint(w, `{"Time":"2024-01-01T00:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "example.com/mod/v2", Version: "v2.1.0"},
+		{Path: "example.com/other", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	require.Len(t, result.Dependencies, 2)
+	assert.Equal(t, &MajorUpgrade{Path: "example.com/mod/v3", Version: "v3.2.0"}, result.Dependencies[0].NewerMajorAvailable,
+		"pre-releases of the next major version are ignored")
+	assert.Empty(t, result.Dependencies[0].Update, "go list -u doesn't report major versions")
+	assert.Nil(t, result.Dependencies[1].NewerMajorAvailable)
+	assert.Equal(t, 1, result.Summary.NewerMajor)
+}
+
+func TestNewerMajorProbeSkipsDirect(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL+",direct")
+
+	var commands []string
+	scanner := NewScanner(".")
+	scanner.SetPrivatePatterns("", "", "")
+	scanner.SetCommandExecutor(&MockCommandExecutor{ExecuteFunc: func(_ context.Context, _ string, _ []string, name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil, fmt.Errorf("unexpected command")
+	}})
+
+	dep := &Dependency{Path: "example.com/mod", Version: "v1.0.0"}
+	scanner.checkNewerMajor(context.Background(), dep)
+	assert.Nil(t, dep.NewerMajorAvailable)
+	assert.Empty(t, commands, "the 404 of the proxy must not start go list")
+
+	// Regular lookups still fall back to direct like the go command
+	_, err := scanner.getVersionListFromProxy(context.Background(), "example.com/mod/v2")
+	require.Error(t, err)
+	assert.Len(t, commands, 1)
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/tool"
+	"golang.org/x/mod/module"
+)
+
+// SetOffline enables offline scans for air-gapped environments. They don't
+// access the network at all: the go command runs with GOPROXY=off and the
+// release times are read from the .info files of the module download cache
+// ($GOMODCACHE/cache/download). Update checks only see the versions in the
+// cache, health signals, license, vulnerability and repository lookups are
+// skipped. Results are marked as offline.
+func (s *Scanner) SetOffline(offline bool) {
+	s.offline = offline
+	s.result.Offline = offline
+}
+
+// runGo runs the go command with the given arguments in dir and returns
+// its output. Workspace members run with GOWORK=off, offline scans must
+// neither download modules nor toolchains.
+func (s *Scanner) runGo(ctx context.Context, dir string, workspaceMember bool, args ...string) ([]byte, error) {
+	var env []string
+	if workspaceMember {
+		env = append(env, "GOWORK=off")
+	}
+	if s.offline {
+		env = append(env, "GOPROXY=off", "GOTOOLCHAIN=local")
+	}
+	return s.executor.Execute(ctx, dir, env, tool.Go, args...)
+}
+
+// downloadCache returns the module download cache, reading GOMODCACHE on
+// first use
+func (s *Scanner) downloadCache(ctx context.Context) string {
+	s.modCacheOnce.Do(func() {
+		s.modCache = filepath.Join(s.loadModCache(ctx), "cache", "download")
+	})
+	return s.modCache
+}
+
+// loadModCache reads GOMODCACHE with go env, which includes the value set
+// with go env -w. Without the go command it defaults to GOPATH/pkg/mod like
+// the go command does.
+func (s *Scanner) loadModCache(ctx context.Context) string {
+	output, err := s.executor.Execute(ctx, "", nil, tool.Go, "env", "GOMODCACHE")
+	if dir := strings.TrimSpace(string(output)); err == nil && dir != "" {
+		return dir
+	}
+	logger(ctx).Debug("Failed to read GOMODCACHE with go env, using the environment", slog.Any(logKeyError, err))
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	return filepath.Join(build.Default.GOPATH, "pkg", "mod")
+}
+
+// checkCachedReleaseTime is the offline variant of checkMaintenanceStatus.
+// The release time comes from the pseudo-version or the cached .info file,
+// the latest version from the versions in the cache.
+func (s *Scanner) checkCachedReleaseTime(ctx context.Context, dep *Dependency) {
+	versionDir, err := cachedVersionDir(s.downloadCache(ctx), dep.Path)
+	if err != nil {
+		dep.Error = dep.addError(StageProxy, err)
+		return
+	}
+
+	if latest := latestFromVersionList(cachedVersions(s.files, versionDir)); latest != "" {
+		dep.Latest = latest
+		if isNewerVersion(dep.Version, latest) {
+			dep.Update = latest
+		}
+	}
+
+	releaseTime, err := module.PseudoVersionTime(dep.Version)
+	if err != nil {
+		releaseTime, err = cachedVersionTime(s.files, versionDir, dep.Version)
+	}
+	if err != nil {
+		logFailure(ctx, StageProxy, "Failed to get release time from the module cache", err)
+		message := "Version not in the module cache"
+		if !errors.Is(err, fs.ErrNotExist) {
+			message = "Failed to read version info from the module cache: " + warningReason(err)
+		}
+		s.warnings.add(message, dep.Path)
+		dep.Error = dep.addError(StageProxy, err)
+		return
+	}
+
+	dep.LastReleaseTime = releaseTime
+	dep.DaysSinceLastRelease = int(s.now().Sub(releaseTime).Hours() / 24)
+	dep.IsActive = !s.isDependencyStale(dep.requirementPath(), dep.DaysSinceLastRelease)
+}
+
+// cachedVersionDir returns the @v directory of the module in the download
+// cache, which holds the files the proxy protocol serves
+func cachedVersionDir(downloadCache, modulePath string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %s: %w", modulePath, err)
+	}
+	return filepath.Join(downloadCache, filepath.FromSlash(escapedPath), "@v"), nil
+}
+
+// cachedVersions returns the versions of the module the cache has .info
+// files of, plus the version list if the go command cached it
+func cachedVersions(files FileReader, versionDir string) []string {
+	var versions []string
+	if list, err := files.ReadFile(filepath.Join(versionDir, "list")); err == nil {
+		versions = strings.Fields(string(list))
+	}
+	entries, err := files.ReadDir(versionDir)
+	if err != nil {
+		return versions
+	}
+	for _, entry := range entries {
+		escaped, found := strings.CutSuffix(entry.Name(), ".info")
+		if !found {
+			continue
+		}
+		if version, err := module.UnescapeVersion(escaped); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// cachedVersionTime reads the release time of the version from its cached
+// .info file
+func cachedVersionTime(files FileReader, versionDir, version string) (time.Time, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid version %s: %w", version, err)
+	}
+	data, err := files.ReadFile(filepath.Join(versionDir, escapedVersion+".info"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	var info versionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode cached version info of %s: %w", version, err)
+	}
+	return info.Time, nil
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCachedVersion stores the .info file of a module version in the
+// download cache below modCache
+func writeCachedVersion(t *testing.T, modCache, versionDir, version string, released time.Time) {
+	t.Helper()
+	dir := filepath.Join(modCache, "cache", "download", filepath.FromSlash(versionDir), "@v")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	info := `{"Version":"` + version + `","Time":"` + released.Format(time.RFC3339) + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, version+".info"), []byte(info), 0o644))
+}
+
+func TestOfflineScan(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("offline scan requested %s", r.URL)
+	}))
+	defer proxy.Close()
+	t.Setenv("GOPROXY", proxy.URL)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	writeCachedVersion(t, modCache, "github.com/example/mod", "v1.0.0", now.AddDate(0, 0, -400))
+	writeCachedVersion(t, modCache, "github.com/example/mod", "v1.1.0", now.AddDate(0, 0, -30))
+	// Upper case letters are escaped in the cache
+	writeCachedVersion(t, modCache, "github.com/!example/upper", "v0.1.0", now.AddDate(0, 0, -10))
+
+	dir := t.TempDir()
+	goMod := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/example/mod v1.0.0
+	github.com/Example/upper v0.1.0
+	github.com/example/missing v1.0.0
+	github.com/example/pseudo v0.0.0-20240102030405-abcdef123456
+)
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644))
+
+	scanner := NewScanner(dir)
+	scanner.SetOffline(true)
+	scanner.SetCheckVulnerabilities(true)
+	scanner.SetClock(func() time.Time { return now })
+	require.NoError(t, scanner.Scan(context.Background()))
+
+	result := scanner.GetResults()
+	assert.True(t, result.Offline)
+	deps := make(map[string]Dependency)
+	for _, dep := range result.Dependencies {
+		deps[dep.Path] = dep
+	}
+	require.Len(t, deps, 4)
+
+	mod := deps["github.com/example/mod"]
+	assert.False(t, mod.IsActive)
+	assert.Equal(t, 400, mod.DaysSinceLastRelease)
+	assert.Equal(t, "v1.1.0", mod.Update, "updates are found among the cached versions")
+
+	assert.True(t, deps["github.com/Example/upper"].IsActive)
+	assert.Equal(t, 2024, deps["github.com/example/pseudo"].LastReleaseTime.Year())
+
+	missing := deps["github.com/example/missing"]
+	require.NotNil(t, missing.Error)
+	assert.Equal(t, ErrorNotFound, missing.Error.Category)
+}
+
+func TestCachedVersionsReadsThroughFileReader(t *testing.T) {
+	files := fakeFiles{
+		"cache/github.com/example/mod/@v/list":        {Data: []byte("v1.0.0\n")},
+		"cache/github.com/example/mod/@v/v1.1.0.info": {Data: []byte(`{"Version":"v1.1.0"}`)},
+		"cache/github.com/example/mod/@v/v1.1.0.mod":  {Data: []byte("module github.com/example/mod\n")},
+	}
+	assert.ElementsMatch(t, []string{"v1.0.0", "v1.1.0"}, cachedVersions(files, "/cache/github.com/example/mod/@v"))
+}
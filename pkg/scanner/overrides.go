@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/owners"
+)
+
+// Override adjusts the checks of a single dependency where the global
+// settings don't fit, e.g. a module which is intentionally pinned
+type Override struct {
+	// Path is the module path the override applies to
+	Path string `mapstructure:"path"`
+	// Ignore excludes the dependency from the scan and its results
+	Ignore bool `mapstructure:"ignore"`
+	// StaleThresholdDays replaces the global stale threshold and the one of
+	// the tier if not zero
+	StaleThresholdDays int `mapstructure:"stale_threshold_days"`
+	// Tier holds the dependency to the thresholds of the tier, see
+	// SetTierPolicies
+	Tier Tier `mapstructure:"tier"`
+	// Reason documents the override and is shown as note of the dependency
+	Reason string `mapstructure:"reason"`
+}
+
+// SetOverrides sets the per dependency overrides. A later override of the
+// same module replaces an earlier one.
+func (s *Scanner) SetOverrides(overrides []Override) error {
+	s.overrides = make(map[string]Override, len(overrides))
+	for i, override := range overrides {
+		if override.Path == "" {
+			return fmt.Errorf("dependency override %d has no path", i+1)
+		}
+		if override.StaleThresholdDays < 0 {
+			return fmt.Errorf("invalid stale_threshold_days %d for %s", override.StaleThresholdDays, override.Path)
+		}
+		if override.Tier != "" && !validTier(override.Tier) {
+			return fmt.Errorf("unknown tier %q for %s, use critical, normal or low", override.Tier, override.Path)
+		}
+		s.overrides[override.Path] = override
+	}
+	return nil
+}
+
+// SetIgnorePatterns excludes all dependencies matching one of the module
+// patterns from the scan and its results, e.g. github.com/mycorp/* or
+// golang.org/x/*. Patterns are module paths with path.Match wildcards, which
+// may end in /... like owner patterns. A pattern also matches the modules
+// below a path it matches, so github.com/mycorp/* matches
+// github.com/mycorp/lib/v2.
+func (s *Scanner) SetIgnorePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("empty ignore pattern")
+		}
+		if err := owners.ValidatePattern(pattern); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+	s.ignorePatterns = patterns
+	return nil
+}
+
+// isIgnored returns whether an override or an ignore pattern excludes the
+// module path
+func (s *Scanner) isIgnored(modulePath string) bool {
+	if s.overrides[modulePath].Ignore {
+		return true
+	}
+	for _, pattern := range s.ignorePatterns {
+		// Match the path and each of its parents, github.com/a/b/v2 is
+		// checked as github.com/a/b and github.com/a as well
+		for candidate := modulePath; ; {
+			if owners.Match(pattern, candidate) {
+				return true
+			}
+			i := strings.LastIndex(candidate, "/")
+			if i < 0 {
+				break
+			}
+			candidate = candidate[:i]
+		}
+	}
+	return false
+}
+
+// withoutIgnored returns the dependencies not ignored by an override or an
+// ignore pattern
+func (s *Scanner) withoutIgnored(deps []Dependency) []Dependency {
+	if len(s.overrides) == 0 && len(s.ignorePatterns) == 0 {
+		return deps
+	}
+	kept := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		if s.isIgnored(dep.Path) {
+			continue
+		}
+		kept = append(kept, dep)
+	}
+	if ignored := len(deps) - len(kept); ignored > 0 {
+		eslog.Logger.Debug("Ignoring dependencies", slog.Int("dependencies", ignored))
+	}
+	return kept
+}
+
+// isDependencyStale is like isStale, honoring the stale threshold override
+// and the tier of the dependency
+func (s *Scanner) isDependencyStale(path string, daysSinceRelease int) bool {
+	return daysSinceRelease > s.staleThresholdOf(path)
+}
+
+// isReleaseStale returns whether the release threshold is set and the
+// module has no tagged release within it
+func (s *Scanner) isReleaseStale(dep *Dependency) bool {
+	threshold := s.releaseThresholdOf(dep.requirementPath())
+	if threshold <= 0 {
+		return false
+	}
+	if dep.Unreleased {
+		return true
+	}
+	return dep.DaysSinceLatestRelease != nil && *dep.DaysSinceLatestRelease > threshold
+}
+
+// isUnresponsive returns whether the response time check is enabled and
+// the maintainers took longer to respond in median
+func (s *Scanner) isUnresponsive(dep *Dependency) bool {
+	if s.maxResponseDays <= 0 || dep.MedianResponseHours == nil {
+		return false
+	}
+	return *dep.MedianResponseHours > s.maxResponseDays*24
+}
+
+// applyOverrideReason notes the reason of the override of dep, unless the
+// scan already noted why the dependency was not checked, and its tier
+func (s *Scanner) applyOverrideReason(dep *Dependency) {
+	dep.Tier = s.overrides[dep.Path].Tier
+	if reason := s.overrides[dep.Path].Reason; reason != "" && dep.Note == "" {
+		dep.Note = reason
+	}
+}
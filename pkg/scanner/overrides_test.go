@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrides(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 400})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scan := func(overrides ...Override) []Dependency {
+		scanner := NewScanner(t.TempDir())
+		scanner.SetQuick(true)
+		require.NoError(t, scanner.SetOverrides(overrides))
+		require.NoError(t, scanner.ScanDependencies(context.Background(), []Dependency{
+			{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+			{Path: "github.com/example/local", Version: "v1.0.0", Replace: &Replacement{Path: "../local"}},
+		}))
+		return scanner.GetResults().Dependencies
+	}
+
+	deps := scan()
+	require.Len(t, deps, 2)
+	assert.False(t, deps[0].IsActive, "older than the global threshold")
+
+	deps = scan(
+		Override{Path: "github.com/example/mod", StaleThresholdDays: 730, Reason: "intentionally pinned"},
+		Override{Path: "github.com/example/local", Reason: "vendored fork"},
+	)
+	require.Len(t, deps, 2)
+	assert.True(t, deps[0].IsActive)
+	assert.Equal(t, "intentionally pinned", deps[0].Note)
+	assert.Contains(t, deps[1].Note, "replaced by local directory", "the scan note is kept")
+
+	deps = scan(Override{Path: "github.com/example/mod", Ignore: true})
+	require.Len(t, deps, 1)
+	assert.Equal(t, "github.com/example/local", deps[0].Path)
+
+	scanner := NewScanner(t.TempDir())
+	assert.Error(t, scanner.SetOverrides([]Override{{Ignore: true}}))
+	assert.Error(t, scanner.SetOverrides([]Override{{Path: "github.com/example/mod", StaleThresholdDays: -1}}))
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	scanner := NewScanner(t.TempDir())
+	require.NoError(t, scanner.SetIgnorePatterns([]string{"github.com/mycorp/*", "golang.org/x/..."}))
+
+	assert.True(t, scanner.isIgnored("github.com/mycorp/lib"))
+	assert.True(t, scanner.isIgnored("github.com/mycorp/lib/v2"), "modules below a matching path are ignored")
+	assert.True(t, scanner.isIgnored("golang.org/x/net"))
+	assert.False(t, scanner.isIgnored("github.com/mycorporation/lib"))
+	assert.False(t, scanner.isIgnored("github.com/other/lib"))
+
+	deps := scanner.withoutIgnored([]Dependency{
+		{Path: "github.com/mycorp/lib", Version: "v1.0.0"},
+		{Path: "github.com/other/lib", Version: "v1.0.0"},
+	})
+	require.Len(t, deps, 1)
+	assert.Equal(t, "github.com/other/lib", deps[0].Path)
+
+	assert.Error(t, scanner.SetIgnorePatterns([]string{"github.com/[mycorp"}))
+	assert.Error(t, scanner.SetIgnorePatterns([]string{""}))
+}
+
+func TestOverridesOfReplacedModules(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 400})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	for _, quick := range []bool{false, true} {
+		scanner := NewScanner(t.TempDir())
+		scanner.SetQuick(quick)
+		// Overrides are keyed by the requirement of go.mod, not the
+		// replacement which is looked up
+		require.NoError(t, scanner.SetOverrides([]Override{{Path: "github.com/original/mod", StaleThresholdDays: 730}}))
+		require.NoError(t, scanner.ScanDependencies(context.Background(), []Dependency{
+			{Path: "github.com/original/mod", Version: "v0.9.0", IsActive: true, Replace: &Replacement{Path: "github.com/example/mod", Version: "v1.0.0"}},
+		}))
+
+		deps := scanner.GetResults().Dependencies
+		require.Len(t, deps, 1)
+		assert.Equal(t, 400, deps[0].DaysSinceLastRelease)
+		assert.True(t, deps[0].IsActive, "quick: %v", quick)
+	}
+}
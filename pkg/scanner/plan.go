@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/license"
+	"github.com/steffakasid/govital/pkg/vuln"
+)
+
+// Plan lists the dependencies a scan would check and the services it would
+// query for each, see Scanner.Plan
+type Plan struct {
+	ProjectPath string `json:"project_path"`
+	// GoProxy is the GOPROXY list the version lookups go through
+	GoProxy      string              `json:"goproxy"`
+	Dependencies []PlannedDependency `json:"dependencies"`
+}
+
+// PlannedDependency is a dependency of a plan
+type PlannedDependency struct {
+	Path       string       `json:"path"`
+	Version    string       `json:"version"`
+	Module     string       `json:"module,omitempty"`
+	IsIndirect bool         `json:"is_indirect,omitempty"`
+	Replace    *Replacement `json:"replace,omitempty"`
+	// Private is set for modules matching GOPRIVATE or GONOSUMDB, which
+	// are not sent to public services
+	Private bool `json:"private,omitempty"`
+	// Repository is the source repository, empty if it is only resolved
+	// with a go-get lookup during the scan
+	Repository string `json:"repository,omitempty"`
+	// Host is the host of the repository
+	Host string `json:"host,omitempty"`
+	// Sources are the services queried for the dependency, e.g.
+	// "proxy https://proxy.golang.org"
+	Sources []string `json:"sources"`
+	Note    string   `json:"note,omitempty"`
+}
+
+// Plan lists the dependencies of the project and, for each, its source
+// repository and the services a scan with the current settings would
+// query, without any network access. Like offline scans the dependencies
+// are listed from the module cache, falling back to the requirements of
+// go.mod. Repositories of vanity import paths are only resolved during
+// the scan.
+func (s *Scanner) Plan(ctx context.Context) (*Plan, error) {
+	deps, err := s.planDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{ProjectPath: s.projectPath, GoProxy: s.goProxyValue(), Dependencies: make([]PlannedDependency, 0, len(deps))}
+	for _, dep := range s.withoutIgnored(deps) {
+		plan.Dependencies = append(plan.Dependencies, s.planDependency(ctx, dep))
+	}
+	return plan, nil
+}
+
+// planDependencies lists the dependencies like an offline scan, so no
+// module is downloaded
+func (s *Scanner) planDependencies(ctx context.Context) ([]Dependency, error) {
+	offline := s.offline
+	s.offline = true
+	defer func() { s.offline = offline }()
+
+	modules, err := s.discoverModules()
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) == 0 {
+		return s.listDependencies(ctx, s.projectPath, false)
+	}
+	var deps []Dependency
+	for _, mod := range modules {
+		moduleDeps, err := s.listDependencies(ctx, mod.Dir, true)
+		if err != nil {
+			return nil, err
+		}
+		for i := range moduleDeps {
+			moduleDeps[i].Module = mod.Path
+		}
+		deps = append(deps, moduleDeps...)
+	}
+	return deps, nil
+}
+
+// planDependency determines the repository and the services of a
+// dependency in the order the scan queries them
+func (s *Scanner) planDependency(ctx context.Context, dep Dependency) PlannedDependency {
+	planned := PlannedDependency{Path: dep.Path, Version: dep.Version, Module: dep.Module, IsIndirect: dep.IsIndirect, Replace: dep.Replace, Sources: []string{}}
+	if dep.Replace.IsLocal() {
+		planned.Note = fmt.Sprintf("replaced by local directory %s, not checked", dep.Replace.Path)
+		return planned
+	}
+	if s.acknowledgedDependencies[dep.Path] {
+		planned.Note = "acknowledged"
+	}
+	path, _ := dep.lookupModule()
+	planned.Private = s.IsPrivate(ctx, path)
+
+	repository, known := s.resolver.Known(path)
+	if known {
+		planned.Repository = repository.URL
+		planned.Host = repository.Host()
+	}
+
+	switch {
+	case s.offline:
+		planned.Sources = append(planned.Sources, "module cache")
+		return planned
+	case s.privatePatterns(ctx).direct(path):
+		target := path
+		if known {
+			target = repository.URL
+		}
+		planned.Sources = append(planned.Sources, "git "+target)
+	default:
+		planned.Sources = append(planned.Sources, "proxy "+s.goProxyValue())
+	}
+	if s.quick {
+		return planned
+	}
+
+	if !known {
+		planned.Sources = append(planned.Sources, "go-get https://"+path+"?go-get=1")
+	}
+	if s.licenseClient != nil && !planned.Private {
+		planned.Sources = append(planned.Sources, "licenses "+serviceHost(s.licenseClient.BaseURL, license.DefaultBaseURL))
+	}
+	if s.vulnClient != nil && !planned.Private {
+		planned.Sources = append(planned.Sources, "vulnerabilities "+serviceHost(s.vulnClient.BaseURL, vuln.DefaultBaseURL))
+	}
+	if s.forge != nil {
+		switch {
+		case !known:
+			planned.Sources = append(planned.Sources, "forge of the resolved repository")
+		default:
+			if apiHost, err := s.forge.APIHost(repository); err == nil {
+				planned.Sources = append(planned.Sources, "forge "+apiHost)
+			}
+		}
+	}
+	return planned
+}
+
+// goProxyValue returns the GOPROXY list of the version lookups
+func (s *Scanner) goProxyValue() string {
+	specs, err := s.goProxyList()
+	if err != nil {
+		return os.Getenv("GOPROXY")
+	}
+	var value strings.Builder
+	for i, spec := range specs {
+		value.WriteString(spec.url)
+		if i < len(specs)-1 {
+			if spec.fallBackOnError {
+				value.WriteString("|")
+			} else {
+				value.WriteString(",")
+			}
+		}
+	}
+	return value.String()
+}
+
+// serviceHost returns the host of a service URL, the default if unset
+func serviceHost(baseURL, defaultURL string) string {
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "http://")
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("plan requested %s", r.URL)
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+	t.Setenv("GOPRIVATE", "git.corp.example.com")
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GONOSUMDB", "")
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	dir := t.TempDir()
+	goMod := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/example/mod v1.0.0
+	git.corp.example.com/team/lib v0.3.0
+	example.com/local v1.0.0
+	go.uber.org/zap v1.27.0
+)
+
+replace example.com/local => ../local
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644))
+
+	scanner := NewScanner(dir)
+	scanner.SetCheckLicenses(true)
+	scanner.SetCheckRepositories(true, forge.Config{})
+	plan, err := scanner.Plan(context.Background())
+	require.NoError(t, err)
+	assert.False(t, scanner.offline)
+	assert.Equal(t, server.URL, plan.GoProxy)
+
+	deps := map[string]PlannedDependency{}
+	for _, dep := range plan.Dependencies {
+		deps[dep.Path] = dep
+	}
+	require.Len(t, deps, 4)
+
+	public := deps["github.com/example/mod"]
+	assert.Equal(t, "https://github.com/example/mod", public.Repository)
+	assert.Equal(t, []string{"proxy " + server.URL, "licenses api.deps.dev", "forge api.github.com"}, public.Sources)
+
+	private := deps["git.corp.example.com/team/lib"]
+	assert.True(t, private.Private)
+	assert.Equal(t, []string{
+		"git git.corp.example.com/team/lib",
+		"go-get https://git.corp.example.com/team/lib?go-get=1",
+		"forge of the resolved repository",
+	}, private.Sources)
+
+	local := deps["example.com/local"]
+	assert.Empty(t, local.Sources)
+	assert.Contains(t, local.Note, "local directory")
+}
+
+func TestPlanQuick(t *testing.T) {
+	t.Setenv("GOPROXY", "https://proxy.example.com")
+	t.Setenv("GOPRIVATE", "")
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GONOSUMDB", "")
+
+	scanner := NewScanner(".")
+	scanner.SetQuick(true)
+	scanner.SetCheckLicenses(true)
+	planned := scanner.planDependency(context.Background(), Dependency{Path: "github.com/example/mod", Version: "v1.0.0"})
+	assert.Equal(t, []string{"proxy https://proxy.example.com"}, planned.Sources)
+}
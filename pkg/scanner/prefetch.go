@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/tool"
+)
+
+// directEnv makes go list read modules from their repositories like the go
+// command does for GONOPROXY modules, prompts are disabled
+var directEnv = []string{"GOPROXY=direct", "GOFLAGS=-mod=mod", "GOWORK=off", "GIT_TERMINAL_PROMPT=0"}
+
+// listedModule is a module reported by go list -m -json
+type listedModule struct {
+	versionInfo
+	Path     string
+	Versions []string
+	GoMod    string
+	Error    *struct {
+		Err string
+	}
+}
+
+// listModules runs go list -m -json with directEnv and decodes the modules
+// it reports, one per target
+func (s *Scanner) listModules(ctx context.Context, args ...string) ([]listedModule, error) {
+	output, err := s.executor.Execute(ctx, os.TempDir(), directEnv, tool.Go, append([]string{"list", "-m", "-json"}, args...)...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	var modules []listedModule
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var listed listedModule
+		if err := decoder.Decode(&listed); err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %w", err)
+		}
+		modules = append(modules, listed)
+	}
+	return modules, nil
+}
+
+// directBatch holds the used versions and known versions of the private
+// modules fetched directly, looked up together by prefetchDirect. It is
+// read-only once the lookups start.
+type directBatch struct {
+	// modules maps module path and version to the listed module
+	modules map[string]listedModule
+	// versions maps module paths to their known versions
+	versions map[string][]string
+}
+
+// answer returns the response to a proxy endpoint from the batch. Only the
+// used version, its go.mod and the version list are prefetched.
+func (b *directBatch) answer(files FileReader, modulePath, query, kind string) ([]byte, bool) {
+	if b == nil {
+		return nil, false
+	}
+	if kind == "list" {
+		versions, ok := b.versions[modulePath]
+		return []byte(strings.Join(versions, "\n")), ok
+	}
+	listed, ok := b.modules[modulePath+"@"+query]
+	if !ok {
+		return nil, false
+	}
+	switch kind {
+	case "mod":
+		if listed.GoMod == "" {
+			return nil, false
+		}
+		body, err := files.ReadFile(listed.GoMod)
+		return body, err == nil
+	default:
+		body, err := json.Marshal(listed.versionInfo)
+		return body, err == nil
+	}
+}
+
+// prefetchDirect looks up the used versions, their go.mod and the known
+// versions of all private modules fetched directly in a single go list run
+// instead of one per module and endpoint, so the go command queries the
+// repositories concurrently and the scan spawns one process instead of
+// hundreds. Modules the batch fails for are looked up one by one later,
+// which reports their errors.
+func (s *Scanner) prefetchDirect(ctx context.Context, queue []*Dependency) {
+	s.directBatch = nil
+	if s.offline || s.quick {
+		return
+	}
+	var targets []string
+	seen := make(map[string]bool)
+	for _, dep := range queue {
+		path, version := dep.lookupModule()
+		target := path + "@" + version
+		if dep.Replace.IsLocal() || version == "" || seen[target] || s.lookupStage(ctx, path) != StageGit {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+	if len(targets) < 2 {
+		return
+	}
+
+	start := time.Now()
+	// The batch is bounded like a single fetch, since the go command queries
+	// the repositories concurrently
+	gitCtx, cancel := withTimeout(ctx, s.gitTimeout)
+	defer cancel()
+	modules, err := s.listModules(gitCtx, append([]string{"-e", "-versions"}, targets...)...)
+	if err != nil {
+		logFailure(ctx, StageGit, "Failed to list private modules in one batch, listing them one by one", err, "modules", len(targets))
+		return
+	}
+
+	batch := &directBatch{modules: make(map[string]listedModule, len(modules)), versions: make(map[string][]string)}
+	for _, listed := range modules {
+		if listed.Error != nil || listed.Version == "" {
+			continue
+		}
+		batch.modules[listed.Path+"@"+listed.Version] = listed
+		batch.versions[listed.Path] = listed.Versions
+	}
+	s.directBatch = batch
+	logger(ctx).Debug("Listed private modules in one batch", "modules", len(targets), "listed", len(batch.modules), "duration", time.Since(start))
+}
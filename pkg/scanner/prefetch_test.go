@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useFileProxy serves the modules, each with the versions v1.0.0 and
+// v1.1.0, from a file proxy which replaces the repositories of directEnv
+func useFileProxy(tb testing.TB, modules []string) {
+	tb.Helper()
+	proxyDir := tb.TempDir()
+	for _, path := range modules {
+		dir := filepath.Join(proxyDir, filepath.FromSlash(path), "@v")
+		require.NoError(tb, os.MkdirAll(dir, 0o755))
+		require.NoError(tb, os.WriteFile(filepath.Join(dir, "list"), []byte("v1.0.0\nv1.1.0\n"), 0o644))
+		for i, version := range []string{"v1.0.0", "v1.1.0"} {
+			info := fmt.Sprintf(`{"Version":%q,"Time":"2024-0%d-01T00:00:00Z"}`, version, i+1)
+			require.NoError(tb, os.WriteFile(filepath.Join(dir, version+".info"), []byte(info), 0o644))
+			require.NoError(tb, os.WriteFile(filepath.Join(dir, version+".mod"), []byte("module "+path+"\n\ngo 1.22\n"), 0o644))
+		}
+	}
+
+	env := directEnv
+	directEnv = []string{"GOPROXY=file://" + filepath.ToSlash(proxyDir), "GOSUMDB=off", "GOFLAGS=-mod=mod", "GOWORK=off"}
+	tb.Cleanup(func() { directEnv = env })
+	tb.Setenv("GOMODCACHE", tb.TempDir())
+}
+
+func TestPrefetchDirect(t *testing.T) {
+	useFileProxy(t, []string{"git.corp.example.com/team/a", "git.corp.example.com/team/b"})
+
+	scanner := NewScanner(".")
+	scanner.SetPrivatePatterns("git.corp.example.com", "", "")
+	scanner.prefetchDirect(context.Background(), []*Dependency{
+		{Path: "git.corp.example.com/team/a", Version: "v1.0.0"},
+		{Path: "git.corp.example.com/team/b", Version: "v1.1.0"},
+		{Path: "git.corp.example.com/team/missing", Version: "v1.0.0"},
+		{Path: "github.com/example/public", Version: "v1.0.0"},
+	})
+	require.NotNil(t, scanner.directBatch)
+	assert.Len(t, scanner.directBatch.modules, 2)
+
+	ctx := context.Background()
+	body, err := scanner.fetchFromRepository(ctx, "git.corp.example.com/team/a", "@v/v1.0.0.info")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Version":"v1.0.0","Time":"2024-01-01T00:00:00Z"}`, string(body))
+
+	body, err = scanner.fetchFromRepository(ctx, "git.corp.example.com/team/b", "@v/list")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0\nv1.1.0", string(body))
+
+	// Lookups missing from the batch still run go list for the module
+	body, err = scanner.fetchFromRepository(ctx, "git.corp.example.com/team/a", "@v/v1.1.0.info")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Version":"v1.1.0","Time":"2024-02-01T00:00:00Z"}`, string(body))
+
+	_, err = scanner.fetchFromRepository(ctx, "git.corp.example.com/team/missing", "@v/v1.0.0.info")
+	assert.Error(t, err)
+}
+
+func TestPrefetchDirectSkipsSingleModule(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.SetPrivatePatterns("git.corp.example.com", "", "")
+	scanner.prefetchDirect(context.Background(), []*Dependency{{Path: "git.corp.example.com/team/a", Version: "v1.0.0"}})
+	assert.Nil(t, scanner.directBatch)
+}
+
+// BenchmarkListModules compares one go list run per private module with
+// the single batched run of prefetchDirect
+func BenchmarkListModules(b *testing.B) {
+	var modules, targets []string
+	for i := range 20 {
+		path := fmt.Sprintf("git.corp.example.com/team/mod%d", i)
+		modules = append(modules, path)
+		targets = append(targets, path+"@v1.0.0")
+	}
+	useFileProxy(b, modules)
+	ctx := context.Background()
+	scanner := NewScanner(".")
+
+	b.Run("per-module", func(b *testing.B) {
+		for b.Loop() {
+			for _, target := range targets {
+				_, err := scanner.listModules(ctx, "-versions", target)
+				require.NoError(b, err)
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for b.Loop() {
+			listed, err := scanner.listModules(ctx, append([]string{"-e", "-versions"}, targets...)...)
+			require.NoError(b, err)
+			require.Len(b, listed, len(targets))
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/tool"
+	"golang.org/x/mod/module"
+)
+
+// privacy holds the module path patterns of GOPRIVATE, GONOPROXY and
+// GONOSUMDB, which decide like for the go command which modules are not
+// fetched from the proxies and not sent to public services
+type privacy struct {
+	private string
+	noProxy string
+	noSumDB string
+}
+
+// loadPrivacy reads the patterns with go env, which includes the values set
+// with go env -w. The environment is used if go env fails. GONOPROXY and
+// GONOSUMDB default to GOPRIVATE, GONOSUMCHECK is the name GONOSUMDB had
+// before Go 1.13 and extends it.
+func (s *Scanner) loadPrivacy(ctx context.Context) privacy {
+	env := map[string]string{
+		"GOPRIVATE": os.Getenv("GOPRIVATE"),
+		"GONOPROXY": os.Getenv("GONOPROXY"),
+		"GONOSUMDB": os.Getenv("GONOSUMDB"),
+	}
+	output, err := s.executor.Execute(ctx, "", nil, tool.Go, "env", "-json", "GOPRIVATE", "GONOPROXY", "GONOSUMDB")
+	if err == nil {
+		err = json.Unmarshal(output, &env)
+	}
+	if err != nil {
+		logger(ctx).Debug("Failed to read GOPRIVATE with go env, using the environment", slog.Any(logKeyError, err))
+	}
+	return newPrivacy(env["GOPRIVATE"], env["GONOPROXY"], joinPatterns(env["GONOSUMDB"], os.Getenv("GONOSUMCHECK")))
+}
+
+func newPrivacy(private, noProxy, noSumDB string) privacy {
+	if noProxy == "" {
+		noProxy = private
+	}
+	if noSumDB == "" {
+		noSumDB = private
+	}
+	return privacy{private: private, noProxy: noProxy, noSumDB: noSumDB}
+}
+
+func joinPatterns(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + "," + b
+}
+
+// direct reports whether the module is fetched from its repository instead
+// of the proxies
+func (p privacy) direct(modulePath string) bool {
+	return module.MatchPrefixPatterns(p.noProxy, modulePath)
+}
+
+// isPrivate reports whether the module path must not be sent to public
+// services like deps.dev or OSV
+func (p privacy) isPrivate(modulePath string) bool {
+	return module.MatchPrefixPatterns(p.private, modulePath) || module.MatchPrefixPatterns(p.noSumDB, modulePath)
+}
+
+// SetPrivatePatterns overrides the GOPRIVATE, GONOPROXY and GONOSUMDB
+// patterns of the environment. Empty GONOPROXY and GONOSUMDB patterns
+// default to GOPRIVATE.
+func (s *Scanner) SetPrivatePatterns(private, noProxy, noSumDB string) {
+	s.privacyOnce.Do(func() {})
+	s.privacy = newPrivacy(private, noProxy, noSumDB)
+}
+
+// privatePatterns returns the patterns, reading them on first use
+func (s *Scanner) privatePatterns(ctx context.Context) privacy {
+	s.privacyOnce.Do(func() {
+		s.privacy = s.loadPrivacy(ctx)
+	})
+	return s.privacy
+}
+
+// IsPrivate reports whether the module matches GOPRIVATE or GONOSUMDB, so
+// lookups of it on public services like deps.dev are skipped
+func (s *Scanner) IsPrivate(ctx context.Context, modulePath string) bool {
+	return s.privatePatterns(ctx).isPrivate(modulePath)
+}
+
+// lookupStage returns the stage of the version lookups of the module, git
+// for private modules fetched directly
+func (s *Scanner) lookupStage(ctx context.Context, modulePath string) ErrorStage {
+	if s.privatePatterns(ctx).direct(modulePath) {
+		return StageGit
+	}
+	return StageProxy
+}
+
+// fetchDirect answers a proxy endpoint from the repository of the module
+// with go list and GOPROXY=direct, like the go command does for GONOPROXY
+// modules. git authenticates with the usual netrc, credential helper or SSH
+// configuration, prompts are disabled. Each fetch is bounded by the git
+// timeout.
+func (s *Scanner) fetchDirect(ctx context.Context, modulePath, endpoint string) ([]byte, error) {
+	gitCtx, cancel := withTimeout(ctx, s.gitTimeout)
+	defer cancel()
+	body, err := s.fetchFromRepository(gitCtx, modulePath, endpoint)
+	if err != nil && ctx.Err() == nil && errors.Is(gitCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("failed to fetch %s of %s from its repository within the git timeout of %s: %w", endpoint, modulePath, s.gitTimeout, context.DeadlineExceeded)
+	}
+	return body, err
+}
+
+// fetchFromRepository answers a proxy endpoint from the repository of the
+// module without a timeout of its own
+func (s *Scanner) fetchFromRepository(ctx context.Context, modulePath, endpoint string) ([]byte, error) {
+	query, kind, err := directQuery(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if body, ok := s.directBatch.answer(s.files, modulePath, query, kind); ok {
+		logger(ctx).Debug("Read from the batched go list", "path", modulePath, "endpoint", endpoint)
+		return body, nil
+	}
+	if kind == "info" {
+		if body, ok := s.directInfo(ctx, modulePath, query); ok {
+			logger(ctx).Debug("Read from the repository with git", "path", modulePath, "endpoint", endpoint)
+			return body, nil
+		}
+	}
+
+	var args []string
+	target := modulePath + "@" + query
+	if kind == "list" {
+		args = append(args, "-versions")
+		target = modulePath
+	}
+	modules, err := s.listModules(ctx, append(args, target)...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to fetch %s directly (go list -m %s): %w", endpoint, target, err)
+	}
+	if len(modules) != 1 {
+		return nil, fmt.Errorf("go list returned %d modules for %s", len(modules), target)
+	}
+	info := modules[0]
+	logger(ctx).Debug("Fetched directly from the repository", "path", modulePath, "endpoint", endpoint)
+
+	switch kind {
+	case "list":
+		return []byte(strings.Join(info.Versions, "\n")), nil
+	case "mod":
+		if info.GoMod == "" {
+			return nil, fmt.Errorf("go list returned no go.mod for %s", target)
+		}
+		return s.files.ReadFile(info.GoMod)
+	default:
+		return json.Marshal(info.versionInfo)
+	}
+}
+
+// directQuery maps a proxy endpoint to the version query of go list and the
+// kind of the response: list, info or mod
+func directQuery(endpoint string) (string, string, error) {
+	if endpoint == "@v/list" {
+		return "", "list", nil
+	}
+	if endpoint == "@latest" {
+		return "latest", "info", nil
+	}
+	name, ok := strings.CutPrefix(endpoint, "@v/")
+	if !ok {
+		return "", "", fmt.Errorf("unsupported proxy endpoint %s", endpoint)
+	}
+	for _, kind := range []string{"info", "mod"} {
+		if escaped, ok := strings.CutSuffix(name, "."+kind); ok {
+			version, err := module.UnescapeVersion(escaped)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid version %s: %w", escaped, err)
+			}
+			return version, kind, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported proxy endpoint %s", endpoint)
+}
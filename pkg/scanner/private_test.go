@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacy(t *testing.T) {
+	p := newPrivacy("git.corp.example.com,*.internal", "", "")
+	assert.True(t, p.direct("git.corp.example.com/team/mod"))
+	assert.True(t, p.isPrivate("git.corp.example.com/team/mod"))
+	assert.True(t, p.direct("go.build.internal/tools"))
+	assert.False(t, p.direct("github.com/example/mod"))
+	assert.False(t, p.isPrivate("github.com/example/mod"))
+
+	// Modules of a private proxy are fetched from the proxy but kept from
+	// public services
+	p = newPrivacy("git.corp.example.com", "none", "")
+	assert.False(t, p.direct("git.corp.example.com/team/mod"))
+	assert.True(t, p.isPrivate("git.corp.example.com/team/mod"))
+
+	p = newPrivacy("", "github.com/example", "github.com/other")
+	assert.True(t, p.direct("github.com/example/mod"))
+	assert.False(t, p.isPrivate("github.com/example/mod"))
+	assert.True(t, p.isPrivate("github.com/other/mod"))
+}
+
+func TestDirectQuery(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		query    string
+		kind     string
+	}{
+		{"@v/list", "", "list"},
+		{"@latest", "latest", "info"},
+		{"@v/v1.2.0.info", "v1.2.0", "info"},
+		{"@v/v0.0.0-20240101000000-!abcdef.mod", "v0.0.0-20240101000000-Abcdef", "mod"},
+	}
+	for _, tt := range tests {
+		query, kind, err := directQuery(tt.endpoint)
+		require.NoError(t, err, tt.endpoint)
+		assert.Equal(t, tt.query, query, tt.endpoint)
+		assert.Equal(t, tt.kind, kind, tt.endpoint)
+	}
+
+	_, _, err := directQuery("@v/v1.2.0.zip")
+	assert.Error(t, err)
+}
+
+func TestPrivateModulesSkipPublicLookups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	}))
+	defer server.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetPrivatePatterns("git.corp.example.com", "none", "")
+	scanner.SetCheckVulnerabilities(true)
+	scanner.vulnClient.BaseURL = server.URL
+
+	deps := []*Dependency{{Path: "git.corp.example.com/team/mod", Version: "v1.0.0"}}
+	scanner.checkVulnerabilities(context.Background(), deps)
+
+	assert.Empty(t, deps[0].Vulnerabilities)
+	assert.Empty(t, scanner.warnings.flush())
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+// quickRequestTimeout bounds each proxy request of a quick scan, so a slow
+// or unreachable proxy can't stall a pre-commit hook
+const quickRequestTimeout = 2 * time.Second
+
+// SetQuick enables quick scans. They only determine the release time of the
+// used versions, from the pseudo-version timestamp or a single .info
+// request, and skip update checks, health signals, license, vulnerability
+// and repository lookups as well as git. Results are marked as quick.
+func (s *Scanner) SetQuick(quick bool) {
+	s.quick = quick
+	s.result.Quick = quick
+}
+
+// readGoMod returns the requirements of the go.mod in dir. Quick scans use
+// it instead of go list, which may have to download the module graph.
+func (s *Scanner) readGoMod(dir string) ([]Dependency, error) {
+	data, err := s.files.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return s.ParseGoMod(data)
+}
+
+// checkReleaseTime is the quick variant of checkMaintenanceStatus
+func (s *Scanner) checkReleaseTime(ctx context.Context, dep *Dependency) {
+	releaseTime, err := module.PseudoVersionTime(dep.Version)
+	if err != nil {
+		requestCtx, cancel := context.WithTimeout(ctx, quickRequestTimeout)
+		releaseTime, err = s.getVersionInfoFromProxy(requestCtx, dep.Path, dep.Version)
+		cancel()
+	}
+	if err != nil {
+		logFailure(ctx, s.lookupStage(ctx, dep.Path), "Failed to get release time", err)
+		if ctx.Err() == nil {
+			s.warnings.add("Failed to get version info from proxy: "+warningReason(err), dep.Path)
+		}
+		dep.Error = dep.addError(s.lookupStage(ctx, dep.Path), err)
+		return
+	}
+
+	dep.LastReleaseTime = releaseTime
+	dep.DaysSinceLastRelease = int(s.now().Sub(releaseTime).Hours() / 24)
+	dep.IsActive = !s.isDependencyStale(dep.requirementPath(), dep.DaysSinceLastRelease)
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickScan(t *testing.T) {
+	var requests atomic.Int32
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 400})
+	defer server.Close()
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+	t.Setenv("GOPROXY", counting.URL)
+
+	dir := t.TempDir()
+	goMod := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/example/mod v1.0.0
+	github.com/example/pseudo v0.0.0-20200102030405-abcdef123456
+	github.com/example/indirect v1.0.0 // indirect
+)
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644))
+
+	scanner := NewScanner(dir)
+	scanner.SetQuick(true)
+	scanner.SetCheckVulnerabilities(true)
+	require.NoError(t, scanner.Scan(context.Background()))
+
+	result := scanner.GetResults()
+	assert.True(t, result.Quick)
+	require.Len(t, result.Dependencies, 2)
+	assert.Equal(t, int32(1), requests.Load(), "only the .info of the tagged version is requested")
+
+	mod := result.Dependencies[0]
+	assert.False(t, mod.IsActive)
+	assert.Empty(t, mod.Latest, "no update check")
+
+	pseudo := result.Dependencies[1]
+	assert.Equal(t, 2020, pseudo.LastReleaseTime.Year())
+	assert.Nil(t, pseudo.Error)
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/detect"
+)
+
+// SetRecursive enables recursive scans. They scan every module found below
+// the project path like the members of a workspace, e.g. in monorepos
+// without go.work. The result holds a summary per module in addition to the
+// aggregated one. vendor, testdata and hidden directories are skipped like
+// by the go command.
+func (s *Scanner) SetRecursive(recursive bool) {
+	s.recursive = recursive
+	s.result.Recursive = recursive
+}
+
+// discoverNestedModules returns all modules in and below the project path,
+// the top level module or workspace members first
+func (s *Scanner) discoverNestedModules() ([]workspaceModule, error) {
+	project, err := detect.Detect(s.projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []workspaceModule
+	seen := make(map[string]string)
+	for _, module := range append(project.Modules, project.Nested...) {
+		if module.Path == "" {
+			eslog.Logger.Warn("Skipping module without module directive in go.mod", slog.String("dir", module.Dir))
+			continue
+		}
+		// Summaries and dependencies are attributed by module path
+		if dir, ok := seen[module.Path]; ok {
+			eslog.Logger.Warn("Skipping module found twice", slog.String(logKeyModule, module.Path), slog.String("dir", module.Dir), slog.String("first_dir", dir))
+			continue
+		}
+		seen[module.Path] = module.Dir
+		modules = append(modules, workspaceModule{
+			Path: module.Path,
+			Dir:  filepath.Join(s.projectPath, filepath.FromSlash(module.Dir)),
+		})
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no go.mod found in or below %s", s.projectPath)
+	}
+	return modules, nil
+}
@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMonorepo creates modules without go.work in dir, plus a module in a
+// vendor directory which must not be scanned
+func writeMonorepo(t *testing.T, dir string) {
+	t.Helper()
+	modules := map[string]string{
+		"services/api":    "module example.com/api\n\ngo 1.22\n\nrequire github.com/example/a v0.0.0-20200102030405-abcdef123456\n",
+		"services/worker": "module example.com/worker\n\ngo 1.22\n\nrequire (\n\tgithub.com/example/a v0.0.0-20200102030405-abcdef123456\n\tgithub.com/example/b v0.0.0-20210102030405-abcdef123456\n)\n",
+		"vendor/example":  "module example.com/vendored\n",
+		".cache/example":  "module example.com/cached\n",
+	}
+	for rel, goMod := range modules {
+		modDir := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(modDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte(goMod), 0o600))
+	}
+}
+
+func TestDiscoverNestedModules(t *testing.T) {
+	t.Run("modules below the project path", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeMonorepo(t, tmpDir)
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/root\n"), 0o600))
+		scanner := NewScanner(tmpDir)
+		scanner.SetRecursive(true)
+
+		modules, err := scanner.discoverModules()
+
+		require.NoError(t, err)
+		assert.Equal(t, []workspaceModule{
+			{Path: "example.com/root", Dir: tmpDir},
+			{Path: "example.com/api", Dir: filepath.Join(tmpDir, "services", "api")},
+			{Path: "example.com/worker", Dir: filepath.Join(tmpDir, "services", "worker")},
+		}, modules)
+	})
+
+	t.Run("duplicate module paths are scanned once", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		for _, rel := range []string{"a", "b"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, rel), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, rel, "go.mod"), []byte("module example.com/same\n"), 0o600))
+		}
+		scanner := NewScanner(tmpDir)
+		scanner.SetRecursive(true)
+
+		modules, err := scanner.discoverModules()
+
+		require.NoError(t, err)
+		assert.Equal(t, []workspaceModule{{Path: "example.com/same", Dir: filepath.Join(tmpDir, "a")}}, modules)
+	})
+
+	t.Run("no modules", func(t *testing.T) {
+		scanner := NewScanner(t.TempDir())
+		scanner.SetRecursive(true)
+
+		_, err := scanner.discoverModules()
+
+		assert.ErrorContains(t, err, "no go.mod found")
+	})
+}
+
+func TestScanRecursive(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeMonorepo(t, tmpDir)
+	scanner := NewScanner(tmpDir)
+	scanner.SetRecursive(true)
+	scanner.SetQuick(true)
+
+	require.NoError(t, scanner.Scan(context.Background()))
+
+	result := scanner.GetResults()
+	assert.True(t, result.Recursive)
+	assert.Nil(t, result.Fingerprint)
+	require.Len(t, result.Modules, 2)
+	assert.Equal(t, "example.com/api", result.Modules[0].Path)
+	assert.Equal(t, 1, result.Modules[0].Summary.Total)
+	assert.Equal(t, "example.com/worker", result.Modules[1].Path)
+	assert.Equal(t, 2, result.Modules[1].Summary.Total)
+	assert.Equal(t, 3, result.Summary.Total)
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/repo"
+)
+
+// maxReleases bounds the releases read per repository, older releases are
+// rarely between the used and the available version
+const maxReleases = 100
+
+// SetFetchReleaseNotes enables collecting the release notes between the
+// used and the available version of outdated dependencies. It costs up to
+// two forge requests per outdated dependency and only applies if
+// repository checks are enabled.
+func (s *Scanner) SetFetchReleaseNotes(fetch bool) {
+	s.fetchReleaseNotes = fetch
+}
+
+// checkReleaseNotes sets the release notes of the versions after the used
+// one up to the available update. The forge releases are preferred, the
+// CHANGELOG.md of the module directory is read if none of them match.
+// Failures are reported as warning, a missing changelog is not.
+func (s *Scanner) checkReleaseNotes(ctx context.Context, dep *Dependency, repository repo.Repository) {
+	if dep.Update == "" {
+		return
+	}
+	prefix := changelog.TagPrefix(dep.Path, repository.Root)
+
+	releases, err := s.forge.Releases(ctx, repository, maxReleases)
+	if err != nil {
+		s.releaseNotesFailed(ctx, dep, err)
+		return
+	}
+	notes := changelog.FromReleases(releases, prefix, dep.Version, dep.Update)
+	if len(notes) == 0 {
+		content, err := s.forge.File(ctx, repository, prefix+"CHANGELOG.md")
+		if err != nil {
+			if !forge.IsNotFound(err) {
+				s.releaseNotesFailed(ctx, dep, err)
+			}
+			return
+		}
+		notes = changelog.ParseChangelog(string(content), dep.Version, dep.Update)
+	}
+	dep.ReleaseNotes = notes
+}
+
+func (s *Scanner) releaseNotesFailed(ctx context.Context, dep *Dependency, err error) {
+	if ctx.Err() != nil || errors.Is(err, forge.ErrUnsupported) {
+		return
+	}
+	logFailure(ctx, StageForge, "Failed to fetch release notes", err)
+	s.warnings.add("Failed to fetch release notes: "+warningReason(err), dep.Path)
+	dep.addError(StageForge, err)
+}
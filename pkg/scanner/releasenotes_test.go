@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanDependenciesReleaseNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		releases  string
+		changelog string
+		expected  []changelog.Note
+	}{
+		{
+			name:     "releases",
+			releases: `[{"tag_name":"v1.2.0","body":"second","html_url":"https://github.com/example/mod/releases/tag/v1.2.0"},{"tag_name":"v1.1.0","body":"first"},{"tag_name":"v1.0.0","body":"used"}]`,
+			expected: []changelog.Note{
+				{Version: "v1.2.0", Notes: "second", URL: "https://github.com/example/mod/releases/tag/v1.2.0"},
+				{Version: "v1.1.0", Notes: "first"},
+			},
+		},
+		{
+			name:      "changelog without releases",
+			releases:  `[]`,
+			changelog: `{"content":"## 1.2.0\n\n- second\n\n## 1.0.0\n\n- used\n","encoding":"text"}`,
+			expected:  []changelog.Note{{Version: "v1.2.0", Notes: "- second"}},
+		},
+		{
+			name:     "neither",
+			releases: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeProxy(t, map[string]int{"v1.0.0": 100, "v1.2.0": 10})
+			defer server.Close()
+			t.Setenv("GOPROXY", server.URL)
+			github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/releases"):
+					_, _ = w.Write([]byte(tt.releases))
+				case strings.HasSuffix(r.URL.Path, "/contents/CHANGELOG.md") && tt.changelog != "":
+					_, _ = w.Write([]byte(tt.changelog))
+				case strings.HasSuffix(r.URL.Path, "/commits"):
+					_, _ = w.Write([]byte(`[]`))
+				case r.URL.Path == "/repos/example/mod":
+					_, _ = w.Write([]byte(`{}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer github.Close()
+
+			scanner := NewScanner(".")
+			scanner.SetCheckRepositories(true, forge.Config{})
+			scanner.SetFetchReleaseNotes(true)
+			scanner.forge.GitHubURL = github.URL
+
+			err := scanner.ScanDependencies(context.Background(), []Dependency{
+				{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+			})
+
+			require.NoError(t, err)
+			dep := scanner.GetResults().Dependencies[0]
+			assert.Equal(t, "v1.2.0", dep.Update)
+			assert.Equal(t, tt.expected, dep.ReleaseNotes)
+			assert.Nil(t, dep.Error, "a missing changelog is no error")
+		})
+	}
+}
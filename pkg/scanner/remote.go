@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ScanRemote scans the dependencies of a module which is not checked out.
+// The target is a module path with an optional version query, e.g.
+// github.com/org/repo@v1.2.0 or github.com/org/repo@main. The go.mod is
+// fetched from the Go proxy, without a query the latest version is used.
+func (s *Scanner) ScanRemote(ctx context.Context, target string) error {
+	goMod, version, err := s.fetchRemoteGoMod(ctx, target)
+	if err != nil {
+		return err
+	}
+	modulePath, _, _ := strings.Cut(target, "@")
+	logger(ctx).Info("Scanning from the module proxy", slog.String(logKeyModule, modulePath), slog.String(logKeyVersion, version))
+	start := time.Now()
+
+	deps, err := s.ParseGoMod(goMod)
+	if err != nil {
+		return err
+	}
+
+	fingerprint := NewFingerprint(goMod, nil)
+	fingerprint.Revision = s.extractCommitHash(version)
+	s.SetFingerprint(fingerprint)
+	if !s.baselineFingerprint.SameProject(fingerprint) {
+		logger(ctx).Warn("Ignoring the baseline of another project", slog.String("baseline", s.baselineFingerprint.Module), slog.String("project", fingerprint.Module))
+		s.baseline = nil
+	}
+	s.result.ProjectPath = modulePath + "@" + version
+
+	if s.checkToolchain {
+		directives, err := parseGoModDirectives("go.mod", goMod)
+		if err != nil {
+			return err
+		}
+		s.setToolchainFindings(ctx, []goModDirectives{directives}, deps)
+	}
+
+	if err := s.ScanDependencies(ctx, deps); err != nil {
+		logger(ctx).Error("Scan aborted", slog.Any(logKeyError, err))
+		return err
+	}
+	s.logScanned(ctx, start)
+	return nil
+}
+
+// fetchRemoteGoMod resolves the version query of the target and returns the
+// go.mod of the resolved version
+func (s *Scanner) fetchRemoteGoMod(ctx context.Context, target string) ([]byte, string, error) {
+	modulePath, query, _ := strings.Cut(target, "@")
+	if err := module.CheckPath(modulePath); err != nil {
+		return nil, "", fmt.Errorf("invalid remote module %s: %w", target, err)
+	}
+
+	version, err := s.resolveVersionQuery(ctx, modulePath, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid version %s: %w", version, err)
+	}
+	goMod, err := s.fetchFromProxy(ctx, modulePath, "@v/"+escapedVersion+".mod")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch go.mod of %s@%s: %w", modulePath, version, err)
+	}
+	return goMod, version, nil
+}
+
+// resolveVersionQuery returns the canonical version for a version, branch
+// or commit query. The proxy resolves queries in the .info endpoint like
+// go get does.
+func (s *Scanner) resolveVersionQuery(ctx context.Context, modulePath, query string) (string, error) {
+	endpoint := "@latest"
+	if query != "" && query != "latest" {
+		escapedQuery, err := module.EscapeVersion(query)
+		if err != nil {
+			return "", fmt.Errorf("invalid version query %s: %w", query, err)
+		}
+		endpoint = "@v/" + escapedQuery + ".info"
+	}
+
+	body, err := s.fetchFromProxy(ctx, modulePath, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s@%s: %w", modulePath, query, err)
+	}
+
+	var info versionInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to decode version of %s@%s: %w", modulePath, query, err)
+	}
+	if info.Version == "" {
+		return "", fmt.Errorf("no version found for %s@%s", modulePath, query)
+	}
+	return info.Version, nil
+}
+
+// ResolveRemote resolves the version query of the target like ScanRemote
+// and returns the module as a dependency to scan together with all modules
+// its go.mod requires, including indirect ones
+func (s *Scanner) ResolveRemote(ctx context.Context, target string) (Dependency, []Dependency, error) {
+	goMod, version, err := s.fetchRemoteGoMod(ctx, target)
+	if err != nil {
+		return Dependency{}, nil, err
+	}
+	file, err := modfile.ParseLax("go.mod", goMod, nil)
+	if err != nil {
+		return Dependency{}, nil, fmt.Errorf("failed to parse go.mod of %s: %w", target, err)
+	}
+
+	modulePath, _, _ := strings.Cut(target, "@")
+	candidate := Dependency{Path: modulePath, Version: version, IsActive: true}
+	requirements := make([]Dependency, 0, len(file.Require))
+	for _, req := range file.Require {
+		requirements = append(requirements, Dependency{
+			Path:       req.Mod.Path,
+			Version:    req.Mod.Version,
+			IsActive:   true,
+			IsIndirect: req.Indirect,
+		})
+	}
+	return candidate, requirements, nil
+}
+
+// Versions returns the versions of the module listed by the proxy, the
+// newest first
+func (s *Scanner) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	versions, err := s.getVersionListFromProxy(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s: %w", modulePath, err)
+	}
+	semver.Sort(versions)
+	slices.Reverse(versions)
+	return versions, nil
+}
+
+// LatestVersion returns the version the proxy resolves @latest to for the
+// module, a pseudo-version for repositories without tags
+func (s *Scanner) LatestVersion(ctx context.Context, modulePath string) (string, error) {
+	return s.resolveVersionQuery(ctx, modulePath, "latest")
+}
@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const remotePseudoVersion = "v0.0.0-20240102030405-abcdef123456"
+
+// newRemoteModuleProxy serves github.com/example/app, whose main branch
+// resolves to a pseudo-version requiring github.com/example/mod
+func newRemoteModuleProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/example/app/@v/main.info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Version":%q,"Time":"2024-01-02T03:04:05Z"}`, remotePseudoVersion)
+	})
+	mux.HandleFunc("/github.com/example/app/@latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Version":"v1.0.0","Time":"2024-01-01T00:00:00Z"}`)
+	})
+	mux.HandleFunc("/github.com/example/app/@v/"+remotePseudoVersion+".mod", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "module github.com/example/app\n\nrequire github.com/example/mod v1.0.0\n")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestScanRemote(t *testing.T) {
+	remote := newRemoteModuleProxy(t)
+	defer remote.Close()
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", remote.URL+","+server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanRemote(context.Background(), "github.com/example/app@main")
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	assert.Equal(t, "github.com/example/app@"+remotePseudoVersion, result.ProjectPath)
+	require.Len(t, result.Dependencies, 1)
+	assert.Equal(t, "github.com/example/mod", result.Dependencies[0].Path)
+	require.NotNil(t, result.Fingerprint)
+	assert.Equal(t, "github.com/example/app", result.Fingerprint.Module)
+	assert.Equal(t, "abcdef123456", result.Fingerprint.Revision)
+}
+
+func TestResolveVersionQueryLatest(t *testing.T) {
+	remote := newRemoteModuleProxy(t)
+	defer remote.Close()
+	t.Setenv("GOPROXY", remote.URL)
+
+	version, err := NewScanner(".").resolveVersionQuery(context.Background(), "github.com/example/app", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", version)
+}
+
+func TestScanRemoteInvalidModule(t *testing.T) {
+	err := NewScanner(".").ScanRemote(context.Background(), "not a module")
+
+	assert.ErrorContains(t, err, "invalid remote module")
+}
+
+func TestResolveRemote(t *testing.T) {
+	remote := newRemoteModuleProxy(t)
+	defer remote.Close()
+	t.Setenv("GOPROXY", remote.URL)
+
+	candidate, requirements, err := NewScanner(".").ResolveRemote(context.Background(), "github.com/example/app@main")
+
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/example/app", candidate.Path)
+	assert.Equal(t, remotePseudoVersion, candidate.Version)
+	require.Len(t, requirements, 1)
+	assert.Equal(t, "github.com/example/mod", requirements[0].Path)
+}
+
+func TestVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/github.com/example/mod/@v/list" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "v1.2.0\nv1.10.0\nv0.9.0\nv1.10.1-rc.1\n")
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+	scanner := NewScanner(".")
+
+	versions, err := scanner.Versions(context.Background(), "github.com/example/mod")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.10.1-rc.1", "v1.10.0", "v1.2.0", "v0.9.0"}, versions)
+
+	_, err = scanner.Versions(context.Background(), "github.com/example/missing")
+	assert.ErrorContains(t, err, "failed to list versions of github.com/example/missing")
+}
@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"golang.org/x/mod/modfile"
+)
+
+// Replacement is the target of a replace directive in go.mod
+type Replacement struct {
+	Path string `json:"path"`
+	// Version is empty for local filesystem replacements
+	Version string `json:"version,omitempty"`
+}
+
+// IsLocal reports whether the replacement is a local directory, which has
+// no release history to check
+func (r *Replacement) IsLocal() bool {
+	return r != nil && (r.Version == "" || modfile.IsDirectoryPath(r.Path))
+}
+
+// lookupModule returns the module whose health is checked for the
+// dependency: the replacement target if it is replaced, else itself
+func (d *Dependency) lookupModule() (path, version string) {
+	if d.Replace != nil && !d.Replace.IsLocal() {
+		return d.Replace.Path, d.Replace.Version
+	}
+	return d.Path, d.Version
+}
+
+// requirementPath returns the module path of the requirement, also while
+// its replacement is looked up. Overrides and tiers are keyed by it.
+func (d *Dependency) requirementPath() string {
+	if d.requirement != "" {
+		return d.requirement
+	}
+	return d.Path
+}
+
+// scanKey identifies a dependency for deduplication. The same requirement
+// may be replaced differently by different workspace modules.
+func (d *Dependency) scanKey() string {
+	key := d.Path + "@" + d.Version
+	if d.Replace != nil {
+		key += "=>" + d.Replace.Path + "@" + d.Replace.Version
+	}
+	return key
+}
+
+// applyReplacements sets the replacement of every requirement matched by
+// a replace directive. A directive without old version applies to all
+// versions, a directive with version takes precedence.
+func applyReplacements(deps []Dependency, replaces []*modfile.Replace) {
+	for i := range deps {
+		var match *modfile.Replace
+		for _, replace := range replaces {
+			if replace.Old.Path != deps[i].Path {
+				continue
+			}
+			if replace.Old.Version == deps[i].Version {
+				match = replace
+				break
+			}
+			if replace.Old.Version == "" {
+				match = replace
+			}
+		}
+		if match != nil {
+			deps[i].Replace = &Replacement{Path: match.New.Path, Version: match.New.Version}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoModReplacements(t *testing.T) {
+	goMod := []byte(`module example.com/service
+
+go 1.21
+
+require (
+	github.com/example/forked v1.0.0
+	github.com/example/local v0.1.0
+	github.com/example/pinned v1.2.0
+	github.com/example/other-pin v1.3.0
+	github.com/example/plain v1.0.0
+)
+
+replace github.com/example/forked => github.com/fork/forked v1.0.1
+
+replace github.com/example/local => ../local
+
+replace github.com/example/pinned v1.2.0 => github.com/fork/pinned v1.2.1
+
+replace github.com/example/other-pin v1.2.0 => github.com/fork/other-pin v1.2.1
+`)
+
+	deps, err := NewScanner(".").ParseGoMod(goMod)
+
+	require.NoError(t, err)
+	require.Len(t, deps, 5)
+	assert.Equal(t, &Replacement{Path: "github.com/fork/forked", Version: "v1.0.1"}, deps[0].Replace)
+	assert.Equal(t, &Replacement{Path: "../local"}, deps[1].Replace)
+	assert.True(t, deps[1].Replace.IsLocal())
+	assert.Equal(t, &Replacement{Path: "github.com/fork/pinned", Version: "v1.2.1"}, deps[2].Replace)
+	assert.Nil(t, deps[3].Replace, "version specific replacement of another version")
+	assert.Nil(t, deps[4].Replace)
+}
+
+func TestLookupModule(t *testing.T) {
+	dep := Dependency{Path: "github.com/example/forked", Version: "v1.0.0"}
+	path, version := dep.lookupModule()
+	assert.Equal(t, "github.com/example/forked", path)
+	assert.Equal(t, "v1.0.0", version)
+
+	dep.Replace = &Replacement{Path: "github.com/fork/forked", Version: "v1.0.1"}
+	path, version = dep.lookupModule()
+	assert.Equal(t, "github.com/fork/forked", path)
+	assert.Equal(t, "v1.0.1", version)
+	original := Dependency{Path: dep.Path, Version: dep.Version}
+	assert.NotEqual(t, original.scanKey(), dep.scanKey())
+}
+
+func TestScanDependenciesReplacements(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 400, "v1.3.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		// The fake proxy only knows github.com/example/mod
+		{Path: "github.com/original/mod", Version: "v0.9.0", Replace: &Replacement{Path: "github.com/example/mod", Version: "v1.0.0"}},
+		{Path: "github.com/original/local", Version: "v0.1.0", Replace: &Replacement{Path: "./local"}},
+	})
+
+	require.NoError(t, err)
+	deps := scanner.GetResults().Dependencies
+	require.Len(t, deps, 2)
+
+	assert.Equal(t, "github.com/original/mod", deps[0].Path)
+	assert.Equal(t, "v0.9.0", deps[0].Version)
+	assert.Equal(t, "v1.3.0", deps[0].Update)
+	assert.Equal(t, 400, deps[0].DaysSinceLastRelease)
+	assert.Empty(t, deps[0].Note)
+
+	assert.Equal(t, "replaced by local directory ./local, not checked", deps[1].Note)
+	assert.True(t, deps[1].LastReleaseTime.IsZero())
+	assert.Empty(t, scanner.GetResults().Diagnostics)
+}
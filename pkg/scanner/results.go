@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveResults writes the result of the last scan as JSON to path, in the
+// format of --output json. The file is replaced atomically, so a failed
+// write never leaves a truncated snapshot.
+func (s *Scanner) SaveResults(path string) error {
+	if s.result == nil {
+		return fmt.Errorf("no scan results to save")
+	}
+	data, err := json.MarshalIndent(s.result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scan results: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save scan results: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to save scan results: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to save scan results: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to save scan results: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to save scan results: %w", err)
+	}
+	return nil
+}
+
+// LoadResults reads a scan result written with SaveResults or --output json
+func LoadResults(path string) (*ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan result: %w", err)
+	}
+	var result ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse scan result %s: %w", path, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	s := NewScanner(".")
+
+	assert.Error(t, (&Scanner{}).SaveResults(path))
+
+	s.result = &ScanResult{
+		ProjectPath: "/project",
+		Dependencies: []Dependency{{
+			Path:            "github.com/example/stale",
+			Version:         "v0.1.0",
+			Status:          StatusStale,
+			LastReleaseTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+		Summary: Summary{Total: 1, Inactive: 1},
+	}
+	require.NoError(t, s.SaveResults(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	loaded, err := LoadResults(path)
+	require.NoError(t, err)
+	assert.Equal(t, s.result, loaded)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestLoadResultsErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadResults(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("{"), 0o644))
+	_, err = LoadResults(invalid)
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Retraction marks a version retracted by the module author with a
+// retract directive
+type Retraction struct {
+	// Rationale is the comment of the retract directive, e.g. the bug
+	// which made the version unusable
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// String returns the rationale or a placeholder if the author gave none
+func (r *Retraction) String() string {
+	if r.Rationale == "" {
+		return "no rationale given"
+	}
+	return r.Rationale
+}
+
+// checkModuleStatus reads the go.mod of the latest version, which holds the
+// authoritative retract directives and deprecation comment like for the go
+// command. Failures are only logged, the status is just unknown then.
+func (s *Scanner) checkModuleStatus(ctx context.Context, dep *Dependency) {
+	escapedVersion, err := module.EscapeVersion(dep.Latest)
+	if err != nil {
+		return
+	}
+	data, err := s.fetchFromProxy(ctx, dep.Path, "@v/"+escapedVersion+".mod")
+	if err != nil {
+		logFailure(ctx, StageProxy, "Failed to get go.mod of the latest version", err, "latest", dep.Latest)
+		return
+	}
+	file, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		logFailure(ctx, StageProxy, "Failed to parse go.mod of the latest version", err, "latest", dep.Latest)
+		return
+	}
+
+	if file.Module != nil {
+		dep.Deprecated = file.Module.Deprecated
+	}
+	dep.Retracted = findRetraction(file.Retract, dep.Version)
+}
+
+// findRetraction returns the retraction covering the version, nil if the
+// version is not retracted
+func findRetraction(retracts []*modfile.Retract, version string) *Retraction {
+	if !semver.IsValid(version) {
+		return nil
+	}
+	for _, retract := range retracts {
+		if semver.Compare(version, retract.Low) >= 0 && semver.Compare(version, retract.High) <= 0 {
+			return &Retraction{Rationale: retract.Rationale}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestFindRetraction(t *testing.T) {
+	file, err := modfile.ParseLax("go.mod", []byte(`module example.com/mod
+
+retract (
+	v1.0.1 // Published by accident
+	[v1.1.0, v1.1.5]
+)
+`), nil)
+	require.NoError(t, err)
+
+	retraction := findRetraction(file.Retract, "v1.0.1")
+	require.NotNil(t, retraction)
+	assert.Equal(t, "Published by accident", retraction.String())
+
+	retraction = findRetraction(file.Retract, "v1.1.3")
+	require.NotNil(t, retraction)
+	assert.Equal(t, "no rationale given", retraction.String())
+
+	assert.Nil(t, findRetraction(file.Retract, "v1.0.0"))
+	assert.Nil(t, findRetraction(file.Retract, "v1.2.0"))
+	assert.Nil(t, findRetraction(file.Retract, "invalid"))
+}
+
+func TestScanDependenciesRetractedAndDeprecated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/mod/@v/list":
+			fmt.Fprintln(w, "v1.0.0")
+			fmt.Fprintln(w, "v1.1.0")
+		case "/example.com/mod/@v/v1.1.0.mod":
+			fmt.Fprint(w, "// Deprecated: use example.com/mod/v2 instead.\nmodule example.com/mod\n\nretract v1.0.0 // Data corruption\n")
+		case "/example.com/mod/@v/v1.0.0.info", "/example.com/mod/@v/v1.1.0.info":
+			fmt.Fprint(w, `{"Time":"2024-01-01T00:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "example.com/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	dep := result.Dependencies[0]
+	require.NotNil(t, dep.Retracted)
+	assert.Equal(t, "Data corruption", dep.Retracted.Rationale)
+	assert.Equal(t, "use example.com/mod/v2 instead.", dep.Deprecated)
+	assert.Equal(t, 1, result.Summary.Retracted)
+	assert.Equal(t, 1, result.Summary.Deprecated)
+}
@@ -2,73 +2,409 @@ package scanner
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"net/url"
-	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/internal/version"
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/detect"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/license"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/steffakasid/govital/pkg/score"
+	"github.com/steffakasid/govital/pkg/transport"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 )
 
 type Dependency struct {
-	Path                 string
-	Version              string
-	Update               string
-	Latest               string
-	Error                string
-	LastReleaseTime      time.Time
-	IsActive             bool
-	IsIndirect           bool
-	IsAcknowledged       bool
-	DaysSinceLastRelease int
+	Path                 string     `json:"path"`
+	Version              string     `json:"version"`
+	Update               string     `json:"update,omitempty"`
+	Latest               string     `json:"latest,omitempty"`
+	Error                *ScanError `json:"error,omitempty"`
+	LastReleaseTime      time.Time  `json:"last_release_time"`
+	IsActive             bool       `json:"is_active"`
+	IsIndirect           bool       `json:"is_indirect"`
+	IsAcknowledged       bool       `json:"is_acknowledged"`
+	DaysSinceLastRelease int        `json:"days_since_last_release"`
+	// Errors are all failed checks of the dependency in the order they
+	// occurred. Error is the one which left the maintenance status unknown.
+	Errors []ScanError `json:"errors,omitempty"`
+	// Status is the maintenance status of the scanned dependency. IsActive
+	// is kept for compatibility, it is only set if Status is active.
+	Status Status `json:"status,omitempty"`
+	// Module is the workspace module requiring this dependency. It is only
+	// set when scanning a go.work workspace.
+	Module string `json:"module,omitempty"`
+	// Vulnerabilities lists known advisories for the used version. It is
+	// only populated if vulnerability checks are enabled.
+	Vulnerabilities []vuln.Vulnerability `json:"vulnerabilities,omitempty"`
+	// LatestReleaseTime is the release time of the newest tagged version
+	LatestReleaseTime time.Time `json:"latest_release_time"`
+	// DaysSinceLatestRelease is the age of the newest tagged version, nil if
+	// LatestReleaseTime is unknown. Unlike DaysSinceLastRelease it ignores
+	// newer commits used as pseudo-versions.
+	DaysSinceLatestRelease *int `json:"days_since_latest_release,omitempty"`
+	// Unreleased is set if the Go proxy lists no tagged release of the
+	// module, only commits are used as pseudo-versions
+	Unreleased bool `json:"unreleased,omitempty"`
+	// ReleasesLastYear counts the tagged releases within the last 365 days.
+	// It is only meaningful if LatestReleaseTime is set.
+	ReleasesLastYear int `json:"releases_last_year"`
+	// Score is the composite health score from 0 to 100, nil if unknown
+	Score *int `json:"score,omitempty"`
+	// Owners are the teams responsible for the dependency according to the
+	// configured owner rules
+	Owners []string `json:"owners,omitempty"`
+	// Repository is the clone URL of the source repository, resolved via
+	// go-import meta tags for vanity import paths
+	Repository string `json:"repository,omitempty"`
+	// Replace is set if a replace directive overrides the dependency. The
+	// replacement target is checked instead of the original module.
+	Replace *Replacement `json:"replace,omitempty"`
+	// Note explains why a dependency was not checked, e.g. because it is
+	// replaced by a local directory, or the reason of its override
+	Note string `json:"note,omitempty"`
+	// Tier is the tier assigned by the overrides, empty for normal
+	// dependencies
+	Tier Tier `json:"tier,omitempty"`
+	// Licenses are the SPDX identifiers of the used version. They are only
+	// populated if license checks are enabled.
+	Licenses []string `json:"licenses,omitempty"`
+	// LicenseChange is set if the licenses differ from the baseline scan
+	LicenseChange *license.Change `json:"license_change,omitempty"`
+	// NewerMajorAvailable is the newest release of a following major
+	// version, which has its own module path, nil if there is none
+	NewerMajorAvailable *MajorUpgrade `json:"newer_major_available,omitempty"`
+	// Retracted is set if the module author retracted the used version
+	Retracted *Retraction `json:"retracted,omitempty"`
+	// Deprecated is the deprecation message of the module, empty if the
+	// module is not deprecated
+	Deprecated string `json:"deprecated,omitempty"`
+	// Archived is whether the source repository is archived, nil if unknown.
+	// It is only populated if repository checks are enabled.
+	Archived *bool `json:"archived,omitempty"`
+	// ContributorCount is the number of distinct commit authors of the
+	// source repository in the last 12 months, nil if unknown. It is only
+	// populated if repository checks are enabled.
+	ContributorCount *int `json:"contributor_count,omitempty"`
+	// BusFactorRisk is set if fewer contributors than required kept the
+	// source repository going in the last 12 months
+	BusFactorRisk bool `json:"bus_factor_risk,omitempty"`
+	// OpenIssues is the number of open issues of the source repository, on
+	// GitHub including pull requests, nil if unknown
+	OpenIssues *int `json:"open_issues,omitempty"`
+	// MedianResponseHours is the median time until someone other than the
+	// author responded to issues opened in the last 90 days, nil if unknown
+	// or if no issue was answered. It is only populated if responsiveness
+	// checks are enabled.
+	MedianResponseHours *int `json:"median_response_hours,omitempty"`
+	// SampledIssues is the number of issues opened in the last 90 days
+	// sampled for the response time, at most 10, AnsweredIssues how many of
+	// them someone other than the author responded to
+	SampledIssues  *int `json:"sampled_issues,omitempty"`
+	AnsweredIssues *int `json:"answered_issues,omitempty"`
+	// MergedPullRequests is the number of pull requests merged in the last
+	// 90 days, nil if unknown
+	MergedPullRequests *int `json:"merged_pull_requests,omitempty"`
+	// Unresponsive is set if the median response time exceeds the
+	// configured maximum, which marks the dependency inactive
+	Unresponsive bool `json:"unresponsive,omitempty"`
+	// Security holds the security practices of the source repository, nil
+	// if unknown. It is only populated if security signals are checked.
+	Security *SecuritySignals `json:"security,omitempty"`
+	// Upstream is the repository the source repository was forked from,
+	// nil if it is no fork or repository checks are disabled
+	Upstream *Upstream `json:"upstream,omitempty"`
+	// StaleFork is set if nobody pushed to the forked source repository
+	// within the stale threshold while its upstream is still active
+	StaleFork bool `json:"stale_fork,omitempty"`
+	// ReleaseNotes are the notes of the versions after the used one up to
+	// the available update, newest first, if release notes are fetched
+	ReleaseNotes []changelog.Note `json:"release_notes,omitempty"`
+	// Location is the declaration in go.mod, or in go.sum for modules not
+	// listed in go.mod, nil if unknown
+	Location *Location `json:"location,omitempty"`
+	// IntroducedBy is the shortest requirement chain pulling in an inactive
+	// indirect dependency, starting with the direct dependency to replace.
+	// It is only set when scanning a project with indirect dependencies.
+	IntroducedBy []string `json:"introduced_by,omitempty"`
+	// TransitiveRisk rolls up the indirect dependencies a direct
+	// dependency pulls in, nil for indirect dependencies and if indirect
+	// dependencies are not scanned
+	TransitiveRisk *TransitiveRisk `json:"transitive_risk,omitempty"`
+	// ImportedByPackages is the number of packages of the project importing
+	// a package of the dependency, directly or through other modules, nil
+	// if imports were not counted. Heavily imported inactive dependencies
+	// are the most expensive to replace and come first in reports.
+	ImportedByPackages *int `json:"imported_by_packages,omitempty"`
+	// GoVersion is the go directive of the go.mod of the used version,
+	// empty if unknown
+	GoVersion string `json:"go_version,omitempty"`
+	// Severity is the highest severity of the findings of the dependency,
+	// empty if it has none
+	Severity Severity `json:"severity,omitempty"`
+
+	// requirement is the module path of go.mod while the replacement is
+	// looked up under its own path
+	requirement string
+}
+
+// Summary aggregates the scan counters for a set of dependencies
+type Summary struct {
+	Total int `json:"total"`
+	// Updated counts dependencies already using the latest version
+	Updated int `json:"updated"`
+	// Outdated counts dependencies with a newer version available
+	Outdated int `json:"outdated"`
+	Errors   int `json:"errors"`
+	// ErrorsByCategory breaks Errors down by error category
+	ErrorsByCategory map[ErrorCategory]int `json:"errors_by_category,omitempty"`
+	Inactive         int                   `json:"inactive"`
+	// Unknown counts dependencies whose maintenance status is unknown
+	// without an error, e.g. local replacements
+	Unknown int `json:"unknown"`
+	// Vulnerable counts dependencies with at least one known vulnerability
+	Vulnerable int `json:"vulnerable"`
+	// Vulnerabilities counts all known vulnerabilities of all dependencies
+	Vulnerabilities int `json:"vulnerabilities"`
+	// LicenseChanges counts dependencies whose license changed since the
+	// baseline scan
+	LicenseChanges int `json:"license_changes"`
+	// NewerMajor counts dependencies with a newer major version under a
+	// different module path
+	NewerMajor int `json:"newer_major"`
+	// Retracted counts dependencies using a retracted version
+	Retracted int `json:"retracted"`
+	// Deprecated counts deprecated dependencies
+	Deprecated int `json:"deprecated"`
+	// BusFactorRisk counts dependencies with too few contributors
+	BusFactorRisk      int `json:"bus_factor_risk"`
+	StaleThresholdDays int `json:"stale_threshold_days"`
+	// Severities counts dependencies by the severity of their findings
+	Severities map[Severity]int `json:"severities,omitempty"`
+}
+
+// ModuleResult holds the per-module breakdown of a workspace or recursive
+// scan
+type ModuleResult struct {
+	Path    string  `json:"path"`
+	Dir     string  `json:"dir"`
+	Summary Summary `json:"summary"`
 }
 
 type ScanResult struct {
-	ProjectPath  string
-	Dependencies []Dependency
-	Summary      struct {
-		Total              int
-		Updated            int
-		Outdated           int
-		Errors             int
-		Inactive           int
-		StaleThresholdDays int
-	}
+	ProjectPath  string       `json:"project_path"`
+	Dependencies []Dependency `json:"dependencies"`
+	// Modules is only populated when a go.work workspace was scanned or
+	// the scan was recursive
+	Modules []ModuleResult `json:"modules,omitempty"`
+	Summary Summary        `json:"summary"`
+	// Diagnostics holds the warnings of the scan aggregated by message
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	// Fingerprint identifies the scanned source state, nil if unknown
+	Fingerprint *Fingerprint `json:"fingerprint,omitempty"`
+	// Conflicts lists dependencies required at different versions by the
+	// modules of a go.work workspace
+	Conflicts []VersionConflict `json:"conflicts,omitempty"`
+	// Quick is set for quick scans, which only check release times and
+	// are less reliable than a full scan
+	Quick bool `json:"quick,omitempty"`
+	// Recursive is set if all modules below the project path were scanned
+	Recursive bool `json:"recursive,omitempty"`
+	// Offline is set for scans which only read the local module cache.
+	// Update checks only saw the cached versions.
+	Offline bool `json:"offline,omitempty"`
+	// ToolchainFindings are the outdated and end of life Go versions of the
+	// project and its dependencies, if the toolchain was checked
+	ToolchainFindings []ToolchainFinding `json:"toolchain_findings,omitempty"`
 }
 
+const (
+	// defaultGitWorkers is the number of concurrent lookups of private
+	// modules, each of which runs go list and git
+	defaultGitWorkers = 2
+	// defaultForgeWorkers is the number of concurrent forge API lookups,
+	// which are rate limited per token
+	defaultForgeWorkers = 4
+	// autoMaxWorkers is the worker count and the per host concurrency
+	// ceiling with adaptive concurrency
+	autoMaxWorkers = 32
+	// autoMinConcurrency is the initial and lowest per host concurrency
+	// with adaptive concurrency
+	autoMinConcurrency = 2
+)
+
+// DefaultMinContributors is the number of contributors in the last 12
+// months below which a dependency is a bus factor risk, i.e. it depends on
+// a single maintainer
+const DefaultMinContributors = 2
+
 type Scanner struct {
 	projectPath                 string
 	result                      *ScanResult
 	staleThresholdDays          int
+	releaseThresholdDays        int
+	minContributors             int
+	maxResponseDays             int
 	includeIndirectDependencies bool
 	workers                     int
+	httpClient                  *http.Client
 	resultMutex                 *sync.Mutex
 	acknowledgedDependencies    map[string]bool
+	// overrides adjust the checks per module path
+	overrides   map[string]Override
+	warnings    *warningCollector
+	vulnClient  *vuln.Client
+	scoreEngine *score.Engine
+	// ignorePatterns exclude all matching modules from the scan
+	ignorePatterns []string
+	// tierPolicies hold the thresholds of the tiers the overrides assign
+	tierPolicies map[Tier]TierPolicy
+	// limiter adapts the request concurrency per host if set
+	limiter *transport.AdaptiveLimiter
+	// privacy decides which modules are fetched directly and kept from
+	// public services, it is read from go env on first use
+	privacy     privacy
+	privacyOnce sync.Once
+	// rateLimiter keeps the request rate to public hosts at a courtesy level
+	rateLimiter *transport.RateLimiter
+	// retry retries temporary failures, breaker fails requests to hosts
+	// which keep failing fast
+	retry         *transport.Retry
+	breaker       *transport.CircuitBreaker
+	owners        *owners.Matcher
+	resolver      *repo.Resolver
+	licenseClient *license.Client
+	// forge looks up repository metadata on the supported forges if set
+	forge *forge.Client
+	// checkResponsiveness measures how maintainers react to issues and
+	// pull requests, which needs the forge
+	checkResponsiveness bool
+	// fetchReleaseNotes collects the release notes of outdated
+	// dependencies, which needs the forge
+	fetchReleaseNotes bool
+	// checkSecuritySignals looks up the security practices of the source
+	// repositories, which needs the forge
+	checkSecuritySignals bool
+	// countImports counts the packages of the project importing each
+	// dependency with go list
+	countImports bool
+	// checkToolchain checks the declared Go versions against the releases
+	// listed at goReleasesURL, which tests replace
+	checkToolchain bool
+	goReleasesURL  string
+	// baseline is a previous scan of the project to detect license changes
+	baseline            map[string]Dependency
+	baselineFingerprint *Fingerprint
+	// pulledIn lists the indirect dependencies each direct dependency of
+	// the scanned modules pulls in, from go mod graph
+	pulledIn map[moduleDependency][]string
+	// reusable are the dependencies of a previous scan by scan key, which
+	// incremental scans don't look up again
+	reusable map[string]Dependency
+	// progress is notified after each scanned dependency if set
+	progress ProgressFunc
+	// onDependencyScanned receives each dependency as soon as it is scanned
+	onDependencyScanned func(Dependency)
+	// quick skips all lookups except the release time of the used version
+	quick bool
+	// recursive scans all modules below the project path
+	recursive bool
+	// offline reads release times from the module cache without any
+	// network access
+	offline bool
+	// modCache is the module download cache, read from go env on first use
+	modCache     string
+	modCacheOnce sync.Once
+	// now is the reference time for release ages
+	now func() time.Time
+	// gitWorkers and forgeWorkers are the pool sizes for private modules
+	// fetched with git and for forge API calls, workers the one for proxy
+	// lookups
+	gitWorkers   int
+	forgeWorkers int
+	// severities classifies the findings of the dependencies
+	severities SeverityConfig
+	// timeout bounds each HTTP request, gitTimeout each fetch of a private
+	// module from its repository and dependencyTimeout all lookups of a
+	// dependency
+	timeout           *transport.Timeout
+	gitTimeout        time.Duration
+	dependencyTimeout time.Duration
+	// directBatch answers the lookups of private modules fetched directly
+	// from a single go list run at the start of the scan if set
+	directBatch *directBatch
+	// executor runs the go and git commands, files reads the project files
+	// and the module cache and git, if set, reads tags instead of git
+	executor CommandExecutor
+	files    FileReader
+	git      GitClient
 }
 
+// ProgressFunc is called after each scanned dependency with the number of
+// scanned and total dependencies and the module scanned last. Calls are
+// serialized.
+type ProgressFunc func(done, total int, current string)
+
 func NewScanner(projectPath string) *Scanner {
 	result := &ScanResult{
 		ProjectPath:  projectPath,
 		Dependencies: make([]Dependency, 0),
+		Diagnostics:  make([]Diagnostic, 0),
 	}
 	result.Summary.StaleThresholdDays = 180 // Set default threshold in result
+	// Every retry is paced by the rate limiter, the circuit breaker only
+	// sees requests which failed after all retries
+	// The rate limiter's wait doesn't count against the request timeout
+	timeout := transport.NewTimeout(nil)
+	rateLimiter := transport.NewRateLimiter(timeout)
+	retry := transport.NewRetry(rateLimiter)
+	breaker := transport.NewCircuitBreaker(retry)
+	// Private proxies and go-get lookups authenticate with .netrc logins
+	httpClient := &http.Client{Transport: transport.NewUserAgent(transport.LoadNetrc(breaker), version.UserAgent())}
 
 	return &Scanner{
 		projectPath:                 projectPath,
 		staleThresholdDays:          180,
+		tierPolicies:                DefaultTierPolicies(),
 		includeIndirectDependencies: false,
 		workers:                     4,
+		gitWorkers:                  defaultGitWorkers,
+		forgeWorkers:                defaultForgeWorkers,
+		minContributors:             DefaultMinContributors,
+		httpClient:                  httpClient,
+		rateLimiter:                 rateLimiter,
+		retry:                       retry,
+		breaker:                     breaker,
+		resolver:                    repo.NewResolver(httpClient),
 		resultMutex:                 &sync.Mutex{},
 		result:                      result,
 		acknowledgedDependencies:    make(map[string]bool),
+		warnings:                    newWarningCollector(),
+		scoreEngine:                 score.NewEngine(score.DefaultWeights()),
+		severities:                  DefaultSeverityConfig(),
+		timeout:                     timeout,
+		gitTimeout:                  DefaultGitTimeout,
+		dependencyTimeout:           DefaultDependencyTimeout,
+		now:                         time.Now,
+		executor:                    DefaultCommandExecutor{},
+		files:                       DefaultFileReader{},
 	}
 }
 
@@ -101,6 +437,33 @@ func (s *Scanner) SetWorkers(count int) {
 		count = 1
 	}
 	s.workers = count
+	if s.limiter != nil {
+		s.httpClient.Transport = s.limiter.Unwrap()
+		s.limiter = nil
+	}
+}
+
+// SetGitWorkers sets the number of private modules looked up concurrently
+// with go list and git. Default: 2
+func (s *Scanner) SetGitWorkers(count int) {
+	s.gitWorkers = max(count, 1)
+}
+
+// SetForgeWorkers sets the number of repositories looked up concurrently
+// on their forge. Default: 4
+func (s *Scanner) SetForgeWorkers(count int) {
+	s.forgeWorkers = max(count, 1)
+}
+
+// SetAutoWorkers replaces the fixed worker count by per host concurrency
+// limits, which start low and adapt to the observed latency and rate-limit
+// responses of the proxies
+func (s *Scanner) SetAutoWorkers() {
+	if s.limiter == nil {
+		s.limiter = transport.NewAdaptiveLimiter(s.httpClient.Transport, autoMinConcurrency, autoMaxWorkers)
+		s.httpClient.Transport = s.limiter
+	}
+	s.workers = autoMaxWorkers
 }
 
 func (s *Scanner) SetStaleThreshold(days int) {
@@ -108,10 +471,131 @@ func (s *Scanner) SetStaleThreshold(days int) {
 	s.result.Summary.StaleThresholdDays = days
 }
 
+// SetReleaseThreshold marks dependencies inactive whose newest tagged
+// release is older than days, even if newer commits are used. Modules
+// without any tagged release are inactive as well. 0 disables the check.
+func (s *Scanner) SetReleaseThreshold(days int) {
+	s.releaseThresholdDays = days
+}
+
+// SetMinContributors flags dependencies whose source repository had fewer
+// distinct commit authors in the last 12 months as bus factor risk. 0
+// disables the flag, the contributors are still counted.
+func (s *Scanner) SetMinContributors(contributors int) {
+	s.minContributors = contributors
+}
+
+// SetMaxResponseDays marks dependencies inactive whose maintainers took
+// longer than days in median to respond to recent issues. It needs
+// responsiveness checks, 0 disables the check.
+func (s *Scanner) SetMaxResponseDays(days int) {
+	s.maxResponseDays = days
+}
+
 func (s *Scanner) SetIncludeIndirectDependencies(include bool) {
 	s.includeIndirectDependencies = include
 }
 
+// SetCheckVulnerabilities enables looking up known vulnerabilities of the
+// used versions in the OSV database
+func (s *Scanner) SetCheckVulnerabilities(check bool) {
+	if check {
+		s.vulnClient = vuln.NewClient()
+		s.vulnClient.HTTPClient = s.httpClient
+	} else {
+		s.vulnClient = nil
+	}
+}
+
+// SetCheckLicenses enables looking up the licenses of the used versions
+// on deps.dev
+func (s *Scanner) SetCheckLicenses(check bool) {
+	if check {
+		s.licenseClient = license.NewClient()
+		s.licenseClient.HTTPClient = s.httpClient
+	} else {
+		s.licenseClient = nil
+	}
+}
+
+// SetCheckResponsiveness enables measuring the median response time to
+// recent issues and counting recently merged pull requests. It costs up to a
+// dozen forge requests per dependency and only applies if repository checks
+// are enabled.
+func (s *Scanner) SetCheckResponsiveness(check bool) {
+	s.checkResponsiveness = check
+}
+
+// SetCheckRepositories enables looking up the metadata of the source
+// repositories on the supported forges, authenticated with the tokens of config
+func (s *Scanner) SetCheckRepositories(check bool, config forge.Config) {
+	if check {
+		s.forge = forge.NewClient(s.httpClient, config)
+	} else {
+		s.forge = nil
+	}
+}
+
+// SetClock sets the reference time source for release ages, e.g. the time
+// of a recorded scan which is replayed
+func (s *Scanner) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// WrapTransport wraps the transport shared by all HTTP clients of the
+// scanner, e.g. to record or replay the responses. Call it after the worker
+// configuration, which replaces the transport.
+func (s *Scanner) WrapTransport(wrap func(next http.RoundTripper) http.RoundTripper) {
+	s.httpClient.Transport = wrap(s.httpClient.Transport)
+}
+
+// SetProgress sets a function to report the scan progress to
+func (s *Scanner) SetProgress(progress ProgressFunc) {
+	s.progress = progress
+}
+
+// OnDependencyScanned sets a function receiving each dependency as soon as
+// it is scanned, so results can be shown before the whole scan completes.
+// Dependencies arrive in the order their lookups finish, GetResults keeps
+// the go list order. Calls are serialized, dependencies shared by several
+// workspace modules are passed once per module. Nothing is passed for
+// dependencies interrupted by a cancelled scan.
+func (s *Scanner) OnDependencyScanned(callback func(Dependency)) {
+	s.onDependencyScanned = callback
+}
+
+// SetBaseline sets a previous scan result of the project. Dependencies
+// whose licenses differ from the baseline get a LicenseChange.
+func (s *Scanner) SetBaseline(baseline *ScanResult) {
+	s.baseline = make(map[string]Dependency, len(baseline.Dependencies))
+	for _, dep := range baseline.Dependencies {
+		s.baseline[dep.Path] = dep
+	}
+	s.baselineFingerprint = baseline.Fingerprint
+}
+
+// SetRateLimits overrides the request rate of hosts. Public hosts like
+// proxy.golang.org are limited to transport.DefaultPublicRate by default,
+// other hosts are not limited.
+func (s *Scanner) SetRateLimits(limits []transport.HostLimit) {
+	for _, limit := range limits {
+		s.rateLimiter.SetLimit(limit.Host, limit.RequestsPerSecond)
+	}
+}
+
+// SetRetries configures the retries of failed requests and the circuit
+// breaker shared by all HTTP clients of the scanner. By default requests
+// are retried transport.DefaultMaxRetries times.
+func (s *Scanner) SetRetries(config transport.RetryConfig) {
+	s.retry.SetMaxRetries(config.MaxRetries)
+	s.breaker.Configure(config.BreakerThreshold, config.BreakerCooldown)
+}
+
+// SetOwners sets the rules annotating dependencies with their owners
+func (s *Scanner) SetOwners(matcher *owners.Matcher) {
+	s.owners = matcher
+}
+
 func (s *Scanner) SetAcknowledgedDependencies(deps []string) {
 	s.acknowledgedDependencies = make(map[string]bool)
 	for _, dep := range deps {
@@ -119,43 +603,268 @@ func (s *Scanner) SetAcknowledgedDependencies(deps []string) {
 	}
 }
 
-func (s *Scanner) Scan() error {
-	// Check if go.mod exists
-	goModPath := filepath.Join(s.projectPath, "go.mod")
-	if _, err := os.Stat(goModPath); err != nil {
-		eslog.Errorf("go.mod not found at %s", goModPath)
+// Scan lists and checks all dependencies of the project. The scan is aborted
+// when ctx is cancelled or its deadline is exceeded.
+func (s *Scanner) Scan(ctx context.Context) error {
+	start := time.Now()
+	modules, err := s.discoverModules()
+	if err != nil {
+		logger(ctx).Error("Failed to find the modules", slog.Any(logKeyError, err))
+		return err
+	}
+	isWorkspace := len(modules) > 0
+	// No single manifest identifies the modules of a recursive scan
+	if !s.recursive {
+		s.result.Fingerprint = s.fingerprintProject(ctx, isWorkspace)
+	}
+	if !s.baselineFingerprint.SameProject(s.result.Fingerprint) {
+		logger(ctx).Warn("Ignoring the baseline of another project", slog.String("baseline", s.baselineFingerprint.Module), slog.String("project", s.result.Fingerprint.Module))
+		s.baseline = nil
+	}
+
+	var depsToScan []Dependency
+	if isWorkspace {
+		for _, mod := range modules {
+			deps, err := s.listDependencies(ctx, mod.Dir, true)
+			if err != nil {
+				return err
+			}
+			for i := range deps {
+				deps[i].Module = mod.Path
+			}
+			s.setLocations(deps, mod.Dir)
+			if s.includeIndirectDependencies && !s.quick {
+				s.setIntroducedBy(ctx, deps, mod.Dir, true)
+			}
+			if s.countImports && !s.quick {
+				s.setImportedBy(ctx, deps, mod.Dir, true)
+			}
+			depsToScan = append(depsToScan, deps...)
+			s.result.Modules = append(s.result.Modules, ModuleResult{
+				Path:    mod.Path,
+				Dir:     mod.Dir,
+				Summary: Summary{StaleThresholdDays: s.staleThresholdDays},
+			})
+		}
+	} else {
+		depsToScan, err = s.listDependencies(ctx, s.projectPath, false)
+		if err != nil {
+			return err
+		}
+		s.setLocations(depsToScan, s.projectPath)
+		if s.includeIndirectDependencies && !s.quick {
+			s.setIntroducedBy(ctx, depsToScan, s.projectPath, false)
+		}
+		if s.countImports && !s.quick {
+			s.setImportedBy(ctx, depsToScan, s.projectPath, false)
+		}
+	}
+
+	if s.checkToolchain {
+		dirs := []string{s.projectPath}
+		if isWorkspace {
+			dirs = dirs[:0]
+			for _, mod := range modules {
+				dirs = append(dirs, mod.Dir)
+			}
+		}
+		var projects []goModDirectives
+		for _, dir := range dirs {
+			directives, err := readGoModDirectives(s.files, dir)
+			if err != nil {
+				logger(ctx).Warn("Skipping the toolchain check", slog.String("dir", dir), slog.Any(logKeyError, err))
+				continue
+			}
+			projects = append(projects, directives)
+		}
+		s.setToolchainFindings(ctx, projects, depsToScan)
+	}
+
+	if err := s.ScanDependencies(ctx, depsToScan); err != nil {
+		logger(ctx).Error("Scan aborted", slog.Any(logKeyError, err))
+		return err
+	}
+
+	if isWorkspace {
+		s.result.Conflicts = findConflicts(s.result.Dependencies)
+		s.logScanned(ctx, start, slog.Int("modules", len(modules)), slog.Int("conflicts", len(s.result.Conflicts)))
+	} else {
+		s.logScanned(ctx, start)
+	}
+	return nil
+}
+
+// concurrencyDescription describes the worker setup for log messages
+func (s *Scanner) concurrencyDescription() string {
+	if s.limiter == nil {
+		return fmt.Sprintf("%d proxy, %d git and %d forge workers", s.workers, s.gitWorkers, s.forgeWorkers)
+	}
+
+	limits := s.limiter.Limits()
+	hosts := make([]string, 0, len(limits))
+	for host, limit := range limits {
+		hosts = append(hosts, fmt.Sprintf("%s: %d", host, limit))
+	}
+	sort.Strings(hosts)
+	return fmt.Sprintf("adaptive concurrency, final limits %s", strings.Join(hosts, ", "))
+}
+
+// ScanDependencies checks the maintenance status of an explicit list of
+// dependencies without running go list, e.g. for a go.mod read from git.
+func (s *Scanner) ScanDependencies(ctx context.Context, deps []Dependency) error {
+	// Scan dependencies in parallel
+	err := s.scanParallel(ctx, deps)
+	s.result.Diagnostics = append(s.result.Diagnostics, s.warnings.flush()...)
+	if err != nil {
+		return err
+	}
+	s.setTransitiveRisk()
+	s.result.Summary.StaleThresholdDays = s.staleThresholdDays
+	return nil
+}
+
+// ParseGoMod returns the required modules of the given go.mod content.
+// Indirect requirements are only returned if indirect dependencies are included.
+func (s *Scanner) ParseGoMod(data []byte) ([]Dependency, error) {
+	// Parse rather than ParseLax, which ignores replace directives
+	file, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	var deps []Dependency
+	for _, req := range file.Require {
+		if !s.includeIndirectDependencies && req.Indirect {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Path:       req.Mod.Path,
+			Version:    req.Mod.Version,
+			IsActive:   true,
+			IsIndirect: req.Indirect,
+		})
+	}
+	applyReplacements(deps, file.Replace)
+	return deps, nil
+}
+
+// workspaceModule is a module referenced by a use directive in go.work or
+// found by a recursive scan
+type workspaceModule struct {
+	Path string
+	Dir  string
+}
+
+// discoverModules returns the member modules if the project path contains a
+// go.work file, or all nested modules for recursive scans. It returns no
+// modules for a plain single-module project.
+func (s *Scanner) discoverModules() ([]workspaceModule, error) {
+	if s.recursive {
+		return s.discoverNestedModules()
+	}
+
+	goWorkPath := filepath.Join(s.projectPath, "go.work")
+	data, err := s.files.ReadFile(goWorkPath)
+	if err != nil {
+		goModPath := filepath.Join(s.projectPath, "go.mod")
+		if _, err := s.files.Stat(goModPath); err != nil {
+			return nil, notScannable(s.projectPath, goModPath)
+		}
+		return nil, nil
+	}
+
+	work, err := modfile.ParseWork(goWorkPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", goWorkPath, err)
+	}
+
+	var modules []workspaceModule
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(s.projectPath, dir)
+		}
+		goModPath := filepath.Join(dir, "go.mod")
+		goMod, err := s.files.ReadFile(goModPath)
+		if err != nil {
+			eslog.Logger.Warn("Skipping workspace module", slog.String("dir", use.Path), slog.Any(logKeyError, err))
+			continue
+		}
+		modulePath := modfile.ModulePath(goMod)
+		if modulePath == "" {
+			eslog.Logger.Warn("Skipping workspace module without module directive", slog.String("dir", use.Path), slog.String("file", goModPath))
+			continue
+		}
+		modules = append(modules, workspaceModule{Path: modulePath, Dir: dir})
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no usable modules found in %s", goWorkPath)
+	}
+	return modules, nil
+}
+
+// notScannable returns the error for a project without go.mod, explaining
+// what the directory contains instead
+func notScannable(projectPath, goModPath string) error {
+	project, err := detect.Detect(projectPath)
+	if err != nil || len(project.Hints()) == 0 {
 		return fmt.Errorf("go.mod not found at %s", goModPath)
 	}
+	return fmt.Errorf("go.mod not found at %s: %s", goModPath, strings.Join(project.Hints(), "; "))
+}
 
-	// Get all dependencies with go list
-	cmd := exec.Command("go", "list", "-json", "-m", "all")
-	cmd.Dir = s.projectPath
+// listDependencies runs go list in dir and returns the dependencies to scan.
+// Workspace members are listed with GOWORK=off so that each module reports
+// its own requirements instead of the combined workspace build list.
+func (s *Scanner) listDependencies(ctx context.Context, dir string, workspaceMember bool) ([]Dependency, error) {
+	if s.quick {
+		return s.readGoMod(dir)
+	}
 
-	output, err := cmd.Output()
+	args := []string{"list", "-json", "-m"}
+	if detect.IsVendored(dir) {
+		// go list can't compute all from the vendor directory
+		args = append(args, "-mod=mod")
+	}
+	output, err := s.runGo(ctx, dir, workspaceMember, append(args, "all")...)
 	if err != nil {
-		eslog.Errorf("Failed to list dependencies (go list -json -m all): %v", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to list dependencies: %w", ctx.Err())
+		}
+		if s.offline {
+			// The module graph needs the go.mod of every dependency, which
+			// may be missing from the cache
+			logger(ctx).Warn("Failed to list dependencies offline, scanning the requirements of go.mod", slog.String("dir", dir), slog.Any(logKeyError, err))
+			return s.readGoMod(dir)
+		}
+		args := []any{slog.String("dir", dir), slog.Any(logKeyError, err)}
 		if len(output) > 0 {
-			eslog.Errorf("go list output: %s", string(output))
+			args = append(args, slog.String("output", string(output)))
 		}
-		eslog.Error()
-		return fmt.Errorf("failed to list dependencies: %w", err)
+		logger(ctx).Error("Failed to list dependencies (go list -json -m all)", args...)
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
 	}
 
-	// Collect dependencies to scan
-	var depsToScan []Dependency
+	var deps []Dependency
 	decoder := json.NewDecoder(bytes.NewReader(output))
 	for decoder.More() {
 		var dep struct {
-			Path     string
-			Version  string
-			Main     bool
-			Indirect bool
+			Path      string
+			Version   string
+			Main      bool
+			Indirect  bool
+			GoVersion string
+			Replace   *struct {
+				Path    string
+				Version string
+			}
 		}
 
 		if err := decoder.Decode(&dep); err != nil {
-			eslog.Errorf("Failed to decode dependency: %v", err)
-			s.result.Summary.Errors++
-			continue
+			logger(ctx).Error("Failed to decode dependency", slog.Any(logKeyError, err))
+			s.result.Summary.addError(ErrorParse)
+			break
 		}
 
 		if dep.Main {
@@ -167,347 +876,723 @@ func (s *Scanner) Scan() error {
 			continue
 		}
 
-		depsToScan = append(depsToScan, Dependency{
+		scanned := Dependency{
 			Path:       dep.Path,
 			Version:    dep.Version,
 			IsActive:   true,
 			IsIndirect: dep.Indirect,
-		})
+			GoVersion:  dep.GoVersion,
+		}
+		if dep.Replace != nil {
+			scanned.Replace = &Replacement{Path: dep.Replace.Path, Version: dep.Replace.Version}
+		}
+		deps = append(deps, scanned)
 	}
+	return deps, nil
+}
 
-	// Scan dependencies in parallel
-	s.scanParallel(depsToScan)
+// scanJob is a dependency passing through the stages of the scan pipeline
+type scanJob struct {
+	dep *Dependency
+	// target is the dependency with the module path and version of its
+	// replacement, which is looked up instead
+	target Dependency
+	// repository is set once the source repository is resolved
+	repository repo.Repository
+	// log carries the module and trace ID of the dependency
+	log *slog.Logger
+	// started is when the lookups of the dependency started, deadline when
+	// they are cut off, zero without a per dependency timeout
+	started  time.Time
+	deadline time.Time
+}
 
-	s.result.Summary.StaleThresholdDays = s.staleThresholdDays
-	eslog.Infof("Dependencies found: %d (scanned with %d workers)", s.result.Summary.Total, s.workers)
+// scanParallel scans dependencies in a pipeline of worker pools. Version
+// lookups go to the proxy pool, or to the git pool for private modules
+// fetched directly from their repository, since each of those runs git.
+// Dependencies with a resolved source repository continue to the forge
+// pool, whose smaller size keeps the forge APIs from rate limiting the
+// scan. Slow git and forge calls this way don't block the cheap proxy
+// lookups of other dependencies.
+// Dependencies ignored by an override are dropped.
+// Dependencies shared by several workspace modules are only looked up once.
+// Reusable dependencies of a previous scan are not looked up at all.
+// On cancellation the workers drain the queues without further lookups and
+// no results are recorded.
+func (s *Scanner) scanParallel(ctx context.Context, depsToScan []Dependency) error {
+	depsToScan = s.withoutIgnored(depsToScan)
+	unique := make(map[string]*Dependency)
+	// entries maps the scan keys to the indexes of their dependencies
+	entries := make(map[string][]int)
+	var queue []*Dependency
+	for i := range depsToScan {
+		key := depsToScan[i].scanKey()
+		entries[key] = append(entries[key], i)
+		if _, ok := unique[key]; !ok {
+			dep := depsToScan[i]
+			unique[key] = &dep
+			queue = append(queue, &dep)
+		}
+	}
+
+	// Vulnerabilities are looked up in a single batch up front, so each
+	// dependency is complete once its last stage is done
+	if s.vulnClient != nil && !s.quick && !s.offline {
+		s.checkVulnerabilities(ctx, queue)
+	}
+	queue, reused := s.reuseDependencies(queue)
+	s.prefetchDirect(ctx, queue)
+
+	notify := func(dep *Dependency) {
+		if s.onDependencyScanned != nil && ctx.Err() == nil {
+			for _, i := range entries[dep.scanKey()] {
+				s.onDependencyScanned(s.finishDependency(*dep, depsToScan[i]))
+			}
+		}
+	}
+	for _, dep := range reused {
+		notify(dep)
+	}
+
+	var progressMutex sync.Mutex
+	done := 0
+	s.reportProgress(done, len(queue), "")
+	complete := func(job *scanJob) {
+		s.completeDependency(job)
+
+		progressMutex.Lock()
+		defer progressMutex.Unlock()
+		done++
+		s.reportProgress(done, len(queue), job.dep.Path)
+		notify(job.dep)
+	}
+
+	// The queues hold all dependencies, so no stage blocks on a busy one
+	proxyChan := make(chan *scanJob, len(queue))
+	gitChan := make(chan *scanJob, len(queue))
+	forgeChan := make(chan *scanJob, len(queue))
+
+	var lookups, forgeLookups sync.WaitGroup
+	lookup := func(jobs <-chan *scanJob) {
+		for job := range jobs {
+			if ctx.Err() != nil {
+				continue
+			}
+			job.started = time.Now()
+			if s.dependencyTimeout > 0 {
+				job.deadline = job.started.Add(s.dependencyTimeout)
+			}
+			depCtx, cancel := s.dependencyContext(ctx, job)
+			forgeLookups := s.lookupDependency(depCtx, job)
+			timedOut := s.checkDependencyTimeout(ctx, depCtx, &job.target, s.lookupStage(ctx, job.target.Path))
+			cancel()
+			if forgeLookups && !timedOut {
+				forgeChan <- job
+				continue
+			}
+			complete(job)
+		}
+	}
+	startPool(&lookups, s.workers, func() { lookup(proxyChan) })
+	startPool(&lookups, s.gitWorkers, func() { lookup(gitChan) })
+	startPool(&forgeLookups, s.forgeWorkers, func() {
+		for job := range forgeChan {
+			if ctx.Err() != nil {
+				continue
+			}
+			depCtx, cancel := s.dependencyContext(ctx, job)
+			s.checkForge(depCtx, &job.target, job.repository)
+			s.checkDependencyTimeout(ctx, depCtx, &job.target, StageForge)
+			cancel()
+			complete(job)
+		}
+	})
+
+	for _, dep := range queue {
+		job := &scanJob{dep: dep, log: dependencyLogger(dep)}
+		if path, _ := dep.lookupModule(); !dep.Replace.IsLocal() && s.lookupStage(ctx, path) == StageGit {
+			gitChan <- job
+		} else {
+			proxyChan <- job
+		}
+	}
+	close(proxyChan)
+	close(gitChan)
+	lookups.Wait()
+	close(forgeChan)
+	forgeLookups.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("scan aborted: %w", err)
+	}
+
+	// Collect results in go list order
+	for i := range depsToScan {
+		s.addResult(s.finishDependency(*unique[depsToScan[i].scanKey()], depsToScan[i]))
+	}
 	return nil
 }
 
-// scanParallel scans dependencies in parallel using worker goroutines
-func (s *Scanner) scanParallel(depsToScan []Dependency) {
-	var wg sync.WaitGroup
-	depChan := make(chan *Dependency, len(depsToScan))
-
-	// Start worker goroutines
-	for i := 0; i < s.workers; i++ {
+// startPool starts count workers running work
+func startPool(wg *sync.WaitGroup, count int, work func()) {
+	for i := 0; i < count; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for dep := range depChan {
-				// Check if dependency is acknowledged
-				if s.acknowledgedDependencies[dep.Path] {
-					dep.IsAcknowledged = true
-				}
-
-				// Check maintenance status
-				if err := s.checkMaintenanceStatus(dep); err != nil {
-					eslog.Debugf("Failed to check maintenance status for %s: %v", dep.Path, err)
-				}
-
-				// Append result safely
-				s.resultMutex.Lock()
-				s.result.Dependencies = append(s.result.Dependencies, *dep)
-				s.result.Summary.Total++
-				if !dep.IsActive && !dep.IsAcknowledged {
-					s.result.Summary.Inactive++
-				}
-				if dep.Update != "" {
-					s.result.Summary.Updated++
-				}
-				s.resultMutex.Unlock()
-			}
+			work()
 		}()
 	}
+}
 
-	// Send dependencies to be scanned
-	for i := range depsToScan {
-		depChan <- &depsToScan[i]
+// finishDependency completes the shared lookup results of a dependency for
+// the entry of a single workspace module
+func (s *Scanner) finishDependency(scanned, entry Dependency) Dependency {
+	scanned.Module = entry.Module
+	scanned.IsIndirect = entry.IsIndirect
+	scanned.IntroducedBy = nil
+	scanned.Status = scanned.maintenanceStatus()
+	scanned.IsActive = scanned.Status == StatusActive
+	if scanned.IsInactive() {
+		scanned.IntroducedBy = entry.IntroducedBy
+	}
+	scanned.Owners = s.owners.Owners(scanned.Path)
+	s.applyOverrideReason(&scanned)
+	if previous, ok := s.baseline[scanned.Path]; ok {
+		scanned.LicenseChange = license.Compare(previous.Licenses, scanned.Licenses)
 	}
-	close(depChan)
+	scanned.Severity = s.severities.Classify(scanned)
+	return scanned
+}
 
-	// Wait for all workers to finish
-	wg.Wait()
+func (s *Scanner) reportProgress(done, total int, current string) {
+	if s.progress != nil {
+		s.progress(done, total, current)
+	}
 }
 
-func (s *Scanner) checkMaintenanceStatus(dep *Dependency) error {
-	// Get version info from Go proxy
-	commitTime, err := s.getVersionInfoFromProxy(dep.Path, dep.Version)
-	if err != nil {
-		eslog.Warnf("Failed to get version info for %s@%s from proxy: %v", dep.Path, dep.Version, err)
-		dep.IsActive = true // Assume active if we can't check
-		return nil
+// lookupDependency runs the version, repository and license lookups of the
+// dependency. It reports whether the forge lookups of the resolved source
+// repository are still to be done.
+func (s *Scanner) lookupDependency(ctx context.Context, job *scanJob) bool {
+	dep := job.dep
+	// Check if dependency is acknowledged
+	if s.acknowledgedDependencies[dep.Path] {
+		dep.IsAcknowledged = true
 	}
+	job.target = *dep
 
-	dep.LastReleaseTime = commitTime
-	daysSinceRelease := int(time.Since(dep.LastReleaseTime).Hours() / 24)
-	dep.DaysSinceLastRelease = daysSinceRelease
+	if dep.Replace.IsLocal() {
+		job.target.Note = fmt.Sprintf("replaced by local directory %s, not checked", dep.Replace.Path)
+		return false
+	}
 
-	if s.isStale(daysSinceRelease) {
-		dep.IsActive = false
+	target := &job.target
+	target.requirement = dep.Path
+	target.Path, target.Version = dep.lookupModule()
+
+	start := time.Now()
+	if s.quick || s.offline {
+		if s.offline {
+			s.checkCachedReleaseTime(ctx, target)
+		} else {
+			s.checkReleaseTime(ctx, target)
+		}
+		logStage(ctx, StageProxy, start)
+		return false
 	}
 
-	// Get latest version
-	latestVersion, err := s.getLatestVersionFromProxy(dep.Path)
+	// Check maintenance status
+	err := s.checkMaintenanceStatus(ctx, target)
+	stage := s.lookupStage(ctx, target.Path)
+	logStage(ctx, stage, start)
 	if err != nil {
-		eslog.Debugf("Failed to get latest version for %s: %v", dep.Path, err)
-	} else {
-		dep.Latest = latestVersion
-		// Check if update is available
-		if semver.Compare(dep.Version, latestVersion) < 0 {
-			dep.Update = latestVersion
+		logFailure(ctx, stage, "Failed to check maintenance status", err)
+	}
+	repository, resolved := s.resolveRepository(ctx, target)
+	if s.licenseClient != nil && !s.IsPrivate(ctx, target.Path) {
+		s.checkLicenses(ctx, target)
+	}
+	job.repository = repository
+	return resolved && s.forge != nil
+}
+
+// completeDependency scores the looked up dependency, unless it is a
+// local replacement or scanned quick or offline, and stores it under its
+// own module path and version. The duration of all its lookups is logged
+// at debug level, so slow lookups can be diagnosed.
+func (s *Scanner) completeDependency(job *scanJob) {
+	target := job.target
+	if !job.dep.Replace.IsLocal() && !s.quick && !s.offline {
+		s.scoreDependency(&target)
+	}
+	job.log.Debug("Dependency scanned", slog.Duration(logKeyDuration, time.Since(job.started)), slog.Int("errors", len(target.Errors)))
+	target.Path, target.Version, target.requirement = job.dep.Path, job.dep.Version, ""
+	*job.dep = target
+}
+
+// resolveRepository sets the source repository of the dependency. Failures
+// are reported as warning, the repository is just unknown then.
+func (s *Scanner) resolveRepository(ctx context.Context, dep *Dependency) (repo.Repository, bool) {
+	defer logStage(ctx, StageResolve, time.Now())
+	repository, err := s.resolver.Resolve(ctx, dep.Path)
+	if err != nil {
+		if ctx.Err() == nil {
+			logFailure(ctx, StageResolve, "Failed to resolve repository", err)
+			s.warnings.add("Failed to resolve source repository: "+warningReason(err), dep.Path)
+			dep.addError(StageResolve, err)
 		}
+		return repo.Repository{}, false
 	}
+	dep.Repository = repository.URL
+	return repository, true
+}
 
-	return nil
+// checkForge runs all lookups of the source repository on its forge
+func (s *Scanner) checkForge(ctx context.Context, dep *Dependency, repository repo.Repository) {
+	defer logStage(ctx, StageForge, time.Now())
+	s.checkRepository(ctx, dep, repository)
+	if s.fetchReleaseNotes {
+		s.checkReleaseNotes(ctx, dep, repository)
+	}
+	if s.checkSecuritySignals {
+		s.checkSecurity(ctx, dep, repository)
+	}
 }
 
-// getGoProxyURLs returns a list of Go proxy URLs from the GOPROXY environment variable
-// Falls back to proxy.golang.org if GOPROXY is not set
-// Handles multiple proxies separated by commas
-func (s *Scanner) getGoProxyURLs() []string {
-	goproxy := os.Getenv("GOPROXY")
-	if goproxy == "" {
-		return []string{"https://proxy.golang.org"}
+// checkRepository sets whether the source repository is archived, its open
+// issues, how many contributors it had in the last 12 months and, if
+// enabled, how responsive its maintainers are. Repositories on other forges
+// and lookups a forge doesn't support are skipped, failures are reported as
+// warning.
+func (s *Scanner) checkRepository(ctx context.Context, dep *Dependency, repository repo.Repository) {
+	info, err := s.forge.Repository(ctx, repository)
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, forge.ErrUnsupported) {
+			logFailure(ctx, StageForge, "Failed to look up repository", err, "repository", repository.URL)
+			s.warnings.add("Failed to look up repository metadata: "+warningReason(err), dep.Path)
+			dep.addError(StageForge, err)
+		}
+		return
+	}
+	dep.Archived = &info.Archived
+	dep.OpenIssues = info.OpenIssues
+	if info.Parent != "" {
+		s.checkUpstream(ctx, dep, info)
 	}
 
-	var proxies []string
-	for _, p := range strings.Split(goproxy, ",") {
-		p = strings.TrimSpace(p)
-		if p != "" && p != "direct" {
-			// Remove trailing slash for consistency
-			p = strings.TrimSuffix(p, "/")
-			proxies = append(proxies, p)
+	contributors, err := s.forge.Contributors(ctx, repository, s.now().AddDate(-1, 0, 0))
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, forge.ErrUnsupported) {
+			logFailure(ctx, StageForge, "Failed to count contributors", err, "repository", repository.URL)
+			s.warnings.add("Failed to count contributors: "+warningReason(err), dep.Path)
+			dep.addError(StageForge, err)
 		}
+		return
 	}
+	dep.ContributorCount = &contributors
+	dep.BusFactorRisk = contributors < s.minContributors
 
-	// If no valid proxies found (e.g., only "direct" was specified),
-	// fall back to the default proxy
-	if len(proxies) == 0 {
-		proxies = append(proxies, "https://proxy.golang.org")
+	if s.checkResponsiveness {
+		s.measureResponsiveness(ctx, dep, repository)
 	}
+}
 
-	return proxies
+// measureResponsiveness sets the median response time to the issues and
+// the pull requests merged in the last 90 days. Unresponsive maintainers
+// mark the dependency inactive, no matter how recent its commits are.
+func (s *Scanner) measureResponsiveness(ctx context.Context, dep *Dependency, repository repo.Repository) {
+	responsiveness, err := s.forge.Responsiveness(ctx, repository, s.now().AddDate(0, 0, -90))
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, forge.ErrUnsupported) {
+			logFailure(ctx, StageForge, "Failed to measure responsiveness", err, "repository", repository.URL)
+			s.warnings.add("Failed to measure maintainer responsiveness: "+warningReason(err), dep.Path)
+			dep.addError(StageForge, err)
+		}
+		return
+	}
+	dep.MergedPullRequests = &responsiveness.MergedPullRequests
+	answered := responsiveness.Sampled - responsiveness.Unanswered
+	dep.SampledIssues = &responsiveness.Sampled
+	dep.AnsweredIssues = &answered
+	if responsiveness.MedianFirstResponse != nil {
+		hours := int(responsiveness.MedianFirstResponse.Hours())
+		dep.MedianResponseHours = &hours
+	}
+	if s.isUnresponsive(dep) {
+		dep.Unresponsive = true
+		dep.IsActive = false
+	}
 }
 
-// versionInfo represents the JSON response from the Go proxy
-type versionInfo struct {
-	Version string    `json:"Version"`
-	Time    time.Time `json:"Time"`
+// checkLicenses sets the licenses of the dependency. Failures are reported
+// as warning, the licenses are just unknown then.
+func (s *Scanner) checkLicenses(ctx context.Context, dep *Dependency) {
+	defer logStage(ctx, StageLicense, time.Now())
+	licenses, err := s.licenseClient.Lookup(ctx, dep.Path, dep.Version)
+	if err != nil {
+		if ctx.Err() == nil {
+			logFailure(ctx, StageLicense, "Failed to look up licenses", err)
+			s.warnings.add("Failed to look up licenses: "+warningReason(err), dep.Path)
+			dep.addError(StageLicense, err)
+		}
+		return
+	}
+	dep.Licenses = licenses
 }
 
-// getVersionInfoFromProxy fetches version information from the Go proxy
-// Tries each proxy in order and returns the first successful result
-func (s *Scanner) getVersionInfoFromProxy(modulePath, version string) (time.Time, error) {
-	proxies := s.getGoProxyURLs()
-	var lastErr error
+// checkVulnerabilities annotates the dependencies with known vulnerabilities.
+// A failing lookup is reported as warning and does not abort the scan.
+func (s *Scanner) checkVulnerabilities(ctx context.Context, queue []*Dependency) {
+	defer logStage(ctx, StageVulnerability, time.Now())
+	// Local replacements have no published versions to check, private
+	// modules are not disclosed to OSV
+	var deps []*Dependency
+	for _, dep := range queue {
+		if path, _ := dep.lookupModule(); !dep.Replace.IsLocal() && !s.IsPrivate(ctx, path) {
+			deps = append(deps, dep)
+		}
+	}
 
-	// Try each proxy in order
-	for i, proxyURL := range proxies {
-		// Construct the proxy URL for the version info endpoint
-		// Format: {GOPROXY}/{modulePath}/@v/{version}.info
-		escapedPath := url.PathEscape(modulePath)
-		infoURL := fmt.Sprintf("%s/%s/@v/%s.info", proxyURL, escapedPath, url.PathEscape(version))
+	queries := make([]vuln.Query, len(deps))
+	for i, dep := range deps {
+		path, version := dep.lookupModule()
+		queries[i] = vuln.Query{Path: path, Version: version}
+	}
 
-		response, err := http.Get(infoURL)
-		if err != nil {
-			lastErr = fmt.Errorf("proxy %s: %w", proxyURL, err)
-			eslog.Debugf("Failed to fetch from proxy %d/%d (%s): %v", i+1, len(proxies), proxyURL, err)
-			continue
+	results, err := s.vulnClient.Check(ctx, queries)
+	if err != nil {
+		logFailure(ctx, StageVulnerability, "Failed to check vulnerabilities", err, "dependencies", len(deps))
+		for _, dep := range deps {
+			s.warnings.add("Failed to check vulnerabilities: "+warningReason(err), dep.Path)
+			dep.addError(StageVulnerability, err)
 		}
-		defer response.Body.Close()
+		return
+	}
 
-		if response.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(response.Body)
-			lastErr = fmt.Errorf("proxy %s returned status %d: %s", proxyURL, response.StatusCode, string(body))
-			eslog.Debugf("Proxy %d/%d (%s) failed: %v", i+1, len(proxies), proxyURL, lastErr)
-			continue
-		}
+	for i, dep := range deps {
+		dep.Vulnerabilities = results[i]
+	}
+}
 
-		// Successfully got response, decode it
-		var info versionInfo
-		if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
-			lastErr = fmt.Errorf("failed to decode version info from proxy %s: %w", proxyURL, err)
-			eslog.Debugf("Failed to decode response from proxy %d/%d (%s): %v", i+1, len(proxies), proxyURL, err)
-			continue
+// addResult appends a scanned dependency and updates the summaries
+func (s *Scanner) addResult(dep Dependency) {
+	s.resultMutex.Lock()
+	defer s.resultMutex.Unlock()
+
+	s.result.Dependencies = append(s.result.Dependencies, dep)
+	countDependency(&s.result.Summary, dep)
+	for i := range s.result.Modules {
+		if s.result.Modules[i].Path == dep.Module {
+			countDependency(&s.result.Modules[i].Summary, dep)
 		}
+	}
+}
 
-		// Success!
-		eslog.Debugf("Successfully fetched version info for %s@%s from proxy %d/%d (%s)", modulePath, version, i+1, len(proxies), proxyURL)
-		return info.Time, nil
+// countDependency adds a single dependency to the given summary
+func countDependency(summary *Summary, dep Dependency) {
+	summary.Total++
+	if dep.IsInactive() && !dep.IsAcknowledged {
+		summary.Inactive++
 	}
+	if dep.Status == StatusUnknown {
+		summary.Unknown++
+	}
+	if dep.Update != "" {
+		summary.Outdated++
+	} else if dep.Latest != "" {
+		summary.Updated++
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		summary.Vulnerable++
+		summary.Vulnerabilities += len(dep.Vulnerabilities)
+	}
+	if dep.Error != nil {
+		summary.addError(dep.Error.Category)
+	}
+	if dep.LicenseChange != nil {
+		summary.LicenseChanges++
+	}
+	if dep.NewerMajorAvailable != nil {
+		summary.NewerMajor++
+	}
+	if dep.Retracted != nil {
+		summary.Retracted++
+	}
+	if dep.Deprecated != "" {
+		summary.Deprecated++
+	}
+	if dep.BusFactorRisk {
+		summary.BusFactorRisk++
+	}
+	if dep.Severity != "" {
+		if summary.Severities == nil {
+			summary.Severities = make(map[Severity]int)
+		}
+		summary.Severities[dep.Severity]++
+	}
+}
 
-	// All proxies failed
-	if lastErr != nil {
-		return time.Time{}, fmt.Errorf("failed to fetch version info from all %d proxies: %w", len(proxies), lastErr)
+// Summarize counts the dependencies like a scan does, for results put
+// together from several scans. The stale threshold is copied into the
+// summary.
+func Summarize(deps []Dependency, staleThresholdDays int) Summary {
+	summary := Summary{StaleThresholdDays: staleThresholdDays}
+	for _, dep := range deps {
+		countDependency(&summary, dep)
 	}
-	return time.Time{}, fmt.Errorf("no proxies available")
+	return summary
 }
 
-// getLatestVersionFromProxy fetches the latest version from the Go proxy
-func (s *Scanner) getLatestVersionFromProxy(modulePath string) (string, error) {
-	proxies := s.getGoProxyURLs()
-	var lastErr error
+func (s *Scanner) checkMaintenanceStatus(ctx context.Context, dep *Dependency) error {
+	// Update detection does not depend on the release time lookup
+	versions, listed := s.checkForUpdate(ctx, dep)
+	s.checkReleaseCadence(ctx, dep, versions, listed)
+	if dep.Latest != "" {
+		s.checkModuleStatus(ctx, dep)
+		s.checkNewerMajor(ctx, dep)
+	}
 
-	// Try each proxy in order
-	for i, proxyURL := range proxies {
-		escapedPath := url.PathEscape(modulePath)
-		latestURL := fmt.Sprintf("%s/%s/@latest", proxyURL, escapedPath)
+	// Get version info from Go proxy
+	commitTime, err := s.getVersionInfoFromProxy(ctx, dep.Path, dep.Version)
+	if err != nil {
+		logFailure(ctx, s.lookupStage(ctx, dep.Path), "Failed to get version info", err)
+		s.warnings.add("Failed to get version info from proxy: "+warningReason(err), dep.Path)
+		dep.Error = dep.addError(s.lookupStage(ctx, dep.Path), err)
+		return nil
+	}
 
-		response, err := http.Get(latestURL)
-		if err != nil {
-			lastErr = fmt.Errorf("proxy %s: %w", proxyURL, err)
-			eslog.Debugf("Failed to fetch latest from proxy %d/%d (%s): %v", i+1, len(proxies), proxyURL, err)
-			continue
-		}
-		defer response.Body.Close()
+	dep.LastReleaseTime = commitTime
+	daysSinceRelease := int(s.now().Sub(dep.LastReleaseTime).Hours() / 24)
+	dep.DaysSinceLastRelease = daysSinceRelease
 
-		if response.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("proxy %s returned status %d", proxyURL, response.StatusCode)
-			eslog.Debugf("Proxy %d/%d (%s) latest failed: %v", i+1, len(proxies), proxyURL, lastErr)
-			continue
-		}
+	if s.isDependencyStale(dep.requirementPath(), daysSinceRelease) || s.isReleaseStale(dep) {
+		dep.IsActive = false
+	}
 
-		var info versionInfo
-		if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
-			lastErr = fmt.Errorf("failed to decode latest version from proxy %s: %w", proxyURL, err)
-			eslog.Debugf("Failed to decode latest from proxy %d/%d (%s): %v", i+1, len(proxies), proxyURL, err)
-			continue
+	return nil
+}
+
+// checkForUpdate sets Latest and, if the latest version is newer than the
+// one in use, Update for the given dependency. It returns the known versions
+// of the module for further checks and whether the proxy listed them.
+func (s *Scanner) checkForUpdate(ctx context.Context, dep *Dependency) ([]string, bool) {
+	versions, err := s.getVersionListFromProxy(ctx, dep.Path)
+	if err != nil {
+		logFailure(ctx, s.lookupStage(ctx, dep.Path), "Failed to get version list", err)
+	}
+	listed := err == nil
+
+	latestVersion := latestFromVersionList(versions)
+	if latestVersion == "" {
+		latestVersion, err = s.getLatestVersionFromProxy(ctx, dep.Path)
+		if err != nil {
+			logFailure(ctx, s.lookupStage(ctx, dep.Path), "Failed to get latest version", err)
+			return versions, listed
 		}
+	}
 
-		eslog.Debugf("Successfully fetched latest version for %s from proxy %d/%d (%s): %s", modulePath, i+1, len(proxies), proxyURL, info.Version)
-		return info.Version, nil
+	dep.Latest = latestVersion
+	if isNewerVersion(dep.Version, latestVersion) {
+		dep.Update = latestVersion
 	}
+	return versions, listed
+}
 
-	// All proxies failed
-	if lastErr != nil {
-		return "", fmt.Errorf("failed to fetch latest version from all %d proxies: %w", len(proxies), lastErr)
+// isNewerVersion reports whether candidate is a newer semantic version than
+// current. Invalid versions never count as newer.
+func isNewerVersion(current, candidate string) bool {
+	if !semver.IsValid(candidate) {
+		return false
 	}
-	return "", fmt.Errorf("no proxies available")
+	if !semver.IsValid(current) {
+		return false
+	}
+	return semver.Compare(current, candidate) < 0
 }
 
-func (s *Scanner) PrintResults() {
-	fmt.Printf("\n=== Govital Dependency Scan Results ===\n")
-	fmt.Printf("Project: %s\n", s.projectPath)
-	fmt.Printf("Stale Threshold: %d days\n\n", s.staleThresholdDays)
+// versionInfo represents the JSON response from the Go proxy
+type versionInfo struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
 
-	// Separate direct and indirect dependencies
-	var directDeps, indirectDeps []Dependency
-	for _, dep := range s.result.Dependencies {
-		if dep.IsIndirect {
-			indirectDeps = append(indirectDeps, dep)
-		} else {
-			directDeps = append(directDeps, dep)
-		}
-	}
-
-	// Count inactive dependencies by type
-	directInactive := 0
-	indirectInactive := 0
-	directUpdates := 0
-	indirectUpdates := 0
-	directAcknowledged := 0
-	indirectAcknowledged := 0
-	for _, dep := range directDeps {
-		if !dep.IsActive {
-			if !dep.IsAcknowledged {
-				directInactive++
-			} else {
-				directAcknowledged++
+// fetchFromProxy requests the given endpoint of a module from the Go proxy.
+// The proxies of GOPROXY are tried in order like the go command does: after
+// a comma only if the module or version doesn't exist, after a pipe on any
+// error. direct fetches the module from its repository, off fails.
+// Format: {GOPROXY}/{escaped module path}/{endpoint}. Modules matching
+// GONOPROXY are fetched from their repositories instead.
+func (s *Scanner) fetchFromProxy(ctx context.Context, modulePath, endpoint string) ([]byte, error) {
+	return s.fetchModule(ctx, modulePath, endpoint, false)
+}
+
+// probeProxy is fetchFromProxy for lookups of modules which most likely
+// don't exist, like the next major versions. A direct entry after a proxy
+// which doesn't know the module is skipped, since it would run go list and
+// git for each of them. direct is only used as the first GOPROXY entry or
+// for GONOPROXY modules.
+func (s *Scanner) probeProxy(ctx context.Context, modulePath, endpoint string) ([]byte, error) {
+	return s.fetchModule(ctx, modulePath, endpoint, true)
+}
+
+// fetchModule tries the proxies of GOPROXY in order, see fetchFromProxy
+func (s *Scanner) fetchModule(ctx context.Context, modulePath, endpoint string, probe bool) ([]byte, error) {
+	if s.privatePatterns(ctx).direct(modulePath) {
+		return s.fetchDirect(ctx, modulePath, endpoint)
+	}
+
+	proxies, err := s.goProxyList()
+	if err != nil {
+		return nil, err
+	}
+
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path %s: %w", modulePath, err)
+	}
+
+	var lastErr error
+	for _, proxy := range proxies {
+		var body []byte
+		switch proxy.url {
+		case proxyOff:
+			err = errProxyOff
+		case proxyDirect:
+			if probe && lastErr != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", endpoint, lastErr)
 			}
+			body, err = s.fetchDirect(ctx, modulePath, endpoint)
+		default:
+			body, err = s.fetchFromProxyURL(ctx, proxy.url, escapedPath, endpoint)
 		}
-		if dep.Update != "" {
-			directUpdates++
+		if err == nil {
+			logger(ctx).Debug("Fetched from proxy", "path", modulePath, "endpoint", endpoint, "proxy", proxy.url)
+			return body, nil
 		}
-	}
-	for _, dep := range indirectDeps {
-		if !dep.IsActive {
-			if !dep.IsAcknowledged {
-				indirectInactive++
-			} else {
-				indirectAcknowledged++
-			}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		if dep.Update != "" {
-			indirectUpdates++
-		}
-	}
-
-	fmt.Printf("Summary:\n")
-	fmt.Printf("  Total Dependencies:        %d\n", s.result.Summary.Total)
-	fmt.Printf("  Inactive Dependencies:     %d (Direct: %d, Indirect: %d)\n", s.result.Summary.Inactive, directInactive, indirectInactive)
-	fmt.Printf("  Acknowledged:              %d (Direct: %d, Indirect: %d)\n", directAcknowledged+indirectAcknowledged, directAcknowledged, indirectAcknowledged)
-	fmt.Printf("  Update Available:          %d (Direct: %d, Indirect: %d)\n", directUpdates+indirectUpdates, directUpdates, indirectUpdates)
-	fmt.Printf("  Errors:                    %d\n", s.result.Summary.Errors)
-	fmt.Printf("\nDependencies:\n")
-
-	// Print direct dependencies
-	if len(directDeps) > 0 {
-		fmt.Printf("\nDirect Dependencies (%d):\n", len(directDeps))
-		for _, dep := range directDeps {
-			status := "✓ Active"
-			if !dep.IsActive {
-				if dep.IsAcknowledged {
-					status = "⊘ Acknowledged"
-				} else {
-					status = "✗ Inactive"
-				}
-			}
-
-			updateStatus := ""
-			if dep.Update != "" {
-				updateStatus = fmt.Sprintf(" [UPDATE: %s]", dep.Update)
-			} else if dep.Latest != "" {
-				updateStatus = " [Latest]"
-			}
 
-			if dep.Error != "" {
-				fmt.Printf("  - %s@%s [ERROR: %s]\n", dep.Path, dep.Version, dep.Error)
-			} else if !dep.LastReleaseTime.IsZero() {
-				fmt.Printf("  - %s@%s [%s] (last release: %d days ago)%s\n",
-					dep.Path, dep.Version, status, dep.DaysSinceLastRelease, updateStatus)
-			} else {
-				fmt.Printf("  - %s@%s [%s]%s\n", dep.Path, dep.Version, status, updateStatus)
-			}
+		logFailure(ctx, StageProxy, "Failed to fetch from proxy", err, "path", modulePath, "endpoint", endpoint, "proxy", proxy.url)
+		// Like the go command report the last error which says more than
+		// that the module doesn't exist
+		if lastErr == nil || !isNotExist(err) {
+			lastErr = err
+		}
+		if !proxy.fallBackOnError && !isNotExist(err) {
+			break
 		}
 	}
 
-	// Print indirect dependencies
-	if len(indirectDeps) > 0 {
-		fmt.Printf("\nIndirect Dependencies (%d):\n", len(indirectDeps))
-		for _, dep := range indirectDeps {
-			status := "✓ Active"
-			if !dep.IsActive {
-				if dep.IsAcknowledged {
-					status = "⊘ Acknowledged"
-				} else {
-					status = "✗ Inactive"
-				}
-			}
+	return nil, fmt.Errorf("failed to fetch %s: %w", endpoint, lastErr)
+}
 
-			updateStatus := ""
-			if dep.Update != "" {
-				updateStatus = fmt.Sprintf(" [UPDATE: %s]", dep.Update)
-			} else if dep.Latest != "" {
-				updateStatus = " [Latest]"
-			}
+// fetchFromProxyURL requests the endpoint of a module from a single proxy
+func (s *Scanner) fetchFromProxyURL(ctx context.Context, proxyURL, escapedPath, endpoint string) ([]byte, error) {
+	requestURL := fmt.Sprintf("%s/%s/%s", proxyURL, escapedPath, endpoint)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", requestURL, err)
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s: %w", proxyURL, err)
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from proxy %s: %w", proxyURL, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		logger(ctx).Debug("Proxy request failed", "proxy", proxyURL, "endpoint", endpoint, "status", response.StatusCode, "body", strings.TrimSpace(string(body)))
+		return nil, &proxyStatusError{Proxy: proxyURL, StatusCode: response.StatusCode}
+	}
+	return body, nil
+}
 
-			if dep.Error != "" {
-				fmt.Printf("  - %s@%s [ERROR: %s]\n", dep.Path, dep.Version, dep.Error)
-			} else if !dep.LastReleaseTime.IsZero() {
-				fmt.Printf("  - %s@%s [%s] (last release: %d days ago)%s\n",
-					dep.Path, dep.Version, status, dep.DaysSinceLastRelease, updateStatus)
-			} else {
-				fmt.Printf("  - %s@%s [%s]%s\n", dep.Path, dep.Version, status, updateStatus)
+// getVersionInfoFromProxy fetches version information from the Go proxy
+func (s *Scanner) getVersionInfoFromProxy(ctx context.Context, modulePath, version string) (time.Time, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid version %s: %w", version, err)
+	}
+
+	body, err := s.fetchFromProxy(ctx, modulePath, "@v/"+escapedVersion+".info")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var info versionInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode version info for %s@%s: %w", modulePath, version, err)
+	}
+	return info.Time, nil
+}
+
+// getVersionListFromProxy returns the tagged versions of a module
+func (s *Scanner) getVersionListFromProxy(ctx context.Context, modulePath string) ([]string, error) {
+	body, err := s.fetchFromProxy(ctx, modulePath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// probeVersionList returns the tagged versions of a module which may not
+// exist, see probeProxy
+func (s *Scanner) probeVersionList(ctx context.Context, modulePath string) ([]string, error) {
+	body, err := s.probeProxy(ctx, modulePath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// getLatestVersionFromProxy queries the @latest endpoint. It is used for
+// modules without tagged versions, otherwise the latest version is taken from
+// the version list the same way the go command does. Modules the proxy
+// doesn't know aren't looked up directly, see probeProxy.
+func (s *Scanner) getLatestVersionFromProxy(ctx context.Context, modulePath string) (string, error) {
+	body, err := s.probeProxy(ctx, modulePath, "@latest")
+	if err != nil {
+		return "", err
+	}
+
+	var info versionInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to decode latest version for %s: %w", modulePath, err)
+	}
+	return info.Version, nil
+}
+
+// latestFromVersionList returns the highest release version of the list,
+// or the highest pre-release if there is no release at all
+func latestFromVersionList(versions []string) string {
+	var latestRelease, latestPrerelease string
+	for _, v := range versions {
+		if !semver.IsValid(v) {
+			continue
+		}
+		if semver.Prerelease(v) == "" {
+			if latestRelease == "" || semver.Compare(v, latestRelease) > 0 {
+				latestRelease = v
 			}
+		} else if latestPrerelease == "" || semver.Compare(v, latestPrerelease) > 0 {
+			latestPrerelease = v
 		}
 	}
-	fmt.Printf("\n")
+	if latestRelease != "" {
+		return latestRelease
+	}
+	return latestPrerelease
 }
 
 func (s *Scanner) GetInactiveDependencies() []Dependency {
 	var inactive []Dependency
 	for _, dep := range s.result.Dependencies {
-		if !dep.IsActive {
+		if dep.IsInactive() {
 			inactive = append(inactive, dep)
 		}
 	}
@@ -4,6 +4,7 @@
 package scanner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,7 +32,7 @@ func TestScanWithRealGoProject(t *testing.T) {
 	scanner.SetWorkers(2)
 	scanner.SetIncludeIndirectDependencies(false)
 
-	err = scanner.Scan()
+	err = scanner.Scan(context.Background())
 	require.NoError(t, err, "Scan should succeed")
 
 	result := scanner.GetResults()
@@ -53,7 +54,7 @@ func TestScanDirectDependencies(t *testing.T) {
 	scanner.SetStaleThreshold(30)
 	scanner.SetIncludeIndirectDependencies(false)
 
-	err := scanner.Scan()
+	err := scanner.Scan(context.Background())
 	require.NoError(t, err)
 
 	result := scanner.GetResults()
@@ -92,7 +93,7 @@ func TestScanWithIndirectDependencies(t *testing.T) {
 	scanner.SetIncludeIndirectDependencies(true)
 	scanner.SetStaleThreshold(30)
 
-	err := scanner.Scan()
+	err := scanner.Scan(context.Background())
 	require.NoError(t, err)
 
 	resultWithIndirect := scanner.GetResults()
@@ -102,7 +103,7 @@ func TestScanWithIndirectDependencies(t *testing.T) {
 	scanner2.SetIncludeIndirectDependencies(false)
 	scanner2.SetStaleThreshold(30)
 
-	err = scanner2.Scan()
+	err = scanner2.Scan(context.Background())
 	require.NoError(t, err)
 
 	resultWithoutIndirect := scanner2.GetResults()
@@ -124,7 +125,7 @@ func TestScanResultConsistency(t *testing.T) {
 	scanner1.SetStaleThreshold(30)
 	scanner1.SetIncludeIndirectDependencies(false)
 
-	err := scanner1.Scan()
+	err := scanner1.Scan(context.Background())
 	require.NoError(t, err)
 	result1 := scanner1.GetResults()
 
@@ -132,7 +133,7 @@ func TestScanResultConsistency(t *testing.T) {
 	scanner2.SetStaleThreshold(30)
 	scanner2.SetIncludeIndirectDependencies(false)
 
-	err = scanner2.Scan()
+	err = scanner2.Scan(context.Background())
 	require.NoError(t, err)
 	result2 := scanner2.GetResults()
 
@@ -159,7 +160,7 @@ func TestScanWithDifferentThresholds(t *testing.T) {
 	scanner1.SetStaleThreshold(30) // Very strict - 30 days
 	scanner1.SetIncludeIndirectDependencies(false)
 
-	err := scanner1.Scan()
+	err := scanner1.Scan(context.Background())
 	require.NoError(t, err)
 	inactiveStrict := scanner1.GetInactiveDependencies()
 
@@ -168,7 +169,7 @@ func TestScanWithDifferentThresholds(t *testing.T) {
 	scanner2.SetStaleThreshold(730) // Very lenient - 2 years
 	scanner2.SetIncludeIndirectDependencies(false)
 
-	err = scanner2.Scan()
+	err = scanner2.Scan(context.Background())
 	require.NoError(t, err)
 	inactiveLenient := scanner2.GetInactiveDependencies()
 
@@ -194,7 +195,7 @@ func TestParallelScanConsistency(t *testing.T) {
 		scanner.SetStaleThreshold(30)
 		scanner.SetIncludeIndirectDependencies(false)
 
-		err := scanner.Scan()
+		err := scanner.Scan(context.Background())
 		require.NoError(t, err)
 
 		result := scanner.GetResults()
@@ -207,23 +208,3 @@ func TestParallelScanConsistency(t *testing.T) {
 			"Different worker counts should find same number of dependencies")
 	}
 }
-
-// TestPrintResultsWithRealData tests printing results with actual scan data
-func TestPrintResultsWithRealData(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
-
-	govitalPath := filepath.Join("..", "..")
-	scanner := NewScanner(govitalPath)
-	scanner.SetStaleThreshold(30)
-	scanner.SetIncludeIndirectDependencies(false)
-
-	err := scanner.Scan()
-	require.NoError(t, err)
-
-	// Should not panic when printing
-	assert.NotPanics(t, func() {
-		scanner.PrintResults()
-	})
-}
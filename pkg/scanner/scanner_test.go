@@ -1,12 +1,23 @@
 package scanner
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/owners"
+	"github.com/steffakasid/govital/pkg/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewScanner(t *testing.T) {
@@ -36,17 +47,29 @@ func TestScanGoModNotFound(t *testing.T) {
 	tmpDir := t.TempDir()
 	scanner := NewScanner(tmpDir)
 
-	err := scanner.Scan()
+	err := scanner.Scan(context.Background())
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "go.mod not found")
 }
 
+func TestScanExplainsMissingGoMod(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "service"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "service", "go.mod"), []byte("module example.com/service\n"), 0o644))
+
+	err := NewScanner(tmpDir).Scan(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "go.mod not found")
+	assert.Contains(t, err.Error(), "found modules in service, scan them with --project-path or --recursive")
+}
+
 func TestScanWithValidGoMod(t *testing.T) {
 	// We can't reliably test with "." since test working dir varies
 	// Just verify the error handling works correctly
 	scanner := NewScanner(".")
-	err := scanner.Scan()
+	err := scanner.Scan(context.Background())
 	
 	// Either succeeds (if run from project root) or fails with proper error
 	if err != nil {
@@ -150,7 +173,7 @@ func TestScanResultSummary(t *testing.T) {
 	scanner := NewScanner(tmpDir)
 
 	// This will fail since tmpDir has no go.mod, but we can still test the result structure
-	err := scanner.Scan()
+	err := scanner.Scan(context.Background())
 	
 	assert.Error(t, err)
 	result := scanner.GetResults()
@@ -181,6 +204,36 @@ func TestSetWorkers(t *testing.T) {
 	}
 }
 
+func TestSetPoolWorkers(t *testing.T) {
+	scanner := NewScanner(".")
+	assert.Equal(t, defaultGitWorkers, scanner.gitWorkers)
+	assert.Equal(t, defaultForgeWorkers, scanner.forgeWorkers)
+
+	scanner.SetGitWorkers(6)
+	scanner.SetForgeWorkers(0)
+	assert.Equal(t, 6, scanner.gitWorkers)
+	assert.Equal(t, 1, scanner.forgeWorkers)
+	assert.Equal(t, "4 proxy, 6 git and 1 forge workers", scanner.concurrencyDescription())
+}
+
+func TestSetAutoWorkers(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.SetCheckVulnerabilities(true)
+
+	scanner.SetAutoWorkers()
+
+	assert.Equal(t, autoMaxWorkers, scanner.workers)
+	require.NotNil(t, scanner.limiter)
+	assert.Same(t, scanner.limiter, scanner.httpClient.Transport)
+	assert.Same(t, scanner.httpClient, scanner.vulnClient.HTTPClient)
+	assert.Contains(t, scanner.concurrencyDescription(), "adaptive concurrency")
+
+	scanner.SetWorkers(8)
+	assert.Nil(t, scanner.limiter)
+	assert.IsType(t, &transport.UserAgent{}, scanner.httpClient.Transport)
+	assert.Equal(t, "8 proxy, 2 git and 4 forge workers", scanner.concurrencyDescription())
+}
+
 func TestSetIncludeIndirectDependencies(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -250,88 +303,6 @@ func TestExtractCommitHashFromVersion(t *testing.T) {
 	}
 }
 
-func TestGetGoProxyURLs(t *testing.T) {
-	tests := []struct {
-		name                 string
-		env                  string
-		expectedProxyCount   int
-		expectedContains     []string
-	}{
-		{
-			name:                 "default proxy when env empty",
-			env:                  "",
-			expectedProxyCount:   1,
-			expectedContains:     []string{"proxy.golang.org"},
-		},
-		{
-			name:                 "single custom proxy from env",
-			env:                  "https://custom.proxy.com",
-			expectedProxyCount:   1,
-			expectedContains:     []string{"custom.proxy.com"},
-		},
-		{
-			name:                 "multiple proxies in order",
-			env:                  "https://first.proxy.com,https://second.proxy.com",
-			expectedProxyCount:   2,
-			expectedContains:     []string{"first.proxy.com", "second.proxy.com"},
-		},
-		{
-			name:                 "proxy with trailing slash removed",
-			env:                  "https://custom.proxy.com/",
-			expectedProxyCount:   1,
-			expectedContains:     []string{"custom.proxy.com"},
-		},
-		{
-			name:                 "multiple proxies with spaces",
-			env:                  "https://first.proxy.com , https://second.proxy.com",
-			expectedProxyCount:   2,
-			expectedContains:     []string{"first.proxy.com", "second.proxy.com"},
-		},
-		{
-			name:                 "direct keyword is skipped",
-			env:                  "https://custom.proxy.com,direct",
-			expectedProxyCount:   1,
-			expectedContains:     []string{"custom.proxy.com"},
-		},
-		{
-			name:                 "only direct falls back to default",
-			env:                  "direct",
-			expectedProxyCount:   1,
-			expectedContains:     []string{"proxy.golang.org"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Save original GOPROXY
-			origGOPROXY := os.Getenv("GOPROXY")
-			defer os.Setenv("GOPROXY", origGOPROXY)
-
-			// Set test GOPROXY
-			if tt.env == "" {
-				os.Unsetenv("GOPROXY")
-			} else {
-				os.Setenv("GOPROXY", tt.env)
-			}
-
-			scanner := NewScanner(".")
-			proxies := scanner.getGoProxyURLs()
-
-			assert.Equal(t, tt.expectedProxyCount, len(proxies))
-			for _, expected := range tt.expectedContains {
-				found := false
-				for _, p := range proxies {
-					if strings.Contains(p, expected) {
-						found = true
-						break
-					}
-				}
-				assert.True(t, found, "expected proxy containing %q not found in %v", expected, proxies)
-			}
-		})
-	}
-}
-
 func TestCheckMaintenanceStatusWithError(t *testing.T) {
 	scanner := NewScanner(".")
 	dep := &Dependency{
@@ -340,42 +311,15 @@ func TestCheckMaintenanceStatusWithError(t *testing.T) {
 		IsActive: false,
 	}
 
-	// Should handle errors gracefully - either succeeds or marks as active on error
-	err := scanner.checkMaintenanceStatus(dep)
+	// Should handle errors gracefully - either succeeds or records the error
+	err := scanner.checkMaintenanceStatus(context.Background(), dep)
 	assert.NoError(t, err)
-	// When it can't verify, it marks as active
-	assert.True(t, dep.IsActive)
-}
-
-func TestPrintResults(t *testing.T) {
-	scanner := NewScanner(".")
-	scanner.result.Dependencies = []Dependency{
-		{
-			Path:                "github.com/example/active",
-			Version:             "v1.0.0",
-			IsActive:            true,
-			DaysSinceLastRelease: 10,
-			LastReleaseTime:      time.Now().AddDate(0, 0, -10),
-			IsIndirect:           false,
-		},
-		{
-			Path:                "github.com/example/inactive",
-			Version:             "v1.0.0",
-			IsActive:            false,
-			DaysSinceLastRelease: 400,
-			LastReleaseTime:      time.Now().AddDate(0, 0, -400),
-			IsIndirect:           true,
-		},
+	// When it can't verify, the status is an error instead of active
+	if dep.Error != nil {
+		assert.Equal(t, StatusError, dep.maintenanceStatus())
+	} else {
+		assert.NotEqual(t, StatusUnknown, dep.maintenanceStatus())
 	}
-	scanner.result.Summary.Total = 2
-	scanner.result.Summary.Inactive = 1
-	scanner.result.Summary.Errors = 0
-	scanner.result.Summary.StaleThresholdDays = 30
-
-	// Should not panic
-	assert.NotPanics(t, func() {
-		scanner.PrintResults()
-	})
 }
 
 func TestDependencyInitialization(t *testing.T) {
@@ -384,7 +328,7 @@ func TestDependencyInitialization(t *testing.T) {
 		Version:             "v1.2.3",
 		Update:              "v1.2.4",
 		Latest:              "v1.3.0",
-		Error:               "",
+		Error:               nil,
 		LastReleaseTime:      time.Now(),
 		IsActive:            true,
 		DaysSinceLastRelease: 5,
@@ -492,26 +436,16 @@ func TestDependencyStatusEdgeCases(t *testing.T) {
 
 // Mock implementations for testing
 type MockCommandExecutor struct {
-	ExecuteFunc       func(name string, args ...string) ([]byte, error)
-	ExecuteInDirFunc  func(dir, name string, args ...string) ([]byte, error)
+	ExecuteFunc func(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error)
 }
 
-func (m *MockCommandExecutor) Execute(name string, args ...string) ([]byte, error) {
+func (m *MockCommandExecutor) Execute(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
 	if m.ExecuteFunc != nil {
-		return m.ExecuteFunc(name, args...)
-	}
-	return nil, nil
-}
-
-func (m *MockCommandExecutor) ExecuteInDir(dir, name string, args ...string) ([]byte, error) {
-	if m.ExecuteInDirFunc != nil {
-		return m.ExecuteInDirFunc(dir, name, args...)
+		return m.ExecuteFunc(ctx, dir, env, name, args...)
 	}
 	return nil, nil
 }
 
-
-
 // Test maintenance status with various scenarios
 func TestCheckMaintenanceStatusScenarios(t *testing.T) {
 	tests := []struct {
@@ -718,3 +652,425 @@ func TestExtractCommitHash(t *testing.T) {
 		})
 	}
 }
+
+// writeWorkspace creates a go.work with two member modules in dir
+func writeWorkspace(t *testing.T, dir string) {
+	t.Helper()
+	for _, name := range []string{"alpha", "beta"} {
+		modDir := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(modDir, 0o755))
+		goMod := "module example.com/" + name + "\n\ngo 1.21\n"
+		require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte(goMod), 0o600))
+	}
+	goWork := "go 1.21\n\nuse (\n\t./alpha\n\t./beta\n)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.work"), []byte(goWork), 0o600))
+}
+
+func TestDiscoverModules(t *testing.T) {
+	t.Run("plain module has no workspace modules", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/plain\n"), 0o600))
+
+		modules, err := NewScanner(tmpDir).discoverModules()
+
+		assert.NoError(t, err)
+		assert.Empty(t, modules)
+	})
+
+	t.Run("go.work lists member modules", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeWorkspace(t, tmpDir)
+
+		modules, err := NewScanner(tmpDir).discoverModules()
+
+		require.NoError(t, err)
+		require.Len(t, modules, 2)
+		assert.Equal(t, "example.com/alpha", modules[0].Path)
+		assert.Equal(t, filepath.Join(tmpDir, "alpha"), modules[0].Dir)
+		assert.Equal(t, "example.com/beta", modules[1].Path)
+	})
+
+	t.Run("neither go.mod nor go.work", func(t *testing.T) {
+		_, err := NewScanner(t.TempDir()).discoverModules()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "go.mod not found")
+	})
+}
+
+func TestScanWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeWorkspace(t, tmpDir)
+	scanner := NewScanner(tmpDir)
+
+	err := scanner.Scan(context.Background())
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	require.Len(t, result.Modules, 2)
+	assert.Equal(t, "example.com/alpha", result.Modules[0].Path)
+	assert.Equal(t, "example.com/beta", result.Modules[1].Path)
+	assert.Equal(t, 0, result.Summary.Total)
+}
+
+func TestAddResultModuleSummary(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.result.Modules = []ModuleResult{{Path: "example.com/alpha"}, {Path: "example.com/beta"}}
+
+	scanner.addResult(Dependency{Path: "github.com/example/a", Module: "example.com/alpha", IsActive: true})
+	scanner.addResult(Dependency{Path: "github.com/example/b", Module: "example.com/alpha", IsActive: false})
+	scanner.addResult(Dependency{Path: "github.com/example/a", Module: "example.com/beta", IsActive: true, Update: "v2.0.0"})
+
+	assert.Equal(t, 3, scanner.result.Summary.Total)
+	assert.Equal(t, 1, scanner.result.Summary.Inactive)
+	assert.Equal(t, 2, scanner.result.Modules[0].Summary.Total)
+	assert.Equal(t, 1, scanner.result.Modules[0].Summary.Inactive)
+	assert.Equal(t, 1, scanner.result.Modules[1].Summary.Total)
+	assert.Equal(t, 1, scanner.result.Modules[1].Summary.Outdated)
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		candidate string
+		expected  bool
+	}{
+		{"newer patch", "v1.0.0", "v1.0.1", true},
+		{"same version", "v1.2.3", "v1.2.3", false},
+		{"older candidate", "v1.3.0", "v1.2.0", false},
+		{"pseudo-version to tag", "v0.0.0-20240125120000-abcdef123456", "v0.1.0", true},
+		{"incompatible major", "v2.0.0+incompatible", "v2.1.0+incompatible", true},
+		{"invalid candidate", "v1.0.0", "latest", false},
+		{"invalid current", "", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isNewerVersion(tt.current, tt.candidate))
+		})
+	}
+}
+
+func TestCountDependencyUpdates(t *testing.T) {
+	var summary Summary
+
+	countDependency(&summary, Dependency{Path: "outdated", IsActive: true, Latest: "v1.1.0", Update: "v1.1.0"})
+	countDependency(&summary, Dependency{Path: "current", IsActive: true, Latest: "v1.0.0"})
+	countDependency(&summary, Dependency{Path: "unknown", IsActive: true})
+
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Outdated)
+	assert.Equal(t, 1, summary.Updated)
+}
+
+func TestLatestFromVersionList(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected string
+	}{
+		{"highest release wins", []string{"v1.0.0", "v1.10.0", "v1.2.0"}, "v1.10.0"},
+		{"release preferred over newer pre-release", []string{"v1.0.0", "v1.1.0-rc.1"}, "v1.0.0"},
+		{"pre-release when no release exists", []string{"v0.1.0-alpha", "v0.1.0-beta"}, "v0.1.0-beta"},
+		{"invalid entries ignored", []string{"garbage", "v0.2.0"}, "v0.2.0"},
+		{"empty list", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, latestFromVersionList(tt.versions))
+		})
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	goMod := []byte(`module example.com/project
+
+go 1.21
+
+require (
+	github.com/example/direct v1.2.3
+	github.com/example/indirect v0.1.0 // indirect
+)
+`)
+
+	t.Run("direct only", func(t *testing.T) {
+		deps, err := NewScanner(".").ParseGoMod(goMod)
+
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.Equal(t, "github.com/example/direct", deps[0].Path)
+		assert.Equal(t, "v1.2.3", deps[0].Version)
+		assert.False(t, deps[0].IsIndirect)
+	})
+
+	t.Run("including indirect", func(t *testing.T) {
+		scanner := NewScanner(".")
+		scanner.SetIncludeIndirectDependencies(true)
+
+		deps, err := scanner.ParseGoMod(goMod)
+
+		require.NoError(t, err)
+		require.Len(t, deps, 2)
+		assert.True(t, deps[1].IsIndirect)
+	})
+
+	t.Run("invalid go.mod", func(t *testing.T) {
+		_, err := NewScanner(".").ParseGoMod([]byte("require ("))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestScanDependenciesCancelled(t *testing.T) {
+	scanner := NewScanner(".")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := scanner.ScanDependencies(ctx, []Dependency{
+		{Path: "github.com/example/a", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/b", Version: "v1.0.0", IsActive: true},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, scanner.GetResults().Dependencies)
+}
+
+func TestScanDependenciesOwners(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	matcher, err := owners.NewMatcher([]owners.Rule{{Pattern: "github.com/example/...", Owners: []string{"@team"}}})
+	require.NoError(t, err)
+	scanner := NewScanner(".")
+	scanner.SetOwners(matcher)
+
+	err = scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@team"}, scanner.GetResults().Dependencies[0].Owners)
+}
+
+func TestScanDependenciesLicenseChange(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	depsDev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"licenses":["BUSL-1.1"]}`))
+	}))
+	defer depsDev.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetCheckLicenses(true)
+	scanner.licenseClient.BaseURL = depsDev.URL
+	scanner.SetBaseline(&ScanResult{Dependencies: []Dependency{
+		{Path: "github.com/example/mod", Version: "v0.9.0", Licenses: []string{"MIT"}},
+	}})
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	result := scanner.GetResults()
+	assert.Equal(t, []string{"BUSL-1.1"}, result.Dependencies[0].Licenses)
+	require.NotNil(t, result.Dependencies[0].LicenseChange)
+	assert.True(t, result.Dependencies[0].LicenseChange.Restrictive)
+	assert.Equal(t, 1, result.Summary.LicenseChanges)
+}
+
+func TestScanDependenciesArchivedRepository(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/commits") {
+			_, _ = w.Write([]byte(`[{"author":{"login":"alice"}},{"author":{"login":"alice"}}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"archived":true}`))
+	}))
+	defer github.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetCheckRepositories(true, forge.Config{})
+	scanner.forge.GitHubURL = github.URL
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	dep := scanner.GetResults().Dependencies[0]
+	require.NotNil(t, dep.Archived)
+	assert.True(t, *dep.Archived)
+	require.NotNil(t, dep.Score)
+	assert.LessOrEqual(t, *dep.Score, 20, "archived repositories cap the score")
+	require.NotNil(t, dep.ContributorCount)
+	assert.Equal(t, 1, *dep.ContributorCount)
+	assert.True(t, dep.BusFactorRisk)
+	assert.Equal(t, 1, scanner.GetResults().Summary.BusFactorRisk)
+}
+
+func TestScanDependenciesForgePoolDoesNotBlockProxyLookups(t *testing.T) {
+	proxy := newFakeProxy(t, map[string]int{"v1.0.0": 30, "v1.1.0": 20, "v1.2.0": 10, "v1.3.0": 5})
+	defer proxy.Close()
+	// The lookup of the last dependency is the only one fetching v1.2.0
+	lastLookup := make(chan struct{})
+	var once sync.Once
+	front := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/@v/v1.2.0.info") {
+			once.Do(func() { close(lastLookup) })
+		}
+		proxy.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer front.Close()
+	t.Setenv("GOPROXY", front.URL)
+
+	var blocked atomic.Bool
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-lastLookup:
+		case <-time.After(5 * time.Second):
+			blocked.Store(true)
+		}
+		if strings.HasSuffix(r.URL.Path, "/commits") {
+			_, _ = w.Write([]byte(`[{"author":{"login":"alice"}},{"author":{"login":"bob"}}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"archived":false}`))
+	}))
+	defer github.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetWorkers(1)
+	scanner.SetForgeWorkers(1)
+	scanner.SetCheckRepositories(true, forge.Config{})
+	scanner.forge.GitHubURL = github.URL
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+		{Path: "github.com/example/mod", Version: "v1.1.0"},
+		{Path: "github.com/example/mod", Version: "v1.2.0"},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, blocked.Load(), "the forge lookups waited for proxy lookups queued behind them")
+	for _, dep := range scanner.GetResults().Dependencies {
+		require.NotNil(t, dep.Archived, dep.Version)
+		require.NotNil(t, dep.ContributorCount, dep.Version)
+		assert.Equal(t, 2, *dep.ContributorCount)
+		assert.NotNil(t, dep.Score)
+	}
+}
+
+func TestScanDependenciesUnresponsive(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	created := time.Now().AddDate(0, 0, -30).UTC()
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			_, _ = w.Write([]byte(`[{"author":{"login":"alice"}},{"author":{"login":"bob"}}]`))
+		case strings.HasSuffix(r.URL.Path, "/issues"):
+			fmt.Fprintf(w, `[{"number":1,"user":{"login":"carol"},"created_at":%q}]`, created.Format(time.RFC3339))
+		case strings.HasSuffix(r.URL.Path, "/comments"):
+			fmt.Fprintf(w, `[{"user":{"login":"alice"},"created_at":%q}]`, created.AddDate(0, 0, 20).Format(time.RFC3339))
+		case strings.HasSuffix(r.URL.Path, "/pulls"):
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			_, _ = w.Write([]byte(`{"archived":false,"open_issues_count":12}`))
+		}
+	}))
+	defer github.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetCheckRepositories(true, forge.Config{})
+	scanner.SetCheckResponsiveness(true)
+	scanner.SetMaxResponseDays(14)
+	scanner.forge.GitHubURL = github.URL
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	dep := scanner.GetResults().Dependencies[0]
+	require.NotNil(t, dep.OpenIssues)
+	assert.Equal(t, 12, *dep.OpenIssues)
+	require.NotNil(t, dep.MedianResponseHours)
+	assert.Equal(t, 480, *dep.MedianResponseHours)
+	require.NotNil(t, dep.MergedPullRequests)
+	assert.Equal(t, 0, *dep.MergedPullRequests)
+	assert.True(t, dep.Unresponsive)
+	assert.False(t, dep.IsActive, "unresponsive maintainers outweigh the recent release")
+}
+
+func TestScanDependenciesProgress(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	var updates []int
+	scanner := NewScanner(".")
+	scanner.SetProgress(func(done, total int, current string) {
+		assert.Equal(t, 2, total)
+		updates = append(updates, done)
+	})
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+		{Path: "github.com/example/mod", Version: "v1.0.0", Module: "other"},
+		{Path: "github.com/example/mod", Version: "v0.9.0"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, updates, "duplicates are scanned once")
+}
+
+func TestOnDependencyScanned(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 400, "v1.3.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	scanner := NewScanner(".")
+	var streamed []Dependency
+	scanner.OnDependencyScanned(func(dep Dependency) {
+		streamed = append(streamed, dep)
+	})
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		// Shared by two workspace modules, looked up once
+		{Path: "github.com/example/mod", Version: "v1.0.0", Module: "example.com/a"},
+		{Path: "github.com/example/mod", Version: "v1.0.0", Module: "example.com/b", IsIndirect: true},
+		{Path: "github.com/original/local", Version: "v0.1.0", Replace: &Replacement{Path: "./local"}},
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, scanner.GetResults().Dependencies, streamed)
+	for _, dep := range streamed {
+		if dep.Module == "example.com/b" {
+			assert.True(t, dep.IsIndirect, "entry fields of the module")
+			assert.Equal(t, "v1.3.0", dep.Latest, "shared lookup results")
+		}
+	}
+}
+
+func TestOnDependencyScannedCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scanner := NewScanner(".")
+	scanner.OnDependencyScanned(func(dep Dependency) {
+		t.Errorf("unexpected dependency %s", dep.Path)
+	})
+	err := scanner.ScanDependencies(ctx, []Dependency{{Path: "github.com/example/mod", Version: "v1.0.0"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/changelog"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/repo"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// SecuritySignals are the security practices of the source repository of
+// a dependency. Nil signals are unknown, because the forge doesn't expose
+// them.
+type SecuritySignals struct {
+	// SecurityPolicy is whether the repository has a SECURITY.md
+	SecurityPolicy *bool `json:"security_policy,omitempty"`
+	// SignedTags is the number of the checked release tags with a
+	// verified signature
+	SignedTags int `json:"signed_tags"`
+	// CheckedTags is the number of release tags checked, the ones of the
+	// used and the latest version
+	CheckedTags int `json:"checked_tags"`
+	// BranchProtected is whether the default branch is protected
+	BranchProtected *bool `json:"branch_protected,omitempty"`
+}
+
+// share returns the share of the known practices the repository follows,
+// nil if none is known. Signed tags count by their ratio.
+func (s *SecuritySignals) share() *float64 {
+	var total float64
+	known := 0
+	if s.SecurityPolicy != nil {
+		known++
+		if *s.SecurityPolicy {
+			total++
+		}
+	}
+	if s.CheckedTags > 0 {
+		known++
+		total += float64(s.SignedTags) / float64(s.CheckedTags)
+	}
+	if s.BranchProtected != nil {
+		known++
+		if *s.BranchProtected {
+			total++
+		}
+	}
+	if known == 0 {
+		return nil
+	}
+	share := total / float64(known)
+	return &share
+}
+
+// String lists the known practices, like "security policy, 1/2 signed
+// tags, unprotected branch"
+func (s *SecuritySignals) String() string {
+	var parts []string
+	if s.SecurityPolicy != nil {
+		if *s.SecurityPolicy {
+			parts = append(parts, "security policy")
+		} else {
+			parts = append(parts, "no security policy")
+		}
+	}
+	if s.CheckedTags > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d signed tags", s.SignedTags, s.CheckedTags))
+	}
+	if s.BranchProtected != nil {
+		if *s.BranchProtected {
+			parts = append(parts, "protected branch")
+		} else {
+			parts = append(parts, "unprotected branch")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SetCheckSecuritySignals enables looking up whether the source
+// repositories have a security policy, signed release tags and a protected
+// default branch. It costs up to eight forge requests per dependency and
+// only applies if repository checks are enabled.
+func (s *Scanner) SetCheckSecuritySignals(check bool) {
+	s.checkSecuritySignals = check
+}
+
+// checkSecurity sets the security signals of the source repository,
+// checking the tags of the used and the latest release. Failures are
+// reported as warning, the signals are just unknown then.
+func (s *Scanner) checkSecurity(ctx context.Context, dep *Dependency, repository repo.Repository) {
+	prefix := changelog.TagPrefix(dep.Path, repository.Root)
+	var tags []string
+	for _, version := range []string{dep.Version, dep.Latest} {
+		if !semver.IsValid(version) || module.IsPseudoVersion(version) {
+			continue
+		}
+		tag := prefix + strings.TrimSuffix(version, "+incompatible")
+		if len(tags) == 0 || tags[0] != tag {
+			tags = append(tags, tag)
+		}
+	}
+
+	signals, err := s.forge.SecuritySignals(ctx, repository, tags)
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, forge.ErrUnsupported) {
+			logFailure(ctx, StageForge, "Failed to look up security signals", err, "repository", repository.URL)
+			s.warnings.add("Failed to look up security signals: "+warningReason(err), dep.Path)
+			dep.addError(StageForge, err)
+		}
+		return
+	}
+	dep.Security = &SecuritySignals{
+		SecurityPolicy:  signals.SecurityPolicy,
+		SignedTags:      signals.SignedTags,
+		CheckedTags:     signals.CheckedTags,
+		BranchProtected: signals.BranchProtected,
+	}
+}
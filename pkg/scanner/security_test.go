@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanDependenciesSecuritySignals(t *testing.T) {
+	server := newFakeProxy(t, map[string]int{"v1.0.0": 10})
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+
+	var tags []string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/example/mod/contents/SECURITY.md":
+			_, _ = w.Write([]byte(`{"content":"","encoding":"base64"}`))
+		case "/repos/example/mod/git/ref/tags/v1.0.0":
+			tags = append(tags, "v1.0.0")
+			_, _ = w.Write([]byte(`{"object":{"type":"commit","sha":"abc"}}`))
+		case "/repos/example/mod/branches/main":
+			_, _ = w.Write([]byte(`{"protected":true}`))
+		case "/repos/example/mod/commits":
+			_, _ = w.Write([]byte(`[{"author":{"login":"alice"}}]`))
+		default:
+			_, _ = w.Write([]byte(`{"archived":false,"default_branch":"main"}`))
+		}
+	}))
+	defer github.Close()
+
+	scanner := NewScanner(".")
+	scanner.SetCheckRepositories(true, forge.Config{})
+	scanner.SetCheckSecuritySignals(true)
+	scanner.forge.GitHubURL = github.URL
+
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	dep := scanner.GetResults().Dependencies[0]
+	require.NotNil(t, dep.Security)
+	assert.Equal(t, []string{"v1.0.0"}, tags, "the used and the latest version share a tag")
+	assert.Equal(t, "security policy, 0/1 signed tags, protected branch", dep.Security.String())
+}
+
+func TestSecuritySignalsShare(t *testing.T) {
+	yes, no := true, false
+	assert.Nil(t, (&SecuritySignals{}).share())
+
+	share := (&SecuritySignals{SecurityPolicy: &yes, SignedTags: 1, CheckedTags: 2, BranchProtected: &no}).share()
+	require.NotNil(t, share)
+	assert.InDelta(t, 0.5, *share, 0.001)
+}
@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity classifies the findings of a dependency
+type Severity string
+
+const (
+	// SeverityInfo marks findings worth knowing about, like a dependency
+	// which only just became stale
+	SeverityInfo Severity = "info"
+	// SeverityWarn marks findings to address soon
+	SeverityWarn Severity = "warn"
+	// SeverityCritical marks findings to address right away, like an
+	// archived or vulnerable dependency
+	SeverityCritical Severity = "critical"
+)
+
+// Severities are the valid severities from lowest to highest
+var Severities = []Severity{SeverityInfo, SeverityWarn, SeverityCritical}
+
+// Rank orders severities, it is 0 for dependencies without findings
+func (s Severity) Rank() int {
+	return slices.Index(Severities, s) + 1
+}
+
+// ParseSeverity parses a severity name, warning is accepted for warn
+func ParseSeverity(name string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(name)))
+	if severity == "warning" {
+		severity = SeverityWarn
+	}
+	if severity.Rank() == 0 {
+		return "", fmt.Errorf("invalid severity %q, expected info, warn or critical", name)
+	}
+	return severity, nil
+}
+
+// StaleSeverity is the severity of stale dependencies whose last release
+// is at least MinDays old
+type StaleSeverity struct {
+	MinDays  int      `mapstructure:"min_days"`
+	Severity Severity `mapstructure:"severity"`
+}
+
+// SeverityConfig maps the findings of a dependency to severities. Findings
+// without severity are not classified. The severity of a dependency is the
+// highest severity of its findings.
+type SeverityConfig struct {
+	Archived       Severity `mapstructure:"archived"`
+	Vulnerable     Severity `mapstructure:"vulnerable"`
+	Deprecated     Severity `mapstructure:"deprecated"`
+	Retracted      Severity `mapstructure:"retracted"`
+	LicenseChanged Severity `mapstructure:"license_changed"`
+	BusFactor      Severity `mapstructure:"bus_factor"`
+	Outdated       Severity `mapstructure:"outdated"`
+	// Stale are the severities of stale dependencies by the age of their
+	// last release, the entry with the highest MinDays not above the age
+	// applies. Acknowledged dependencies are not classified as stale.
+	Stale []StaleSeverity `mapstructure:"stale"`
+}
+
+// DefaultSeverityConfig classifies archived and vulnerable dependencies as
+// critical, stale dependencies by the age of their last release as info
+// below a year, warn below two years and critical above, and deprecated,
+// retracted and relicensed dependencies as warn
+func DefaultSeverityConfig() SeverityConfig {
+	return SeverityConfig{
+		Archived:       SeverityCritical,
+		Vulnerable:     SeverityCritical,
+		Deprecated:     SeverityWarn,
+		Retracted:      SeverityWarn,
+		LicenseChanged: SeverityWarn,
+		BusFactor:      SeverityInfo,
+		Stale: []StaleSeverity{
+			{MinDays: 0, Severity: SeverityInfo},
+			{MinDays: 365, Severity: SeverityWarn},
+			{MinDays: 730, Severity: SeverityCritical},
+		},
+	}
+}
+
+// Validate checks that all configured severities are valid and normalizes
+// their names
+func (c *SeverityConfig) Validate() error {
+	fields := map[string]*Severity{
+		"archived":        &c.Archived,
+		"vulnerable":      &c.Vulnerable,
+		"deprecated":      &c.Deprecated,
+		"retracted":       &c.Retracted,
+		"license_changed": &c.LicenseChanged,
+		"bus_factor":      &c.BusFactor,
+		"outdated":        &c.Outdated,
+	}
+	for name, severity := range fields {
+		if *severity == "" {
+			continue
+		}
+		parsed, err := ParseSeverity(string(*severity))
+		if err != nil {
+			return fmt.Errorf("severity.%s: %w", name, err)
+		}
+		*severity = parsed
+	}
+	for i := range c.Stale {
+		parsed, err := ParseSeverity(string(c.Stale[i].Severity))
+		if err != nil {
+			return fmt.Errorf("severity.stale[%d]: %w", i, err)
+		}
+		c.Stale[i].Severity = parsed
+		if c.Stale[i].MinDays < 0 {
+			return fmt.Errorf("severity.stale[%d]: min_days must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// Classify returns the highest severity of the findings of the dependency,
+// empty if it has none
+func (c SeverityConfig) Classify(dep Dependency) Severity {
+	var highest Severity
+	raise := func(found bool, severity Severity) {
+		if found && severity.Rank() > highest.Rank() {
+			highest = severity
+		}
+	}
+
+	raise(dep.Archived != nil && *dep.Archived, c.Archived)
+	raise(len(dep.Vulnerabilities) > 0, c.Vulnerable)
+	raise(dep.Deprecated != "", c.Deprecated)
+	raise(dep.Retracted != nil, c.Retracted)
+	raise(dep.LicenseChange != nil, c.LicenseChanged)
+	raise(dep.BusFactorRisk, c.BusFactor)
+	raise(dep.Update != "", c.Outdated)
+	if dep.IsInactive() && !dep.IsAcknowledged {
+		minDays := -1
+		var stale Severity
+		for _, entry := range c.Stale {
+			if entry.MinDays <= dep.DaysSinceLastRelease && entry.MinDays > minDays {
+				minDays, stale = entry.MinDays, entry.Severity
+			}
+		}
+		raise(stale != "", stale)
+	}
+	return highest
+}
+
+// SetSeverityConfig sets how the findings of dependencies are classified,
+// DefaultSeverityConfig by default
+func (s *Scanner) SetSeverityConfig(config SeverityConfig) {
+	s.severities = config
+}
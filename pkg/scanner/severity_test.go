@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity(" Critical")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityCritical, severity)
+
+	severity, err = ParseSeverity("warning")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityWarn, severity)
+
+	_, err = ParseSeverity("high")
+	assert.ErrorContains(t, err, `invalid severity "high"`)
+
+	assert.Less(t, Severity("").Rank(), SeverityInfo.Rank())
+	assert.Less(t, SeverityWarn.Rank(), SeverityCritical.Rank())
+}
+
+func TestClassify(t *testing.T) {
+	archived := true
+	config := DefaultSeverityConfig()
+	tests := []struct {
+		name     string
+		dep      Dependency
+		expected Severity
+	}{
+		{"active", Dependency{Status: StatusActive, IsActive: true}, ""},
+		{"recently stale", Dependency{Status: StatusStale, DaysSinceLastRelease: 200}, SeverityInfo},
+		{"stale for a year", Dependency{Status: StatusStale, DaysSinceLastRelease: 400}, SeverityWarn},
+		{"stale for two years", Dependency{Status: StatusStale, DaysSinceLastRelease: 800}, SeverityCritical},
+		{"acknowledged", Dependency{Status: StatusStale, DaysSinceLastRelease: 800, IsAcknowledged: true}, ""},
+		{"archived", Dependency{Status: StatusArchived, Archived: &archived, DaysSinceLastRelease: 30}, SeverityCritical},
+		{"vulnerable", Dependency{Status: StatusActive, Vulnerabilities: []vuln.Vulnerability{{ID: "GO-1"}}}, SeverityCritical},
+		{"deprecated", Dependency{Status: StatusActive, Deprecated: "use v2"}, SeverityWarn},
+		{"outdated is unclassified", Dependency{Status: StatusActive, Update: "v1.1.0"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, config.Classify(tt.dep))
+		})
+	}
+
+	config.Archived = SeverityWarn
+	config.Outdated = SeverityInfo
+	config.Stale = []StaleSeverity{{MinDays: 540, Severity: SeverityCritical}}
+	assert.Equal(t, SeverityWarn, config.Classify(Dependency{Status: StatusArchived, Archived: &archived}))
+	assert.Equal(t, SeverityInfo, config.Classify(Dependency{Status: StatusActive, Update: "v1.1.0"}))
+	assert.Equal(t, Severity(""), config.Classify(Dependency{Status: StatusStale, DaysSinceLastRelease: 400}), "no range applies")
+}
+
+func TestSeveritySummary(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.addResult(scanner.finishDependency(Dependency{Path: "example.com/old", Version: "v1.0.0", Status: StatusStale, DaysSinceLastRelease: 800}, Dependency{}))
+	scanner.addResult(scanner.finishDependency(Dependency{Path: "example.com/fine", Version: "v1.0.0", IsActive: true, LastReleaseTime: scanner.now()}, Dependency{}))
+
+	result := scanner.GetResults()
+	assert.Equal(t, SeverityCritical, result.Dependencies[0].Severity)
+	assert.Equal(t, map[Severity]int{SeverityCritical: 1}, result.Summary.Severities)
+}
@@ -0,0 +1,56 @@
+package scanner
+
+// Status is the maintenance status of a dependency
+type Status string
+
+const (
+	// StatusActive marks dependencies released within the stale threshold
+	StatusActive Status = "active"
+	// StatusStale marks dependencies without a recent release or with
+	// unresponsive maintainers
+	StatusStale Status = "stale"
+	// StatusArchived marks dependencies whose source repository is archived
+	StatusArchived Status = "archived"
+	// StatusUnknown marks dependencies whose release time is unknown
+	// without a failed lookup, e.g. local replacements
+	StatusUnknown Status = "unknown"
+	// StatusError marks dependencies whose release time lookup failed
+	StatusError Status = "error"
+)
+
+// IsInactive reports whether the status marks an unmaintained dependency
+func (s Status) IsInactive() bool {
+	return s == StatusStale || s == StatusArchived
+}
+
+// IsUnknown reports whether the maintenance status could not be determined
+func (s Status) IsUnknown() bool {
+	return s == StatusUnknown || s == StatusError
+}
+
+// IsInactive reports whether the dependency is stale or archived, no
+// matter if it is acknowledged. Results saved before statuses were
+// introduced fall back to IsActive, failed lookups never count as inactive.
+func (d Dependency) IsInactive() bool {
+	if d.Status == "" {
+		return !d.IsActive && d.Error == nil
+	}
+	return d.Status.IsInactive()
+}
+
+// maintenanceStatus derives the status from the lookup results. Failed
+// lookups never count as active.
+func (d Dependency) maintenanceStatus() Status {
+	switch {
+	case d.Error != nil:
+		return StatusError
+	case d.Archived != nil && *d.Archived:
+		return StatusArchived
+	case !d.IsActive:
+		return StatusStale
+	case d.LastReleaseTime.IsZero():
+		return StatusUnknown
+	default:
+		return StatusActive
+	}
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceStatus(t *testing.T) {
+	archived := true
+	released := time.Now().AddDate(0, 0, -10)
+	tests := []struct {
+		name     string
+		dep      Dependency
+		expected Status
+	}{
+		{"active", Dependency{IsActive: true, LastReleaseTime: released}, StatusActive},
+		{"stale", Dependency{LastReleaseTime: released}, StatusStale},
+		{"archived", Dependency{IsActive: true, LastReleaseTime: released, Archived: &archived}, StatusArchived},
+		{"failed lookup", Dependency{IsActive: true, Error: &ScanError{Category: ErrorNetwork, Message: "timeout"}}, StatusError},
+		{"not checked", Dependency{IsActive: true, Replace: &Replacement{Path: "../local"}}, StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.dep.maintenanceStatus())
+		})
+	}
+}
+
+func TestFinishDependencySetsStatus(t *testing.T) {
+	scanner := NewScanner(".")
+
+	failed := scanner.finishDependency(Dependency{Path: "github.com/example/a", IsActive: true, Error: &ScanError{Category: ErrorNotFound, Message: "not found"}}, Dependency{})
+
+	assert.Equal(t, StatusError, failed.Status)
+	assert.False(t, failed.IsActive, "failed lookups are not assumed active")
+	assert.False(t, failed.IsInactive())
+}
+
+func TestDependencyIsInactive(t *testing.T) {
+	assert.True(t, Dependency{Status: StatusArchived, IsActive: true}.IsInactive())
+	assert.False(t, Dependency{Status: StatusUnknown}.IsInactive())
+	// Results saved before statuses were introduced
+	assert.True(t, Dependency{}.IsInactive())
+	assert.False(t, Dependency{IsActive: true}.IsInactive())
+	assert.False(t, Dependency{Error: &ScanError{Message: "not found"}}.IsInactive())
+}
+
+func TestCountDependencyStatus(t *testing.T) {
+	var summary Summary
+
+	countDependency(&summary, Dependency{Path: "active", Status: StatusActive, IsActive: true})
+	countDependency(&summary, Dependency{Path: "stale", Status: StatusStale})
+	countDependency(&summary, Dependency{Path: "local", Status: StatusUnknown})
+	countDependency(&summary, Dependency{Path: "failed", Status: StatusError, Error: &ScanError{Category: ErrorNotFound, Message: "not found"}})
+
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 1, summary.Inactive)
+	assert.Equal(t, 1, summary.Unknown)
+	assert.Equal(t, 1, summary.Errors)
+}
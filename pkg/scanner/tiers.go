@@ -0,0 +1,87 @@
+package scanner
+
+import "fmt"
+
+// Tier tells how critical a dependency is to the project, e.g. the core
+// framework or a leaf utility, so it is held to the activity requirements
+// of its tier
+type Tier string
+
+const (
+	TierCritical Tier = "critical"
+	// TierNormal is the tier of dependencies without a tier
+	TierNormal Tier = "normal"
+	TierLow    Tier = "low"
+)
+
+// TierPolicy holds the activity requirements of a tier. Zero values fall
+// back to the global stale and release thresholds.
+type TierPolicy struct {
+	StaleThresholdDays   int `mapstructure:"stale_threshold_days"`
+	ReleaseThresholdDays int `mapstructure:"release_threshold_days"`
+}
+
+// DefaultTierPolicies returns the thresholds of the tiers: critical
+// dependencies are stale after 90 days without a release, low ones after
+// 730 days and normal ones use the global thresholds
+func DefaultTierPolicies() map[Tier]TierPolicy {
+	return map[Tier]TierPolicy{
+		TierCritical: {StaleThresholdDays: 90},
+		TierNormal:   {},
+		TierLow:      {StaleThresholdDays: 730},
+	}
+}
+
+// validTier reports whether the tier is one of critical, normal and low
+func validTier(tier Tier) bool {
+	switch tier {
+	case TierCritical, TierNormal, TierLow:
+		return true
+	}
+	return false
+}
+
+// SetTierPolicies sets the thresholds of the tiers assigned by the
+// overrides. Tiers which are not set keep their default.
+func (s *Scanner) SetTierPolicies(policies map[Tier]TierPolicy) error {
+	s.tierPolicies = DefaultTierPolicies()
+	for tier, policy := range policies {
+		if !validTier(tier) {
+			return fmt.Errorf("unknown tier %q, use critical, normal or low", tier)
+		}
+		if policy.StaleThresholdDays < 0 || policy.ReleaseThresholdDays < 0 {
+			return fmt.Errorf("invalid thresholds of tier %s: values must not be negative", tier)
+		}
+		s.tierPolicies[tier] = policy
+	}
+	return nil
+}
+
+// tierOf returns the tier the overrides assign to the module
+func (s *Scanner) tierOf(path string) Tier {
+	if tier := s.overrides[path].Tier; tier != "" {
+		return tier
+	}
+	return TierNormal
+}
+
+// staleThresholdOf returns the stale threshold of the module: its override,
+// else the one of its tier, else the global one
+func (s *Scanner) staleThresholdOf(path string) int {
+	if threshold := s.overrides[path].StaleThresholdDays; threshold > 0 {
+		return threshold
+	}
+	if threshold := s.tierPolicies[s.tierOf(path)].StaleThresholdDays; threshold > 0 {
+		return threshold
+	}
+	return s.staleThresholdDays
+}
+
+// releaseThresholdOf returns the release threshold of the module: the one
+// of its tier, else the global one
+func (s *Scanner) releaseThresholdOf(path string) int {
+	if threshold := s.tierPolicies[s.tierOf(path)].ReleaseThresholdDays; threshold > 0 {
+		return threshold
+	}
+	return s.releaseThresholdDays
+}
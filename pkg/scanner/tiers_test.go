@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiers(t *testing.T) {
+	scan := func(releaseAge int, policies map[Tier]TierPolicy, overrides ...Override) Dependency {
+		server := newFakeProxy(t, map[string]int{"v1.0.0": releaseAge})
+		defer server.Close()
+		t.Setenv("GOPROXY", server.URL)
+
+		scanner := NewScanner(t.TempDir())
+		scanner.SetQuick(true)
+		require.NoError(t, scanner.SetOverrides(overrides))
+		require.NoError(t, scanner.SetTierPolicies(policies))
+		require.NoError(t, scanner.ScanDependencies(context.Background(), []Dependency{
+			{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+		}))
+		deps := scanner.GetResults().Dependencies
+		require.Len(t, deps, 1)
+		return deps[0]
+	}
+	critical := Override{Path: "github.com/example/mod", Tier: TierCritical}
+
+	dep := scan(120, nil)
+	assert.True(t, dep.IsActive, "within the global threshold")
+	assert.Empty(t, dep.Tier)
+
+	dep = scan(120, nil, critical)
+	assert.False(t, dep.IsActive, "older than the default threshold of critical dependencies")
+	assert.Equal(t, TierCritical, dep.Tier)
+
+	dep = scan(120, map[Tier]TierPolicy{TierCritical: {StaleThresholdDays: 150}}, critical)
+	assert.True(t, dep.IsActive, "within the configured threshold of the tier")
+
+	// The threshold of the override takes precedence over its tier
+	dep = scan(120, nil, Override{Path: "github.com/example/mod", Tier: TierCritical, StaleThresholdDays: 365})
+	assert.True(t, dep.IsActive)
+
+	dep = scan(400, nil, Override{Path: "github.com/example/mod", Tier: TierLow})
+	assert.True(t, dep.IsActive, "within the default threshold of low dependencies")
+	assert.Equal(t, TierLow, dep.Tier)
+
+	scanner := NewScanner(t.TempDir())
+	assert.Error(t, scanner.SetOverrides([]Override{{Path: "github.com/example/mod", Tier: "core"}}))
+	assert.Error(t, scanner.SetTierPolicies(map[Tier]TierPolicy{"core": {StaleThresholdDays: 30}}))
+	assert.Error(t, scanner.SetTierPolicies(map[Tier]TierPolicy{TierLow: {StaleThresholdDays: -1}}))
+}
+
+func TestReleaseThresholdOfTier(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.SetReleaseThreshold(365)
+	require.NoError(t, scanner.SetOverrides([]Override{{Path: "github.com/example/framework", Tier: TierCritical}}))
+	require.NoError(t, scanner.SetTierPolicies(map[Tier]TierPolicy{TierCritical: {ReleaseThresholdDays: 180}}))
+
+	days := 200
+	assert.True(t, scanner.isReleaseStale(&Dependency{Path: "github.com/example/framework", DaysSinceLatestRelease: &days}))
+	assert.False(t, scanner.isReleaseStale(&Dependency{Path: "github.com/example/util", DaysSinceLatestRelease: &days}))
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/transport"
+)
+
+const (
+	// DefaultGitTimeout bounds fetching a private module from its repository
+	DefaultGitTimeout = 2 * time.Minute
+	// DefaultDependencyTimeout bounds all lookups of a dependency
+	DefaultDependencyTimeout = 5 * time.Minute
+)
+
+// Timeouts bound the external operations of a scan, so a hung git clone or
+// a slow proxy can't stall the whole scan. Zero disables a timeout.
+type Timeouts struct {
+	// Git bounds the git and go commands fetching a private module from
+	// its repository
+	Git time.Duration
+	// HTTP bounds each request, every retry gets its own deadline
+	HTTP time.Duration
+	// PerDependency bounds all lookups of a dependency, which is reported
+	// with a timeout error once it is exceeded
+	PerDependency time.Duration
+}
+
+// DefaultTimeouts returns the timeouts of a scan without configuration
+func DefaultTimeouts() Timeouts {
+	return Timeouts{Git: DefaultGitTimeout, HTTP: transport.DefaultHTTPTimeout, PerDependency: DefaultDependencyTimeout}
+}
+
+// SetTimeouts sets the timeouts of the external operations. Default:
+// DefaultTimeouts
+func (s *Scanner) SetTimeouts(timeouts Timeouts) {
+	s.gitTimeout = max(timeouts.Git, 0)
+	s.timeout.SetTimeout(timeouts.HTTP)
+	s.dependencyTimeout = max(timeouts.PerDependency, 0)
+}
+
+// withTimeout bounds ctx by timeout unless it is zero
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// dependencyContext returns the context of the lookups of the job, which
+// logs to the logger of the dependency and ends at its deadline
+func (s *Scanner) dependencyContext(ctx context.Context, job *scanJob) (context.Context, context.CancelFunc) {
+	ctx = withLogger(ctx, job.log)
+	if job.deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, job.deadline)
+}
+
+// checkDependencyTimeout reports whether the lookups of the dependency in
+// depCtx were cut off by the per dependency timeout and records a timeout
+// error unless a lookup already did. Other lookups failing due to the
+// timeout are not reported on their own, like on cancellation.
+func (s *Scanner) checkDependencyTimeout(ctx, depCtx context.Context, dep *Dependency, stage ErrorStage) bool {
+	if ctx.Err() != nil || !errors.Is(depCtx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	for _, scanErr := range dep.Errors {
+		if scanErr.Category == ErrorTimeout {
+			return true
+		}
+	}
+	err := fmt.Errorf("lookups exceeded the timeout of %s per dependency: %w", s.dependencyTimeout, context.DeadlineExceeded)
+	logFailure(depCtx, stage, "Dependency lookups timed out", err)
+	s.warnings.add("Lookups timed out: "+warningReason(err), dep.Path)
+	dep.addError(stage, err)
+	return true
+}
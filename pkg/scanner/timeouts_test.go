@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHungProxy returns a proxy answering no request until it is cancelled
+func newHungProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScanDependenciesPerDependencyTimeout(t *testing.T) {
+	t.Setenv("GOPROXY", newHungProxy(t).URL)
+
+	scanner := NewScanner(".")
+	scanner.SetTimeouts(Timeouts{PerDependency: 100 * time.Millisecond})
+	start := time.Now()
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	result := scanner.GetResults()
+	require.Len(t, result.Dependencies, 1)
+	require.NotNil(t, result.Dependencies[0].Error)
+	assert.Equal(t, ErrorTimeout, result.Dependencies[0].Error.Category)
+	assert.True(t, result.Dependencies[0].Error.Retryable)
+	assert.Equal(t, map[ErrorCategory]int{ErrorTimeout: 1}, result.Summary.ErrorsByCategory)
+}
+
+func TestScanDependenciesHTTPTimeout(t *testing.T) {
+	t.Setenv("GOPROXY", newHungProxy(t).URL)
+
+	scanner := NewScanner(".")
+	scanner.SetRetries(transport.RetryConfig{})
+	scanner.SetTimeouts(Timeouts{HTTP: 50 * time.Millisecond})
+	start := time.Now()
+	err := scanner.ScanDependencies(context.Background(), []Dependency{
+		{Path: "github.com/example/mod", Version: "v1.0.0", IsActive: true},
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	result := scanner.GetResults()
+	require.Len(t, result.Dependencies, 1)
+	require.NotNil(t, result.Dependencies[0].Error)
+	assert.Equal(t, ErrorTimeout, result.Dependencies[0].Error.Category)
+	assert.Equal(t, StageProxy, result.Dependencies[0].Error.Stage)
+}
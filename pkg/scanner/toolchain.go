@@ -0,0 +1,260 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/version"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goReleasesURL lists the supported Go releases, the latest patch release
+// of each supported minor version
+const goReleasesURL = "https://go.dev/dl/?mode=json"
+
+// ToolchainStatus classifies a Go version
+type ToolchainStatus string
+
+const (
+	// ToolchainOutdated is a supported Go version with a newer release
+	ToolchainOutdated ToolchainStatus = "outdated"
+	// ToolchainEOL is a Go version which no longer gets security fixes.
+	// Only the two newest minor versions are supported.
+	ToolchainEOL ToolchainStatus = "eol"
+)
+
+// Subjects of toolchain findings
+const (
+	// GoDirective is the go directive of a go.mod of the project
+	GoDirective = "go"
+	// ToolchainDirective is the toolchain directive of a go.mod of the
+	// project
+	ToolchainDirective = "toolchain"
+	// DependencyGoVersion is the go directive of a dependency
+	DependencyGoVersion = "dependency"
+)
+
+// ToolchainFinding is an outdated or end of life Go version declared by the
+// project or a dependency
+type ToolchainFinding struct {
+	// Subject is GoDirective, ToolchainDirective or DependencyGoVersion
+	Subject string `json:"subject"`
+	// Module is the module declaring the version
+	Module  string          `json:"module"`
+	Version string          `json:"version"`
+	Status  ToolchainStatus `json:"status"`
+	// Latest is the version to move to
+	Latest string `json:"latest"`
+}
+
+func (f ToolchainFinding) String() string {
+	subject := f.Subject + " directive"
+	if f.Subject == DependencyGoVersion {
+		subject = "go directive"
+	}
+	reason := "end of life"
+	if f.Status == ToolchainOutdated {
+		reason = "outdated"
+	}
+	return fmt.Sprintf("%s: %s %s is %s, latest is %s", f.Module, subject, f.Version, reason, f.Latest)
+}
+
+// SetCheckToolchain enables checking the go and toolchain directives of the
+// project and the go directives of the dependencies against the supported
+// Go releases listed by go.dev
+func (s *Scanner) SetCheckToolchain(check bool) {
+	s.checkToolchain = check
+}
+
+// goReleases fetches the supported Go releases, e.g. go1.25.3
+func (s *Scanner) goReleases(ctx context.Context) ([]string, error) {
+	releasesURL := s.goReleasesURL
+	if releasesURL == "" {
+		releasesURL = goReleasesURL
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", releasesURL, err)
+	}
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Go releases: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, response.Body)
+		return nil, fmt.Errorf("failed to fetch Go releases: %s returned status %d", releasesURL, response.StatusCode)
+	}
+
+	var listed []struct {
+		Version string `json:"version"`
+		Stable  bool   `json:"stable"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&listed); err != nil {
+		return nil, fmt.Errorf("failed to decode Go releases: %w", err)
+	}
+	var releases []string
+	for _, release := range listed {
+		if release.Stable && version.IsValid(release.Version) {
+			releases = append(releases, release.Version)
+		}
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no stable Go release listed by %s", releasesURL)
+	}
+	return releases, nil
+}
+
+// goModDirectives are the go and toolchain directives of a go.mod
+type goModDirectives struct {
+	Module    string
+	Go        string
+	Toolchain string
+}
+
+// readGoModDirectives reads the directives of the go.mod in dir
+func readGoModDirectives(files FileReader, dir string) (goModDirectives, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := files.ReadFile(path)
+	if err != nil {
+		return goModDirectives{}, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return parseGoModDirectives(path, data)
+}
+
+func parseGoModDirectives(path string, data []byte) (goModDirectives, error) {
+	file, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return goModDirectives{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	directives := goModDirectives{}
+	if file.Module != nil {
+		directives.Module = file.Module.Mod.Path
+	}
+	if file.Go != nil {
+		directives.Go = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		directives.Toolchain = file.Toolchain.Name
+	}
+	return directives, nil
+}
+
+// goReleaseLines indexes the releases by their minor version, e.g. go1.25
+type goReleaseLines struct {
+	// latest is the newest release
+	latest string
+	// oldest is the oldest supported minor version
+	oldest string
+	// patches maps each supported minor version to its newest release
+	patches map[string]string
+}
+
+func newGoReleaseLines(releases []string) goReleaseLines {
+	lines := goReleaseLines{patches: make(map[string]string)}
+	for _, release := range releases {
+		lang := version.Lang(release)
+		if version.Compare(release, lines.patches[lang]) > 0 {
+			lines.patches[lang] = release
+		}
+		if version.Compare(release, lines.latest) > 0 {
+			lines.latest = release
+		}
+		if lines.oldest == "" || version.Compare(lang, lines.oldest) < 0 {
+			lines.oldest = lang
+		}
+	}
+	return lines
+}
+
+// check classifies the Go version v. Go directives only name the minimum
+// language version, so they are outdated if a newer minor version exists.
+// Toolchains are outdated if a newer patch release of their minor version
+// exists, which fixes security issues.
+func (l goReleaseLines) check(v string, toolchain bool) (ToolchainStatus, string, bool) {
+	lang := version.Lang(v)
+	if version.Compare(lang, l.oldest) < 0 {
+		return ToolchainEOL, l.latest, true
+	}
+	if toolchain {
+		if patch := l.patches[lang]; patch != "" && version.Compare(v, patch) < 0 {
+			return ToolchainOutdated, patch, true
+		}
+		return "", "", false
+	}
+	if version.Compare(lang, version.Lang(l.latest)) < 0 {
+		return ToolchainOutdated, l.latest, true
+	}
+	return "", "", false
+}
+
+// toolchainFindings checks the directives of the project modules and the go
+// directives of the dependencies, which are only reported if end of life.
+// Findings are sorted by subject and module.
+func toolchainFindings(projects []goModDirectives, deps []Dependency, releases []string) []ToolchainFinding {
+	lines := newGoReleaseLines(releases)
+	var findings []ToolchainFinding
+	add := func(subject, module, v string, toolchain bool) {
+		if !version.IsValid(v) {
+			return
+		}
+		if status, latest, ok := lines.check(v, toolchain); ok {
+			findings = append(findings, ToolchainFinding{Subject: subject, Module: module, Version: strings.TrimPrefix(v, "go"), Status: status, Latest: strings.TrimPrefix(latest, "go")})
+		}
+	}
+
+	for _, project := range projects {
+		add(GoDirective, project.Module, "go"+project.Go, false)
+		// toolchain default means the go directive applies
+		if project.Toolchain != "" && project.Toolchain != "default" {
+			add(ToolchainDirective, project.Module, project.Toolchain, true)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, dep := range deps {
+		if dep.GoVersion == "" || seen[dep.Path] {
+			continue
+		}
+		seen[dep.Path] = true
+		if status, latest, ok := lines.check("go"+dep.GoVersion, false); ok && status == ToolchainEOL {
+			findings = append(findings, ToolchainFinding{Subject: DependencyGoVersion, Module: dep.Path, Version: dep.GoVersion, Status: status, Latest: strings.TrimPrefix(latest, "go")})
+		}
+	}
+
+	subjects := map[string]int{GoDirective: 0, ToolchainDirective: 1, DependencyGoVersion: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Subject != findings[j].Subject {
+			return subjects[findings[i].Subject] < subjects[findings[j].Subject]
+		}
+		return findings[i].Module < findings[j].Module
+	})
+	return findings
+}
+
+// setToolchainFindings checks the directives of the project modules and the
+// dependencies to scan. It runs before the dependencies are scanned, so
+// its warnings are part of the diagnostics. Failing to fetch the Go
+// releases only loses the findings.
+func (s *Scanner) setToolchainFindings(ctx context.Context, projects []goModDirectives, deps []Dependency) {
+	if !s.checkToolchain || s.quick {
+		return
+	}
+	if s.offline {
+		logger(ctx).Warn("Skipping the toolchain check, the Go releases are not available offline")
+		return
+	}
+	releases, err := s.goReleases(ctx)
+	if err != nil {
+		logger(ctx).Warn("Skipping the toolchain check", slog.Any(logKeyError, err))
+		s.warnings.add("Failed to fetch Go releases: "+warningReason(err), "go.dev")
+		return
+	}
+	s.result.ToolchainFindings = toolchainFindings(projects, deps, releases)
+}
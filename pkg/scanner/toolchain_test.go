@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolchainFindings(t *testing.T) {
+	releases := []string{"go1.25.3", "go1.24.9"}
+	projects := []goModDirectives{
+		{Module: "example.com/current", Go: "1.25", Toolchain: "go1.25.3"},
+		{Module: "example.com/behind", Go: "1.24.0", Toolchain: "go1.24.2"},
+		{Module: "example.com/old", Go: "1.21", Toolchain: "default"},
+	}
+	deps := []Dependency{
+		{Path: "github.com/a/old", GoVersion: "1.13"},
+		{Path: "github.com/a/old", GoVersion: "1.13", Module: "example.com/other"},
+		{Path: "github.com/b/supported", GoVersion: "1.24"},
+		{Path: "github.com/c/unknown"},
+	}
+
+	findings := toolchainFindings(projects, deps, releases)
+
+	assert.Equal(t, []ToolchainFinding{
+		{Subject: GoDirective, Module: "example.com/behind", Version: "1.24.0", Status: ToolchainOutdated, Latest: "1.25.3"},
+		{Subject: GoDirective, Module: "example.com/old", Version: "1.21", Status: ToolchainEOL, Latest: "1.25.3"},
+		{Subject: ToolchainDirective, Module: "example.com/behind", Version: "1.24.2", Status: ToolchainOutdated, Latest: "1.24.9"},
+		{Subject: DependencyGoVersion, Module: "github.com/a/old", Version: "1.13", Status: ToolchainEOL, Latest: "1.25.3"},
+	}, findings, "dependencies are only reported if end of life and once")
+	assert.Equal(t, "example.com/behind: toolchain directive 1.24.2 is outdated, latest is 1.24.9", findings[2].String())
+	assert.Equal(t, "github.com/a/old: go directive 1.13 is end of life, latest is 1.25.3", findings[3].String())
+}
+
+func TestSetToolchainFindings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"version":"go1.26rc1","stable":false},
+			{"version":"go1.25.3","stable":true},
+			{"version":"go1.24.9","stable":true}
+		]`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n\ntoolchain go1.22.1\n"), 0o644))
+	directives, err := readGoModDirectives(DefaultFileReader{}, dir)
+	require.NoError(t, err)
+	assert.Equal(t, goModDirectives{Module: "example.com/app", Go: "1.22", Toolchain: "go1.22.1"}, directives)
+
+	scanner := NewScanner(dir)
+	scanner.SetCheckToolchain(true)
+	scanner.goReleasesURL = server.URL
+	releases, err := scanner.goReleases(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go1.25.3", "go1.24.9"}, releases, "unstable releases are skipped")
+
+	scanner.setToolchainFindings(context.Background(), []goModDirectives{directives}, nil)
+	require.Len(t, scanner.GetResults().ToolchainFindings, 2)
+	assert.Equal(t, ToolchainEOL, scanner.GetResults().ToolchainFindings[0].Status)
+
+	scanner = NewScanner(dir)
+	scanner.SetCheckToolchain(true)
+	scanner.goReleasesURL = server.URL + "/missing"
+	scanner.setToolchainFindings(context.Background(), []goModDirectives{directives}, nil)
+	assert.Empty(t, scanner.GetResults().ToolchainFindings, "failures only lose the findings")
+}
@@ -0,0 +1,78 @@
+package scanner
+
+// moduleDependency identifies a dependency of one of the scanned modules,
+// the module is empty for single module projects
+type moduleDependency struct {
+	module string
+	path   string
+}
+
+// TransitiveRisk is the worst maintenance status among the indirect
+// dependencies a direct dependency pulls in, so the direct dependencies
+// dragging in unmaintained code stand out
+type TransitiveRisk struct {
+	// Status is the worst status of the indirect dependencies which are
+	// not acknowledged: archived, stale, error, unknown, then active
+	Status Status `json:"status"`
+	// Dependencies is the number of indirect dependencies pulled in
+	Dependencies int `json:"dependencies"`
+	// Inactive are the paths of the inactive indirect dependencies which
+	// are not acknowledged
+	Inactive []string `json:"inactive,omitempty"`
+}
+
+// statusRisk orders the statuses from the least to the most risky
+var statusRisk = map[Status]int{
+	StatusActive:   0,
+	StatusUnknown:  1,
+	StatusError:    2,
+	StatusStale:    3,
+	StatusArchived: 4,
+}
+
+// setTransitiveRisk rolls up the scanned indirect dependencies into the
+// direct dependencies pulling them in. Acknowledged dependencies don't
+// raise the risk.
+func (s *Scanner) setTransitiveRisk() {
+	if len(s.pulledIn) == 0 {
+		return
+	}
+	indirect := make(map[moduleDependency]Dependency)
+	for _, dep := range s.result.Dependencies {
+		if dep.IsIndirect {
+			indirect[moduleDependency{module: dep.Module, path: dep.Path}] = dep
+		}
+	}
+
+	for i := range s.result.Dependencies {
+		direct := &s.result.Dependencies[i]
+		if direct.IsIndirect {
+			continue
+		}
+		pulled, ok := s.pulledIn[moduleDependency{module: direct.Module, path: direct.Path}]
+		if !ok || len(pulled) == 0 {
+			continue
+		}
+
+		risk := &TransitiveRisk{Status: StatusActive}
+		for _, path := range pulled {
+			dep, scanned := indirect[moduleDependency{module: direct.Module, path: path}]
+			if !scanned {
+				continue
+			}
+			risk.Dependencies++
+			if dep.IsAcknowledged {
+				continue
+			}
+			if statusRisk[dep.Status] > statusRisk[risk.Status] {
+				risk.Status = dep.Status
+			}
+			if dep.IsInactive() {
+				risk.Inactive = append(risk.Inactive, dep.Path)
+			}
+		}
+		if risk.Dependencies > 0 {
+			direct.TransitiveRisk = risk
+		}
+	}
+}
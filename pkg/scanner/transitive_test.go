@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTransitiveRisk(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.result.Dependencies = []Dependency{
+		{Path: "github.com/a/direct", Status: StatusActive, IsActive: true},
+		{Path: "github.com/b/direct", Status: StatusActive, IsActive: true},
+		{Path: "github.com/c/direct", Status: StatusActive, IsActive: true},
+		{Path: "github.com/d/stale", Status: StatusStale, IsIndirect: true},
+		{Path: "github.com/e/archived", Status: StatusArchived, IsIndirect: true, IsAcknowledged: true},
+		{Path: "github.com/f/unknown", Status: StatusError, IsIndirect: true},
+	}
+	scanner.pulledIn = map[moduleDependency][]string{
+		{path: "github.com/a/direct"}: {"github.com/d/stale", "github.com/e/archived", "github.com/f/unknown"},
+		{path: "github.com/b/direct"}: {"github.com/e/archived", "github.com/g/unscanned"},
+		{path: "github.com/c/direct"}: nil,
+	}
+
+	scanner.setTransitiveRisk()
+
+	deps := scanner.result.Dependencies
+	assert.Equal(t, &TransitiveRisk{Status: StatusStale, Dependencies: 3, Inactive: []string{"github.com/d/stale"}}, deps[0].TransitiveRisk,
+		"acknowledged dependencies don't raise the risk")
+	assert.Equal(t, &TransitiveRisk{Status: StatusActive, Dependencies: 1}, deps[1].TransitiveRisk)
+	assert.Nil(t, deps[2].TransitiveRisk, "pulls in nothing")
+	assert.Nil(t, deps[3].TransitiveRisk, "indirect")
+}
+
+func TestSetTransitiveRiskWorkspace(t *testing.T) {
+	scanner := NewScanner(".")
+	scanner.result.Dependencies = []Dependency{
+		{Path: "github.com/a/direct", Module: "example.com/one", Status: StatusActive},
+		{Path: "github.com/a/direct", Module: "example.com/two", Status: StatusActive},
+		{Path: "github.com/d/stale", Module: "example.com/one", Status: StatusStale, IsIndirect: true},
+	}
+	scanner.pulledIn = map[moduleDependency][]string{
+		{module: "example.com/one", path: "github.com/a/direct"}: {"github.com/d/stale"},
+		{module: "example.com/two", path: "github.com/a/direct"}: {"github.com/d/stale"},
+	}
+
+	scanner.setTransitiveRisk()
+
+	require.NotNil(t, scanner.result.Dependencies[0].TransitiveRisk)
+	assert.Equal(t, StatusStale, scanner.result.Dependencies[0].TransitiveRisk.Status)
+	assert.Nil(t, scanner.result.Dependencies[1].TransitiveRisk, "the other module doesn't scan the indirect dependency")
+}
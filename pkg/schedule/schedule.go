@@ -0,0 +1,150 @@
+// Package schedule computes the run times of cron schedules
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the next run time after the given time
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// maxLookahead bounds the search for the next run of schedules which never
+// match, like the 31st of February
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a standard five field cron expression (minute, hour, day of
+// month, month, day of week) with lists, ranges and steps, one of the
+// macros like @daily, or @every followed by a duration like @every 6h
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || every < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q, expected a duration of at least 1m", spec)
+		}
+		return Every(every), nil
+	}
+	if expanded, ok := macros[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, expected 5 fields: minute hour day-of-month month day-of-week", spec)
+	}
+	bounds := []struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, field := range fields {
+		set, err := parseField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		sets[i] = set
+	}
+	// Sunday is 0 or 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &cron{
+		minutes: sets[0], hours: sets[1], daysOfMonth: sets[2], months: sets[3], daysOfWeek: sets[4],
+		anyDayOfMonth: fields[2] == "*", anyDayOfWeek: fields[4] == "*",
+	}, nil
+}
+
+// parseField expands a field like "*/15", "1-5" or "0,30" into its values
+func parseField(field string, low, high int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		from, to := low, high
+		if rangeSpec != "*" {
+			start, end, isRange := strings.Cut(rangeSpec, "-")
+			var err error
+			if from, err = strconv.Atoi(start); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(end); err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				to = high
+			}
+		}
+		if from < low || to > high || from > to {
+			return nil, fmt.Errorf("value %q out of range %d-%d", part, low, high)
+		}
+		for value := from; value <= to; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+type cron struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek map[int]bool
+	// Like in cron a day matches either restricted day field if both are
+	// restricted
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// Next returns the first matching minute after the given time, or the zero
+// time if there is none within five years
+func (c *cron) Next(after time.Time) time.Time {
+	next := after.Truncate(time.Minute).Add(time.Minute)
+	for limit := after.Add(maxLookahead); next.Before(limit); {
+		switch {
+		case !c.months[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !c.hours[next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !c.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cron) matchesDay(t time.Time) bool {
+	dayOfMonth, dayOfWeek := c.daysOfMonth[t.Day()], c.daysOfWeek[int(t.Weekday())]
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// Every is a fixed interval schedule
+type Every time.Duration
+
+// Next returns the time one interval after the given time
+func (e Every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
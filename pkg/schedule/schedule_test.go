@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// A Monday
+	start := time.Date(2024, 1, 1, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 6 * * *", time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches if both are restricted
+		{"0 0 13 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", start.Add(6 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(start))
+		})
+	}
+}
+
+func TestNextNeverMatches(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 10s", "@every soon"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,155 @@
+package score
+
+import "math"
+
+const (
+	// freshDays is the age up to which the recency signal is perfect
+	freshDays = 30
+	// abandonedDays is the age from which the recency signal is zero
+	abandonedDays = 730
+	// healthyReleasesPerYear is the release count for a perfect cadence signal
+	healthyReleasesPerYear = 4
+	// healthyContributors is the contributor count for a perfect signal
+	healthyContributors = 5
+	// promptResponseHours is the median issue response time up to which
+	// the issues signal is perfect
+	promptResponseHours = 48
+	// ignoredResponseHours is the median issue response time from which the
+	// issues signal is zero
+	ignoredResponseHours = 30 * 24
+	// archivedMaxScore caps the score of archived repositories, no matter
+	// how good the remaining signals look
+	archivedMaxScore = 20
+)
+
+// Weights configures how much each signal contributes to the score. The
+// weights are relative to each other and don't need to add up to one.
+type Weights struct {
+	Recency      float64 `mapstructure:"recency"`
+	Cadence      float64 `mapstructure:"cadence"`
+	Archived     float64 `mapstructure:"archived"`
+	Issues       float64 `mapstructure:"issues"`
+	Contributors float64 `mapstructure:"contributors"`
+	Security     float64 `mapstructure:"security"`
+}
+
+// DefaultWeights returns the weights used if none are configured
+func DefaultWeights() Weights {
+	return Weights{
+		Recency:      0.4,
+		Cadence:      0.2,
+		Archived:     0.2,
+		Issues:       0.1,
+		Contributors: 0.1,
+		Security:     0.1,
+	}
+}
+
+// Signals are the inputs of the health score. Nil signals are unknown and
+// their weight is distributed over the known signals.
+type Signals struct {
+	// DaysSinceLastActivity is the age of the most recent release or commit
+	DaysSinceLastActivity *int
+	// ReleasesLastYear is the number of releases within the last 365 days
+	ReleasesLastYear *int
+	// Archived is whether the repository is archived
+	Archived *bool
+	// IssueResponse rates how maintainers react to new issues, from 0
+	// (ignored) to 1 (answered promptly), see ResponseSpeed
+	IssueResponse *float64
+	// Contributors is the number of distinct recent contributors
+	Contributors *int
+	// Security is the share of the security practices, like a security
+	// policy or signed releases, the repository follows, from 0 to 1
+	Security *float64
+}
+
+// Engine computes health scores with a fixed set of weights
+type Engine struct {
+	weights Weights
+}
+
+// NewEngine creates a scoring engine. Negative weights are treated as zero.
+func NewEngine(weights Weights) *Engine {
+	return &Engine{weights: Weights{
+		Recency:      math.Max(weights.Recency, 0),
+		Cadence:      math.Max(weights.Cadence, 0),
+		Archived:     math.Max(weights.Archived, 0),
+		Issues:       math.Max(weights.Issues, 0),
+		Contributors: math.Max(weights.Contributors, 0),
+		Security:     math.Max(weights.Security, 0),
+	}}
+}
+
+// Score returns the health score from 0 (unhealthy) to 100 (healthy). The
+// second return value is false if no weighted signal is known.
+func (e *Engine) Score(signals Signals) (int, bool) {
+	var total, weightSum float64
+	add := func(weight, value float64) {
+		total += weight * value
+		weightSum += weight
+	}
+
+	if signals.DaysSinceLastActivity != nil {
+		add(e.weights.Recency, recency(*signals.DaysSinceLastActivity))
+	}
+	if signals.ReleasesLastYear != nil {
+		add(e.weights.Cadence, ratio(float64(*signals.ReleasesLastYear), healthyReleasesPerYear))
+	}
+	if signals.Archived != nil {
+		value := 1.0
+		if *signals.Archived {
+			value = 0
+		}
+		add(e.weights.Archived, value)
+	}
+	if signals.IssueResponse != nil {
+		add(e.weights.Issues, ratio(*signals.IssueResponse, 1))
+	}
+	if signals.Contributors != nil {
+		add(e.weights.Contributors, ratio(float64(*signals.Contributors), healthyContributors))
+	}
+	if signals.Security != nil {
+		add(e.weights.Security, ratio(*signals.Security, 1))
+	}
+
+	if weightSum == 0 {
+		return 0, false
+	}
+
+	score := int(math.Round(total / weightSum * 100))
+	if signals.Archived != nil && *signals.Archived {
+		score = min(score, archivedMaxScore)
+	}
+	return score, true
+}
+
+// recency decays linearly from 1 at freshDays to 0 at abandonedDays
+func recency(days int) float64 {
+	switch {
+	case days <= freshDays:
+		return 1
+	case days >= abandonedDays:
+		return 0
+	default:
+		return 1 - float64(days-freshDays)/float64(abandonedDays-freshDays)
+	}
+}
+
+// ResponseSpeed rates the median time until maintainers respond to an
+// issue: 1 up to two days, decaying linearly to 0 at thirty days
+func ResponseSpeed(medianHours int) float64 {
+	switch {
+	case medianHours <= promptResponseHours:
+		return 1
+	case medianHours >= ignoredResponseHours:
+		return 0
+	default:
+		return 1 - float64(medianHours-promptResponseHours)/float64(ignoredResponseHours-promptResponseHours)
+	}
+}
+
+// ratio returns value/target capped to the range [0, 1]
+func ratio(value, target float64) float64 {
+	return math.Min(math.Max(value/target, 0), 1)
+}
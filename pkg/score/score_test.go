@@ -0,0 +1,127 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int           { return &v }
+func boolPtr(v bool) *bool        { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		signals       Signals
+		expectedScore int
+		expectedKnown bool
+	}{
+		{
+			name:          "no signals",
+			signals:       Signals{},
+			expectedScore: 0,
+			expectedKnown: false,
+		},
+		{
+			name:          "fresh release only",
+			signals:       Signals{DaysSinceLastActivity: intPtr(10)},
+			expectedScore: 100,
+			expectedKnown: true,
+		},
+		{
+			name:          "abandoned release only",
+			signals:       Signals{DaysSinceLastActivity: intPtr(1000)},
+			expectedScore: 0,
+			expectedKnown: true,
+		},
+		{
+			name: "recent but rarely released",
+			signals: Signals{
+				DaysSinceLastActivity: intPtr(30),
+				ReleasesLastYear:      intPtr(1),
+			},
+			// (0.4*1 + 0.2*0.25) / 0.6
+			expectedScore: 75,
+			expectedKnown: true,
+		},
+		{
+			name: "all signals healthy",
+			signals: Signals{
+				DaysSinceLastActivity: intPtr(5),
+				ReleasesLastYear:      intPtr(12),
+				Archived:              boolPtr(false),
+				IssueResponse:         floatPtr(1),
+				Contributors:          intPtr(20),
+			},
+			expectedScore: 100,
+			expectedKnown: true,
+		},
+		{
+			name: "archived is capped",
+			signals: Signals{
+				DaysSinceLastActivity: intPtr(5),
+				ReleasesLastYear:      intPtr(12),
+				Archived:              boolPtr(true),
+			},
+			expectedScore: archivedMaxScore,
+			expectedKnown: true,
+		},
+		{
+			name: "recent without security practices",
+			signals: Signals{
+				DaysSinceLastActivity: intPtr(5),
+				Security:              floatPtr(0),
+			},
+			// (0.4*1 + 0.1*0) / 0.5
+			expectedScore: 80,
+			expectedKnown: true,
+		},
+	}
+
+	engine := NewEngine(DefaultWeights())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, known := engine.Score(tt.signals)
+
+			assert.Equal(t, tt.expectedScore, score)
+			assert.Equal(t, tt.expectedKnown, known)
+		})
+	}
+}
+
+func TestScoreCustomWeights(t *testing.T) {
+	signals := Signals{
+		DaysSinceLastActivity: intPtr(10),
+		ReleasesLastYear:      intPtr(0),
+	}
+
+	onlyRecency := NewEngine(Weights{Recency: 1})
+	score, known := onlyRecency.Score(signals)
+	assert.True(t, known)
+	assert.Equal(t, 100, score)
+
+	onlyCadence := NewEngine(Weights{Cadence: 1, Recency: -5})
+	score, known = onlyCadence.Score(signals)
+	assert.True(t, known)
+	assert.Equal(t, 0, score)
+
+	noWeights := NewEngine(Weights{})
+	_, known = noWeights.Score(signals)
+	assert.False(t, known)
+}
+
+func TestRecency(t *testing.T) {
+	assert.InDelta(t, 1.0, recency(0), 0.001)
+	assert.InDelta(t, 1.0, recency(freshDays), 0.001)
+	assert.InDelta(t, 0.5, recency((freshDays+abandonedDays)/2), 0.001)
+	assert.InDelta(t, 0.0, recency(abandonedDays), 0.001)
+}
+
+func TestResponseSpeed(t *testing.T) {
+	assert.Equal(t, 1.0, ResponseSpeed(0))
+	assert.Equal(t, 1.0, ResponseSpeed(promptResponseHours))
+	assert.InDelta(t, 0.5, ResponseSpeed((promptResponseHours+ignoredResponseHours)/2), 0.001)
+	assert.Equal(t, 0.0, ResponseSpeed(ignoredResponseHours))
+	assert.Equal(t, 0.0, ResponseSpeed(10000))
+}
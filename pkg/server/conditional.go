@@ -0,0 +1,72 @@
+// Package server contains the HTTP API of govital
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WriteJSON writes value as JSON response with an ETag and, if lastModified
+// is set, a Last-Modified header. Requests whose If-None-Match or
+// If-Modified-Since match the current representation get a 304 without
+// body, so clients polling unchanged results don't transfer them again.
+func WriteJSON(w http.ResponseWriter, r *http.Request, value any, lastModified time.Time) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// notModified evaluates the conditional request headers. If-None-Match
+// takes precedence over If-Modified-Since, like RFC 9110 requires.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// HTTP dates have a resolution of seconds
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	value := map[string]int{"total": 3}
+
+	recorder := httptest.NewRecorder()
+	WriteJSON(recorder, httptest.NewRequest(http.MethodGet, "/results/a", nil), value, modified)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"total":3}`, recorder.Body.String())
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", recorder.Header().Get("Last-Modified"))
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	t.Run("matching etag", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/results/a", nil)
+		request.Header.Set("If-None-Match", `"other", W/`+etag)
+		recorder := httptest.NewRecorder()
+		WriteJSON(recorder, request, value, modified)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+		assert.Equal(t, etag, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("changed value", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/results/a", nil)
+		request.Header.Set("If-None-Match", etag)
+		recorder := httptest.NewRecorder()
+		WriteJSON(recorder, request, map[string]int{"total": 4}, modified)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotEqual(t, etag, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("if-none-match wins over if-modified-since", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/results/a", nil)
+		request.Header.Set("If-None-Match", `"other"`)
+		request.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+		recorder := httptest.NewRecorder()
+		WriteJSON(recorder, request, value, modified)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("not modified since", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/results/a", nil)
+		request.Header.Set("If-Modified-Since", modified.Add(time.Minute).Format(http.TimeFormat))
+		recorder := httptest.NewRecorder()
+		WriteJSON(recorder, request, value, modified.Add(500*time.Millisecond))
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+	})
+
+	t.Run("modified since", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/results/a", nil)
+		request.Header.Set("If-Modified-Since", modified.Add(-time.Minute).Format(http.TimeFormat))
+		recorder := httptest.NewRecorder()
+		WriteJSON(recorder, request, value, modified)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/badge"
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/steffakasid/govital/pkg/metrics"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"golang.org/x/mod/module"
+)
+
+// maxRequestSize limits the body of scan requests
+const maxRequestSize = 1 << 20
+
+// maxPageSize bounds the ?limit= of GET /results
+const maxPageSize = 1000
+
+// Server is the REST API submitting scans to a job queue:
+//
+//	POST   /scan          submit a scan, answered with 202 and the queued job
+//	GET    /results       list the jobs, newest first, paged with ?offset=
+//	                      and ?limit=, default 100
+//	GET    /results/{id}  get a job with its ScanResult once it succeeded
+//	DELETE /results/{id}  cancel a queued or running job
+//	GET    /metrics       Prometheus metrics of the latest scan of each project
+//	GET    /badge         SVG health badge of the latest scan of ?project=,
+//	                      showing inactive dependencies or the ?kind=grade
+//
+// With a token all endpoints but the badge, which READMEs embed as image,
+// require it as bearer token.
+type Server struct {
+	queue *jobs.Queue
+	mux   *http.ServeMux
+	token string
+}
+
+// NewServer creates the API for the queue
+func NewServer(queue *jobs.Queue) *Server {
+	s := &Server{queue: queue, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /scan", s.submit)
+	s.mux.HandleFunc("GET /results", s.list)
+	s.mux.HandleFunc("GET /results/{id}", s.get)
+	s.mux.HandleFunc("DELETE /results/{id}", s.cancel)
+	s.mux.HandleFunc("GET /metrics", s.metrics)
+	s.mux.HandleFunc("GET /badge", s.badge)
+	return s
+}
+
+// SetToken requires the bearer token for all requests but GET /badge,
+// empty allows all requests
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.URL.Path != "/badge" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="govital"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether the request carries the bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) == 1
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	var request jobs.Request
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid scan request: %w", err))
+		return
+	}
+	if err := ValidateRequest(request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := s.queue.Submit(request)
+	switch {
+	case errors.Is(err, jobs.ErrQueueFull):
+		w.Header().Set("Retry-After", "30")
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	w.Header().Set("Location", "/results/"+job.ID)
+	writeStatus(w, http.StatusAccepted, job)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := queryInt(r, "limit", jobs.DefaultPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	WriteJSON(w, r, s.queue.Page(offset, min(limit, maxPageSize)), time.Time{})
+}
+
+// queryInt returns the non-negative integer query parameter, fallback if
+// it is missing
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a non-negative number", name, value)
+	}
+	return number, nil
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	job, err := s.queue.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	WriteJSON(w, r, job, lastModified(job))
+}
+
+func (s *Server) cancel(w http.ResponseWriter, r *http.Request) {
+	job, err := s.queue.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeStatus(w, http.StatusOK, job)
+}
+
+// metrics exposes the latest successful scan of each project. Scans of
+// module lists are ad hoc lookups and left out.
+func (s *Server) metrics(w http.ResponseWriter, r *http.Request) {
+	var snapshots []metrics.Snapshot
+	seen := make(map[string]bool)
+	states := make(map[jobs.State]int)
+	// List returns the newest jobs first
+	for _, job := range s.queue.List() {
+		states[job.State]++
+		if job.State != jobs.Succeeded || job.Request.ProjectPath == "" || seen[job.Request.ProjectPath] {
+			continue
+		}
+		seen[job.Request.ProjectPath] = true
+		snapshots = append(snapshots, metrics.Snapshot{Project: job.Request.ProjectPath, ScannedAt: job.Finished, Result: job.Result})
+	}
+
+	jobGauge := metrics.Gauge{Name: "govital_scan_jobs", Help: "Number of scan jobs by state."}
+	for _, state := range []jobs.State{jobs.Queued, jobs.Running, jobs.Succeeded, jobs.Failed, jobs.Cancelled} {
+		jobGauge.Samples = append(jobGauge.Samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "state", Value: string(state)}},
+			Value:  float64(states[state]),
+		})
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_ = metrics.WriteGauges(w, append(metrics.ResultGauges(snapshots), jobGauge))
+}
+
+// badge renders the badge of the latest successful scan of the project
+func (s *Server) badge(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+	if project == "" {
+		writeError(w, http.StatusBadRequest, errors.New("the project query parameter is required"))
+		return
+	}
+	kind := badge.Inactive
+	if value := r.URL.Query().Get("kind"); value != "" {
+		var err error
+		if kind, err = badge.ParseKind(value); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// List returns the newest jobs first
+	for _, job := range s.queue.List() {
+		if job.State != jobs.Succeeded || job.Request.ProjectPath != project {
+			continue
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		// README renderers like GitHub's camo proxy must not keep stale badges
+		w.Header().Set("Cache-Control", "no-cache")
+		_ = badge.ForResult(job.Result, kind, r.URL.Query().Get("label")).WriteSVG(w)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("no successful scan of %s", project))
+}
+
+// ValidateRequest checks that the request selects either a project path or
+// a list of valid module versions
+func ValidateRequest(request jobs.Request) error {
+	if (request.ProjectPath == "") == (len(request.Modules) == 0) {
+		return errors.New("either project_path or modules is required")
+	}
+	_, err := ParseModules(request.Modules)
+	return err
+}
+
+// ParseModules converts module versions like github.com/org/repo@v1.2.0
+// into dependencies to scan
+func ParseModules(specs []string) ([]scanner.Dependency, error) {
+	deps := make([]scanner.Dependency, 0, len(specs))
+	for _, spec := range specs {
+		path, version, ok := strings.Cut(spec, "@")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid module %q, expected <module>@<version>", spec)
+		}
+		if err := module.Check(path, version); err != nil {
+			return nil, fmt.Errorf("invalid module %q: %w", spec, err)
+		}
+		deps = append(deps, scanner.Dependency{Path: path, Version: version, IsActive: true})
+	}
+	return deps, nil
+}
+
+// lastModified is the time of the latest state change of the job
+func lastModified(job jobs.Job) time.Time {
+	switch {
+	case !job.Finished.IsZero():
+		return job.Finished
+	case !job.Started.IsZero():
+		return job.Started
+	default:
+		return job.Created
+	}
+}
+
+// writeStatus writes value as JSON response with the given status code
+func writeStatus(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes err as JSON object with an error field
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeStatus(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, run jobs.RunFunc) *httptest.Server {
+	t.Helper()
+	queue := jobs.NewQueue(1, 1, run)
+	server := httptest.NewServer(NewServer(queue))
+	t.Cleanup(func() {
+		server.Close()
+		queue.Close()
+	})
+	return server
+}
+
+func postScan(t *testing.T, server *httptest.Server, body string) (*http.Response, jobs.Job) {
+	t.Helper()
+	response, err := http.Post(server.URL+"/scan", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	var job jobs.Job
+	_ = json.NewDecoder(response.Body).Decode(&job)
+	return response, job
+}
+
+func TestServerScan(t *testing.T) {
+	server := newTestServer(t, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{ProjectPath: request.ProjectPath, Summary: scanner.Summary{Total: len(request.Modules)}}, nil
+	})
+
+	response, job := postScan(t, server, `{"modules":["github.com/example/mod@v1.0.0"]}`)
+	require.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.Equal(t, "/results/"+job.ID, response.Header.Get("Location"))
+
+	require.Eventually(t, func() bool {
+		response, err := http.Get(server.URL + "/results/" + job.ID)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&job))
+		return job.State == jobs.Succeeded
+	}, 2*time.Second, 5*time.Millisecond)
+	require.NotNil(t, job.Result)
+	assert.Equal(t, 1, job.Result.Summary.Total)
+
+	response, err := http.Get(server.URL + "/results")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	var list []jobs.Job
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&list))
+	assert.Len(t, list, 1)
+
+	response, err = http.Get(server.URL + "/results?offset=1&limit=10")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&list))
+	assert.Empty(t, list)
+
+	response, err = http.Get(server.URL + "/results?limit=-1")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestServerErrors(t *testing.T) {
+	release := make(chan struct{})
+	server := newTestServer(t, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		<-release
+		return &scanner.ScanResult{}, nil
+	})
+	defer close(release)
+
+	for _, body := range []string{`{}`, `{"project_path":".","modules":["a@v1.0.0"]}`, `{"modules":["example.com/mod"]}`, `{"unknown":1}`} {
+		response, _ := postScan(t, server, body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, body)
+	}
+
+	response, err := http.Get(server.URL + "/results/missing")
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	// One job runs, one waits, the third is rejected
+	postScan(t, server, `{"project_path":"/a"}`)
+	postScan(t, server, `{"project_path":"/b"}`)
+	require.Eventually(t, func() bool {
+		response, _ := postScan(t, server, `{"project_path":"/c"}`)
+		return response.StatusCode == http.StatusServiceUnavailable && response.Header.Get("Retry-After") != ""
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestServerToken(t *testing.T) {
+	queue := jobs.NewQueue(1, 1, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{}, nil
+	})
+	defer queue.Close()
+	api := NewServer(queue)
+	api.SetToken("secret")
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	get := func(path, authorization string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		response.Body.Close()
+		return response
+	}
+
+	for _, authorization := range []string{"", "Bearer wrong", "Basic secret"} {
+		response := get("/results", authorization)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode, authorization)
+		assert.Equal(t, `Bearer realm="govital"`, response.Header.Get("WWW-Authenticate"))
+	}
+	assert.Equal(t, http.StatusOK, get("/results", "Bearer secret").StatusCode)
+	assert.Equal(t, http.StatusOK, get("/metrics", "bearer secret").StatusCode)
+	// Badges are embedded as images, which can't send the token
+	assert.Equal(t, http.StatusNotFound, get("/badge?project=/src/app", "").StatusCode)
+}
+
+func TestServerCancel(t *testing.T) {
+	server := newTestServer(t, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	_, job := postScan(t, server, `{"project_path":"/a"}`)
+
+	request, err := http.NewRequest(http.MethodDelete, server.URL+"/results/"+job.ID, nil)
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestParseModules(t *testing.T) {
+	deps, err := ParseModules([]string{"github.com/example/mod@v1.2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []scanner.Dependency{{Path: "github.com/example/mod", Version: "v1.2.0", IsActive: true}}, deps)
+
+	_, err = ParseModules([]string{"github.com/example/mod@latest"})
+	assert.Error(t, err, "queries are not resolved")
+}
+
+func TestServerMetrics(t *testing.T) {
+	server := newTestServer(t, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{Summary: scanner.Summary{Total: 3, Inactive: 1}}, nil
+	})
+	_, job := postScan(t, server, `{"project_path":"/src/app"}`)
+
+	require.Eventually(t, func() bool {
+		response, err := http.Get(server.URL + "/results/" + job.ID)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&job))
+		return job.State == jobs.Succeeded
+	}, 2*time.Second, 5*time.Millisecond)
+
+	response, err := http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, response.Header.Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, string(body), `govital_dependencies_inactive{project="/src/app"} 1`)
+	assert.Contains(t, string(body), `govital_scan_jobs{state="succeeded"} 1`)
+}
+
+func TestServerBadge(t *testing.T) {
+	server := newTestServer(t, func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{Summary: scanner.Summary{Total: 3, Inactive: 1}}, nil
+	})
+	_, job := postScan(t, server, `{"project_path":"/src/app"}`)
+
+	require.Eventually(t, func() bool {
+		response, err := http.Get(server.URL + "/results/" + job.ID)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&job))
+		return job.State == jobs.Succeeded
+	}, 2*time.Second, 5*time.Millisecond)
+
+	response, err := http.Get(server.URL + "/badge?project=/src/app")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "image/svg+xml", response.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "<title>deps: 1 inactive</title>")
+
+	for query, status := range map[string]int{
+		"":                             http.StatusBadRequest,
+		"?project=/src/app&kind=stars": http.StatusBadRequest,
+		"?project=/src/other":          http.StatusNotFound,
+		"?project=/src/app&kind=grade": http.StatusOK,
+	} {
+		response, err := http.Get(server.URL + "/badge" + query)
+		require.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, status, response.StatusCode, query)
+	}
+}
@@ -0,0 +1,215 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/jobs"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	jobsBucket    = []byte("jobs")
+	historyBucket = []byte("history")
+)
+
+// boltStore keeps jobs by ID and records by an increasing sequence as JSON
+// documents. BoltDB locks the file, so only one process can use it.
+type boltStore struct {
+	db   *bolt.DB
+	path string
+}
+
+func openBolt(path string) (*boltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	// Fail instead of waiting forever if another process holds the lock
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt storage %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{jobsBucket, historyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt buckets: %w", err)
+	}
+	return &boltStore{db: db, path: path}, nil
+}
+
+func (s *boltStore) SaveJob(_ context.Context, job jobs.Job) error {
+	document, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), document)
+	})
+}
+
+func (s *boltStore) Job(_ context.Context, id string) (jobs.Job, error) {
+	var job jobs.Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		document := tx.Bucket(jobsBucket).Get([]byte(id))
+		if document == nil {
+			return fmt.Errorf("%w: %s", jobs.ErrNotFound, id)
+		}
+		return json.Unmarshal(document, &job)
+	})
+	return job, err
+}
+
+func (s *boltStore) Jobs(_ context.Context, offset, limit int) ([]jobs.Job, error) {
+	list, err := s.jobs()
+	return pageJobs(list, offset, limit), err
+}
+
+func (s *boltStore) PruneJobs(_ context.Context, keep int, finishedBefore time.Time) (int, error) {
+	list, err := s.jobs()
+	if err != nil {
+		return 0, err
+	}
+	expired := expiredJobs(list, keep, finishedBefore)
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(jobsBucket)
+		for _, job := range expired {
+			if err := bucket.Delete([]byte(job.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	return len(expired), nil
+}
+
+// jobs returns all jobs, newest first
+func (s *boltStore) jobs() ([]jobs.Job, error) {
+	var list []jobs.Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, document []byte) error {
+			var job jobs.Job
+			if err := json.Unmarshal(document, &job); err != nil {
+				return fmt.Errorf("invalid job: %w", err)
+			}
+			list = append(list, job)
+			return nil
+		})
+	})
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].Created.After(list[k].Created)
+	})
+	return list, err
+}
+
+func (s *boltStore) Append(_ context.Context, record history.Record) error {
+	document, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket)
+		sequence, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(sequenceKey(sequence), document)
+	})
+}
+
+func (s *boltStore) Load(_ context.Context, project string) ([]history.Record, error) {
+	all, _, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	var records []history.Record
+	for _, record := range all {
+		if record.Project == project {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ScannedAt.Before(records[j].ScannedAt)
+	})
+	return records, nil
+}
+
+func (s *boltStore) Prune(_ context.Context, retention history.Retention) (history.PruneResult, error) {
+	records, keys, err := s.records()
+	if err != nil {
+		return history.PruneResult{}, err
+	}
+	expired := history.Expired(records, retention, now())
+	result := history.PruneResult{Kept: len(records) - len(expired), Removed: len(expired)}
+	if len(expired) == 0 {
+		return result, nil
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket)
+		for _, index := range expired {
+			if err := bucket.Delete(keys[index]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return history.PruneResult{}, fmt.Errorf("failed to prune history: %w", err)
+	}
+	return result, nil
+}
+
+// records returns all records in the order they were appended with their
+// keys
+func (s *boltStore) records() ([]history.Record, [][]byte, error) {
+	var records []history.Record
+	var keys [][]byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyBucket).ForEach(func(key, document []byte) error {
+			var record history.Record
+			if err := json.Unmarshal(document, &record); err != nil {
+				return fmt.Errorf("invalid history record: %w", err)
+			}
+			records = append(records, record)
+			// Keys are only valid during the transaction
+			keys = append(keys, append([]byte(nil), key...))
+			return nil
+		})
+	})
+	return records, keys, err
+}
+
+// sequenceKey encodes the sequence big-endian, so keys sort in the order
+// records were appended
+func sequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *boltStore) Location() string {
+	return s.path
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/jobs"
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// fileStore is the history file. It keeps no jobs, the queue of a single
+// server holds them in memory.
+type fileStore struct {
+	path    string
+	history *history.Store
+}
+
+func newFileStore(path string) *fileStore {
+	return &fileStore{path: path, history: history.NewStore(path)}
+}
+
+func (s *fileStore) SaveJob(context.Context, jobs.Job) error {
+	return nil
+}
+
+func (s *fileStore) Job(_ context.Context, id string) (jobs.Job, error) {
+	return jobs.Job{}, fmt.Errorf("%w: %s", jobs.ErrNotFound, id)
+}
+
+func (s *fileStore) Jobs(context.Context, int, int) ([]jobs.Job, error) {
+	return nil, nil
+}
+
+func (s *fileStore) PruneJobs(context.Context, int, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *fileStore) Append(_ context.Context, record history.Record) error {
+	return s.history.Append(record)
+}
+
+func (s *fileStore) Load(_ context.Context, project string) ([]history.Record, error) {
+	return s.history.Load(project)
+}
+
+func (s *fileStore) Prune(_ context.Context, retention history.Retention) (history.PruneResult, error) {
+	return s.history.Prune(retention)
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+func (s *fileStore) Location() string {
+	return s.path
+}
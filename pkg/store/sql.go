@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/jobs"
+	_ "modernc.org/sqlite"
+)
+
+// dialect holds the differences of the SQL databases
+type dialect struct {
+	name   string
+	driver string
+	// serial is the column type of auto-incremented IDs
+	serial string
+	// numbered is set for databases using $1 placeholders instead of ?
+	numbered bool
+	// file is set for databases stored in a local file
+	file bool
+}
+
+var (
+	sqliteDialect   = dialect{name: TypeSQLite, driver: "sqlite", serial: "INTEGER PRIMARY KEY AUTOINCREMENT", file: true}
+	postgresDialect = dialect{name: TypePostgres, driver: "pgx", serial: "BIGSERIAL PRIMARY KEY", numbered: true}
+)
+
+// bind replaces the ? placeholders of the query for the dialect
+func (d dialect) bind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var builder strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// sqlStore keeps jobs and records as JSON documents. The columns besides
+// the documents are only used to select and order them. Times are stored
+// as Unix nanoseconds, which all databases compare the same way.
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+	dsn     string
+}
+
+func openSQL(ctx context.Context, d dialect, dsn string) (*sqlStore, error) {
+	if d.file {
+		if err := os.MkdirAll(filepath.Dir(dsn), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s storage: %w", d.name, err)
+	}
+	if d.file {
+		// SQLite allows a single writer, concurrent connections would fail
+		// with SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+
+	s := &sqlStore{db: db, dialect: d, dsn: dsn}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *sqlStore) migrate(ctx context.Context) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS govital_jobs (id TEXT PRIMARY KEY, created BIGINT NOT NULL, job TEXT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS govital_history (id ` + s.dialect.serial + `, project TEXT NOT NULL, scanned_at BIGINT NOT NULL, record TEXT NOT NULL)`,
+		`CREATE INDEX IF NOT EXISTS govital_history_project ON govital_history (project, scanned_at)`,
+	} {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create %s tables: %w", s.dialect.name, err)
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) SaveJob(ctx context.Context, job jobs.Job) error {
+	document, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	_, err = s.db.ExecContext(ctx, s.dialect.bind(
+		`INSERT INTO govital_jobs (id, created, job) VALUES (?, ?, ?) ON CONFLICT (id) DO UPDATE SET job = excluded.job`),
+		job.ID, job.Created.UnixNano(), string(document))
+	if err != nil {
+		return fmt.Errorf("failed to store job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *sqlStore) Job(ctx context.Context, id string) (jobs.Job, error) {
+	var document string
+	err := s.db.QueryRowContext(ctx, s.dialect.bind(`SELECT job FROM govital_jobs WHERE id = ?`), id).Scan(&document)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobs.Job{}, fmt.Errorf("%w: %s", jobs.ErrNotFound, id)
+	}
+	if err != nil {
+		return jobs.Job{}, fmt.Errorf("failed to load job %s: %w", id, err)
+	}
+	var job jobs.Job
+	if err := json.Unmarshal([]byte(document), &job); err != nil {
+		return jobs.Job{}, fmt.Errorf("invalid job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+func (s *sqlStore) Jobs(ctx context.Context, offset, limit int) ([]jobs.Job, error) {
+	query, args := `SELECT job FROM govital_jobs ORDER BY created DESC`, []any{}
+	if limit > 0 {
+		query, args = query+` LIMIT ? OFFSET ?`, []any{limit, max(offset, 0)}
+	}
+	rows, err := s.db.QueryContext(ctx, s.dialect.bind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var list []jobs.Job
+	for rows.Next() {
+		var document string
+		if err := rows.Scan(&document); err != nil {
+			return nil, fmt.Errorf("failed to list jobs: %w", err)
+		}
+		var job jobs.Job
+		if err := json.Unmarshal([]byte(document), &job); err != nil {
+			return nil, fmt.Errorf("invalid job: %w", err)
+		}
+		list = append(list, job)
+	}
+	if limit == 0 {
+		// SQLite has no OFFSET without LIMIT
+		list = pageJobs(list, offset, 0)
+	}
+	return list, rows.Err()
+}
+
+// PruneJobs selects the expired jobs from the documents, the finish time
+// has no column. The jobs are bounded by the retention, so there are few.
+func (s *sqlStore) PruneJobs(ctx context.Context, keep int, finishedBefore time.Time) (int, error) {
+	list, err := s.Jobs(ctx, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, job := range expiredJobs(list, keep, finishedBefore) {
+		result, err := s.db.ExecContext(ctx, s.dialect.bind(`DELETE FROM govital_jobs WHERE id = ?`), job.ID)
+		if err != nil {
+			return removed, fmt.Errorf("failed to prune jobs: %w", err)
+		}
+		if count, err := result.RowsAffected(); err == nil {
+			removed += int(count)
+		}
+	}
+	return removed, nil
+}
+
+func (s *sqlStore) Append(ctx context.Context, record history.Record) error {
+	document, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, s.dialect.bind(`INSERT INTO govital_history (project, scanned_at, record) VALUES (?, ?, ?)`),
+		record.Project, record.ScannedAt.UnixNano(), string(document))
+	if err != nil {
+		return fmt.Errorf("failed to record scan of %s: %w", record.Project, err)
+	}
+	return nil
+}
+
+func (s *sqlStore) Load(ctx context.Context, project string) ([]history.Record, error) {
+	records, _, err := s.records(ctx, s.dialect.bind(`SELECT id, record FROM govital_history WHERE project = ? ORDER BY scanned_at, id`), project)
+	return records, err
+}
+
+func (s *sqlStore) Prune(ctx context.Context, retention history.Retention) (history.PruneResult, error) {
+	records, ids, err := s.records(ctx, `SELECT id, record FROM govital_history ORDER BY id`)
+	if err != nil {
+		return history.PruneResult{}, err
+	}
+	expired := history.Expired(records, retention, now())
+	result := history.PruneResult{Kept: len(records) - len(expired), Removed: len(expired)}
+	if len(expired) == 0 {
+		return result, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return history.PruneResult{}, fmt.Errorf("failed to prune history: %w", err)
+	}
+	defer tx.Rollback()
+	for _, index := range expired {
+		if _, err := tx.ExecContext(ctx, s.dialect.bind(`DELETE FROM govital_history WHERE id = ?`), ids[index]); err != nil {
+			return history.PruneResult{}, fmt.Errorf("failed to prune history: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return history.PruneResult{}, fmt.Errorf("failed to prune history: %w", err)
+	}
+	return result, nil
+}
+
+// records returns the records selected by the query with their IDs
+func (s *sqlStore) records(ctx context.Context, query string, args ...any) ([]history.Record, []int64, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []history.Record
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var document string
+		if err := rows.Scan(&id, &document); err != nil {
+			return nil, nil, fmt.Errorf("failed to load history: %w", err)
+		}
+		var record history.Record
+		if err := json.Unmarshal([]byte(document), &record); err != nil {
+			return nil, nil, fmt.Errorf("invalid history record %d: %w", id, err)
+		}
+		records = append(records, record)
+		ids = append(ids, id)
+	}
+	return records, ids, rows.Err()
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqlStore) Location() string {
+	if s.dialect.file {
+		return s.dsn
+	}
+	return s.dialect.name + " database"
+}
@@ -0,0 +1,140 @@
+// Package store persists the history of scans and the jobs of the server in
+// a selectable backend: the history file, SQLite, BoltDB or PostgreSQL.
+// Servers sharing a PostgreSQL database see the jobs of each other.
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/steffakasid/govital/internal/dirs"
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Backend types
+const (
+	// TypeFile appends the history to a JSON lines file and keeps jobs in
+	// memory only
+	TypeFile = "file"
+	// TypeSQLite stores everything in a SQLite database file
+	TypeSQLite = "sqlite"
+	// TypeBolt stores everything in a BoltDB file
+	TypeBolt = "bolt"
+	// TypePostgres stores everything in a PostgreSQL database
+	TypePostgres = "postgres"
+)
+
+// Config selects the backend. DSN is the database file for SQLite and
+// BoltDB and the connection string for PostgreSQL.
+type Config struct {
+	Type string `mapstructure:"type"`
+	DSN  string `mapstructure:"dsn"`
+}
+
+// Store persists recorded scans and server jobs
+type Store interface {
+	jobs.Store
+	// Append records a scan in the history
+	Append(ctx context.Context, record history.Record) error
+	// Load returns the recorded scans of the project, oldest first
+	Load(ctx context.Context, project string) ([]history.Record, error)
+	// Prune removes the recorded scans of all projects exceeding the
+	// retention, the latest scan of each project is kept
+	Prune(ctx context.Context, retention history.Retention) (history.PruneResult, error)
+	// Close releases the database
+	Close() error
+	// Location describes where the data is stored, for messages
+	Location() string
+}
+
+// Open opens the backend of config. The file backend writes the history to
+// historyPath, the database files of SQLite and BoltDB default to the
+// govital directory of the user.
+func Open(ctx context.Context, config Config, historyPath string) (Store, error) {
+	switch config.Type {
+	case "", TypeFile:
+		return newFileStore(historyPath), nil
+	case TypeSQLite:
+		return openSQL(ctx, sqliteDialect, defaultDSN(config.DSN, "govital.db"))
+	case TypeBolt:
+		return openBolt(defaultDSN(config.DSN, "govital.bolt"))
+	case TypePostgres:
+		if config.DSN == "" {
+			return nil, fmt.Errorf("storage.dsn is required for the postgres storage")
+		}
+		return openSQL(ctx, postgresDialect, config.DSN)
+	}
+	return nil, fmt.Errorf("unknown storage type %q, expected %s, %s, %s or %s", config.Type, TypeFile, TypeSQLite, TypeBolt, TypePostgres)
+}
+
+// pageJobs returns limit jobs of the list skipping the first offset, all
+// after offset if limit is 0
+func pageJobs(list []jobs.Job, offset, limit int) []jobs.Job {
+	if offset >= len(list) {
+		return nil
+	}
+	list = list[max(offset, 0):]
+	if limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+	return list
+}
+
+// expiredJobs returns the finished jobs of the list which finished before
+// the given time or aren't among the keep latest finished, 0 keeps all.
+// Queued and running jobs never expire.
+func expiredJobs(list []jobs.Job, keep int, finishedBefore time.Time) []jobs.Job {
+	var finished, expired []jobs.Job
+	for _, job := range list {
+		if !job.State.Done() {
+			continue
+		}
+		if job.Finished.Before(finishedBefore) {
+			expired = append(expired, job)
+			continue
+		}
+		finished = append(finished, job)
+	}
+	if keep == 0 || len(finished) <= keep {
+		return expired
+	}
+	slices.SortFunc(finished, func(a, b jobs.Job) int {
+		return b.Finished.Compare(a.Finished)
+	})
+	return append(expired, finished[keep:]...)
+}
+
+func defaultDSN(dsn, name string) string {
+	if dsn != "" {
+		return dsn
+	}
+	return dirs.Home(name)
+}
+
+// Publisher records every published scan in the history of the store and
+// prunes it if a retention is set. It implements publish.Publisher.
+type Publisher struct {
+	store     Store
+	retention history.Retention
+}
+
+// NewPublisher creates a publisher recording scans in store
+func NewPublisher(store Store, retention history.Retention) *Publisher {
+	return &Publisher{store: store, retention: retention}
+}
+
+// Publish appends the summary of the scan result
+func (p *Publisher) Publish(ctx context.Context, result *scanner.ScanResult) error {
+	if err := p.store.Append(ctx, history.NewRecord(result, now())); err != nil {
+		return err
+	}
+	if !p.retention.Enabled() {
+		return nil
+	}
+	_, err := p.store.Prune(ctx, p.retention)
+	return err
+}
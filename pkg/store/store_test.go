@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/history"
+	"github.com/steffakasid/govital/pkg/jobs"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// openTestStores returns the backends to test. PostgreSQL is only tested
+// if GOVITAL_TEST_POSTGRES_DSN names a database the tests may clear.
+func openTestStores(t *testing.T) map[string]Store {
+	t.Helper()
+	dir := t.TempDir()
+	stores := make(map[string]Store)
+	for name, config := range map[string]Config{
+		TypeSQLite: {Type: TypeSQLite, DSN: filepath.Join(dir, "govital.db")},
+		TypeBolt:   {Type: TypeBolt, DSN: filepath.Join(dir, "govital.bolt")},
+	} {
+		store, err := Open(context.Background(), config, "")
+		require.NoError(t, err)
+		stores[name] = store
+	}
+	if dsn := os.Getenv("GOVITAL_TEST_POSTGRES_DSN"); dsn != "" {
+		store, err := Open(context.Background(), Config{Type: TypePostgres, DSN: dsn}, "")
+		require.NoError(t, err)
+		for _, table := range []string{"govital_jobs", "govital_history"} {
+			_, err = store.(*sqlStore).db.Exec("DELETE FROM " + table)
+			require.NoError(t, err)
+		}
+		stores[TypePostgres] = store
+	}
+	for _, store := range stores {
+		t.Cleanup(func() { store.Close() })
+	}
+	return stores
+}
+
+func TestJobs(t *testing.T) {
+	for name, store := range openTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			older := jobs.Job{ID: "a", State: jobs.Queued, Request: jobs.Request{ProjectPath: "/project"}, Created: testNow.Add(-time.Hour)}
+			newer := jobs.Job{ID: "b", State: jobs.Running, Created: testNow}
+			require.NoError(t, store.SaveJob(ctx, older))
+			require.NoError(t, store.SaveJob(ctx, newer))
+
+			older.State = jobs.Succeeded
+			older.Result = &scanner.ScanResult{ProjectPath: "/project"}
+			require.NoError(t, store.SaveJob(ctx, older), "saving again updates the job")
+
+			job, err := store.Job(ctx, "a")
+			require.NoError(t, err)
+			assert.Equal(t, jobs.Succeeded, job.State)
+			require.NotNil(t, job.Result)
+			assert.Equal(t, "/project", job.Result.ProjectPath)
+
+			list, err := store.Jobs(ctx, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			assert.Equal(t, "b", list[0].ID, "newest first")
+			list, err = store.Jobs(ctx, 1, 1)
+			require.NoError(t, err)
+			require.Len(t, list, 1)
+			assert.Equal(t, "a", list[0].ID, "second page")
+			list, err = store.Jobs(ctx, 1, 0)
+			require.NoError(t, err)
+			assert.Len(t, list, 1)
+
+			_, err = store.Job(ctx, "missing")
+			assert.True(t, errors.Is(err, jobs.ErrNotFound))
+
+			older.Finished = testNow.Add(-30 * time.Minute)
+			require.NoError(t, store.SaveJob(ctx, older))
+			require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "c", State: jobs.Succeeded, Created: testNow.Add(time.Minute), Finished: testNow.Add(2 * time.Minute)}))
+			require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "long", State: jobs.Failed, Created: testNow.Add(-2 * time.Hour), Finished: testNow}))
+			removed, err := store.PruneJobs(ctx, 0, testNow.Add(-time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, 1, removed, "finished before")
+			_, err = store.Job(ctx, "long")
+			assert.NoError(t, err, "created before but finished within the retention")
+			removed, err = store.PruneJobs(ctx, 1, time.Time{})
+			require.NoError(t, err)
+			assert.Equal(t, 1, removed, "beyond the latest finished")
+			list, err = store.Jobs(ctx, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			assert.Equal(t, []string{"c", "b"}, []string{list[0].ID, list[1].ID}, "running jobs are kept")
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	now = func() time.Time { return testNow }
+	defer func() { now = time.Now }()
+
+	for name, store := range openTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, record := range []history.Record{
+				{Project: "example.com/app", ScannedAt: testNow.AddDate(0, 0, -1), Summary: scanner.Summary{Total: 3}},
+				{Project: "example.com/app", ScannedAt: testNow.AddDate(0, 0, -400), Summary: scanner.Summary{Total: 1}},
+				{Project: "example.com/app", ScannedAt: testNow.AddDate(0, 0, -10), Summary: scanner.Summary{Total: 2}},
+				{Project: "example.com/other", ScannedAt: testNow.AddDate(0, 0, -500)},
+			} {
+				require.NoError(t, store.Append(ctx, record))
+			}
+
+			records, err := store.Load(ctx, "example.com/app")
+			require.NoError(t, err)
+			require.Len(t, records, 3)
+			assert.Equal(t, []int{1, 2, 3}, []int{records[0].Summary.Total, records[1].Summary.Total, records[2].Summary.Total}, "oldest first")
+
+			result, err := store.Prune(ctx, history.Retention{MaxAgeDays: 365})
+			require.NoError(t, err)
+			assert.Equal(t, history.PruneResult{Kept: 3, Removed: 1}, result, "the latest scan of a project is kept")
+
+			records, err = store.Load(ctx, "example.com/app")
+			require.NoError(t, err)
+			assert.Len(t, records, 2)
+			records, err = store.Load(ctx, "example.com/other")
+			require.NoError(t, err)
+			assert.Len(t, records, 1)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(context.Background(), Config{}, filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, err)
+	_, err = store.Job(context.Background(), "a")
+	assert.True(t, errors.Is(err, jobs.ErrNotFound), "the file backend keeps no jobs")
+
+	_, err = Open(context.Background(), Config{Type: TypePostgres}, "")
+	assert.ErrorContains(t, err, "storage.dsn is required")
+	_, err = Open(context.Background(), Config{Type: "mongo"}, "")
+	assert.ErrorContains(t, err, `unknown storage type "mongo"`)
+}
+
+func TestPublisher(t *testing.T) {
+	store := newFileStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	publisher := NewPublisher(store, history.Retention{MaxScans: 1})
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, publisher.Publish(context.Background(), &scanner.ScanResult{
+			ProjectPath: "/project",
+			Fingerprint: &scanner.Fingerprint{Module: "example.com/app"},
+		}))
+	}
+
+	records, err := store.Load(context.Background(), "example.com/app")
+	require.NoError(t, err)
+	assert.Len(t, records, 1, "pruned to the retention")
+}
+
+func TestDialectBind(t *testing.T) {
+	query := `SELECT job FROM govital_jobs WHERE id = ? AND created > ?`
+	assert.Equal(t, query, sqliteDialect.bind(query))
+	assert.Equal(t, `SELECT job FROM govital_jobs WHERE id = $1 AND created > $2`, postgresDialect.bind(query))
+}
+
+func TestQueueSharesJobsThroughStore(t *testing.T) {
+	store, err := Open(context.Background(), Config{Type: TypeSQLite, DSN: filepath.Join(t.TempDir(), "govital.db")}, "")
+	require.NoError(t, err)
+	defer store.Close()
+	run := func(ctx context.Context, request jobs.Request) (*scanner.ScanResult, error) {
+		return &scanner.ScanResult{ProjectPath: request.ProjectPath}, nil
+	}
+	first := jobs.NewQueue(1, 1, run)
+	first.SetStore(store)
+	defer first.Close()
+	second := jobs.NewQueue(1, 1, run)
+	second.SetStore(store)
+	defer second.Close()
+
+	submitted, err := first.Submit(jobs.Request{ProjectPath: "/project"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		job, err := second.Get(submitted.ID)
+		return err == nil && job.State == jobs.Succeeded && job.Result != nil
+	}, 2*time.Second, 5*time.Millisecond, "the other instance sees the finished job")
+	assert.Len(t, second.List(), 1)
+}
+
+func TestFailOrphaned(t *testing.T) {
+	store, err := Open(context.Background(), Config{Type: TypeSQLite, DSN: filepath.Join(t.TempDir(), "govital.db")}, "")
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+	require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "running", State: jobs.Running, Created: testNow.Add(-time.Hour), Instance: "a"}))
+	require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "unowned", State: jobs.Queued, Created: testNow.Add(-time.Hour)}))
+	require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "other", State: jobs.Running, Created: testNow.Add(-time.Hour), Instance: "b"}))
+	require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "done", State: jobs.Succeeded, Created: testNow.Add(-time.Hour)}))
+	require.NoError(t, store.SaveJob(ctx, jobs.Job{ID: "new", State: jobs.Queued, Created: testNow}))
+
+	count, err := jobs.FailOrphaned(ctx, store, "a", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	job, err := store.Job(ctx, "running")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.Failed, job.State)
+	assert.Contains(t, job.Error, "orphaned")
+	job, err = store.Job(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.Queued, job.State, "created after the start")
+	job, err = store.Job(ctx, "unowned")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.Failed, job.State, "stored before instances were recorded")
+	job, err = store.Job(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.Running, job.State, "run by another live instance")
+}
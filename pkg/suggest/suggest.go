@@ -0,0 +1,338 @@
+// Package suggest looks up maintained alternatives for inactive or archived
+// dependencies: known successors, the source repositories deps.dev relates
+// to a module and active forks on GitHub, GitLab and Gitea/Forgejo.
+package suggest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/license"
+	"github.com/steffakasid/govital/pkg/repo"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Sources of suggestions
+const (
+	// SourceKnown is the built-in table of successors
+	SourceKnown = "known"
+	// SourceDepsDev is a source repository deps.dev relates to the module
+	SourceDepsDev = "deps.dev"
+	// SourceFork is an active fork of the source repository
+	SourceFork = "fork"
+)
+
+// Suggestion is a replacement for a dependency
+type Suggestion struct {
+	// Module is the module path or, for forks, the repository of the
+	// replacement
+	Module string `json:"module"`
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+	// Stars and PushedAt are only set for forks
+	Stars    int       `json:"stars,omitempty"`
+	PushedAt time.Time `json:"pushed_at,omitzero"`
+}
+
+// Entry lists the suggestions for a dead dependency
+type Entry struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Problem is why the dependency needs a replacement, e.g. archived
+	Problem string `json:"problem"`
+	// Repository is the root of the source repository, like
+	// github.com/owner/name, empty if it couldn't be resolved
+	Repository  string       `json:"repository,omitempty"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// successor is a known replacement of a module and everything below it
+type successor struct {
+	module string
+	reason string
+}
+
+// knownSuccessors maps module paths to their maintained replacements
+var knownSuccessors = map[string]successor{
+	"github.com/pkg/errors":                 {"errors", "the standard library wraps errors with fmt.Errorf(\"%w\") and errors.Is/As since Go 1.13"},
+	"github.com/hashicorp/go-multierror":    {"errors", "the standard library joins errors with errors.Join since Go 1.20"},
+	"github.com/mitchellh/go-homedir":       {"os", "the standard library provides os.UserHomeDir since Go 1.12"},
+	"github.com/golang/protobuf":            {"google.golang.org/protobuf", "the APIv2 successor by the same authors"},
+	"github.com/golang/mock":                {"go.uber.org/mock", "the maintained fork recommended by the archived repository"},
+	"github.com/dgrijalva/jwt-go":           {"github.com/golang-jwt/jwt/v5", "the community fork continuing the project"},
+	"github.com/form3tech-oss/jwt-go":       {"github.com/golang-jwt/jwt/v5", "the community fork continuing the project"},
+	"github.com/satori/go.uuid":             {"github.com/gofrs/uuid/v5", "the maintained fork fixing the insecure random generation"},
+	"github.com/ghodss/yaml":                {"sigs.k8s.io/yaml", "the maintained fork by Kubernetes"},
+	"gopkg.in/yaml.v2":                      {"go.yaml.in/yaml/v3", "the maintained successor by the YAML organization"},
+	"gopkg.in/yaml.v3":                      {"go.yaml.in/yaml/v3", "the maintained continuation by the YAML organization"},
+	"github.com/mitchellh/mapstructure":     {"github.com/go-viper/mapstructure/v2", "the maintained fork by the Viper maintainers"},
+	"github.com/boltdb/bolt":                {"go.etcd.io/bbolt", "the maintained fork by etcd"},
+	"github.com/go-kit/kit/log":             {"github.com/go-kit/log", "the extracted logging module of Go kit"},
+	"github.com/streadway/amqp":             {"github.com/rabbitmq/amqp091-go", "the fork maintained by the RabbitMQ team"},
+	"github.com/square/go-jose":             {"github.com/go-jose/go-jose/v4", "the maintained fork by the original authors"},
+	"gopkg.in/square/go-jose.v2":            {"github.com/go-jose/go-jose/v4", "the maintained fork by the original authors"},
+	"github.com/opentracing/opentracing-go": {"go.opentelemetry.io/otel", "OpenTracing was merged into OpenTelemetry"},
+	"github.com/codegangsta/cli":            {"github.com/urfave/cli/v2", "the project moved and continues there"},
+	"github.com/docker/distribution":        {"github.com/distribution/distribution/v3", "the project moved to the distribution organization"},
+	"github.com/jteeuwen/go-bindata":        {"embed", "the standard library embeds files since Go 1.16"},
+	"github.com/rakyll/statik":              {"embed", "the standard library embeds files since Go 1.16"},
+	"github.com/gobuffalo/packr":            {"embed", "the standard library embeds files since Go 1.16"},
+}
+
+// knownSuccessor returns the successor of the module path or of the
+// closest parent path in the table
+func knownSuccessor(modulePath string) (successor, bool) {
+	for prefix := modulePath; prefix != ""; {
+		if s, ok := knownSuccessors[prefix]; ok {
+			return s, true
+		}
+		index := strings.LastIndex(prefix, "/")
+		if index < 0 {
+			break
+		}
+		prefix = prefix[:index]
+	}
+	return successor{}, false
+}
+
+// Advisor looks up suggestions. Failing lookups are logged as warnings and
+// only lose the suggestions of their source.
+type Advisor struct {
+	// DepsDevURL is the deps.dev API, empty to skip the lookup
+	DepsDevURL string
+	HTTPClient *http.Client
+	// MaxForks is the number of forks suggested per dependency, 0 skips the
+	// lookup of forks
+	MaxForks int
+	// ActiveDays is the number of days since the last push for a fork to be
+	// suggested
+	ActiveDays int
+	// IsPrivate reports whether a module must not be sent to deps.dev,
+	// e.g. scanner.Scanner.IsPrivate. Without it all modules are looked up.
+	IsPrivate func(modulePath string) bool
+
+	forge    *forge.Client
+	resolver *repo.Resolver
+	now      func() time.Time
+}
+
+// NewAdvisor creates an advisor querying deps.dev and the forges with the
+// tokens of config, suggesting up to 3 forks pushed within 180 days
+func NewAdvisor(config forge.Config) *Advisor {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	return &Advisor{
+		DepsDevURL: license.DefaultBaseURL,
+		HTTPClient: httpClient,
+		MaxForks:   3,
+		ActiveDays: 180,
+		forge:      forge.NewClient(httpClient, config),
+		resolver:   repo.NewResolver(httpClient),
+		now:        time.Now,
+	}
+}
+
+// Problem returns why the dependency needs a replacement, empty if it
+// doesn't. Acknowledged dependencies don't.
+func Problem(dep scanner.Dependency) string {
+	if dep.IsAcknowledged {
+		return ""
+	}
+	switch {
+	case dep.Archived != nil && *dep.Archived:
+		return "archived"
+	case dep.Deprecated != "":
+		return "deprecated"
+	case dep.IsInactive():
+		return fmt.Sprintf("inactive for %d days", dep.DaysSinceLastRelease)
+	}
+	return ""
+}
+
+// Suggest returns the suggestions for every dependency of the result which
+// needs a replacement, in the order of the result
+func (a *Advisor) Suggest(ctx context.Context, result *scanner.ScanResult) ([]Entry, error) {
+	entries := []Entry{}
+	seen := make(map[string]bool)
+	for _, dep := range result.Dependencies {
+		problem := Problem(dep)
+		if problem == "" || seen[dep.Path] {
+			continue
+		}
+		seen[dep.Path] = true
+
+		entry := Entry{Path: dep.Path, Version: dep.Version, Problem: problem}
+		if err := a.lookup(ctx, dep, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// lookup collects the suggestions of all sources into the entry, it only
+// fails if ctx is done
+func (a *Advisor) lookup(ctx context.Context, dep scanner.Dependency, entry *Entry) error {
+	entry.Suggestions = []Suggestion{}
+	if s, ok := knownSuccessor(dep.Path); ok {
+		entry.Suggestions = append(entry.Suggestions, Suggestion{Module: s.module, Source: SourceKnown, Reason: s.reason})
+	}
+
+	repository, err := a.resolver.Resolve(ctx, dep.Path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		eslog.Warnf("Failed to resolve the repository of %s: %v", dep.Path, err)
+		return nil
+	}
+	entry.Repository = repository.Root
+
+	if a.DepsDevURL != "" && (a.IsPrivate == nil || !a.IsPrivate(dep.Path)) {
+		related, err := a.relatedRepositories(ctx, dep.Path, dep.Version)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			eslog.Warnf("Failed to look up %s on deps.dev: %v", dep.Path, err)
+		}
+		for _, root := range related {
+			if !strings.EqualFold(root, repository.Root) {
+				entry.Suggestions = append(entry.Suggestions, Suggestion{Module: root, Source: SourceDepsDev, Reason: "deps.dev relates the module to this repository, it may have moved"})
+			}
+		}
+	}
+
+	if a.MaxForks > 0 {
+		forks, err := a.activeForks(ctx, repository)
+		if err != nil {
+			return err
+		}
+		entry.Suggestions = append(entry.Suggestions, forks...)
+	}
+	return nil
+}
+
+// activeForks returns the starred forks which are not archived and were
+// pushed within ActiveDays
+func (a *Advisor) activeForks(ctx context.Context, repository repo.Repository) ([]Suggestion, error) {
+	// Fetch more forks than suggested, most of them are abandoned as well
+	forks, err := a.forge.Forks(ctx, repository, a.MaxForks*10)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, forge.ErrUnsupported) {
+			eslog.Warnf("Failed to look up the forks of %s: %v", repository.Root, err)
+		}
+		return nil, nil
+	}
+
+	cutoff := a.now().AddDate(0, 0, -a.ActiveDays)
+	sort.SliceStable(forks, func(i, j int) bool { return forks[i].Stars > forks[j].Stars })
+	var suggestions []Suggestion
+	for _, fork := range forks {
+		if len(suggestions) == a.MaxForks {
+			break
+		}
+		if fork.Archived || fork.Stars == 0 || fork.PushedAt.Before(cutoff) {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			Module:   fork.Root,
+			Source:   SourceFork,
+			Reason:   fmt.Sprintf("active fork with %d stars", fork.Stars),
+			Stars:    fork.Stars,
+			PushedAt: fork.PushedAt,
+		})
+	}
+	return suggestions, nil
+}
+
+// relatedRepositories returns the roots of the source repositories deps.dev
+// relates to the module version, like github.com/owner/name
+func (a *Advisor) relatedRepositories(ctx context.Context, modulePath, version string) ([]string, error) {
+	path := fmt.Sprintf("/v3/systems/go/packages/%s/versions/%s", url.PathEscape(modulePath), url.PathEscape(version))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(a.DepsDevURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deps.dev request: %w", err)
+	}
+
+	response, err := a.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("deps.dev request for %s@%s failed: %w", modulePath, version, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, response.Body)
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("deps.dev returned status %d", response.StatusCode)
+	}
+
+	var result struct {
+		RelatedProjects []struct {
+			ProjectKey struct {
+				ID string `json:"id"`
+			} `json:"projectKey"`
+			RelationType string `json:"relationType"`
+		} `json:"relatedProjects"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode deps.dev response for %s@%s: %w", modulePath, version, err)
+	}
+
+	var roots []string
+	for _, project := range result.RelatedProjects {
+		if project.RelationType == "SOURCE_REPO" && project.ProjectKey.ID != "" {
+			roots = append(roots, project.ProjectKey.ID)
+		}
+	}
+	return roots, nil
+}
+
+// Write prints the suggestions as text
+func Write(w io.Writer, entries []Entry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "No inactive, archived or deprecated dependencies, nothing to replace")
+		return err
+	}
+
+	for i, entry := range entries {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s@%s (%s)\n", entry.Path, entry.Version, entry.Problem); err != nil {
+			return err
+		}
+		if len(entry.Suggestions) == 0 {
+			if _, err := fmt.Fprintln(w, "  no maintained alternative found"); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, suggestion := range entry.Suggestions {
+			line := fmt.Sprintf("  -> %s [%s]: %s", suggestion.Module, suggestion.Source, suggestion.Reason)
+			if !suggestion.PushedAt.IsZero() {
+				line += ", last push " + suggestion.PushedAt.Format(time.DateOnly)
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
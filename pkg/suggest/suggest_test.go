@@ -0,0 +1,151 @@
+package suggest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/forge"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestAdvisor returns an advisor for the test server, which serves
+// deps.dev below /depsdev and GitHub below /github
+func newTestAdvisor(server *httptest.Server) *Advisor {
+	advisor := NewAdvisor(forge.Config{})
+	advisor.DepsDevURL = server.URL + "/depsdev"
+	advisor.HTTPClient = server.Client()
+	advisor.forge = forge.NewClient(server.Client(), forge.Config{})
+	advisor.forge.GitHubURL = server.URL + "/github"
+	advisor.now = func() time.Time { return now }
+	return advisor
+}
+
+func TestKnownSuccessor(t *testing.T) {
+	s, ok := knownSuccessor("github.com/go-kit/kit/log/level")
+	require.True(t, ok)
+	assert.Equal(t, "github.com/go-kit/log", s.module)
+
+	s, ok = knownSuccessor("github.com/pkg/errors")
+	require.True(t, ok)
+	assert.Equal(t, "errors", s.module)
+
+	_, ok = knownSuccessor("github.com/go-kit/kit")
+	assert.False(t, ok)
+}
+
+func TestProblem(t *testing.T) {
+	archived := true
+	assert.Equal(t, "archived", Problem(scanner.Dependency{IsActive: true, Archived: &archived}))
+	assert.Equal(t, "deprecated", Problem(scanner.Dependency{IsActive: true, Deprecated: "use v2"}))
+	assert.Equal(t, "inactive for 400 days", Problem(scanner.Dependency{DaysSinceLastRelease: 400}))
+	assert.Empty(t, Problem(scanner.Dependency{DaysSinceLastRelease: 400, IsAcknowledged: true}))
+	assert.Empty(t, Problem(scanner.Dependency{IsActive: true}))
+	assert.Empty(t, Problem(scanner.Dependency{Error: &scanner.ScanError{Message: "not found"}}))
+}
+
+func TestSuggest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/depsdev/v3/systems/go/packages/github.com/pkg/errors/versions/v0.9.1":
+			_, _ = w.Write([]byte(`{"relatedProjects":[
+				{"projectKey":{"id":"github.com/pkg/errors"},"relationType":"SOURCE_REPO"},
+				{"projectKey":{"id":"github.com/pkg/errors-moved"},"relationType":"SOURCE_REPO"},
+				{"projectKey":{"id":"github.com/pkg/tracker"},"relationType":"ISSUE_TRACKER"}]}`))
+		case "/github/repos/pkg/errors/forks":
+			fmt.Fprintf(w, `[
+				{"full_name":"old/errors","stargazers_count":90,"pushed_at":"2020-01-01T00:00:00Z"},
+				{"full_name":"archived/errors","stargazers_count":80,"archived":true,"pushed_at":"2026-05-01T00:00:00Z"},
+				{"full_name":"unstarred/errors","stargazers_count":0,"pushed_at":"2026-05-01T00:00:00Z"},
+				{"full_name":"active/errors","stargazers_count":12,"pushed_at":"2026-05-01T00:00:00Z"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	advisor := newTestAdvisor(server)
+
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", DaysSinceLastRelease: 2000},
+		{Path: "github.com/example/active", Version: "v1.0.0", IsActive: true},
+		{Path: "github.com/example/dead", Version: "v1.0.0", DaysSinceLastRelease: 900},
+	}}
+
+	entries, err := advisor.Suggest(context.Background(), result)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "github.com/pkg/errors", entries[0].Path)
+	assert.Equal(t, "inactive for 2000 days", entries[0].Problem)
+	assert.Equal(t, "github.com/pkg/errors", entries[0].Repository)
+	require.Len(t, entries[0].Suggestions, 3)
+	assert.Equal(t, "errors", entries[0].Suggestions[0].Module)
+	assert.Equal(t, SourceKnown, entries[0].Suggestions[0].Source)
+	assert.Equal(t, Suggestion{Module: "github.com/pkg/errors-moved", Source: SourceDepsDev, Reason: "deps.dev relates the module to this repository, it may have moved"}, entries[0].Suggestions[1])
+	assert.Equal(t, Suggestion{
+		Module:   "github.com/active/errors",
+		Source:   SourceFork,
+		Reason:   "active fork with 12 stars",
+		Stars:    12,
+		PushedAt: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+	}, entries[0].Suggestions[2])
+
+	// Failing lookups only lose their suggestions
+	assert.Equal(t, "github.com/example/dead", entries[1].Path)
+	assert.Empty(t, entries[1].Suggestions)
+}
+
+func TestSuggestSkipsPrivateModules(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	advisor := newTestAdvisor(server)
+	advisor.MaxForks = 0
+	advisor.IsPrivate = func(modulePath string) bool {
+		return strings.HasPrefix(modulePath, "github.com/corp/")
+	}
+
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/corp/internal", Version: "v1.0.0", DaysSinceLastRelease: 900},
+		{Path: "github.com/example/dead", Version: "v1.0.0", DaysSinceLastRelease: 900},
+	}}
+	_, err := advisor.Suggest(context.Background(), result)
+	require.NoError(t, err)
+
+	assert.Contains(t, requested, "/depsdev/v3/systems/go/packages/github.com/example/dead/versions/v1.0.0")
+	for _, path := range requested {
+		assert.NotContains(t, path, "corp", "private module sent to deps.dev")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, []Entry{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Problem: "archived", Suggestions: []Suggestion{
+			{Module: "errors", Source: SourceKnown, Reason: "standard library"},
+			{Module: "github.com/active/errors", Source: SourceFork, Reason: "active fork with 12 stars", Stars: 12, PushedAt: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+		{Path: "github.com/example/dead", Version: "v1.0.0", Problem: "inactive for 900 days"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, `github.com/pkg/errors@v0.9.1 (archived)
+  -> errors [known]: standard library
+  -> github.com/active/errors [fork]: active fork with 12 stars, last push 2026-05-01
+
+github.com/example/dead@v1.0.0 (inactive for 900 days)
+  no maintained alternative found
+`, buf.String())
+}
@@ -0,0 +1,171 @@
+// Package tool locates and runs the external tools govital depends on, go
+// and git, the same way on all operating systems
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// Go is the go command
+	Go = "go"
+	// Git is the git command
+	Git = "git"
+)
+
+// Locator finds tools on the PATH and, if they are missing there, in the
+// directories their installers use by default. The platform is injected so
+// the lookup of every operating system can be tested on any of them.
+type Locator struct {
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	isFile   func(string) bool
+
+	mutex sync.Mutex
+	found map[string]string
+}
+
+// NewLocator creates a locator for the given operating system, e.g.
+// runtime.GOOS, using the environment and filesystem lookups passed in
+func NewLocator(goos string, getenv func(string) string, lookPath func(string) (string, error), isFile func(string) bool) *Locator {
+	return &Locator{
+		goos:     goos,
+		getenv:   getenv,
+		lookPath: lookPath,
+		isFile:   isFile,
+		found:    make(map[string]string),
+	}
+}
+
+var defaultLocator = NewLocator(runtime.GOOS, os.Getenv, exec.LookPath, func(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.Mode().IsRegular()
+})
+
+// Find returns the path of the tool. Executables in the current directory
+// are never used, like for exec.LookPath. Successful lookups are cached.
+func (l *Locator) Find(name string) (string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if found, ok := l.found[name]; ok {
+		return found, nil
+	}
+
+	found, err := l.lookPath(name)
+	if errors.Is(err, exec.ErrDot) {
+		found, err = "", fmt.Errorf("refusing to run %s from the current directory: %w", name, err)
+	}
+	if err != nil {
+		for _, candidate := range l.candidates(name) {
+			if l.isFile(candidate) {
+				found, err = candidate, nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s not found: %w", name, err)
+	}
+	l.found[name] = found
+	return found, nil
+}
+
+// candidates returns the default install locations of the tool
+func (l *Locator) candidates(name string) []string {
+	executable := name
+	if l.goos == "windows" {
+		executable += ".exe"
+	}
+
+	var candidates []string
+	switch name {
+	case Go:
+		if goroot := l.getenv("GOROOT"); goroot != "" {
+			candidates = append(candidates, l.join(goroot, "bin", executable))
+		}
+		if l.goos == "windows" {
+			candidates = append(candidates, l.programFiles("Go", "bin", executable)...)
+		} else {
+			candidates = append(candidates, "/usr/local/go/bin/go")
+		}
+	case Git:
+		if l.goos == "windows" {
+			candidates = append(candidates, l.programFiles("Git", "cmd", executable)...)
+			if local := l.getenv("LOCALAPPDATA"); local != "" {
+				candidates = append(candidates, l.join(local, "Programs", "Git", "cmd", executable))
+			}
+		}
+	}
+	return candidates
+}
+
+// programFiles joins elem to all program files directories of Windows
+func (l *Locator) programFiles(elem ...string) []string {
+	var paths []string
+	for _, env := range []string{"ProgramFiles", "ProgramW6432", "ProgramFiles(x86)"} {
+		if dir := l.getenv(env); dir != "" {
+			paths = append(paths, l.join(append([]string{dir}, elem...)...))
+		}
+	}
+	return paths
+}
+
+// join is filepath.Join for the operating system of the locator
+func (l *Locator) join(elem ...string) string {
+	separator := "/"
+	if l.goos == "windows" {
+		separator = `\`
+	}
+	parts := make([]string, len(elem))
+	for i, part := range elem {
+		if i == 0 {
+			parts[i] = strings.TrimRight(part, `/\`)
+		} else {
+			parts[i] = strings.Trim(part, `/\`)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+// Args returns the arguments to run the tool with. Git on Windows is told
+// to support paths longer than 260 characters.
+func (l *Locator) Args(name string, args ...string) []string {
+	if name == Git && l.goos == "windows" {
+		return append([]string{"-c", "core.longpaths=true"}, args...)
+	}
+	return args
+}
+
+// CommandContext is like exec.CommandContext for one of the tools, run in
+// dir. Like for exec.Command a failed lookup is reported when the command
+// is run.
+func (l *Locator) CommandContext(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	path, err := l.Find(name)
+	if err != nil {
+		path = name
+	}
+	cmd := exec.CommandContext(ctx, path, l.Args(name, args...)...)
+	cmd.Dir = dir
+	if err != nil {
+		cmd.Err = err
+	}
+	return cmd
+}
+
+// CommandContext runs the tool located for the current operating system
+func CommandContext(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	return defaultLocator.CommandContext(ctx, dir, name, args...)
+}
+
+// Command is CommandContext without context
+func Command(dir, name string, args ...string) *exec.Cmd {
+	return CommandContext(context.Background(), dir, name, args...)
+}
@@ -0,0 +1,105 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocator(goos string, env map[string]string, path map[string]string, files ...string) *Locator {
+	existing := make(map[string]bool)
+	for _, file := range files {
+		existing[file] = true
+	}
+	return NewLocator(goos,
+		func(key string) string { return env[key] },
+		func(name string) (string, error) {
+			if found, ok := path[name]; ok {
+				return found, nil
+			}
+			return "", exec.ErrNotFound
+		},
+		func(name string) bool { return existing[name] },
+	)
+}
+
+func TestFind(t *testing.T) {
+	t.Run("PATH wins", func(t *testing.T) {
+		locator := newTestLocator("linux", map[string]string{"GOROOT": "/opt/go"}, map[string]string{"go": "/usr/bin/go"}, "/opt/go/bin/go")
+		found, err := locator.Find(Go)
+		require.NoError(t, err)
+		assert.Equal(t, "/usr/bin/go", found)
+	})
+
+	t.Run("GOROOT fallback", func(t *testing.T) {
+		locator := newTestLocator("linux", map[string]string{"GOROOT": "/opt/go/"}, nil, "/opt/go/bin/go")
+		found, err := locator.Find(Go)
+		require.NoError(t, err)
+		assert.Equal(t, "/opt/go/bin/go", found)
+	})
+
+	t.Run("git for windows", func(t *testing.T) {
+		env := map[string]string{
+			"ProgramFiles":      `C:\Program Files`,
+			"ProgramFiles(x86)": `C:\Program Files (x86)`,
+			"LOCALAPPDATA":      `C:\Users\dev\AppData\Local`,
+		}
+		locator := newTestLocator("windows", env, nil, `C:\Users\dev\AppData\Local\Programs\Git\cmd\git.exe`)
+		found, err := locator.Find(Git)
+		require.NoError(t, err)
+		assert.Equal(t, `C:\Users\dev\AppData\Local\Programs\Git\cmd\git.exe`, found)
+
+		locator = newTestLocator("windows", env, nil, `C:\Program Files (x86)\Git\cmd\git.exe`)
+		found, err = locator.Find(Git)
+		require.NoError(t, err)
+		assert.Equal(t, `C:\Program Files (x86)\Git\cmd\git.exe`, found)
+	})
+
+	t.Run("go for windows", func(t *testing.T) {
+		locator := newTestLocator("windows", map[string]string{"ProgramFiles": `C:\Program Files\`}, nil, `C:\Program Files\Go\bin\go.exe`)
+		found, err := locator.Find(Go)
+		require.NoError(t, err)
+		assert.Equal(t, `C:\Program Files\Go\bin\go.exe`, found)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		locator := newTestLocator("windows", nil, nil)
+		_, err := locator.Find(Git)
+		assert.ErrorIs(t, err, exec.ErrNotFound)
+	})
+
+	t.Run("current directory is refused", func(t *testing.T) {
+		locator := NewLocator("windows", func(string) string { return "" },
+			func(name string) (string, error) { return name + ".exe", exec.ErrDot },
+			func(string) bool { return false })
+		_, err := locator.Find(Git)
+		assert.ErrorIs(t, err, exec.ErrDot)
+	})
+}
+
+func TestArgs(t *testing.T) {
+	windows := newTestLocator("windows", nil, nil)
+	assert.Equal(t, []string{"-c", "core.longpaths=true", "rev-parse", "HEAD"}, windows.Args(Git, "rev-parse", "HEAD"))
+	assert.Equal(t, []string{"list", "-m"}, windows.Args(Go, "list", "-m"))
+
+	linux := newTestLocator("linux", nil, nil)
+	assert.Equal(t, []string{"rev-parse", "HEAD"}, linux.Args(Git, "rev-parse", "HEAD"))
+}
+
+func TestCommandContext(t *testing.T) {
+	locator := newTestLocator("windows", nil, map[string]string{"git": `C:\Git\cmd\git.exe`})
+	cmd := locator.CommandContext(context.Background(), `C:\src\app`, Git, "status")
+	assert.Equal(t, `C:\Git\cmd\git.exe`, cmd.Path)
+	assert.Equal(t, []string{`C:\Git\cmd\git.exe`, "-c", "core.longpaths=true", "status"}, cmd.Args)
+	assert.Equal(t, `C:\src\app`, cmd.Dir)
+
+	missing := newTestLocator("linux", nil, nil)
+	cmd = missing.CommandContext(context.Background(), ".", Git, "status")
+	err := cmd.Run()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, exec.ErrNotFound))
+}
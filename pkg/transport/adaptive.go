@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyTolerance is how much slower than the fastest observed response
+	// the smoothed latency of a host may get before the limit is lowered
+	latencyTolerance = 3
+	// latencyNoise is the latency increase which is always ignored, so very
+	// fast hosts don't shrink on jitter
+	latencyNoise = 10 * time.Millisecond
+	// smoothingFactor is the weight of a new sample in the smoothed latency
+	smoothingFactor = 0.2
+)
+
+// AdaptiveLimiter is an http.RoundTripper limiting the number of concurrent
+// requests per host. Each host starts at the minimum limit, which grows by
+// one after a window of fast responses and shrinks when the host gets
+// slower. Rate limited responses (429, 503 or an exhausted rate-limit
+// header) halve the limit immediately.
+type AdaptiveLimiter struct {
+	next     http.RoundTripper
+	minLimit int
+	maxLimit int
+
+	mutex sync.Mutex
+	hosts map[string]*hostLimit
+}
+
+type hostLimit struct {
+	limit    int
+	inFlight int
+	// released is closed and replaced whenever a request finishes
+	released chan struct{}
+	// sinceChange counts responses since the limit was last evaluated
+	sinceChange int
+	baseline    time.Duration
+	smoothed    time.Duration
+	// nearRateLimit is set if the server reports fewer remaining requests
+	// than the current limit
+	nearRateLimit bool
+}
+
+// NewAdaptiveLimiter wraps next, http.DefaultTransport if nil, with per host
+// concurrency limits between minLimit and maxLimit
+func NewAdaptiveLimiter(next http.RoundTripper, minLimit, maxLimit int) *AdaptiveLimiter {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	minLimit = max(minLimit, 1)
+	return &AdaptiveLimiter{
+		next:     next,
+		minLimit: minLimit,
+		maxLimit: max(maxLimit, minLimit),
+		hosts:    make(map[string]*hostLimit),
+	}
+}
+
+// RoundTrip waits for a free slot of the request host, forwards the request
+// and adapts the host limit to the response
+func (a *AdaptiveLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := a.host(req.URL.Host)
+	if err := a.acquire(req, host); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := a.next.RoundTrip(req)
+	a.release(host, time.Since(start), resp, err)
+	return resp, err
+}
+
+// Unwrap returns the wrapped round tripper
+func (a *AdaptiveLimiter) Unwrap() http.RoundTripper {
+	return a.next
+}
+
+// Limits returns the current concurrency limit of every host seen so far
+func (a *AdaptiveLimiter) Limits() map[string]int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	limits := make(map[string]int, len(a.hosts))
+	for name, host := range a.hosts {
+		limits[name] = host.limit
+	}
+	return limits
+}
+
+func (a *AdaptiveLimiter) host(name string) *hostLimit {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	host, ok := a.hosts[name]
+	if !ok {
+		host = &hostLimit{limit: a.minLimit, released: make(chan struct{})}
+		a.hosts[name] = host
+	}
+	return host
+}
+
+func (a *AdaptiveLimiter) acquire(req *http.Request, host *hostLimit) error {
+	for {
+		a.mutex.Lock()
+		if host.inFlight < host.limit {
+			host.inFlight++
+			a.mutex.Unlock()
+			return nil
+		}
+		released := host.released
+		a.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+}
+
+func (a *AdaptiveLimiter) release(host *hostLimit, latency time.Duration, resp *http.Response, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	host.inFlight--
+	close(host.released)
+	host.released = make(chan struct{})
+
+	// Network errors say nothing about the capacity of the host
+	if err != nil {
+		return
+	}
+
+	remaining, hasRemaining := rateLimitRemaining(resp.Header)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable ||
+		(hasRemaining && remaining == 0) {
+		host.limit = max(host.limit/2, a.minLimit)
+		host.sinceChange = 0
+		return
+	}
+	host.nearRateLimit = hasRemaining && remaining < host.limit
+
+	if host.baseline == 0 || latency < host.baseline {
+		host.baseline = latency
+	}
+	if host.smoothed == 0 {
+		host.smoothed = latency
+	} else {
+		host.smoothed = time.Duration(smoothingFactor*float64(latency) + (1-smoothingFactor)*float64(host.smoothed))
+	}
+
+	// Evaluate once per window of limit responses, so a single change has
+	// time to show its effect
+	host.sinceChange++
+	if host.sinceChange < host.limit {
+		return
+	}
+	host.sinceChange = 0
+
+	switch {
+	case host.smoothed > host.baseline*latencyTolerance && host.smoothed-host.baseline > latencyNoise:
+		host.limit = max(host.limit-1, a.minLimit)
+	case !host.nearRateLimit:
+		host.limit = min(host.limit+1, a.maxLimit)
+	}
+}
+
+// rateLimitRemaining parses the remaining request quota from the common
+// rate-limit response headers
+func rateLimitRemaining(header http.Header) (int, bool) {
+	for _, name := range []string{"X-RateLimit-Remaining", "RateLimit-Remaining"} {
+		if value := header.Get(name); value != "" {
+			remaining, err := strconv.Atoi(value)
+			if err == nil {
+				return remaining, true
+			}
+		}
+	}
+	return 0, false
+}
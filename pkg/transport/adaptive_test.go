@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func respond(status int, header http.Header) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{StatusCode: status, Header: header, Body: http.NoBody}, nil
+	}
+}
+
+func doRequests(t *testing.T, limiter http.RoundTripper, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+		require.NoError(t, err)
+		_, err = limiter.RoundTrip(req)
+		require.NoError(t, err)
+	}
+}
+
+func TestAdaptiveLimiterGrowsOnFastResponses(t *testing.T) {
+	limiter := NewAdaptiveLimiter(respond(http.StatusOK, nil), 2, 4)
+
+	doRequests(t, limiter, 2)
+	assert.Equal(t, 3, limiter.Limits()["proxy.example.com"])
+
+	doRequests(t, limiter, 100)
+	assert.Equal(t, 4, limiter.Limits()["proxy.example.com"], "limit is capped at the maximum")
+}
+
+func TestAdaptiveLimiterHalvesWhenRateLimited(t *testing.T) {
+	status := http.StatusOK
+	limiter := NewAdaptiveLimiter(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return respond(status, nil)(req)
+	}), 2, 16)
+
+	doRequests(t, limiter, 200)
+	require.Equal(t, 16, limiter.Limits()["proxy.example.com"])
+
+	status = http.StatusTooManyRequests
+	doRequests(t, limiter, 1)
+	assert.Equal(t, 8, limiter.Limits()["proxy.example.com"])
+
+	doRequests(t, limiter, 10)
+	assert.Equal(t, 2, limiter.Limits()["proxy.example.com"], "limit never drops below the minimum")
+}
+
+func TestAdaptiveLimiterRateLimitHeaders(t *testing.T) {
+	exhausted := NewAdaptiveLimiter(respond(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"0"}}), 2, 16)
+	doRequests(t, exhausted, 20)
+	assert.Equal(t, 2, exhausted.Limits()["proxy.example.com"])
+
+	nearLimit := NewAdaptiveLimiter(respond(http.StatusOK, http.Header{"Ratelimit-Remaining": []string{"1"}}), 2, 16)
+	doRequests(t, nearLimit, 20)
+	assert.Equal(t, 2, nearLimit.Limits()["proxy.example.com"], "limit doesn't grow close to the quota")
+}
+
+func TestAdaptiveLimiterShrinksOnSlowResponses(t *testing.T) {
+	var calls atomic.Int32
+	limiter := NewAdaptiveLimiter(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// Fast responses first, then the host gets much slower
+		if calls.Add(1) > 6 {
+			time.Sleep(20 * time.Millisecond)
+		} else {
+			time.Sleep(time.Millisecond)
+		}
+		return respond(http.StatusOK, nil)(req)
+	}), 2, 16)
+
+	doRequests(t, limiter, 6)
+	grown := limiter.Limits()["proxy.example.com"]
+	doRequests(t, limiter, 30)
+
+	assert.Less(t, limiter.Limits()["proxy.example.com"], grown)
+}
+
+func TestAdaptiveLimiterBoundsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	limiter := NewAdaptiveLimiter(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		current := inFlight.Add(1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		// Rate limited responses keep the limit at the minimum
+		return respond(http.StatusTooManyRequests, nil)(req)
+	}), 2, 16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doRequests(t, limiter, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestAdaptiveLimiterCancelWhileWaiting(t *testing.T) {
+	block := make(chan struct{})
+	limiter := NewAdaptiveLimiter(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-block
+		return respond(http.StatusOK, nil)(req)
+	}), 1, 1)
+	defer close(block)
+
+	go doRequests(t, limiter, 1)
+	require.Eventually(t, func() bool {
+		limiter.mutex.Lock()
+		defer limiter.mutex.Unlock()
+		host, ok := limiter.hosts["proxy.example.com"]
+		return ok && host.inFlight == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://proxy.example.com/other/@v/list", nil)
+	require.NoError(t, err)
+
+	_, err = limiter.RoundTrip(req)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
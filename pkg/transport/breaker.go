@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerThreshold is the number of consecutive failures of a
+	// host opening its circuit
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown is how long an open circuit rejects requests
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// CircuitOpenError is returned for requests to a host whose circuit is
+// open after repeated failures
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit of %s open after repeated failures until %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+// CircuitBreaker is an http.RoundTripper failing requests fast to hosts
+// which keep failing. Network errors, 429 and 5xx responses count as
+// failures. After threshold consecutive failures the circuit of the host
+// opens and requests fail with a CircuitOpenError for the cooldown. Then a
+// single trial request is let through, whose success closes the circuit
+// again.
+type CircuitBreaker struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mutex sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	// trial is set while the request probing an expired open circuit runs
+	trial bool
+}
+
+// NewCircuitBreaker wraps next, http.DefaultTransport if nil, opening
+// circuits after DefaultBreakerThreshold failures for
+// DefaultBreakerCooldown
+func NewCircuitBreaker(next http.RoundTripper) *CircuitBreaker {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &CircuitBreaker{
+		next:      next,
+		threshold: DefaultBreakerThreshold,
+		cooldown:  DefaultBreakerCooldown,
+		now:       time.Now,
+		hosts:     make(map[string]*circuit),
+	}
+}
+
+// Configure sets the consecutive failures opening a circuit and how long it
+// stays open. A threshold of zero or less disables the circuit breaker.
+func (b *CircuitBreaker) Configure(threshold int, cooldown time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.threshold = threshold
+	if cooldown > 0 {
+		b.cooldown = cooldown
+	}
+}
+
+// RoundTrip rejects the request if the circuit of its host is open and
+// records the outcome otherwise
+func (b *CircuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	trial, err := b.allow(host)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.next.RoundTrip(req)
+	// Cancelled requests say nothing about the host
+	if req.Context().Err() != nil {
+		b.record(host, trial, nil)
+		return resp, err
+	}
+	failed := err != nil || isTemporaryStatus(resp.StatusCode)
+	b.record(host, trial, &failed)
+	return resp, err
+}
+
+// allow returns whether the request may pass and whether it is the trial
+// of an expired open circuit
+func (b *CircuitBreaker) allow(host string) (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.threshold <= 0 {
+		return false, nil
+	}
+	c, ok := b.hosts[host]
+	if !ok || c.openUntil.IsZero() {
+		return false, nil
+	}
+	if c.trial || b.now().Before(c.openUntil) {
+		return false, &CircuitOpenError{Host: host, Until: c.openUntil}
+	}
+	c.trial = true
+	return true, nil
+}
+
+// record counts the outcome of a request, failed is nil if it is unknown
+func (b *CircuitBreaker) record(host string, trial bool, failed *bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.threshold <= 0 {
+		return
+	}
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	if trial {
+		c.trial = false
+	}
+	if failed == nil {
+		return
+	}
+
+	if !*failed {
+		c.failures = 0
+		c.openUntil = time.Time{}
+		return
+	}
+	c.failures++
+	if trial || c.failures >= b.threshold {
+		c.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	calls := 0
+	breaker := NewCircuitBreaker(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return respond(status, nil)(req)
+	}))
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+	breaker.Configure(3, time.Minute)
+
+	roundTrip := func(host string) error {
+		req, err := http.NewRequest(http.MethodGet, "https://"+host+"/mod/@v/list", nil)
+		require.NoError(t, err)
+		_, err = breaker.RoundTrip(req)
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, roundTrip("proxy.example.com"))
+	}
+	var openErr *CircuitOpenError
+	require.True(t, errors.As(roundTrip("proxy.example.com"), &openErr))
+	assert.Equal(t, "proxy.example.com", openErr.Host)
+	assert.Equal(t, 3, calls, "open circuits don't send requests")
+	assert.NoError(t, roundTrip("other.example.com"), "circuits are per host")
+
+	// A failing trial after the cooldown opens the circuit again
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, roundTrip("proxy.example.com"))
+	assert.Error(t, roundTrip("proxy.example.com"))
+
+	// A successful trial closes it
+	now = now.Add(2 * time.Minute)
+	status = http.StatusOK
+	require.NoError(t, roundTrip("proxy.example.com"))
+	assert.NoError(t, roundTrip("proxy.example.com"))
+}
+
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	statuses := []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusNotFound, http.StatusBadGateway, http.StatusBadGateway}
+	calls := 0
+	breaker := NewCircuitBreaker(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[calls]
+		calls++
+		return respond(status, nil)(req)
+	}))
+	breaker.Configure(3, time.Minute)
+
+	for range statuses {
+		req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+		require.NoError(t, err)
+		_, err = breaker.RoundTrip(req)
+		require.NoError(t, err, "a 404 answer resets the consecutive failures")
+	}
+	assert.True(t, breaker.hosts["proxy.example.com"].openUntil.IsZero())
+}
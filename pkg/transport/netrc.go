@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// NetrcLine is the login of a machine in a .netrc file
+type NetrcLine struct {
+	Machine  string
+	Login    string
+	Password string
+}
+
+// ParseNetrc parses the machine entries of a .netrc file. Like for the go
+// command, macros are skipped and parsing stops at the default entry.
+func ParseNetrc(data string) []NetrcLine {
+	var lines []NetrcLine
+	var current *NetrcLine
+	inMacro := false
+	for _, line := range strings.Split(data, "\n") {
+		if inMacro {
+			// Macro definitions end with an empty line
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			switch fields[i] {
+			case "machine":
+				if i+1 >= len(fields) {
+					break
+				}
+				i++
+				lines = append(lines, NetrcLine{Machine: fields[i]})
+				current = &lines[len(lines)-1]
+			case "default":
+				return completeNetrcLines(lines)
+			case "login", "password":
+				if current == nil || i+1 >= len(fields) {
+					break
+				}
+				if fields[i] == "login" {
+					current.Login = fields[i+1]
+				} else {
+					current.Password = fields[i+1]
+				}
+				i++
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	return completeNetrcLines(lines)
+}
+
+// completeNetrcLines drops machines without login or password
+func completeNetrcLines(lines []NetrcLine) []NetrcLine {
+	complete := lines[:0]
+	for _, line := range lines {
+		if line.Login != "" && line.Password != "" {
+			complete = append(complete, line)
+		}
+	}
+	return complete
+}
+
+// NetrcPath returns the .netrc file the go command uses: $NETRC, else
+// .netrc in the home directory, _netrc on Windows
+func NetrcPath() (string, error) {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name), nil
+}
+
+// Netrc is an http.RoundTripper authenticating requests with the login of
+// their host in a .netrc file, like the go command does for private
+// proxies and go-get lookups. Requests which already carry credentials are
+// passed through.
+type Netrc struct {
+	next  http.RoundTripper
+	lines []NetrcLine
+}
+
+// NewNetrc wraps next, http.DefaultTransport if nil, with the logins of
+// lines
+func NewNetrc(next http.RoundTripper, lines []NetrcLine) *Netrc {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Netrc{next: next, lines: lines}
+}
+
+// LoadNetrc wraps next with the logins of the .netrc file of NetrcPath. A
+// missing or unreadable file just adds no logins.
+func LoadNetrc(next http.RoundTripper) *Netrc {
+	var lines []NetrcLine
+	if path, err := NetrcPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			lines = ParseNetrc(string(data))
+		}
+	}
+	return NewNetrc(next, lines)
+}
+
+// RoundTrip sets basic authentication on a copy of the request if the
+// netrc file has a login for its host
+func (n *Netrc) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" || req.URL.User != nil {
+		return n.next.RoundTrip(req)
+	}
+	for _, line := range n.lines {
+		if line.Machine == req.URL.Hostname() {
+			clone := req.Clone(req.Context())
+			clone.SetBasicAuth(line.Login, line.Password)
+			return n.next.RoundTrip(clone)
+		}
+	}
+	return n.next.RoundTrip(req)
+}
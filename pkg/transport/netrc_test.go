@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetrc(t *testing.T) {
+	lines := ParseNetrc(`machine goproxy.corp.example.com login ci password secret
+machine git.corp.example.com
+  login deploy
+  password token
+macdef init
+  machine ignored.example.com login a password b
+
+machine incomplete.example.com login nobody
+default login anonymous password guest
+machine after-default.example.com login a password b
+`)
+
+	assert.Equal(t, []NetrcLine{
+		{Machine: "goproxy.corp.example.com", Login: "ci", Password: "secret"},
+		{Machine: "git.corp.example.com", Login: "deploy", Password: "token"},
+	}, lines)
+}
+
+func TestNetrc(t *testing.T) {
+	var authorization string
+	netrc := NewNetrc(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return respond(http.StatusOK, nil)(req)
+	}), []NetrcLine{{Machine: "goproxy.corp.example.com", Login: "ci", Password: "secret"}})
+
+	req, err := http.NewRequest(http.MethodGet, "https://goproxy.corp.example.com:8443/mod/@v/list", nil)
+	require.NoError(t, err)
+	_, err = netrc.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Basic Y2k6c2VjcmV0", authorization)
+	assert.Empty(t, req.Header.Get("Authorization"), "the request of the caller is not modified")
+
+	req.Header.Set("Authorization", "Bearer token")
+	_, err = netrc.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", authorization)
+
+	req, err = http.NewRequest(http.MethodGet, "https://proxy.golang.org/mod/@v/list", nil)
+	require.NoError(t, err)
+	_, err = netrc.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Empty(t, authorization)
+}
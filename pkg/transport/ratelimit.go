@@ -0,0 +1,163 @@
+package transport
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultPublicRate is the courtesy limit in requests per second for the
+// public Go infrastructure and APIs govital talks to
+const DefaultPublicRate = 20
+
+// PublicHosts are rate limited with DefaultPublicRate unless configured
+// otherwise. Other hosts, e.g. private proxies, are not limited by default.
+var PublicHosts = []string{
+	"proxy.golang.org",
+	"sum.golang.org",
+	"index.golang.org",
+	"api.osv.dev",
+	"api.deps.dev",
+	"api.github.com",
+}
+
+// HostLimit configures the request rate of a single host
+type HostLimit struct {
+	Host string `mapstructure:"host"`
+	// RequestsPerSecond of zero or less disables rate limiting for the host
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+}
+
+// RetryConfig configures the retries of failed requests and the circuit
+// breaker of hosts which keep failing
+type RetryConfig struct {
+	// MaxRetries is the number of retries of requests failing with a
+	// network error, 429 or 5xx, 0 disables retries
+	MaxRetries int `mapstructure:"max_retries"`
+	// BreakerThreshold is the number of consecutive failures opening the
+	// circuit of a host, 0 disables the circuit breaker
+	BreakerThreshold int `mapstructure:"breaker_threshold"`
+	// BreakerCooldown is how long an open circuit rejects requests
+	BreakerCooldown time.Duration `mapstructure:"breaker_cooldown"`
+}
+
+// DefaultRetryConfig returns the retries and circuit breaker used unless
+// configured otherwise
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:       DefaultMaxRetries,
+		BreakerThreshold: DefaultBreakerThreshold,
+		BreakerCooldown:  DefaultBreakerCooldown,
+	}
+}
+
+// RateLimiter is an http.RoundTripper limiting the request rate per host
+// with a token bucket. Requests wait for a token; hosts without a limit
+// are passed through.
+type RateLimiter struct {
+	next http.RoundTripper
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter wraps next, http.DefaultTransport if nil, with the
+// courtesy limits for PublicHosts
+func NewRateLimiter(next http.RoundTripper) *RateLimiter {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	limiter := &RateLimiter{next: next, buckets: make(map[string]*bucket)}
+	for _, host := range PublicHosts {
+		limiter.SetLimit(host, DefaultPublicRate)
+	}
+	return limiter
+}
+
+// SetLimit sets the rate of the host in requests per second. A rate of zero
+// or less removes the limit. The burst is one second worth of requests.
+func (r *RateLimiter) SetLimit(host string, perSecond float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if perSecond <= 0 {
+		delete(r.buckets, host)
+		return
+	}
+	burst := math.Max(math.Ceil(perSecond), 1)
+	r.buckets[host] = &bucket{rate: perSecond, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// RoundTrip waits until the request host has a token left and forwards the
+// request
+func (r *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	for {
+		wait := r.reserve(req.URL.Host)
+		if wait == 0 {
+			return r.next.RoundTrip(req)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// reserve takes a token of the host and returns zero, or returns how long
+// to wait for the next token
+func (r *RateLimiter) reserve(host string) time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b, ok := r.buckets[host]
+	if !ok {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// UserAgent is an http.RoundTripper setting the User-Agent header of
+// requests which don't set one themselves
+type UserAgent struct {
+	next  http.RoundTripper
+	agent string
+}
+
+// NewUserAgent wraps next, http.DefaultTransport if nil, identifying
+// requests with agent
+func NewUserAgent(next http.RoundTripper, agent string) *UserAgent {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &UserAgent{next: next, agent: agent}
+}
+
+// RoundTrip sets the User-Agent header on a copy of the request
+func (u *UserAgent) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return u.next.RoundTrip(req)
+	}
+	clone := req.Clone(req.Context())
+	clone.Header.Set("User-Agent", u.agent)
+	return u.next.RoundTrip(clone)
+}
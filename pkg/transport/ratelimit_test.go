@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(respond(http.StatusOK, nil))
+	limiter.SetLimit("proxy.example.com", 20)
+
+	start := time.Now()
+	doRequests(t, limiter, 30)
+	elapsed := time.Since(start)
+
+	// 20 requests of burst, the remaining 10 at 20 per second
+	assert.GreaterOrEqual(t, elapsed, 450*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestRateLimiterUnlimitedHost(t *testing.T) {
+	limiter := NewRateLimiter(respond(http.StatusOK, nil))
+	limiter.SetLimit("proxy.example.com", 1)
+	limiter.SetLimit("proxy.example.com", 0)
+
+	start := time.Now()
+	doRequests(t, limiter, 100)
+
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimiterPublicHostDefaults(t *testing.T) {
+	limiter := NewRateLimiter(nil)
+
+	for _, host := range PublicHosts {
+		require.Contains(t, limiter.buckets, host)
+		assert.InDelta(t, DefaultPublicRate, limiter.buckets[host].rate, 0.001)
+	}
+	assert.NotContains(t, limiter.buckets, "goproxy.internal")
+}
+
+func TestRateLimiterCancelWhileWaiting(t *testing.T) {
+	limiter := NewRateLimiter(respond(http.StatusOK, nil))
+	limiter.SetLimit("proxy.example.com", 0.1)
+	doRequests(t, limiter, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+
+	_, err = limiter.RoundTrip(req)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	transport := NewUserAgent(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return respond(http.StatusOK, nil)(req)
+	}), "govital/test")
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("User-Agent"), "the original request is not modified")
+
+	req.Header.Set("User-Agent", "custom")
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"govital/test", "custom"}, agents)
+}
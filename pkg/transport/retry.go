@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries of a failed request
+	DefaultMaxRetries = 3
+	// baseRetryBackoff is the wait before the first retry, doubled per retry
+	baseRetryBackoff = 500 * time.Millisecond
+	// maxRetryWait is the longest Retry-After honored. Longer waits, like
+	// hourly rate limits, return the response instead of stalling the scan.
+	maxRetryWait = 30 * time.Second
+)
+
+// Retry is an http.RoundTripper retrying requests which failed with a
+// network error, 429 or a 5xx status, with jittered exponential backoff.
+// A Retry-After header replaces the backoff. Requests with a body are only
+// retried if the body can be replayed.
+type Retry struct {
+	next       http.RoundTripper
+	maxRetries int
+	// sleep waits for the duration or until ctx is done
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetry wraps next, http.DefaultTransport if nil, with DefaultMaxRetries
+// retries
+func NewRetry(next http.RoundTripper) *Retry {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Retry{next: next, maxRetries: DefaultMaxRetries, sleep: sleepContext}
+}
+
+// SetMaxRetries sets the number of retries, 0 disables them
+func (r *Retry) SetMaxRetries(retries int) {
+	r.maxRetries = max(retries, 0)
+}
+
+// RoundTrip forwards the request and retries it while it fails temporarily
+func (r *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := r.next.RoundTrip(req)
+		if attempt == r.maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := retryBackoff(attempt + 1)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header); ok {
+				if after > maxRetryWait {
+					return resp, nil
+				}
+				wait = after
+			}
+			drain(resp)
+		}
+		if err := r.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the outcome of the request is temporary and
+// the request can be sent again
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		// Unknown hosts won't appear within the backoff
+		var dnsErr *net.DNSError
+		return !errors.As(err, &dnsErr) || !dnsErr.IsNotFound
+	}
+	return isTemporaryStatus(resp.StatusCode)
+}
+
+// isTemporaryStatus reports whether the status is worth a retry
+func isTemporaryStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header in seconds or as HTTP date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// retryBackoff returns the jittered exponential wait before the given retry
+func retryBackoff(attempt int) time.Duration {
+	wait := baseRetryBackoff << (attempt - 1)
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// drain discards the body of a response which is not returned, so the
+// connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
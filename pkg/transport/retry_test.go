@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRetry returns a retry transport answering with the given statuses
+// in order, which records the waits instead of sleeping
+func newTestRetry(statuses []int, header http.Header) (*Retry, *[]time.Duration, *int) {
+	calls := 0
+	var waits []time.Duration
+	retry := NewRetry(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		return respond(status, header)(req)
+	}))
+	retry.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return retry, &waits, &calls
+}
+
+func TestRetryTemporaryFailures(t *testing.T) {
+	retry, waits, calls := newTestRetry([]int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	resp, err := retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, *calls)
+	require.Len(t, *waits, 2)
+	assert.LessOrEqual(t, (*waits)[0], baseRetryBackoff)
+	assert.LessOrEqual(t, (*waits)[1], 2*baseRetryBackoff)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	retry, _, calls := newTestRetry([]int{http.StatusServiceUnavailable}, nil)
+	retry.SetMaxRetries(2)
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	resp, err := retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestRetryKeepsPermanentFailures(t *testing.T) {
+	retry, waits, calls := newTestRetry([]int{http.StatusNotFound}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	resp, err := retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.Empty(t, *waits)
+}
+
+func TestRetryAfter(t *testing.T) {
+	retry, waits, _ := newTestRetry([]int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": []string{"7"}})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/repos", nil)
+	require.NoError(t, err)
+	_, err = retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+
+	// Waits beyond the limit return the response
+	retry, waits, calls := newTestRetry([]int{http.StatusTooManyRequests}, http.Header{"Retry-After": []string{"3600"}})
+	resp, err := retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, *calls)
+	assert.Empty(t, *waits)
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	var bodies []string
+	calls := 0
+	retry := NewRetry(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return respond(http.StatusOK, nil)(req)
+	}))
+	retry.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com/query", strings.NewReader(`{"q":1}`))
+	require.NoError(t, err)
+	_, err = retry.RoundTrip(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"q":1}`, `{"q":1}`}, bodies)
+}
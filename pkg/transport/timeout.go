@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHTTPTimeout bounds a single request including its response body
+const DefaultHTTPTimeout = 30 * time.Second
+
+// Timeout is an http.RoundTripper bounding every request by a deadline on
+// its context, which also covers reading the response body. Below the
+// retries each attempt gets its own deadline, so a hung connection is
+// retried instead of stalling the scan.
+type Timeout struct {
+	next    http.RoundTripper
+	timeout atomic.Int64
+}
+
+// NewTimeout wraps next, http.DefaultTransport if nil, with
+// DefaultHTTPTimeout
+func NewTimeout(next http.RoundTripper) *Timeout {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &Timeout{next: next}
+	t.SetTimeout(DefaultHTTPTimeout)
+	return t
+}
+
+// SetNext replaces the wrapped transport, http.DefaultTransport if nil. It
+// must not be called while requests are sent.
+func (t *Timeout) SetNext(next http.RoundTripper) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t.next = next
+}
+
+// SetTimeout sets the deadline of each request, 0 disables it
+func (t *Timeout) SetTimeout(timeout time.Duration) {
+	t.timeout.Store(int64(max(timeout, 0)))
+}
+
+// RoundTrip forwards the request with a deadline
+func (t *Timeout) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := time.Duration(t.timeout.Load())
+	if timeout == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the deadline of the request once the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutAbortsHungRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hung" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	timeout := NewTimeout(nil)
+	timeout.SetTimeout(50 * time.Millisecond)
+	client := &http.Client{Transport: timeout}
+
+	start := time.Now()
+	_, err := client.Get(server.URL + "/hung")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The deadline stays active while the body is read
+	resp, err := client.Get(server.URL + "/ok")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestTimeoutDisabled(t *testing.T) {
+	var deadline bool
+	timeout := NewTimeout(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		_, deadline = req.Context().Deadline()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	timeout.SetTimeout(0)
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	_, err = timeout.RoundTrip(req)
+	require.NoError(t, err)
+	assert.False(t, deadline)
+
+	timeout.SetTimeout(time.Minute)
+	_, err = timeout.RoundTrip(req)
+	require.NoError(t, err)
+	assert.True(t, deadline)
+}
+
+func TestTimeoutSetNext(t *testing.T) {
+	timeout := NewTimeout(nil)
+	var sent bool
+	timeout.SetNext(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "https://proxy.example.com/mod/@v/list", nil)
+	require.NoError(t, err)
+	_, err = timeout.RoundTrip(req)
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	timeout.SetNext(nil)
+	assert.Equal(t, http.DefaultTransport, timeout.next)
+}
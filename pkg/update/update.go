@@ -0,0 +1,167 @@
+// Package update plans the go commands which move outdated dependencies to
+// their latest compatible versions, or swap inactive dependencies for
+// maintained forks
+package update
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/suggest"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Kinds of steps
+const (
+	// KindUpgrade moves a dependency to a newer version of the same major
+	// version
+	KindUpgrade = "upgrade"
+	// KindFork replaces a dependency by a maintained fork
+	KindFork = "fork"
+	// KindTidy updates go.mod and go.sum after replacements
+	KindTidy = "tidy"
+)
+
+// Step is a single go command of an update
+type Step struct {
+	Kind string `json:"kind"`
+	// Path and From are the dependency and the version in use, To is the
+	// version upgraded to or the fork module with its version
+	Path string `json:"path,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Args are the arguments of the go command
+	Args []string `json:"args"`
+}
+
+// Command returns the step as shell command
+func (s Step) Command() string {
+	return "go " + strings.Join(s.Args, " ")
+}
+
+// Upgrades returns a go get step for every dependency with an update
+// available, sorted by path. Replaced dependencies are skipped, go get
+// would only change the requirement which the replacement overrides.
+func Upgrades(result *scanner.ScanResult) []Step {
+	updates := make(map[string]scanner.Dependency)
+	for _, dep := range result.Dependencies {
+		if dep.Update == "" || dep.Replace != nil {
+			continue
+		}
+		// Multi-module results can list a dependency per module, the newest
+		// update wins
+		if seen, ok := updates[dep.Path]; ok && semver.Compare(seen.Update, dep.Update) >= 0 {
+			continue
+		}
+		updates[dep.Path] = dep
+	}
+
+	steps := make([]Step, 0, len(updates))
+	for _, dep := range updates {
+		steps = append(steps, Step{
+			Kind: KindUpgrade,
+			Path: dep.Path,
+			From: dep.Version,
+			To:   dep.Update,
+			Args: []string{"get", dep.Path + "@" + dep.Update},
+		})
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Path < steps[j].Path })
+	return steps
+}
+
+// LatestFunc resolves the latest version of a module
+type LatestFunc func(ctx context.Context, modulePath string) (string, error)
+
+// ForkSwaps returns a replace step for every entry with a suggested active
+// fork, using the most starred one, followed by a go mod tidy step. Forks
+// whose latest version can't be resolved are skipped with a warning.
+func ForkSwaps(ctx context.Context, entries []suggest.Entry, latest LatestFunc) ([]Step, error) {
+	steps := []Step{}
+	for _, entry := range entries {
+		fork, ok := firstFork(entry)
+		if !ok {
+			continue
+		}
+		forkPath, ok := ForkModulePath(entry.Path, entry.Repository, fork.Module)
+		if !ok {
+			eslog.Warnf("Skipping fork %s of %s, the module is not part of %s", fork.Module, entry.Path, entry.Repository)
+			continue
+		}
+		version, err := latest(ctx, forkPath)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			eslog.Warnf("Skipping fork %s of %s: %v", forkPath, entry.Path, err)
+			continue
+		}
+		steps = append(steps, Step{
+			Kind: KindFork,
+			Path: entry.Path,
+			From: entry.Version,
+			To:   forkPath + "@" + version,
+			Args: []string{"mod", "edit", fmt.Sprintf("-replace=%s=%s@%s", entry.Path, forkPath, version)},
+		})
+	}
+	if len(steps) > 0 {
+		steps = append(steps, Step{Kind: KindTidy, Args: []string{"mod", "tidy"}})
+	}
+	return steps, nil
+}
+
+func firstFork(entry suggest.Entry) (suggest.Suggestion, bool) {
+	for _, suggestion := range entry.Suggestions {
+		if suggestion.Source == suggest.SourceFork {
+			return suggestion, true
+		}
+	}
+	return suggest.Suggestion{}, false
+}
+
+// ForkModulePath returns the path of the module in the fork of its source
+// repository, keeping the subdirectory and major version suffix. Modules
+// with vanity import paths are assumed to live in the repository root,
+// gopkg.in paths can't be mapped.
+func ForkModulePath(modulePath, repositoryRoot, forkRoot string) (string, bool) {
+	if repositoryRoot == "" {
+		return "", false
+	}
+	if modulePath == repositoryRoot {
+		return forkRoot, true
+	}
+	if subpath, ok := strings.CutPrefix(modulePath, repositoryRoot+"/"); ok {
+		return forkRoot + "/" + subpath, true
+	}
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok || strings.HasPrefix(pathMajor, ".") {
+		return "", false
+	}
+	return forkRoot + pathMajor, true
+}
+
+// Write prints the steps as shell commands, one per line, so they can be
+// piped to a shell
+func Write(w io.Writer, steps []Step) error {
+	if len(steps) == 0 {
+		_, err := fmt.Fprintln(w, "# Nothing to update")
+		return err
+	}
+	for _, step := range steps {
+		if step.Path != "" {
+			if _, err := fmt.Fprintf(w, "# %s %s -> %s\n", step.Path, step.From, step.To); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, step.Command()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/suggest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrades(t *testing.T) {
+	result := &scanner.ScanResult{Dependencies: []scanner.Dependency{
+		{Path: "github.com/example/b", Version: "v1.0.0", Update: "v1.2.0", Module: "example.com/one"},
+		{Path: "github.com/example/b", Version: "v1.1.0", Update: "v1.3.0", Module: "example.com/two"},
+		{Path: "github.com/example/a", Version: "v0.1.0", Update: "v0.2.0"},
+		{Path: "github.com/example/latest", Version: "v1.0.0", Latest: "v1.0.0"},
+		{Path: "github.com/example/replaced", Version: "v1.0.0", Update: "v1.1.0", Replace: &scanner.Replacement{Path: "../replaced"}},
+	}}
+
+	steps := Upgrades(result)
+
+	assert.Equal(t, []Step{
+		{Kind: KindUpgrade, Path: "github.com/example/a", From: "v0.1.0", To: "v0.2.0", Args: []string{"get", "github.com/example/a@v0.2.0"}},
+		{Kind: KindUpgrade, Path: "github.com/example/b", From: "v1.1.0", To: "v1.3.0", Args: []string{"get", "github.com/example/b@v1.3.0"}},
+	}, steps)
+}
+
+func TestForkSwaps(t *testing.T) {
+	fork := suggest.Suggestion{Module: "github.com/active/mod", Source: suggest.SourceFork}
+	entries := []suggest.Entry{
+		{Path: "github.com/old/mod/sub/v2", Version: "v2.0.0", Repository: "github.com/old/mod", Suggestions: []suggest.Suggestion{
+			{Module: "errors", Source: suggest.SourceKnown},
+			fork,
+		}},
+		{Path: "github.com/old/known", Version: "v1.0.0", Repository: "github.com/old/known", Suggestions: []suggest.Suggestion{{Module: "errors", Source: suggest.SourceKnown}}},
+		{Path: "github.com/old/broken", Version: "v1.0.0", Repository: "github.com/old/broken", Suggestions: []suggest.Suggestion{{Module: "github.com/active/broken", Source: suggest.SourceFork}}},
+	}
+	latest := func(_ context.Context, modulePath string) (string, error) {
+		if modulePath == "github.com/active/broken" {
+			return "", errors.New("not found")
+		}
+		return "v2.1.1-0.20260101000000-abcdefabcdef", nil
+	}
+
+	steps, err := ForkSwaps(context.Background(), entries, latest)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		{
+			Kind: KindFork, Path: "github.com/old/mod/sub/v2", From: "v2.0.0",
+			To:   "github.com/active/mod/sub/v2@v2.1.1-0.20260101000000-abcdefabcdef",
+			Args: []string{"mod", "edit", "-replace=github.com/old/mod/sub/v2=github.com/active/mod/sub/v2@v2.1.1-0.20260101000000-abcdefabcdef"},
+		},
+		{Kind: KindTidy, Args: []string{"mod", "tidy"}},
+	}, steps)
+
+	steps, err = ForkSwaps(context.Background(), entries[1:2], latest)
+	require.NoError(t, err)
+	assert.Empty(t, steps, "no tidy without swaps")
+}
+
+func TestForkModulePath(t *testing.T) {
+	tests := []struct {
+		modulePath string
+		root       string
+		expected   string
+		ok         bool
+	}{
+		{"github.com/old/mod", "github.com/old/mod", "github.com/new/mod", true},
+		{"github.com/old/mod/v3", "github.com/old/mod", "github.com/new/mod/v3", true},
+		{"github.com/old/mod/sub", "github.com/old/mod", "github.com/new/mod/sub", true},
+		{"example.com/mod/v2", "github.com/old/mod", "github.com/new/mod/v2", true},
+		{"example.com/mod", "github.com/old/mod", "github.com/new/mod", true},
+		{"gopkg.in/yaml.v3", "github.com/go-yaml/yaml", "", false},
+		{"github.com/old/mod", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			path, ok := ForkModulePath(tt.modulePath, tt.root, "github.com/new/mod")
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, []Step{
+		{Kind: KindUpgrade, Path: "github.com/example/a", From: "v0.1.0", To: "v0.2.0", Args: []string{"get", "github.com/example/a@v0.2.0"}},
+		{Kind: KindTidy, Args: []string{"mod", "tidy"}},
+	}))
+	assert.Equal(t, `# github.com/example/a v0.1.0 -> v0.2.0
+go get github.com/example/a@v0.2.0
+go mod tidy
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, nil))
+	assert.Equal(t, "# Nothing to update\n", buf.String())
+}
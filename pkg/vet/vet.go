@@ -0,0 +1,107 @@
+// Package vet evaluates a module before it is added as a dependency
+package vet
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// Report is the go/no-go evaluation of a candidate module
+type Report struct {
+	// Candidate is the scanned candidate at the resolved version
+	Candidate scanner.Dependency `json:"candidate"`
+	// Requirements counts the modules the candidate requires
+	Requirements int `json:"requirements"`
+	// NewModules are the required modules the project does not require yet,
+	// they are added to go.mod together with the candidate
+	NewModules []string `json:"new_modules"`
+	// Violations are the fail-on conditions the candidate meets
+	Violations []string `json:"violations"`
+	// Go is true if the candidate meets no fail-on condition
+	Go bool `json:"go"`
+}
+
+// Evaluate checks the scanned candidate against the conditions and counts
+// the modules its requirements add to the project. Since Go 1.17 the go.mod
+// of a module lists all modules its packages need, so its requirements
+// approximate the transitive weight.
+func Evaluate(candidate scanner.Dependency, requirements, project []scanner.Dependency, conditions []policy.Condition) Report {
+	required := make(map[string]bool, len(project))
+	for _, dep := range project {
+		required[dep.Path] = true
+	}
+
+	report := Report{Candidate: candidate, Requirements: len(requirements), NewModules: []string{}, Violations: []string{}}
+	for _, dep := range requirements {
+		if !required[dep.Path] && dep.Path != candidate.Path {
+			report.NewModules = append(report.NewModules, dep.Path)
+		}
+	}
+	sort.Strings(report.NewModules)
+
+	for _, condition := range conditions {
+		if !condition.Warn && condition.Matches(candidate) {
+			report.Violations = append(report.Violations, condition.Name)
+		}
+	}
+	report.Go = len(report.Violations) == 0
+	return report
+}
+
+// Write prints the evaluation with the recommendation on the last line
+func Write(w io.Writer, report Report) {
+	dep := report.Candidate
+	fmt.Fprintf(w, "Vetting %s@%s\n\n", dep.Path, dep.Version)
+
+	switch {
+	case dep.Error != nil:
+		fmt.Fprintf(w, "Maintenance:     unknown (%s)\n", dep.Error)
+	case dep.IsInactive():
+		fmt.Fprintf(w, "Maintenance:     inactive, last release %d days ago\n", dep.DaysSinceLastRelease)
+	case dep.Status == scanner.StatusUnknown:
+		fmt.Fprintf(w, "Maintenance:     unknown\n")
+	default:
+		fmt.Fprintf(w, "Maintenance:     active, last release %d days ago\n", dep.DaysSinceLastRelease)
+	}
+	if dep.Score != nil {
+		fmt.Fprintf(w, "Health score:    %d\n", *dep.Score)
+	}
+	if dep.Update != "" {
+		fmt.Fprintf(w, "Update:          %s is available\n", dep.Update)
+	}
+	if len(dep.Licenses) > 0 {
+		fmt.Fprintf(w, "Licenses:        %s\n", strings.Join(dep.Licenses, ", "))
+	}
+	if len(dep.Vulnerabilities) > 0 {
+		ids := make([]string, len(dep.Vulnerabilities))
+		for i, vulnerability := range dep.Vulnerabilities {
+			ids[i] = vulnerability.ID
+		}
+		fmt.Fprintf(w, "Vulnerabilities: %s\n", strings.Join(ids, ", "))
+	}
+	if dep.Retracted != nil {
+		fmt.Fprintf(w, "Retracted:       %s\n", dep.Retracted)
+	}
+	if dep.Deprecated != "" {
+		fmt.Fprintf(w, "Deprecated:      %s\n", dep.Deprecated)
+	}
+	if dep.Archived != nil && *dep.Archived {
+		fmt.Fprintf(w, "Repository:      archived\n")
+	}
+
+	fmt.Fprintf(w, "\nTransitive weight: %d required modules, %d new to the project\n", report.Requirements, len(report.NewModules))
+	for _, module := range report.NewModules {
+		fmt.Fprintf(w, "  - %s\n", module)
+	}
+
+	if report.Go {
+		fmt.Fprintf(w, "\nRecommendation: GO, no fail-on condition is met\n")
+		return
+	}
+	fmt.Fprintf(w, "\nRecommendation: NO-GO, meets %s\n", strings.Join(report.Violations, ", "))
+}
@@ -0,0 +1,47 @@
+package vet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/steffakasid/govital/pkg/policy"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/steffakasid/govital/pkg/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	conditions, err := policy.ParseConditions([]string{"inactive,vulnerable"})
+	require.NoError(t, err)
+	requirements := []scanner.Dependency{
+		{Path: "example.com/shared", Version: "v1.2.0"},
+		{Path: "example.com/new-b", Version: "v1.0.0", IsIndirect: true},
+		{Path: "example.com/new-a", Version: "v0.1.0"},
+	}
+	project := []scanner.Dependency{{Path: "example.com/shared", Version: "v1.0.0"}}
+
+	candidate := scanner.Dependency{Path: "example.com/lib", Version: "v1.0.0", IsActive: true, DaysSinceLastRelease: 12}
+	report := Evaluate(candidate, requirements, project, conditions)
+
+	assert.True(t, report.Go)
+	assert.Equal(t, 3, report.Requirements)
+	assert.Equal(t, []string{"example.com/new-a", "example.com/new-b"}, report.NewModules)
+
+	var out bytes.Buffer
+	Write(&out, report)
+	assert.Contains(t, out.String(), "Maintenance:     active, last release 12 days ago")
+	assert.Contains(t, out.String(), "Transitive weight: 3 required modules, 2 new to the project")
+	assert.Contains(t, out.String(), "Recommendation: GO")
+
+	candidate.IsActive = false
+	candidate.Vulnerabilities = []vuln.Vulnerability{{ID: "GO-2024-0001"}}
+	report = Evaluate(candidate, nil, project, conditions)
+
+	assert.False(t, report.Go)
+	assert.Equal(t, []string{"inactive", "vulnerable"}, report.Violations)
+	out.Reset()
+	Write(&out, report)
+	assert.Contains(t, out.String(), "Vulnerabilities: GO-2024-0001")
+	assert.Contains(t, out.String(), "Recommendation: NO-GO, meets inactive, vulnerable")
+}
@@ -0,0 +1,207 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultBaseURL is the public OSV.dev API
+const DefaultBaseURL = "https://api.osv.dev"
+
+// batchSize is the maximum number of queries OSV accepts per batch request
+const batchSize = 1000
+
+// SeverityUnknown is used when an advisory carries no severity rating
+const SeverityUnknown = "UNKNOWN"
+
+// Vulnerability is a known advisory affecting a module version
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity string   `json:"severity"`
+}
+
+// Query identifies a Go module version to check
+type Query struct {
+	Path    string
+	Version string
+}
+
+// Client queries the OSV.dev API for known vulnerabilities of Go modules
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	detailsMutex sync.Mutex
+	details      map[string]Vulnerability
+}
+
+// NewClient creates a client for the public OSV.dev API
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{},
+		details:    make(map[string]Vulnerability),
+	}
+}
+
+type osvQuery struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+type osvVulnerability struct {
+	ID               string   `json:"id"`
+	Summary          string   `json:"summary"`
+	Aliases          []string `json:"aliases"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+}
+
+// Check returns the known vulnerabilities for each query, in query order
+func (c *Client) Check(ctx context.Context, queries []Query) ([][]Vulnerability, error) {
+	results := make([][]Vulnerability, len(queries))
+
+	for start := 0; start < len(queries); start += batchSize {
+		end := min(start+batchSize, len(queries))
+
+		ids, err := c.queryBatch(ctx, queries[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for i, vulnIDs := range ids {
+			for _, id := range vulnIDs {
+				vulnerability, err := c.vulnerability(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				results[start+i] = append(results[start+i], vulnerability)
+			}
+		}
+	}
+	return results, nil
+}
+
+// queryBatch returns the advisory IDs affecting each of the queries
+func (c *Client) queryBatch(ctx context.Context, queries []Query) ([][]string, error) {
+	request := struct {
+		Queries []osvQuery `json:"queries"`
+	}{Queries: make([]osvQuery, len(queries))}
+
+	for i, query := range queries {
+		request.Queries[i].Package.Name = query.Path
+		request.Queries[i].Package.Ecosystem = "Go"
+		// OSV expects Go versions without the leading v
+		request.Queries[i].Version = strings.TrimPrefix(query.Version, "v")
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OSV query: %w", err)
+	}
+
+	var response osvBatchResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/querybatch", bytes.NewReader(body), &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(queries) {
+		return nil, fmt.Errorf("OSV returned %d results for %d queries", len(response.Results), len(queries))
+	}
+
+	ids := make([][]string, len(queries))
+	for i, result := range response.Results {
+		for _, v := range result.Vulns {
+			ids[i] = append(ids[i], v.ID)
+		}
+	}
+	return ids, nil
+}
+
+// vulnerability returns the details of an advisory, cached per client
+func (c *Client) vulnerability(ctx context.Context, id string) (Vulnerability, error) {
+	c.detailsMutex.Lock()
+	cached, ok := c.details[id]
+	c.detailsMutex.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var response osvVulnerability
+	if err := c.do(ctx, http.MethodGet, "/v1/vulns/"+id, nil, &response); err != nil {
+		return Vulnerability{}, err
+	}
+
+	vulnerability := Vulnerability{
+		ID:       response.ID,
+		Aliases:  response.Aliases,
+		Summary:  response.Summary,
+		Severity: severity(response),
+	}
+
+	c.detailsMutex.Lock()
+	c.details[id] = vulnerability
+	c.detailsMutex.Unlock()
+	return vulnerability, nil
+}
+
+// severity prefers the rating of the advisory database and falls back to
+// the CVSS vector if that is all there is
+func severity(v osvVulnerability) string {
+	if v.DatabaseSpecific.Severity != "" {
+		return strings.ToUpper(v.DatabaseSpecific.Severity)
+	}
+	for _, s := range v.Severity {
+		if s.Score != "" {
+			return s.Score
+		}
+	}
+	return SeverityUnknown
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, target any) error {
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create OSV request: %w", err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("OSV request %s failed: %w", path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("OSV request %s returned status %d", path, response.StatusCode)
+	}
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode OSV response for %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, detailRequests *int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/querybatch", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Queries []osvQuery `json:"queries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		type vulnRef struct {
+			ID string `json:"id"`
+		}
+		type result struct {
+			Vulns []vulnRef `json:"vulns,omitempty"`
+		}
+		response := struct {
+			Results []result `json:"results"`
+		}{}
+		for _, query := range request.Queries {
+			assert.Equal(t, "Go", query.Package.Ecosystem)
+			if query.Package.Name == "github.com/example/vulnerable" && query.Version == "1.0.0" {
+				response.Results = append(response.Results, result{Vulns: []vulnRef{{ID: "GO-2024-0001"}, {ID: "GHSA-xxxx"}}})
+			} else {
+				response.Results = append(response.Results, result{})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	mux.HandleFunc("GET /v1/vulns/{id}", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(detailRequests, 1)
+		switch r.PathValue("id") {
+		case "GO-2024-0001":
+			_, _ = w.Write([]byte(`{"id":"GO-2024-0001","summary":"Remote code execution","aliases":["CVE-2024-0001"]}`))
+		case "GHSA-xxxx":
+			_, _ = w.Write([]byte(`{"id":"GHSA-xxxx","database_specific":{"severity":"high"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestCheck(t *testing.T) {
+	var detailRequests int32
+	server := newTestServer(t, &detailRequests)
+	defer server.Close()
+
+	client := NewClient()
+	client.BaseURL = server.URL
+
+	results, err := client.Check(context.Background(), []Query{
+		{Path: "github.com/example/safe", Version: "v1.0.0"},
+		{Path: "github.com/example/vulnerable", Version: "v1.0.0"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Empty(t, results[0])
+	require.Len(t, results[1], 2)
+	assert.Equal(t, "GO-2024-0001", results[1][0].ID)
+	assert.Equal(t, []string{"CVE-2024-0001"}, results[1][0].Aliases)
+	assert.Equal(t, SeverityUnknown, results[1][0].Severity)
+	assert.Equal(t, "HIGH", results[1][1].Severity)
+
+	// Details are cached per client
+	_, err = client.Check(context.Background(), []Query{{Path: "github.com/example/vulnerable", Version: "v1.0.0"}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&detailRequests))
+}
+
+func TestCheckServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.BaseURL = server.URL
+
+	_, err := client.Check(context.Background(), []Query{{Path: "github.com/example/safe", Version: "v1.0.0"}})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+}
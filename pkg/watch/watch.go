@@ -0,0 +1,191 @@
+// Package watch rescans a project whenever its go.mod or go.sum change and
+// reports how its dependencies changed
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/steffakasid/eslog"
+	"github.com/steffakasid/govital/pkg/diff"
+	"github.com/steffakasid/govital/pkg/scanner"
+)
+
+// DefaultDebounce is how long go.mod and go.sum need to stay unchanged
+// before a rescan, so the writes of a single go get cause one rescan
+const DefaultDebounce = 500 * time.Millisecond
+
+// ParseFunc returns the requirements to scan of a go.mod
+type ParseFunc func(goMod []byte) ([]scanner.Dependency, error)
+
+// ScanFunc scans the given dependencies
+type ScanFunc func(ctx context.Context, deps []scanner.Dependency) (*scanner.ScanResult, error)
+
+// Watcher keeps the scan result of a project up to date. Only requirements
+// which are new or required at another version are scanned again.
+type Watcher struct {
+	// Debounce is how long the files need to stay unchanged before a rescan
+	Debounce time.Duration
+
+	dir    string
+	parse  ParseFunc
+	scan   ScanFunc
+	out    io.Writer
+	result *scanner.ScanResult
+}
+
+// New creates a watcher of the go.mod in dir which writes the changes of
+// each rescan to out
+func New(dir string, parse ParseFunc, scan ScanFunc, out io.Writer) *Watcher {
+	return &Watcher{
+		Debounce: DefaultDebounce,
+		dir:      dir,
+		parse:    parse,
+		scan:     scan,
+		out:      out,
+	}
+}
+
+// Result returns the result of the last successful scan, nil before the
+// first one
+func (w *Watcher) Result() *scanner.ScanResult {
+	return w.result
+}
+
+// Rescan reads go.mod and scans the requirements which are new or changed
+// since the previous scan, the results of the others are kept. The report
+// compares with the previous scan, the first scan with an empty one. A
+// failed rescan keeps the previous result.
+func (w *Watcher) Rescan(ctx context.Context) (*diff.Report, error) {
+	goModPath := filepath.Join(w.dir, "go.mod")
+	goMod, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", goModPath, err)
+	}
+	deps, err := w.parse(goMod)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := w.result
+	if previous == nil {
+		previous = &scanner.ScanResult{ProjectPath: w.dir}
+	}
+	known := make(map[string]scanner.Dependency, len(previous.Dependencies))
+	for _, dep := range previous.Dependencies {
+		known[requirement(dep)] = dep
+	}
+
+	result := &scanner.ScanResult{ProjectPath: w.dir}
+	staleThresholdDays := previous.Summary.StaleThresholdDays
+	var changed []scanner.Dependency
+	for _, dep := range deps {
+		if scanned, ok := known[requirement(dep)]; ok {
+			result.Dependencies = append(result.Dependencies, scanned)
+		} else {
+			changed = append(changed, dep)
+		}
+	}
+	if len(changed) > 0 {
+		scanned, err := w.scan(ctx, changed)
+		if err != nil {
+			return nil, err
+		}
+		result.Dependencies = append(result.Dependencies, scanned.Dependencies...)
+		result.Diagnostics = scanned.Diagnostics
+		staleThresholdDays = scanned.Summary.StaleThresholdDays
+	}
+	// The summaries of the scans only count the changed requirements
+	result.Summary = scanner.Summarize(result.Dependencies, staleThresholdDays)
+	sort.Slice(result.Dependencies, func(i, j int) bool {
+		return result.Dependencies[i].Path < result.Dependencies[j].Path
+	})
+
+	w.result = result
+	return diff.Compare(previous, result), nil
+}
+
+// Run scans the project and rescans it after every change of go.mod or
+// go.sum until the context is cancelled. Only the first scan must succeed,
+// failed rescans, e.g. of a go.mod in the middle of an edit, are logged.
+func (w *Watcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", w.dir, err)
+	}
+	defer watcher.Close()
+	// Editors replace files on save, which ends watches of the files
+	if err := watcher.Add(w.dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", w.dir, err)
+	}
+
+	if _, err := w.Rescan(ctx); err != nil {
+		return err
+	}
+	inactive := 0
+	for _, dep := range w.result.Dependencies {
+		if dep.IsInactive() && !dep.IsAcknowledged {
+			inactive++
+		}
+	}
+	fmt.Fprintf(w.out, "Watching %s: %d dependencies, %d inactive\n", filepath.Join(w.dir, "go.mod"), len(w.result.Dependencies), inactive)
+
+	var rescan <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if isModuleFile(event) {
+				rescan = time.After(w.Debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			eslog.Warnf("Failed to watch %s: %v", w.dir, err)
+		case <-rescan:
+			rescan = nil
+			report, err := w.Rescan(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				eslog.Warnf("Rescan failed, keeping the previous result: %v", err)
+				continue
+			}
+			if len(report.Changes) > 0 {
+				fmt.Fprintf(w.out, "%s dependencies changed:\n", time.Now().Format(time.TimeOnly))
+				report.PrintCompact(w.out)
+			}
+		}
+	}
+}
+
+// isModuleFile reports whether the event changed go.mod or go.sum
+func isModuleFile(event fsnotify.Event) bool {
+	name := filepath.Base(event.Name)
+	if name != "go.mod" && name != "go.sum" {
+		return false
+	}
+	return event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename)
+}
+
+// requirement identifies a requirement with its replacement, so changed
+// replace directives are scanned again
+func requirement(dep scanner.Dependency) string {
+	key := dep.Path + "@" + dep.Version
+	if dep.Replace != nil {
+		key += " => " + dep.Replace.Path + "@" + dep.Replace.Version
+	}
+	return key
+}
@@ -0,0 +1,183 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steffakasid/govital/pkg/diff"
+	"github.com/steffakasid/govital/pkg/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goMod = `module example.com/app
+
+go 1.22
+
+require (
+	github.com/example/a v1.0.0
+	github.com/example/b v1.0.0
+)
+`
+
+// fakeScan records the scanned dependencies and reports the ones of
+// github.com/example/b as inactive
+type fakeScan struct {
+	mu      sync.Mutex
+	scanned [][]string
+	calls   chan struct{}
+}
+
+func (f *fakeScan) scan(ctx context.Context, deps []scanner.Dependency) (*scanner.ScanResult, error) {
+	f.mu.Lock()
+	var paths []string
+	result := &scanner.ScanResult{}
+	for _, dep := range deps {
+		paths = append(paths, dep.Path+"@"+dep.Version)
+		dep.IsActive = dep.Path != "github.com/example/b"
+		dep.Status = scanner.StatusActive
+		if !dep.IsActive {
+			dep.Status = scanner.StatusStale
+		}
+		result.Dependencies = append(result.Dependencies, dep)
+	}
+	f.scanned = append(f.scanned, paths)
+	f.mu.Unlock()
+	if f.calls != nil {
+		f.calls <- struct{}{}
+	}
+	return result, nil
+}
+
+func writeGoMod(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644))
+}
+
+func TestRescan(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, goMod)
+	fake := &fakeScan{}
+	watcher := New(dir, scanner.NewScanner(dir).ParseGoMod, fake.scan, &bytes.Buffer{})
+
+	report, err := watcher.Rescan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Summary.Added)
+	assert.Equal(t, 1, report.Summary.InactiveIntroduced)
+
+	writeGoMod(t, dir, strings.Replace(goMod, "github.com/example/a v1.0.0", "github.com/example/a v1.1.0\n\tgithub.com/example/c v0.1.0", 1))
+	report, err = watcher.Rescan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"github.com/example/a@v1.0.0", "github.com/example/b@v1.0.0"},
+		{"github.com/example/a@v1.1.0", "github.com/example/c@v0.1.0"},
+	}, fake.scanned, "only changed requirements are scanned again")
+	require.Len(t, report.Changes, 2)
+	assert.Equal(t, diff.Upgraded, report.Changes[0].Kind)
+	assert.Equal(t, diff.Added, report.Changes[1].Kind)
+	assert.Len(t, watcher.Result().Dependencies, 3)
+
+	writeGoMod(t, dir, "module example.com/app\nrequire (\n")
+	_, err = watcher.Rescan(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, watcher.Result().Dependencies, 3, "the previous result is kept")
+}
+
+func TestRescanSummary(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, goMod)
+	fake := &fakeScan{}
+	watcher := New(dir, scanner.NewScanner(dir).ParseGoMod, fake.scan, &bytes.Buffer{})
+
+	_, err := watcher.Rescan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, watcher.Result().Summary.Total)
+	assert.Equal(t, 1, watcher.Result().Summary.Inactive)
+
+	// Only a is scanned again, the summary still counts the kept b
+	writeGoMod(t, dir, strings.Replace(goMod, "github.com/example/a v1.0.0", "github.com/example/a v1.1.0", 1))
+	_, err = watcher.Rescan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, watcher.Result().Summary.Total)
+	assert.Equal(t, 1, watcher.Result().Summary.Inactive)
+
+	// Removed requirements are no longer counted
+	writeGoMod(t, dir, strings.Replace(goMod, "\tgithub.com/example/b v1.0.0\n", "", 1))
+	_, err = watcher.Rescan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, watcher.Result().Summary.Total)
+	assert.Equal(t, 0, watcher.Result().Summary.Inactive)
+}
+
+func TestRescanFailedScan(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, goMod)
+	failing := func(context.Context, []scanner.Dependency) (*scanner.ScanResult, error) {
+		return nil, errors.New("proxy unreachable")
+	}
+	watcher := New(dir, scanner.NewScanner(dir).ParseGoMod, failing, &bytes.Buffer{})
+
+	_, err := watcher.Rescan(context.Background())
+	assert.EqualError(t, err, "proxy unreachable")
+	assert.Nil(t, watcher.Result())
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of Run
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, goMod)
+	fake := &fakeScan{calls: make(chan struct{}, 4)}
+	out := &syncBuffer{}
+	watcher := New(dir, scanner.NewScanner(dir).ParseGoMod, fake.scan, out)
+	watcher.Debounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+
+	select {
+	case <-fake.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no initial scan")
+	}
+	// Unrelated files don't trigger a rescan
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+	writeGoMod(t, dir, strings.Replace(goMod, "github.com/example/b v1.0.0", "github.com/example/b v1.2.0", 1))
+	select {
+	case <-fake.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no rescan after go.mod changed")
+	}
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "~ github.com/example/b v1.0.0 -> v1.2.0")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Contains(t, out.String(), "2 dependencies, 1 inactive")
+	assert.Len(t, fake.scanned, 2)
+}